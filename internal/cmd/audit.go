@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewAuditCmd creates the audit command for reading records written by the audit hook
+func NewAuditCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "audit",
+		Usage: "Query audit records written by the audit hook",
+		Description: `The audit hook writes one schema-versioned JSON record per tool call to
+.claude/audit/audit-YYYY-MM-DD.jsonl, with index.json summarizing each day.
+BT_AUDIT_DIR overrides the directory.`,
+		Commands: []*cli.Command{
+			newAuditQueryCommand(),
+		},
+	}
+}
+
+func newAuditQueryCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "query",
+		Usage: "List audit records matching filters",
+		Description: `Records are printed oldest first. --since accepts Go durations plus days
+(e.g. 90m, 24h, 7d) or an RFC 3339 timestamp.
+
+Examples:
+  blues-traveler audit query --tool Bash --since 24h
+  blues-traveler audit query --decision failed --json | jq .inputs`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tool", Usage: "Only records for this tool"},
+			&cli.StringFlag{Name: "event", Usage: "Only this event (PreToolUse, PostToolUse)"},
+			&cli.StringFlag{Name: "decision", Usage: "Only this decision (requested, executed, failed)"},
+			&cli.StringFlag{Name: "session", Usage: "Only records from this session ID"},
+			&cli.StringFlag{Name: "since", Usage: "Only records newer than a duration or timestamp"},
+			&cli.IntFlag{Name: "limit", Value: 100, Usage: "Show at most this many of the most recent records (0 for all)"},
+			&cli.BoolFlag{Name: "json", Usage: "Print records as JSON lines"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			filter := core.AuditFilter{
+				Tool:      cmd.String("tool"),
+				Event:     cmd.String("event"),
+				Decision:  cmd.String("decision"),
+				SessionID: cmd.String("session"),
+				Limit:     int(cmd.Int("limit")),
+			}
+			if s := cmd.String("since"); s != "" {
+				since, err := parseSince(s, time.Now())
+				if err != nil {
+					return err
+				}
+				filter.Since = since
+			}
+			records, err := core.QueryAuditRecords(filter)
+			if err != nil {
+				return err
+			}
+			if cmd.Bool("json") {
+				enc := json.NewEncoder(os.Stdout)
+				for _, rec := range records {
+					if err := enc.Encode(rec); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			printAuditRecords(records)
+			return nil
+		},
+	}
+}
+
+// parseSince resolves --since relative to now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since '%s'\n  Suggestion: Use a duration like 24h or 7d, or an RFC 3339 timestamp", value)
+	}
+	return now.Add(-d), nil
+}
+
+func printAuditRecords(records []core.AuditRecord) {
+	if len(records) == 0 {
+		output.Println("No audit records match.")
+		return
+	}
+	output.Printf("%-20s %-12s %-10s %-10s %s\n", "TIME", "EVENT", "TOOL", "DECISION", "INPUT")
+	for _, rec := range records {
+		output.Printf("%-20s %-12s %-10s %-10s %s\n", rec.Time.Local().Format("2006-01-02 15:04:05"),
+			rec.Event, rec.Tool, rec.Decision, auditInputSummary(rec.Inputs))
+	}
+}
+
+// auditInputSummary picks the most telling input field for the table view
+func auditInputSummary(inputs map[string]interface{}) string {
+	for _, key := range []string{"command", "file_path", "pattern"} {
+		if v, ok := inputs[key].(string); ok {
+			v = strings.Join(strings.Fields(v), " ")
+			if len(v) > 80 {
+				v = v[:77] + "..."
+			}
+			return v
+		}
+	}
+	return ""
+}
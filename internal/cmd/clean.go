@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// cleanOptions configures a clean run
+type cleanOptions struct {
+	all     bool
+	yes     bool
+	confirm func(prompt string) bool
+}
+
+// NewCleanCmd creates the clean command for data hooks record in the project
+func NewCleanCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "clean",
+		Usage: "Prune local hook data by its retention policy, or remove all of it",
+		Description: `Hooks record data under .claude/ as they run. Each category has a retention
+policy, applied once a day by 'hooks housekeeping' and right away by this
+command:
+
+  payloads    tool calls and inputs from the audit hook        .claude/audit
+  runHistory  finding baselines, compat counts, experiments    .claude/cache
+  artifacts   files the delete-guard hook kept                 .claude/trash
+  metrics     hook runs recorded for 'hooks stats'             .claude/hooks/metrics
+  state       per-session state for custom jobs                .claude/state
+
+Entries not written to for maxAgeDays are removed, then the oldest until the
+category fits in maxSizeMB. Set policies in blues-traveler-config.json, e.g.
+{"retention": {"metrics": {"maxAgeDays": 30}}}; -1 turns a limit off.
+
+--all-local-data removes every category and the hook logs, leaving config
+and settings untouched.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "all-local-data", Usage: "Remove all recorded data and hook logs instead of applying retention"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Skip interactive confirmation for --all-local-data"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return runClean(cleanOptions{
+				all: cmd.Bool("all-local-data"),
+				yes: cmd.Bool("yes"),
+				confirm: func(prompt string) bool {
+					output.Printf("%s (y/N): ", prompt)
+					var response string
+					_, _ = fmt.Scanln(&response)
+					return response == "y" || response == "Y" || response == "yes"
+				},
+			})
+		},
+	}
+}
+
+func runClean(opts cleanOptions) error {
+	if !opts.all {
+		if cfgPath, err := config.GetLogConfigPath(false); err == nil {
+			if cfg, err := config.LoadLogConfig(cfgPath); err == nil {
+				if err := config.ValidateRetention(cfg.Retention); err != nil {
+					return err
+				}
+			}
+		}
+		results, err := applyRetention(config.GetRetentionPolicies(), time.Now())
+		printCleanResults(results)
+		return err
+	}
+
+	targets, err := core.RetentionTargets()
+	if err != nil {
+		return err
+	}
+	logs := hookLogFiles()
+	output.Println("This removes:")
+	for _, t := range targets {
+		output.Printf("  • %-10s %s\n", t.Category, t.Dir)
+	}
+	output.Printf("  • %-10s %d file(s) in %s\n", "logs", len(logs), filepath.Dir(config.GetLogPath("clean")))
+	if !opts.yes && (opts.confirm == nil || !opts.confirm("\nRemove all local hook data?")) {
+		output.Println("Operation cancelled.")
+		return nil
+	}
+
+	results := make([]core.RetentionResult, 0, len(targets)+1)
+	var firstErr error
+	for _, t := range targets {
+		res, err := core.ClearRetentionTarget(t)
+		results = append(results, res)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	logResult := core.RetentionResult{Category: "logs"}
+	for _, f := range logs {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(f); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to remove %s: %w", f, err)
+			}
+			continue
+		}
+		logResult.Removed++
+		logResult.FreedBytes += info.Size()
+	}
+	printCleanResults(append(results, logResult))
+	return firstErr
+}
+
+// hookLogFiles lists hook logs and their rotated backups
+func hookLogFiles() []string {
+	dir := filepath.Dir(config.GetLogPath("clean"))
+	var files []string
+	for _, pattern := range []string{"*.log", "*.log.gz"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		files = append(files, matches...)
+	}
+	return files
+}
+
+func printCleanResults(results []core.RetentionResult) {
+	removed, freed := 0, int64(0)
+	for _, r := range results {
+		if r.Removed == 0 {
+			continue
+		}
+		output.Printf("  %-10s removed %d, freed %s\n", r.Category, r.Removed, core.FormatBytes(r.FreedBytes))
+		removed += r.Removed
+		freed += r.FreedBytes
+	}
+	if removed == 0 {
+		output.Println("✅ Nothing to clean.")
+		return
+	}
+	output.Printf("✅ Removed %d entries, freed %s\n", removed, core.FormatBytes(freed))
+}
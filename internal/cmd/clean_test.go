@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunClean_AllLocalData(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	for _, f := range []string{
+		".claude/audit/audit-2026-01-01.jsonl",
+		".claude/cache/compat.json",
+		".claude/trash/20260101-000000/manifest.json",
+		".claude/hooks/metrics/metrics-2026-01-01.jsonl",
+		".claude/state/session/key",
+		".claude/hooks/format.log",
+		".claude/hooks/blues-traveler-config.json",
+	} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runClean(cleanOptions{all: true, confirm: func(string) bool { return false }}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude/audit")); err != nil {
+		t.Fatal("declined confirmation should leave data in place")
+	}
+
+	if err := runClean(cleanOptions{all: true, yes: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, gone := range []string{".claude/audit", ".claude/cache", ".claude/trash", ".claude/hooks/metrics", ".claude/state", ".claude/hooks/format.log"} {
+		if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed", gone)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude/hooks/blues-traveler-config.json")); err != nil {
+		t.Error("config must survive --all-local-data")
+	}
+}
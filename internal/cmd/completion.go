@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/urfave/cli/v3"
+)
+
+// completionRequestFlag is the flag the completion scripts append when
+// they ask the binary for candidates
+const completionRequestFlag = "--generate-shell-completion"
+
+// completionScripts are the scripts 'completion <shell>' prints. Every one
+// hands the words typed so far back to blues-traveler with
+// --generate-shell-completion, so candidates such as plugin keys and group
+// names come from the registry and hooks config at the time of completion.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for blues-traveler
+# Add to ~/.bashrc: source <(blues-traveler completion bash)
+_blues_traveler_complete() {
+  local cur words cword
+  COMPREPLY=()
+  if declare -F _init_completion >/dev/null 2>&1; then
+    _init_completion -n "=:" || return
+  else
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    words=("${COMP_WORDS[@]}")
+    cword=$COMP_CWORD
+  fi
+  local request=("${words[@]:0:$cword}")
+  if [[ "$cur" == -* ]]; then
+    request+=("$cur")
+  fi
+  local IFS=$'\n'
+  COMPREPLY=($(compgen -W "$("${request[@]}" --generate-shell-completion 2>/dev/null)" -- "$cur"))
+}
+complete -o bashdefault -o default -F _blues_traveler_complete blues-traveler
+`,
+	"zsh": `#compdef blues-traveler
+# zsh completion for blues-traveler
+# Add to ~/.zshrc: source <(blues-traveler completion zsh)
+_blues_traveler() {
+  local -a opts request
+  request=("${words[@]:0:$((CURRENT-1))}")
+  if [[ "${words[CURRENT]}" == -* ]]; then
+    request+=("${words[CURRENT]}")
+  fi
+  opts=("${(@f)$(SHELL=zsh "${request[@]}" --generate-shell-completion 2>/dev/null)}")
+  if [[ -n "${opts[1]}" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+compdef _blues_traveler blues-traveler
+`,
+	"fish": `# fish completion for blues-traveler
+# Save as ~/.config/fish/completions/blues-traveler.fish
+function __blues_traveler_complete
+    set -l request (commandline -opc)
+    set -l cur (commandline -ct)
+    if string match -q -- '-*' $cur
+        set request $request $cur
+    end
+    $request --generate-shell-completion 2>/dev/null
+end
+complete -c blues-traveler -f -a '(__blues_traveler_complete)'
+`,
+	"powershell": `# PowerShell completion for blues-traveler
+# Add to $PROFILE: blues-traveler completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName blues-traveler, blues-traveler.exe -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and $words.Count -gt 0 -and $words[-1] -eq $wordToComplete) {
+        $words = $words[0..($words.Count - 2)]
+    }
+    if ($wordToComplete -like '-*') {
+        $words += $wordToComplete
+    }
+    $exe = $words[0]
+    $rest = @()
+    if ($words.Count -gt 1) { $rest = $words[1..($words.Count - 1)] }
+    & $exe @rest --generate-shell-completion 2>$null |
+        Where-Object { $_ -like "$wordToComplete*" } |
+        ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_) }
+}
+`,
+}
+
+// completionShells lists the shells 'completion' accepts
+func completionShells() []string {
+	shells := make([]string, 0, len(completionScripts))
+	for s := range completionScripts {
+		shells = append(shells, s)
+	}
+	sort.Strings(shells)
+	return shells
+}
+
+// ConfigureCompletionCommand turns the completion command urfave/cli adds
+// for EnableShellCompletion into 'blues-traveler completion <shell>',
+// printing scripts that complete plugin keys and groups dynamically
+func ConfigureCompletionCommand(c *cli.Command) {
+	c.Hidden = false
+	c.Usage = "Print a shell completion script for bash, zsh, fish or powershell"
+	c.ArgsUsage = "<bash|zsh|fish|powershell>"
+	c.Description = `Print a completion script for your shell. Besides commands and flags it
+completes plugin keys for 'hooks run' and 'hooks install', custom job keys
+(config:<group>:<job>) for 'hooks run', group names for 'config sync' and
+'hooks custom install|sync', and event names for --event. Keys and groups are
+read from the registry and hooks config each time you press Tab, so new jobs
+complete without regenerating the script.
+
+  # bash (~/.bashrc)
+  source <(blues-traveler completion bash)
+
+  # zsh (~/.zshrc)
+  source <(blues-traveler completion zsh)
+
+  # fish
+  blues-traveler completion fish > ~/.config/fish/completions/blues-traveler.fish
+
+  # PowerShell ($PROFILE)
+  blues-traveler completion powershell | Out-String | Invoke-Expression`
+	c.ShellComplete = func(_ context.Context, cmd *cli.Command) {
+		if cmd.Args().Len() == 0 {
+			printCandidates(cmd.Root().Writer, completionShells())
+		}
+	}
+	c.Action = func(_ context.Context, cmd *cli.Command) error {
+		return printCompletionScript(cmd.Root().Writer, cmd.Args().First())
+	}
+}
+
+// printCompletionScript writes the script for shell; pwsh is accepted for
+// powershell
+func printCompletionScript(w io.Writer, shell string) error {
+	if shell == "pwsh" {
+		shell = "powershell"
+	}
+	script, ok := completionScripts[shell]
+	if !ok {
+		if shell == "" {
+			return fmt.Errorf("no shell given\n  Suggestion: Run 'blues-traveler completion <%s>'", strings.Join(completionShells(), "|"))
+		}
+		return fmt.Errorf("unknown shell '%s'\n  Suggestion: Use one of %s", shell, strings.Join(completionShells(), ", "))
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// completeArgs returns a ShellCompleteFunc for a command taking one
+// positional argument: flags complete as usual, --event takes event names,
+// and the argument itself is completed from candidates
+func completeArgs(candidates func() []string) cli.ShellCompleteFunc {
+	return func(_ context.Context, cmd *cli.Command) {
+		w := cmd.Root().Writer
+		switch completionPreviousWord() {
+		case "--event", "-e":
+			printCandidates(w, core.ValidEventTypes())
+			return
+		}
+		if cur := completionCurrentWord(); strings.HasPrefix(cur, "-") {
+			printFlagCandidates(w, cur, cmd.Flags)
+			return
+		}
+		if cmd.Args().Len() > 0 || candidates == nil {
+			return
+		}
+		printCandidates(w, candidates())
+	}
+}
+
+// completionCurrentWord is the partial word being completed when it is a
+// flag; the scripts pass other partial words to the shell's own filtering
+func completionCurrentWord() string {
+	args := os.Args
+	if len(args) >= 2 && args[len(args)-1] == completionRequestFlag && strings.HasPrefix(args[len(args)-2], "-") {
+		return args[len(args)-2]
+	}
+	return ""
+}
+
+// completionPreviousWord is the last complete word before the one being
+// completed, e.g. "--event" while completing its value
+func completionPreviousWord() string {
+	args := os.Args
+	if len(args) < 2 || args[len(args)-1] != completionRequestFlag {
+		return ""
+	}
+	return args[len(args)-2]
+}
+
+// builtinPluginKeys filters custom job keys out of keys
+func builtinPluginKeys(keys []string) []string {
+	var out []string
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "config:") {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// hookGroupNames lists the groups in the project and global hooks config
+func hookGroupNames() []string {
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		return nil
+	}
+	return config.ListHookGroups(cfg)
+}
+
+// printFlagCandidates prints the visible flags of a command whose long form
+// starts with cur, e.g. "--log" and "--log-format" for "--lo"
+func printFlagCandidates(w io.Writer, cur string, flags []cli.Flag) {
+	prefix := strings.TrimLeft(cur, "-")
+	for _, f := range flags {
+		if vf, ok := f.(cli.VisibleFlag); ok && !vf.IsVisible() {
+			continue
+		}
+		for _, name := range f.Names() {
+			if len(name) > 1 && strings.HasPrefix(name, prefix) {
+				_, _ = fmt.Fprintln(w, "--"+name)
+			}
+		}
+	}
+}
+
+func printCandidates(w io.Writer, candidates []string) {
+	for _, c := range candidates {
+		_, _ = fmt.Fprintln(w, c)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+)
+
+// completeWith runs root the way a completion script does and returns the
+// candidates it prints
+func completeWith(t *testing.T, root *cli.Command, args ...string) []string {
+	t.Helper()
+	var out bytes.Buffer
+	root.Writer = &out
+	root.EnableShellCompletion = true
+	root.ConfigureShellCompletionCommand = ConfigureCompletionCommand
+	argv := append(append([]string{root.Name}, args...), completionRequestFlag)
+	orig := os.Args
+	os.Args = argv
+	t.Cleanup(func() { os.Args = orig })
+	if err := root.Run(context.Background(), argv); err != nil {
+		t.Fatalf("completion run: %v", err)
+	}
+	return strings.Fields(out.String())
+}
+
+func TestCompletion_GroupsEventsAndFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	t.Chdir(project)
+	if err := os.MkdirAll(filepath.Join(project, ".claude"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	hooksYAML := "lint:\n  PreToolUse:\n    jobs:\n      - name: vet\n        run: echo ok\n"
+	if err := os.WriteFile(filepath.Join(project, ".claude", "hooks.yml"), []byte(hooksYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newRoot := func() *cli.Command {
+		return &cli.Command{Name: "blues-traveler", Commands: []*cli.Command{
+			newHooksCustomSyncCommand(func(string) bool { return true }, func() []string { return nil }),
+		}}
+	}
+
+	if got := completeWith(t, newRoot(), "sync"); len(got) != 1 || got[0] != "lint" {
+		t.Errorf("group candidates = %v, want [lint]", got)
+	}
+	if got := completeWith(t, newRoot(), "sync", "lint"); len(got) != 0 {
+		t.Errorf("expected nothing after the group, got %v", got)
+	}
+	if got := completeWith(t, newRoot(), "sync", "--event"); len(got) == 0 || got[0] != "PreToolUse" {
+		t.Errorf("event candidates = %v", got)
+	}
+	got := completeWith(t, newRoot(), "sync", "--pr")
+	if strings.Join(got, " ") != "--prune-only" {
+		t.Errorf("flag candidates = %v, want [--prune-only]", got)
+	}
+}
+
+func TestPrintCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell", "pwsh"} {
+		var out bytes.Buffer
+		if err := printCompletionScript(&out, shell); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), completionRequestFlag) {
+			t.Errorf("%s script does not ask blues-traveler for candidates", shell)
+		}
+	}
+	if err := printCompletionScript(&bytes.Buffer{}, "tcsh"); err == nil || !strings.Contains(err.Error(), "Suggestion") {
+		t.Errorf("expected an unknown shell to fail with a suggestion, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// applyOptions configures a config apply run
+type applyOptions struct {
+	install installOptions
+	remove  bool
+	dryRun  bool
+}
+
+// projectApplyResult is the outcome of applying a group to one project
+type projectApplyResult struct {
+	Project string
+	// Entries is how many settings entries were added or removed
+	Entries int
+	Err     error
+}
+
+// NewConfigApplyCmd creates the config apply subcommand
+func NewConfigApplyCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "apply",
+		Usage: "Install or remove a hook group in every project in the XDG registry",
+		Description: `Installs a custom hook group into the .claude/settings.json of each project
+registered in the XDG config (see 'config list'), or with --remove takes it
+out again. Each project is handled in its own directory, so the group is
+read from that project's hooks config and the global one, and a project
+that doesn't define it fails on its own without stopping the rest.
+Installing replaces the group's existing entries, so running it again is
+safe. A line per project reports what changed; the command fails if any
+project did.
+
+Examples:
+  blues-traveler config apply --all-projects --group security-base
+  blues-traveler config apply --all-projects --group security-base --dry-run
+  blues-traveler config apply --all-projects --group security-base --remove`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "all-projects", Usage: "Apply to every project in the XDG registry"},
+			&cli.StringFlag{Name: "group", Usage: "Custom hook group to install or remove"},
+			&cli.BoolFlag{Name: "remove", Usage: "Remove the group's entries instead of installing them"},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "Report what would change without writing settings"},
+			&cli.StringFlag{Name: "event", Aliases: []string{"e"}, Usage: "Restrict to a single event (e.g., PreToolUse, PostToolUse)"},
+			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events (e.g., '*' or 'mcp:github')"},
+			&cli.StringFlag{Name: "post-matcher", Value: "Edit,Write", Usage: "Matcher for PostToolUse when not overridden"},
+			&cli.IntFlag{Name: "timeout", Aliases: []string{"t"}, Usage: "Override timeout in seconds for installed commands"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			opts, err := parseApplyOptions(cmd)
+			if err != nil {
+				return err
+			}
+			projects, err := config.NewXDGConfig().ListProjects()
+			if err != nil {
+				return fmt.Errorf("failed to list projects: %w", err)
+			}
+			if len(projects) == 0 {
+				output.Println("No projects in the XDG registry. Run 'blues-traveler config migrate' in a project to register it.")
+				return nil
+			}
+			sort.Strings(projects)
+			return reportApplyResults(applyGroupToProjects(projects, opts), opts)
+		},
+	}
+}
+
+// parseApplyOptions reads and validates the apply flags
+func parseApplyOptions(cmd *cli.Command) (applyOptions, error) {
+	if !cmd.Bool("all-projects") {
+		return applyOptions{}, fmt.Errorf("config apply needs --all-projects\n  Suggestion: Use 'blues-traveler hooks custom install <group>' for the current project only")
+	}
+	group := strings.TrimSpace(cmd.String("group"))
+	if group == "" {
+		return applyOptions{}, fmt.Errorf("--group is required\n  Suggestion: Run 'blues-traveler hooks custom list' to see the groups defined")
+	}
+	if group == config.BuiltinGroupName {
+		return applyOptions{}, fmt.Errorf("group '%s' is reserved for built-in hook installs\n  Suggestion: Apply a custom group from your hooks config", group)
+	}
+	if config.ProjectClaudeDirOverridden() {
+		return applyOptions{}, fmt.Errorf("--all-projects cannot be used while the project .claude directory is relocated by --claude-dir, %s or a --config-dir sandbox\n  Suggestion: Unset the override so each project's own .claude directory is used", config.ClaudeDirEnv)
+	}
+
+	eventFilter := strings.TrimSpace(cmd.String("event"))
+	if eventFilter != "" {
+		if !core.IsValidEventType(eventFilter) {
+			return applyOptions{}, fmt.Errorf("invalid --event '%s'. Valid events: %s", eventFilter, strings.Join(core.ValidEventTypes(), ", "))
+		}
+		if resolved := core.ResolveEventAlias(eventFilter); resolved != "" {
+			eventFilter = resolved
+		}
+	}
+
+	return applyOptions{
+		install: installOptions{
+			groupName:       group,
+			defaultMatcher:  core.ExpandMCPMatcher(cmd.String("matcher")),
+			postMatcher:     core.ExpandMCPMatcher(cmd.String("post-matcher")),
+			eventFilter:     eventFilter,
+			timeoutOverride: cmd.Int("timeout"),
+		},
+		remove: cmd.Bool("remove"),
+		dryRun: cmd.Bool("dry-run"),
+	}, nil
+}
+
+// applyGroupToProjects applies opts to each project in turn, from inside
+// the project directory, and returns to the current directory afterwards
+func applyGroupToProjects(projects []string, opts applyOptions) []projectApplyResult {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+	results := make([]projectApplyResult, 0, len(projects))
+	for _, project := range projects {
+		entries, err := applyGroupInProject(project, opts)
+		results = append(results, projectApplyResult{Project: project, Entries: entries, Err: err})
+	}
+	if cwd != "" {
+		_ = os.Chdir(cwd)
+	}
+	return results
+}
+
+// applyGroupInProject installs or removes the group in project's settings
+func applyGroupInProject(project string, opts applyOptions) (int, error) {
+	if info, err := os.Stat(project); err != nil || !info.IsDir() {
+		return 0, fmt.Errorf("project directory not found\n  Suggestion: Run 'blues-traveler config clean' to drop projects that no longer exist")
+	}
+	if err := os.Chdir(project); err != nil {
+		return 0, fmt.Errorf("failed to enter project: %w", err)
+	}
+
+	install := opts.install
+	var group *config.HookGroup
+	if !opts.remove {
+		cfg, err := config.LoadHooksConfig()
+		if err != nil {
+			return 0, fmt.Errorf("load hooks config: %w", err)
+		}
+		if cfg == nil || (*cfg)[install.groupName] == nil {
+			return 0, fmt.Errorf("group '%s' is not defined in this project's or the global hooks config", install.groupName)
+		}
+		group = (*cfg)[install.groupName]
+		if install.execPath, err = resolveHookExecutable(false, opts.dryRun); err != nil {
+			return 0, err
+		}
+	}
+
+	settings, settingsPath, release, err := loadSettingsForInstall(false)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	removed := config.RemoveConfigGroupFromSettings(settings, install.groupName, install.eventFilter)
+	entries := removed
+	if !opts.remove {
+		entries = installGroupHooks(settings, group, install)
+	} else if removed == 0 {
+		return 0, nil
+	}
+	if opts.dryRun {
+		return entries, nil
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return 0, fmt.Errorf("failed to save settings to %s: %w", settingsPath, err)
+	}
+	return entries, nil
+}
+
+// reportApplyResults prints a line per project and a summary, and returns
+// an error when any project failed
+func reportApplyResults(results []projectApplyResult, opts applyOptions) error {
+	verb := "installed"
+	switch {
+	case opts.remove && opts.dryRun:
+		verb = "would remove"
+	case opts.remove:
+		verb = "removed"
+	case opts.dryRun:
+		verb = "would install"
+	}
+
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			output.Printf("❌ %s: %v\n", r.Project, r.Err)
+		case opts.remove && r.Entries == 0:
+			output.Printf("➖ %s: '%s' not installed\n", r.Project, opts.install.groupName)
+		default:
+			output.Printf("✅ %s: %s '%s' (%d entries)\n", r.Project, verb, opts.install.groupName, r.Entries)
+		}
+	}
+
+	output.Printf("\n%d of %d projects succeeded", len(results)-failed, len(results))
+	if opts.dryRun {
+		output.Print(" (dry run, nothing written)")
+	}
+	output.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects failed\n  Suggestion: Fix the projects listed above and run the command again; projects that succeeded are unaffected", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestApplyGroupToProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	root := t.TempDir()
+	withGroup := filepath.Join(root, "api")
+	withoutGroup := filepath.Join(root, "web")
+	missing := filepath.Join(root, "gone")
+	hooks := "security-base:\n  PreToolUse:\n    jobs:\n      - name: scan\n        run: echo scan\n      - name: lint\n        run: echo lint\n"
+	for dir, content := range map[string]string{withGroup: hooks, withoutGroup: "other:\n  Stop:\n    jobs:\n      - name: x\n        run: true\n"} {
+		if err := os.MkdirAll(filepath.Join(dir, ".claude"), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".claude", "hooks.yml"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cwd, _ := os.Getwd()
+
+	opts := applyOptions{install: installOptions{groupName: "security-base", defaultMatcher: "*", postMatcher: "Edit,Write"}}
+	results := applyGroupToProjects([]string{withGroup, withoutGroup, missing}, opts)
+	if got, _ := os.Getwd(); got != cwd {
+		t.Errorf("working directory not restored: %s", got)
+	}
+	if results[0].Err != nil || results[0].Entries != 2 {
+		t.Errorf("api: expected 2 entries installed, got %+v", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "not defined") {
+		t.Errorf("web: expected an undefined group error, got %+v", results[1])
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "not found") {
+		t.Errorf("gone: expected a missing directory error, got %+v", results[2])
+	}
+	if err := reportApplyResults(results, opts); err == nil || !strings.Contains(err.Error(), "2 of 3 projects failed") {
+		t.Errorf("expected the report to fail for two projects, got %v", err)
+	}
+
+	settingsPath := filepath.Join(withGroup, ".claude", "settings.json")
+	data, err := os.ReadFile(settingsPath)
+	if err != nil || !strings.Contains(string(data), "config:security-base:scan") {
+		t.Fatalf("group not installed in api settings: %v\n%s", err, data)
+	}
+
+	// Applying again replaces the entries instead of duplicating them
+	results = applyGroupToProjects([]string{withGroup}, opts)
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil || results[0].Err != nil {
+		t.Fatal(err, results[0].Err)
+	}
+	if n := len(settings.Hooks.PreToolUse); n != 1 || len(settings.Hooks.PreToolUse[0].Hooks) != 2 {
+		t.Errorf("expected one matcher with two hooks after re-applying, got %+v", settings.Hooks.PreToolUse)
+	}
+
+	opts.remove = true
+	results = applyGroupToProjects([]string{withGroup, withoutGroup}, opts)
+	if results[0].Err != nil || results[0].Entries != 2 || results[1].Err != nil || results[1].Entries != 0 {
+		t.Errorf("unexpected remove results %+v", results)
+	}
+	if data, _ := os.ReadFile(settingsPath); strings.Contains(string(data), "security-base") {
+		t.Errorf("group still in settings after --remove:\n%s", data)
+	}
+}
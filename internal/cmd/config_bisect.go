@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// defaultBisectPaths are the repo paths whose history is bisected when
+// --path is not given: the project's hook configs and Claude settings.
+var defaultBisectPaths = []string{".claude"}
+
+// bisectOptions configures a hook-config bisect run
+type bisectOptions struct {
+	repoDir     string
+	paths       []string
+	testCmd     string
+	good        string
+	bad         string
+	maxDuration time.Duration
+	// sync regenerates settings.json in the sandbox; nil skips syncing
+	sync func(sandbox string) error
+}
+
+// bisectResult reports the first bad revision and its parent
+type bisectResult struct {
+	FirstBad string
+	LastGood string
+	Steps    int
+}
+
+// NewConfigBisectCmd creates the config bisect subcommand
+func NewConfigBisectCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "bisect",
+		Usage: "Find the hook config change that introduced a failure or slowdown",
+		Description: `Walk the git history of the project's hook configuration (.claude/ by default),
+materializing each revision into a sandbox directory, syncing custom hooks into
+its settings.json, and running a test command there. A revision is bad when the
+test exits non-zero or, with --max-duration, runs longer than allowed.
+
+The test command runs via 'sh -c' with the sandbox as its working directory and:
+  BT_BISECT_REV      revision being tested
+  BT_BISECT_SANDBOX  sandbox directory (contains .claude/ at that revision)
+  BT_PROJECT_ROOT    the real repository root
+
+Examples:
+  blues-traveler config bisect --test 'echo {} | blues-traveler hooks test config:ci:lint --file -'
+  blues-traveler config bisect --test './scripts/hook-smoke.sh' --max-duration 2s --good v1.2.0`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "test", Aliases: []string{"t"}, Usage: "Command that exits 0 for a good revision", Required: true},
+			&cli.StringFlag{Name: "good", Usage: "Known-good revision (default: oldest revision touching the paths)"},
+			&cli.StringFlag{Name: "bad", Value: "HEAD", Usage: "Known-bad revision"},
+			&cli.StringSliceFlag{Name: "path", Aliases: []string{"p"}, Usage: "Repo-relative path to track (repeatable; default .claude)"},
+			&cli.DurationFlag{Name: "max-duration", Usage: "Treat revisions whose test takes longer than this as bad (e.g. 1500ms)"},
+			&cli.BoolFlag{Name: "no-sync", Usage: "Do not run 'hooks custom sync' in the sandbox"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			root, err := core.GitOutput(".", "rev-parse", "--show-toplevel")
+			if err != nil {
+				return fmt.Errorf("not inside a git repository: %w\n  Suggestion: Run config bisect from your project checkout", err)
+			}
+			opts := bisectOptions{
+				repoDir:     strings.TrimSpace(root),
+				paths:       cmd.StringSlice("path"),
+				testCmd:     cmd.String("test"),
+				good:        cmd.String("good"),
+				bad:         cmd.String("bad"),
+				maxDuration: cmd.Duration("max-duration"),
+			}
+			if len(opts.paths) == 0 {
+				opts.paths = defaultBisectPaths
+			}
+			if !cmd.Bool("no-sync") {
+				opts.sync = syncSandboxSettings
+			}
+
+			result, err := runConfigBisect(ctx, opts, os.Stdout)
+			if err != nil {
+				return err
+			}
+			printBisectResult(opts, result)
+			return nil
+		},
+	}
+}
+
+// runConfigBisect binary-searches the revisions that touched opts.paths
+func runConfigBisect(ctx context.Context, opts bisectOptions, out io.Writer) (*bisectResult, error) {
+	revs, err := bisectRevisions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) < 2 {
+		return nil, fmt.Errorf("need at least two revisions touching %s to bisect, found %d\n  Suggestion: Pass --good with an older revision", strings.Join(opts.paths, ", "), len(revs))
+	}
+
+	sandboxRoot, err := os.MkdirTemp("", "bt-bisect-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(sandboxRoot) }()
+
+	test := func(rev string) (bool, error) {
+		good, detail, err := testRevision(ctx, opts, sandboxRoot, rev)
+		if err != nil {
+			return false, err
+		}
+		verdict := "good"
+		if !good {
+			verdict = "bad"
+		}
+		_, _ = fmt.Fprintf(out, "  %s %-4s %s\n", shortRev(rev), verdict, detail)
+		return good, nil
+	}
+
+	_, _ = fmt.Fprintf(out, "🔎 Bisecting %d revisions of %s\n", len(revs), strings.Join(opts.paths, ", "))
+	lo, hi := 0, len(revs)-1
+	steps := 0
+	if good, err := test(revs[hi]); err != nil {
+		return nil, err
+	} else if good {
+		return nil, fmt.Errorf("bad revision %s passes the test; nothing to bisect", shortRev(revs[hi]))
+	}
+	if good, err := test(revs[lo]); err != nil {
+		return nil, err
+	} else if !good {
+		return nil, fmt.Errorf("good revision %s already fails the test\n  Suggestion: Pass an older --good revision", shortRev(revs[lo]))
+	}
+	steps += 2
+
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		good, err := test(revs[mid])
+		if err != nil {
+			return nil, err
+		}
+		steps++
+		if good {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return &bisectResult{FirstBad: revs[hi], LastGood: revs[lo], Steps: steps}, nil
+}
+
+// bisectRevisions lists candidate revisions oldest-first: the good revision
+// followed by every later commit that touched the tracked paths, up to bad
+func bisectRevisions(opts bisectOptions) ([]string, error) {
+	rangeSpec := opts.bad
+	if opts.good != "" {
+		rangeSpec = opts.good + ".." + opts.bad
+	}
+	args := append([]string{"rev-list", "--reverse", rangeSpec, "--"}, opts.paths...)
+	listing, err := core.GitOutput(opts.repoDir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	revs := strings.Fields(listing)
+
+	if opts.good != "" {
+		good, err := core.GitOutput(opts.repoDir, "rev-parse", "--verify", opts.good+"^{commit}")
+		if err != nil {
+			return nil, fmt.Errorf("unknown --good revision '%s': %w", opts.good, err)
+		}
+		revs = append([]string{strings.TrimSpace(good)}, revs...)
+	}
+	return revs, nil
+}
+
+// testRevision materializes rev into a fresh sandbox, syncs it, and runs the test
+func testRevision(ctx context.Context, opts bisectOptions, sandboxRoot, rev string) (bool, string, error) {
+	sandbox := filepath.Join(sandboxRoot, shortRev(rev))
+	if err := materializeRevision(opts.repoDir, rev, opts.paths, sandbox); err != nil {
+		return false, "", err
+	}
+	if opts.sync != nil {
+		if err := opts.sync(sandbox); err != nil {
+			return false, fmt.Sprintf("(sync failed: %v)", err), nil
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", opts.testCmd) // #nosec G204 - user-provided test command
+	core.ConfigureCancel(cmd)
+	cmd.Dir = sandbox
+	cmd.Env = append(os.Environ(),
+		"BT_BISECT_REV="+rev,
+		"BT_BISECT_SANDBOX="+sandbox,
+		"BT_PROJECT_ROOT="+opts.repoDir,
+	)
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if ctx.Err() != nil {
+		// An interrupted test says nothing about the revision
+		return false, "", fmt.Errorf("bisect cancelled: %w", ctx.Err())
+	}
+	if err != nil {
+		return false, fmt.Sprintf("(test failed after %s: %v)", elapsed, err), nil
+	}
+	if opts.maxDuration > 0 && elapsed > opts.maxDuration {
+		return false, fmt.Sprintf("(took %s, limit %s)", elapsed, opts.maxDuration), nil
+	}
+	return true, fmt.Sprintf("(%s)", elapsed), nil
+}
+
+// materializeRevision writes the files under paths at rev into dir
+func materializeRevision(repoDir, rev string, paths []string, dir string) error {
+	args := append([]string{"ls-tree", "-r", "--name-only", rev, "--"}, paths...)
+	listing, err := core.GitOutput(repoDir, args...)
+	if err != nil {
+		return fmt.Errorf("failed to list files at %s: %w", shortRev(rev), err)
+	}
+	for _, name := range strings.Split(listing, "\n") {
+		if name == "" {
+			continue
+		}
+		content, err := core.GitOutput(repoDir, "show", rev+":"+name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s at %s: %w", name, shortRev(rev), err)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return fmt.Errorf("failed to create sandbox directory: %w", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("failed to write sandbox file: %w", err)
+		}
+	}
+	return os.MkdirAll(dir, 0o750)
+}
+
+// syncSandboxSettings runs 'hooks custom sync' with the sandbox as project root
+func syncSandboxSettings(sandbox string) error {
+	cmd := exec.Command(resolveExecutablePath(), "hooks", "custom", "sync") // #nosec G204 - invoking our own binary
+	cmd.Dir = sandbox
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func shortRev(rev string) string {
+	if len(rev) > 10 {
+		return rev[:10]
+	}
+	return rev
+}
+
+func printBisectResult(opts bisectOptions, r *bisectResult) {
+	output.Println()
+	summary, err := core.GitOutput(opts.repoDir, "log", "-1", "--format=%h %s (%an, %ad)", "--date=short", r.FirstBad)
+	summary = strings.TrimSpace(summary)
+	if err != nil {
+		summary = shortRev(r.FirstBad)
+	}
+	output.Printf("🎯 First bad revision after %d tests: %s\n", r.Steps, summary)
+	args := append([]string{"diff", "--stat", r.LastGood, r.FirstBad, "--"}, opts.paths...)
+	if stat, err := core.GitOutput(opts.repoDir, args...); err == nil && stat != "" {
+		output.Println(strings.TrimRight(stat, "\n"))
+	}
+	output.Printf("   Inspect with: git diff %s %s -- %s\n", shortRev(r.LastGood), shortRev(r.FirstBad), strings.Join(opts.paths, " "))
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initBisectRepo creates a repo whose .claude/hooks.yml changes each commit,
+// plus an unrelated commit that bisect should ignore. It returns the repo dir
+// and the commit hashes in order.
+func initBisectRepo(t *testing.T, contents []string) (string, []string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+
+	var revs []string
+	for i, c := range contents {
+		if err := os.MkdirAll(filepath.Join(dir, ".claude"), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".claude", "hooks.yml"), []byte(c), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "config "+string(rune('a'+i)))
+		revs = append(revs, git("rev-parse", "HEAD"))
+
+		if err := os.WriteFile(filepath.Join(dir, "README"), []byte(c), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "unrelated")
+	}
+	return dir, revs
+}
+
+func TestRunConfigBisect_FindsFirstBadRevision(t *testing.T) {
+	dir, revs := initBisectRepo(t, []string{
+		"ok: 1\n", "ok: 2\n", "ok: 3\n", "broken: true\n", "broken: still\n", "broken: yes\n",
+	})
+
+	synced := 0
+	var out bytes.Buffer
+	result, err := runConfigBisect(context.Background(), bisectOptions{
+		repoDir: dir,
+		paths:   []string{".claude"},
+		testCmd: `! grep -q broken .claude/hooks.yml && [ "$BT_PROJECT_ROOT" = "` + dir + `" ]`,
+		bad:     "HEAD",
+		sync:    func(string) error { synced++; return nil },
+	}, &out)
+	if err != nil {
+		t.Fatalf("bisect: %v\n%s", err, out.String())
+	}
+	if result.FirstBad != revs[3] || result.LastGood != revs[2] {
+		t.Errorf("first bad = %s, last good = %s; want %s, %s\n%s", result.FirstBad, result.LastGood, revs[3], revs[2], out.String())
+	}
+	if synced != result.Steps {
+		t.Errorf("sync ran %d times, want once per tested revision (%d)", synced, result.Steps)
+	}
+	if result.Steps >= len(revs)*2 {
+		t.Errorf("bisect should only test config revisions, took %d steps", result.Steps)
+	}
+}
+
+func TestRunConfigBisect_Errors(t *testing.T) {
+	dir, revs := initBisectRepo(t, []string{"ok: 1\n", "ok: 2\n", "ok: 3\n"})
+
+	_, err := runConfigBisect(context.Background(), bisectOptions{repoDir: dir, paths: []string{".claude"}, testCmd: "true", bad: "HEAD"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "nothing to bisect") {
+		t.Errorf("all-good history: err = %v", err)
+	}
+
+	_, err = runConfigBisect(context.Background(), bisectOptions{repoDir: dir, paths: []string{".claude"}, testCmd: "false", bad: "HEAD"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "already fails") {
+		t.Errorf("all-bad history: err = %v", err)
+	}
+
+	_, err = runConfigBisect(context.Background(), bisectOptions{repoDir: dir, paths: []string{".claude"}, testCmd: "true", good: revs[2], bad: "HEAD"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "at least two revisions") {
+		t.Errorf("single revision: err = %v", err)
+	}
+}
+
+func TestRunConfigBisect_LatencyRegression(t *testing.T) {
+	dir, revs := initBisectRepo(t, []string{"sleep: 0\n", "sleep: 0 # tweak\n", "sleep: 1\n"})
+
+	result, err := runConfigBisect(context.Background(), bisectOptions{
+		repoDir:     dir,
+		paths:       []string{".claude"},
+		testCmd:     `if grep -q "sleep: 1" .claude/hooks.yml; then sleep 0.5; fi`,
+		bad:         "HEAD",
+		maxDuration: 250 * time.Millisecond,
+	}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("bisect: %v", err)
+	}
+	if result.FirstBad != revs[2] {
+		t.Errorf("first bad = %s, want %s", result.FirstBad, revs[2])
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// importFormatBundle is a group bundle written by 'config export'
+const importFormatBundle = "bundle"
+
+// NewConfigExportCmd creates the config export subcommand
+func NewConfigExportCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Package a custom hook group and its scripts as a shareable bundle",
+		ArgsUsage: "<group>",
+		Description: `Write a hook group's definition, plus the project scripts its jobs run, to a
+.tar.gz bundle that 'config import' unpacks in another repository. Scripts are
+found from the words of each job's run: command that name files under the
+project (relative to the job's workdir or the project root, or under
+$PROJECT_ROOT); tools on PATH and absolute paths are not bundled.
+
+Examples:
+  blues-traveler config export lint --out lint.tar.gz
+  blues-traveler config import lint.tar.gz            # in the other repository`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "Bundle file to write (default: <group>.tar.gz)"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: <group>")
+			}
+			out := cmd.String("out")
+			if out == "" {
+				out = args[0] + ".tar.gz"
+			}
+			return executeExportBundle(args[0], out)
+		},
+	}
+}
+
+// executeExportBundle writes group's bundle to out
+func executeExportBundle(groupName, out string) error {
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		return fmt.Errorf("load hooks config: %w", err)
+	}
+	group, ok := (*cfg)[groupName]
+	if !ok || group == nil || len(group.Events) == 0 {
+		return fmt.Errorf("group '%s' not found\n  Suggestion: Run 'blues-traveler hooks custom list' to see available groups", groupName)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	bundle, err := config.NewGroupBundle(groupName, group, root)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600) // #nosec G304 - user-chosen output file
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", out, err)
+	}
+	if err := config.WriteGroupBundle(f, bundle); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+
+	output.Printf("✅ Exported group '%s' to %s\n", groupName, out)
+	for _, file := range bundle.Files {
+		output.Printf("   + %s\n", file.Path)
+	}
+	if len(bundle.Files) == 0 {
+		output.Println("   (no project scripts referenced)")
+	}
+	return nil
+}
+
+// isBundlePath reports whether path names a gzipped tar bundle
+func isBundlePath(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// importBundle unpacks a group bundle: the group file goes to .claude/hooks
+// and the scripts to their paths under the project. Existing files are kept
+// unless opts.overwrite is set. The group is not installed into settings.
+func importBundle(opts importOptions) error {
+	f, err := os.Open(opts.source) // #nosec G304 - user-specified import source
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", opts.source, err)
+	}
+	bundle, err := config.ReadGroupBundle(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.source, err)
+	}
+	if opts.groupSet {
+		bundle.Rename(opts.group)
+	}
+
+	out, err := config.EncodeHooksConfig(bundle.Config, config.FormatYAML)
+	if err != nil {
+		return fmt.Errorf("failed to render group: %w", err)
+	}
+	if opts.dryRun {
+		output.Print(string(out))
+		output.Printf("\n# Would import group '%s' from %s with %d script(s):\n", bundle.Group, opts.source, len(bundle.Files))
+		for _, file := range bundle.Files {
+			output.Printf("#   %s\n", file.Path)
+		}
+		return nil
+	}
+
+	root, err := bundleRoot(opts.global)
+	if err != nil {
+		return err
+	}
+	if !opts.overwrite {
+		for _, file := range bundle.Files {
+			target := filepath.Join(root, filepath.FromSlash(file.Path))
+			if _, err := os.Stat(target); err == nil {
+				return fmt.Errorf("script %s already exists\n  Suggestion: Pass --overwrite to replace it", target)
+			}
+		}
+	}
+
+	opts.group = bundle.Group
+	groupPath, err := writeImportedGroup(opts, out)
+	if err != nil {
+		return err
+	}
+	for _, file := range bundle.Files {
+		target := filepath.Join(root, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, file.Data, file.Mode|0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		// WriteFile keeps the mode of a file it overwrites
+		if err := os.Chmod(target, file.Mode|0o600); err != nil {
+			return fmt.Errorf("failed to set mode of %s: %w", target, err)
+		}
+	}
+
+	output.Printf("✅ Imported group '%s' from %s\n", bundle.Group, opts.source)
+	output.Printf("   Group file: %s\n", groupPath)
+	for _, file := range bundle.Files {
+		output.Printf("   Script:     %s\n", file.Path)
+	}
+	output.Printf("   Install with: blues-traveler hooks custom install %s\n", bundle.Group)
+	return nil
+}
+
+// bundleRoot is the directory bundled scripts are extracted under: the
+// project, or the home directory for --global
+func bundleRoot(global bool) (string, error) {
+	if global {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return home, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return cwd, nil
+}
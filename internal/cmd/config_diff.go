@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// diffContextLines is how many unchanged lines surround each hunk
+const diffContextLines = 3
+
+// diffEventLinePattern finds the event a line of rendered hooks JSON belongs to
+var diffEventLinePattern = regexp.MustCompile(`^  "(\w+)": \[`)
+
+// NewConfigDiffCmd creates the config diff subcommand
+func NewConfigDiffCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Show how 'hooks custom sync' would change settings.json",
+		ArgsUsage: "[group]",
+		Description: `Compare the hooks in settings.json with what 'hooks custom sync' would write,
+without changing anything. The hooks section is shown as a unified diff with
+each hunk labelled by event, followed by the entries added and removed per
+group (config groups and bt-builtin for built-in installs). Takes the same
+filters as sync. With --exit-code the command fails when settings have
+drifted, for CI checks.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Compare global settings (~/.claude/settings.json)"},
+			&cli.StringFlag{Name: "event", Aliases: []string{"e"}, Usage: "Restrict the comparison to a single event"},
+			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events, as for sync"},
+			&cli.StringFlag{Name: "post-matcher", Value: "Edit,Write", Usage: "Matcher for PostToolUse when not overridden, as for sync"},
+			&cli.IntFlag{Name: "timeout", Aliases: []string{"t"}, Usage: "Timeout override in seconds, as for sync"},
+			&cli.BoolFlag{Name: "exit-code", Usage: "Exit with an error when settings differ"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			opts, err := parseSyncFlags(cmd, true, core.IsValidEventType, core.ValidEventTypes)
+			if err != nil {
+				return err
+			}
+			opts.quiet = true
+
+			hooksCfg, proposed, settingsPath, err := loadSyncDependencies(opts.useGlobal)
+			if err != nil {
+				return err
+			}
+			current, err := config.LoadSettings(settingsPath)
+			if err != nil {
+				return err
+			}
+			performSync(proposed, hooksCfg, opts)
+
+			diff, err := diffSettingsHooks(settingsPath, current, proposed)
+			if err != nil {
+				return err
+			}
+			if diff == nil {
+				output.Printf("✅ %s matches what sync would write.\n", settingsPath)
+				return nil
+			}
+			output.Print(diff.Text)
+			output.Println()
+			output.Println("Changes by group:")
+			for _, g := range diff.Groups {
+				output.Printf("  %-20s +%d -%d\n", g.Group, g.Added, g.Removed)
+			}
+			if cmd.Bool("exit-code") {
+				return fmt.Errorf("settings have drifted from the hooks config\n  Suggestion: Run 'blues-traveler hooks custom sync' to apply the changes above")
+			}
+			return nil
+		},
+	}
+}
+
+// settingsDiff is the difference between current and synced settings hooks
+type settingsDiff struct {
+	// Text is the unified diff of the hooks section
+	Text   string
+	Groups []groupDiff
+}
+
+// groupDiff counts settings entries a group gains and loses
+type groupDiff struct {
+	Group   string
+	Added   int
+	Removed int
+}
+
+// diffSettingsHooks compares the hooks sections; nil means they match
+func diffSettingsHooks(settingsPath string, current, proposed *config.Settings) (*settingsDiff, error) {
+	before, err := renderHooksLines(current.Hooks)
+	if err != nil {
+		return nil, err
+	}
+	after, err := renderHooksLines(proposed.Hooks)
+	if err != nil {
+		return nil, err
+	}
+	hunks := unifiedDiff(before, after, diffContextLines)
+	if len(hunks) == 0 {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s (current)\n+++ %s (after sync)\n", settingsPath, settingsPath)
+	for _, h := range hunks {
+		b.WriteString(h)
+	}
+	return &settingsDiff{Text: b.String(), Groups: diffGroups(current.Hooks, proposed.Hooks)}, nil
+}
+
+// renderHooksLines renders hooks the way SaveSettings indents them
+func renderHooksLines(hooks config.HooksConfig) ([]string, error) {
+	data, err := json.MarshalIndent(hooks, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render hooks: %w", err)
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// diffGroups counts added and removed entries per group. An entry is an
+// event, matcher and command; commands blues-traveler doesn't manage are
+// left out since sync never touches them.
+func diffGroups(before, after config.HooksConfig) []groupDiff {
+	type entry struct{ event, matcher, command string }
+	collect := func(hooks config.HooksConfig) map[entry]string {
+		entries := map[entry]string{}
+		for _, h := range config.InstalledHooks(hooks) {
+			if group := config.HookCommandGroup(h.Command); group != "" {
+				entries[entry{h.Event, h.Matcher, h.Command}] = group
+			}
+		}
+		return entries
+	}
+	old, updated := collect(before), collect(after)
+
+	counts := map[string]*groupDiff{}
+	count := func(group string) *groupDiff {
+		if counts[group] == nil {
+			counts[group] = &groupDiff{Group: group}
+		}
+		return counts[group]
+	}
+	for e, group := range updated {
+		if _, ok := old[e]; !ok {
+			count(group).Added++
+		}
+	}
+	for e, group := range old {
+		if _, ok := updated[e]; !ok {
+			count(group).Removed++
+		}
+	}
+
+	groups := make([]groupDiff, 0, len(counts))
+	for _, g := range counts {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Group < groups[j].Group })
+	return groups
+}
+
+// unifiedDiff returns the hunks turning a into b, each with its header.
+// Headers end with the events the hunk changes, where git would show the
+// enclosing function.
+func unifiedDiff(a, b []string, context int) []string {
+	ops := diffOps(a, b)
+	var hunks []string
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are closer than two contexts apart
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		stop := min(end+context, len(ops))
+		hunks = append(hunks, formatHunk(ops[start:stop], a, b))
+		i = stop
+	}
+	return hunks
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added.
+// aLine and bLine are the 0-based positions before the op in a and b.
+type diffOp struct {
+	kind         byte
+	text         string
+	aLine, bLine int
+}
+
+// diffOps computes a line edit script from the longest common subsequence
+func diffOps(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// formatHunk renders ops with a "@@ -a,n +b,m @@ Events" header
+func formatHunk(ops []diffOp, a, bLines []string) string {
+	aCount, bCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aCount++
+		}
+		if op.kind != '-' {
+			bCount++
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%s +%s @@", hunkRange(ops[0].aLine, aCount), hunkRange(ops[0].bLine, bCount))
+	if events := hunkEvents(a, bLines, ops); len(events) > 0 {
+		b.WriteString(" " + strings.Join(events, ", "))
+	}
+	b.WriteString("\n")
+	for _, op := range ops {
+		b.WriteByte(op.kind)
+		b.WriteString(op.text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// hunkRange formats a 0-based start and line count as unified diff does
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// hunkEvents names the events the hunk's changed lines belong to, in order
+func hunkEvents(a, b []string, ops []diffOp) []string {
+	var events []string
+	for _, op := range ops {
+		var event string
+		switch op.kind {
+		case '-':
+			event = eventAt(a, op.aLine)
+		case '+':
+			event = eventAt(b, op.bLine)
+		default:
+			continue
+		}
+		if event != "" && !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// eventAt returns the event key at or above line i of rendered hooks JSON
+func eventAt(lines []string, i int) string {
+	for ; i >= 0; i-- {
+		if m := diffEventLinePattern.FindStringSubmatch(lines[i]); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
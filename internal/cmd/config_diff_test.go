@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []string{"{", `  "PreToolUse": [`, "    a", "    b", "  ]", "}"}
+	b := []string{"{", `  "PreToolUse": [`, "    a", "    c", "  ]", "}"}
+	hunks := unifiedDiff(a, b, 1)
+	if len(hunks) != 1 {
+		t.Fatalf("expected one hunk, got %q", hunks)
+	}
+	want := "@@ -3,3 +3,3 @@ PreToolUse\n     a\n-    b\n+    c\n   ]\n"
+	if hunks[0] != want {
+		t.Fatalf("hunk = %q, want %q", hunks[0], want)
+	}
+	if hunks := unifiedDiff(a, a, 3); len(hunks) != 0 {
+		t.Fatalf("expected no hunks for identical input, got %q", hunks)
+	}
+}
+
+func TestDiffSettingsHooks(t *testing.T) {
+	current := &config.Settings{}
+	config.AddHookToSettings(current, "PreToolUse", "*", "blues-traveler hooks run config:old:lint", nil)
+	config.AddHookToSettings(current, "PreToolUse", "Bash", "blues-traveler hooks run security", nil)
+	config.AddHookToSettings(current, "PreToolUse", "Bash", "other-tool check", nil)
+
+	proposed := &config.Settings{}
+	config.AddHookToSettings(proposed, "PreToolUse", "Bash", "blues-traveler hooks run security", nil)
+	config.AddHookToSettings(proposed, "PreToolUse", "Bash", "other-tool check", nil)
+	config.AddHookToSettings(proposed, "PostToolUse", "Edit,Write", "blues-traveler hooks run config:go:fmt", nil)
+
+	if diff, err := diffSettingsHooks("settings.json", current, current); err != nil || diff != nil {
+		t.Fatalf("expected no diff for identical settings, got %+v, %v", diff, err)
+	}
+
+	diff, err := diffSettingsHooks("settings.json", current, proposed)
+	if err != nil || diff == nil {
+		t.Fatalf("expected a diff, got %+v, %v", diff, err)
+	}
+	for _, want := range []string{
+		"--- settings.json (current)\n+++ settings.json (after sync)\n",
+		`-          "command": "blues-traveler hooks run config:old:lint"`,
+		`+          "command": "blues-traveler hooks run config:go:fmt"`,
+		"@@ PreToolUse, PostToolUse\n",
+	} {
+		if !strings.Contains(diff.Text, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff.Text)
+		}
+	}
+	want := []groupDiff{{Group: "go", Added: 1}, {Group: "old", Removed: 1}}
+	if len(diff.Groups) != len(want) || diff.Groups[0] != want[0] || diff.Groups[1] != want[1] {
+		t.Fatalf("groups = %+v, want %+v", diff.Groups, want)
+	}
+}
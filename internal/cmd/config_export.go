@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewConfigExportScriptCmd creates the config export-script subcommand
+func NewConfigExportScriptCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "export-script",
+		Usage:     "Export a custom hook group as a standalone shell script",
+		ArgsUsage: "<group>",
+		Description: `Generate a self-contained bash script that replicates a custom hook group's jobs,
+with only/skip conditions and glob filters inlined. Useful where the blues-traveler
+binary cannot be installed, and for reviewing exactly what a group does.
+
+The script takes the event name as its first argument and reads the hook event
+JSON from stdin (jq is used to extract tool context when available):
+
+  blues-traveler config export-script my-group -o my-group.sh
+  echo '{"tool_name":"Edit","tool_input":{"file_path":"main.go"}}' | ./my-group.sh PostToolUse`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the script to this file (made executable) instead of stdout",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: <group>")
+			}
+			return executeExportScriptCommand(args[0], cmd.String("output"))
+		},
+	}
+}
+
+// executeExportScriptCommand loads the group and writes its script
+func executeExportScriptCommand(groupName, outPath string) error {
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		return fmt.Errorf("load hooks config: %w", err)
+	}
+	group, ok := (*cfg)[groupName]
+	if !ok || group == nil || len(group.Events) == 0 {
+		return fmt.Errorf("group '%s' not found\n  Suggestion: Run 'blues-traveler hooks custom list' to see available groups", groupName)
+	}
+
+	script := buildGroupScript(groupName, group)
+	if outPath == "" {
+		fmt.Print(script)
+		return nil
+	}
+	if err := os.WriteFile(outPath, []byte(script), 0o755); err != nil { // #nosec G306 - generated script must be executable
+		return fmt.Errorf("failed to write script: %w", err)
+	}
+	_, _ = fmt.Fprintf(output.Stderr(), "✅ Exported group '%s' to %s\n", groupName, outPath)
+	return nil
+}
+
+// buildGroupScript renders a standalone bash script for a hook group
+func buildGroupScript(groupName string, group *config.HookGroup) string {
+	events := make([]string, 0, len(group.Events))
+	for name, ev := range group.Events {
+		if ev != nil && len(ev.Jobs) > 0 {
+			events = append(events, name)
+		}
+	}
+	sort.Strings(events)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `#!/usr/bin/env bash
+# Standalone export of blues-traveler custom hook group %q.
+# Generated by 'blues-traveler config export-script %s'; edits here are not synced back.
+#
+# Usage: $0 <Event> < event.json
+#   Events: %s
+# Event JSON on stdin is optional. With jq installed, TOOL_NAME, FILES_CHANGED,
+# TOOL_FILE, TOOL_OUTPUT_FILE and USER_PROMPT are derived from it; otherwise set
+# them in the environment. MCP_SERVER and MCP_TOOL come from an MCP TOOL_NAME.
+# Exits 2 (blocking) if any job fails.
+set -uo pipefail
+
+`, groupName, groupName, strings.Join(events, ", "))
+	if group.Description != "" {
+		fmt.Fprintf(&b, "# %s\n\n", scriptComment(group.Description))
+	}
+
+	b.WriteString(core.ShellExpressionHelpers())
+	b.WriteString(exportScriptRuntime)
+	if group.Lock != "" {
+		fmt.Fprintf(&b, "\n# NOTE: lock %q is not enforced by this script; jobs may overlap with other sessions.\n", group.Lock)
+	}
+
+	for ei, eventName := range events {
+		ev := group.Events[eventName]
+		fmt.Fprintf(&b, "\n# --- %s %s\n", eventName, strings.Repeat("-", 60-len(eventName)))
+		for ji, job := range ev.Jobs {
+			writeJobFunction(&b, ei, ji, job)
+		}
+		writeLifecycleFunction(&b, fmt.Sprintf("before_all_%d", ei), ev.BeforeAll)
+		writeLifecycleFunction(&b, fmt.Sprintf("after_all_%d", ei), ev.AfterAll)
+		writeEventFunction(&b, ei, eventName, ev)
+	}
+
+	b.WriteString("\nBT_EVENT=\"${1:-${EVENT_NAME:-}}\"\n")
+	b.WriteString("bt_load_event \"$BT_EVENT\"\n\n")
+	b.WriteString("case \"$BT_EVENT\" in\n")
+	for ei, eventName := range events {
+		fmt.Fprintf(&b, "  %s) event_%d ;;\n", eventName, ei)
+	}
+	b.WriteString("  \"\") echo \"usage: $0 <Event> (one of: " + strings.Join(events, ", ") + ")\" >&2; exit 1 ;;\n")
+	b.WriteString("  *) exit 0 ;; # group has no jobs for this event\n")
+	b.WriteString("esac\n")
+	b.WriteString("rc=$?\n")
+	fmt.Fprintf(&b, "[ \"$rc\" -eq 0 ] || { echo %s\": job failure in $BT_EVENT\" >&2; exit 2; }\n", core.ShellQuote(groupName))
+	return b.String()
+}
+
+// scriptComment continues a multi-line description as shell comments
+func scriptComment(text string) string {
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n# ")
+}
+
+// writeJobFunction emits one job with its conditions inlined
+func writeJobFunction(b *strings.Builder, ei, ji int, job config.HookJob) {
+	fmt.Fprintf(b, "\n# job: %s\n", job.Name)
+	if job.Description != "" {
+		fmt.Fprintf(b, "# %s\n", scriptComment(job.Description))
+	}
+	fmt.Fprintf(b, "job_%d_%d() {\n", ei, ji)
+	if strings.TrimSpace(job.Skip) != "" {
+		fmt.Fprintf(b, "  # skip: %s\n  if %s; then return 0; fi\n", job.Skip, core.ExpressionToShell(job.Skip))
+	}
+	if strings.TrimSpace(job.Only) != "" {
+		fmt.Fprintf(b, "  # only: %s\n  if ! { %s; }; then return 0; fi\n", job.Only, core.ExpressionToShell(job.Only))
+	}
+	if job.Scope == config.JobScopeGit {
+		b.WriteString("  # scope: git is not supported here; glob applies to the event's files\n")
+	}
+	if job.Serialize {
+		b.WriteString("  # serialize is not enforced here; instances may overlap\n")
+	}
+	if len(job.Glob) > 0 {
+		quoted := make([]string, len(job.Glob))
+		for i, g := range job.Glob {
+			quoted[i] = core.ShellQuote(g)
+		}
+		fmt.Fprintf(b, "  bt_files_match %s || return 0\n", strings.Join(quoted, " "))
+	}
+
+	b.WriteString("  (\n")
+	if job.WorkDir != "" {
+		fmt.Fprintf(b, "    cd %s || exit 1\n", core.ShellQuote(job.WorkDir))
+	}
+	if len(job.EnvFile) > 0 {
+		b.WriteString("    set -a\n")
+		for _, f := range job.EnvFile {
+			if rest, ok := strings.CutPrefix(f, "~/"); ok {
+				fmt.Fprintf(b, "    . \"$HOME\"/%s || exit 1\n", core.ShellQuote(rest))
+			} else {
+				fmt.Fprintf(b, "    . %s || exit 1\n", core.ShellQuote(f))
+			}
+		}
+		b.WriteString("    set +a\n")
+	}
+	envKeys := make([]string, 0, len(job.Env))
+	for k := range job.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		fmt.Fprintf(b, "    export %s=%s\n", k, core.ShellQuote(job.Env[k]))
+	}
+	fmt.Fprintf(b, "    printf '%%s' \"$BT_EVENT_JSON\" | bt_with_timeout %d bash -lc %s\n", job.Timeout, core.ShellQuote(job.Run))
+	b.WriteString("  )\n}\n")
+}
+
+// writeLifecycleFunction emits a before_all or after_all command; nothing
+// when the event has none
+func writeLifecycleFunction(b *strings.Builder, name string, lc *config.LifecycleCommand) {
+	if lc == nil {
+		return
+	}
+	fmt.Fprintf(b, "\n%s() {\n  (\n", name)
+	if lc.WorkDir != "" {
+		fmt.Fprintf(b, "    cd %s || exit 1\n", core.ShellQuote(lc.WorkDir))
+	}
+	envKeys := make([]string, 0, len(lc.Env))
+	for k := range lc.Env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		fmt.Fprintf(b, "    export %s=%s\n", k, core.ShellQuote(lc.Env[k]))
+	}
+	fmt.Fprintf(b, "    printf '%%s' \"$BT_EVENT_JSON\" | bt_with_timeout %d bash -lc %s\n", lc.Timeout, core.ShellQuote(lc.Run))
+	b.WriteString("  )\n}\n")
+}
+
+// writeEventFunction emits the dispatcher for an event, honoring chain,
+// parallel and the before_all/after_all commands around the jobs
+func writeEventFunction(b *strings.Builder, ei int, eventName string, ev *config.EventConfig) {
+	fmt.Fprintf(b, "\n# %s dispatcher\nevent_%d() {\n  local rc=0\n", eventName, ei)
+	if ev.BeforeAll != nil {
+		fmt.Fprintf(b, "  if ! before_all_%d; then\n", ei)
+		if ev.BeforeAll.Continues() {
+			b.WriteString("    echo \"before_all failed; running jobs anyway\" >&2\n  fi\n")
+		} else {
+			b.WriteString("    echo \"before_all failed; skipping jobs\" >&2\n    rc=1\n  fi\n  if [ \"$rc\" -eq 0 ]; then\n")
+		}
+	}
+	writeEventJobs(b, ei, ev)
+	if ev.BeforeAll != nil && !ev.BeforeAll.Continues() {
+		b.WriteString("  fi\n")
+	}
+	if ev.AfterAll != nil {
+		if ev.AfterAll.Continues() {
+			fmt.Fprintf(b, "  after_all_%d || echo \"after_all failed\" >&2\n", ei)
+		} else {
+			fmt.Fprintf(b, "  after_all_%d || rc=1\n", ei)
+		}
+	}
+	b.WriteString("  return $rc\n}\n")
+}
+
+// writeEventJobs emits the calls running an event's jobs
+func writeEventJobs(b *strings.Builder, ei int, ev *config.EventConfig) {
+	switch {
+	case ev.Chain:
+		// Each job's $BT_OUTPUT lines are exported for the jobs after it
+		for ji := range ev.Jobs {
+			fmt.Fprintf(b, "  BT_OUTPUT=$(mktemp) && export BT_OUTPUT\n  job_%d_%d || rc=1\n  bt_import_outputs \"$BT_OUTPUT\"\n", ei, ji)
+		}
+	case ev.Parallel:
+		b.WriteString("  local pids=()\n")
+		for ji := range ev.Jobs {
+			fmt.Fprintf(b, "  job_%d_%d & pids+=($!)\n", ei, ji)
+		}
+		b.WriteString("  local pid\n  for pid in \"${pids[@]}\"; do wait \"$pid\" || rc=1; done\n")
+	default:
+		for ji := range ev.Jobs {
+			fmt.Fprintf(b, "  job_%d_%d || rc=1\n", ei, ji)
+		}
+	}
+}
+
+// exportScriptRuntime holds the event loading, glob, timeout and chain helpers
+const exportScriptRuntime = `
+# bt_files_match GLOB...: true if any file in FILES_CHANGED matches any glob
+bt_files_match() (
+  set -f
+  for f in ${FILES_CHANGED:-}; do
+    for g in "$@"; do
+      # shellcheck disable=SC2254
+      case "$f" in $g) exit 0 ;; esac
+    done
+  done
+  exit 1
+)
+
+# bt_with_timeout SECONDS CMD...: run CMD, bounded by SECONDS when >0 and timeout(1) exists
+bt_with_timeout() {
+  local secs=$1; shift
+  if [ "$secs" -gt 0 ] && command -v timeout >/dev/null 2>&1; then
+    timeout "$secs" "$@"
+  else
+    "$@"
+  fi
+}
+
+# bt_import_outputs FILE: export the KEY=VALUE lines a chained job wrote to
+# FILE, except event and BT_ variables, then remove FILE
+bt_import_outputs() {
+  local line key
+  if [ -f "$1" ]; then
+    while IFS= read -r line || [ -n "$line" ]; do
+      line="${line#"${line%%[![:space:]]*}"}"
+      key=${line%%=*}
+      [ "$key" != "$line" ] || continue
+      case "$key" in
+        ''|[0-9]*|*[!A-Za-z0-9_]*|BT_*|EVENT_NAME|TOOL_NAME|MCP_SERVER|MCP_TOOL|FILES_CHANGED|TOOL_FILE|TOOL_OUTPUT_FILE|PROJECT_ROOT|USER_PROMPT) continue ;;
+      esac
+      export "$key=${line#*=}"
+    done < "$1"
+  fi
+  rm -f "$1"
+}
+
+# bt_load_event EVENT: read event JSON from stdin and derive hook variables
+bt_load_event() {
+  BT_EVENT_JSON=""
+  [ -t 0 ] || BT_EVENT_JSON="$(cat)"
+  export EVENT_NAME="$1"
+  if [ -n "$BT_EVENT_JSON" ] && command -v jq >/dev/null 2>&1; then
+    local j="$BT_EVENT_JSON" files file
+    : "${TOOL_NAME:=$(printf '%s' "$j" | jq -r '.tool_name // empty')}"
+    : "${USER_PROMPT:=$(printf '%s' "$j" | jq -r '.prompt // .user_prompt // empty')}"
+    if [ "$1" = "PostToolUse" ]; then
+      # Edit/Write/NotebookEdit paths plus per-edit MultiEdit paths, first-seen order
+      files=$(printf '%s' "$j" | jq -r '[.tool_input.file_path?, .tool_input.notebook_path?, (.tool_input.edits[]?.file_path?)]
+        | map(select(type == "string" and . != "")) | reduce .[] as $f ([]; if index([$f]) then . else . + [$f] end) | join(" ")')
+      if [ -n "$files" ]; then
+        file=${files%% *}
+        : "${FILES_CHANGED:=$files}" "${TOOL_FILE:=$file}" "${TOOL_OUTPUT_FILE:=$file}"
+      fi
+    fi
+  fi
+  : "${PROJECT_ROOT:=$(pwd)}"
+  # mcp__<server>__<tool>: the server ends at the first "__"
+  case "${TOOL_NAME:-}" in
+    mcp__?*__?*)
+      local mcp="${TOOL_NAME#mcp__}"
+      : "${MCP_SERVER:=${mcp%%__*}}" "${MCP_TOOL:=${mcp#*__}}"
+      ;;
+  esac
+  export TOOL_NAME="${TOOL_NAME:-}" FILES_CHANGED="${FILES_CHANGED:-}" TOOL_FILE="${TOOL_FILE:-}"
+  export TOOL_OUTPUT_FILE="${TOOL_OUTPUT_FILE:-}" USER_PROMPT="${USER_PROMPT:-}" PROJECT_ROOT
+  export MCP_SERVER="${MCP_SERVER:-}" MCP_TOOL="${MCP_TOOL:-}"
+}
+`
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func exportTestGroup(out string) *config.HookGroup {
+	return &config.HookGroup{Description: "Demo checks\nfor export", Lock: "fmt", Events: map[string]*config.EventConfig{
+		"PostToolUse": {
+			Jobs: []config.HookJob{
+				{Name: "go-files", Run: "echo go:$GREETING >> " + out, Glob: []string{"*.go"}, Env: map[string]string{"GREETING": "it's ok"}},
+				{Name: "ruby-files", Run: "echo ruby >> " + out, Glob: []string{"*.rb"}, Description: "Notes Ruby edits"},
+				{Name: "edit-only", Run: "echo edit >> " + out, Only: "${TOOL_NAME} == Edit"},
+				{Name: "skip-edit", Run: "echo skipped >> " + out, Skip: "${TOOL_NAME} matches Ed*"},
+			},
+		},
+		"PreToolUse": {
+			Parallel: true,
+			Jobs: []config.HookJob{
+				{Name: "fail", Run: "exit 3"},
+				{Name: "ok", Run: "true", Timeout: 5},
+			},
+		},
+		"SessionEnd": {
+			BeforeAll: &config.LifecycleCommand{Run: "echo up >> " + out + "; exit 1"},
+			AfterAll:  &config.LifecycleCommand{Run: "echo down >> " + out},
+			Jobs:      []config.HookJob{{Name: "work", Run: "echo work >> " + out}},
+		},
+		"SubagentStop": {
+			Jobs: []config.HookJob{{Name: "mcp", Run: "echo $MCP_SERVER/$MCP_TOOL >> " + out}},
+		},
+		"UserPromptSubmit": {
+			Chain: true,
+			Jobs: []config.HookJob{
+				{Name: "version", Run: `printf 'VERSION=1.2\nTOOL_NAME=spoofed\n' >> "$BT_OUTPUT"`},
+				{Name: "report", Run: "echo v$VERSION:$TOOL_NAME >> " + out},
+			},
+		},
+	}}
+}
+
+func TestBuildGroupScript_Contents(t *testing.T) {
+	script := buildGroupScript("demo", exportTestGroup("/tmp/out"))
+
+	for _, want := range []string{
+		"#!/usr/bin/env bash",
+		"set -uo pipefail",
+		"# Demo checks\n# for export\n",
+		"# job: ruby-files\n# Notes Ruby edits\njob_0_1() {",
+		"bt_glob_any()",
+		"# only: ${TOOL_NAME} == Edit",
+		`if ! { [ "${TOOL_NAME:-}" = "Edit" ]; }; then return 0; fi`,
+		"bt_files_match '*.go' || return 0",
+		`export GREETING='it'\''s ok'`,
+		`lock "fmt" is not enforced`,
+		"job_1_0 & pids+=($!)",
+		"  PostToolUse) event_0 ;;",
+		"  PreToolUse) event_1 ;;",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q\n%s", want, script)
+		}
+	}
+
+	if again := buildGroupScript("demo", exportTestGroup("/tmp/out")); again != script {
+		t.Error("script output should be deterministic")
+	}
+}
+
+func TestBuildGroupScript_Runs(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+	scriptPath := filepath.Join(dir, "demo.sh")
+	if err := os.WriteFile(scriptPath, []byte(buildGroupScript("demo", exportTestGroup(out))), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if syntax, err := exec.Command(bash, "-n", scriptPath).CombinedOutput(); err != nil { // #nosec G204 - test script
+		t.Fatalf("generated script has syntax errors: %v\n%s", err, syntax)
+	}
+
+	run := func(event string, env ...string) (int, string) {
+		cmd := exec.Command(bash, scriptPath, event) // #nosec G204 - test script
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdin = strings.NewReader("")
+		output, err := cmd.CombinedOutput()
+		code := 0
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("run %s: %v", event, err)
+		}
+		return code, string(output)
+	}
+
+	if code, output := run("PostToolUse", "TOOL_NAME=Edit", "FILES_CHANGED=main.go"); code != 0 {
+		t.Fatalf("PostToolUse exit = %d, output: %s", code, output)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "go:it's ok\nedit\n"; got != want {
+		t.Errorf("PostToolUse jobs wrote %q, want %q", got, want)
+	}
+
+	code, output := run("PreToolUse")
+	if code != 2 || !strings.Contains(output, "demo: job failure in PreToolUse") {
+		t.Errorf("PreToolUse exit = %d, output %q; want blocking exit 2", code, output)
+	}
+
+	if err := os.Remove(out); err != nil {
+		t.Fatal(err)
+	}
+	if code, output := run("UserPromptSubmit", "TOOL_NAME=none"); code != 0 {
+		t.Fatalf("UserPromptSubmit exit = %d, output: %s", code, output)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "v1.2:none\n" {
+		t.Errorf("chained job wrote %q (%v), want the earlier job's VERSION and the event's TOOL_NAME", data, err)
+	}
+
+	if err := os.Remove(out); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := run("SessionEnd"); code != 2 {
+		t.Errorf("SessionEnd exit = %d, want 2 after before_all failed", code)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "up\ndown\n" {
+		t.Errorf("SessionEnd wrote %q (%v), want the job skipped and after_all still run", data, err)
+	}
+
+	if err := os.Remove(out); err != nil {
+		t.Fatal(err)
+	}
+	if code, output := run("SubagentStop", "TOOL_NAME=mcp__github__create__issue"); code != 0 {
+		t.Fatalf("SubagentStop exit = %d, output: %s", code, output)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "github/create__issue\n" {
+		t.Errorf("MCP job wrote %q (%v), want the server and tool split at the first __", data, err)
+	}
+
+	if code, _ := run("Stop"); code != 0 {
+		t.Errorf("unconfigured event exit = %d, want 0", code)
+	}
+}
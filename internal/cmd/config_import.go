@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Source formats understood by config import
+const (
+	importFormatClaude  = "claude"
+	importFormatCursor  = "cursor"
+	importFormatScripts = "scripts"
+)
+
+// cursorImportEvents maps Cursor hook names (beyond the aliases in
+// core.AllClaudeCodeEvents) to a Claude event and the tool matcher that
+// narrows it to the same calls
+var cursorImportEvents = map[string]struct{ event, matcher string }{
+	"beforeShellExecution": {string(core.PreToolUseEvent), "Bash"},
+	"afterShellExecution":  {string(core.PostToolUseEvent), "Bash"},
+	"beforeFileEdit":       {string(core.PreToolUseEvent), "Edit|MultiEdit|Write"},
+	"afterFileEdit":        {string(core.PostToolUseEvent), "Edit|MultiEdit|Write"},
+	"beforeFileWrite":      {string(core.PreToolUseEvent), "Write"},
+	"afterFileWrite":       {string(core.PostToolUseEvent), "Write"},
+	"beforeReadFile":       {string(core.PreToolUseEvent), "Read"},
+	"beforeMCPExecution":   {string(core.PreToolUseEvent), "mcp__.*"},
+	"beforeSubmitPrompt":   {string(core.UserPromptSubmitEvent), ""},
+	"stop":                 {string(core.StopEvent), ""},
+}
+
+// importedJob is one hook command converted to a job, with the event and
+// matcher its settings entry is installed under
+type importedJob struct {
+	Event   string
+	Matcher string
+	Job     config.HookJob
+	// Command is the original command, removed from settings on --replace
+	Command string
+}
+
+// NewConfigImportCmd creates the config import subcommand
+func NewConfigImportCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Convert hooks from another hook manager into a custom hook group",
+		ArgsUsage: "<path>",
+		Description: `Read hooks configured for another tool and turn them into a blues-traveler group
+(.claude/hooks/<group>.yml) plus settings.json entries that run it. Supported sources:
+
+  claude   A hooks.json in Claude Code's format: a plugin's hooks/hooks.json or any
+           settings.json with a "hooks" section
+  cursor   A Cursor hooks.json ({"version": 1, "hooks": {"afterFileEdit": [...]}})
+  scripts  A directory with one subdirectory per event (PreToolUse/, post-tool-use/,
+           afterFileEdit/, ...) holding executable scripts
+  bundle   A .tar.gz written by 'config export'. Its group and scripts are unpacked
+           into the project as they were; install the group afterwards with
+           'hooks custom install'
+
+The format is detected from the path when --from is not given. Tool matchers are
+kept on the settings entries and also written as 'only' conditions, so a later
+'hooks custom sync' does not widen them. Commands that already run blues-traveler
+are skipped. Importing from the settings.json being written to replaces the
+original entries instead of running them twice.
+
+Examples:
+  blues-traveler config import ~/.claude/plugins/formatter/hooks/hooks.json --group formatter
+  blues-traveler config import .cursor/hooks.json --dry-run
+  blues-traveler config import ~/dotfiles/claude-hooks --from scripts --global
+  blues-traveler config import lint.tar.gz --group shared-lint`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "from", Aliases: []string{"f"}, Usage: "Source format: claude, cursor, scripts or bundle (default: detect)"},
+			&cli.StringFlag{Name: "group", Aliases: []string{"G"}, Value: "imported", Usage: "Name of the group to create (bundles keep their own unless set)"},
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Write the group and settings under ~/.claude"},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "Print the group YAML without writing anything"},
+			&cli.BoolFlag{Name: "no-install", Usage: "Write the group file but leave settings.json unchanged"},
+			&cli.BoolFlag{Name: "overwrite", Usage: "Replace an existing group file"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: <path>")
+			}
+			return executeImportCommand(importOptions{
+				source:    args[0],
+				format:    cmd.String("from"),
+				group:     cmd.String("group"),
+				groupSet:  cmd.IsSet("group"),
+				global:    cmd.Bool("global"),
+				dryRun:    cmd.Bool("dry-run"),
+				noInstall: cmd.Bool("no-install"),
+				overwrite: cmd.Bool("overwrite"),
+			})
+		},
+	}
+}
+
+type importOptions struct {
+	source    string
+	format    string
+	group     string
+	groupSet  bool
+	global    bool
+	dryRun    bool
+	noInstall bool
+	overwrite bool
+}
+
+// executeImportCommand reads the source, writes the group file and installs it
+func executeImportCommand(opts importOptions) error {
+	if strings.TrimSpace(opts.group) == "" || strings.ContainsAny(opts.group, ":/\\") {
+		return fmt.Errorf("invalid --group '%s': use a name without ':', '/' or '\\'", opts.group)
+	}
+	format := opts.format
+	if format == "" {
+		detected, err := detectImportFormat(opts.source)
+		if err != nil {
+			return err
+		}
+		format = detected
+	}
+	if format == importFormatBundle {
+		return importBundle(opts)
+	}
+
+	jobs, warnings, err := importHooks(opts.source, format)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", w)
+	}
+	if len(jobs) == 0 {
+		output.Printf("No hooks to import from %s.\n", opts.source)
+		return nil
+	}
+
+	out, err := yaml.Marshal(config.CustomHooksConfig{opts.group: buildImportedGroup(jobs)})
+	if err != nil {
+		return fmt.Errorf("failed to render group: %w", err)
+	}
+	if opts.dryRun {
+		output.Print(string(out))
+		output.Printf("\n# Would import %d job(s) from %s (%s format)\n", len(jobs), opts.source, format)
+		return nil
+	}
+
+	groupPath, err := writeImportedGroup(opts, out)
+	if err != nil {
+		return err
+	}
+	output.Printf("✅ Imported %d job(s) from %s into group '%s'\n", len(jobs), opts.source, opts.group)
+	output.Printf("   Group file: %s\n", groupPath)
+
+	if opts.noInstall {
+		output.Printf("   Install with: blues-traveler hooks custom install %s\n", opts.group)
+		return nil
+	}
+	return installImportedJobs(opts, jobs)
+}
+
+// writeImportedGroup writes the group YAML to .claude/hooks/<group>.yml
+func writeImportedGroup(opts importOptions, content []byte) (string, error) {
+	dir, err := config.EnsureClaudeDir(opts.global)
+	if err != nil {
+		return "", err
+	}
+	base, err := sanitizeFileName(opts.group)
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(dir, constants.HooksSubDir, base)
+	if _, err := os.Stat(target); err == nil && !opts.overwrite {
+		return "", fmt.Errorf("group file %s already exists\n  Suggestion: Pick another --group or pass --overwrite", target)
+	}
+	if err := os.WriteFile(target, content, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return target, nil
+}
+
+// installImportedJobs adds a settings entry per job under its original matcher
+func installImportedJobs(opts importOptions, jobs []importedJob) error {
+	settings, settingsPath, release, err := loadSettingsForInstall(opts.global)
+	if err != nil {
+		return err
+	}
+	defer release()
+	execPath, err := resolveHookExecutable(opts.global, false)
+	if err != nil {
+		return err
+	}
+
+	replace := config.SameFile(opts.source, settingsPath)
+	replaced := 0
+	for _, ij := range jobs {
+		if replace && config.RemoveHookFromSettings(settings, ij.Command) {
+			replaced++
+		}
+		var timeout *int
+		if ij.Job.Timeout > 0 {
+			t := ij.Job.Timeout
+			timeout = &t
+		}
+		config.AddHookToSettings(settings, ij.Event, ij.Matcher, config.ConfigHookCommand(execPath, opts.group, ij.Job.Name), timeout)
+	}
+
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("failed to save settings to %s: %w\n  Suggestion: Verify file permissions and available disk space", settingsPath, err)
+	}
+	printInstallSuccess(opts.group, getScopeName(opts.global), len(jobs), settingsPath)
+	if replaced > 0 {
+		output.Printf("   Replaced %d original entr(ies) in the same settings file\n", replaced)
+	}
+	return nil
+}
+
+// detectImportFormat guesses the source format: directories are script
+// layouts, and JSON with a top-level "version" or Cursor event names is Cursor's
+func detectImportFormat(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return importFormatScripts, nil
+	}
+	if isBundlePath(path) {
+		return importFormatBundle, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - user-specified import source
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	var probe struct {
+		Version *int                       `json:"version"`
+		Hooks   map[string]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("%s is not JSON: %w\n  Suggestion: Pass --from to choose the format", path, err)
+	}
+	if probe.Version != nil {
+		return importFormatCursor, nil
+	}
+	for event := range probe.Hooks {
+		if _, ok := cursorImportEvents[event]; ok {
+			return importFormatCursor, nil
+		}
+	}
+	return importFormatClaude, nil
+}
+
+// importHooks reads path in the given format. Warnings describe entries that
+// were skipped or may need attention after import.
+func importHooks(path, format string) ([]importedJob, []string, error) {
+	switch format {
+	case importFormatScripts:
+		return importScriptsDir(path)
+	case importFormatClaude, importFormatCursor:
+		data, err := os.ReadFile(path) // #nosec G304 - user-specified import source
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read %s: %w", path, err)
+		}
+		if format == importFormatCursor {
+			return importCursorHooks(data)
+		}
+		return importClaudeHooks(data)
+	default:
+		return nil, nil, fmt.Errorf("unknown import format '%s' (valid: %s, %s, %s, %s)", format, importFormatClaude, importFormatCursor, importFormatScripts, importFormatBundle)
+	}
+}
+
+// importClaudeHooks converts Claude Code's hooks format. The events may sit
+// under a "hooks" key (settings.json, plugin hooks.json) or at the top level.
+func importClaudeHooks(data []byte) ([]importedJob, []string, error) {
+	var wrapped struct {
+		Hooks map[string][]config.HookMatcher `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, nil, fmt.Errorf("invalid Claude hooks JSON: %w", err)
+	}
+	events := wrapped.Hooks
+	if events == nil {
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, nil, fmt.Errorf("invalid Claude hooks JSON: %w", err)
+		}
+	}
+
+	var jobs []importedJob
+	var warnings []string
+	for _, event := range sortedKeys(events) {
+		canonical := core.ResolveEventAlias(event)
+		if canonical == "" {
+			warnings = append(warnings, fmt.Sprintf("skipping unknown event '%s'", event))
+			continue
+		}
+		for _, m := range events[event] {
+			for _, hc := range m.Hooks {
+				job, warn, ok := importCommand(hc.Type, hc.Command, hc.Timeout)
+				if warn != "" {
+					warnings = append(warnings, warn)
+				}
+				if ok {
+					jobs = append(jobs, newImportedJob(canonical, m.Matcher, job, hc.Command))
+				}
+			}
+		}
+	}
+	return jobs, warnings, nil
+}
+
+// importCursorHooks converts a Cursor hooks.json
+func importCursorHooks(data []byte) ([]importedJob, []string, error) {
+	var file struct {
+		Hooks map[string][]struct {
+			Command string `json:"command"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("invalid Cursor hooks JSON: %w", err)
+	}
+
+	var jobs []importedJob
+	var warnings []string
+	for _, name := range sortedKeys(file.Hooks) {
+		event, matcher := core.ResolveEventAlias(name), ""
+		if mapped, ok := cursorImportEvents[name]; ok {
+			event, matcher = mapped.event, mapped.matcher
+		}
+		if event == "" {
+			warnings = append(warnings, fmt.Sprintf("skipping unknown Cursor hook '%s'", name))
+			continue
+		}
+		for _, h := range file.Hooks[name] {
+			job, warn, ok := importCommand("command", h.Command, nil)
+			if warn != "" {
+				warnings = append(warnings, warn)
+			}
+			if ok {
+				jobs = append(jobs, newImportedJob(event, matcher, job, h.Command))
+			}
+		}
+	}
+	if len(jobs) > 0 {
+		warnings = append(warnings, "Cursor runs relative commands from the project root; run blues-traveler from the same directory or make them absolute")
+	}
+	return jobs, warnings, nil
+}
+
+// importScriptsDir converts a directory of per-event script folders
+func importScriptsDir(dir string) ([]importedJob, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read %s: %w", dir, err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jobs []importedJob
+	var warnings []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		event, matcher := scriptDirEvent(entry.Name())
+		if event == "" {
+			warnings = append(warnings, fmt.Sprintf("skipping directory '%s': not an event name", entry.Name()))
+			continue
+		}
+		scripts, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read %s: %w", entry.Name(), err)
+		}
+		for _, s := range scripts {
+			info, err := s.Info()
+			if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(s.Name(), ".") {
+				continue
+			}
+			if info.Mode().Perm()&0o111 == 0 {
+				warnings = append(warnings, fmt.Sprintf("skipping %s/%s: not executable", entry.Name(), s.Name()))
+				continue
+			}
+			command := core.ShellQuote(filepath.Join(absDir, entry.Name(), s.Name()))
+			job, _, _ := importCommand("command", command, nil)
+			job.Name = jobNameFromCommand(s.Name())
+			jobs = append(jobs, newImportedJob(event, matcher, job, command))
+		}
+	}
+	return jobs, warnings, nil
+}
+
+// scriptDirEvent resolves a directory name such as "PreToolUse",
+// "pre-tool-use", "pre_tool_use" or a Cursor hook name to an event
+func scriptDirEvent(name string) (event, matcher string) {
+	if mapped, ok := cursorImportEvents[name]; ok {
+		return mapped.event, mapped.matcher
+	}
+	if event := core.ResolveEventAlias(name); event != "" {
+		return event, ""
+	}
+	flat := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for _, e := range core.AllClaudeCodeEvents() {
+		if strings.ToLower(e.Name) == flat {
+			return e.Name, ""
+		}
+	}
+	return "", ""
+}
+
+// importCommand turns one hook command into a job. ok is false (with a
+// warning) for entries that can't or shouldn't be imported.
+func importCommand(kind, command string, timeout *int) (config.HookJob, string, bool) {
+	command = strings.TrimSpace(command)
+	switch {
+	case kind != "" && kind != "command":
+		return config.HookJob{}, fmt.Sprintf("skipping '%s' hook: only command hooks can be imported", kind), false
+	case command == "":
+		return config.HookJob{}, "", false
+	case config.IsBluesTravelerCommand(command):
+		return config.HookJob{}, fmt.Sprintf("skipping '%s': already runs blues-traveler", command), false
+	}
+	job := config.HookJob{Name: jobNameFromCommand(command), Run: command}
+	if timeout != nil && *timeout > 0 {
+		job.Timeout = *timeout
+	}
+	var warn string
+	if strings.Contains(command, "${CLAUDE_PLUGIN_ROOT}") {
+		warn = fmt.Sprintf("'%s' uses ${CLAUDE_PLUGIN_ROOT}, which is only set for plugin hooks; replace it with the plugin's path", command)
+	}
+	return job, warn, true
+}
+
+// newImportedJob records the matcher and, for tool events, mirrors it as an
+// only condition so the job keeps its scope under any settings matcher
+func newImportedJob(event, matcher string, job config.HookJob, command string) importedJob {
+	matcher = strings.TrimSpace(matcher)
+	if matcher == "" {
+		matcher = "*"
+	}
+	isToolEvent := event == string(core.PreToolUseEvent) || event == string(core.PostToolUseEvent)
+	if isToolEvent && matcher != "*" {
+		pattern := strings.ReplaceAll(matcher, ",", "|")
+		if _, err := regexp.Compile(pattern); err == nil {
+			job.Only = fmt.Sprintf(`${TOOL_NAME} regex "^(?:%s)$"`, pattern)
+		}
+	}
+	return importedJob{Event: event, Matcher: matcher, Job: job, Command: command}
+}
+
+// buildImportedGroup arranges jobs by event, giving every job a name that
+// is unique within the group (job keys don't include the event)
+func buildImportedGroup(jobs []importedJob) *config.HookGroup {
+	group := config.NewHookGroup(nil)
+	used := map[string]int{}
+	for i := range jobs {
+		base := jobs[i].Job.Name
+		used[base]++
+		if n := used[base]; n > 1 {
+			jobs[i].Job.Name = fmt.Sprintf("%s-%d", base, n)
+		}
+		ev := group.Events[jobs[i].Event]
+		if ev == nil {
+			ev = &config.EventConfig{}
+			group.Events[jobs[i].Event] = ev
+		}
+		ev.Jobs = append(ev.Jobs, jobs[i].Job)
+	}
+	return group
+}
+
+var jobNameInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// jobNameFromCommand derives a job name from the program a command runs,
+// e.g. "npx prettier --write" -> "npx", "./hooks/format.sh" -> "format"
+func jobNameFromCommand(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "job"
+	}
+	prog := strings.Trim(fields[0], `'"`)
+	prog = strings.TrimSuffix(filepath.Base(prog), filepath.Ext(prog))
+	name := strings.Trim(jobNameInvalidChars.ReplaceAllString(strings.ToLower(prog), "-"), "-")
+	if name == "" {
+		return "job"
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
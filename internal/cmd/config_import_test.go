@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const claudePluginHooks = `{
+  "description": "formatter plugin",
+  "hooks": {
+    "PostToolUse": [
+      {"matcher": "Edit|Write", "hooks": [
+        {"type": "command", "command": "npx prettier --write \"$CLAUDE_FILE\"", "timeout": 30},
+        {"type": "command", "command": "/usr/local/bin/blues-traveler hooks run format"}
+      ]}
+    ],
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "${CLAUDE_PLUGIN_ROOT}/scripts/notify.sh"}]}
+    ],
+    "Bogus": [{"hooks": [{"type": "command", "command": "true"}]}]
+  }
+}`
+
+func TestImportClaudeHooks(t *testing.T) {
+	jobs, warnings, err := importClaudeHooks([]byte(claudePluginHooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs (blues-traveler command skipped), got %+v", jobs)
+	}
+
+	prettier := jobs[0]
+	if prettier.Event != "PostToolUse" || prettier.Matcher != "Edit|Write" || prettier.Job.Name != "npx" || prettier.Job.Timeout != 30 {
+		t.Errorf("unexpected prettier job: %+v", prettier)
+	}
+	if ok, err := core.EvalExpression(prettier.Job.Only, map[string]string{"TOOL_NAME": "Write"}); err != nil || !ok {
+		t.Errorf("only %q should pass for Write: %v %v", prettier.Job.Only, ok, err)
+	}
+	if ok, _ := core.EvalExpression(prettier.Job.Only, map[string]string{"TOOL_NAME": "Bash"}); ok {
+		t.Errorf("only %q should reject Bash", prettier.Job.Only)
+	}
+
+	if notify := jobs[1]; notify.Event != "Stop" || notify.Matcher != "*" || notify.Job.Only != "" || notify.Job.Name != "notify" {
+		t.Errorf("unexpected notify job: %+v", notify)
+	}
+
+	joined := strings.Join(warnings, "\n")
+	for _, want := range []string{"already runs blues-traveler", "CLAUDE_PLUGIN_ROOT", "unknown event 'Bogus'"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("warnings missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestImportCursorHooks(t *testing.T) {
+	data := `{"version": 1, "hooks": {
+	  "afterFileEdit": [{"command": "./hooks/format.sh"}],
+	  "beforeShellExecution": [{"command": "./hooks/guard.sh"}, {"command": "./hooks/guard.py"}],
+	  "stop": [{"command": "say done"}]
+	}}`
+	jobs, _, err := importCursorHooks([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, j := range jobs {
+		got[j.Job.Name] = j.Event + " " + j.Matcher
+	}
+	group := buildImportedGroup(jobs)
+	if len(group.Events["PreToolUse"].Jobs) != 2 || group.Events["PreToolUse"].Jobs[1].Name != "guard-2" {
+		t.Errorf("duplicate names should be numbered, got %+v", group.Events["PreToolUse"].Jobs)
+	}
+	if got["format"] != "PostToolUse Edit|MultiEdit|Write" || got["say"] != "Stop *" {
+		t.Errorf("unexpected event mapping: %v", got)
+	}
+}
+
+func TestImportScriptsDir(t *testing.T) {
+	dir := t.TempDir()
+	writeScript := func(rel string, mode os.FileMode) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeScript("pre-tool-use/check.sh", 0o755)
+	writeScript("SessionStart/greet", 0o755)
+	writeScript("afterShellExecution/log.sh", 0o755)
+	writeScript("pre-tool-use/README.md", 0o644)
+	writeScript("lib/helpers.sh", 0o755)
+
+	if format, err := detectImportFormat(dir); err != nil || format != importFormatScripts {
+		t.Fatalf("detectImportFormat(dir) = %q, %v", format, err)
+	}
+	jobs, warnings, err := importScriptsDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := map[string]importedJob{}
+	for _, j := range jobs {
+		events[j.Job.Name] = j
+	}
+	if len(jobs) != 3 || events["check"].Event != "PreToolUse" || events["greet"].Event != "SessionStart" || events["log"].Matcher != "Bash" {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+	if !strings.HasPrefix(events["check"].Job.Run, "'"+dir) {
+		t.Errorf("script should run by quoted absolute path, got %q", events["check"].Job.Run)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "README.md: not executable") || !strings.Contains(joined, "'lib'") {
+		t.Errorf("unexpected warnings:\n%s", joined)
+	}
+}
+
+func TestExecuteImportCommand_ReplacesSourceSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	t.Chdir(project)
+
+	settingsPath, err := config.GetSettingsPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	raw := `{"hooks":{"PreToolUse":[{"matcher":"Bash","hooks":[{"type":"command","command":"./guard.sh"}]}]}}`
+	if err := os.WriteFile(settingsPath, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeImportCommand(importOptions{source: settingsPath, group: "legacy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Hooks.PreToolUse) != 1 || len(settings.Hooks.PreToolUse[0].Hooks) != 1 {
+		t.Fatalf("expected the original entry replaced by one blues-traveler entry, got %+v", settings.Hooks.PreToolUse)
+	}
+	entry := settings.Hooks.PreToolUse[0]
+	if entry.Matcher != "Bash" || !strings.Contains(entry.Hooks[0].Command, "config:legacy:guard") {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobs := (*cfg)["legacy"].Events["PreToolUse"].Jobs; len(jobs) != 1 || jobs[0].Run != "./guard.sh" {
+		t.Errorf("group not loaded from .claude/hooks/legacy.yml: %+v", (*cfg)["legacy"])
+	}
+
+	other := filepath.Join(t.TempDir(), "hooks.json")
+	if err := os.WriteFile(other, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeImportCommand(importOptions{source: other, group: "legacy"}); err == nil {
+		t.Error("expected an error when the group file already exists")
+	}
+}
+
+func TestConfigBundle_ExportImport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	source := t.TempDir()
+	t.Chdir(source)
+	writeFile := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(source, ".claude", "hooks.yml"), "lint:\n  PostToolUse:\n    jobs:\n      - name: lint\n        run: ./scripts/lint.sh\n", 0o600)
+	writeFile(filepath.Join(source, "scripts", "lint.sh"), "#!/bin/sh\necho lint\n", 0o700)
+
+	bundlePath := filepath.Join(t.TempDir(), "lint.tar.gz")
+	if err := executeExportBundle("lint", bundlePath); err != nil {
+		t.Fatal(err)
+	}
+
+	target := t.TempDir()
+	t.Chdir(target)
+	if err := executeImportCommand(importOptions{source: bundlePath, group: "shared", groupSet: true}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(target, "scripts", "lint.sh"))
+	if err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("expected an executable script in the target project, got %v %v", info, err)
+	}
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobs := (*cfg)["shared"].Events["PostToolUse"].Jobs; len(jobs) != 1 || jobs[0].Run != "./scripts/lint.sh" {
+		t.Errorf("group not imported under its new name: %+v", *cfg)
+	}
+
+	if err := executeImportCommand(importOptions{source: bundlePath, group: "other", groupSet: true}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected existing scripts to be kept without --overwrite, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// pruneArchiveSubDir is where --archive moves files, under .claude/hooks/.
+// The loader ignores subdirectories, so archived files stop taking effect.
+const pruneArchiveSubDir = "archive"
+
+// pruneConfigsOptions configures a prune-configs run
+type pruneConfigsOptions struct {
+	hooksDir string
+	dryRun   bool
+	archive  bool
+	yes      bool
+	// mainGroups and installed are injected so tests need not touch $HOME
+	mainGroups config.CustomHooksConfig
+	installed  map[string]bool
+	confirm    func(prompt string) bool
+}
+
+// NewConfigPruneConfigsCmd creates the config prune-configs subcommand
+func NewConfigPruneConfigsCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "prune-configs",
+		Usage: "Delete or archive per-group hook files that are no longer used",
+		Description: `Find per-group hook files (.claude/hooks/<name>.yml) whose groups are all either
+duplicated by the customHooks in the main config or not installed in any
+settings.json, and offer to delete them. A group that an installed or main
+config group extends, directly or through other groups, is still in use.
+With --archive they are moved to
+.claude/hooks/archive/<timestamp>/ instead.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Prune ~/.claude/hooks instead of the project"},
+			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "List unused files without changing anything"},
+			&cli.BoolFlag{Name: "archive", Usage: "Move unused files to .claude/hooks/archive/ instead of deleting"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Skip interactive confirmation"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			global := cmd.Bool("global")
+			hooksDir, err := config.GroupHooksDir(global)
+			if err != nil {
+				return err
+			}
+			cfgPath, err := config.GetLogConfigPath(global)
+			if err != nil {
+				return err
+			}
+			mainCfg, err := config.LoadLogConfig(cfgPath)
+			if err != nil {
+				return fmt.Errorf("failed to load main config: %w\n  Suggestion: Fix %s before pruning so groups are not misjudged as unused", err, cfgPath)
+			}
+
+			return runPruneConfigs(pruneConfigsOptions{
+				hooksDir:   hooksDir,
+				dryRun:     cmd.Bool("dry-run"),
+				archive:    cmd.Bool("archive"),
+				yes:        cmd.Bool("yes"),
+				mainGroups: mainCfg.CustomHooks,
+				installed:  config.InstalledConfigGroups(),
+				confirm: func(prompt string) bool {
+					output.Printf("%s (y/N): ", prompt)
+					var response string
+					_, _ = fmt.Scanln(&response)
+					return response == "y" || response == "Y" || response == "yes"
+				},
+			})
+		},
+	}
+}
+
+// runPruneConfigs reports unused per-group files and removes or archives them
+func runPruneConfigs(opts pruneConfigsOptions) error {
+	files := config.PerGroupFiles(opts.hooksDir)
+	stale, parseErrs := config.FindStaleGroupFiles(files, opts.mainGroups, opts.installed)
+	for _, err := range parseErrs {
+		output.Printf("⚠️  Skipping unparseable file %v\n", err)
+	}
+
+	if len(stale) == 0 {
+		output.Printf("✅ No unused per-group config files in %s\n", opts.hooksDir)
+		return nil
+	}
+
+	output.Printf("🔍 Found %d unused per-group config file(s) in %s:\n", len(stale), opts.hooksDir)
+	paths := make([]string, 0, len(stale))
+	for _, s := range stale {
+		paths = append(paths, s.Path)
+		label := filepath.Base(s.Path)
+		if len(s.Groups) > 0 {
+			label += fmt.Sprintf(" (groups: %s)", strings.Join(s.Groups, ", "))
+		}
+		output.Printf("  • %s\n", label)
+		for _, reason := range s.Reasons {
+			output.Printf("      - %s\n", reason)
+		}
+	}
+
+	if opts.dryRun {
+		output.Println("\nDry run: no files changed.")
+		return nil
+	}
+
+	action := "Delete"
+	archiveDir := ""
+	if opts.archive {
+		archiveDir = filepath.Join(opts.hooksDir, pruneArchiveSubDir, time.Now().Format("20060102-150405"))
+		action = "Archive"
+	}
+	prompt := fmt.Sprintf("\n%s %d file(s)?", action, len(stale))
+	if !opts.yes && (opts.confirm == nil || !opts.confirm(prompt)) {
+		output.Println("Operation cancelled.")
+		return nil
+	}
+
+	targets := make([]config.PreflightTarget, 0, len(paths))
+	for _, p := range paths {
+		targets = append(targets, config.PreflightTarget{Path: p, AllowedRoot: opts.hooksDir})
+	}
+	if err := config.Preflight("prune-configs", targets...); err != nil {
+		return err
+	}
+
+	if opts.archive {
+		if err := config.ArchiveGroupFiles(paths, archiveDir); err != nil {
+			return err
+		}
+		output.Printf("📦 Archived %d file(s) to %s\n", len(paths), archiveDir)
+		return nil
+	}
+
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", p, err)
+		}
+	}
+	output.Printf("🗑️  Deleted %d file(s)\n", len(paths))
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestRunPruneConfigs(t *testing.T) {
+	dir := t.TempDir()
+	job := ":\n  PreToolUse:\n    jobs:\n      - name: j\n        run: true\n"
+	for name, group := range map[string]string{"keep.yml": "keep", "gone.yml": "gone", "hooks.yml": "gone"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(group+job), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := pruneConfigsOptions{
+		hooksDir:   dir,
+		mainGroups: config.CustomHooksConfig{},
+		installed:  map[string]bool{"keep": true},
+	}
+
+	// Dry run and a declined prompt leave everything in place
+	opts.dryRun = true
+	if err := runPruneConfigs(opts); err != nil {
+		t.Fatal(err)
+	}
+	opts.dryRun = false
+	opts.confirm = func(string) bool { return false }
+	if err := runPruneConfigs(opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gone.yml")); err != nil {
+		t.Fatalf("gone.yml should survive dry run and cancel: %v", err)
+	}
+
+	// Archive moves the unused file out of the loader's view
+	opts.archive, opts.yes = true, true
+	if err := runPruneConfigs(opts); err != nil {
+		t.Fatal(err)
+	}
+	archived, _ := filepath.Glob(filepath.Join(dir, pruneArchiveSubDir, "*", "gone.yml"))
+	if len(archived) != 1 {
+		t.Errorf("expected gone.yml in archive, found %v", archived)
+	}
+	for _, name := range []string{"keep.yml", "hooks.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s should be untouched: %v", name, err)
+		}
+	}
+
+	// Delete removes files outright
+	if err := os.WriteFile(filepath.Join(dir, "gone2.yml"), []byte("gone2"+job), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts.archive = false
+	if err := runPruneConfigs(opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gone2.yml")); !os.IsNotExist(err) {
+		t.Errorf("gone2.yml should be deleted, stat err = %v", err)
+	}
+}
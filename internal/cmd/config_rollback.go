@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewConfigRollbackCmd creates the config rollback subcommand
+func NewConfigRollbackCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "rollback",
+		Usage: "Restore settings.json from a snapshot taken before it was changed",
+		Description: `Every install, uninstall, sync or other change blues-traveler makes to
+settings.json first copies the previous version to .claude/.settings-backups/
+(the last 20 are kept). Without flags the newest snapshot is restored, undoing
+the last change. The version being replaced is snapshotted too, so running
+rollback again undoes the rollback.
+
+Examples:
+  blues-traveler config rollback --list
+  blues-traveler config rollback
+  blues-traveler config rollback --to 20250101T120000.000 --global`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Roll back global settings (~/.claude/settings.json)"},
+			&cli.BoolFlag{Name: "list", Aliases: []string{"l"}, Usage: "List snapshots, newest first, without changing anything"},
+			&cli.StringFlag{Name: "to", Usage: "Timestamp of the snapshot to restore (see --list)"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			global := cmd.Bool("global")
+			settingsPath, err := config.GetSettingsPath(global)
+			if err != nil {
+				return fmt.Errorf("error getting settings path: %w", err)
+			}
+			if cmd.Bool("list") {
+				if cmd.IsSet("to") {
+					return fmt.Errorf("--list and --to cannot be combined")
+				}
+				return listSettingsBackups(settingsPath, getScopeName(global))
+			}
+			return rollbackSettings(settingsPath, getScopeName(global), cmd.String("to"))
+		},
+	}
+}
+
+func listSettingsBackups(settingsPath, scope string) error {
+	backups, err := config.ListSettingsBackups(settingsPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		output.Printf("No snapshots of %s settings (%s).\n", scope, settingsPath)
+		return nil
+	}
+	output.Printf("Snapshots of %s settings (%s), newest first:\n", scope, settingsPath)
+	for _, b := range backups {
+		entries := "unreadable"
+		if s, err := config.LoadSettings(b.Path); err == nil {
+			entries = fmt.Sprintf("%d hook entries", len(config.SettingsEntries(s.Hooks)))
+		}
+		output.Printf("  %s  %s  %8s  %s\n", b.ID, b.Time.Format("2006-01-02 15:04:05"), core.FormatBytes(b.Size), entries)
+	}
+	return nil
+}
+
+func rollbackSettings(settingsPath, scope, id string) error {
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	restored, err := config.RestoreSettingsBackup(settingsPath, id)
+	if err != nil {
+		return err
+	}
+	output.Printf("✅ Restored %s settings from snapshot %s (%s)\n", scope, restored.ID, restored.Time.Format(time.RFC1123))
+	output.Println("   Run 'blues-traveler config rollback' again to undo this.")
+	return nil
+}
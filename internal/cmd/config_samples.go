@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewConfigCleanSamplesCmd creates the config clean-samples subcommand
+func NewConfigCleanSamplesCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "clean-samples",
+		Usage: "Remove sample files blues-traveler created, such as blocked-urls.txt",
+		Description: `Remove the sample files created by 'hooks install fetch-blocker' (blocked-urls.txt)
+and 'hooks custom init --name' (per-group hook files). Only files blues-traveler
+wrote are touched, and files edited since are kept unless --force is given.
+
+  blues-traveler config clean-samples
+  blues-traveler config clean-samples --owner fetch-blocker --global`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Clean samples in ~/.claude instead of the project"},
+			&cli.StringFlag{Name: "owner", Usage: "Only samples created for this hook type or config:<group>"},
+			&cli.BoolFlag{Name: "force", Usage: "Also remove samples edited since they were created"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return purgeSampleArtifacts(cmd.Bool("global"), cmd.String("owner"), cmd.Bool("force"))
+		},
+	}
+}
+
+// purgeSampleArtifacts removes the tracked samples for owner (all when
+// empty) and reports what was removed and kept
+func purgeSampleArtifacts(global bool, owner string, force bool) error {
+	cleanup, err := config.RemoveSampleArtifacts(global, owner, force)
+	for _, path := range cleanup.Removed {
+		output.Printf("🧹 Removed sample %s\n", path)
+	}
+	for _, path := range cleanup.Edited {
+		output.Printf("📝 Kept %s: edited since it was created (use --force to remove it)\n", path)
+	}
+	if err != nil {
+		return err
+	}
+	if len(cleanup.Removed) == 0 && len(cleanup.Edited) == 0 {
+		output.Println("No sample files to remove")
+	}
+	return nil
+}
@@ -72,7 +72,7 @@ func createConfigWithGroup(t *testing.T, groupName string) {
 	}
 
 	// Create the group directly
-	config.CustomHooks[groupName] = btconfig.HookGroup{
+	config.CustomHooks[groupName] = &btconfig.HookGroup{Events: map[string]*btconfig.EventConfig{
 		"PreToolUse": &btconfig.EventConfig{
 			Jobs: []btconfig.HookJob{
 				{
@@ -96,7 +96,7 @@ func createConfigWithGroup(t *testing.T, groupName string) {
 				},
 			},
 		},
-	}
+	}}
 
 	// Save config
 	if err := btconfig.SaveLogConfig(configPath, config); err != nil {
@@ -258,7 +258,7 @@ func syncCurrentGroups(t *testing.T, settings *btconfig.Settings, hooksCfg *btco
 }
 
 // syncGroup syncs a single group to settings
-func syncGroup(t *testing.T, settings *btconfig.Settings, groupName string, group btconfig.HookGroup) int {
+func syncGroup(t *testing.T, settings *btconfig.Settings, groupName string, group *btconfig.HookGroup) int {
 	t.Helper()
 	changed := 0
 
@@ -269,7 +269,7 @@ func syncGroup(t *testing.T, settings *btconfig.Settings, groupName string, grou
 	}
 
 	// Add current definitions
-	for eventName, ev := range group {
+	for eventName, ev := range group.Events {
 		changed += addJobsToSettings(settings, groupName, eventName, ev.Jobs)
 	}
 
@@ -473,3 +473,48 @@ func TestConfigSync_GroupFilter_RemovedGroup_ShouldCleanup(t *testing.T) {
 		t.Errorf("Other group was unexpectedly affected: had %d, now has %d", otherCount, finalOtherCount)
 	}
 }
+
+func TestConfigSync_PruneOnly_RemovesOrphansWithoutAdding(t *testing.T) {
+	hooksCfg := &btconfig.CustomHooksConfig{
+		"lint": {Events: map[string]*btconfig.EventConfig{
+			"PostToolUse": {Jobs: []btconfig.HookJob{{Name: "eslint", Run: "eslint ."}, {Name: "prettier", Run: "prettier -c ."}}},
+		}},
+	}
+	settings := &btconfig.Settings{}
+	btconfig.AddHookToSettings(settings, "PostToolUse", "Edit,Write", "blues-traveler hooks run config:lint:eslint", nil)
+	btconfig.AddHookToSettings(settings, "PostToolUse", "Edit,Write", "blues-traveler hooks run config:lint:stylelint", nil)
+	btconfig.AddHookToSettings(settings, "Stop", "", "blues-traveler hooks run config:lint:eslint", nil)
+	btconfig.AddHookToSettings(settings, "PreToolUse", "*", "blues-traveler hooks run config:old:check", nil)
+	btconfig.AddHookToSettings(settings, "PreToolUse", "Bash", "blues-traveler hooks run security", nil)
+	btconfig.AddHookToSettings(settings, "Stop", "", "./notify.sh", nil)
+
+	orphans := btconfig.OrphanedEntries(settings, hooksCfg, "", "")
+	var got []string
+	for _, o := range orphans {
+		got = append(got, o.Event+" "+o.Group+":"+o.Job)
+	}
+	want := []string{"PreToolUse old:check", "PostToolUse lint:stylelint", "Stop lint:eslint"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("orphans = %v, want %v", got, want)
+	}
+	if only := btconfig.OrphanedEntries(settings, hooksCfg, "lint", "Stop"); len(only) != 1 {
+		t.Errorf("filtered orphans = %+v, want the Stop entry only", only)
+	}
+
+	if removed := pruneOrphans(settings, hooksCfg, syncOptions{groupFilter: "lint", quiet: true}); removed != 2 {
+		t.Fatalf("pruned %d entries for group lint, want 2", removed)
+	}
+	var left []string
+	for _, e := range btconfig.SettingsEntries(settings.Hooks) {
+		left = append(left, e.Event+" "+e.Command)
+	}
+	wantLeft := []string{
+		"PreToolUse blues-traveler hooks run config:old:check",
+		"PreToolUse blues-traveler hooks run security",
+		"PostToolUse blues-traveler hooks run config:lint:eslint",
+		"Stop ./notify.sh",
+	}
+	if strings.Join(left, "\n") != strings.Join(wantLeft, "\n") {
+		t.Errorf("settings after prune:\n%s\nwant:\n%s", strings.Join(left, "\n"), strings.Join(wantLeft, "\n"))
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewConfigValidateCmd creates the config validate subcommand
+func NewConfigValidateCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Check custom hooks files against the hooks config JSON Schema",
+		ArgsUsage: "[file...]",
+		Description: `Validate hooks.yml and the other custom hooks files against the embedded JSON
+Schema, reporting each problem with its line and column: unknown fields and
+event names, wrong value types, job fields out of range, skip/only expressions
+that don't parse and globs that don't compile. Without files, the project's
+hooks files are checked (with --global, those under ~/.claude), followed by
+the checks 'hooks custom validate' runs on the merged config. YAML and JSON
+files are checked against the schema; TOML files get the merged checks only.
+
+Point editors at the schema for completion and inline errors:
+  blues-traveler config validate --print-schema -o .claude/hooks.schema.json
+  # .claude/hooks.yml
+  # yaml-language-server: $schema=./hooks.schema.json`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Check the hooks files under ~/.claude"},
+			&cli.BoolFlag{Name: "print-schema", Usage: "Print the JSON Schema instead of validating"},
+			&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "With --print-schema, write the schema to this file"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("print-schema") {
+				return writeJSONDocument(hooksConfigSchema(), cmd.String("output"))
+			}
+			files := cmd.Args().Slice()
+			merged := len(files) == 0
+			if merged {
+				found, err := config.HooksConfigFiles(cmd.Bool("global"))
+				if err != nil {
+					return err
+				}
+				files = found
+			}
+			return runConfigValidate(files, merged)
+		},
+	}
+}
+
+// hooksConfigSchema is the hooks config schema for the events this build knows,
+// Cursor aliases included
+func hooksConfigSchema() map[string]interface{} {
+	var events []string
+	for _, e := range core.AllClaudeCodeEvents() {
+		events = append(events, e.Name)
+		events = append(events, e.CursorAliases...)
+	}
+	return config.HooksConfigSchema(events)
+}
+
+// hooksSchemaFormats checks the formats the hooks config schema uses
+var hooksSchemaFormats = config.FormatCheckers{
+	config.SchemaFormatCondition: core.CheckExpression,
+	config.SchemaFormatGlob: func(pattern string) error {
+		_, err := filepath.Match(pattern, "")
+		return err
+	},
+}
+
+// runConfigValidate checks files against the schema and, when merged is
+// set, the effective config as 'hooks custom validate' does
+func runConfigValidate(files []string, merged bool) error {
+	schema := hooksConfigSchema()
+	problems := 0
+	for _, path := range files {
+		issues, err := validateHooksFile(path, schema)
+		switch {
+		case err != nil:
+			problems++
+			output.Printf("❌ %s\n   %v\n", path, err)
+		case len(issues) > 0:
+			problems += len(issues)
+			output.Printf("❌ %s\n", path)
+			for _, issue := range issues {
+				output.Printf("   %s:%s\n", path, issue)
+			}
+		case config.HooksConfigFormat(path) == config.FormatTOML:
+			output.Printf("➖ %s (TOML: merged checks only)\n", path)
+		default:
+			output.Printf("✅ %s\n", path)
+		}
+	}
+
+	if merged {
+		if err := validateMergedHooksConfig(); err != nil {
+			problems++
+			output.Printf("❌ merged config: %v\n", err)
+		} else if len(files) > 0 {
+			output.Println("✅ merged config")
+		}
+	}
+	if len(files) == 0 && merged {
+		output.Println("No custom hooks files found.")
+	}
+	if problems > 0 {
+		return fmt.Errorf("found %d problem(s) in the hooks config", problems)
+	}
+	return nil
+}
+
+// validateHooksFile checks one YAML or JSON hooks file against schema
+func validateHooksFile(path string, schema map[string]interface{}) ([]config.SchemaIssue, error) {
+	format := config.HooksConfigFormat(path)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported file extension; use .yml, .yaml, .json or .toml")
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - user-specified or discovered hooks file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+	if format == config.FormatTOML {
+		_, err := config.ParseHooksConfig(data, format)
+		return nil, err
+	}
+	return config.ValidateHooksYAML(data, schema, hooksSchemaFormats)
+}
+
+// validateMergedHooksConfig runs the semantic checks on the effective config
+func validateMergedHooksConfig() error {
+	cfg, err := config.LoadHooksConfig()
+	if err != nil {
+		return fmt.Errorf("load error: %w", err)
+	}
+	if err := config.ValidateHooksConfig(cfg); err != nil {
+		return err
+	}
+	if errs := config.CheckJobEnvFiles(cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
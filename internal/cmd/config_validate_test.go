@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestRunConfigValidate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.MkdirAll(".claude", 0o750); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(".claude", "hooks.yml")
+	good := "go:\n  PostToolUse:\n    jobs:\n      - name: vet\n        run: go vet ./...\n        only: TOOL_NAME == \"Edit\"\n"
+	if err := os.WriteFile(path, []byte(good), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := config.HooksConfigFiles(false)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected the project hooks file, got %v %v", files, err)
+	}
+	var runErr error
+	out := string(captureStdout(func() { runErr = runConfigValidate(files, true) }))
+	if runErr != nil {
+		t.Fatalf("expected a clean config, got %v\n%s", runErr, out)
+	}
+	if !strings.Contains(out, "✅ merged config") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	bad := "go:\n  PostToolUse:\n    jobs:\n      - name: vet\n        run: go vet ./...\n        only: TOOL_NAME ==\n"
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out = string(captureStdout(func() { runErr = runConfigValidate(files, false) }))
+	if runErr == nil {
+		t.Fatalf("expected an error for a broken only expression:\n%s", out)
+	}
+	if !strings.Contains(out, path+":6:15: go.PostToolUse.jobs[0].only:") {
+		t.Errorf("expected a line-level issue, got:\n%s", out)
+	}
+}
+
+func TestHooksConfigSchemaIncludesCursorAliases(t *testing.T) {
+	data, err := json.Marshal(hooksConfigSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"PreToolUse", "SessionStart", "beforeShellExecution"} {
+		if !strings.Contains(string(data), `"`+name+`"`) {
+			t.Errorf("schema is missing event %s", name)
+		}
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -24,8 +26,20 @@ func NewConfigCmd() *cli.Command {
 			NewConfigListCmd(),
 			NewConfigEditCmd(),
 			NewConfigCleanCmd(),
+			NewConfigCleanSamplesCmd(),
 			NewConfigStatusCmd(),
 			NewConfigLogCmd(),
+			NewConfigExportCmd(),
+			NewConfigExportScriptCmd(),
+			NewConfigBisectCmd(),
+			NewConfigPruneConfigsCmd(),
+			NewConfigImportCmd(),
+			NewConfigDiffCmd(),
+			newHooksCustomSyncCommand(core.IsValidEventType, core.ValidEventTypes),
+			newHooksCustomShowCommand(),
+			NewConfigValidateCmd(),
+			NewConfigRollbackCmd(),
+			NewConfigApplyCmd(),
 		},
 	}
 }
@@ -51,13 +65,19 @@ func executeMigrateCommand(dryRun, verbose, all bool) error {
 
 	printFoundConfigs(configs, verbose)
 
+	if !dryRun {
+		if err := config.Preflight("migrate", migratePreflightTargets(xdg, configs)...); err != nil {
+			return err
+		}
+	}
+
 	result, err := discovery.MigrateConfigs(configs, dryRun)
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
 	// Display results
-	fmt.Print(config.FormatMigrationResult(result, dryRun))
+	output.Print(config.FormatMigrationResult(result, dryRun))
 	printMigrationSummary(result, dryRun, xdg.GetConfigDir())
 
 	return nil
@@ -105,52 +125,52 @@ Use --all to search across common project directories (~/dev, ~/projects, etc.).
 // printEmptyProjectsMessage prints message when no projects are found.
 func printEmptyProjectsMessage(pathsOnly bool) {
 	if !pathsOnly {
-		fmt.Println("No projects found in XDG configuration registry.")
-		fmt.Printf("Run 'blues-traveler config migrate' to migrate existing configurations.\n")
+		output.Println("No projects found in XDG configuration registry.")
+		output.Printf("Run 'blues-traveler config migrate' to migrate existing configurations.\n")
 	}
 }
 
 // printPathsOnly outputs just the project paths.
 func printPathsOnly(projects []string) {
 	for _, project := range projects {
-		fmt.Println(project)
+		output.Println(project)
 	}
 }
 
 // printProjectSummary outputs the project summary header.
 func printProjectSummary(projects []string) {
-	fmt.Printf("Found %d project(s) in XDG configuration registry:\n\n", len(projects))
+	output.Printf("Found %d project(s) in XDG configuration registry:\n\n", len(projects))
 }
 
 // printProjectBasic outputs basic project information.
 func printProjectBasic(project string) {
-	fmt.Printf("Project: %s\n", project)
+	output.Printf("Project: %s\n", project)
 }
 
 // printProjectVerbose outputs detailed project information.
 func printProjectVerbose(xdg *config.XDGConfig, project string) {
 	projectConfig, err := xdg.GetProjectConfig(project)
 	if err != nil {
-		fmt.Printf("  Error: %v\n", err)
+		output.Printf("  Error: %v\n", err)
 		return
 	}
 
 	configPath := filepath.Join(xdg.GetConfigDir(), projectConfig.ConfigFile)
-	fmt.Printf("  Config File: %s\n", configPath)
-	fmt.Printf("  Format: %s\n", projectConfig.ConfigFormat)
-	fmt.Printf("  Last Modified: %s\n", projectConfig.LastModified)
+	output.Printf("  Config File: %s\n", configPath)
+	output.Printf("  Format: %s\n", projectConfig.ConfigFormat)
+	output.Printf("  Last Modified: %s\n", projectConfig.LastModified)
 
 	// Check if config file exists
 	if _, err := os.Stat(configPath); err != nil {
-		fmt.Printf("  Status: Missing (config file not found)\n")
+		output.Printf("  Status: Missing (config file not found)\n")
 	} else {
-		fmt.Printf("  Status: OK\n")
+		output.Printf("  Status: OK\n")
 	}
 }
 
 // printVerboseHint prints hint about using verbose flag.
 func printVerboseHint() {
-	fmt.Printf("Use --verbose flag for detailed information.\n")
+	output.Printf("Use --verbose flag for detailed information.\n")
 }
 
 // executeListPathsOnly displays only project paths.
@@ -169,7 +189,7 @@ func executeListVerbose(xdg *config.XDGConfig, projects []string, verbose bool)
 			printProjectVerbose(xdg, project)
 		}
 
-		fmt.Println()
+		output.Println()
 	}
 
 	if !verbose {
@@ -287,7 +307,7 @@ func ensureGlobalConfigExists(xdg *config.XDGConfig) (string, error) {
 		if err := xdg.SaveGlobalConfig(make(map[string]interface{}), "json"); err != nil {
 			return "", fmt.Errorf("failed to create global config: %w", err)
 		}
-		fmt.Printf("Created new global configuration file: %s\n", configPath)
+		output.Printf("Created new global configuration file: %s\n", configPath)
 	}
 
 	return configPath, nil
@@ -336,7 +356,7 @@ func createNewProjectConfig(projectPath string, xdg *config.XDGConfig) error {
 	if err := xdg.SaveProjectConfig(projectPath, defaultConfig, "json"); err != nil {
 		return fmt.Errorf("failed to create project config: %w", err)
 	}
-	fmt.Printf("Created new project configuration for: %s\n", projectPath)
+	output.Printf("Created new project configuration for: %s\n", projectPath)
 	return nil
 }
 
@@ -370,7 +390,7 @@ func findCommonEditor() string {
 
 // launchEditor launches the specified editor with the config file
 func launchEditor(editor, configPath string) error {
-	fmt.Printf("Opening %s with %s...\n", configPath, editor)
+	output.Printf("Opening %s with %s...\n", configPath, editor)
 
 	// Parse editor string to support commands with arguments (e.g., "code -w")
 	parts := strings.Fields(editor)
@@ -407,15 +427,15 @@ func findOrphanedProjects(xdg *config.XDGConfig) ([]string, error) {
 
 // printOrphanedProjects prints the list of orphaned projects
 func printOrphanedProjects(orphaned []string) {
-	fmt.Printf("Found %d orphaned configuration(s):\n", len(orphaned))
+	output.Printf("Found %d orphaned configuration(s):\n", len(orphaned))
 	for _, project := range orphaned {
-		fmt.Printf("  - %s\n", project)
+		output.Printf("  - %s\n", project)
 	}
 
 	if len(orphaned) > 0 {
-		fmt.Printf("\nTo remove these configurations, run: blues-traveler config clean\n")
+		output.Printf("\nTo remove these configurations, run: blues-traveler config clean\n")
 	} else {
-		fmt.Printf("No orphaned configurations found.\n")
+		output.Printf("No orphaned configurations found.\n")
 	}
 }
 
@@ -426,13 +446,13 @@ func performCleanup(xdg *config.XDGConfig) error {
 		return fmt.Errorf("cleanup failed: %w", err)
 	}
 
-	fmt.Printf("Cleaned up %d orphaned configuration(s):\n", len(orphaned))
+	output.Printf("Cleaned up %d orphaned configuration(s):\n", len(orphaned))
 	for _, project := range orphaned {
-		fmt.Printf("  - %s\n", project)
+		output.Printf("  - %s\n", project)
 	}
 
 	if len(orphaned) == 0 {
-		fmt.Printf("No orphaned configurations found.\n")
+		output.Printf("No orphaned configurations found.\n")
 	}
 
 	return nil
@@ -440,7 +460,7 @@ func performCleanup(xdg *config.XDGConfig) error {
 
 // executeCleanDryRun executes a dry-run cleanup check.
 func executeCleanDryRun(xdg *config.XDGConfig) error {
-	fmt.Println("Dry run: checking for orphaned configurations...")
+	output.Println("Dry run: checking for orphaned configurations...")
 	orphaned, err := findOrphanedProjects(xdg)
 	if err != nil {
 		return err
@@ -457,7 +477,7 @@ func executeCleanCommand(dryRun bool) error {
 		return executeCleanDryRun(xdg)
 	}
 
-	fmt.Println("Cleaning up orphaned configurations...")
+	output.Println("Cleaning up orphaned configurations...")
 	return performCleanup(xdg)
 }
 
@@ -483,48 +503,48 @@ func NewConfigCleanCmd() *cli.Command {
 
 // displayLegacyConfigStatus displays legacy configuration status.
 func displayLegacyConfigStatus(status *config.MigrationStatus) {
-	fmt.Printf("Legacy Configuration (.claude/hooks/):\n")
+	output.Printf("Legacy Configuration (.claude/hooks/):\n")
 	if status.HasLegacyConfig {
-		fmt.Printf("  ✓ Found: %s\n", status.LegacyConfigPath)
+		output.Printf("  ✓ Found: %s\n", status.LegacyConfigPath)
 	} else {
-		fmt.Printf("  ✗ Not found: %s\n", status.LegacyConfigPath)
+		output.Printf("  ✗ Not found: %s\n", status.LegacyConfigPath)
 	}
 }
 
 // displayXDGConfigStatus displays XDG configuration status.
 func displayXDGConfigStatus(status *config.MigrationStatus) {
-	fmt.Printf("\nXDG Configuration (~/.config/blues-traveler/):\n")
+	output.Printf("\nXDG Configuration (~/.config/blues-traveler/):\n")
 	if status.HasXDGConfig {
-		fmt.Printf("  ✓ Found: %s\n", status.XDGConfigPath)
+		output.Printf("  ✓ Found: %s\n", status.XDGConfigPath)
 	} else {
-		fmt.Printf("  ✗ Not found\n")
+		output.Printf("  ✗ Not found\n")
 	}
 }
 
 // displayMigrationStatus displays migration status.
 func displayMigrationStatus(status *config.MigrationStatus) {
-	fmt.Printf("\nMigration Status:\n")
+	output.Printf("\nMigration Status:\n")
 	switch {
 	case status.NeedsMigration:
-		fmt.Printf("  ⚠ Migration needed\n")
-		fmt.Printf("  Run: blues-traveler config migrate\n")
+		output.Printf("  ⚠ Migration needed\n")
+		output.Printf("  Run: blues-traveler config migrate\n")
 	case status.HasXDGConfig:
-		fmt.Printf("  ✓ Already migrated to XDG\n")
+		output.Printf("  ✓ Already migrated to XDG\n")
 	case !status.HasLegacyConfig:
-		fmt.Printf("  ✓ No configuration found (will use defaults)\n")
+		output.Printf("  ✓ No configuration found (will use defaults)\n")
 	}
 }
 
 // displayStatusRecommendations displays recommendations based on status.
 func displayStatusRecommendations(status *config.MigrationStatus) {
-	fmt.Printf("\nRecommendations:\n")
+	output.Printf("\nRecommendations:\n")
 	switch {
 	case status.NeedsMigration:
-		fmt.Printf("  • Run 'blues-traveler config migrate' to migrate to XDG structure\n")
+		output.Printf("  • Run 'blues-traveler config migrate' to migrate to XDG structure\n")
 	case status.HasXDGConfig:
-		fmt.Printf("  • Use 'blues-traveler config edit' to modify configuration\n")
+		output.Printf("  • Use 'blues-traveler config edit' to modify configuration\n")
 	default:
-		fmt.Printf("  • Use 'blues-traveler config edit' to create a new configuration\n")
+		output.Printf("  • Use 'blues-traveler config edit' to create a new configuration\n")
 	}
 }
 
@@ -559,7 +579,7 @@ func NewConfigStatusCmd() *cli.Command {
 				return fmt.Errorf("failed to get migration status: %w", err)
 			}
 
-			fmt.Printf("Configuration Status for: %s\n\n", status.ProjectPath)
+			output.Printf("Configuration Status for: %s\n\n", status.ProjectPath)
 			displayLegacyConfigStatus(status)
 			displayXDGConfigStatus(status)
 			displayMigrationStatus(status)
@@ -608,6 +628,12 @@ func logCmdFlags() []cli.Flag {
 			Value:   0,
 			Usage:   "Maximum number of backup files to retain (default: 5)",
 		},
+		&cli.IntFlag{
+			Name:    "max-total-size",
+			Aliases: []string{"t"},
+			Value:   0,
+			Usage:   "Maximum size in MB across all hook logs, enforced by 'hooks housekeeping' (default: 100)",
+		},
 		&cli.BoolFlag{
 			Name:    "compress",
 			Aliases: []string{"c"},
@@ -662,11 +688,12 @@ func getScopeString(global bool) string {
 
 // printLogRotationSettings displays the log rotation settings.
 func printLogRotationSettings(prefix, scope, configPath string, logConfig *config.LogConfig) {
-	fmt.Printf("%s log rotation settings (%s: %s):\n", prefix, scope, configPath)
-	fmt.Printf("  Max Age: %d days\n", logConfig.LogRotation.MaxAge)
-	fmt.Printf("  Max Size: %d MB\n", logConfig.LogRotation.MaxSize)
-	fmt.Printf("  Max Backups: %d files\n", logConfig.LogRotation.MaxBackups)
-	fmt.Printf("  Compress: %t\n", logConfig.LogRotation.Compress)
+	output.Printf("%s log rotation settings (%s: %s):\n", prefix, scope, configPath)
+	output.Printf("  Max Age: %d days\n", logConfig.LogRotation.MaxAge)
+	output.Printf("  Max Size: %d MB\n", logConfig.LogRotation.MaxSize)
+	output.Printf("  Max Backups: %d files\n", logConfig.LogRotation.MaxBackups)
+	output.Printf("  Max Total Size: %d MB\n", logConfig.LogRotation.MaxTotalSize)
+	output.Printf("  Compress: %t\n", logConfig.LogRotation.Compress)
 }
 
 // applyLogRotationUpdates applies non-zero values from command flags to the config.
@@ -680,6 +707,9 @@ func applyLogRotationUpdates(cmd *cli.Command, logConfig *config.LogConfig) {
 	if maxBackups := cmd.Int("max-backups"); maxBackups > 0 {
 		logConfig.LogRotation.MaxBackups = maxBackups
 	}
+	if maxTotal := cmd.Int("max-total-size"); maxTotal > 0 {
+		logConfig.LogRotation.MaxTotalSize = maxTotal
+	}
 	if cmd.IsSet("compress") {
 		logConfig.LogRotation.Compress = cmd.Bool("compress")
 	}
@@ -687,48 +717,58 @@ func applyLogRotationUpdates(cmd *cli.Command, logConfig *config.LogConfig) {
 
 // Helper functions for migrate command
 
+// migratePreflightTargets lists what migrate writes: the XDG registry (and
+// project configs beside it) plus a backup next to each legacy config
+func migratePreflightTargets(xdg *config.XDGConfig, configs map[string]string) []config.PreflightTarget {
+	targets := []config.PreflightTarget{{Path: xdg.GetRegistryPath(), AllowedRoot: xdg.GetConfigDir()}}
+	for _, configPath := range configs {
+		targets = append(targets, config.PreflightTarget{Path: configPath + ".backup"})
+	}
+	return targets
+}
+
 // printMigrateSearchInfo displays information about the migration search scope.
 func printMigrateSearchInfo(xdg *config.XDGConfig, verbose, all bool) {
 	if !verbose {
 		return
 	}
-	fmt.Printf("XDG config directory: %s\n", xdg.GetConfigDir())
+	output.Printf("XDG config directory: %s\n", xdg.GetConfigDir())
 	if all {
-		fmt.Printf("Searching globally across common project directories\n")
+		output.Printf("Searching globally across common project directories\n")
 	} else {
-		fmt.Printf("Searching only in current directory\n")
+		output.Printf("Searching only in current directory\n")
 	}
 }
 
 // printNoConfigsFound displays message when no legacy configs are found.
 func printNoConfigsFound(all bool) {
 	if all {
-		fmt.Printf("No legacy configurations found to migrate.\n")
+		output.Printf("No legacy configurations found to migrate.\n")
 	} else {
-		fmt.Printf("No legacy configuration found in current directory.\n")
-		fmt.Printf("Use --all flag to search across common project directories.\n")
+		output.Printf("No legacy configuration found in current directory.\n")
+		output.Printf("Use --all flag to search across common project directories.\n")
 	}
 }
 
 // printFoundConfigs displays discovered configuration files.
 func printFoundConfigs(configs map[string]string, verbose bool) {
-	fmt.Printf("Found %d legacy configuration file(s)\n", len(configs))
+	output.Printf("Found %d legacy configuration file(s)\n", len(configs))
 
 	if verbose && len(configs) > 0 {
-		fmt.Printf("\nDiscovered configurations:\n")
+		output.Printf("\nDiscovered configurations:\n")
 		for projectPath, configPath := range configs {
-			fmt.Printf("  - %s\n    → %s\n", projectPath, configPath)
+			output.Printf("  - %s\n    → %s\n", projectPath, configPath)
 		}
-		fmt.Println()
+		output.Println()
 	}
 }
 
 // printMigrationSummary displays summary after migration.
 func printMigrationSummary(result *config.MigrationResult, dryRun bool, configDir string) {
 	if !dryRun && result.TotalMigrated > 0 {
-		fmt.Printf("\nMigration completed successfully!\n")
-		fmt.Printf("Configurations are now available in: %s\n", configDir)
+		output.Printf("\nMigration completed successfully!\n")
+		output.Printf("Configurations are now available in: %s\n", configDir)
 	} else if dryRun && result.TotalFound > 0 {
-		fmt.Printf("\nTo perform the actual migration, run: blues-traveler config migrate\n")
+		output.Printf("\nTo perform the actual migration, run: blues-traveler config migrate\n")
 	}
 }
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// exampleFile is the file named by the bundled file tool payloads
+const exampleFile = "example.go"
+
+// exampleToolInputs are the bundled tool inputs the gallery demos send.
+// Tools missing here get an empty input.
+var exampleToolInputs = map[string]map[string]interface{}{
+	constants.ToolBash:         {"command": "ls -la"},
+	constants.ToolEdit:         {"file_path": exampleFile, "old_string": "func main() {}", "new_string": "func main() {\n\tprintln(\"hi\")\n}"},
+	constants.ToolMultiEdit:    {"file_path": exampleFile, "edits": []map[string]string{{"old_string": "func main() {}", "new_string": "func main() {\n\tprintln(\"hi\")\n}"}}},
+	constants.ToolWrite:        {"file_path": exampleFile, "content": "package main\n\nfunc main() {}\n"},
+	constants.ToolNotebookEdit: {"notebook_path": "example.ipynb", "new_source": "print('hi')"},
+	constants.ToolRead:         {"file_path": exampleFile},
+	constants.ToolGlob:         {"pattern": "**/*.go"},
+	constants.ToolGrep:         {"pattern": "TODO"},
+	"WebFetch":                 {"url": "https://example.com", "prompt": "Summarize the page"},
+}
+
+// NewExamplesCmd creates the examples command, a gallery of the built-in hooks
+func NewExamplesCmd(cfg *HooksCommandConfig) *cli.Command {
+	return &cli.Command{
+		Name:      "examples",
+		Usage:     "Show install commands, sample configuration and a demo run for built-in hooks",
+		ArgsUsage: "[plugin-key]",
+		Description: `Print a runnable gallery entry for a built-in hook, or for every built-in
+hook when no key is given. Each entry is built from the hook's manifest:
+install commands for its default event and matcher, a configuration skeleton
+for its settings section, and the result of running the hook against a
+bundled sample payload for its default tool. Demos run in a scratch directory,
+so they never touch the project; use --no-run to skip them.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "no-run", Usage: "Skip the demo runs"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var keys []string
+			switch cmd.Args().Len() {
+			case 0:
+				for _, k := range cfg.PluginKeys() {
+					if !strings.HasPrefix(k, "config:") {
+						keys = append(keys, k)
+					}
+				}
+				sort.Strings(keys)
+			case 1:
+				keys = []string{cmd.Args().First()}
+			default:
+				return fmt.Errorf("at most one argument allowed: [plugin-key]")
+			}
+
+			for i, key := range keys {
+				p, ok := cfg.GetPlugin(key)
+				if !ok {
+					return fmt.Errorf("plugin '%s' not found\n  Suggestion: Available plugins: %s", key, strings.Join(cfg.PluginKeys(), ", "))
+				}
+				if i > 0 {
+					output.Println()
+				}
+				printExample(ctx, p.Manifest(), !cmd.Bool("no-run"))
+			}
+			return nil
+		},
+	}
+}
+
+// printExample prints the gallery entry for one hook
+func printExample(ctx context.Context, m core.Manifest, demo bool) {
+	output.Printf("== %s: %s ==\n", m.Key, m.Name)
+	if m.Description != "" {
+		output.Println(m.Description)
+	}
+
+	output.Println()
+	output.Println("Install:")
+	for _, line := range exampleInstallCommands(m) {
+		output.Printf("  %s\n", line)
+	}
+
+	if m.SettingsKey != "" {
+		output.Println()
+		output.Printf("Configuration (%s):\n", constants.ClaudeDir+"/"+constants.HooksSubDir+"/blues-traveler-config.json")
+		data, err := json.MarshalIndent(map[string]interface{}{m.SettingsKey: sampleFromSchema(m.SettingsSchema)}, "  ", "  ")
+		if err == nil {
+			output.Printf("  %s\n", data)
+		}
+	}
+
+	if !demo {
+		return
+	}
+	output.Println()
+	event, tool := exampleEvent(m)
+	if event != string(core.PreToolUseEvent) && event != string(core.PostToolUseEvent) {
+		output.Printf("Demo: skipped, %s events are only sent by Claude Code\n", event)
+		return
+	}
+	payload, err := examplePayload(tool)
+	if err != nil {
+		output.Printf("Demo: %v\n", err)
+		return
+	}
+	output.Printf("Demo (%s, %s):\n", event, tool)
+	output.Printf("  blues-traveler hooks test %s --event %s --tool %s --input %s\n", m.Key, event, tool, core.ShellQuote(exampleInputJSON(tool)))
+	result, err := runExampleDemo(ctx, m.Key, event, payload)
+	if err != nil {
+		output.Printf("  ⚠️  %v\n", err)
+		return
+	}
+	decision := result.Summary.Decision
+	if decision == "" {
+		decision = "(none)"
+	}
+	output.Printf("  Hook decision: %s\n", decision)
+	if result.Summary.UserMessage != "" {
+		output.Printf("  User message:  %s\n", result.Summary.UserMessage)
+	}
+	output.Printf("  Result:        %s\n", result.Final)
+}
+
+// exampleInstallCommands returns install command lines for a manifest's defaults
+func exampleInstallCommands(m core.Manifest) []string {
+	event := m.DefaultEvent
+	if event == "" {
+		event = string(core.PreToolUseEvent)
+	}
+	lines := []string{
+		"blues-traveler hooks install " + m.Key,
+		fmt.Sprintf("blues-traveler hooks install %s --event %s --matcher %s", m.Key, event, core.ShellQuote(m.DefaultMatcher)),
+		"blues-traveler hooks install " + m.Key + " --global",
+	}
+	for _, other := range m.Events {
+		if other != event {
+			lines = append(lines, fmt.Sprintf("blues-traveler hooks install %s --event %s", m.Key, other))
+		}
+	}
+	return lines
+}
+
+// exampleEvent picks the demo event and tool: the default event and the
+// first concrete tool in the default matcher
+func exampleEvent(m core.Manifest) (string, string) {
+	event := m.DefaultEvent
+	if event == "" {
+		event = string(core.PreToolUseEvent)
+	}
+	if strings.HasPrefix(m.DefaultMatcher, core.MCPToolPrefix) {
+		return event, core.MCPToolPrefix + "example__search"
+	}
+	for _, tool := range strings.Split(m.DefaultMatcher, "|") {
+		tool = strings.TrimSpace(tool)
+		if _, ok := exampleToolInputs[tool]; ok {
+			return event, tool
+		}
+	}
+	return event, constants.ToolBash
+}
+
+// exampleInputJSON returns the bundled tool input for tool as JSON
+func exampleInputJSON(tool string) string {
+	input := exampleToolInputs[tool]
+	if input == nil {
+		return "{}"
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// examplePayload builds the event JSON for tool's bundled input
+func examplePayload(tool string) ([]byte, error) {
+	return buildTestEventPayload("", tool, exampleInputJSON(tool), "{}")
+}
+
+// runExampleDemo runs a hook against payload in a scratch directory holding
+// the file the bundled inputs name, so hooks that inspect or rewrite files
+// never touch the project
+func runExampleDemo(ctx context.Context, key, event string, payload []byte) (*hookTestResult, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	scratch, err := os.MkdirTemp("", "bt-examples-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+	content := exampleToolInputs[constants.ToolWrite]["content"].(string)
+	if err := os.WriteFile(filepath.Join(scratch, exampleFile), []byte(content), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write example file: %w", err)
+	}
+	if err := os.Chdir(scratch); err != nil {
+		return nil, fmt.Errorf("failed to enter scratch directory: %w", err)
+	}
+	defer func() { _ = os.Chdir(cwd) }()
+
+	return runHookTest(ctx, key, event, payload, nil, nil)
+}
+
+// sampleFromSchema returns a placeholder value matching a JSON Schema built
+// by config.SectionSchema
+func sampleFromSchema(schema map[string]interface{}) interface{} {
+	switch schema["type"] {
+	case "boolean":
+		return false
+	case "integer", "number":
+		return 0
+	case "string":
+		return ""
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		return []interface{}{sampleFromSchema(items)}
+	case "object":
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			obj := map[string]interface{}{}
+			for name, prop := range props {
+				p, _ := prop.(map[string]interface{})
+				obj[name] = sampleFromSchema(p)
+			}
+			return obj
+		}
+		if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			return map[string]interface{}{"example": sampleFromSchema(values)}
+		}
+		return map[string]interface{}{}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestSampleFromSchema(t *testing.T) {
+	sample := sampleFromSchema(config.SectionSchema(config.LargeFilesConfig{}))
+	obj, ok := sample.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		t.Fatalf("expected an object with every setting, got %#v", sample)
+	}
+	schema := config.SectionSchema(config.LargeFilesConfig{})
+	for name := range schema["properties"].(map[string]interface{}) {
+		if _, ok := obj[name]; !ok {
+			t.Errorf("sample is missing %q: %#v", name, obj)
+		}
+	}
+}
+
+func TestExampleInstallCommandsAndEvent(t *testing.T) {
+	m := core.Manifest{
+		Key:            "format",
+		Events:         []string{"PostToolUse", "PreToolUse"},
+		DefaultEvent:   "PostToolUse",
+		DefaultMatcher: "Edit|Write",
+	}
+	lines := exampleInstallCommands(m)
+	if lines[1] != "blues-traveler hooks install format --event PostToolUse --matcher 'Edit|Write'" {
+		t.Errorf("unexpected explicit install line %q", lines[1])
+	}
+	if last := lines[len(lines)-1]; last != "blues-traveler hooks install format --event PreToolUse" {
+		t.Errorf("expected a line for the other event, got %q", last)
+	}
+	if event, tool := exampleEvent(m); event != "PostToolUse" || tool != "Edit" {
+		t.Errorf("got %s/%s", event, tool)
+	}
+	if _, tool := exampleEvent(core.Manifest{DefaultMatcher: core.MCPAnyToolMatcher}); !core.IsMCPTool(tool) {
+		t.Errorf("expected an MCP tool for an MCP matcher, got %s", tool)
+	}
+	if _, tool := exampleEvent(core.Manifest{DefaultMatcher: "*"}); tool != "Bash" {
+		t.Errorf("expected Bash for a wildcard matcher, got %s", tool)
+	}
+}
+
+func TestRunExampleDemo_ScratchDirectory(t *testing.T) {
+	registerDecisionTestHooks()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	payload, err := examplePayload("Write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := runExampleDemo(context.Background(), "test-block", string(core.PreToolUseEvent), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Final != "block" {
+		t.Errorf("expected block, got %+v", result)
+	}
+	if cwd, _ := os.Getwd(); !strings.HasSuffix(cwd, dir) {
+		t.Errorf("expected to return to %s, now in %s", dir, cwd)
+	}
+	if _, err := os.Stat(exampleFile); !os.IsNotExist(err) {
+		t.Errorf("demo file should stay in the scratch directory, got %v", err)
+	}
+}
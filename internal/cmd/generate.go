@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/klauern/blues-traveler/internal/generator"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -48,7 +49,7 @@ func executeGenerateCommand(hookName, description, hookTypeStr, outputDir string
 		return fmt.Errorf("failed to generate hook '%s': %w\n  Suggestion: Check write permissions in the output directory '%s'", hookName, err, outputDir)
 	}
 
-	fmt.Printf("\n✅ Successfully generated hook '%s'\n", hookName)
+	output.Printf("\n✅ Successfully generated hook '%s'\n", hookName)
 	return nil
 }
 
@@ -3,12 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -26,7 +31,11 @@ type EventType string
 // PluginProvider defines the interface for plugin operations
 type PluginProvider interface {
 	Run() error
+	// SetRunContext sets the context that cancels the plugin's work
+	SetRunContext(ctx context.Context)
 	Description() string
+	// Manifest describes the plugin's events, install defaults and capabilities
+	Manifest() core.Manifest
 }
 
 // HooksCommandConfig encapsulates all dependencies for hooks command construction
@@ -54,13 +63,45 @@ func NewHooksCommand(cfg *HooksCommandConfig) *cli.Command {
 		Commands: []*cli.Command{
 			newHooksListCommand(cfg.GetPlugin, cfg.PluginKeys, cfg.AllEvents),
 			newHooksRunCommand(cfg.GetPlugin, cfg.IsPluginEnabled, cfg.PluginKeys),
+			newHooksTestCommand(cfg.PluginKeys),
+			newHooksSnoozeCommand(cfg.PluginKeys),
+			newHooksToggleCommand(false, cfg.PluginKeys),
+			newHooksToggleCommand(true, cfg.PluginKeys),
+			newHooksLatencyCommand(),
+			newHooksStatsCommand(),
+			newHooksExperimentsCommand(),
+			newHooksHousekeepingCommand(),
+			newHooksArtifactsCommand(),
+			newHooksInitCommand(cfg.GetPlugin, cfg.IsValidEventType, cfg.ValidEventTypes),
 			newHooksInstallCommand(cfg.GetPlugin, cfg.PluginKeys, cfg.IsValidEventType, cfg.ValidEventTypes),
-			newHooksUninstallCommand(),
+			newHooksUninstallCommand(cfg.IsValidEventType, cfg.ValidEventTypes),
+			newHooksManageCommand(),
 			newHooksCustomCommand(cfg.IsValidEventType, cfg.ValidEventTypes),
 		},
 	}
 }
 
+// NewLegacyRunCommand creates a hidden top-level `run` command that keeps the
+// deprecated "blues-traveler run <key>" form working. It warns on stderr (stdout
+// is reserved for hook responses) and delegates to `hooks run`.
+func NewLegacyRunCommand(cfg *HooksCommandConfig) *cli.Command {
+	runCmd := newHooksRunCommand(cfg.GetPlugin, cfg.IsPluginEnabled, cfg.PluginKeys)
+	delegate := runCmd.Action
+	runCmd.Hidden = true
+	runCmd.Usage = "Deprecated: use 'hooks run'"
+	runCmd.Action = func(ctx context.Context, cmd *cli.Command) error {
+		warnLegacyRunUsage(os.Stderr, cmd.Args().First())
+		return delegate(ctx, cmd)
+	}
+	return runCmd
+}
+
+// warnLegacyRunUsage prints the deprecation notice for the legacy run form
+func warnLegacyRunUsage(w io.Writer, key string) {
+	_, _ = fmt.Fprintf(w, "⚠️  'blues-traveler run %s' is deprecated; use 'blues-traveler hooks run %s'.\n", key, key)
+	_, _ = fmt.Fprintln(w, "   Installed settings are rewritten to the new form the next time they are saved (e.g. 'hooks install').")
+}
+
 // newHooksListCommand creates the consolidated list command
 func newHooksListCommand(
 	getPlugin func(string) (PluginProvider, bool),
@@ -90,14 +131,35 @@ func newHooksListCommand(
 				Value:   false,
 				Usage:   "Show global settings (~/.claude/settings.json) when using --installed",
 			},
+			&cli.BoolFlag{
+				Name:  "manifest",
+				Value: false,
+				Usage: "Print every hook's manifest (events, install defaults, settings schema, capabilities) as JSON",
+			},
+			&cli.BoolFlag{
+				Name:  "source",
+				Value: false,
+				Usage: "With --installed, show where each entry came from: a built-in install, a config group job, or added externally",
+			},
+			&cli.StringFlag{
+				Name:  "filter",
+				Usage: "With --installed, only show entries matching 'group=<name>' (built-in installs are group " + config.BuiltinGroupName + ")",
+			},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("manifest") {
+				return listHookManifests(getPlugin, pluginKeys)
+			}
 			installed := cmd.Bool("installed")
 			events := cmd.Bool("events")
 			global := cmd.Bool("global")
 
-			if installed {
-				return listInstalledHooks(global)
+			if installed || cmd.Bool("source") || cmd.IsSet("filter") {
+				group, err := parseInstalledFilter(cmd.String("filter"))
+				if err != nil {
+					return err
+				}
+				return listInstalledHooks(global, installedListOptions{showSource: cmd.Bool("source"), group: group})
 			}
 
 			if events {
@@ -117,10 +179,30 @@ func newHooksRunCommand(
 	pluginKeys func() []string,
 ) *cli.Command {
 	return &cli.Command{
-		Name:        "run",
-		Usage:       "Run a specific hook plugin",
-		ArgsUsage:   "[plugin-key]",
-		Description: `Run a specific hook plugin. Executes only that hook's handlers (no unified pipeline).`,
+		Name:      "run",
+		Usage:     "Run a specific hook plugin",
+		ArgsUsage: "[plugin-key]",
+		ShellComplete: completeArgs(func() []string {
+			keys := pluginKeys()
+			sort.Strings(keys)
+			return keys
+		}),
+		Description: `Run a specific hook plugin. Executes only that hook's handlers (no unified pipeline).
+
+With --replay, the hook runs once for every event captured in a file instead
+of reading stdin, and the response Claude Code would have received is printed
+for each one. The file can be a payload saved from stdin, JSONL of payloads,
+or a hook log written with --log, whose raw_event entries hold each payload.
+
+With --dry-run, a custom job (config:<group>:<job>) evaluates its only/skip
+conditions and globs against the event and prints whether it would run, the
+command with variables expanded and its environment, without running it. The
+event comes from --replay, stdin, or is made up for the job's event type.
+
+Examples:
+  blues-traveler hooks run security --replay .claude/hooks/debug.log
+  blues-traveler hooks run config:go:fmt --replay event.json
+  blues-traveler hooks run config:go:fmt --dry-run < event.json`,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "log",
@@ -133,23 +215,80 @@ func newHooksRunCommand(
 				Value: "jsonl",
 				Usage: "Log output format: jsonl or pretty (default jsonl)",
 			},
+			&cli.StringFlag{
+				Name:  "replay",
+				Usage: "Replay events captured in this file instead of reading stdin",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show whether a custom job would run, its expanded command and environment, without running it",
+			},
+			&cli.BoolFlag{
+				Name:   "bench-self",
+				Hidden: true,
+				Usage:  "Measure the overhead 'hooks run' adds on this machine (default hook: security)",
+			},
+			&cli.IntFlag{
+				Name:   "bench-iterations",
+				Hidden: true,
+				Value:  defaultBenchIterations,
+				Usage:  "Runs to time with --bench-self",
+			},
 		},
-		Action: func(_ context.Context, cmd *cli.Command) error {
+		Action: func(ctx context.Context, cmd *cli.Command) error {
 			args := cmd.Args().Slice()
+			if cmd.Bool("bench-self") {
+				key := benchSelfDefaultHook
+				if len(args) > 0 {
+					key = args[0]
+				}
+				if _, exists := getPlugin(key); !exists {
+					return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", key, strings.Join(pluginKeys(), ", "))
+				}
+				return runBenchSelf(ctx, key, isPluginEnabled, cmd.Int("bench-iterations"))
+			}
 			if len(args) != 1 {
 				return fmt.Errorf("exactly one argument required: [plugin-key]")
 			}
 			key := args[0]
 
+			if cmd.Bool("dry-run") {
+				if _, exists := getPlugin(key); !exists {
+					return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", key, strings.Join(pluginKeys(), ", "))
+				}
+				return runHookDryRun(ctx, key, cmd.String("replay"))
+			}
+
+			if replayPath := cmd.String("replay"); replayPath != "" {
+				if _, exists := getPlugin(key); !exists {
+					return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", key, strings.Join(pluginKeys(), ", "))
+				}
+				return runHookReplay(ctx, key, replayPath, isPluginEnabled(key))
+			}
+
+			// Kill switch beats everything else so operators can stop all hooks
+			// during an incident, even ones that are misconfigured
+			if path, ok := config.ActiveKillSwitch(); ok {
+				log.Printf("all hooks disabled by %s; allowing '%s' without running", path, key)
+				return nil
+			}
+
 			// Validate plugin exists early
 			p, exists := getPlugin(key)
 			if !exists {
 				return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", key, strings.Join(pluginKeys(), ", "))
 			}
 
+			// Claude Code reads what the hook prints; terse mode keeps banners
+			// and decoration out of it
+			terse := config.GetTerseOutput()
+			output.SetTerse(terse)
+
 			// Enablement check before side effects
 			if !isPluginEnabled(key) {
-				fmt.Printf("Plugin '%s' is disabled via settings. Nothing to do.\n", key)
+				if !terse {
+					output.Say("hooks.run.disabled", map[string]any{"Key": key})
+				}
 				return nil
 			}
 
@@ -162,13 +301,34 @@ func newHooksRunCommand(
 			if logEnabled && !config.IsValidLoggingFormat(logFormat) {
 				return fmt.Errorf("invalid --log-format '%s'. Valid: jsonl, pretty", logFormat)
 			}
+			redactor := hookRedactor(key)
 			if logEnabled {
-				if err := setupHookLogging(key, logFormat); err != nil {
+				closeLog, err := setupHookLogging(key, logFormat, redactor)
+				if err != nil {
 					return err
 				}
+				defer closeLog()
 			}
 
-			fmt.Printf("Running hook '%s'...\n", key)
+			// Snoozed hooks allow immediately; the log line keeps the skip visible
+			if snooze, ok := config.ActiveSnooze(key); ok {
+				log.Printf("hook '%s' snoozed via '%s' until %s; allowing without running", key, snooze.Key, snooze.Until.Local().Format(time.RFC3339))
+				return nil
+			}
+
+			// An entry switched off with 'hooks disable --event' stays
+			// installed; its runs allow without starting the hook
+			if toggle, off := disabledEntryForRun(key); off {
+				log.Printf("hook '%s' entry for %s (matcher %q) disabled in settings; allowing without running", key, toggle.Event, toggle.Matcher)
+				return nil
+			}
+
+			applyHookRunSettings(terse, redactor)
+
+			if !terse {
+				output.Say("hooks.run.start", map[string]any{"Key": key})
+			}
+			p.SetRunContext(ctx)
 			if err := p.Run(); err != nil {
 				return fmt.Errorf("hook '%s' failed: %w", key, err)
 			}
@@ -177,13 +337,30 @@ func newHooksRunCommand(
 	}
 }
 
-// setupHookLogging configures logging with rotation for hook execution
-func setupHookLogging(hookKey, logFormat string) error {
-	logConfig := config.GetLogRotationConfigFromFile(false)
-	// Treat an entirely zeroed config as "not configured"; otherwise respect zeros intentionally set
-	if logConfig.MaxAge == 0 && logConfig.MaxSize == 0 && logConfig.MaxBackups == 0 {
-		logConfig = config.GetLogRotationConfigFromFile(true)
+// applyHookRunSettings loads the config settings hooks read while they run
+func applyHookRunSettings(terse bool, redactor *core.Redactor) {
+	core.SetGlobalRedactor(redactor)
+	core.SetGlobalLatencyThreshold(config.GetLatencyThreshold())
+	core.SetGlobalLoggingDefaults(config.GetLoggingDefaults())
+	core.SetGlobalExperiments(config.GetExperiments())
+	core.SetGlobalMetricsEnabled(config.GetMetricsEnabled())
+	core.SetGlobalTerseOutput(terse)
+}
+
+// hookRedactor compiles the redaction rules for hookKey. Bad rules are
+// logged and skipped; the rest still apply.
+func hookRedactor(hookKey string) *core.Redactor {
+	redactor, err := core.NewRedactor(config.GetRedactionRules(hookKey))
+	if err != nil {
+		log.Printf("%v", err)
 	}
+	return redactor
+}
+
+// setupHookLogging configures logging with rotation for hook execution.
+// The returned func flushes the rotating writer and must be called on exit.
+func setupHookLogging(hookKey, logFormat string, redactor *core.Redactor) (func(), error) {
+	logConfig := config.ResolveLogRotationConfig()
 
 	logPath := config.GetLogPath(hookKey)
 	rotatingLogger := config.SetupLogRotation(logPath, logConfig)
@@ -192,16 +369,23 @@ func setupHookLogging(hookKey, logFormat string) error {
 
 	if rotatingLogger != nil {
 		// Route stdlib logger to the rotating file target so log.Printf from hooks is captured
-		log.SetOutput(rotatingLogger)
-		fmt.Printf("Logging enabled with rotation - output will be written to %s\n", logPath)
-		fmt.Printf("Log rotation: max %d days, %dMB per file, %d backups\n",
-			logConfig.MaxAge, logConfig.MaxSize, logConfig.MaxBackups)
-		if err := config.CleanupOldLogs(filepath.Dir(logPath), logConfig.MaxAge); err != nil {
-			fmt.Printf("Warning: Failed to cleanup old logs: %v\n", err)
+		log.SetOutput(redactor.Writer(rotatingLogger))
+		core.SetGlobalLogWriter(rotatingLogger)
+		if !output.IsTerse() {
+			output.Printf("Logging enabled with rotation - output will be written to %s\n", logPath)
+			output.Printf("Log rotation: max %d days, %dMB per file, %d backups\n",
+				logConfig.MaxAge, logConfig.MaxSize, logConfig.MaxBackups)
 		}
-	} else {
-		fmt.Printf("Logging enabled - output will be written to %s\n", logPath)
+		startBackgroundHousekeeping()
+		return func() {
+			log.SetOutput(os.Stderr)
+			core.SetGlobalLogWriter(nil)
+			_ = rotatingLogger.Close()
+		}, nil
 	}
 
-	return nil
+	if !output.IsTerse() {
+		output.Printf("Logging enabled - output will be written to %s\n", logPath)
+	}
+	return func() {}, nil
 }
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksArtifactsCommand creates the artifacts command for output kept by
+// capture_output jobs
+func newHooksArtifactsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "artifacts",
+		Usage: "List, show and clean output captured from custom jobs",
+		Description: `A custom job with capture_output: true keeps the stdout and stderr of every
+run in .claude/hooks/artifacts/<group>/<job>/<timestamp>/, next to a
+meta.json with the event, exit code and duration. Runs are pruned by the
+logRotation settings in blues-traveler-config.json: runs older than maxAge
+days are removed, each job keeps at most maxBackups runs, and each stream is
+cut to its last maxSize megabytes.
+
+Examples:
+  blues-traveler hooks artifacts list --group go
+  blues-traveler hooks artifacts show go/test
+  blues-traveler hooks artifacts show go/test/20261017-142501.123 --stream stderr
+  blues-traveler hooks artifacts clean --older-than 7d`,
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "Show captured runs, newest first",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "group", Usage: "Only runs of jobs in this group"},
+					&cli.StringFlag{Name: "job", Usage: "Only runs of this job"},
+					&cli.BoolFlag{Name: "json", Usage: "Print runs as JSON lines"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					artifacts, err := core.ListJobArtifacts(cmd.String("group"), cmd.String("job"))
+					if err != nil {
+						return err
+					}
+					return listJobArtifacts(artifacts, cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "show",
+				Usage:     "Print the output of a captured run",
+				ArgsUsage: "<group>/<job>[/<id>]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "stream", Value: "all", Usage: "Output to print: stdout, stderr or all (stdout and stderr print the file as is)"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("show requires one run, <group>/<job> for the latest or <group>/<job>/<id>\n  Suggestion: Run 'blues-traveler hooks artifacts list' to see captured runs")
+					}
+					a, err := core.FindJobArtifact(cmd.Args().First())
+					if err != nil {
+						return err
+					}
+					return showJobArtifact(*a, cmd.String("stream"))
+				},
+			},
+			{
+				Name:  "clean",
+				Usage: "Delete captured runs",
+				Description: `Without --older-than or --all, applies the log rotation policy right away
+instead of waiting for the job's next run or 'hooks housekeeping'.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "group", Usage: "Only runs of jobs in this group"},
+					&cli.StringFlag{Name: "job", Usage: "Only runs of this job"},
+					&cli.StringFlag{Name: "older-than", Usage: "Delete runs older than a duration (e.g. 7d, 12h)"},
+					&cli.BoolFlag{Name: "all", Usage: "Delete every run"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					group, job := cmd.String("group"), cmd.String("job")
+					var (
+						removed int
+						freed   int64
+						err     error
+					)
+					switch {
+					case cmd.Bool("all") && cmd.String("older-than") != "":
+						return fmt.Errorf("--all and --older-than cannot be combined\n  Suggestion: Use --all to delete every run, or --older-than for old ones")
+					case cmd.Bool("all"):
+						removed, freed, err = core.RemoveJobArtifacts(group, job, time.Time{})
+					case cmd.String("older-than") != "":
+						before, perr := parseSince(cmd.String("older-than"), time.Now())
+						if perr != nil {
+							return perr
+						}
+						removed, freed, err = core.RemoveJobArtifacts(group, job, before)
+					default:
+						removed, freed, err = core.PruneJobArtifacts(group, job, config.ResolveLogRotationConfig(), time.Now())
+					}
+					if err != nil {
+						return err
+					}
+					output.Printf("✅ Removed %d captured run(s), freed %s\n", removed, core.FormatBytes(freed))
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func listJobArtifacts(artifacts []core.JobArtifact, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, a := range artifacts {
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(artifacts) == 0 {
+		output.Println("No captured job output. Set capture_output: true on a job in hooks.yml to keep it.")
+		return nil
+	}
+	for _, a := range artifacts {
+		status := fmt.Sprintf("exit %d", a.ExitCode)
+		if a.Error != "" {
+			status = a.Error
+		}
+		output.Printf("%s  %s  %s  %s  %s  (stdout %s, stderr %s)\n",
+			a.Ref(), a.Time.Local().Format("2006-01-02 15:04:05"), a.Event, status,
+			time.Duration(a.DurationMS)*time.Millisecond, core.FormatBytes(a.StdoutBytes), core.FormatBytes(a.StderrBytes))
+	}
+	return nil
+}
+
+func showJobArtifact(a core.JobArtifact, stream string) error {
+	dir, err := core.JobArtifactDir(a)
+	if err != nil {
+		return err
+	}
+	read := func(name string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304 - file in a run dir under the artifacts dir
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return string(data), nil
+	}
+	switch stream {
+	case "stdout", "stderr":
+		name := core.JobArtifactStdout
+		if stream == "stderr" {
+			name = core.JobArtifactStderr
+		}
+		data, err := read(name)
+		if err != nil {
+			return err
+		}
+		// As is, without --plain rewriting, so it can be piped
+		_, err = os.Stdout.WriteString(data)
+		return err
+	case "all":
+	default:
+		return fmt.Errorf("invalid --stream '%s'\n  Suggestion: Use stdout, stderr or all", stream)
+	}
+
+	output.Printf("Run:      %s\n", a.Ref())
+	output.Printf("Time:     %s\n", a.Time.Local().Format(time.RFC3339))
+	event := a.Event
+	if a.Tool != "" {
+		event += " (" + a.Tool + ")"
+	}
+	output.Printf("Event:    %s\n", event)
+	if a.File != "" {
+		output.Printf("File:     %s\n", a.File)
+	}
+	output.Printf("Command:  %s\n", a.Command)
+	output.Printf("Exit:     %d after %s\n", a.ExitCode, time.Duration(a.DurationMS)*time.Millisecond)
+	if a.Error != "" {
+		output.Printf("Error:    %s\n", a.Error)
+	}
+	if a.Truncated {
+		output.Println("Output was cut to logRotation.maxSize; the sizes below are before cutting.")
+	}
+	for _, s := range []struct {
+		label, name string
+		size        int64
+	}{
+		{"stdout", core.JobArtifactStdout, a.StdoutBytes},
+		{"stderr", core.JobArtifactStderr, a.StderrBytes},
+	} {
+		data, err := read(s.name)
+		if err != nil {
+			return err
+		}
+		output.Printf("\n--- %s (%s) ---\n", s.label, core.FormatBytes(s.size))
+		output.Printf("%s", data)
+		if data != "" && data[len(data)-1] != '\n' {
+			output.Println("")
+		}
+	}
+	return nil
+}
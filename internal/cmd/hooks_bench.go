@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+const (
+	defaultBenchIterations = 20
+	// benchSelfDefaultHook runs no commands, so its timings are overhead only
+	benchSelfDefaultHook = "security"
+	// benchSelfPayload is a harmless PreToolUse every hook lets through
+	benchSelfPayload = `{"session_id":"bench-self","transcript_path":"","cwd":".","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"git status","description":"Show working tree status"}}`
+)
+
+// benchTimings summarizes the durations of repeated runs
+type benchTimings struct {
+	runs []time.Duration
+}
+
+func (b *benchTimings) add(d time.Duration) { b.runs = append(b.runs, d) }
+
+// percentile returns the p-th percentile (0-100) by nearest rank
+func (b *benchTimings) percentile(p int) time.Duration {
+	if len(b.runs) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), b.runs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := (p*len(sorted)+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func (b *benchTimings) String() string {
+	return fmt.Sprintf("p50 %s  p95 %s  max %s", formatBenchDuration(b.percentile(50)),
+		formatBenchDuration(b.percentile(95)), formatBenchDuration(b.percentile(100)))
+}
+
+func formatBenchDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
+
+// runBenchSelf times 'hooks run <key>' on this machine, as Claude Code
+// starts it (a new process per tool call) and within this process (config
+// load and the hook's decision only), and compares the median to the
+// overhead budget. The payload is a harmless Bash call, so config hooks
+// whose conditions match it run their commands and inflate the numbers.
+func runBenchSelf(ctx context.Context, key string, isPluginEnabled func(string) bool, iterations int) error {
+	if iterations <= 0 {
+		return fmt.Errorf("--bench-iterations must be positive, got %d", iterations)
+	}
+	events, err := core.ParseReplayEvents([]byte(benchSelfPayload), "bench-self")
+	if err != nil {
+		return err
+	}
+	event := events[0]
+
+	output.Printf("Measuring '%s' over %d run(s) (budget %s)...\n\n", key, iterations, formatBenchDuration(core.HookOverheadBudget))
+
+	var process benchTimings
+	exe, exeErr := os.Executable()
+	if exeErr == nil {
+		for i := 0; i < iterations; i++ {
+			d, err := timeHookProcess(ctx, exe, key)
+			if err != nil {
+				exeErr = err
+				break
+			}
+			process.add(d)
+		}
+	}
+
+	var inProcess benchTimings
+	for i := 0; i < iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("benchmark cancelled: %w", err)
+		}
+		start := time.Now()
+		if _, off := config.ActiveKillSwitch(); !off && isPluginEnabled(key) {
+			if _, snoozed := config.ActiveSnooze(key); !snoozed {
+				applyHookRunSettings(config.GetTerseOutput(), hookRedactor(key))
+			}
+		}
+		if _, err := core.ReplayHook(ctx, key, event); err != nil {
+			return fmt.Errorf("failed to run '%s': %w", key, err)
+		}
+		inProcess.add(time.Since(start))
+	}
+
+	if exeErr != nil {
+		output.Printf("  New process:  unavailable (%v)\n", exeErr)
+	} else {
+		output.Printf("  New process:  %s\n", process.String())
+	}
+	output.Printf("  In process:   %s\n\n", inProcess.String())
+
+	// A new process is what every tool call pays; fall back to the in-process
+	// number when the binary could not be started
+	median := inProcess.percentile(50)
+	if exeErr == nil {
+		median = process.percentile(50)
+	}
+	if median > core.HookOverheadBudget {
+		output.Printf("⚠️  Median overhead %s is over the %s budget.\n", formatBenchDuration(median), formatBenchDuration(core.HookOverheadBudget))
+		output.Println("   Check 'hooks latency' for slow events and keep the config free of large or remote files.")
+		return nil
+	}
+	output.Printf("✅ Median overhead %s is within the %s budget.\n", formatBenchDuration(median), formatBenchDuration(core.HookOverheadBudget))
+	return nil
+}
+
+// timeHookProcess runs 'hooks run <key>' once with the bench payload on
+// stdin and returns how long it took
+func timeHookProcess(ctx context.Context, exe, key string) (time.Duration, error) {
+	c := exec.CommandContext(ctx, exe, "hooks", "run", key) // #nosec G204 - this binary re-running itself
+	c.Stdin = bytes.NewReader([]byte(benchSelfPayload))
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	start := time.Now()
+	err := c.Run()
+	d := time.Since(start)
+	if err != nil {
+		if stderr.Len() > 0 {
+			return 0, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return 0, err
+	}
+	return d, nil
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBenchTimingsPercentile(t *testing.T) {
+	var b benchTimings
+	if got := b.percentile(50); got != 0 {
+		t.Errorf("empty percentile = %s, want 0", got)
+	}
+	for _, ms := range []int{9, 1, 5, 3, 7, 2, 8, 4, 10, 6} {
+		b.add(time.Duration(ms) * time.Millisecond)
+	}
+	for p, want := range map[int]time.Duration{
+		0:   time.Millisecond,
+		50:  5 * time.Millisecond,
+		95:  10 * time.Millisecond,
+		100: 10 * time.Millisecond,
+	} {
+		if got := b.percentile(p); got != want {
+			t.Errorf("p%d = %s, want %s", p, got, want)
+		}
+	}
+	if b.runs[0] != 9*time.Millisecond {
+		t.Error("percentile must not reorder the recorded runs")
+	}
+}
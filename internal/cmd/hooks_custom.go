@@ -3,10 +3,13 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -41,11 +44,11 @@ func newHooksCustomListCommand() *cli.Command {
 			}
 			groups := config.ListHookGroups(cfg)
 			if len(groups) == 0 {
-				fmt.Println("No custom hook groups found")
+				output.Println("No custom hook groups found")
 				return nil
 			}
 			for _, g := range groups {
-				fmt.Println(g)
+				output.Println(groupLabel(cfg, g))
 			}
 			return nil
 		},
@@ -65,7 +68,10 @@ func newHooksCustomValidateCommand() *cli.Command {
 			if err := config.ValidateHooksConfig(cfg); err != nil {
 				return fmt.Errorf("invalid hooks config: %w", err)
 			}
-			fmt.Println("hooks config is valid")
+			if errs := config.CheckJobEnvFiles(cfg); len(errs) > 0 {
+				return fmt.Errorf("invalid hooks config: %w", errors.Join(errs...))
+			}
+			output.Println("hooks config is valid")
 			return nil
 		},
 	}
@@ -79,13 +85,39 @@ func newHooksCustomShowCommand() *cli.Command {
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Value: "yaml", Usage: "Output format: yaml or json"},
 			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Prefer global config when showing embedded sections"},
+			&cli.BoolFlag{Name: "explain", Usage: "List the config layers in precedence order and where each job comes from"},
+			&cli.BoolFlag{Name: "resolved", Usage: "Flatten group extends, showing each group with the jobs it inherits"},
+			&cli.BoolFlag{Name: "files", Usage: "List the file each group comes from, and the files whose definition it overrides"},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("files") {
+				layers, err := config.LoadHooksConfigLayers()
+				if err != nil {
+					return fmt.Errorf("load hooks config: %w", err)
+				}
+				output.Print(describeGroupFiles(layers))
+				return nil
+			}
+			if cmd.Bool("explain") {
+				layers, err := config.LoadHooksConfigLayers()
+				if err != nil {
+					return fmt.Errorf("load hooks config: %w", err)
+				}
+				output.Print(explainHooksConfig(layers))
+				return nil
+			}
+
 			// Load merged hooks config (project over global, including embedded and legacy)
-			hooksCfg, err := config.LoadHooksConfig()
+			layers, err := config.LoadHooksConfigLayers()
 			if err != nil {
 				return fmt.Errorf("load hooks config: %w", err)
 			}
+			hooksCfg := config.MergeHooksConfigLayers(layers)
+			if cmd.Bool("resolved") {
+				if hooksCfg, err = config.ResolveGroupExtends(hooksCfg); err != nil {
+					return fmt.Errorf("resolve group extends: %w\n  Suggestion: Point extends at an existing group and break any cycle", err)
+				}
+			}
 
 			// Load embedded blocked URLs for display (prefer project unless --global)
 			useGlobal := cmd.Bool("global")
@@ -105,6 +137,9 @@ func newHooksCustomShowCommand() *cli.Command {
 			if len(logCfg.BlockedURLs) > 0 {
 				out["blockedUrls"] = logCfg.BlockedURLs
 			}
+			if logCfg.BlockedURLsRemote != nil {
+				out["blockedUrlsRemote"] = logCfg.BlockedURLsRemote
+			}
 
 			switch strings.ToLower(cmd.String("format")) {
 			case "json":
@@ -112,15 +147,115 @@ func newHooksCustomShowCommand() *cli.Command {
 				if err != nil {
 					return err
 				}
-				fmt.Println(string(b))
+				output.Println(string(b))
 			default:
 				b, err := yaml.Marshal(out)
 				if err != nil {
 					return err
 				}
-				fmt.Print(string(b))
+				output.Print(string(b))
 			}
 			return nil
 		},
 	}
 }
+
+// explainHooksConfig describes each layer and, for every job in the
+// effective config, the layer that supplies it and any layers it overrides
+func explainHooksConfig(layers []config.HooksConfigLayer) string {
+	var b strings.Builder
+	if len(layers) == 0 {
+		b.WriteString("No custom hook config found\n")
+		return b.String()
+	}
+	b.WriteString("Config layers (highest precedence first):\n")
+	for i, l := range layers {
+		fmt.Fprintf(&b, "  %d. %-7s %s%s\n", i+1, l.Scope, l.Source, includeNote(l))
+	}
+
+	// origins maps group/event/job to the layers defining it, highest first
+	origins := map[string][]int{}
+	for i, l := range layers {
+		for group, grp := range l.Config {
+			if grp == nil {
+				continue
+			}
+			for event, ev := range grp.Events {
+				if ev == nil {
+					continue
+				}
+				for _, job := range ev.Jobs {
+					key := fmt.Sprintf("%s/%s/%s", group, event, job.Name)
+					origins[key] = append(origins[key], i)
+				}
+			}
+		}
+	}
+	keys := make([]string, 0, len(origins))
+	for k := range origins {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("\nJobs:\n")
+	for _, k := range keys {
+		defs := origins[k]
+		fmt.Fprintf(&b, "  %s  <- %d (%s)", k, defs[0]+1, layers[defs[0]].Scope)
+		if len(defs) > 1 {
+			overridden := make([]string, 0, len(defs)-1)
+			for _, d := range defs[1:] {
+				overridden = append(overridden, fmt.Sprintf("%d", d+1))
+			}
+			fmt.Fprintf(&b, ", overrides %s", strings.Join(overridden, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// describeGroupFiles lists, for every group, the file its effective
+// definition comes from and the lower-precedence files it is merged over
+func describeGroupFiles(layers []config.HooksConfigLayer) string {
+	if len(layers) == 0 {
+		return "No custom hook config found\n"
+	}
+	sources := map[string][]int{}
+	for i, l := range layers {
+		for group, grp := range l.Config {
+			if grp != nil {
+				sources[group] = append(sources[group], i)
+			}
+		}
+	}
+	groups := make([]string, 0, len(sources))
+	width := 0
+	for g := range sources {
+		groups = append(groups, g)
+		width = max(width, len(g))
+	}
+	sort.Strings(groups)
+
+	var b strings.Builder
+	b.WriteString("Groups and the files they come from:\n")
+	for _, g := range groups {
+		defs := sources[g]
+		first := layers[defs[0]]
+		from := first.Scope
+		if first.Include != "" {
+			from += ", includes " + first.Include
+		}
+		fmt.Fprintf(&b, "  %-*s  %s (%s)\n", width, g, relToCwd(first.Source), from)
+		for _, d := range defs[1:] {
+			fmt.Fprintf(&b, "  %-*s    overrides %s (%s)\n", width, "", relToCwd(layers[d].Source), layers[d].Scope)
+		}
+	}
+	return b.String()
+}
+
+// includeNote names the includes pattern a layer's file was read through
+func includeNote(l config.HooksConfigLayer) string {
+	if l.Include == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (includes %s)", l.Include)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -57,13 +58,14 @@ func createBlockedAddCommand() *cli.Command {
 			}
 
 			useGlobal := cmd.Bool("global")
-			path, lc, err := loadLogConfigForBlockedURLs(useGlobal)
+			path, lc, release, err := lockLogConfigForBlockedURLs(useGlobal)
 			if err != nil {
 				return err
 			}
+			defer release()
 
 			if !addBlockedURL(lc, prefix, cmd.String("suggestion")) {
-				fmt.Println("Prefix already present; no change.")
+				output.Println("Prefix already present; no change.")
 				return nil
 			}
 
@@ -71,7 +73,7 @@ func createBlockedAddCommand() *cli.Command {
 				return err
 			}
 
-			fmt.Printf("Added blocked prefix to %s: %s\n", path, prefix)
+			output.Printf("Added blocked prefix to %s: %s\n", path, prefix)
 			return nil
 		},
 	}
@@ -91,13 +93,14 @@ func createBlockedRemoveCommand() *cli.Command {
 			}
 
 			useGlobal := cmd.Bool("global")
-			path, lc, err := loadLogConfigForBlockedURLs(useGlobal)
+			path, lc, release, err := lockLogConfigForBlockedURLs(useGlobal)
 			if err != nil {
 				return err
 			}
+			defer release()
 
 			if !removeBlockedURL(lc, prefix) {
-				fmt.Println("Prefix not found; no change.")
+				output.Println("Prefix not found; no change.")
 				return nil
 			}
 
@@ -105,7 +108,7 @@ func createBlockedRemoveCommand() *cli.Command {
 				return err
 			}
 
-			fmt.Printf("Removed blocked prefix from %s: %s\n", path, prefix)
+			output.Printf("Removed blocked prefix from %s: %s\n", path, prefix)
 			return nil
 		},
 	}
@@ -119,13 +122,14 @@ func createBlockedClearCommand() *cli.Command {
 		Flags: []cli.Flag{&cli.BoolFlag{Name: "global", Aliases: []string{"g"}}},
 		Action: func(_ context.Context, cmd *cli.Command) error {
 			useGlobal := cmd.Bool("global")
-			path, lc, err := loadLogConfigForBlockedURLs(useGlobal)
+			path, lc, release, err := lockLogConfigForBlockedURLs(useGlobal)
 			if err != nil {
 				return err
 			}
+			defer release()
 
 			if len(lc.BlockedURLs) == 0 {
-				fmt.Println("Blocked URLs already empty; no change.")
+				output.Println("Blocked URLs already empty; no change.")
 				return nil
 			}
 
@@ -134,7 +138,7 @@ func createBlockedClearCommand() *cli.Command {
 				return err
 			}
 
-			fmt.Printf("Cleared blocked URLs in %s\n", path)
+			output.Printf("Cleared blocked URLs in %s\n", path)
 			return nil
 		},
 	}
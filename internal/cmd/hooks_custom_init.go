@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -106,16 +107,44 @@ func sanitizeFileName(fileName string) (string, error) {
 	// Use filepath.Base as additional safety (should be a no-op after above checks)
 	base := filepath.Base(fileName)
 
-	// Ensure .yml or .yaml extension
-	if !strings.HasSuffix(strings.ToLower(base), ".yml") && !strings.HasSuffix(strings.ToLower(base), ".yaml") {
+	// Ensure a .yml, .yaml or .toml extension
+	switch config.HooksConfigFormat(base) {
+	case config.FormatYAML, config.FormatTOML:
+	default:
 		base += ".yml"
 	}
 
 	return base, nil
 }
 
-// writePerGroupConfig writes a per-group config file to .claude/hooks/<name>.yml.
-func writePerGroupConfig(global bool, fileName string, sample string, overwrite bool) (string, error) {
+// convertSampleConfig re-encodes the YAML sample in format, keeping its
+// leading comment lines, which are valid TOML comments too.
+func convertSampleConfig(sample, format string) (string, error) {
+	if format == config.FormatYAML {
+		return sample, nil
+	}
+	cfg, err := config.ParseHooksConfig([]byte(sample), config.FormatYAML)
+	if err != nil {
+		return "", fmt.Errorf("invalid sample yaml: %w", err)
+	}
+	data, err := config.EncodeHooksConfig(cfg, format)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode sample as %s: %w", format, err)
+	}
+	var header strings.Builder
+	for _, line := range strings.Split(sample, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	return header.String() + string(data), nil
+}
+
+// writePerGroupConfig writes a per-group config file to .claude/hooks/<name>.yml,
+// or <name>.toml when the name ends in .toml. The file is tracked as a
+// sample of config:<group> for 'config clean-samples'.
+func writePerGroupConfig(global bool, fileName, group, sample string, overwrite bool) (string, error) {
 	dir, err := config.EnsureClaudeDir(global)
 	if err != nil {
 		return "", err
@@ -133,14 +162,21 @@ func writePerGroupConfig(global bool, fileName string, sample string, overwrite
 	target := filepath.Join(hooksDir, base)
 	if !overwrite {
 		if _, err := os.Stat(target); err == nil {
-			fmt.Printf("File already exists: %s (use --overwrite to replace)\n", target)
+			output.Printf("File already exists: %s (use --overwrite to replace)\n", target)
 			return target, nil
 		}
 	}
 
-	if err := os.WriteFile(target, []byte(sample), 0o600); err != nil {
+	content, err := convertSampleConfig(sample, config.HooksConfigFormat(base))
+	if err != nil {
 		return "", err
 	}
+	if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+		return "", err
+	}
+	if err := config.RecordSampleArtifact(global, "config:"+group, target, []byte(content)); err != nil {
+		output.Printf("⚠️  Could not track %s for cleanup: %v\n", target, err)
+	}
 
 	return target, nil
 }
@@ -160,7 +196,7 @@ func writeGlobalDefaultConfig(overwrite bool) (string, error) {
 
 	// Check for existing config without overwrite
 	if !overwrite && logCfg.CustomHooks != nil && len(logCfg.CustomHooks) > 0 {
-		fmt.Printf("File already exists: %s (use --overwrite to replace)\n", configPath)
+		output.Printf("File already exists: %s (use --overwrite to replace)\n", configPath)
 		return configPath, nil
 	}
 
@@ -189,7 +225,7 @@ func newHooksCustomInitCommand() *cli.Command {
 			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Create in ~/.claude"},
 			&cli.BoolFlag{Name: "overwrite", Usage: "Overwrite existing file if present"},
 			&cli.StringFlag{Name: "group", Aliases: []string{"G"}, Value: "example", Usage: "Group name for this config"},
-			&cli.StringFlag{Name: "name", Aliases: []string{"n"}, Usage: "Filename for per-group config (writes .claude/hooks/<name>.yml)"},
+			&cli.StringFlag{Name: "name", Aliases: []string{"n"}, Usage: "Filename for per-group config (writes .claude/hooks/<name>.yml, or TOML for a .toml name)"},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
 			global := cmd.Bool("global")
@@ -205,7 +241,7 @@ func newHooksCustomInitCommand() *cli.Command {
 			// If --name provided, create .claude/hooks/<name>.yml
 			switch {
 			case fileName != "":
-				path, err = writePerGroupConfig(global, fileName, sample, overwrite)
+				path, err = writePerGroupConfig(global, fileName, group, sample, overwrite)
 				if err != nil {
 					return err
 				}
@@ -222,7 +258,7 @@ func newHooksCustomInitCommand() *cli.Command {
 				}
 			}
 
-			fmt.Printf("Created sample hooks config at %s\n", path)
+			output.Printf("Created sample hooks config at %s\n", path)
 			return nil
 		},
 	}
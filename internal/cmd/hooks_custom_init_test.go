@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 )
 
@@ -122,6 +124,8 @@ func TestSanitizeFileName_AddsExtension(t *testing.T) {
 		{"test.yaml", "test.yaml"},
 		{"test.YML", "test.YML"},   // preserves case
 		{"test.YAML", "test.YAML"}, // preserves case
+		{"rust.toml", "rust.toml"},
+		{"test.json", "test.json.yml"},
 	}
 
 	for _, tt := range tests {
@@ -182,7 +186,29 @@ func validateSanitizeSuccess(t *testing.T, fileName, got string, err error) {
 
 	// Verify the result has a proper extension (case-insensitive)
 	gotLower := strings.ToLower(got)
-	if got != "" && !strings.HasSuffix(gotLower, ".yml") && !strings.HasSuffix(gotLower, ".yaml") {
-		t.Errorf("sanitizeFileName(%q) = %q, expected .yml or .yaml extension", fileName, got)
+	if got != "" && !strings.HasSuffix(gotLower, ".yml") && !strings.HasSuffix(gotLower, ".yaml") && !strings.HasSuffix(gotLower, ".toml") {
+		t.Errorf("sanitizeFileName(%q) = %q, expected .yml, .yaml or .toml extension", fileName, got)
+	}
+}
+
+func TestConvertSampleConfig_TOML(t *testing.T) {
+	sample := generateProjectSampleConfig("demo")
+	got, err := convertSampleConfig(sample, config.FormatTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "# Sample hooks configuration for group 'demo'\n") {
+		t.Errorf("expected the sample's header comment, got:\n%s", got)
+	}
+	fromTOML, err := config.ParseHooksConfig([]byte(got), config.FormatTOML)
+	if err != nil {
+		t.Fatalf("converted sample doesn't parse: %v\n%s", err, got)
+	}
+	fromYAML, _ := config.ParseHooksConfig([]byte(sample), config.FormatYAML)
+	if !reflect.DeepEqual(fromTOML, fromYAML) {
+		t.Errorf("TOML sample differs from YAML sample:\n%s", got)
+	}
+	if same, _ := convertSampleConfig(sample, config.FormatYAML); same != sample {
+		t.Error("YAML sample should be written unchanged")
 	}
 }
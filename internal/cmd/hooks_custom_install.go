@@ -13,12 +13,13 @@ import (
 // newHooksCustomInstallCommand creates the install command for custom hooks
 func newHooksCustomInstallCommand(isValidEventType func(string) bool, validEventTypes func() []string) *cli.Command {
 	return &cli.Command{
-		Name:  "install",
-		Usage: "Install hooks from a named group defined in hooks.yml",
+		Name:          "install",
+		Usage:         "Install hooks from a named group defined in hooks.yml",
+		ShellComplete: completeArgs(hookGroupNames),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Install to global settings"},
 			&cli.StringFlag{Name: "event", Aliases: []string{"e"}, Usage: "Filter to a single event"},
-			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events (e.g., '*')"},
+			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events (e.g., '*' or 'mcp:github')"},
 			&cli.StringFlag{Name: "post-matcher", Value: "Edit,Write", Usage: "Matcher for PostToolUse when not overridden"},
 			&cli.BoolFlag{Name: "list", Usage: "List available groups"},
 			&cli.IntFlag{Name: "timeout", Aliases: []string{"t"}, Usage: "Override timeout in seconds for installed commands"},
@@ -41,10 +42,11 @@ func newHooksCustomInstallCommand(isValidEventType func(string) bool, validEvent
 				return err
 			}
 
-			settings, settingsPath, err := loadSettingsForInstall(opts.useGlobal)
+			settings, settingsPath, release, err := loadSettingsForInstall(opts.useGlobal)
 			if err != nil {
 				return err
 			}
+			defer release()
 
 			if opts.prune {
 				handlePruneGroup(settings, opts)
@@ -91,15 +93,21 @@ func parseInstallOptions(cmd *cli.Command, isValidEventType func(string) bool, v
 		}
 	}
 
+	execPath, err := resolveHookExecutable(cmd.Bool("global"), false)
+	if err != nil {
+		return installOptions{}, err
+	}
+
 	return installOptions{
 		groupName:       args[0],
 		useGlobal:       cmd.Bool("global"),
-		defaultMatcher:  cmd.String("matcher"),
-		postMatcher:     cmd.String("post-matcher"),
+		defaultMatcher:  core.ExpandMCPMatcher(cmd.String("matcher")),
+		postMatcher:     core.ExpandMCPMatcher(cmd.String("post-matcher")),
 		eventFilter:     eventFilter,
 		timeoutOverride: cmd.Int("timeout"),
 		prune:           cmd.Bool("prune"),
 		init:            cmd.Bool("init"),
+		execPath:        execPath,
 	}, nil
 }
 
@@ -113,19 +121,25 @@ func loadAndPrepareConfig(opts installOptions) (*config.CustomHooksConfig, error
 	return loadOrCreateGroup(cfg, opts.groupName, opts.init, opts.useGlobal)
 }
 
-// loadSettingsForInstall loads settings for installation
-func loadSettingsForInstall(useGlobal bool) (*config.Settings, string, error) {
-	settingsPath, err := config.GetSettingsPath(useGlobal)
+// loadSettingsForInstall locks and loads settings for installation; call
+// release once the updated settings are saved
+func loadSettingsForInstall(useGlobal bool) (settings *config.Settings, settingsPath string, release func(), err error) {
+	settingsPath, err = config.GetSettingsPath(useGlobal)
 	if err != nil {
-		return nil, "", fmt.Errorf("error getting settings path: %w", err)
+		return nil, "", nil, fmt.Errorf("error getting settings path: %w", err)
 	}
 
-	settings, err := config.LoadSettings(settingsPath)
+	release, err = config.LockFile(settingsPath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	settings, err = config.LoadSettings(settingsPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("error loading settings: %w", err)
+		release()
+		return nil, "", nil, fmt.Errorf("error loading settings: %w", err)
 	}
 
-	return settings, settingsPath, nil
+	return settings, settingsPath, release, nil
 }
 
 // handlePruneGroup prunes previously installed entries for a group
@@ -9,43 +9,111 @@ import (
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
 // newHooksCustomSyncCommand creates the sync command for custom hooks
 func newHooksCustomSyncCommand(isValidEventType func(string) bool, validEventTypes func() []string) *cli.Command {
 	return &cli.Command{
-		Name:      "sync",
-		Usage:     "Sync custom hooks from hooks.yml into Claude settings",
-		ArgsUsage: "[group]",
+		Name:          "sync",
+		Usage:         "Sync custom hooks from hooks.yml into Claude settings",
+		ArgsUsage:     "[group]",
+		ShellComplete: completeArgs(hookGroupNames),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Sync to global settings (~/.claude/settings.json)"},
 			&cli.BoolFlag{Name: "dry-run", Aliases: []string{"n"}, Usage: "Show intended changes without writing"},
 			&cli.StringFlag{Name: "event", Aliases: []string{"e"}, Usage: "Restrict sync to a single event (e.g., PreToolUse, PostToolUse)"},
-			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events (e.g., '*')"},
+			&cli.StringFlag{Name: "matcher", Aliases: []string{"m"}, Value: "*", Usage: "Default tool matcher for events (e.g., '*' or 'mcp:github')"},
 			&cli.StringFlag{Name: "post-matcher", Value: "Edit,Write", Usage: "Matcher for PostToolUse when not overridden"},
 			&cli.IntFlag{Name: "timeout", Aliases: []string{"t"}, Usage: "Override timeout in seconds for installed commands"},
+			&cli.BoolFlag{Name: "watch", Aliases: []string{"w"}, Usage: "Keep running and sync again whenever a project or global hooks config file changes"},
+			&cli.StringFlag{Name: "metrics-addr", Usage: "With --watch, serve hook run counts, errors and durations in Prometheus format at http://<addr>/metrics (loopback only, e.g. 127.0.0.1:9464)"},
+			&cli.BoolFlag{Name: "prune-only", Usage: "Only remove entries whose group or job is gone from the hooks config; add and rewrite nothing"},
+			&cli.BoolFlag{Name: "report", Usage: "List each orphaned entry (event, matcher, group, job) before syncing; combine with --dry-run to review without changes"},
 		},
-		Action: func(_ context.Context, cmd *cli.Command) error {
+		Action: func(ctx context.Context, cmd *cli.Command) error {
 			opts, err := parseSyncOptions(cmd, isValidEventType, validEventTypes)
 			if err != nil {
 				return err
 			}
+			if cmd.Bool("watch") {
+				if opts.pruneOnly || opts.report {
+					return fmt.Errorf("--prune-only and --report cannot be used with --watch\n  Suggestion: Run 'config sync --prune-only' once, then start the watcher")
+				}
+				return watchSync(ctx, opts, cmd.String("metrics-addr"))
+			}
+			if cmd.String("metrics-addr") != "" {
+				return fmt.Errorf("--metrics-addr needs --watch\n  Suggestion: Run 'blues-traveler config sync --watch --metrics-addr %s'", cmd.String("metrics-addr"))
+			}
+
+			release, err := prepareSyncWrite(opts)
+			if err != nil {
+				return err
+			}
+			defer release()
 
 			hooksCfg, settings, settingsPath, err := loadSyncDependencies(opts.useGlobal)
 			if err != nil {
 				return err
 			}
 
-			changed := performSync(settings, hooksCfg, opts)
+			if opts.report {
+				printOrphanReport(config.OrphanedEntries(settings, hooksCfg, opts.groupFilter, opts.eventFilter))
+			}
+			var changed int
+			if opts.pruneOnly {
+				changed = pruneOrphans(settings, hooksCfg, opts)
+			} else {
+				changed = performSync(settings, hooksCfg, opts)
+			}
 
 			return finalizeSyncOperation(settingsPath, settings, changed, opts)
 		},
 	}
 }
 
+// prepareSyncWrite takes the settings lock, held from load to save so a
+// concurrent install isn't lost, and checks that the prune and rewrite
+// sync does can finish. Dry runs need neither.
+func prepareSyncWrite(opts syncOptions) (func(), error) {
+	if opts.dryRun {
+		return func() {}, nil
+	}
+	settingsPath, err := config.GetSettingsPath(opts.useGlobal)
+	if err != nil {
+		return nil, err
+	}
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+	target, err := config.SettingsPreflightTarget(opts.useGlobal)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if err := config.Preflight("sync", target); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
 // parseSyncOptions extracts and validates command line options
 func parseSyncOptions(cmd *cli.Command, isValidEventType func(string) bool, validEventTypes func() []string) (syncOptions, error) {
+	opts, err := parseSyncFlags(cmd, cmd.Bool("dry-run"), isValidEventType, validEventTypes)
+	if err != nil {
+		return opts, err
+	}
+	opts.pruneOnly = cmd.Bool("prune-only")
+	opts.report = cmd.Bool("report")
+	return opts, nil
+}
+
+// parseSyncFlags reads the sync flags shared with 'config diff'. dryRun
+// also keeps the execPath symlink strategy from touching the filesystem.
+func parseSyncFlags(cmd *cli.Command, dryRun bool, isValidEventType func(string) bool, validEventTypes func() []string) (syncOptions, error) {
 	args := cmd.Args().Slice()
 	var groupFilter string
 	if len(args) > 0 {
@@ -55,7 +123,10 @@ func parseSyncOptions(cmd *cli.Command, isValidEventType func(string) bool, vali
 		groupFilter = args[0]
 	}
 
-	execPath := resolveExecutablePath()
+	execPath, err := resolveHookExecutable(cmd.Bool("global"), dryRun)
+	if err != nil {
+		return syncOptions{}, err
+	}
 	eventFilter := strings.TrimSpace(cmd.String("event"))
 
 	// Resolve Cursor alias to canonical event name first
@@ -72,17 +143,27 @@ func parseSyncOptions(cmd *cli.Command, isValidEventType func(string) bool, vali
 
 	return syncOptions{
 		useGlobal:       cmd.Bool("global"),
-		dryRun:          cmd.Bool("dry-run"),
+		dryRun:          dryRun,
 		eventFilter:     eventFilter,
 		groupFilter:     groupFilter,
-		defaultMatcher:  cmd.String("matcher"),
-		postMatcher:     cmd.String("post-matcher"),
+		defaultMatcher:  core.ExpandMCPMatcher(cmd.String("matcher")),
+		postMatcher:     core.ExpandMCPMatcher(cmd.String("post-matcher")),
 		timeoutOverride: cmd.Int("timeout"),
 		execPath:        execPath,
 	}, nil
 }
 
-// resolveExecutablePath returns a stable blues-traveler path for settings entries
+// resolveHookExecutable returns the binary reference for settings entries,
+// following the execPath strategy configured for the scope
+func resolveHookExecutable(global, dryRun bool) (string, error) {
+	strategy, err := config.ConfiguredExecPathStrategy(global)
+	if err != nil {
+		return "", fmt.Errorf("%w\n  Suggestion: Fix execPath in the config file", err)
+	}
+	return config.HookExecutable(strategy, !dryRun)
+}
+
+// resolveExecutablePath returns the running binary's path for invoking ourselves
 func resolveExecutablePath() string {
 	if p, err := os.Executable(); err == nil {
 		return p
@@ -96,6 +177,9 @@ func loadSyncDependencies(useGlobal bool) (*config.CustomHooksConfig, *config.Se
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("load hooks config: %w", err)
 	}
+	if hooksCfg != nil && (*hooksCfg)[config.BuiltinGroupName] != nil {
+		return nil, nil, "", fmt.Errorf("group name '%s' is reserved for built-in hook installs\n  Suggestion: Rename the group in your hooks config", config.BuiltinGroupName)
+	}
 
 	settingsPath, err := config.GetSettingsPath(useGlobal)
 	if err != nil {
@@ -112,53 +196,90 @@ func loadSyncDependencies(useGlobal bool) (*config.CustomHooksConfig, *config.Se
 
 // performSync executes the sync operation
 func performSync(settings *config.Settings, hooksCfg *config.CustomHooksConfig, opts syncOptions) int {
-	changed := 0
-
-	// Step 1: Clean up stale groups
-	existingGroups := config.GetConfigGroupsInSettings(settings)
-	configGroups := buildConfigGroupsMap(hooksCfg)
-	changed += cleanupStaleGroups(settings, existingGroups, configGroups, opts)
-
-	// Step 2: Sync current config groups
-	changed += syncConfigGroups(settings, hooksCfg, opts)
+	return config.SyncHooksToSettings(settings, hooksCfg, opts.configSyncOptions(), opts.printSyncChange)
+}
 
-	return changed
+// syncBuiltinGroup syncs only the built-in installs recorded under the
+// reserved group
+func syncBuiltinGroup(settings *config.Settings, opts syncOptions) int {
+	return config.SyncBuiltinGroup(settings, opts.configSyncOptions(), opts.printSyncChange)
 }
 
-// syncConfigGroups syncs all config groups to settings
-func syncConfigGroups(settings *config.Settings, hooksCfg *config.CustomHooksConfig, opts syncOptions) int {
-	changed := 0
-	if hooksCfg == nil {
-		return changed
+// configSyncOptions maps the command's options onto the sync engine's
+func (o syncOptions) configSyncOptions() config.SyncOptions {
+	return config.SyncOptions{
+		Group:       o.groupFilter,
+		Event:       o.eventFilter,
+		Matcher:     o.defaultMatcher,
+		PostMatcher: o.postMatcher,
+		Timeout:     o.timeoutOverride,
+		ExecPath:    o.execPath,
+		Global:      o.useGlobal,
 	}
+}
 
-	for groupName, group := range *hooksCfg {
-		if shouldSkipGroup(groupName, opts.groupFilter) {
-			continue
+// printSyncChange reports a sync step; quiet keeps all but skipped steps
+// to the caller's own summary
+func (o syncOptions) printSyncChange(c config.SyncChange) {
+	if c.Kind == config.SyncSkipped {
+		output.Printf("⚠️  Skipping built-in hooks: %v\n", c.Err)
+		return
+	}
+	if o.quiet {
+		return
+	}
+	switch c.Kind {
+	case config.SyncAdded:
+		if o.dryRun {
+			output.Printf("Would add: [%s] matcher=%q command=%q\n", c.Event, c.Matcher, c.Command)
 		}
+	case config.SyncPruned:
+		printPrunedMessage(c.Count, c.Group, c.Event)
+	case config.SyncCleaned:
+		printCleanupMessage(c.Count, c.Group, c.Event)
+	}
+}
 
-		// Prune existing settings for this group
-		removed := config.RemoveConfigGroupFromSettings(settings, groupName, opts.eventFilter)
-		if removed > 0 {
-			printPrunedMessage(removed, groupName, opts.eventFilter)
+// pruneOrphans removes the config group entries whose job is no longer in
+// the hooks config for their event, leaving everything else as it is
+func pruneOrphans(settings *config.Settings, hooksCfg *config.CustomHooksConfig, opts syncOptions) int {
+	orphans := config.OrphanedEntries(settings, hooksCfg, opts.groupFilter, opts.eventFilter)
+	if opts.dryRun && !opts.quiet {
+		for _, o := range orphans {
+			output.Printf("Would remove: [%s] matcher=%q command=%q\n", o.Event, o.Matcher, o.Command)
 		}
-
-		// Add current definitions
-		changed += syncGroupToSettings(settings, groupName, group, opts)
 	}
+	return config.RemoveOrphanedEntries(settings, orphans)
+}
 
-	return changed
+// printOrphanReport lists orphaned config group entries for review
+func printOrphanReport(orphans []config.OrphanedEntry) {
+	if len(orphans) == 0 {
+		output.Println("No orphaned entries.")
+		output.Println()
+		return
+	}
+	output.Printf("Orphaned entries (%d), no longer defined in the hooks config:\n", len(orphans))
+	for _, o := range orphans {
+		matcher := o.Matcher
+		if matcher == "" {
+			matcher = "*"
+		}
+		output.Printf("  [%s] matcher=%s group=%s job=%s\n", o.Event, matcher, o.Group, o.Job)
+		output.Printf("      %s\n", o.Command)
+	}
+	output.Println()
 }
 
 // finalizeSyncOperation handles final output and saving
 func finalizeSyncOperation(settingsPath string, settings *config.Settings, changed int, opts syncOptions) error {
 	if changed == 0 {
-		fmt.Println("No changes detected.")
+		output.Println("No changes detected.")
 		return nil
 	}
 
 	if opts.dryRun {
-		fmt.Println("Dry run; not writing settings.")
+		output.Println("Dry run; not writing settings.")
 		return nil
 	}
 
@@ -170,6 +291,10 @@ func finalizeSyncOperation(settingsPath string, settings *config.Settings, chang
 	if opts.useGlobal {
 		scope = constants.ScopeGlobal
 	}
-	fmt.Printf("Synced %d entries into %s settings: %s\n", changed, scope, settingsPath)
+	if opts.pruneOnly {
+		output.Printf("Pruned %d orphaned entries from %s settings: %s\n", changed, scope, settingsPath)
+		return nil
+	}
+	output.Printf("Synced %d entries into %s settings: %s\n", changed, scope, settingsPath)
 	return nil
 }
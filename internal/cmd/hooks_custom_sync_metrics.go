@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Outcomes of a watch cycle, counted for the metrics endpoint
+const (
+	syncCycleSynced    = "synced"
+	syncCycleUnchanged = "unchanged"
+	syncCycleFailed    = "error"
+)
+
+// watchMetrics holds what a watcher exposes on its metrics endpoint: the
+// recorded hook runs plus its own sync cycles
+type watchMetrics struct {
+	started   time.Time
+	runs      *core.HookRunCollector
+	synced    atomic.Int64
+	unchanged atomic.Int64
+	failed    atomic.Int64
+}
+
+func newWatchMetrics() *watchMetrics {
+	return &watchMetrics{started: time.Now(), runs: core.NewHookRunCollector()}
+}
+
+// countCycle notes the outcome of one sync cycle
+func (m *watchMetrics) countCycle(outcome string) {
+	if m == nil {
+		return
+	}
+	switch outcome {
+	case syncCycleSynced:
+		m.synced.Add(1)
+	case syncCycleUnchanged:
+		m.unchanged.Add(1)
+	case syncCycleFailed:
+		m.failed.Add(1)
+	}
+}
+
+// write reads runs recorded since the last scrape and writes every metric
+func (m *watchMetrics) write(w io.Writer) error {
+	if err := m.runs.Collect(); err != nil {
+		return err
+	}
+	if err := m.runs.WritePrometheus(w); err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("# HELP blues_traveler_sync_cycles_total Sync cycles run by the watcher, by result.\n")
+	b.WriteString("# TYPE blues_traveler_sync_cycles_total counter\n")
+	fmt.Fprintf(&b, "blues_traveler_sync_cycles_total{result=%q} %d\n", syncCycleSynced, m.synced.Load())
+	fmt.Fprintf(&b, "blues_traveler_sync_cycles_total{result=%q} %d\n", syncCycleUnchanged, m.unchanged.Load())
+	fmt.Fprintf(&b, "blues_traveler_sync_cycles_total{result=%q} %d\n", syncCycleFailed, m.failed.Load())
+	b.WriteString("# HELP blues_traveler_start_time_seconds When the watcher started, in Unix seconds.\n")
+	b.WriteString("# TYPE blues_traveler_start_time_seconds gauge\n")
+	fmt.Fprintf(&b, "blues_traveler_start_time_seconds %d\n", m.started.Unix())
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// serveWatchMetrics serves m at /metrics on addr until ctx is cancelled and
+// returns the address it listens on. Only loopback addresses are accepted:
+// the endpoint has no authentication and names the project's hooks.
+func serveWatchMetrics(ctx context.Context, addr string, m *watchMetrics) (string, error) {
+	if err := checkLoopbackAddr(addr); err != nil {
+		return "", err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w\n  Suggestion: Pick a free port, e.g. --metrics-addr 127.0.0.1:9464", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		var b strings.Builder
+		if err := m.write(&b); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = io.WriteString(w, b.String())
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	return ln.Addr().String(), nil
+}
+
+// checkLoopbackAddr rejects listen addresses reachable from other machines
+func checkLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid --metrics-addr '%s': %w\n  Suggestion: Use host:port, e.g. 127.0.0.1:9464", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("--metrics-addr '%s' is not a loopback address\n  Suggestion: The endpoint is unauthenticated; bind it to 127.0.0.1 or localhost, e.g. 127.0.0.1:9464", addr)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckLoopbackAddr(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:9464": true,
+		"localhost:9464": true,
+		"[::1]:9464":     true,
+		":9464":          false,
+		"0.0.0.0:9464":   false,
+		"10.0.0.5:9464":  false,
+		"9464":           false,
+	} {
+		if err := checkLoopbackAddr(addr); (err == nil) != ok {
+			t.Errorf("checkLoopbackAddr(%q) = %v, want ok=%v", addr, err, ok)
+		}
+	}
+}
+
+func TestServeWatchMetrics(t *testing.T) {
+	t.Setenv("BT_METRICS_DIR", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := newWatchMetrics()
+	m.countCycle(syncCycleSynced)
+	m.countCycle(syncCycleFailed)
+	m.countCycle(syncCycleFailed)
+	addr, err := serveWatchMetrics(ctx, "127.0.0.1:0", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		`blues_traveler_sync_cycles_total{result="synced"} 1`,
+		`blues_traveler_sync_cycles_total{result="error"} 2`,
+		"# TYPE blues_traveler_hook_duration_seconds histogram",
+		"blues_traveler_start_time_seconds ",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
 )
 
 func TestParseSyncOptions_EventValidation(t *testing.T) {
@@ -102,3 +106,43 @@ func TestEventValidation_ListsValidEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncBuiltinGroup(t *testing.T) {
+	t.Chdir(t.TempDir())
+	security := config.BuiltinInstall{Hook: "security", Event: "PreToolUse", Matcher: "Bash", Command: "blues-traveler hooks run security"}
+	format := config.BuiltinInstall{Hook: "format", Event: "PostToolUse", Matcher: "Edit", Command: "blues-traveler hooks run format"}
+
+	settings := &config.Settings{}
+	config.AddHookToSettings(settings, "PreToolUse", "Bash", security.Command, nil)
+	config.AddHookToSettings(settings, "PreToolUse", "*", "blues-traveler hooks run debug", nil)
+	config.AddHookToSettings(settings, "PreToolUse", "*", "blues-traveler hooks run config:go:vet", nil)
+
+	// Untracked scopes are left alone
+	if changed := syncBuiltinGroup(settings, syncOptions{}); changed != 0 {
+		t.Fatalf("expected no changes without a manifest, got %d", changed)
+	}
+
+	path, err := config.BuiltinManifestPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveBuiltinManifest(path, &config.BuiltinManifest{Hooks: []config.BuiltinInstall{security, format}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// format is restored, debug is pruned, security and the config group stay
+	if changed := syncBuiltinGroup(settings, syncOptions{}); changed != 2 {
+		t.Fatalf("expected 2 changes, got %d", changed)
+	}
+	var types []string
+	for _, h := range config.InstalledHooks(settings.Hooks) {
+		types = append(types, h.HookType)
+	}
+	sort.Strings(types)
+	if got := strings.Join(types, ","); got != "config:go:vet,format,security" {
+		t.Fatalf("unexpected hooks after sync: %s", got)
+	}
+	if changed := syncBuiltinGroup(settings, syncOptions{}); changed != 0 {
+		t.Fatalf("expected second sync to be a no-op, got %d", changed)
+	}
+}
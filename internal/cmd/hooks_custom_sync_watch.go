@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// syncWatchDebounce collapses the burst of events one editor save produces
+const syncWatchDebounce = 300 * time.Millisecond
+
+// watchSync syncs once, then again each time a hooks config file in the
+// project, global or inherited .claude directories changes, until ctx is
+// cancelled. Failed cycles are reported and watching goes on, since a
+// half-edited file is expected while tuning. A non-empty metricsAddr serves
+// hook run and sync metrics there while watching.
+func watchSync(ctx context.Context, opts syncOptions, metricsAddr string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	opts.quiet = true
+	watched := map[string]bool{}
+	watchSyncDirs(watcher, watched)
+	if len(watched) == 0 {
+		return fmt.Errorf("no .claude directories to watch\n  Suggestion: Run 'blues-traveler hooks custom init' to create a hooks config first")
+	}
+	var metrics *watchMetrics
+	if metricsAddr != "" {
+		metrics = newWatchMetrics()
+		addr, err := serveWatchMetrics(ctx, metricsAddr, metrics)
+		if err != nil {
+			return err
+		}
+		output.Printf("📈 Serving metrics at http://%s/metrics\n", addr)
+		if !config.GetMetricsEnabled() {
+			output.Println("⚠️  Hook runs aren't recorded. Set \"metrics\": true in blues-traveler-config.json for per-hook metrics.")
+		}
+	}
+	output.Printf("👀 Watching hooks config in %s (Ctrl+C to stop)\n", strings.Join(sortedKeys(watched), ", "))
+	metrics.countCycle(syncWatchCycle(opts, nil))
+
+	timer := time.NewTimer(syncWatchDebounce)
+	timer.Stop()
+	pending := map[string]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			output.Println("Stopped watching.")
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Has(fsnotify.Chmod) || !config.IsHooksConfigFileName(filepath.Base(ev.Name)) {
+				// A new hooks/ directory holds files worth watching too
+				if ev.Has(fsnotify.Create) && filepath.Base(ev.Name) == constants.HooksSubDir {
+					watchSyncDirs(watcher, watched)
+				}
+				continue
+			}
+			pending[ev.Name] = true
+			timer.Reset(syncWatchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			output.Printf("⚠️  Watcher error: %v\n", err)
+		case <-timer.C:
+			changed := sortedKeys(pending)
+			pending = map[string]bool{}
+			metrics.countCycle(syncWatchCycle(opts, changed))
+			// extendsPath may now point somewhere new
+			watchSyncDirs(watcher, watched)
+		}
+	}
+}
+
+// watchSyncDirs adds the directories hooks config is read from that exist
+// and are not yet watched. Directories are watched rather than files so
+// editors that save by renaming a temp file are still seen.
+func watchSyncDirs(watcher *fsnotify.Watcher, watched map[string]bool) {
+	var dirs []string
+	for _, global := range []bool{false, true} {
+		if dir, err := config.ClaudeDirFor(global); err == nil {
+			dirs = append(dirs, dir, filepath.Join(dir, constants.HooksSubDir))
+		}
+	}
+	if layers, err := config.LoadHooksConfigLayers(); err == nil {
+		for _, l := range layers {
+			dirs = append(dirs, filepath.Dir(l.Source))
+		}
+	}
+	for _, dir := range dirs {
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err == nil {
+			watched[dir] = true
+		}
+	}
+}
+
+// syncWatchCycle runs one sync and prints a one-line summary per group it
+// changed. changed lists the files that triggered the cycle. It returns the
+// cycle's outcome.
+func syncWatchCycle(opts syncOptions, changed []string) string {
+	stamp := time.Now().Format("15:04:05")
+	trigger := "initial sync"
+	if len(changed) > 0 {
+		names := make([]string, len(changed))
+		for i, f := range changed {
+			names[i] = filepath.Base(f)
+		}
+		trigger = strings.Join(names, ", ") + " changed"
+	}
+
+	diff, settingsPath, err := syncWithSummary(opts)
+	if err != nil {
+		output.Printf("[%s] %s: ❌ %v\n", stamp, trigger, err)
+		return syncCycleFailed
+	}
+	if diff == nil {
+		output.Printf("[%s] %s: settings already up to date\n", stamp, trigger)
+		return syncCycleUnchanged
+	}
+	verb := "synced"
+	if opts.dryRun {
+		verb = "would sync"
+	}
+	if len(diff.Groups) == 0 {
+		// Same entries; only timeouts or ordering differ
+		output.Printf("[%s] %s: %s %s (entries updated in place)\n", stamp, trigger, verb, settingsPath)
+		return syncCycleSynced
+	}
+	output.Printf("[%s] %s: %s %s\n", stamp, trigger, verb, settingsPath)
+	for _, g := range diff.Groups {
+		output.Printf("  %-20s +%d -%d\n", g.Group, g.Added, g.Removed)
+	}
+	return syncCycleSynced
+}
+
+// syncWithSummary syncs once and returns how settings changed; nil means
+// they already matched
+func syncWithSummary(opts syncOptions) (*settingsDiff, string, error) {
+	release, err := prepareSyncWrite(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	hooksCfg, settings, settingsPath, err := loadSyncDependencies(opts.useGlobal)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := config.ValidateHooksConfig(hooksCfg); err != nil {
+		return nil, settingsPath, err
+	}
+	before, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return nil, settingsPath, err
+	}
+	if performSync(settings, hooksCfg, opts) == 0 {
+		return nil, settingsPath, nil
+	}
+	diff, err := diffSettingsHooks(settingsPath, before, settings)
+	if err != nil || diff == nil || opts.dryRun {
+		return diff, settingsPath, err
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return nil, settingsPath, err
+	}
+	return diff, settingsPath, nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestWatchSyncResyncsOnHooksConfigChange(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	opts := syncOptions{defaultMatcher: "*", postMatcher: "Edit,Write", execPath: "blues-traveler"}
+	go func() { done <- watchSync(ctx, opts, "") }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("watchSync: %v", err)
+		}
+	}()
+
+	settingsPath, err := config.GetSettingsPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Give the watcher time to start before the write it should see
+	time.Sleep(200 * time.Millisecond)
+	hooks := "tuning:\n  PreToolUse:\n    jobs:\n      - name: lint\n        run: echo lint\n"
+	if err := os.WriteFile(filepath.Join(".claude", "hooks.yml"), []byte(hooks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(settingsPath); err == nil && strings.Contains(string(data), "config:tuning:lint") {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("settings were not synced after hooks.yml changed")
+}
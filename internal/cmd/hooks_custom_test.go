@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestExplainHooksConfig(t *testing.T) {
+	job := func(name string) *config.EventConfig {
+		return &config.EventConfig{Jobs: []config.HookJob{{Name: name, Run: "true"}}}
+	}
+	layers := []config.HooksConfigLayer{
+		{Scope: config.LayerScopeProject, Source: "svc/.claude/hooks/hooks.yml", Config: config.CustomHooksConfig{"go": {Events: map[string]*config.EventConfig{"PostToolUse": job("vet")}}}},
+		{Scope: config.LayerScopeParent, Source: ".claude/hooks/hooks.yml", Config: config.CustomHooksConfig{"go": {Events: map[string]*config.EventConfig{"PostToolUse": job("vet"), "Stop": job("report")}}}},
+	}
+
+	out := explainHooksConfig(layers)
+	for _, want := range []string{
+		"1. project svc/.claude/hooks/hooks.yml",
+		"2. parent  .claude/hooks/hooks.yml",
+		"go/PostToolUse/vet  <- 1 (project), overrides 2",
+		"go/Stop/report  <- 2 (parent)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explain output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDescribeGroupFiles(t *testing.T) {
+	job := func(name string) *config.EventConfig {
+		return &config.EventConfig{Jobs: []config.HookJob{{Name: name, Run: "true"}}}
+	}
+	layers := []config.HooksConfigLayer{
+		{Scope: config.LayerScopeProject, Source: ".claude/hooks/hooks-local.yml", Config: config.CustomHooksConfig{"go": {Events: map[string]*config.EventConfig{"Stop": job("vet")}}}},
+		{Scope: config.LayerScopeProject, Source: ".claude/hooks/go.yml", Include: "hooks/*.yml", Config: config.CustomHooksConfig{"go": {Events: map[string]*config.EventConfig{"Stop": job("vet")}}}},
+		{Scope: config.LayerScopeProject, Source: ".claude/hooks/python.yml", Include: "hooks/*.yml", Config: config.CustomHooksConfig{"python": {Events: map[string]*config.EventConfig{"Stop": job("ruff")}}}},
+	}
+
+	out := describeGroupFiles(layers)
+	for _, want := range []string{
+		"go      .claude/hooks/hooks-local.yml (project)\n",
+		"overrides .claude/hooks/go.yml (project)\n",
+		"python  .claude/hooks/python.yml (project, includes hooks/*.yml)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("files output missing %q:\n%s", want, out)
+		}
+	}
+	if got := describeGroupFiles(nil); !strings.Contains(got, "No custom hook config") {
+		t.Errorf("describeGroupFiles(nil) = %q", got)
+	}
+}
+
+func TestGroupLabel(t *testing.T) {
+	cfg := config.CustomHooksConfig{
+		"infra": &config.HookGroup{Description: "Deploy checks"},
+		"lint":  &config.HookGroup{},
+	}
+	if got := groupLabel(&cfg, "infra"); got != "infra - Deploy checks" {
+		t.Errorf("groupLabel(infra) = %q", got)
+	}
+	if got := groupLabel(&cfg, "lint"); got != "lint" {
+		t.Errorf("groupLabel(lint) = %q", got)
+	}
+	if got := groupLabel(nil, "lint"); got != "lint" {
+		t.Errorf("groupLabel with no config = %q", got)
+	}
+}
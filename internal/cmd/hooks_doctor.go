@@ -2,22 +2,38 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
 // NewDoctorCommand creates the doctor command for diagnosing hook installation
 func NewDoctorCommand() *cli.Command {
 	return &cli.Command{
-		Name:        "doctor",
-		Usage:       "Diagnose hooks installation and configuration",
-		Description: `Check the health of your hooks installation, showing what's configured, where, and any potential issues.`,
+		Name:  "doctor",
+		Usage: "Diagnose hooks installation and configuration",
+		Description: `Check the health of your hooks installation, showing what's configured, where, and any potential issues.
+
+With --fix, broken entries in the project and global settings.json are
+repaired: blues-traveler commands whose binary no longer exists are
+repointed at the current one, repeated matchers and commands are merged,
+config:<group>:<job> commands for groups missing from every hooks config are
+removed, and hooks keys that aren't event names are moved to the event they
+alias (or removed). Other tools' missing executables are reported for a
+manual fix. Add --dry-run to preview the report without writing.
+
+'doctor settings' lints settings.json for entries that load but misbehave:
+shadowed matchers, commands repeated across events, stale binaries and
+timeouts over the Claude Code limit.`,
+		Commands: []*cli.Command{
+			newDoctorSettingsCommand(),
+		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "verbose",
@@ -25,8 +41,39 @@ func NewDoctorCommand() *cli.Command {
 				Value:   false,
 				Usage:   "Show detailed configuration information",
 			},
+			&cli.BoolFlag{
+				Name:  "compat",
+				Value: false,
+				Usage: "Check the cchooks library and recent event payloads for schema mismatches",
+			},
+			&cli.BoolFlag{
+				Name:  "clear",
+				Value: false,
+				Usage: "With --compat, forget recorded payload mismatches",
+			},
+			&cli.BoolFlag{
+				Name:  "fix",
+				Value: false,
+				Usage: "Repair broken settings entries: stale binary paths, duplicate matchers, missing config groups and invalid event names",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Value: false,
+				Usage: "With --fix, report the repairs without writing settings",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Value: false,
+				Usage: "With --fix, print the repair report as JSON",
+			},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("compat") {
+				return runCompatCheck(cmd.Bool("clear"))
+			}
+			if cmd.Bool("fix") || cmd.Bool("dry-run") {
+				return runDoctorFix(cmd.Bool("dry-run"), cmd.Bool("json"))
+			}
 			verbose := cmd.Bool("verbose")
 			return runDoctorCheck(verbose)
 		},
@@ -35,31 +82,31 @@ func NewDoctorCommand() *cli.Command {
 
 // runDoctorCheck performs the diagnosis of the hooks system
 func runDoctorCheck(verbose bool) error {
-	fmt.Println("🔍 Blues Traveler Hooks Doctor")
-	fmt.Println("=" + strings.Repeat("=", 50))
-	fmt.Println()
+	output.Println("🔍 Blues Traveler Hooks Doctor")
+	output.Println("=" + strings.Repeat("=", 50))
+	output.Println()
 
 	// Check project settings
-	fmt.Println("📁 Project Settings")
-	fmt.Println(strings.Repeat("-", 52))
+	output.Println("📁 Project Settings")
+	output.Println(strings.Repeat("-", 52))
 	checkProjectSettings(verbose)
-	fmt.Println()
+	output.Println()
 
 	// Check global settings
-	fmt.Println("🌍 Global Settings")
-	fmt.Println(strings.Repeat("-", 52))
+	output.Println("🌍 Global Settings")
+	output.Println(strings.Repeat("-", 52))
 	checkGlobalSettings(verbose)
-	fmt.Println()
+	output.Println()
 
 	// Check custom hooks configuration
-	fmt.Println("⚙️  Custom Hooks Configuration")
-	fmt.Println(strings.Repeat("-", 52))
+	output.Println("⚙️  Custom Hooks Configuration")
+	output.Println(strings.Repeat("-", 52))
 	checkCustomHooksConfig(verbose)
-	fmt.Println()
+	output.Println()
 
 	// Summary and recommendations
-	fmt.Println("📋 Summary")
-	fmt.Println(strings.Repeat("-", 52))
+	output.Println("📋 Summary")
+	output.Println(strings.Repeat("-", 52))
 	printSummary()
 
 	return nil
@@ -79,34 +126,57 @@ func checkGlobalSettings(verbose bool) {
 func checkSettings(isGlobal bool, verbose bool, scope string, installCmd string) {
 	settingsPath, err := config.GetSettingsPath(isGlobal)
 	if err != nil {
-		fmt.Printf("⚠️  Error getting %s settings path: %v\n", scope, err)
+		output.Printf("⚠️  Error getting %s settings path: %v\n", scope, err)
 		return
 	}
 
-	fmt.Printf("Location: %s\n", settingsPath)
+	output.Printf("Location: %s\n", settingsPath)
 
 	// Check if file exists first, since LoadSettings returns empty settings for missing files
 	if _, err := os.Stat(settingsPath); err != nil {
 		if os.IsNotExist(err) {
-			fmt.Printf("Status: ✗ No %s settings file found\n", scope)
-			fmt.Printf("        Use '%s' to create %s settings\n", installCmd, scope)
+			output.Printf("Status: ✗ No %s settings file found\n", scope)
+			output.Printf("        Use '%s' to create %s settings\n", installCmd, scope)
 		} else {
-			fmt.Printf("Status: ⚠️  Error checking settings file: %v\n", err)
+			output.Printf("Status: ⚠️  Error checking settings file: %v\n", err)
 		}
 		return
 	}
 
 	settings, err := config.LoadSettings(settingsPath)
 	if err != nil {
-		fmt.Printf("Status: ⚠️  Error loading settings: %v\n", err)
+		output.Printf("Status: ⚠️  Error loading settings: %v\n", err)
 		return
 	}
 
 	if config.IsHooksConfigEmpty(settings.Hooks) {
-		fmt.Println("Status: ✓ Settings file exists, but no hooks installed")
+		output.Println("Status: ✓ Settings file exists, but no hooks installed")
 	} else {
-		fmt.Println("Status: ✓ Hooks configured")
+		output.Println("Status: ✓ Hooks configured")
 		printHooksSummary(settings.Hooks, verbose)
+		checkInstalledManifests(settings.Hooks)
+	}
+
+	if legacy := config.CountLegacyCommands(settings); legacy > 0 {
+		output.Printf("⚠️  %d hook command(s) use the deprecated 'blues-traveler run' form\n", legacy)
+		output.Println("        They still work, and are rewritten to 'hooks run' on the next settings save")
+	}
+}
+
+// checkInstalledManifests warns about installed hooks that don't exist or
+// that are installed for an event their manifest doesn't handle
+func checkInstalledManifests(hooks config.HooksConfig) {
+	for _, inst := range config.InstalledHooks(hooks) {
+		hook, err := core.CreateHook(inst.HookType)
+		if err != nil {
+			output.Printf("⚠️  '%s' (%s) is not a known hook; remove it or define the job it names\n", inst.HookType, inst.Event)
+			continue
+		}
+		m := hook.Manifest()
+		if !m.HandlesEvent(inst.Event) {
+			output.Printf("⚠️  '%s' is installed for %s but only handles %s; it does nothing there\n", inst.HookType, inst.Event, strings.Join(m.Events, ", "))
+			output.Printf("        Reinstall with 'hooks install %s --event %s'\n", inst.HookType, m.DefaultEvent)
+		}
 	}
 }
 
@@ -114,13 +184,13 @@ func checkSettings(isGlobal bool, verbose bool, scope string, installCmd string)
 func checkCustomHooksConfig(verbose bool) {
 	cfg, err := config.LoadHooksConfig()
 	if err != nil {
-		fmt.Printf("⚠️  Error loading hooks config: %v\n", err)
+		output.Printf("⚠️  Error loading hooks config: %v\n", err)
 		return
 	}
 
 	foundFiles, err := findExistingConfigFiles()
 	if err != nil {
-		fmt.Printf("⚠️  Error getting config paths: %v\n", err)
+		output.Printf("⚠️  Error getting config paths: %v\n", err)
 		return
 	}
 
@@ -129,8 +199,8 @@ func checkCustomHooksConfig(verbose bool) {
 		return
 	}
 
-	fmt.Printf("Status: ✓ Found %d configuration file(s)\n", len(foundFiles))
-	fmt.Println()
+	output.Printf("Status: ✓ Found %d configuration file(s)\n", len(foundFiles))
+	output.Println()
 
 	if verbose {
 		printConfigFiles(foundFiles)
@@ -138,12 +208,12 @@ func checkCustomHooksConfig(verbose bool) {
 
 	// Validate and show groups
 	if cfg == nil || len(*cfg) == 0 {
-		fmt.Println("⚠️  Configuration files exist but no groups defined")
+		output.Println("⚠️  Configuration files exist but no groups defined")
 		return
 	}
 
 	groups := config.ListHookGroups(cfg)
-	fmt.Printf("Groups: %d defined\n", len(groups))
+	output.Printf("Groups: %d defined\n", len(groups))
 
 	if verbose {
 		printGroupDetails(cfg, groups)
@@ -151,9 +221,9 @@ func checkCustomHooksConfig(verbose bool) {
 
 	// Validate configuration
 	if err := config.ValidateHooksConfig(cfg); err != nil {
-		fmt.Printf("⚠️  Configuration validation failed: %v\n", err)
+		output.Printf("⚠️  Configuration validation failed: %v\n", err)
 	} else {
-		fmt.Println("✓ Configuration is valid")
+		output.Println("✓ Configuration is valid")
 	}
 }
 
@@ -165,11 +235,11 @@ func printHooksSummary(hooks config.HooksConfig, verbose bool) {
 		totalHooks += count
 	}
 
-	fmt.Printf("        %d hook(s) installed across %d event type(s)\n", totalHooks, len(events))
+	output.Printf("        %d hook(s) installed across %d event type(s)\n", totalHooks, len(events))
 
 	if verbose && totalHooks > 0 {
-		fmt.Println()
-		fmt.Println("        Event breakdown:")
+		output.Println()
+		output.Println("        Event breakdown:")
 
 		// Sort events for consistent display
 		eventNames := make([]string, 0, len(events))
@@ -180,7 +250,7 @@ func printHooksSummary(hooks config.HooksConfig, verbose bool) {
 
 		for _, name := range eventNames {
 			count := events[name]
-			fmt.Printf("          • %s: %d hook(s)\n", name, count)
+			output.Printf("          • %s: %d hook(s)\n", name, count)
 		}
 	}
 }
@@ -218,15 +288,21 @@ func countEventHooks(events map[string]int, eventName string, matchers []config.
 
 // printSummary prints overall summary and recommendations
 func printSummary() {
-	fmt.Println("The hooks system has been checked.")
-	fmt.Println()
-	fmt.Println("Next steps:")
-	fmt.Println("  • View available plugins: blues-traveler hooks list")
-	fmt.Println("  • View installed hooks: blues-traveler hooks list --installed")
-	fmt.Println("  • Install a hook: blues-traveler hooks install <plugin-key>")
-	fmt.Println("  • Create custom hooks: blues-traveler hooks custom init <group-name>")
-	fmt.Println()
-	fmt.Println("For more verbose output, run: blues-traveler doctor --verbose")
+	output.Println("The hooks system has been checked.")
+	if path, ok := config.ActiveKillSwitch(); ok {
+		output.Printf("⚠️  Kill switch active: every hook allows without running until %s is removed\n", path)
+	}
+	if link, broken := config.ExecSymlinkBroken(); broken {
+		output.Printf("⚠️  %s points to a missing binary; run 'blues-traveler hooks custom sync' or reinstall a hook to repoint it\n", link)
+	}
+	output.Println()
+	output.Println("Next steps:")
+	output.Println("  • View available plugins: blues-traveler hooks list")
+	output.Println("  • View installed hooks: blues-traveler hooks list --installed")
+	output.Println("  • Install a hook: blues-traveler hooks install <plugin-key>")
+	output.Println("  • Create custom hooks: blues-traveler hooks custom init <group-name>")
+	output.Println()
+	output.Println("For more verbose output, run: blues-traveler doctor --verbose")
 }
 
 // getCandidateConfigPaths returns all potential hook config file locations
@@ -249,87 +325,91 @@ func findExistingConfigFiles() ([]string, error) {
 
 // printNoConfigFilesFound displays message when no config files are found
 func printNoConfigFilesFound() {
-	fmt.Println("Status: ✗ No custom hooks configuration files found")
-	fmt.Println()
-	fmt.Println("Searched locations:")
-	fmt.Println("  Project: .claude/hooks/hooks.yml")
-	fmt.Println("           .claude/hooks.yml")
-	fmt.Println("           .claude/hooks/*.yml")
-	fmt.Println("  Global:  ~/.claude/hooks/hooks.yml")
-	fmt.Println("           ~/.claude/hooks.yml")
-	fmt.Println("           ~/.claude/hooks/*.yml")
-	fmt.Println()
-	fmt.Println("To create: Use 'hooks custom init <group-name>' to get started")
+	output.Println("Status: ✗ No custom hooks configuration files found")
+	output.Println()
+	output.Println("Searched locations:")
+	output.Println("  Project: .claude/hooks/hooks.yml")
+	output.Println("           .claude/hooks.yml")
+	output.Println("           .claude/hooks/*.yml")
+	output.Println("  Global:  ~/.claude/hooks/hooks.yml")
+	output.Println("           ~/.claude/hooks.yml")
+	output.Println("           ~/.claude/hooks/*.yml")
+	output.Println()
+	output.Println("To create: Use 'hooks custom init <group-name>' to get started")
 }
 
 // printConfigFiles displays list of found config files with scope
 func printConfigFiles(foundFiles []string) {
-	fmt.Println("Configuration files (in merge order):")
-	home, _ := os.UserHomeDir()
-	globalPrefix := ""
-	if home != "" {
-		globalPrefix = filepath.Join(home, ".claude")
-	}
+	output.Println("Configuration files (in merge order):")
+	globalPrefix, _ := config.GlobalClaudeDir()
 
 	for _, f := range foundFiles {
 		scope := "project"
 		if globalPrefix != "" && strings.HasPrefix(f, globalPrefix) {
 			scope = "global"
 		}
-		fmt.Printf("  • %s (%s)\n", f, scope)
+		output.Printf("  • %s (%s)\n", f, scope)
 	}
-	fmt.Println()
+	output.Println()
 }
 
 // printGroupDetails displays details about hook groups
 func printGroupDetails(cfg *config.CustomHooksConfig, groups []string) {
 	for _, groupName := range groups {
 		group := (*cfg)[groupName]
-		eventCount := len(group)
+		if group == nil {
+			continue
+		}
+		eventCount := len(group.Events)
 		jobCount := 0
-		for _, ev := range group {
+		for _, ev := range group.Events {
 			jobCount += len(ev.Jobs)
 		}
-		fmt.Printf("  • %s (%d events, %d jobs)\n", groupName, eventCount, jobCount)
+		output.Printf("  • %s (%d events, %d jobs)\n", groupName, eventCount, jobCount)
+		if group.Description != "" {
+			output.Printf("    %s\n", group.Description)
+		}
 	}
-	fmt.Println()
+	output.Println()
 }
 
 func getCandidateConfigPaths() ([]string, error) {
 	var paths []string
 
 	// Project scope
-	cwd, err := os.Getwd()
+	proj, err := config.ProjectClaudeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %w", err)
+		return nil, err
 	}
-	proj := filepath.Join(cwd, ".claude")
 
 	// Main hooks config files
 	paths = append(paths,
 		filepath.Join(proj, "hooks", "hooks.yml"),
 		filepath.Join(proj, "hooks", "hooks.yaml"),
+		filepath.Join(proj, "hooks", "hooks.toml"),
 		filepath.Join(proj, "hooks.yml"),
 		filepath.Join(proj, "hooks.yaml"),
 		filepath.Join(proj, "hooks.json"),
+		filepath.Join(proj, "hooks.toml"),
 	)
 
 	// Global scope
-	home, err := os.UserHomeDir()
+	glob, err := config.GlobalClaudeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
-	glob := filepath.Join(home, ".claude")
 
 	paths = append(paths,
 		filepath.Join(glob, "hooks", "hooks.yml"),
 		filepath.Join(glob, "hooks", "hooks.yaml"),
+		filepath.Join(glob, "hooks", "hooks.toml"),
 		filepath.Join(glob, "hooks.yml"),
 		filepath.Join(glob, "hooks.yaml"),
 		filepath.Join(glob, "hooks.json"),
+		filepath.Join(glob, "hooks.toml"),
 	)
 
-	// Enumerate all *.yml and *.yaml files in project hooks directory
+	// Enumerate all *.yml, *.yaml and *.toml files in project hooks directory
 	projHooksDir := filepath.Join(proj, "hooks")
 	if projYmls, err := filepath.Glob(filepath.Join(projHooksDir, "*.yml")); err == nil {
 		paths = append(paths, projYmls...)
@@ -337,8 +417,11 @@ func getCandidateConfigPaths() ([]string, error) {
 	if projYamls, err := filepath.Glob(filepath.Join(projHooksDir, "*.yaml")); err == nil {
 		paths = append(paths, projYamls...)
 	}
+	if projTomls, err := filepath.Glob(filepath.Join(projHooksDir, "*.toml")); err == nil {
+		paths = append(paths, projTomls...)
+	}
 
-	// Enumerate all *.yml and *.yaml files in global hooks directory
+	// Enumerate all *.yml, *.yaml and *.toml files in global hooks directory
 	globHooksDir := filepath.Join(glob, "hooks")
 	if globYmls, err := filepath.Glob(filepath.Join(globHooksDir, "*.yml")); err == nil {
 		paths = append(paths, globYmls...)
@@ -346,6 +429,9 @@ func getCandidateConfigPaths() ([]string, error) {
 	if globYamls, err := filepath.Glob(filepath.Join(globHooksDir, "*.yaml")); err == nil {
 		paths = append(paths, globYamls...)
 	}
+	if globTomls, err := filepath.Glob(filepath.Join(globHooksDir, "*.toml")); err == nil {
+		paths = append(paths, globTomls...)
+	}
 
 	return paths, nil
 }
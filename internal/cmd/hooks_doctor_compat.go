@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// runCompatCheck reports mismatches between Claude Code payloads, the linked
+// cchooks library and what built-in hooks expect
+func runCompatCheck(clear bool) error {
+	if clear {
+		if err := core.ClearPayloadMismatches(); err != nil {
+			return err
+		}
+		output.Println("✅ Recorded payload mismatches cleared")
+		return nil
+	}
+
+	output.Println("🔌 Compatibility Check")
+	output.Println(strings.Repeat("-", 52))
+
+	issues := 0
+	version := core.CchooksVersion()
+	tested := strings.Join(core.CchooksTestedVersions, ", ")
+	switch {
+	case version == "":
+		output.Printf("cchooks: version unknown (tested: %s)\n", tested)
+	case core.IsTestedCchooksVersion(version):
+		output.Printf("cchooks: ✓ %s\n", version)
+	default:
+		issues++
+		output.Printf("cchooks: ⚠️  %s is not a tested version (tested: %s)\n", version, tested)
+	}
+
+	if problems := core.CheckCchooksDecoding(); len(problems) > 0 {
+		issues += len(problems)
+		output.Println("Decoding: ⚠️  cchooks no longer reads fields Claude Code sends:")
+		for _, p := range problems {
+			output.Printf("  • %s\n", p)
+		}
+	} else {
+		output.Println("Decoding: ✓ PreToolUse and PostToolUse payloads decode fully")
+	}
+
+	mismatches, err := core.LoadPayloadMismatches()
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		output.Println("Payloads: ✓ No mismatches recorded from hook runs in this project")
+	} else {
+		issues += len(mismatches)
+		output.Println("Payloads: ⚠️  Claude Code sent events without fields hooks rely on:")
+		for _, m := range mismatches {
+			output.Printf("  • %s missing %s (%d time(s), last %s)\n", m.Event, strings.Join(m.Missing, ", "), m.Count, m.LastSeen.Local().Format(time.RFC3339))
+		}
+		output.Println("  Hooks may be silently allowing these events. Upgrade blues-traveler, then run 'doctor --compat --clear'.")
+	}
+
+	output.Println()
+	if issues == 0 {
+		output.Println("✅ No compatibility problems found")
+	} else {
+		output.Printf("Found %d compatibility problem(s)\n", issues)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// doctorFixReport is the repair report for one settings file
+type doctorFixReport struct {
+	Scope   string                  `json:"scope"`
+	Path    string                  `json:"path"`
+	Applied bool                    `json:"applied"`
+	Repairs []config.SettingsRepair `json:"repairs"`
+}
+
+// runDoctorFix repairs the project and global settings files, or with
+// dryRun reports what it would repair
+func runDoctorFix(dryRun, jsonOut bool) error {
+	var groups map[string]bool
+	if cfg, err := config.LoadHooksConfig(); err == nil {
+		groups = map[string]bool{}
+		for _, g := range config.ListHookGroups(cfg) {
+			groups[g] = true
+		}
+	} else if !jsonOut {
+		output.Printf("⚠️  Skipping the missing-group check: %v\n", err)
+	}
+
+	var reports []doctorFixReport
+	for _, global := range []bool{false, true} {
+		scope := "project"
+		if global {
+			scope = "global"
+		}
+		path, err := config.GetSettingsPath(global)
+		if err != nil {
+			return fmt.Errorf("failed to get %s settings path: %w", scope, err)
+		}
+		opts := config.SettingsRepairOptions{Groups: groups, ResolveEvent: core.ResolveEventAlias}
+		if exe, err := resolveHookExecutable(global, dryRun); err == nil {
+			opts.Executable = exe
+		} else if !jsonOut {
+			output.Printf("⚠️  Stale %s binary paths will only be reported: %v\n", scope, err)
+		}
+		repairs, err := config.RepairSettingsFile(path, opts, !dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to repair %s settings: %w", scope, err)
+		}
+		if repairs == nil {
+			repairs = []config.SettingsRepair{}
+		}
+		reports = append(reports, doctorFixReport{Scope: scope, Path: path, Applied: !dryRun, Repairs: repairs})
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode repair report: %w", err)
+		}
+		output.Println(string(data))
+		return nil
+	}
+	printDoctorFixReports(reports, dryRun)
+	return nil
+}
+
+// printDoctorFixReports prints every repair, grouped by settings file
+func printDoctorFixReports(reports []doctorFixReport, dryRun bool) {
+	fixedWord := "fixed"
+	if dryRun {
+		fixedWord = "would fix"
+	}
+	fixed, manual := 0, 0
+	for _, r := range reports {
+		output.Printf("\n🔧 %s settings: %s\n", r.Scope, r.Path)
+		if len(r.Repairs) == 0 {
+			output.Println("  ✓ Nothing to repair")
+			continue
+		}
+		for _, rep := range r.Repairs {
+			status := "⚠️  manual"
+			if rep.Fixed {
+				status = "✓ " + fixedWord
+				fixed++
+			} else {
+				manual++
+			}
+			where := rep.Event
+			if rep.Matcher != "" {
+				where += " [" + rep.Matcher + "]"
+			}
+			output.Printf("  %s  %s  %s\n", status, rep.Kind, where)
+			if rep.Command != "" {
+				output.Printf("      command: %s\n", rep.Command)
+			}
+			output.Printf("      %s\n", rep.Action)
+		}
+	}
+
+	output.Println()
+	if dryRun {
+		output.Printf("Dry run: %d repair(s) would be applied, %d need a manual fix. Run 'doctor --fix' to apply.\n", fixed, manual)
+		return
+	}
+	output.Printf("%d repair(s) applied, %d need a manual fix.\n", fixed, manual)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// doctorSettingsReport is the lint report for one settings file
+type doctorSettingsReport struct {
+	Scope    string                   `json:"scope"`
+	Path     string                   `json:"path"`
+	Findings []config.SettingsFinding `json:"findings"`
+}
+
+// newDoctorSettingsCommand creates "doctor settings", which lints the
+// project and global settings.json
+func newDoctorSettingsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "settings",
+		Usage: "Lint settings.json for shadowed matchers, repeated commands, stale binaries and timeouts",
+		Description: `Check the project and global settings.json for hook entries that load
+fine but misbehave:
+
+  shadowed-matcher   the same command under two matchers of an event that
+                     overlap, e.g. "*" and "Edit,Write", so it runs twice
+  duplicate-command  the same command under several events it doesn't handle
+  stale-binary       a blues-traveler command whose binary is missing or is
+                     not the one new installs use
+  timeout-limit      a timeout outside the 1-600 seconds Claude Code allows
+
+Each finding comes with a hint on how to fix it. Nothing is changed; missing
+binaries and repeated matchers can be repaired with 'doctor --fix'.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Value: false,
+				Usage: "Print the findings as JSON",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return runDoctorSettings(cmd.Bool("json"))
+		},
+	}
+}
+
+// runDoctorSettings lints the project and global settings files
+func runDoctorSettings(jsonOut bool) error {
+	var reports []doctorSettingsReport
+	for _, global := range []bool{false, true} {
+		scope := "project"
+		if global {
+			scope = "global"
+		}
+		path, err := config.GetSettingsPath(global)
+		if err != nil {
+			return fmt.Errorf("failed to get %s settings path: %w", scope, err)
+		}
+		report := doctorSettingsReport{Scope: scope, Path: path, Findings: []config.SettingsFinding{}}
+		if _, err := os.Stat(path); err == nil {
+			settings, err := config.LoadSettings(path)
+			if err != nil {
+				return fmt.Errorf("failed to load %s settings: %w\n  Suggestion: Fix the JSON in %s", scope, err, path)
+			}
+			opts := config.SettingsLintOptions{HandlesEvent: hookHandlesEvent}
+			if exe, err := resolveHookExecutable(global, true); err == nil {
+				opts.Executable = exe
+			}
+			if found := config.LintSettings(settings, opts); found != nil {
+				report.Findings = found
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode settings report: %w", err)
+		}
+		output.Println(string(data))
+		return nil
+	}
+	printDoctorSettingsReports(reports)
+	return nil
+}
+
+// hookHandlesEvent reports whether a hook type's manifest handles event
+func hookHandlesEvent(hookType, event string) bool {
+	hook, err := core.CreateHook(hookType)
+	if err != nil {
+		return false
+	}
+	return hook.Manifest().HandlesEvent(event)
+}
+
+// printDoctorSettingsReports prints every finding, grouped by settings file
+func printDoctorSettingsReports(reports []doctorSettingsReport) {
+	total := 0
+	for _, r := range reports {
+		output.Printf("\n🔎 %s settings: %s\n", r.Scope, r.Path)
+		if len(r.Findings) == 0 {
+			output.Println("  ✓ No problems found")
+			continue
+		}
+		for _, f := range r.Findings {
+			total++
+			where := f.Event
+			if f.Matcher != "" {
+				where += " [" + f.Matcher + "]"
+			}
+			output.Printf("  ⚠️  %s  %s\n", f.Kind, where)
+			if f.Command != "" {
+				output.Printf("      command: %s\n", f.Command)
+			}
+			output.Printf("      %s\n", f.Problem)
+			output.Printf("      → %s\n", f.Hint)
+		}
+	}
+	output.Println()
+	output.Printf("%d problem(s) found.\n", total)
+}
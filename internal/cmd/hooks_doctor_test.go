@@ -202,3 +202,59 @@ func TestGetCandidateConfigPaths(t *testing.T) {
 		t.Error("expected global-level paths in candidates")
 	}
 }
+
+func TestRunDoctorFix(t *testing.T) {
+	hooks := `test-group:
+  PreToolUse:
+    jobs:
+      - name: test-job
+        run: echo "test"
+`
+	cleanup := setupDoctorTest(t, hooks)
+	defer cleanup()
+	t.Setenv("HOME", t.TempDir())
+
+	exe := filepath.Join(t.TempDir(), "blues-traveler")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := filepath.Join(".claude", "settings.json")
+	settings := &btconfig.Settings{Hooks: btconfig.HooksConfig{
+		PreToolUse: []btconfig.HookMatcher{
+			{Matcher: "*", Hooks: []btconfig.HookCommand{
+				{Type: "command", Command: "/gone/blues-traveler hooks run config:test-group:test-job"},
+				{Type: "command", Command: exe + " hooks run config:missing-group:job"},
+			}},
+			{Matcher: "*", Hooks: []btconfig.HookCommand{{Type: "command", Command: "echo hi"}}},
+		},
+	}}
+	if err := btconfig.SaveSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(settingsPath)
+
+	if err := runDoctorFix(true, true); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if after, _ := os.ReadFile(settingsPath); string(after) != string(before) {
+		t.Fatal("dry run modified settings")
+	}
+
+	if err := runDoctorFix(false, false); err != nil {
+		t.Fatalf("fix failed: %v", err)
+	}
+	fixed, err := btconfig.LoadSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pre := fixed.Hooks.PreToolUse
+	if len(pre) != 1 || len(pre[0].Hooks) != 2 {
+		t.Fatalf("unexpected PreToolUse after fix: %+v", pre)
+	}
+	if cmd := pre[0].Hooks[0].Command; strings.HasPrefix(cmd, "/gone/") || !strings.HasSuffix(cmd, "hooks run config:test-group:test-job") {
+		t.Errorf("stale binary path not repointed: %q", cmd)
+	}
+	if pre[0].Hooks[1].Command != "echo hi" {
+		t.Errorf("duplicate matcher not merged: %+v", pre[0].Hooks)
+	}
+}
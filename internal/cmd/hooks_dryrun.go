@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// runHookDryRun shows what the hook would do for each event in replayPath,
+// the payload piped on stdin, or a synthesized event when neither is given
+func runHookDryRun(ctx context.Context, key, replayPath string) error {
+	hook, err := core.CreateHook(key)
+	if err != nil {
+		return err
+	}
+	dr, ok := hook.(core.DryRunner)
+	if !ok {
+		return fmt.Errorf("'%s' does not support --dry-run\n  Suggestion: Dry runs cover custom jobs (config:<group>:<job>); try 'hooks test %s' for built-in hooks", key, key)
+	}
+
+	payloads, err := dryRunPayloads(replayPath, os.Stdin)
+	if err != nil {
+		return err
+	}
+	for i, payload := range payloads {
+		if i > 0 {
+			output.Println()
+		}
+		report, err := dr.DryRun(ctx, payload)
+		if err != nil {
+			return fmt.Errorf("dry run of '%s' failed: %w", key, err)
+		}
+		printDryRunReport(key, report)
+	}
+	return nil
+}
+
+// dryRunPayloads returns the events to evaluate. A nil entry asks the hook
+// to synthesize one.
+func dryRunPayloads(replayPath string, stdin *os.File) ([][]byte, error) {
+	if replayPath != "" {
+		events, err := core.LoadReplayEvents(replayPath)
+		if err != nil {
+			return nil, err
+		}
+		payloads := make([][]byte, len(events))
+		for i, ev := range events {
+			payloads[i] = ev.Payload
+		}
+		return payloads, nil
+	}
+	if !hasPipedInput(stdin) {
+		return [][]byte{nil}, nil
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event from stdin: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return [][]byte{nil}, nil
+	}
+	return [][]byte{data}, nil
+}
+
+// hasPipedInput reports whether f is a pipe or file to read an event from,
+// rather than a terminal or a device such as /dev/null
+func hasPipedInput(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
+}
+
+func printDryRunReport(key string, r *core.DryRunReport) {
+	label := r.Event
+	if r.Tool != "" {
+		label += " " + r.Tool
+	}
+	output.Printf("Dry run: %s (%s)\n", key, label)
+	if r.Synthesized {
+		output.Printf("  Payload (synthesized): %s\n", r.Payload)
+	}
+	for _, note := range r.Notes {
+		output.Printf("  Note: %s\n", note)
+	}
+	for _, step := range r.Steps {
+		prefix := "  "
+		if step.File != "" {
+			output.Printf("  File: %s\n", step.File)
+			prefix = "    "
+		}
+		if step.WouldRun {
+			output.Printf("%sWould run: yes\n", prefix)
+		} else {
+			output.Printf("%sWould run: no (%s)\n", prefix, step.Reason)
+		}
+		if step.Command != "" {
+			output.Printf("%sCommand:\n%s\n", prefix, indentLines(step.Command, prefix+"  "))
+		}
+		if len(step.Env) > 0 {
+			output.Printf("%sEnvironment:\n", prefix)
+			keys := make([]string, 0, len(step.Env))
+			for k := range step.Env {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				output.Printf("%s  %s=%s\n", prefix, k, step.Env[k])
+			}
+		}
+	}
+}
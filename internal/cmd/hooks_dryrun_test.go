@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDryRunPayloads(t *testing.T) {
+	dir := t.TempDir()
+	replay := filepath.Join(dir, "events.jsonl")
+	event := `{"hook_event_name":"PostToolUse","tool_name":"Write"}`
+	if err := os.WriteFile(replay, []byte(event+"\n"+event+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	payloads, err := dryRunPayloads(replay, nil)
+	if err != nil || len(payloads) != 2 {
+		t.Fatalf("replay payloads = %d, %v; want 2", len(payloads), err)
+	}
+
+	stdin, err := os.Open(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stdin.Close() }()
+	payloads, err = dryRunPayloads("", stdin)
+	if err != nil || len(payloads) != 1 || payloads[0] == nil {
+		t.Fatalf("expected the file on stdin as the payload, got %q, %v", payloads, err)
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = devNull.Close() }()
+	payloads, err = dryRunPayloads("", devNull)
+	if err != nil || len(payloads) != 1 || payloads[0] != nil {
+		t.Fatalf("expected a synthesized event without piped input, got %q, %v", payloads, err)
+	}
+}
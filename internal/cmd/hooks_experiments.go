@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksExperimentsCommand creates the experiments command
+func newHooksExperimentsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "experiments",
+		Usage: "Compare message variants from A/B experiments in this project",
+		Description: `Experiments are configured under "experiments" in blues-traveler-config.json.
+Each one names a hook, the decision whose message it rewrites ("block" by
+default, or "approve") and two or more variants. A variant is a template
+that can use {{.Message}} (the hook's own message), {{.Hook}} and {{.Tool}}:
+
+  "experiments": [{
+    "name": "security-wording",
+    "hook": "security",
+    "variants": {
+      "terse": "{{.Message}}",
+      "guided": "{{.Message}}\nExplain why the command is needed, or find a safer alternative."
+    }
+  }]
+
+Every session is assigned one variant, and each PreToolUse/PostToolUse
+response from the hook is recorded. A block counts as recovered when the
+agent's next call of the same tool through that hook is approved, and as
+repeated when it is blocked again; SUCCESS is recovered blocks over all blocks.
+
+Examples:
+  blues-traveler hooks experiments
+  blues-traveler hooks experiments --reset`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "reset",
+				Value: false,
+				Usage: "Discard recorded experiment results",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("reset") {
+				if err := core.ResetExperimentRecords(); err != nil {
+					return err
+				}
+				output.Println("✅ Experiment results reset")
+				return nil
+			}
+			experiments, err := config.LoadExperiments()
+			if err != nil {
+				return fmt.Errorf("invalid experiments: %w", err)
+			}
+			return showExperimentResults(experiments)
+		},
+	}
+}
+
+// showExperimentResults prints recorded results per experiment and variant
+func showExperimentResults(experiments []config.Experiment) error {
+	records, err := core.LoadExperimentRecords()
+	if err != nil {
+		return err
+	}
+	if len(experiments) == 0 {
+		output.Println("⚠️  No experiments are running. Add \"experiments\" to blues-traveler-config.json to start one.")
+	}
+	for _, e := range experiments {
+		status := "running"
+		if e.Disabled {
+			status = "disabled"
+		}
+		decision := e.Decision
+		if decision == "" {
+			decision = config.ExperimentDecisionBlock
+		}
+		output.Printf("🧪 %s: %s %s messages, %d variants (%s)\n", e.Name, e.Hook, decision, len(e.Variants), status)
+	}
+	results := core.SummarizeExperiments(records)
+	if len(results) == 0 {
+		output.Println("No experiment results recorded yet.")
+		return nil
+	}
+
+	output.Println()
+	output.Printf("  %-20s %-12s %8s %8s %9s %9s %9s %8s %8s\n",
+		"EXPERIMENT", "VARIANT", "SESSIONS", "BLOCKS", "RECOVERED", "REPEATED", "NO-RETRY", "APPROVED", "SUCCESS")
+	for _, r := range results {
+		success := "-"
+		if rate := r.RetrySuccessRate(); rate >= 0 {
+			success = fmt.Sprintf("%.0f%%", rate*100)
+		}
+		output.Printf("  %-20s %-12s %8d %8d %9d %9d %9d %8d %8s\n",
+			r.Experiment, r.Variant, r.Sessions, r.Blocks, r.Recovered, r.Repeated, r.NoRetry, r.Approvals, success)
+	}
+	return nil
+}
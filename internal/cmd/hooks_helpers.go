@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 )
 
 // Scope constants
@@ -37,21 +39,22 @@ func listAvailableHooks(
 	sort.Strings(builtin)
 	sort.Strings(custom)
 
-	fmt.Println("Available hook plugins:")
-	fmt.Println()
+	output.Println("Available hook plugins:")
+	output.Println()
 	if len(builtin) > 0 {
-		fmt.Println("Built-in hooks:")
+		output.Println("Built-in hooks:")
 		for _, key := range builtin {
 			p, _ := getPlugin(key)
-			fmt.Printf("  %s - %s\n", key, p.Description())
+			output.Printf("  %s - %s\n", key, p.Description())
+			output.Printf("      %s\n", manifestSummary(p.Manifest()))
 		}
-		fmt.Println()
+		output.Println()
 	}
 	if len(custom) > 0 {
-		fmt.Println("Custom config hooks (from config):")
+		output.Println("Custom config hooks (from config):")
 		for _, key := range custom {
 			p, _ := getPlugin(key)
-			fmt.Printf("  %s - %s\n", key, p.Description())
+			output.Printf("  %s - %s\n", key, p.Description())
 		}
 		// Show groups summary
 		groups := make([]string, 0, len(groupSet))
@@ -60,23 +63,87 @@ func listAvailableHooks(
 		}
 		sort.Strings(groups)
 		if len(groups) > 0 {
-			fmt.Printf("\nGroups: %s\n", strings.Join(groups, ", "))
+			cfg, _ := config.LoadHooksConfig()
+			output.Println("\nGroups:")
+			for _, g := range groups {
+				output.Printf("  %s\n", groupLabel(cfg, g))
+			}
 		}
-		fmt.Println()
+		output.Println()
 	} else {
 		// Suggest how to create custom hooks if none present
-		fmt.Println("No custom hooks found. Create .claude/hooks.yml and use 'blues-traveler hooks custom install --list'.")
-		fmt.Println()
+		output.Println("No custom hooks found. Create .claude/hooks.yml and use 'blues-traveler hooks custom install --list'.")
+		output.Println()
+	}
+
+	output.Println("Use 'blues-traveler hooks run <key>' to run a hook.")
+	output.Println("Use 'blues-traveler hooks install <key>' to install a built-in hook.")
+	output.Println("Use 'blues-traveler hooks custom install <group>' to install a group from hooks.yml.")
+	return nil
+}
+
+// manifestSummary renders a manifest's install defaults and capabilities on one line
+func manifestSummary(m core.Manifest) string {
+	line := fmt.Sprintf("default: %s on %s", m.DefaultEvent, m.DefaultMatcher)
+	if len(m.Events) > 1 {
+		line += fmt.Sprintf(" (also handles %s)", strings.Join(m.Events[1:], ", "))
+	}
+	if len(m.Capabilities) > 0 {
+		caps := make([]string, len(m.Capabilities))
+		for i, c := range m.Capabilities {
+			caps[i] = string(c)
+		}
+		line += "; " + strings.Join(caps, ", ")
+	}
+	if m.SettingsKey != "" {
+		line += "; settings: " + m.SettingsKey
 	}
+	return line
+}
 
-	fmt.Println("Use 'blues-traveler hooks run <key>' to run a hook.")
-	fmt.Println("Use 'blues-traveler hooks install <key>' to install a built-in hook.")
-	fmt.Println("Use 'blues-traveler hooks custom install <group>' to install a group from hooks.yml.")
+// listHookManifests prints every hook's manifest as a JSON array
+func listHookManifests(
+	getPlugin func(string) (PluginProvider, bool),
+	pluginKeys func() []string,
+) error {
+	keys := pluginKeys()
+	sort.Strings(keys)
+	manifests := make([]core.Manifest, 0, len(keys))
+	for _, key := range keys {
+		if p, ok := getPlugin(key); ok {
+			manifests = append(manifests, p.Manifest())
+		}
+	}
+	data, err := json.MarshalIndent(manifests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifests: %w", err)
+	}
+	output.Println(string(data))
 	return nil
 }
 
+// installedListOptions control 'hooks list --installed'
+type installedListOptions struct {
+	// showSource annotates each entry with where it came from
+	showSource bool
+	// group limits the listing to one group's entries when set
+	group string
+}
+
+// parseInstalledFilter reads a --filter value, returning the group it selects
+func parseInstalledFilter(filter string) (string, error) {
+	if filter == "" {
+		return "", nil
+	}
+	key, value, ok := strings.Cut(filter, "=")
+	if !ok || strings.TrimSpace(key) != "group" || strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("invalid filter '%s'\n  Suggestion: Use --filter group=<name>, e.g. --filter group=%s", filter, config.BuiltinGroupName)
+	}
+	return strings.TrimSpace(value), nil
+}
+
 // listInstalledHooks lists hooks installed in settings
-func listInstalledHooks(global bool) error {
+func listInstalledHooks(global bool, opts installedListOptions) error {
 	// Get settings path
 	settingsPath, err := config.GetSettingsPath(global)
 	if err != nil {
@@ -93,26 +160,44 @@ func listInstalledHooks(global bool) error {
 		return fmt.Errorf("failed to load settings from %s: %w\n  Suggestion: Check if the settings file exists and is valid JSON", settingsPath, err)
 	}
 
+	// Config group entries are checked against the hooks config, so stale
+	// ones stand out
+	var hooksCfg *config.CustomHooksConfig
+	if opts.showSource {
+		if hooksCfg, err = config.LoadHooksConfig(); err != nil {
+			output.Printf("⚠️  Could not load hooks config, config group jobs are not checked: %v\n\n", err)
+		}
+	}
+
 	scope := ScopeProject
 	if global {
 		scope = ScopeGlobal
 	}
 
-	fmt.Printf("Installed hooks (%s settings):\n", scope)
-	fmt.Printf("Settings file: %s\n\n", settingsPath)
-
-	if config.IsHooksConfigEmpty(settings.Hooks) {
-		fmt.Println("No hooks are currently installed.")
-	} else {
-		printHookMatchers("PreToolUse", settings.Hooks.PreToolUse)
-		printHookMatchers("PostToolUse", settings.Hooks.PostToolUse)
-		printHookMatchers("UserPromptSubmit", settings.Hooks.UserPromptSubmit)
-		printHookMatchers("Notification", settings.Hooks.Notification)
-		printHookMatchers("Stop", settings.Hooks.Stop)
-		printHookMatchers("SubagentStop", settings.Hooks.SubagentStop)
-		printHookMatchers("PreCompact", settings.Hooks.PreCompact)
-		printHookMatchers("SessionStart", settings.Hooks.SessionStart)
-		printHookMatchers("SessionEnd", settings.Hooks.SessionEnd)
+	output.Printf("Installed hooks (%s settings):\n", scope)
+	output.Printf("Settings file: %s\n", settingsPath)
+	if opts.group != "" {
+		output.Printf("Filter: group=%s\n", opts.group)
+	}
+	output.Println()
+
+	shown := 0
+	h := settings.Hooks
+	shown += printHookMatchers(settings, "PreToolUse", h.PreToolUse, opts, hooksCfg)
+	shown += printHookMatchers(settings, "PostToolUse", h.PostToolUse, opts, hooksCfg)
+	shown += printHookMatchers(settings, "UserPromptSubmit", h.UserPromptSubmit, opts, hooksCfg)
+	shown += printHookMatchers(settings, "Notification", h.Notification, opts, hooksCfg)
+	shown += printHookMatchers(settings, "Stop", h.Stop, opts, hooksCfg)
+	shown += printHookMatchers(settings, "SubagentStop", h.SubagentStop, opts, hooksCfg)
+	shown += printHookMatchers(settings, "PreCompact", h.PreCompact, opts, hooksCfg)
+	shown += printHookMatchers(settings, "SessionStart", h.SessionStart, opts, hooksCfg)
+	shown += printHookMatchers(settings, "SessionEnd", h.SessionEnd, opts, hooksCfg)
+	switch {
+	case shown > 0:
+	case opts.group != "":
+		output.Printf("No installed hooks belong to group '%s'.\n\n", opts.group)
+	default:
+		output.Println("No hooks are currently installed.")
 	}
 
 	// Add examples section
@@ -122,8 +207,8 @@ func listInstalledHooks(global bool) error {
 
 // listEvents lists all available Claude Code hook events
 func listEvents(allEvents func() []ClaudeCodeEvent) error {
-	fmt.Println("Available Claude Code Hook Events:")
-	fmt.Println()
+	output.Println("Available Claude Code Hook Events:")
+	output.Println()
 
 	events := allEvents()
 	ccHooksSupported := 0
@@ -136,42 +221,66 @@ func listEvents(allEvents func() []ClaudeCodeEvent) error {
 			status = " ⚠ (Claude Code only)"
 		}
 
-		fmt.Printf("  %s%s\n", event.Name, status)
-		fmt.Printf("      %s\n", event.Description)
-		fmt.Println()
+		output.Printf("  %s%s\n", event.Name, status)
+		output.Printf("      %s\n", event.Description)
+		output.Println()
 	}
 
-	fmt.Printf("Total: %d events available (%d supported by cchooks library)\n\n", len(events), ccHooksSupported)
-	fmt.Println("✓ Events marked with checkmark can be handled by blues-traveler plugins")
-	fmt.Println("⚠ Events marked with warning require custom hook implementations")
-	fmt.Println()
-	fmt.Println("Use 'blues-traveler hooks install <plugin-key> --event <event-name>' to install a hook for a specific event.")
-	fmt.Println("Use 'blues-traveler hooks list --installed' to see currently configured hooks.")
+	output.Printf("Total: %d events available (%d supported by cchooks library)\n\n", len(events), ccHooksSupported)
+	output.Println("✓ Events marked with checkmark can be handled by blues-traveler plugins")
+	output.Println("⚠ Events marked with warning are handled by custom hook jobs, which get every payload")
+	output.Println("  field as a BT_EVENT_<FIELD> variable and the full payload in the $BT_EVENT_JSON file")
+	output.Println()
+	output.Println("Use 'blues-traveler hooks install <plugin-key> --event <event-name>' to install a hook for a specific event.")
+	output.Println("Use 'blues-traveler hooks list --installed' to see currently configured hooks.")
 	return nil
 }
 
-// printHookMatchers prints hook matchers for a specific event
-func printHookMatchers(eventName string, matchers []config.HookMatcher) {
-	if len(matchers) == 0 {
-		return
-	}
-
-	fmt.Printf("%s:\n", eventName)
+// printHookMatchers prints hook matchers for a specific event, marking
+// entries switched off with 'hooks disable', and returns how many entries
+// it printed
+func printHookMatchers(settings *config.Settings, eventName string, matchers []config.HookMatcher, opts installedListOptions, hooksCfg *config.CustomHooksConfig) int {
+	printed := 0
 	for _, matcher := range matchers {
+		var lines []string
+		for _, hook := range matcher.Hooks {
+			source := config.EntrySourceOf(hook.Command, eventName, hooksCfg)
+			if opts.group != "" && !source.InGroup(opts.group) {
+				continue
+			}
+			line := "    - " + hook.Command
+			if hook.Timeout != nil {
+				line += fmt.Sprintf(" (timeout: %ds)", *hook.Timeout)
+			}
+			if settings.EntryDisabled(eventName, matcher.Matcher, hook.Command) {
+				line += " (disabled)"
+			}
+			if opts.showSource {
+				line += "\n        source: " + source.String()
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if printed == 0 {
+			output.Printf("%s:\n", eventName)
+		}
+		printed += len(lines)
+
 		matcherStr := matcher.Matcher
 		if matcherStr == "" {
 			matcherStr = "*"
 		}
-		fmt.Printf("  Matcher: %s\n", matcherStr)
-		for _, hook := range matcher.Hooks {
-			fmt.Printf("    - %s", hook.Command)
-			if hook.Timeout != nil {
-				fmt.Printf(" (timeout: %ds)", *hook.Timeout)
-			}
-			fmt.Println()
+		output.Printf("  Matcher: %s\n", matcherStr)
+		for _, line := range lines {
+			output.Println(line)
 		}
 	}
-	fmt.Println()
+	if printed > 0 {
+		output.Println()
+	}
+	return printed
 }
 
 // printUninstallExamples prints examples of how to uninstall hooks
@@ -183,30 +292,30 @@ func printUninstallExamples(global bool) {
 		globalFlag = " --global"
 	}
 
-	fmt.Printf("📝 How to remove hooks:\n\n")
+	output.Printf("📝 How to remove hooks:\n\n")
 
-	fmt.Printf("Remove a specific hook type (removes ALL instances):\n")
-	fmt.Printf("  blues-traveler hooks uninstall debug%s\n", globalFlag)
-	fmt.Printf("  blues-traveler hooks uninstall security%s\n", globalFlag)
-	fmt.Printf("  blues-traveler hooks uninstall audit%s\n\n", globalFlag)
+	output.Printf("Remove a specific hook type (removes ALL instances):\n")
+	output.Printf("  blues-traveler hooks uninstall debug%s\n", globalFlag)
+	output.Printf("  blues-traveler hooks uninstall security%s\n", globalFlag)
+	output.Printf("  blues-traveler hooks uninstall audit%s\n\n", globalFlag)
 
-	fmt.Printf("Remove ALL blues-traveler hooks (preserves other hooks):\n")
-	fmt.Printf("  blues-traveler hooks uninstall all%s\n\n", globalFlag)
+	output.Printf("Remove ALL blues-traveler hooks (preserves other hooks):\n")
+	output.Printf("  blues-traveler hooks uninstall all%s\n\n", globalFlag)
 
-	fmt.Printf("Remove ALL hooks from %s settings:\n", scope)
+	output.Printf("Remove ALL hooks from %s settings:\n", scope)
 	if global {
-		fmt.Printf("  rm ~/.claude/settings.json\n")
-		fmt.Printf("  # (or edit the file manually to remove specific events)\n\n")
+		output.Printf("  rm ~/.claude/settings.json\n")
+		output.Printf("  # (or edit the file manually to remove specific events)\n\n")
 	} else {
-		fmt.Printf("  rm .claude/settings.json\n")
-		fmt.Printf("  # (or edit the file manually to remove specific events)\n\n")
+		output.Printf("  rm .claude/settings.json\n")
+		output.Printf("  # (or edit the file manually to remove specific events)\n\n")
 	}
 
-	fmt.Printf("View this list again:\n")
-	fmt.Printf("  %s hooks list --installed%s\n\n", "blues-traveler", globalFlag)
+	output.Printf("View this list again:\n")
+	output.Printf("  %s hooks list --installed%s\n\n", "blues-traveler", globalFlag)
 
-	fmt.Printf("💡 Note: The 'uninstall' command removes ALL instances of a hook type\n")
-	fmt.Printf("   from ALL events (PreToolUse, PostToolUse, etc.)\n")
+	output.Printf("💡 Note: The 'uninstall' command removes ALL instances of a hook type\n")
+	output.Printf("   from ALL events (PreToolUse, PostToolUse, etc.)\n")
 }
 
 // syncOptions holds parameters for the sync command
@@ -219,48 +328,13 @@ type syncOptions struct {
 	postMatcher     string
 	timeoutOverride int
 	execPath        string
-}
-
-// pickMatcherForEvent returns the appropriate matcher based on event type
-func pickMatcherForEvent(eventName, postMatcher, defaultMatcher string) string {
-	if eventName == "PostToolUse" {
-		return postMatcher
-	}
-	return defaultMatcher
-}
-
-// buildConfigGroupsMap creates a map of group names from hooks config
-func buildConfigGroupsMap(hooksCfg *config.CustomHooksConfig) map[string]bool {
-	configGroups := make(map[string]bool)
-	if hooksCfg != nil {
-		for groupName := range *hooksCfg {
-			configGroups[groupName] = true
-		}
-	}
-	return configGroups
-}
-
-// cleanupStaleGroups removes groups that exist in settings but not in config
-func cleanupStaleGroups(settings *config.Settings, existingGroups map[string]bool, configGroups map[string]bool, opts syncOptions) int {
-	changed := 0
-	for existingGroup := range existingGroups {
-		if shouldSkipGroup(existingGroup, opts.groupFilter) {
-			continue
-		}
-		if !configGroups[existingGroup] {
-			removed := config.RemoveConfigGroupFromSettings(settings, existingGroup, opts.eventFilter)
-			if removed > 0 {
-				printCleanupMessage(removed, existingGroup, opts.eventFilter)
-				changed += removed
-			}
-		}
-	}
-	return changed
-}
-
-// shouldSkipGroup returns true if the group should be skipped based on filter
-func shouldSkipGroup(groupName, groupFilter string) bool {
-	return groupFilter != "" && groupName != groupFilter
+	// quiet suppresses progress messages, for callers that report the
+	// changes themselves (config diff)
+	quiet bool
+	// pruneOnly removes orphaned config group entries without syncing
+	pruneOnly bool
+	// report lists orphaned entries before anything changes
+	report bool
 }
 
 // printCleanupMessage prints a message about cleaned up entries
@@ -269,7 +343,7 @@ func printCleanupMessage(removed int, groupName, eventFilter string) {
 	if eventFilter != "" {
 		suffix = " (event: " + eventFilter + ")"
 	}
-	fmt.Printf("Cleaned up %d stale entries for removed group '%s'%s\n", removed, groupName, suffix)
+	output.Printf("Cleaned up %d stale entries for removed group '%s'%s\n", removed, groupName, suffix)
 }
 
 // printPrunedMessage prints a message about pruned entries
@@ -278,19 +352,7 @@ func printPrunedMessage(removed int, groupName, eventFilter string) {
 	if eventFilter != "" {
 		suffix = " (event: " + eventFilter + ")"
 	}
-	fmt.Printf("Pruned %d entries for group '%s'%s\n", removed, groupName, suffix)
-}
-
-// syncGroupToSettings syncs a single group's events and jobs to settings
-func syncGroupToSettings(settings *config.Settings, groupName string, group config.HookGroup, opts syncOptions) int {
-	changed := 0
-	for eventName, ev := range group {
-		if shouldSkipEvent(eventName, opts.eventFilter) {
-			continue
-		}
-		changed += syncJobsForEvent(settings, groupName, eventName, ev, opts)
-	}
-	return changed
+	output.Printf("Pruned %d entries for group '%s'%s\n", removed, groupName, suffix)
 }
 
 // shouldSkipEvent returns true if the event should be skipped based on filter
@@ -298,45 +360,6 @@ func shouldSkipEvent(eventName, eventFilter string) bool {
 	return eventFilter != "" && eventFilter != eventName
 }
 
-// syncJobsForEvent syncs jobs for a specific event to settings
-func syncJobsForEvent(settings *config.Settings, groupName, eventName string, ev *config.EventConfig, opts syncOptions) int {
-	changed := 0
-	for _, job := range ev.Jobs {
-		if job.Name == "" {
-			continue
-		}
-		hookCommand := buildHookCommand(opts.execPath, groupName, job.Name)
-		timeout := selectTimeout(opts.timeoutOverride, job.Timeout)
-		matcher := pickMatcherForEvent(eventName, opts.postMatcher, opts.defaultMatcher)
-
-		result := config.AddHookToSettings(settings, eventName, matcher, hookCommand, timeout)
-		if !result.WasDuplicate {
-			changed++
-		}
-
-		if opts.dryRun {
-			fmt.Printf("Would add: [%s] matcher=%q command=%q\n", eventName, matcher, hookCommand)
-		}
-	}
-	return changed
-}
-
-// buildHookCommand constructs the hook command string
-func buildHookCommand(execPath, groupName, jobName string) string {
-	return fmt.Sprintf("%s hooks run config:%s:%s", execPath, groupName, jobName)
-}
-
-// selectTimeout returns the appropriate timeout value
-func selectTimeout(override, jobTimeout int) *int {
-	if override > 0 {
-		return &override
-	}
-	if jobTimeout > 0 {
-		return &jobTimeout
-	}
-	return nil
-}
-
 // installOptions holds parameters for the install command
 type installOptions struct {
 	groupName       string
@@ -347,22 +370,34 @@ type installOptions struct {
 	timeoutOverride int
 	prune           bool
 	init            bool
+	execPath        string
 }
 
 // listCustomHookGroups lists all custom hook groups from config
 func listCustomHookGroups(cfg *config.CustomHooksConfig) error {
 	groups := config.ListHookGroups(cfg)
 	if len(groups) == 0 {
-		fmt.Println("No custom hook groups found. Create .claude/hooks.yml to define groups.")
+		output.Println("No custom hook groups found. Create .claude/hooks.yml to define groups.")
 		return nil
 	}
-	fmt.Println("Available custom hook groups:")
+	output.Println("Available custom hook groups:")
 	for _, g := range groups {
-		fmt.Printf("- %s\n", g)
+		output.Printf("- %s\n", groupLabel(cfg, g))
 	}
 	return nil
 }
 
+// groupLabel is the group's name followed by its description, if it has one
+func groupLabel(cfg *config.CustomHooksConfig, name string) string {
+	if cfg == nil {
+		return name
+	}
+	if group := (*cfg)[name]; group != nil && group.Description != "" {
+		return name + " - " + group.Description
+	}
+	return name
+}
+
 // loadOrCreateGroup loads a group from config, optionally creating a stub if --init is used
 func loadOrCreateGroup(cfg *config.CustomHooksConfig, groupName string, initFlag, useGlobal bool) (*config.CustomHooksConfig, error) {
 	if cfg != nil && (*cfg)[groupName] != nil {
@@ -409,9 +444,12 @@ func createSampleGroupYAML(groupName string) string {
 }
 
 // installGroupHooks installs all hooks from a group into settings
-func installGroupHooks(settings *config.Settings, group config.HookGroup, opts installOptions) int {
+func installGroupHooks(settings *config.Settings, group *config.HookGroup, opts installOptions) int {
 	installed := 0
-	for eventName, ev := range group {
+	if group == nil {
+		return 0
+	}
+	for eventName, ev := range group.Events {
 		if shouldSkipEvent(eventName, opts.eventFilter) {
 			continue
 		}
@@ -428,13 +466,10 @@ func installJobsForEvent(settings *config.Settings, eventName string, ev *config
 			continue
 		}
 
-		hookCommand, err := buildInstallCommand(opts.groupName, job.Name)
-		if err != nil {
-			continue // skip on error
-		}
+		hookCommand := config.ConfigHookCommand(opts.execPath, opts.groupName, job.Name)
 
-		timeout := selectTimeout(opts.timeoutOverride, job.Timeout)
-		matcher := pickMatcherForEvent(eventName, opts.postMatcher, opts.defaultMatcher)
+		timeout := config.JobTimeout(opts.timeoutOverride, job.Timeout)
+		matcher := config.MatcherForEvent(eventName, opts.postMatcher, opts.defaultMatcher)
 
 		config.AddHookToSettings(settings, eventName, matcher, hookCommand, timeout)
 		installed++
@@ -442,26 +477,10 @@ func installJobsForEvent(settings *config.Settings, eventName string, ev *config
 	return installed
 }
 
-// buildInstallCommand builds the hook command for installation
-func buildInstallCommand(groupName, jobName string) (string, error) {
-	execPath, err := os.Executable()
-	if err != nil {
-		return "", fmt.Errorf("failed to get executable path: %w", err)
-	}
-
-	// Quote the path when it contains spaces to handle paths like "/Program Files/app"
-	// Commands are executed via bash -lc, so unquoted paths with spaces break
-	if strings.ContainsRune(execPath, ' ') {
-		execPath = `"` + execPath + `"`
-	}
-
-	return fmt.Sprintf("%s hooks run config:%s:%s", execPath, groupName, jobName), nil
-}
-
 // printInstallSuccess prints success message for hook installation
 func printInstallSuccess(groupName, scope string, installed int, settingsPath string) {
-	fmt.Printf("✅ Installed custom group '%s' to %s settings (%d entries)\n", groupName, scope, installed)
-	fmt.Printf("   Settings: %s\n", settingsPath)
+	output.Printf("✅ Installed custom group '%s' to %s settings (%d entries)\n", groupName, scope, installed)
+	output.Printf("   Settings: %s\n", settingsPath)
 }
 
 // getScopeName returns the scope name for display
@@ -489,21 +508,40 @@ func loadLogConfigForBlockedURLs(useGlobal bool) (string, *config.LogConfig, err
 	return path, lc, nil
 }
 
+// lockLogConfigForBlockedURLs is loadLogConfigForBlockedURLs for commands
+// that change the list; call release after saving
+func lockLogConfigForBlockedURLs(useGlobal bool) (path string, lc *config.LogConfig, release func(), err error) {
+	path, err = config.GetLogConfigPath(useGlobal)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	release, err = config.LockFile(path)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	lc, err = config.LoadLogConfig(path)
+	if err != nil {
+		release()
+		return "", nil, nil, err
+	}
+	return path, lc, release, nil
+}
+
 // displayBlockedURLs prints the blocked URLs list
 func displayBlockedURLs(lc *config.LogConfig, path string, useGlobal bool) {
 	scope := getScopeName(useGlobal)
-	fmt.Printf("Blocked URLs (%s config: %s):\n", scope, path)
+	output.Printf("Blocked URLs (%s config: %s):\n", scope, path)
 
 	if len(lc.BlockedURLs) == 0 {
-		fmt.Println("(none)")
+		output.Println("(none)")
 		return
 	}
 
 	for _, b := range lc.BlockedURLs {
 		if b.Suggestion != "" {
-			fmt.Printf("- %s | %s\n", b.Prefix, b.Suggestion)
+			output.Printf("- %s | %s\n", b.Prefix, b.Suggestion)
 		} else {
-			fmt.Printf("- %s\n", b.Prefix)
+			output.Printf("- %s\n", b.Prefix)
 		}
 	}
 }
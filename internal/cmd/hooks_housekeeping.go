@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksHousekeepingCommand creates the housekeeping command
+func newHooksHousekeepingCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "housekeeping",
+		Usage: "Remove old hook logs and local data past its retention policy",
+		Description: `Deletes hook logs older than the rotation max age, then the oldest logs
+(rotated backups first) until all of them fit in logRotation.MaxTotalSize
+megabytes (default 100), and prunes job output kept by capture_output the
+same way (see 'hooks artifacts'). Then applies the retention policy of each category
+of local data (payloads, runHistory, artifacts, metrics, state); see
+'blues-traveler clean'.
+
+'hooks run --log' starts this in the background at most once a day per
+project; the last run is recorded in .claude/state/housekeeping.json. Run it
+by hand to see what it does or to clean up right away.
+
+Examples:
+  blues-traveler hooks housekeeping
+  blues-traveler hooks housekeeping --force`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Value: false,
+				Usage: "Run even if housekeeping ran in the last day",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			task := combineHousekeeping(
+				logHousekeepingTask(config.ResolveLogRotationConfig()),
+				jobArtifactHousekeepingTask(config.ResolveLogRotationConfig()),
+				retentionHousekeepingTask(config.GetRetentionPolicies()),
+			)
+			rec, ran, err := core.RunHousekeeping(cmd.Bool("force"), task)
+			if err != nil {
+				return fmt.Errorf("housekeeping failed: %w", err)
+			}
+			switch {
+			case ran:
+				output.Printf("✅ Removed %d file(s), freed %s\n", rec.Removed, core.FormatBytes(rec.FreedBytes))
+			case rec == nil:
+				output.Println("Housekeeping is already running in another process.")
+			default:
+				output.Printf("Housekeeping last ran %s (removed %d file(s)); use --force to run again.\n",
+					rec.LastRun.Local().Format(time.RFC3339), rec.Removed)
+			}
+			return nil
+		},
+	}
+}
+
+// logHousekeepingTask removes expired logs, then enforces the size cap
+func logHousekeepingTask(cfg config.LogRotationConfig) core.HousekeepingTask {
+	return func() (int, int64, error) {
+		logDir := filepath.Dir(config.GetLogPath("housekeeping"))
+		removed, freed, err := config.CleanupOldLogs(logDir, cfg.MaxAge)
+		if err != nil {
+			return removed, freed, err
+		}
+		n, bytes, err := config.EnforceLogSizeCap(logDir, cfg.MaxTotalSize)
+		return removed + n, freed + bytes, err
+	}
+}
+
+// jobArtifactHousekeepingTask prunes output captured by capture_output jobs
+// by the same rotation policy as the logs
+func jobArtifactHousekeepingTask(cfg config.LogRotationConfig) core.HousekeepingTask {
+	return func() (int, int64, error) {
+		return core.PruneJobArtifacts("", "", cfg, time.Now())
+	}
+}
+
+// retentionHousekeepingTask prunes every retention category by its policy
+func retentionHousekeepingTask(policies map[string]config.RetentionPolicy) core.HousekeepingTask {
+	return func() (int, int64, error) {
+		results, err := applyRetention(policies, time.Now())
+		removed, freed := 0, int64(0)
+		for _, r := range results {
+			removed += r.Removed
+			freed += r.FreedBytes
+		}
+		return removed, freed, err
+	}
+}
+
+// combineHousekeeping runs tasks in order, adding up what they removed. A
+// failing task doesn't stop the ones after it; the first error is returned.
+func combineHousekeeping(tasks ...core.HousekeepingTask) core.HousekeepingTask {
+	return func() (int, int64, error) {
+		removed, freed := 0, int64(0)
+		var firstErr error
+		for _, task := range tasks {
+			n, bytes, err := task()
+			removed += n
+			freed += bytes
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return removed, freed, firstErr
+	}
+}
+
+// applyRetention prunes each retention target by its category's policy
+func applyRetention(policies map[string]config.RetentionPolicy, now time.Time) ([]core.RetentionResult, error) {
+	targets, err := core.RetentionTargets()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]core.RetentionResult, 0, len(targets))
+	var firstErr error
+	for _, t := range targets {
+		p := policies[t.Category]
+		maxAge := time.Duration(max(p.MaxAgeDays, 0)) * 24 * time.Hour
+		maxBytes := int64(max(p.MaxSizeMB, 0)) << 20
+		res, err := core.PruneRetention(t, maxAge, maxBytes, now)
+		results = append(results, res)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", t.Category, err)
+		}
+	}
+	return results, firstErr
+}
+
+// startBackgroundHousekeeping launches 'hooks housekeeping' as a detached
+// process when it is due, so cleanup never delays the hook being run
+func startBackgroundHousekeeping() {
+	if !core.HousekeepingDue(time.Now()) {
+		return
+	}
+	cmd := exec.Command(resolveExecutablePath(), "hooks", "housekeeping") // #nosec G204 - invoking our own binary
+	cmd.Env = os.Environ()
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/klauern/blues-traveler/internal/presets"
+	"github.com/klauern/blues-traveler/internal/templates"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksInitCommand creates the 'hooks init' command
+func newHooksInitCommand(
+	getPlugin func(string) (PluginProvider, bool),
+	isValidEventType func(string) bool,
+	validEventTypes func() []string,
+) *cli.Command {
+	return &cli.Command{
+		Name:  "init",
+		Usage: "Set up a project's hooks from a template",
+		Description: `Scaffold a project from a template: its custom hooks are written to
+.claude/hooks/<template>.yml and, with the built-in hooks it recommends,
+installed in the project's .claude/settings.json.
+
+Templates are embedded in the binary. Add your own, or replace a built-in one,
+with a directory per template under $BT_TEMPLATE_DIR or
+~/.config/blues-traveler/templates holding template.yml (description and
+built-in hooks) and hooks.yml.
+
+Examples:
+  blues-traveler hooks init --list
+  blues-traveler hooks init --template python
+  blues-traveler hooks init --template go --no-settings`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "template", Aliases: []string{"T"}, Usage: "Template to scaffold from (see --list)"},
+			&cli.BoolFlag{Name: "list", Usage: "List available templates"},
+			&cli.BoolFlag{Name: "overwrite", Usage: "Replace the template's hooks file if it already exists"},
+			&cli.BoolFlag{Name: "no-settings", Usage: "Only write the hooks file; leave settings.json alone"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			all, err := templates.All()
+			if err != nil {
+				return fmt.Errorf("failed to load templates: %w\n  Suggestion: Fix or remove the template in %s", err, templates.Dir())
+			}
+			if cmd.Bool("list") {
+				listTemplates(all)
+				return nil
+			}
+			name := cmd.String("template")
+			if name == "" {
+				return fmt.Errorf("a template is required\n  Suggestion: Use --template with one of: %s", strings.Join(templates.Names(all), ", "))
+			}
+			t, ok, err := templates.Get(name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("unknown template '%s'\n  Suggestion: Available templates: %s", name, strings.Join(templates.Names(all), ", "))
+			}
+			return initFromTemplate(t, cmd.Bool("overwrite"), !cmd.Bool("no-settings"), getPlugin, isValidEventType, validEventTypes)
+		},
+	}
+}
+
+func listTemplates(all []templates.Template) {
+	for _, t := range all {
+		output.Printf("%s", t.Name)
+		if t.Source != templates.SourceBuiltin {
+			output.Printf(" (%s)", t.Source)
+		}
+		output.Printf("\n  %s\n", t.Description)
+		for _, h := range t.Hooks {
+			output.Printf("    • %s", h.Key)
+			if h.Event != "" {
+				output.Printf(" (%s %s)", h.Event, h.Matcher)
+			}
+			output.Println()
+		}
+		output.Println()
+	}
+}
+
+// initFromTemplate writes t's hooks file, then with settings installs its
+// built-in hooks and syncs its groups into the project settings
+func initFromTemplate(
+	t templates.Template,
+	overwrite, settings bool,
+	getPlugin func(string) (PluginProvider, bool),
+	isValidEventType func(string) bool,
+	validEventTypes func() []string,
+) error {
+	var groups []string
+	if len(t.HooksConfig) > 0 {
+		cfg, err := config.ParseHooksConfig(t.HooksConfig, config.FormatYAML)
+		if err != nil {
+			return fmt.Errorf("template '%s': invalid %s: %w", t.Name, templates.HooksFile, err)
+		}
+		groups = sortedKeys(cfg)
+		path, err := writePerGroupConfig(false, t.Name+".yml", t.Name, string(t.HooksConfig), overwrite)
+		if err != nil {
+			return err
+		}
+		output.Printf("📝 Hooks config: %s\n", path)
+	}
+	if !settings {
+		output.Println("Run 'blues-traveler hooks custom sync' to install the custom hooks.")
+		return nil
+	}
+
+	if len(t.Hooks) > 0 {
+		flags, err := templateInstallFlags()
+		if err != nil {
+			return err
+		}
+		hooks := make([]presets.Hook, len(t.Hooks))
+		for i, h := range t.Hooks {
+			hooks[i] = presets.Hook{Key: h.Key, Event: h.Event, Matcher: h.Matcher}
+		}
+		if err := installHookBundle("template", t.Name, hooks, flags, getPlugin, isValidEventType, validEventTypes); err != nil {
+			return err
+		}
+	}
+	for _, group := range groups {
+		if err := syncTemplateGroup(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateInstallFlags are the project install flags a template's built-in
+// hooks are written with, following the configured policies
+func templateInstallFlags() (installFlags, error) {
+	flags := installFlags{logFormat: config.LoggingFormatJSONL}
+	var err error
+	if flags.mergePolicy, err = config.ConfiguredMergePolicy(false); err != nil {
+		return flags, fmt.Errorf("%w\n  Suggestion: Fix mergePolicy in the config file", err)
+	}
+	if flags.execPath, err = config.ConfiguredExecPathStrategy(false); err != nil {
+		return flags, fmt.Errorf("%w\n  Suggestion: Fix execPath in the config file", err)
+	}
+	return flags, nil
+}
+
+// syncTemplateGroup syncs one custom hooks group into the project settings,
+// as 'hooks custom sync <group>' does
+func syncTemplateGroup(group string) error {
+	execPath, err := resolveHookExecutable(false, false)
+	if err != nil {
+		return err
+	}
+	opts := syncOptions{groupFilter: group, defaultMatcher: "*", postMatcher: "Edit,Write", execPath: execPath}
+	release, err := prepareSyncWrite(opts)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	hooksCfg, settings, settingsPath, err := loadSyncDependencies(false)
+	if err != nil {
+		return err
+	}
+	if hooksCfg == nil || (*hooksCfg)[group] == nil {
+		return fmt.Errorf("group '%s' is not in the effective hooks config\n  Suggestion: Run 'blues-traveler hooks custom show --explain' to see which file overrides it", group)
+	}
+	return finalizeSyncOperation(settingsPath, settings, performSync(settings, hooksCfg, opts), opts)
+}
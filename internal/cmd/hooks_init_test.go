@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/templates"
+)
+
+func TestInitFromTemplate(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	tmpl := templates.Template{
+		Name:        "demo",
+		Hooks:       []templates.Hook{{Key: "security"}},
+		HooksConfig: []byte("demo:\n  Stop:\n    jobs:\n      - name: check\n        run: make check\n"),
+	}
+	if err := initFromTemplate(tmpl, false, true, presetTestPlugins(t), core.IsValidEventType, core.ValidEventTypes); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".claude", "hooks", "demo.yml"))
+	if err != nil || string(data) != string(tmpl.HooksConfig) {
+		t.Fatalf("expected the template's hooks file, got %q (%v)", data, err)
+	}
+	settings, err := config.LoadSettings(filepath.Join(dir, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pre := settings.Hooks.PreToolUse; len(pre) != 1 || !strings.HasSuffix(pre[0].Hooks[0].Command, "hooks run security") {
+		t.Errorf("expected the built-in hook installed, got %+v", pre)
+	}
+	if !config.GetConfigGroupsInSettings(settings)["demo"] {
+		t.Error("expected the template's group synced into settings")
+	}
+}
+
+func TestInitFromTemplate_NoSettings(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", t.TempDir())
+
+	tmpl := templates.Template{
+		Name:        "demo",
+		Hooks:       []templates.Hook{{Key: "security"}},
+		HooksConfig: []byte("demo:\n  Stop:\n    jobs:\n      - name: check\n        run: make check\n"),
+	}
+	if err := initFromTemplate(tmpl, false, false, presetTestPlugins(t), core.IsValidEventType, core.ValidEventTypes); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude", "hooks", "demo.yml")); err != nil {
+		t.Errorf("expected the hooks file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude", "settings.json")); !os.IsNotExist(err) {
+		t.Error("settings should not be written with --no-settings")
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -21,6 +23,10 @@ type installFlags struct {
 	timeout    int
 	logEnabled bool
 	logFormat  string
+	// mergePolicy decides what happens when the hook type is already installed
+	mergePolicy config.MergePolicy
+	// execPath decides how the command references the blues-traveler binary
+	execPath config.ExecPathStrategy
 }
 
 // parseInstallFlags extracts and validates flags from the command.
@@ -28,7 +34,7 @@ func parseInstallFlags(cmd *cli.Command) (installFlags, error) {
 	flags := installFlags{
 		global:     cmd.Bool("global"),
 		event:      cmd.String("event"),
-		matcher:    cmd.String("matcher"),
+		matcher:    core.ExpandMCPMatcher(cmd.String("matcher")),
 		timeout:    cmd.Int("timeout"),
 		logEnabled: cmd.Bool("log"),
 		logFormat:  cmd.String("log-format"),
@@ -38,24 +44,38 @@ func parseInstallFlags(cmd *cli.Command) (installFlags, error) {
 		flags.logFormat = config.LoggingFormatJSONL
 	}
 
+	if flags.timeout > config.MaxHookTimeout {
+		return flags, fmt.Errorf("--timeout %d is over the %ds limit\n  Suggestion: Use a shorter timeout or move long work out of the hook", flags.timeout, config.MaxHookTimeout)
+	}
+
 	if flags.logEnabled && !config.IsValidLoggingFormat(flags.logFormat) {
 		return flags, fmt.Errorf("invalid --log-format '%s'. Valid: jsonl, pretty", flags.logFormat)
 	}
 
+	// An explicit flag wins over the mergePolicy in blues-traveler-config.json
+	var err error
+	if name := cmd.String("merge-policy"); name != "" {
+		flags.mergePolicy, err = config.ParseMergePolicy(name)
+	} else {
+		flags.mergePolicy, err = config.ConfiguredMergePolicy(flags.global)
+	}
+	if err != nil {
+		return flags, fmt.Errorf("%w\n  Suggestion: Use --merge-policy or fix mergePolicy in the config file", err)
+	}
+
+	flags.execPath, err = config.ConfiguredExecPathStrategy(flags.global)
+	if err != nil {
+		return flags, fmt.Errorf("%w\n  Suggestion: Fix execPath in the config file", err)
+	}
+
 	return flags, nil
 }
 
 // buildInstallHookCommand constructs the hook command string for install.
 func buildInstallHookCommand(hookType string, flags installFlags) (string, error) {
-	execPath, err := os.Executable()
+	execPath, err := config.HookExecutable(flags.execPath, true)
 	if err != nil {
-		return "", fmt.Errorf("failed to get executable path: %w", err)
-	}
-
-	// Quote the path when it contains spaces to handle paths like "/Program Files/app"
-	// Commands are executed via bash -lc, so unquoted paths with spaces break
-	if strings.ContainsRune(execPath, ' ') {
-		execPath = `"` + execPath + `"`
+		return "", err
 	}
 
 	hookCommand := fmt.Sprintf("%s hooks run %s", execPath, hookType)
@@ -76,23 +96,19 @@ func handleDuplicateHookResult(result config.MergeResult) bool {
 	}
 
 	if strings.Contains(result.DuplicateInfo, "Replaced existing") {
-		fmt.Printf("🔄 %s\n", result.DuplicateInfo)
+		output.Say("hooks.install.replaced", map[string]any{"Info": result.DuplicateInfo})
 		return false
 	}
 
-	fmt.Printf("⚠️  Hook already installed: %s\n", result.DuplicateInfo)
-	fmt.Printf("No changes made. The hook is already configured for this event.\n")
+	output.Say("hooks.install.duplicate", map[string]any{"Info": result.DuplicateInfo})
 	return true
 }
 
 // printHookInstallSuccess displays success message.
 func printHookInstallSuccess(hookType, scope, event, matcher, hookCommand, settingsPath string) {
-	fmt.Printf("✅ Successfully installed %s hook in %s settings\n", hookType, scope)
-	fmt.Printf("   Event: %s\n", event)
-	fmt.Printf("   Matcher: %s\n", matcher)
-	fmt.Printf("   Command: %s\n", hookCommand)
-	fmt.Printf("   Settings: %s\n", settingsPath)
-	fmt.Println()
+	output.Say("hooks.install.success", map[string]any{
+		"Hook": hookType, "Scope": scope, "Event": event, "Matcher": matcher, "Command": hookCommand, "Settings": settingsPath,
+	})
 }
 
 // resolveAndValidateEvent resolves event alias and validates it.
@@ -139,8 +155,7 @@ func performPostInstallActions(hookType string, global bool) {
 func showInstallSuccessMessages(hookType string, scope string, flags installFlags, hookCommand string, settingsPath string, isDuplicateNoChange bool) {
 	if !isDuplicateNoChange {
 		printHookInstallSuccess(hookType, scope, flags.event, flags.matcher, hookCommand, settingsPath)
-		fmt.Println("The hook will be active in new Claude Code sessions.")
-		fmt.Println("Use 'claude /hooks' to verify the configuration.")
+		output.Say("hooks.install.next", nil)
 	}
 }
 
@@ -169,6 +184,12 @@ func installHookAction(hookType string, flags installFlags, isValidEventType fun
 		return fmt.Errorf("failed to locate %s settings path: %w\n  Suggestion: Run 'blues-traveler hooks init' to initialize the project", scope, err)
 	}
 
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Load existing settings
 	settings, err := loadAndValidateSettings(settingsPath)
 	if err != nil {
@@ -180,7 +201,7 @@ func installHookAction(hookType string, flags installFlags, isValidEventType fun
 	if flags.timeout > 0 {
 		timeout = &flags.timeout
 	}
-	result := config.AddHookToSettings(settings, flags.event, flags.matcher, hookCommand, timeout)
+	result := config.AddHookToSettingsWithPolicy(settings, flags.event, flags.matcher, hookCommand, timeout, flags.mergePolicy)
 
 	// Check for duplicates or replacements
 	isDuplicateNoChange := handleDuplicateHookResult(result)
@@ -189,6 +210,7 @@ func installHookAction(hookType string, flags installFlags, isValidEventType fun
 	if err := saveSettingsIfNeeded(settingsPath, settings, isDuplicateNoChange); err != nil {
 		return err
 	}
+	recordBuiltinInstall(flags, settings, hookType, hookCommand, timeout)
 
 	scope := ScopeProject
 	if flags.global {
@@ -204,6 +226,23 @@ func installHookAction(hookType string, flags installFlags, isValidEventType fun
 	return nil
 }
 
+// recordBuiltinInstall tracks the install under the reserved built-in group.
+// The settings entry is already written, so a failure only warns.
+func recordBuiltinInstall(flags installFlags, settings *config.Settings, hookType, hookCommand string, timeout *int) {
+	install := config.BuiltinInstall{Hook: hookType, Event: flags.event, Matcher: flags.matcher, Command: hookCommand, Timeout: timeout}
+	if err := config.RecordBuiltinInstall(flags.global, settings, install); err != nil {
+		output.Printf("⚠️  Could not record the install under group '%s': %v\n", config.BuiltinGroupName, err)
+	}
+}
+
+// forgetBuiltinInstalls drops uninstalled hooks from the reserved built-in
+// group; the settings change has already been saved, so a failure only warns.
+func forgetBuiltinInstalls(global bool, hookType, event, matcher string) {
+	if err := config.ForgetBuiltinInstallsIn(global, hookType, event, matcher); err != nil {
+		output.Printf("⚠️  Could not update group '%s': %v\n", config.BuiltinGroupName, err)
+	}
+}
+
 // executeInstallCommand executes the hooks install command.
 func executeInstallCommand(
 	hookType string,
@@ -214,13 +253,29 @@ func executeInstallCommand(
 	validEventTypes func() []string,
 ) error {
 	// Validate plugin exists
-	if _, exists := getPlugin(hookType); !exists {
+	p, exists := getPlugin(hookType)
+	if !exists {
 		return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", hookType, strings.Join(pluginKeys(), ", "))
 	}
 
+	applyManifestDefaults(&flags, p.Manifest())
 	return installHookAction(hookType, flags, isValidEventType, validEventTypes)
 }
 
+// applyManifestDefaults fills the event and matcher the user left out from
+// the hook's manifest, and warns when the chosen event is one the hook ignores
+func applyManifestDefaults(flags *installFlags, m core.Manifest) {
+	if flags.event == "" {
+		flags.event = m.DefaultEvent
+	}
+	if flags.matcher == "" {
+		flags.matcher = m.DefaultMatcher
+	}
+	if len(m.Events) > 0 && !m.HandlesEvent(flags.event) {
+		output.Printf("⚠️  '%s' only handles %s; installed for %s it will do nothing.\n", m.Key, strings.Join(m.Events, ", "), flags.event)
+	}
+}
+
 // newHooksInstallCommand creates the install command.
 func newHooksInstallCommand(
 	getPlugin func(string) (PluginProvider, bool),
@@ -232,8 +287,19 @@ func newHooksInstallCommand(
 		Name:      "install",
 		Usage:     "Install a hook type into Claude Code settings",
 		ArgsUsage: "[hook-type]",
+		ShellComplete: completeArgs(func() []string {
+			keys := builtinPluginKeys(pluginKeys())
+			sort.Strings(keys)
+			return append(keys, "preset")
+		}),
 		Description: `Install a hook type into your Claude Code settings.json file.
-This will automatically configure the hook to run for the specified events.`,
+This will automatically configure the hook to run for the specified events.
+Without --event and --matcher, the hook's manifest picks them (for example
+PostToolUse on Edit|Write for format); 'hooks list' shows each default.
+'hooks install preset <name>' installs a curated bundle of hooks at once.`,
+		Commands: []*cli.Command{
+			newHooksInstallPresetCommand(getPlugin, isValidEventType, validEventTypes),
+		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "global",
@@ -244,14 +310,12 @@ This will automatically configure the hook to run for the specified events.`,
 			&cli.StringFlag{
 				Name:    "event",
 				Aliases: []string{"e"},
-				Value:   "PreToolUse",
-				Usage:   "Hook event (PreToolUse, PostToolUse, UserPromptSubmit, etc.)",
+				Usage:   "Hook event (PreToolUse, PostToolUse, UserPromptSubmit, etc.; default from the hook's manifest)",
 			},
 			&cli.StringFlag{
 				Name:    "matcher",
 				Aliases: []string{"m"},
-				Value:   "*",
-				Usage:   "Tool matcher pattern (* for all tools)",
+				Usage:   "Tool matcher pattern (* for all tools, mcp:<server> for an MCP server's tools; default from the hook's manifest)",
 			},
 			&cli.IntFlag{
 				Name:    "timeout",
@@ -270,6 +334,10 @@ This will automatically configure the hook to run for the specified events.`,
 				Value: "jsonl",
 				Usage: "Log output format: jsonl or pretty (default jsonl)",
 			},
+			&cli.StringFlag{
+				Name:  "merge-policy",
+				Usage: "When the hook type is already installed: by-hook-type (replace), exact (add unless identical) or never-replace (default from config, else by-hook-type)",
+			},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
 			args := cmd.Args().Slice()
@@ -289,18 +357,44 @@ This will automatically configure the hook to run for the specified events.`,
 	}
 }
 
-// executeUninstallSpecificHook uninstalls a specific hook type.
-func executeUninstallSpecificHook(hookType string, global bool) error {
+// uninstallScope narrows an uninstall to one event and/or matcher; empty
+// fields match any
+type uninstallScope struct {
+	event   string
+	matcher string
+}
+
+// describe renders the scope for messages, e.g. " from PostToolUse (matcher Edit,Write)"
+func (s uninstallScope) describe() string {
+	var b strings.Builder
+	if s.event != "" {
+		b.WriteString(" from " + s.event)
+	}
+	if s.matcher != "" {
+		fmt.Fprintf(&b, " (matcher %s)", s.matcher)
+	}
+	return b.String()
+}
+
+// executeUninstallSpecificHook uninstalls a specific hook type within scope.
+// With purge, the hook's sample files go too once no install of it is left.
+func executeUninstallSpecificHook(hookType string, global bool, scope uninstallScope, purge bool) error {
 	// Get settings path
 	settingsPath, err := config.GetSettingsPath(global)
 	if err != nil {
-		scope := ScopeProject
+		scopeName := ScopeProject
 		if global {
-			scope = ScopeGlobal
+			scopeName = ScopeGlobal
 		}
-		return fmt.Errorf("failed to locate %s settings path: %w\n  Suggestion: Run 'blues-traveler hooks init' to initialize the project", scope, err)
+		return fmt.Errorf("failed to locate %s settings path: %w\n  Suggestion: Run 'blues-traveler hooks init' to initialize the project", scopeName, err)
 	}
 
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Load existing settings
 	settings, err := config.LoadSettings(settingsPath)
 	if err != nil {
@@ -309,9 +403,12 @@ func executeUninstallSpecificHook(hookType string, global bool) error {
 
 	// Remove hook from settings using pattern matching
 	// This handles hooks installed with flags (--log, --format) or different executable paths
-	removed := config.RemoveHookTypeFromSettings(settings, hookType)
+	removed := config.RemoveHookTypeFromScope(settings, hookType, scope.event, scope.matcher)
 
-	if !removed {
+	if removed == 0 {
+		if scope != (uninstallScope{}) {
+			return fmt.Errorf("hook type '%s' was not found%s in settings\n  Suggestion: Run 'blues-traveler hooks list --installed' to see where it is installed", hookType, scope.describe())
+		}
 		return fmt.Errorf("hook type '%s' was not found in settings", hookType)
 	}
 
@@ -319,34 +416,83 @@ func executeUninstallSpecificHook(hookType string, global bool) error {
 	if err := config.SaveSettings(settingsPath, settings); err != nil {
 		return fmt.Errorf("error saving settings: %w", err)
 	}
+	forgetBuiltinInstalls(global, hookType, scope.event, scope.matcher)
 
-	scope := constants.ScopeProject
+	scopeName := constants.ScopeProject
 	if global {
-		scope = constants.ScopeGlobal
+		scopeName = constants.ScopeGlobal
 	}
 
-	fmt.Printf("✅ Successfully removed all '%s' hooks from %s settings\n", hookType, scope)
-	fmt.Printf("   Settings: %s\n", settingsPath)
-	return nil
+	if scope != (uninstallScope{}) {
+		output.Say("hooks.uninstall.scoped_success", map[string]any{
+			"Hook": hookType, "Count": removed, "Where": scope.describe(), "Scope": scopeName, "Settings": settingsPath,
+		})
+	} else {
+		output.Say("hooks.uninstall.success", map[string]any{"Hook": hookType, "Scope": scopeName, "Settings": settingsPath})
+	}
+	if !purge {
+		return nil
+	}
+	// Samples are shared by every install of the hook, so they stay while
+	// one remains
+	for _, h := range config.InstalledHooks(settings.Hooks) {
+		if h.HookType == hookType {
+			output.Printf("📄 Kept %s sample files: the hook is still installed in %s\n", hookType, h.Event)
+			return nil
+		}
+	}
+	return purgeSampleArtifacts(global, hookType, false)
 }
 
 // executeUninstallCommand executes the hooks uninstall command.
-func executeUninstallCommand(hookType string, global, skipConfirmation bool) error {
+func executeUninstallCommand(hookType string, global, skipConfirmation bool, scope uninstallScope, purge bool) error {
 	// Handle 'all' case
 	if hookType == "all" {
-		return uninstallAllKlauerHooks(global, skipConfirmation)
+		if scope != (uninstallScope{}) {
+			return fmt.Errorf("--event and --matcher cannot be used with 'all'\n  Suggestion: Name the hook type to remove, e.g. 'blues-traveler hooks uninstall security --event PostToolUse'")
+		}
+		return uninstallAllKlauerHooks(global, skipConfirmation, purge)
 	}
 
-	return executeUninstallSpecificHook(hookType, global)
+	return executeUninstallSpecificHook(hookType, global, scope, purge)
+}
+
+// parseUninstallScope reads --event and --matcher, resolving Cursor event
+// aliases and rejecting unknown events
+func parseUninstallScope(cmd *cli.Command, isValidEventType func(string) bool, validEventTypes func() []string) (uninstallScope, error) {
+	scope := uninstallScope{
+		event:   strings.TrimSpace(cmd.String("event")),
+		matcher: core.ExpandMCPMatcher(cmd.String("matcher")),
+	}
+	if scope.event == "" {
+		return scope, nil
+	}
+	if resolved := core.ResolveEventAlias(scope.event); resolved != "" {
+		scope.event = resolved
+	}
+	if !isValidEventType(scope.event) {
+		return uninstallScope{}, fmt.Errorf("invalid event '%s'.\nValid events: %s\nUse 'hooks list --events' to see all available events with descriptions", scope.event, strings.Join(validEventTypes(), ", "))
+	}
+	return scope, nil
 }
 
 // newHooksUninstallCommand creates the uninstall command.
-func newHooksUninstallCommand() *cli.Command {
+func newHooksUninstallCommand(isValidEventType func(string) bool, validEventTypes func() []string) *cli.Command {
 	return &cli.Command{
-		Name:        "uninstall",
-		Usage:       "Remove a hook type from Claude Code settings",
-		ArgsUsage:   "[hook-type|all]",
-		Description: `Remove a hook type from your Claude Code settings.json file. Use 'all' to remove all blues-traveler hooks.`,
+		Name:      "uninstall",
+		Usage:     "Remove a hook type from Claude Code settings",
+		ArgsUsage: "[hook-type|all]",
+		ShellComplete: completeArgs(func() []string {
+			keys := builtinPluginKeys(core.GetHookKeys())
+			sort.Strings(keys)
+			return append(keys, "all")
+		}),
+		Description: `Remove a hook type from your Claude Code settings.json file. Use 'all' to remove all blues-traveler hooks.
+
+By default the hook type is removed from every event and matcher. --event and
+--matcher narrow that; the matcher must equal the installed one exactly:
+
+  blues-traveler hooks uninstall security --event PostToolUse --matcher "Edit,Write"`,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "global",
@@ -360,6 +506,20 @@ func newHooksUninstallCommand() *cli.Command {
 				Value:   false,
 				Usage:   "Skip interactive confirmation for 'uninstall all'",
 			},
+			&cli.StringFlag{
+				Name:    "event",
+				Aliases: []string{"e"},
+				Usage:   "Only remove the hook from this event",
+			},
+			&cli.StringFlag{
+				Name:    "matcher",
+				Aliases: []string{"m"},
+				Usage:   "Only remove the hook from entries with this exact matcher",
+			},
+			&cli.BoolFlag{
+				Name:  "purge-artifacts",
+				Usage: "Also remove sample files the hook's install created, unless edited since",
+			},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
 			args := cmd.Args().Slice()
@@ -368,10 +528,16 @@ func newHooksUninstallCommand() *cli.Command {
 			}
 			hookType := args[0]
 
+			scope, err := parseUninstallScope(cmd, isValidEventType, validEventTypes)
+			if err != nil {
+				return err
+			}
 			return executeUninstallCommand(
 				hookType,
 				cmd.Bool("global"),
 				cmd.Bool("yes"),
+				scope,
+				cmd.Bool("purge-artifacts"),
 			)
 		},
 	}
@@ -407,19 +573,14 @@ https://api.github.com/repos/*/*/contents/*|Use 'gh api' for authenticated GitHu
 
 // determineBlockedUrlsDir determines the target directory for blocked-urls.txt.
 func determineBlockedUrlsDir(global bool) (string, string, error) {
-	if global {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", "", err
-		}
-		return filepath.Join(homeDir, ".claude"), constants.ScopeGlobal, nil
-	}
-
-	cwd, err := os.Getwd()
+	dir, err := config.ClaudeDirFor(global)
 	if err != nil {
 		return "", "", err
 	}
-	return filepath.Join(cwd, ".claude"), constants.ScopeProject, nil
+	if global {
+		return dir, constants.ScopeGlobal, nil
+	}
+	return dir, constants.ScopeProject, nil
 }
 
 // ensureBlockedUrlsDir ensures the directory exists.
@@ -432,7 +593,7 @@ func createSampleBlockedUrlsFile(global bool) {
 	// Determine the target directory
 	targetDir, scope, err := determineBlockedUrlsDir(global)
 	if err != nil {
-		fmt.Printf("⚠️  Could not create sample blocked-urls.txt: %v\n", err)
+		output.Printf("⚠️  Could not create sample blocked-urls.txt: %v\n", err)
 		return
 	}
 
@@ -440,30 +601,34 @@ func createSampleBlockedUrlsFile(global bool) {
 
 	// Check if file already exists
 	if _, err := os.Stat(blockedUrlsPath); err == nil {
-		fmt.Printf("📄 Sample blocked-urls.txt already exists: %s\n", blockedUrlsPath)
+		output.Printf("📄 Sample blocked-urls.txt already exists: %s\n", blockedUrlsPath)
 		return
 	}
 
 	// Ensure the .claude directory exists
 	if err := ensureBlockedUrlsDir(targetDir); err != nil {
-		fmt.Printf("⚠️  Could not create .claude directory: %v\n", err)
+		output.Printf("⚠️  Could not create .claude directory: %v\n", err)
 		return
 	}
 
 	// Write the sample file
 	sampleContent := getBlockedUrlsSampleContent()
 	if err := os.WriteFile(blockedUrlsPath, []byte(sampleContent), 0o600); err != nil {
-		fmt.Printf("⚠️  Could not create sample blocked-urls.txt: %v\n", err)
+		output.Printf("⚠️  Could not create sample blocked-urls.txt: %v\n", err)
 		return
 	}
 
-	fmt.Printf("📄 Created sample blocked-urls.txt (%s): %s\n", scope, blockedUrlsPath)
-	fmt.Printf("   Edit this file to add your own blocked URL prefixes.\n")
+	if err := config.RecordSampleArtifact(global, "fetch-blocker", blockedUrlsPath, []byte(sampleContent)); err != nil {
+		output.Printf("⚠️  Could not track sample blocked-urls.txt for cleanup: %v\n", err)
+	}
+
+	output.Printf("📄 Created sample blocked-urls.txt (%s): %s\n", scope, blockedUrlsPath)
+	output.Printf("   Edit this file to add your own blocked URL prefixes.\n")
 }
 
 // promptUninstallAllConfirmation prompts user for confirmation to uninstall all hooks.
 func promptUninstallAllConfirmation(scope string) bool {
-	fmt.Printf("Continue? (y/N): ")
+	output.Print(output.T("hooks.uninstall_all.confirm", nil))
 	var response string
 	_, _ = fmt.Scanln(&response)
 	return response == "y" || response == "Y" || response == "yes"
@@ -471,20 +636,25 @@ func promptUninstallAllConfirmation(scope string) bool {
 
 // displayUninstallAllSummary displays what will be removed before confirmation
 func displayUninstallAllSummary(totalHooks int, scope string, settings *config.Settings) {
-	fmt.Printf("Found %d blues-traveler hooks in %s settings:\n\n", totalHooks, scope)
+	output.Say("hooks.uninstall_all.summary", map[string]any{"Count": totalHooks, "Scope": scope})
 	config.PrintBluesTravelerToRemove(settings)
-	fmt.Printf("\nThis will remove ALL blues-traveler hooks from %s settings.\n", scope)
-	fmt.Printf("Other hooks (not from blues-traveler) will be preserved.\n")
+	output.Say("hooks.uninstall_all.warning", map[string]any{"Scope": scope})
 }
 
 // uninstallAllKlauerHooks removes all blues-traveler hooks from settings
-func uninstallAllKlauerHooks(global bool, skipConfirmation bool) error {
+func uninstallAllKlauerHooks(global, skipConfirmation, purge bool) error {
 	// Get settings path
 	settingsPath, err := config.GetSettingsPath(global)
 	if err != nil {
 		return fmt.Errorf("failed to get settings path: %w", err)
 	}
 
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Load existing settings
 	settings, err := config.LoadSettings(settingsPath)
 	if err != nil {
@@ -500,16 +670,25 @@ func uninstallAllKlauerHooks(global bool, skipConfirmation bool) error {
 	totalHooksBefore := config.CountBluesTravelerInSettings(settings)
 
 	if totalHooksBefore == 0 {
-		fmt.Printf("No blues-traveler hooks found in %s settings.\n", scope)
+		output.Say("hooks.uninstall_all.none", map[string]any{"Scope": scope})
 		return nil
 	}
 
+	// Abort before prompting if the settings file cannot be rewritten safely
+	target, err := config.SettingsPreflightTarget(global)
+	if err != nil {
+		return fmt.Errorf("failed to resolve settings location: %w", err)
+	}
+	if err := config.Preflight("uninstall all", target); err != nil {
+		return err
+	}
+
 	// Show what will be removed
 	displayUninstallAllSummary(totalHooksBefore, scope, settings)
 
 	// Confirmation prompt
 	if !skipConfirmation && !promptUninstallAllConfirmation(scope) {
-		fmt.Println("Operation cancelled.")
+		output.Say("hooks.uninstall_all.cancelled", nil)
 		return nil
 	}
 
@@ -517,7 +696,7 @@ func uninstallAllKlauerHooks(global bool, skipConfirmation bool) error {
 	removed := config.RemoveAllBluesTravelerFromSettings(settings)
 
 	if removed == 0 {
-		fmt.Printf("No blues-traveler hooks were found to remove.\n")
+		output.Say("hooks.uninstall_all.nothing_removed", nil)
 		return nil
 	}
 
@@ -525,14 +704,11 @@ func uninstallAllKlauerHooks(global bool, skipConfirmation bool) error {
 	if err := config.SaveSettings(settingsPath, settings); err != nil {
 		return fmt.Errorf("failed to save settings to %s: %w", settingsPath, err)
 	}
+	forgetBuiltinInstalls(global, "", "", "")
 
-	fmt.Printf("✅ Successfully removed %d blues-traveler hooks from %s settings\n", removed, scope)
-	fmt.Printf("   Settings: %s\n", settingsPath)
-
-	globalFlag := ""
-	if global {
-		globalFlag = " --global"
+	output.Say("hooks.uninstall_all.success", map[string]any{"Count": removed, "Scope": scope, "Settings": settingsPath, "Global": global})
+	if purge {
+		return purgeSampleArtifacts(global, "", false)
 	}
-	fmt.Printf("\nUse 'blues-traveler hooks list --installed%s' to verify the changes.\n", globalFlag)
 	return nil
 }
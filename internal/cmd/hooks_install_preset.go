@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/klauern/blues-traveler/internal/presets"
+	"github.com/urfave/cli/v3"
+)
+
+// presetInstall is one hook of a preset, resolved and ready to write
+type presetInstall struct {
+	key     string
+	flags   installFlags
+	command string
+}
+
+// newHooksInstallPresetCommand creates the 'hooks install preset' subcommand
+func newHooksInstallPresetCommand(
+	getPlugin func(string) (PluginProvider, bool),
+	isValidEventType func(string) bool,
+	validEventTypes func() []string,
+) *cli.Command {
+	return &cli.Command{
+		Name:      "preset",
+		Usage:     "Install a curated bundle of hooks",
+		ArgsUsage: "<preset>",
+		Description: `Install every hook of a preset with the events and matchers it was designed
+for, in a single settings.json update. Installing a preset again replaces its
+entries according to the merge policy. Use --list to see the presets.
+
+Examples:
+  blues-traveler hooks install preset --list
+  blues-traveler hooks install preset go-dev
+  blues-traveler hooks install preset secure-defaults --global`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "list", Usage: "List available presets and their hooks"},
+			&cli.BoolFlag{Name: "global", Aliases: []string{"g"}, Usage: "Install to global settings (~/.claude/settings.json)"},
+			&cli.IntFlag{Name: "timeout", Aliases: []string{"t"}, Usage: "Command timeout in seconds for every hook (0 for no timeout)"},
+			&cli.BoolFlag{Name: "log", Aliases: []string{"l"}, Usage: "Enable detailed logging for every hook"},
+			&cli.StringFlag{Name: "log-format", Value: "jsonl", Usage: "Log output format: jsonl or pretty"},
+			&cli.StringFlag{Name: "merge-policy", Usage: "When a hook type is already installed: by-hook-type, exact or never-replace"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("list") {
+				listPresets()
+				return nil
+			}
+			if cmd.Args().Len() != 1 {
+				return fmt.Errorf("exactly one preset name required\n  Suggestion: Run 'blues-traveler hooks install preset --list' to see presets")
+			}
+			name := cmd.Args().First()
+			p, ok := presets.Get(name)
+			if !ok {
+				return fmt.Errorf("unknown preset '%s'\n  Suggestion: Available presets: %s", name, strings.Join(presets.Names(), ", "))
+			}
+			flags, err := parseInstallFlags(cmd)
+			if err != nil {
+				return err
+			}
+			return installHookBundle("preset", p.Name, p.Hooks, flags, getPlugin, isValidEventType, validEventTypes)
+		},
+	}
+}
+
+func listPresets() {
+	for _, p := range presets.All() {
+		output.Printf("%s\n  %s\n", p.Name, p.Description)
+		for _, h := range p.Hooks {
+			output.Printf("    • %s", h.Key)
+			if h.Event != "" {
+				output.Printf(" (%s %s)", h.Event, h.Matcher)
+			}
+			output.Println()
+		}
+		output.Println()
+	}
+}
+
+// installHookBundle writes every hook of a preset or template (kind) to
+// settings in one locked update. All hooks are resolved first so an unknown
+// key or event changes nothing.
+func installHookBundle(
+	kind, name string,
+	hooks []presets.Hook,
+	flags installFlags,
+	getPlugin func(string) (PluginProvider, bool),
+	isValidEventType func(string) bool,
+	validEventTypes func() []string,
+) error {
+	planned := make([]presetInstall, 0, len(hooks))
+	for _, h := range hooks {
+		plugin, ok := getPlugin(h.Key)
+		if !ok {
+			return fmt.Errorf("%s '%s' uses unknown hook '%s'", kind, name, h.Key)
+		}
+		f := flags
+		f.event, f.matcher = h.Event, h.Matcher
+		applyManifestDefaults(&f, plugin.Manifest())
+		event, err := resolveAndValidateEvent(f.event, isValidEventType, validEventTypes)
+		if err != nil {
+			return err
+		}
+		f.event = event
+		command, err := buildInstallHookCommand(h.Key, f)
+		if err != nil {
+			return err
+		}
+		planned = append(planned, presetInstall{key: h.Key, flags: f, command: command})
+	}
+
+	settingsPath, err := config.GetSettingsPath(flags.global)
+	if err != nil {
+		return fmt.Errorf("failed to locate settings path: %w\n  Suggestion: Run 'blues-traveler hooks init' to initialize the project", err)
+	}
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	settings, err := loadAndValidateSettings(settingsPath)
+	if err != nil {
+		return err
+	}
+
+	var timeout *int
+	if flags.timeout > 0 {
+		timeout = &flags.timeout
+	}
+	unchanged := make([]bool, len(planned))
+	changed := 0
+	for i, pi := range planned {
+		result := config.AddHookToSettingsWithPolicy(settings, pi.flags.event, pi.flags.matcher, pi.command, timeout, pi.flags.mergePolicy)
+		unchanged[i] = result.WasDuplicate && !strings.Contains(result.DuplicateInfo, "Replaced existing")
+		if !unchanged[i] {
+			changed++
+		}
+	}
+	if err := saveSettingsIfNeeded(settingsPath, settings, changed == 0); err != nil {
+		return err
+	}
+
+	output.Printf("✅ Installed %s '%s' in %s\n", kind, name, settingsPath)
+	for i, pi := range planned {
+		note := ""
+		if unchanged[i] {
+			note = " (already installed)"
+		}
+		output.Printf("  • %-15s %-12s %s%s\n", pi.key, pi.flags.event, pi.flags.matcher, note)
+		recordBuiltinInstall(pi.flags, settings, pi.key, pi.command, timeout)
+		performPostInstallActions(pi.key, flags.global)
+	}
+	output.Say("hooks.install.next", nil)
+	return nil
+}
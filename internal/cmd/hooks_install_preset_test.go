@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/presets"
+)
+
+// presetTestPlugin is a built-in hook with only a manifest
+type presetTestPlugin struct {
+	*core.BaseHook
+	manifest core.Manifest
+}
+
+func (p *presetTestPlugin) Run() error              { return nil }
+func (p *presetTestPlugin) Manifest() core.Manifest { return p.manifest }
+
+func presetTestPlugins(t *testing.T) func(string) (PluginProvider, bool) {
+	t.Helper()
+	return func(key string) (PluginProvider, bool) {
+		if key == "missing" {
+			return nil, false
+		}
+		m := core.Manifest{Key: key, DefaultEvent: "PreToolUse", DefaultMatcher: "Bash"}
+		return &presetTestPlugin{BaseHook: core.NewBaseHook(key, key, "", core.TestHookContext(nil)), manifest: m}, true
+	}
+}
+
+func TestInstallPreset(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	p := presets.Preset{Name: "test", Hooks: []presets.Hook{
+		{Key: "format", Event: "PostToolUse", Matcher: "Edit|Write"},
+		{Key: "security"},
+	}}
+	if err := installHookBundle("preset", p.Name, p.Hooks, installFlags{}, presetTestPlugins(t), core.IsValidEventType, core.ValidEventTypes); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := config.LoadSettings(filepath.Join(dir, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, pre := settings.Hooks.PostToolUse, settings.Hooks.PreToolUse
+	if len(post) != 1 || post[0].Matcher != "Edit|Write" || !strings.HasSuffix(post[0].Hooks[0].Command, "hooks run format") {
+		t.Errorf("unexpected PostToolUse hooks %+v", post)
+	}
+	if len(pre) != 1 || pre[0].Matcher != "Bash" || !strings.HasSuffix(pre[0].Hooks[0].Command, "hooks run security") {
+		t.Errorf("unexpected PreToolUse hooks %+v", pre)
+	}
+
+	// Installing again leaves settings as they are
+	before, _ := os.ReadFile(filepath.Join(dir, ".claude", "settings.json"))
+	if err := installHookBundle("preset", p.Name, p.Hooks, installFlags{}, presetTestPlugins(t), core.IsValidEventType, core.ValidEventTypes); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, ".claude", "settings.json"))
+	if string(before) != string(after) {
+		t.Error("reinstalling the preset should not change settings")
+	}
+}
+
+func TestInstallPreset_UnknownHookChangesNothing(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	p := presets.Preset{Name: "broken", Hooks: []presets.Hook{{Key: "security"}, {Key: "missing"}}}
+	if err := installHookBundle("preset", p.Name, p.Hooks, installFlags{}, presetTestPlugins(t), core.IsValidEventType, core.ValidEventTypes); err == nil {
+		t.Fatal("expected an error for the unknown hook")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude", "settings.json")); !os.IsNotExist(err) {
+		t.Error("settings should not be written when a preset hook is unknown")
+	}
+}
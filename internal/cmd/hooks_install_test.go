@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
 )
 
 func TestBuildInstallHookCommand(t *testing.T) {
@@ -110,3 +113,88 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestApplyManifestDefaults(t *testing.T) {
+	m := core.Manifest{Key: "format", Events: []string{"PostToolUse"}, DefaultEvent: "PostToolUse", DefaultMatcher: "Edit|Write"}
+
+	flags := installFlags{}
+	applyManifestDefaults(&flags, m)
+	if flags.event != "PostToolUse" || flags.matcher != "Edit|Write" {
+		t.Errorf("expected manifest defaults, got event=%q matcher=%q", flags.event, flags.matcher)
+	}
+
+	flags = installFlags{event: "afterFileEdit", matcher: "Write"}
+	applyManifestDefaults(&flags, m)
+	if flags.event != "afterFileEdit" || flags.matcher != "Write" {
+		t.Errorf("explicit flags must win, got event=%q matcher=%q", flags.event, flags.matcher)
+	}
+}
+
+func TestExecuteUninstallCommand_Scoped(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	settingsPath, err := config.GetSettingsPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := &config.Settings{}
+	config.AddHookToSettings(settings, "PreToolUse", "*", "/bin/blues-traveler hooks run security", nil)
+	config.AddHookToSettings(settings, "PostToolUse", "Edit,Write", "/bin/blues-traveler hooks run security", nil)
+	config.AddHookToSettings(settings, "PostToolUse", "Bash", "/bin/blues-traveler hooks run security", nil)
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	scope := uninstallScope{event: "PostToolUse", matcher: "Edit,Write"}
+	if err := executeUninstallCommand("all", false, true, scope, false); err == nil {
+		t.Error("expected --event with 'all' to be rejected")
+	}
+	if err := executeUninstallCommand("security", false, false, scope, false); err != nil {
+		t.Fatalf("scoped uninstall: %v", err)
+	}
+	if err := executeUninstallCommand("security", false, false, scope, false); err == nil {
+		t.Error("expected an error once nothing is left in the scope")
+	}
+
+	got, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Hooks.PreToolUse) != 1 || len(got.Hooks.PostToolUse) != 1 || got.Hooks.PostToolUse[0].Matcher != "Bash" {
+		t.Errorf("expected only the Edit,Write entry removed, got pre=%+v post=%+v", got.Hooks.PreToolUse, got.Hooks.PostToolUse)
+	}
+}
+
+func TestExecuteUninstallCommand_PurgeArtifacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	settingsPath, err := config.GetSettingsPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := &config.Settings{}
+	config.AddHookToSettings(settings, "PreToolUse", "WebFetch", "/bin/blues-traveler hooks run fetch-blocker", nil)
+	config.AddHookToSettings(settings, "PreToolUse", "*", "/bin/blues-traveler hooks run fetch-blocker", nil)
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+	createSampleBlockedUrlsFile(false)
+	dir, err := config.ClaudeDirFor(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample := filepath.Join(dir, "blocked-urls.txt")
+
+	if err := executeUninstallCommand("fetch-blocker", false, false, uninstallScope{event: "PreToolUse", matcher: "*"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sample); err != nil {
+		t.Fatalf("sample must stay while the hook is still installed: %v", err)
+	}
+	if err := executeUninstallCommand("fetch-blocker", false, false, uninstallScope{}, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sample); !os.IsNotExist(err) {
+		t.Errorf("expected the sample removed with the last install, stat err %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksLatencyCommand creates the latency command
+func newHooksLatencyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "latency",
+		Usage: "Show how much time hooks add to each event in this project",
+		Description: `Totals the hook runs recorded with "metrics": true in blues-traveler-config.json
+by event, the same records 'hooks stats' reads. When "latencyThreshold" is set
+(e.g. "1.5s"), SLOW counts runs at or over it, and responses from hooks that
+slow note how long they took.
+
+Examples:
+  blues-traveler hooks latency
+  blues-traveler hooks latency --since 24h
+  blues-traveler hooks latency --reset`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "since", Value: "7d", Usage: "Only runs newer than a duration or timestamp (\"all\" for every run)"},
+			&cli.BoolFlag{
+				Name:  "reset",
+				Value: false,
+				Usage: "Delete recorded runs, as 'hooks stats --reset' does",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("reset") {
+				if err := core.ResetHookRunRecords(); err != nil {
+					return err
+				}
+				output.Println("✅ Hook metrics reset")
+				return nil
+			}
+			var since time.Time
+			if s := cmd.String("since"); s != "all" {
+				var err error
+				if since, err = parseSince(s, time.Now()); err != nil {
+					return err
+				}
+			}
+			records, err := core.LoadHookRunRecords(since)
+			if err != nil {
+				return err
+			}
+			showLatencyStats(records, config.GetLatencyThreshold(), config.GetMetricsEnabled())
+			return nil
+		},
+	}
+}
+
+// showLatencyStats prints the time recorded runs added per event, slowest
+// events first
+func showLatencyStats(records []core.HookRunRecord, threshold time.Duration, enabled bool) {
+	if !enabled {
+		output.Println("⚠️  Recording is off. Set \"metrics\": true in blues-traveler-config.json to record hook runs.")
+	}
+	// Runs that exited before reading a payload have no event to add to
+	var timed []core.HookRunRecord
+	slow := map[string]int{}
+	for _, rec := range records {
+		if rec.Event == "" {
+			continue
+		}
+		timed = append(timed, rec)
+		if threshold > 0 && rec.DurationMs >= threshold.Milliseconds() {
+			slow[rec.Event]++
+		}
+	}
+	if len(timed) == 0 {
+		output.Println("No hook latency recorded in this window.")
+		return
+	}
+
+	output.Println("⏱️  Hook latency by event:")
+	output.Printf("  %-18s %6s %10s %8s %8s %8s %6s\n", "EVENT", "RUNS", "TOTAL", "AVG", "P95", "MAX", "SLOW")
+	for _, s := range core.SummarizeHookRuns(timed, false, true) {
+		output.Printf("  %-18s %6d %10s %8s %8s %8s %6d\n", s.Event, s.Runs, formatMillis(s.TotalMs),
+			formatMillis(s.AverageMs()), formatMillis(s.P95Ms), formatMillis(s.MaxMs), slow[s.Event])
+	}
+	if threshold > 0 {
+		output.Printf("\nSLOW counts runs of %s or more.\n", threshold)
+	}
+}
+
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,525 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// Terminal control sequences used by the manage screen
+const (
+	ansiAltScreenOn  = "\x1b[?1049h"
+	ansiAltScreenOff = "\x1b[?1049l"
+	ansiHideCursor   = "\x1b[?25l"
+	ansiShowCursor   = "\x1b[?25h"
+	ansiClear        = "\x1b[H\x1b[2J"
+	ansiReverse      = "\x1b[7m"
+	ansiReset        = "\x1b[0m"
+)
+
+// manageHelp is the key reference shown under the list
+const manageHelp = "↑/↓ move  space toggle  m matcher  t timeout  d uninstall  q save & quit  Q quit without saving"
+
+// newHooksManageCommand creates the interactive manage command
+func newHooksManageCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "manage",
+		Usage: "Interactively enable, edit and uninstall installed hooks",
+		Description: `Open a full-screen list of the hooks in settings.json, grouped by event.
+Move with the arrow keys (or j/k), then:
+
+  space   enable or disable the selected blues-traveler hook (every entry of that hook)
+  m       change the selected hook's matcher
+  t       change its timeout in seconds (empty clears it)
+  d       uninstall it
+  q       save the changes and quit
+  Q       quit without saving (also Ctrl+C)
+
+Changes are written atomically when you quit with q. If settings.json changed
+on disk while the screen was open, nothing is written.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "global",
+				Aliases: []string{"g"},
+				Usage:   "Manage global settings (~/.claude/settings.json)",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			return runHooksManage(cmd.Bool("global"))
+		},
+	}
+}
+
+func runHooksManage(global bool) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd()) // #nosec G115 - file descriptors fit in int
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return fmt.Errorf("'hooks manage' needs an interactive terminal\n  Suggestion: Use 'hooks list --installed', 'hooks install' and 'hooks uninstall' in scripts")
+	}
+	settingsPath, err := config.GetSettingsPath(global)
+	if err != nil {
+		return fmt.Errorf("failed to locate settings path: %w", err)
+	}
+	original, err := os.ReadFile(settingsPath) // #nosec G304 - settings path from GetSettingsPath
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", settingsPath, err)
+	}
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load settings from %s: %w\n  Suggestion: Verify the settings file format is valid JSON", settingsPath, err)
+	}
+	scope := constants.ScopeProject
+	if global {
+		scope = constants.ScopeGlobal
+	}
+	m := newManageModel(settings, settingsPath, scope)
+	if len(m.entries) == 0 {
+		output.Printf("No hooks installed in %s\n", settingsPath)
+		return nil
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to open the terminal: %w", err)
+	}
+	screen := bufio.NewWriter(os.Stdout)
+	_, _ = screen.WriteString(ansiAltScreenOn + ansiHideCursor)
+	err = m.loop(bufio.NewReader(os.Stdin), screen, func() (int, int) {
+		if w, h, err := term.GetSize(outFd); err == nil && w > 0 && h > 0 {
+			return w, h
+		}
+		return 80, 24
+	})
+	_, _ = screen.WriteString(ansiShowCursor + ansiAltScreenOff)
+	_ = screen.Flush()
+	_ = term.Restore(inFd, state)
+	if err != nil {
+		return err
+	}
+
+	if !m.save || !m.dirty {
+		output.Println("No changes written.")
+		return nil
+	}
+	return saveManagedSettings(settingsPath, original, m, global)
+}
+
+// saveManagedSettings writes m's settings unless settings.json changed on
+// disk since it was read
+func saveManagedSettings(settingsPath string, original []byte, m *manageModel, global bool) error {
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	current, err := os.ReadFile(settingsPath) // #nosec G304 - settings path from GetSettingsPath
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", settingsPath, err)
+	}
+	if !bytes.Equal(current, original) {
+		return fmt.Errorf("%s changed while it was being edited; nothing was written\n  Suggestion: Run 'blues-traveler hooks manage' again", settingsPath)
+	}
+	if err := config.SaveSettings(settingsPath, m.settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	for _, hookType := range m.uninstalledTypes() {
+		forgetBuiltinInstalls(global, hookType, "", "")
+	}
+	output.Printf("✅ Saved %d change(s) to %s\n", m.changes, settingsPath)
+	return nil
+}
+
+// managePrompt is a question shown at the bottom of the screen. Confirm
+// prompts take y or n; others read a line of text.
+type managePrompt struct {
+	label   string
+	value   string
+	confirm bool
+	apply   func(value string)
+}
+
+// manageModel is the state of the manage screen, kept apart from the
+// terminal so key handling can be tested
+type manageModel struct {
+	settings *config.Settings
+	path     string
+	scope    string
+	entries  []config.SettingsEntry
+	cursor   int
+	prompt   *managePrompt
+	status   string
+	dirty    bool
+	changes  int
+	done     bool
+	save     bool
+	// removed holds the hook types of uninstalled entries
+	removed []string
+}
+
+func newManageModel(settings *config.Settings, path, scope string) *manageModel {
+	m := &manageModel{settings: settings, path: path, scope: scope}
+	m.refresh()
+	return m
+}
+
+// refresh re-reads the entries after an edit, keeping the cursor in range
+func (m *manageModel) refresh() {
+	m.entries = config.SettingsEntries(m.settings.Hooks)
+	if m.cursor >= len(m.entries) {
+		m.cursor = len(m.entries) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *manageModel) changed(status string) {
+	m.dirty = true
+	m.changes++
+	m.status = status
+	m.refresh()
+}
+
+// uninstalledTypes lists removed hook types with no entries left
+func (m *manageModel) uninstalledTypes() []string {
+	left := map[string]bool{}
+	for _, e := range m.entries {
+		left[e.HookType] = true
+	}
+	var types []string
+	seen := map[string]bool{}
+	for _, t := range m.removed {
+		if t != "" && !left[t] && !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// loop draws the screen and handles keys until the user quits
+func (m *manageModel) loop(in *bufio.Reader, out *bufio.Writer, size func() (int, int)) error {
+	for !m.done {
+		w, h := size()
+		_, _ = out.WriteString(ansiClear)
+		m.render(out, w, h)
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		key, err := readKey(in)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		m.handleKey(key)
+	}
+	return nil
+}
+
+// handleKey applies one key press
+func (m *manageModel) handleKey(key string) {
+	if p := m.prompt; p != nil {
+		switch {
+		case p.confirm:
+			m.prompt = nil
+			if key == "y" || key == "Y" {
+				p.apply("y")
+			} else {
+				m.status = "Cancelled"
+			}
+		case key == "enter":
+			m.prompt = nil
+			p.apply(p.value)
+		case key == "esc" || key == "ctrl-c":
+			m.prompt = nil
+			m.status = "Cancelled"
+		case key == "backspace":
+			if _, size := utf8.DecodeLastRuneInString(p.value); size > 0 {
+				p.value = p.value[:len(p.value)-size]
+			}
+		case utf8.RuneCountInString(key) == 1:
+			p.value += key
+		}
+		return
+	}
+
+	m.status = ""
+	switch key {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.entries) - 1
+	case "q":
+		m.done, m.save = true, true
+	case "Q", "ctrl-c":
+		m.done = true
+	case " ", "e":
+		m.toggle()
+	case "m":
+		m.editMatcher()
+	case "t":
+		m.editTimeout()
+	case "d", "x":
+		m.uninstall()
+	}
+}
+
+func (m *manageModel) selected() (config.SettingsEntry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return config.SettingsEntry{}, false
+	}
+	return m.entries[m.cursor], true
+}
+
+func (m *manageModel) toggle() {
+	e, ok := m.selected()
+	if !ok {
+		return
+	}
+	if e.HookType == "" {
+		m.status = "Only blues-traveler hooks can be disabled; uninstall other commands with d"
+		return
+	}
+	enabled := !m.settings.IsPluginEnabled(e.HookType)
+	m.settings.SetPluginEnabled(e.HookType, enabled)
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	m.changed(fmt.Sprintf("%s %s", e.HookType, state))
+}
+
+func (m *manageModel) editMatcher() {
+	e, ok := m.selected()
+	if !ok {
+		return
+	}
+	m.prompt = &managePrompt{label: "Matcher", value: e.Matcher, apply: func(value string) {
+		value = core.ExpandMCPMatcher(strings.TrimSpace(value))
+		if value == e.Matcher {
+			return
+		}
+		if m.settings.SetHookMatcher(e, value) {
+			m.changed(fmt.Sprintf("Matcher set to %q", value))
+		}
+	}}
+}
+
+func (m *manageModel) editTimeout() {
+	e, ok := m.selected()
+	if !ok {
+		return
+	}
+	current := ""
+	if e.Timeout != nil {
+		current = strconv.Itoa(*e.Timeout)
+	}
+	m.prompt = &managePrompt{label: "Timeout (seconds, empty to clear)", value: current, apply: func(value string) {
+		value = strings.TrimSpace(value)
+		var timeout *int
+		if value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				m.status = fmt.Sprintf("Invalid timeout %q: use a positive number of seconds", value)
+				return
+			}
+			timeout = &n
+		}
+		if m.settings.SetHookTimeout(e, timeout) {
+			m.changed("Timeout updated")
+		}
+	}}
+}
+
+func (m *manageModel) uninstall() {
+	e, ok := m.selected()
+	if !ok {
+		return
+	}
+	m.prompt = &managePrompt{label: fmt.Sprintf("Uninstall %s from %s? (y/n)", manageEntryName(e), e.Event), confirm: true, apply: func(string) {
+		if m.settings.RemoveHookAt(e) {
+			m.removed = append(m.removed, e.HookType)
+			m.changed(fmt.Sprintf("Uninstalled %s", manageEntryName(e)))
+		}
+	}}
+}
+
+// manageEntryName is the hook key, or the command for other hooks
+func manageEntryName(e config.SettingsEntry) string {
+	if e.HookType != "" {
+		return e.HookType
+	}
+	return e.Command
+}
+
+// render draws the screen in width x height cells. Lines end in \r\n
+// because the terminal is in raw mode.
+func (m *manageModel) render(w io.Writer, width, height int) {
+	if width < 20 {
+		width = 20
+	}
+	header := []string{
+		fit(fmt.Sprintf("blues-traveler hooks manage: %s (%s)", m.path, m.scope), width),
+		"",
+	}
+	var footer []string
+	footer = append(footer, "")
+	if m.prompt != nil {
+		line := m.prompt.label
+		if !m.prompt.confirm {
+			line += ": " + m.prompt.value + "█"
+		}
+		footer = append(footer, fit(line, width))
+	} else {
+		footer = append(footer, fit(m.status, width))
+	}
+	footer = append(footer, fit(manageHelp, width))
+
+	var body []string
+	selectedLine := 0
+	event := ""
+	for i, e := range m.entries {
+		if e.Event != event {
+			event = e.Event
+			body = append(body, fit(event, width))
+		}
+		if i == m.cursor {
+			selectedLine = len(body)
+		}
+		body = append(body, m.entryLine(i, e, width))
+	}
+
+	// Scroll so the selection stays on screen
+	rows := height - len(header) - len(footer)
+	if rows < 1 {
+		rows = 1
+	}
+	start := 0
+	if selectedLine >= rows {
+		start = selectedLine - rows + 1
+	}
+	end := start + rows
+	if end > len(body) {
+		end = len(body)
+	}
+
+	lines := append(append([]string{}, header...), body[start:end]...)
+	for i := end - start; i < rows; i++ {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer...)
+	_, _ = io.WriteString(w, strings.Join(lines, "\r\n"))
+}
+
+// entryLine formats one hook row
+func (m *manageModel) entryLine(i int, e config.SettingsEntry, width int) string {
+	state := "   "
+	if e.HookType != "" {
+		state = "[x]"
+		if !m.settings.IsPluginEnabled(e.HookType) {
+			state = "[ ]"
+		}
+	}
+	matcher := e.Matcher
+	if matcher == "" {
+		matcher = "*"
+	}
+	timeout := "-"
+	if e.Timeout != nil {
+		timeout = fmt.Sprintf("%ds", *e.Timeout)
+	}
+	line := fit(fmt.Sprintf("  %s %-18s %-20s %5s  %s", state, fit(manageEntryName(e), 18), fit(matcher, 20), timeout, e.Command), width)
+	if i == m.cursor {
+		if output.IsPlain() {
+			return ">" + line[1:]
+		}
+		return ansiReverse + line + ansiReset
+	}
+	return line
+}
+
+// fit truncates s to width runes, marking the cut with an ellipsis
+func fit(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
+
+// readKey reads one key press from a raw terminal: a printable character,
+// or a name such as "up", "enter", "backspace", "esc" or "ctrl-c"
+func readKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		next, _ := r.ReadByte()
+		if next != '[' && next != 'O' {
+			return "esc", nil
+		}
+		code, _ := r.ReadByte()
+		switch code {
+		case 'A':
+			return "up", nil
+		case 'B':
+			return "down", nil
+		case 'H':
+			return "home", nil
+		case 'F':
+			return "end", nil
+		}
+		// Skip the rest of sequences such as "\x1b[3~"
+		for code >= '0' && code <= '9' || code == ';' {
+			if code, err = r.ReadByte(); err != nil {
+				break
+			}
+		}
+		return "", nil
+	case '\r', '\n':
+		return "enter", nil
+	case 0x7f, 0x08:
+		return "backspace", nil
+	case 0x03:
+		return "ctrl-c", nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	if !unicode.IsPrint(c) {
+		return "", nil
+	}
+	return string(c), nil
+}
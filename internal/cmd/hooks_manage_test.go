@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestManageModel(t *testing.T) {
+	timeout := 10
+	settings := &config.Settings{Hooks: config.HooksConfig{
+		PreToolUse: []config.HookMatcher{{Matcher: "Bash", Hooks: []config.HookCommand{
+			{Type: "command", Command: "blues-traveler hooks run security"},
+			{Type: "command", Command: "./scripts/check.sh", Timeout: &timeout},
+		}}},
+		PostToolUse: []config.HookMatcher{{Matcher: "Edit|Write", Hooks: []config.HookCommand{
+			{Type: "command", Command: "blues-traveler hooks run format"},
+		}}},
+	}}
+	m := newManageModel(settings, "settings.json", "project")
+	if len(m.entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", m.entries)
+	}
+	keys := func(ks ...string) {
+		for _, k := range ks {
+			m.handleKey(k)
+		}
+	}
+
+	// Disable security
+	keys(" ")
+	if settings.IsPluginEnabled("security") {
+		t.Fatal("expected security to be disabled")
+	}
+	// Other commands can't be toggled
+	keys("down", " ")
+	if !strings.Contains(m.status, "Only blues-traveler hooks") {
+		t.Errorf("unexpected status %q", m.status)
+	}
+
+	// Move check.sh to its own matcher, then clear its timeout
+	keys("m", "backspace", "backspace", "backspace", "backspace", "W", "r", "i", "t", "e", "enter")
+	if len(settings.Hooks.PreToolUse) != 2 || settings.Hooks.PreToolUse[1].Matcher != "Write" || len(settings.Hooks.PreToolUse[0].Hooks) != 1 {
+		t.Fatalf("expected check.sh under its own Write matcher, got %+v", settings.Hooks.PreToolUse)
+	}
+	m.cursor = 1
+	keys("t", "backspace", "backspace", "enter")
+	if settings.Hooks.PreToolUse[1].Hooks[0].Timeout != nil {
+		t.Errorf("expected the timeout to be cleared")
+	}
+	keys("t", "x", "enter")
+	if !strings.Contains(m.status, "Invalid timeout") {
+		t.Errorf("expected an invalid timeout message, got %q", m.status)
+	}
+
+	// Uninstall format, cancelling once first
+	keys("G", "d", "n")
+	if len(settings.Hooks.PostToolUse) != 1 {
+		t.Fatal("expected n to cancel the uninstall")
+	}
+	keys("d", "y")
+	if len(settings.Hooks.PostToolUse) != 0 || len(m.entries) != 2 || m.cursor != 1 {
+		t.Fatalf("expected format to be removed, got %+v (cursor %d)", settings.Hooks.PostToolUse, m.cursor)
+	}
+	if got := m.uninstalledTypes(); len(got) != 1 || got[0] != "format" {
+		t.Errorf("unexpected uninstalled types %v", got)
+	}
+
+	var b strings.Builder
+	m.render(&b, 100, 10)
+	if !strings.Contains(b.String(), "PreToolUse") || !strings.Contains(b.String(), "[ ] security") {
+		t.Errorf("unexpected screen:\n%s", b.String())
+	}
+
+	keys("q")
+	if !m.done || !m.save || m.changes != 4 {
+		t.Errorf("expected a save with 4 changes, got done=%v save=%v changes=%d", m.done, m.save, m.changes)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[Aj\r\x7fé\x1b[3~\x03"))
+	var got []string
+	for {
+		k, err := readKey(r)
+		if err != nil {
+			break
+		}
+		got = append(got, k)
+	}
+	if strings.Join(got, ",") != "up,j,enter,backspace,é,,ctrl-c" {
+		t.Errorf("unexpected keys %q", got)
+	}
+}
+
+func TestSaveManagedSettings_RefusesConcurrentChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "settings.json")
+	original := []byte(`{"hooks":{"PreToolUse":[{"matcher":"Bash","hooks":[{"type":"command","command":"blues-traveler hooks run security"}]}]}}`)
+	if err := os.WriteFile(path, original, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := config.LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newManageModel(settings, path, "project")
+	m.handleKey(" ")
+
+	if err := os.WriteFile(path, append(original, '\n'), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveManagedSettings(path, original, m, false); err == nil || !strings.Contains(err.Error(), "changed while") {
+		t.Fatalf("expected a concurrent change error, got %v", err)
+	}
+
+	if err := saveManagedSettings(path, append(original, '\n'), m, false); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := config.LoadSettings(path)
+	if err != nil || saved.IsPluginEnabled("security") {
+		t.Fatalf("expected security to be saved as disabled: %v", err)
+	}
+}
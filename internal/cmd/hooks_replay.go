@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// runHookReplay runs the hook for every event captured in path and prints
+// what each run returned. Replays skip the kill switch and snoozes so the
+// hook's own behavior is what gets reproduced.
+func runHookReplay(ctx context.Context, key, path string, enabled bool) error {
+	events, err := core.LoadReplayEvents(path)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		output.Printf("⚠️  '%s' is disabled in the config; it will allow every event without running its checks.\n", key)
+	}
+
+	blocked, failed := 0, 0
+	for i, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("replay cancelled: %w", err)
+		}
+		result, err := core.ReplayHook(ctx, key, ev)
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", ev.Source, err)
+		}
+		switch {
+		case result.Err != nil:
+			failed++
+		case replayBlocked(result):
+			blocked++
+		}
+		printReplayResult(i+1, len(events), result)
+	}
+	output.Printf("\nReplayed %d event(s) through '%s': %d blocked, %d failed.\n", len(events), key, blocked, failed)
+	return nil
+}
+
+// replayBlocked reports whether the hook stopped the tool or the agent,
+// through exit code 2 or a block decision in its JSON response
+func replayBlocked(r *core.ReplayResult) bool {
+	if r.ExitCode == 2 {
+		return true
+	}
+	var resp struct {
+		Decision string `json:"decision"`
+	}
+	if err := json.Unmarshal([]byte(r.Stdout), &resp); err != nil {
+		return false
+	}
+	return resp.Decision == "block"
+}
+
+// printReplayResult shows one replayed event and the hook's response
+func printReplayResult(n, total int, r *core.ReplayResult) {
+	label := r.Event.Event
+	if r.Event.Tool != "" {
+		label += " " + r.Event.Tool
+	}
+	output.Printf("\n▶ [%d/%d] %s (%s)\n", n, total, label, r.Event.Source)
+	output.Printf("  Exit code: %d\n", r.ExitCode)
+	if r.Err != nil {
+		output.Printf("  Error: %v\n", r.Err)
+	}
+	if out := strings.TrimSpace(r.Stdout); out != "" {
+		output.Printf("  Stdout:\n%s\n", indentLines(out, "    "))
+	} else {
+		output.Println("  Stdout: (empty: allow with no message)")
+	}
+	if errOut := strings.TrimSpace(r.Stderr); errOut != "" {
+		output.Printf("  Stderr:\n%s\n", indentLines(errOut, "    "))
+	}
+}
+
+// indentLines prefixes every line of s with prefix
+func indentLines(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestReplayHook_CapturesResponses(t *testing.T) {
+	registerDecisionTestHooks()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	payload := `{"hook_event_name":"PreToolUse","session_id":"s","tool_name":"Bash","tool_input":{"command":"ls"}}`
+	if err := os.WriteFile(path, []byte(payload+"\n"+payload+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	events, err := core.LoadReplayEvents(path)
+	if err != nil {
+		t.Fatalf("LoadReplayEvents: %v", err)
+	}
+
+	// Each event gets a fresh hook, and the runner's exit doesn't end the test
+	for _, ev := range events {
+		result, err := core.ReplayHook(context.Background(), "test-block", ev)
+		if err != nil {
+			t.Fatalf("ReplayHook: %v", err)
+		}
+		if !replayBlocked(result) || !strings.Contains(result.Stdout, "nope") {
+			t.Fatalf("expected a block with the hook's reason, got %+v", result)
+		}
+	}
+
+	result, err := core.ReplayHook(context.Background(), "test-approve", events[0])
+	if err != nil {
+		t.Fatalf("ReplayHook: %v", err)
+	}
+	if replayBlocked(result) || result.ExitCode != 0 || !strings.Contains(result.Stdout, "approve") {
+		t.Fatalf("expected an approval, got %+v", result)
+	}
+}
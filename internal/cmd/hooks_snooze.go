@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksSnoozeCommand creates the snooze command
+func newHooksSnoozeCommand(pluginKeys func() []string) *cli.Command {
+	return &cli.Command{
+		Name:      "snooze",
+		Usage:     "Temporarily silence a hook or custom group in this project",
+		ArgsUsage: "[plugin-key|group]",
+		Description: `Record a snooze window in the project's .claude/settings.json. While snoozed,
+'hooks run' allows immediately without running the hook and logs the skip. The
+snooze expires on its own, so there is nothing to reinstall afterwards.
+
+Examples:
+  blues-traveler hooks snooze format --for 2h
+  blues-traveler hooks snooze my-group --for 30m     # all jobs in a custom group
+  blues-traveler hooks snooze format --clear
+  blues-traveler hooks snooze --list`,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "for",
+				Value: time.Hour,
+				Usage: "How long to snooze (e.g. 30m, 2h)",
+			},
+			&cli.BoolFlag{
+				Name:  "clear",
+				Value: false,
+				Usage: "Remove an existing snooze",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Value: false,
+				Usage: "List active snoozes",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			settingsPath, err := config.GetSettingsPath(false)
+			if err != nil {
+				return fmt.Errorf("error getting settings path: %w", err)
+			}
+			if cmd.Bool("list") {
+				return listSnoozes(settingsPath)
+			}
+
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: [plugin-key|group]")
+			}
+			key, err := resolveSnoozeTarget(args[0], pluginKeys())
+			if err != nil {
+				return err
+			}
+
+			if cmd.Bool("clear") {
+				return clearSnooze(settingsPath, key)
+			}
+			return snoozeHook(settingsPath, key, cmd.Duration("for"))
+		},
+	}
+}
+
+// resolveSnoozeTarget maps a plugin key or custom group name to the settings
+// key its snooze is stored under
+func resolveSnoozeTarget(target string, keys []string) (string, error) {
+	groupPrefix := config.GroupSnoozeKey(strings.TrimPrefix(target, "config:")) + ":"
+	for _, k := range keys {
+		if k == target {
+			return target, nil
+		}
+	}
+	for _, k := range keys {
+		if strings.HasPrefix(k, groupPrefix) {
+			return strings.TrimSuffix(groupPrefix, ":"), nil
+		}
+	}
+	return "", fmt.Errorf("'%s' is not a hook key or custom group\n  Suggestion: Run 'blues-traveler hooks list' to see available hooks and groups", target)
+}
+
+func snoozeHook(settingsPath, key string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("--for must be positive, got %s", d)
+	}
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("error loading settings: %w", err)
+	}
+
+	until := time.Now().Add(d)
+	settings.SetSnooze(key, until)
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	output.Say("hooks.snooze.set", map[string]any{"Key": key, "Until": until.Format(time.Kitchen), "Duration": d})
+	return nil
+}
+
+func clearSnooze(settingsPath, key string) error {
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("error loading settings: %w", err)
+	}
+	if !settings.ClearSnooze(key) {
+		output.Say("hooks.snooze.not_snoozed", map[string]any{"Key": key})
+		return nil
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	output.Say("hooks.snooze.cleared", map[string]any{"Key": key})
+	return nil
+}
+
+func listSnoozes(settingsPath string) error {
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("error loading settings: %w", err)
+	}
+	now := time.Now()
+	entries := settings.ActiveSnoozes(now)
+	if len(entries) == 0 {
+		output.Say("hooks.snooze.none", nil)
+		return nil
+	}
+	output.Say("hooks.snooze.header", nil)
+	for _, e := range entries {
+		output.Printf("  %s - until %s (%s left)\n", e.Key, e.Until.Local().Format(time.RFC3339), e.Until.Sub(now).Round(time.Minute))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestResolveSnoozeTarget(t *testing.T) {
+	keys := []string{"format", "security", "config:lint:eslint", "config:lint:ruff"}
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"format", "format", false},
+		{"config:lint:ruff", "config:lint:ruff", false},
+		{"lint", "config:lint", false},
+		{"config:lint", "config:lint", false},
+		{"missing", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveSnoozeTarget(tt.target, keys)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolveSnoozeTarget(%q) = (%q, %v), want (%q, err=%v)", tt.target, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSnoozeHook_HonoredByActiveSnooze(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	settingsPath := filepath.Join(dir, ".claude", "settings.json")
+
+	if err := snoozeHook(settingsPath, "config:lint", 2*time.Hour); err != nil {
+		t.Fatalf("snoozeHook: %v", err)
+	}
+	if entry, ok := config.ActiveSnooze("config:lint:eslint"); !ok || entry.Key != "config:lint" {
+		t.Fatalf("ActiveSnooze = (%+v, %v), want group snooze", entry, ok)
+	}
+
+	if err := clearSnooze(settingsPath, "config:lint"); err != nil {
+		t.Fatalf("clearSnooze: %v", err)
+	}
+	if _, ok := config.ActiveSnooze("config:lint:eslint"); ok {
+		t.Error("snooze should be cleared")
+	}
+
+	if err := snoozeHook(settingsPath, "format", 0); err == nil {
+		t.Error("expected error for non-positive duration")
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksStatsCommand creates the stats command
+func newHooksStatsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Show which hooks take the most time, from recorded runs",
+		Description: `Runs are recorded when "metrics": true is set in blues-traveler-config.json.
+Each hook run appends its hook key, event, tool, duration, exit status and
+decision to .claude/hooks/metrics/metrics-YYYY-MM-DD.jsonl. This command
+aggregates them by hook and event over a time window, most total time first.
+--since accepts Go durations plus days (e.g. 90m, 24h, 7d) or an RFC 3339
+timestamp.
+
+Examples:
+  blues-traveler hooks stats
+  blues-traveler hooks stats --since 24h --by hook
+  blues-traveler hooks stats --hook format --by event`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "since", Value: "7d", Usage: "Only runs newer than a duration or timestamp (\"all\" for every run)"},
+			&cli.StringFlag{Name: "by", Value: "hook,event", Usage: "Group by hook, event, or hook,event"},
+			&cli.StringFlag{Name: "hook", Usage: "Only runs of this hook key"},
+			&cli.StringFlag{Name: "event", Usage: "Only runs for this event"},
+			&cli.BoolFlag{Name: "reset", Usage: "Delete recorded runs"},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.Bool("reset") {
+				if err := core.ResetHookRunRecords(); err != nil {
+					return err
+				}
+				output.Println("✅ Hook metrics reset")
+				return nil
+			}
+			byHook, byEvent, err := parseStatsGrouping(cmd.String("by"))
+			if err != nil {
+				return err
+			}
+			var since time.Time
+			if s := cmd.String("since"); s != "all" {
+				if since, err = parseSince(s, time.Now()); err != nil {
+					return err
+				}
+			}
+			records, err := core.LoadHookRunRecords(since)
+			if err != nil {
+				return err
+			}
+			records = filterHookRuns(records, cmd.String("hook"), cmd.String("event"))
+			showHookStats(core.SummarizeHookRuns(records, byHook, byEvent), byHook, byEvent, config.GetMetricsEnabled())
+			return nil
+		},
+	}
+}
+
+// parseStatsGrouping reads --by
+func parseStatsGrouping(value string) (byHook, byEvent bool, err error) {
+	for _, part := range strings.Split(value, ",") {
+		switch strings.TrimSpace(part) {
+		case "hook":
+			byHook = true
+		case "event":
+			byEvent = true
+		default:
+			return false, false, fmt.Errorf("invalid --by '%s'\n  Suggestion: Use hook, event, or hook,event", value)
+		}
+	}
+	return byHook, byEvent, nil
+}
+
+// filterHookRuns keeps runs of hook and event; empty matches any
+func filterHookRuns(records []core.HookRunRecord, hook, event string) []core.HookRunRecord {
+	if hook == "" && event == "" {
+		return records
+	}
+	var out []core.HookRunRecord
+	for _, rec := range records {
+		if (hook == "" || rec.Hook == hook) && (event == "" || rec.Event == event) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// showHookStats prints aggregated runs, most total time first
+func showHookStats(stats []core.HookRunStat, byHook, byEvent, enabled bool) {
+	if !enabled {
+		output.Println("⚠️  Recording is off. Set \"metrics\": true in blues-traveler-config.json to record hook runs.")
+	}
+	if len(stats) == 0 {
+		output.Println("No hook runs recorded in this window.")
+		return
+	}
+
+	output.Println("📊 Hook runs:")
+	header := ""
+	if byHook {
+		header += fmt.Sprintf("%-28s ", "HOOK")
+	}
+	if byEvent {
+		header += fmt.Sprintf("%-18s ", "EVENT")
+	}
+	output.Printf("  %s%6s %10s %8s %8s %8s %6s %5s %6s\n", header, "RUNS", "TOTAL", "AVG", "P95", "MAX", "BLOCK", "ASK", "ERRORS")
+	for _, s := range stats {
+		row := ""
+		if byHook {
+			row += fmt.Sprintf("%-28s ", s.Hook)
+		}
+		if byEvent {
+			event := s.Event
+			if event == "" {
+				event = "-"
+			}
+			row += fmt.Sprintf("%-18s ", event)
+		}
+		output.Printf("  %s%6d %10s %8s %8s %8s %6d %5d %6d\n", row, s.Runs, formatMillis(s.TotalMs),
+			formatMillis(s.AverageMs()), formatMillis(s.P95Ms), formatMillis(s.MaxMs), s.Blocks, s.Asks, s.Errors)
+	}
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// Decisions reported by `hooks test`
+const (
+	testDecisionApprove = "approve"
+	testDecisionAllow   = "allow"
+	testDecisionBlock   = "block"
+)
+
+// capturedHandlers holds the handlers a hook passed to its runner
+type capturedHandlers struct {
+	pre  func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface
+	post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface
+}
+
+// noopRunner satisfies core.Runner without reading stdin
+type noopRunner struct{}
+
+func (noopRunner) RunContext(context.Context) {}
+
+// hookTestResult is the outcome of driving a hook with a synthetic event
+type hookTestResult struct {
+	Summary  core.ResponseSummary
+	Final    string
+	Prompted bool
+}
+
+// newHooksTestCommand creates the test command
+func newHooksTestCommand(pluginKeys func() []string) *cli.Command {
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "Run a hook against a sample event and show its decision",
+		ArgsUsage: "[plugin-key]",
+		Description: `Feed a synthetic PreToolUse or PostToolUse event to a hook and print the
+decision it returns. When the hook answers "ask" and the terminal is interactive,
+you are prompted yes/no and the answer is mapped to approve/block, so the ask flow
+can be exercised locally without Claude in the loop.
+
+Examples:
+  blues-traveler hooks test security --tool Bash --input '{"command":"rm -rf /"}'
+  blues-traveler hooks test config:my-group:guard --file event.json`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "event",
+				Aliases: []string{"e"},
+				Value:   string(core.PreToolUseEvent),
+				Usage:   "Event to simulate (PreToolUse or PostToolUse)",
+			},
+			&cli.StringFlag{
+				Name:    "tool",
+				Aliases: []string{"t"},
+				Value:   "Bash",
+				Usage:   "Tool name for the synthetic event",
+			},
+			&cli.StringFlag{
+				Name:    "input",
+				Aliases: []string{"i"},
+				Value:   "{}",
+				Usage:   "Tool input as JSON",
+			},
+			&cli.StringFlag{
+				Name:  "response",
+				Value: "{}",
+				Usage: "Tool response as JSON (PostToolUse only)",
+			},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Read the full event JSON from a file ('-' for stdin) instead of --tool/--input",
+			},
+			&cli.BoolFlag{
+				Name:  "no-prompt",
+				Value: false,
+				Usage: "Never prompt on ask decisions, even in an interactive terminal",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: [plugin-key]")
+			}
+			key := args[0]
+
+			event := core.ResolveEventAlias(cmd.String("event"))
+			if event != string(core.PreToolUseEvent) && event != string(core.PostToolUseEvent) {
+				return fmt.Errorf("unsupported --event '%s'\n  Suggestion: hooks test supports PreToolUse and PostToolUse", cmd.String("event"))
+			}
+
+			payload, err := buildTestEventPayload(cmd.String("file"), cmd.String("tool"), cmd.String("input"), cmd.String("response"))
+			if err != nil {
+				return err
+			}
+
+			var prompt io.Reader
+			if !cmd.Bool("no-prompt") && cmd.String("file") != "-" && isInteractiveTerminal(os.Stdin) {
+				prompt = os.Stdin
+			}
+
+			result, err := runHookTest(ctx, key, event, payload, prompt, output.Stdout())
+			if err != nil {
+				if strings.Contains(err.Error(), "not found") {
+					return fmt.Errorf("%w\nAvailable plugins: %s", err, strings.Join(pluginKeys(), ", "))
+				}
+				return err
+			}
+			printHookTestResult(key, event, result)
+			return nil
+		},
+	}
+}
+
+// buildTestEventPayload returns the event JSON from a file or from individual flags
+func buildTestEventPayload(file, tool, input, response string) ([]byte, error) {
+	if file != "" {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file) // #nosec G304 - user-provided event file
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+		return data, nil
+	}
+
+	if !json.Valid([]byte(input)) {
+		return nil, fmt.Errorf("--input is not valid JSON: %s", input)
+	}
+	if !json.Valid([]byte(response)) {
+		return nil, fmt.Errorf("--response is not valid JSON: %s", response)
+	}
+	return json.Marshal(map[string]any{
+		"session_id":    "hooks-test",
+		"tool_name":     tool,
+		"tool_input":    json.RawMessage(input),
+		"tool_response": json.RawMessage(response),
+	})
+}
+
+// runHookTest drives the hook's handler for event with payload. If the hook
+// asks and prompt is non-nil, the user's answer decides the final outcome.
+func runHookTest(ctx context.Context, key, event string, payload []byte, prompt io.Reader, out io.Writer) (*hookTestResult, error) {
+	captured := &capturedHandlers{}
+	hook, err := core.CreateHookWithRunner(key, func(
+		pre func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+		post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+		_ func(context.Context, string) *cchooks.RawResponse,
+	) core.Runner {
+		captured.pre, captured.post = pre, post
+		return noopRunner{}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := hook.Run(); err != nil {
+		return nil, fmt.Errorf("hook '%s' failed: %w", key, err)
+	}
+
+	var resp any
+	allowDecision := testDecisionApprove
+	switch event {
+	case string(core.PreToolUseEvent):
+		if captured.pre == nil {
+			return nil, fmt.Errorf("hook '%s' does not handle %s (or is disabled)", key, event)
+		}
+		var ev cchooks.PreToolUseEvent
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return nil, fmt.Errorf("invalid %s event JSON: %w", event, err)
+		}
+		resp = captured.pre(ctx, &ev)
+	case string(core.PostToolUseEvent):
+		if captured.post == nil {
+			return nil, fmt.Errorf("hook '%s' does not handle %s (or is disabled)", key, event)
+		}
+		var ev cchooks.PostToolUseEvent
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return nil, fmt.Errorf("invalid %s event JSON: %w", event, err)
+		}
+		resp = captured.post(ctx, &ev)
+		allowDecision = testDecisionAllow
+	}
+
+	result := &hookTestResult{Summary: core.SummarizeResponse(resp)}
+	switch result.Summary.Decision {
+	case core.PreToolUseAsk:
+		if prompt == nil {
+			result.Final = core.PreToolUseAsk
+			break
+		}
+		result.Prompted = true
+		if promptYesNo(prompt, out, askPromptText(result.Summary)) {
+			result.Final = allowDecision
+		} else {
+			result.Final = testDecisionBlock
+		}
+	case "":
+		result.Final = allowDecision
+	default:
+		result.Final = result.Summary.Decision
+	}
+	return result, nil
+}
+
+func askPromptText(s core.ResponseSummary) string {
+	msg := s.UserMessage
+	if msg == "" {
+		msg = "Hook requests confirmation"
+	}
+	return fmt.Sprintf("❓ %s\n   Proceed? [y/N]: ", msg)
+}
+
+// promptYesNo writes question to out and reads a y/yes answer from in.
+// Anything else, including EOF, is treated as no.
+func promptYesNo(in io.Reader, out io.Writer, question string) bool {
+	_, _ = fmt.Fprint(out, question)
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// isInteractiveTerminal reports whether f is attached to a terminal
+func isInteractiveTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func printHookTestResult(key, event string, r *hookTestResult) {
+	output.Printf("Hook: %s (%s)\n", key, event)
+	decision := r.Summary.Decision
+	if decision == "" {
+		decision = "(none)"
+	}
+	output.Printf("  Hook decision:  %s\n", decision)
+	if r.Summary.UserMessage != "" {
+		output.Printf("  User message:   %s\n", r.Summary.UserMessage)
+	}
+	if r.Summary.AgentMessage != "" && r.Summary.AgentMessage != r.Summary.UserMessage {
+		output.Printf("  Agent message:  %s\n", r.Summary.AgentMessage)
+	}
+
+	switch r.Final {
+	case testDecisionBlock:
+		output.Printf("🚫 Result: %s\n", r.Final)
+	case core.PreToolUseAsk:
+		output.Printf("❓ Result: ask (not prompted; run in an interactive terminal to answer)\n")
+	default:
+		output.Printf("✅ Result: %s\n", r.Final)
+	}
+	if r.Prompted {
+		output.Println("  (decided by your answer to the ask prompt)")
+	}
+}
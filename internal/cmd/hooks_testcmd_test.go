@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// decisionTestHook returns a fixed PreToolUse response for the hooks test command
+type decisionTestHook struct {
+	*core.BaseHook
+	pre cchooks.PreToolUseResponseInterface
+}
+
+func (h *decisionTestHook) Run() error {
+	return h.StandardRun(func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+		return h.pre
+	}, nil)
+}
+
+var registerDecisionHooksOnce sync.Once
+
+func registerDecisionTestHooks() {
+	registerDecisionHooksOnce.Do(func() {
+		newHook := func(key string, resp cchooks.PreToolUseResponseInterface) core.HookFactory {
+			return func(ctx *core.HookContext) core.Hook {
+				return &decisionTestHook{BaseHook: core.NewBaseHook(key, key, "test hook", ctx), pre: resp}
+			}
+		}
+		core.RegisterBuiltinHooks(map[string]core.HookFactory{
+			"test-ask":     newHook("test-ask", core.AskWithMessages("Deploy to prod?", "deploy detected")),
+			"test-block":   newHook("test-block", core.BlockWithMessages("nope")),
+			"test-approve": newHook("test-approve", cchooks.Approve()),
+		})
+	})
+}
+
+func TestRunHookTest_Decisions(t *testing.T) {
+	registerDecisionTestHooks()
+	payload, err := buildTestEventPayload("", "Bash", `{"command":"make deploy"}`, "{}")
+	if err != nil {
+		t.Fatalf("buildTestEventPayload: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		answer       *string
+		wantFinal    string
+		wantPrompted bool
+	}{
+		{"approve passes through", "test-approve", nil, "approve", false},
+		{"block passes through", "test-block", nil, "block", false},
+		{"ask without tty stays ask", "test-ask", nil, "ask", false},
+		{"ask answered yes approves", "test-ask", strPtr("y\n"), "approve", true},
+		{"ask answered YES approves", "test-ask", strPtr("YES\n"), "approve", true},
+		{"ask answered no blocks", "test-ask", strPtr("n\n"), "block", true},
+		{"ask with empty answer blocks", "test-ask", strPtr(""), "block", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var prompt io.Reader
+			if tt.answer != nil {
+				prompt = strings.NewReader(*tt.answer)
+			}
+			result, err := runHookTest(context.Background(), tt.key, string(core.PreToolUseEvent), payload, prompt, &out)
+			if err != nil {
+				t.Fatalf("runHookTest: %v", err)
+			}
+			if result.Final != tt.wantFinal {
+				t.Errorf("Final = %q, want %q", result.Final, tt.wantFinal)
+			}
+			if result.Prompted != tt.wantPrompted {
+				t.Errorf("Prompted = %v, want %v", result.Prompted, tt.wantPrompted)
+			}
+			if tt.wantPrompted && !strings.Contains(out.String(), "Deploy to prod?") {
+				t.Errorf("expected prompt to include user message, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestRunHookTest_Errors(t *testing.T) {
+	registerDecisionTestHooks()
+
+	if _, err := runHookTest(context.Background(), "does-not-exist", string(core.PreToolUseEvent), []byte(`{}`), nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unknown hook")
+	}
+	if _, err := runHookTest(context.Background(), "test-ask", string(core.PostToolUseEvent), []byte(`{}`), nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected error when hook has no PostToolUse handler")
+	}
+	if _, err := buildTestEventPayload("", "Bash", "{not json", "{}"); err == nil {
+		t.Error("expected error for invalid --input JSON")
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// newHooksToggleCommand creates the enable or disable command
+func newHooksToggleCommand(enable bool, pluginKeys func() []string) *cli.Command {
+	name, verb := "disable", "Disable"
+	if enable {
+		name, verb = "enable", "Enable"
+	}
+	return &cli.Command{
+		Name:      name,
+		Usage:     verb + " a hook, or one of its installed entries, without uninstalling it",
+		ArgsUsage: "[plugin-key]",
+		Description: `Without --event, the whole hook is switched in the plugins section of
+settings.json. With --event (and optionally --matcher), only the entries installed
+for that event are switched: the entry stays in settings.json and 'hooks run'
+exits 0 straight away for events it covers. Without --matcher every matcher the
+hook is installed under for the event is switched.
+
+Examples:
+  blues-traveler hooks disable security --event PreToolUse
+  blues-traveler hooks disable format --event PostToolUse --matcher 'Edit|Write'
+  blues-traveler hooks enable security --event PreToolUse
+  blues-traveler hooks disable debug --global`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "global",
+				Aliases: []string{"g"},
+				Usage:   "Change global settings (~/.claude/settings.json)",
+			},
+			&cli.StringFlag{
+				Name:    "event",
+				Aliases: []string{"e"},
+				Usage:   "Only the entries installed for this event",
+			},
+			&cli.StringFlag{
+				Name:    "matcher",
+				Aliases: []string{"m"},
+				Usage:   "Only the entry with this matcher (needs --event)",
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) != 1 {
+				return fmt.Errorf("exactly one argument required: [plugin-key]")
+			}
+			key := args[0]
+			if !slices.Contains(pluginKeys(), key) {
+				return fmt.Errorf("plugin '%s' not found.\nAvailable plugins: %s", key, strings.Join(pluginKeys(), ", "))
+			}
+			event := core.ResolveEventAlias(cmd.String("event"))
+			if cmd.IsSet("matcher") && event == "" {
+				return fmt.Errorf("--matcher needs --event\n  Suggestion: Pass the event the entry is installed for, e.g. --event PreToolUse")
+			}
+			global := cmd.Bool("global")
+			settingsPath, err := config.GetSettingsPath(global)
+			if err != nil {
+				return fmt.Errorf("error getting settings path: %w", err)
+			}
+			var matcher *string
+			if cmd.IsSet("matcher") {
+				m := core.ExpandMCPMatcher(cmd.String("matcher"))
+				matcher = &m
+			}
+			return toggleHook(settingsPath, getScopeName(global), key, event, matcher, enable)
+		},
+	}
+}
+
+// toggleHook switches key, or its entries for event, on or off. A nil
+// matcher covers every matcher key is installed under for event.
+func toggleHook(settingsPath, scope, key, event string, matcher *string, enable bool) error {
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("error loading settings: %w", err)
+	}
+	state := "disabled"
+	if enable {
+		state = "enabled"
+	}
+
+	if event == "" {
+		settings.SetPluginEnabled(key, enable)
+		if err := config.SaveSettings(settingsPath, settings); err != nil {
+			return fmt.Errorf("error saving settings: %w", err)
+		}
+		output.Printf("✅ '%s' %s in %s settings\n", key, state, scope)
+		if n := len(settings.Plugins[key].DisabledEntries); enable && n > 0 {
+			output.Printf("   %d entry toggle(s) still apply; see 'hooks enable %s --event <event>'\n", n, key)
+		}
+		return nil
+	}
+
+	matchers, err := toggleMatchers(settings, key, event, matcher, enable)
+	if err != nil {
+		return err
+	}
+	changed := 0
+	for _, m := range matchers {
+		if settings.SetEntryEnabled(key, event, m, enable) {
+			changed++
+		}
+	}
+	if changed == 0 {
+		output.Printf("'%s' %s entries are already %s in %s settings.\n", key, event, state, scope)
+		return nil
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	for _, m := range matchers {
+		output.Printf("✅ '%s' %s %s in %s settings (matcher %s)\n", key, event, state, scope, formatMatcher(m))
+	}
+	return nil
+}
+
+// toggleMatchers resolves which entries of key for event to switch. Any
+// recorded toggle can be re-enabled, even after the entry was uninstalled.
+func toggleMatchers(settings *config.Settings, key, event string, matcher *string, enable bool) ([]string, error) {
+	installed := settings.InstalledMatchers(key, event)
+	if enable {
+		var recorded []string
+		for _, t := range settings.Plugins[key].DisabledEntries {
+			if t.Event == event && (matcher == nil || t.Matcher == *matcher) {
+				recorded = append(recorded, t.Matcher)
+			}
+		}
+		if len(recorded) > 0 || matcher == nil {
+			return recorded, nil
+		}
+		return []string{*matcher}, nil
+	}
+	if matcher == nil {
+		if len(installed) == 0 {
+			return nil, fmt.Errorf("'%s' is not installed for %s in these settings\n  Suggestion: Run 'blues-traveler hooks list --installed' to see where it is installed", key, event)
+		}
+		return installed, nil
+	}
+	if !slices.Contains(installed, *matcher) {
+		return nil, fmt.Errorf("'%s' has no %s entry with matcher %s\n  Suggestion: Installed matchers: %s", key, event, formatMatcher(*matcher), formatMatchers(installed))
+	}
+	return []string{*matcher}, nil
+}
+
+func formatMatcher(m string) string {
+	if m == "" {
+		return `"" (all tools)`
+	}
+	return fmt.Sprintf("%q", m)
+}
+
+func formatMatchers(ms []string) string {
+	if len(ms) == 0 {
+		return "none"
+	}
+	out := make([]string, len(ms))
+	for i, m := range ms {
+		out[i] = formatMatcher(m)
+	}
+	return strings.Join(out, ", ")
+}
+
+// disabledEntryForRun reads the event from stdin when key has entry
+// toggles, reporting the toggle that covers it. Stdin is put back for the
+// hook when the run goes ahead; hooks without toggles skip all of this.
+func disabledEntryForRun(key string) (config.EntryToggle, bool) {
+	toggles := config.EntryToggles(key)
+	if len(toggles) == 0 {
+		return config.EntryToggle{}, false
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return config.EntryToggle{}, false
+	}
+	if events, err := core.ParseReplayEvents(data, "stdin"); err == nil && len(events) > 0 {
+		if toggle, off := config.DisabledEntryFor(toggles, events[0].Event, events[0].Tool); off {
+			return toggle, true
+		}
+	}
+	if r, err := stdinFrom(data); err == nil {
+		os.Stdin = r
+	}
+	return config.EntryToggle{}, false
+}
+
+// stdinFrom returns a pipe that yields data, to stand in for a consumed stdin
+func stdinFrom(data []byte) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, _ = w.Write(data)
+		_ = w.Close()
+	}()
+	return r, nil
+}
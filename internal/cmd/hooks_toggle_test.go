@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestToggleHook_EntryToggles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", t.TempDir())
+	settingsPath := filepath.Join(dir, ".claude", "settings.json")
+
+	settings := &config.Settings{}
+	config.AddHookToSettings(settings, "PreToolUse", "Bash", "blues-traveler hooks run security", nil)
+	config.AddHookToSettings(settings, "PreToolUse", "Edit|Write", "blues-traveler hooks run security", nil)
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := toggleHook(settingsPath, "project", "security", "PreToolUse", nil, false); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	toggles := config.EntryToggles("security")
+	if len(toggles) != 2 {
+		t.Fatalf("toggles = %+v, want both PreToolUse entries", toggles)
+	}
+	if _, off := config.DisabledEntryFor(toggles, "PreToolUse", "Write"); !off {
+		t.Error("expected PreToolUse Write to be disabled")
+	}
+
+	bash := "Bash"
+	if err := toggleHook(settingsPath, "project", "security", "PreToolUse", &bash, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	toggles = config.EntryToggles("security")
+	if _, off := config.DisabledEntryFor(toggles, "PreToolUse", "Bash"); off {
+		t.Error("expected PreToolUse Bash to be enabled again")
+	}
+
+	if err := toggleHook(settingsPath, "project", "security", "Stop", nil, false); err == nil {
+		t.Error("expected an error for an event the hook is not installed for")
+	}
+	missing := "Read"
+	if err := toggleHook(settingsPath, "project", "security", "PreToolUse", &missing, false); err == nil {
+		t.Error("expected an error for a matcher the hook is not installed under")
+	}
+}
+
+func TestDisabledEntryForRun_RestoresStdin(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("HOME", t.TempDir())
+	settingsPath := filepath.Join(dir, ".claude", "settings.json")
+	settings := &config.Settings{}
+	settings.SetEntryEnabled("security", "PreToolUse", "Bash", false)
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = stdin })
+	feed := func(payload string) {
+		r, err := stdinFrom([]byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Stdin = r
+	}
+
+	feed(`{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}`)
+	if _, off := disabledEntryForRun("security"); !off {
+		t.Error("expected the Bash entry to be disabled")
+	}
+
+	write := `{"hook_event_name":"PreToolUse","tool_name":"Write","tool_input":{"file_path":"a.go"}}`
+	feed(write)
+	if _, off := disabledEntryForRun("security"); off {
+		t.Fatal("expected Write to run")
+	}
+	data := make([]byte, len(write)+1)
+	n, _ := os.Stdin.Read(data)
+	if string(data[:n]) != write {
+		t.Errorf("stdin after check = %q, want the original payload", data[:n])
+	}
+}
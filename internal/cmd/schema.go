@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// NewSchemaCmd creates the schema command for exporting JSON Schemas
+func NewSchemaCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "schema",
+		Usage: "Print JSON Schemas for files blues-traveler manages",
+		Commands: []*cli.Command{
+			{
+				Name:  "settings",
+				Usage: "JSON Schema for the hooks section of settings.json",
+				Description: `Describes events, matchers, hook commands and timeout limits as
+blues-traveler reads and writes them, so hand-edited settings files can be
+validated in CI with any JSON Schema validator.
+
+Examples:
+  blues-traveler schema settings -o settings-hooks.schema.json
+  check-jsonschema --schemafile settings-hooks.schema.json .claude/settings.json
+  blues-traveler schema settings --example`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Write to this file instead of stdout",
+					},
+					&cli.BoolFlag{
+						Name:  "example",
+						Usage: "Print an example settings document instead of the schema",
+					},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					var doc interface{} = config.SettingsHooksSchema()
+					if cmd.Bool("example") {
+						doc = config.ExampleSettingsHooks()
+					}
+					return writeJSONDocument(doc, cmd.String("output"))
+				},
+			},
+		},
+	}
+}
+
+// writeJSONDocument prints doc as indented JSON to output, or stdout when empty
+func writeJSONDocument(doc interface{}, output string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	data := buf.Bytes()
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Wrote %s\n", output)
+	return nil
+}
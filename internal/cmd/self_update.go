@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// selfUpdateOptions configures a self-update run
+type selfUpdateOptions struct {
+	current      string
+	version      string
+	check        bool
+	force        bool
+	to           string
+	yes          bool
+	skipSettings bool
+	confirm      func(prompt string) bool
+}
+
+// NewSelfUpdateCmd creates the self-update command
+func NewSelfUpdateCmd(versionInfo VersionInfo) *cli.Command {
+	return &cli.Command{
+		Name:  "self-update",
+		Usage: "Update blues-traveler to the latest GitHub release",
+		Description: `Look up the latest release (or --version), download the archive for this
+platform, check it against the release's checksums.txt and replace the running
+binary in one rename. On Windows the old binary is kept as <path>.old.
+
+Hook entries in the project and global settings.json that run blues-traveler
+from another absolute path, such as an older install or the binary replaced
+with --to, are listed afterwards with an offer to point them at the updated
+binary. BT_RELEASES_URL replaces the GitHub releases API, e.g. for a mirror;
+GITHUB_TOKEN is sent to avoid rate limits.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "check", Usage: "Only report whether a newer release is available"},
+			&cli.StringFlag{Name: "version", Usage: "Install this release tag (e.g. v0.9.0) instead of the latest; allows downgrades"},
+			&cli.BoolFlag{Name: "force", Usage: "Install even when already up to date or running a development build"},
+			&cli.StringFlag{Name: "to", Usage: "Write the new binary to this path instead of replacing the running one"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Rewrite settings entries without asking"},
+			&cli.BoolFlag{Name: "skip-settings", Usage: "Leave settings.json entries untouched"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return runSelfUpdate(ctx, selfUpdateOptions{
+				current:      versionInfo.Version,
+				version:      cmd.String("version"),
+				check:        cmd.Bool("check"),
+				force:        cmd.Bool("force"),
+				to:           cmd.String("to"),
+				yes:          cmd.Bool("yes"),
+				skipSettings: cmd.Bool("skip-settings"),
+				confirm: func(prompt string) bool {
+					output.Printf("%s (y/N): ", prompt)
+					var response string
+					_, _ = fmt.Scanln(&response)
+					return response == "y" || response == "Y" || response == "yes"
+				},
+			})
+		},
+	}
+}
+
+func runSelfUpdate(ctx context.Context, opts selfUpdateOptions) error {
+	release, err := core.FetchRelease(ctx, opts.version)
+	if err != nil {
+		return fmt.Errorf("%w\n  Suggestion: Check your connection, or set GITHUB_TOKEN if the GitHub API is rate limiting you", err)
+	}
+
+	cmp := core.CompareVersions(opts.current, release.TagName)
+	if opts.check {
+		if cmp < 0 {
+			output.Printf("⬆️  blues-traveler %s is available (running %s). Run 'blues-traveler self-update' to install it.\n", release.TagName, opts.current)
+		} else {
+			output.Printf("✅ blues-traveler %s is up to date (latest release %s)\n", opts.current, release.TagName)
+		}
+		return nil
+	}
+	if !opts.force {
+		if !isReleaseVersion(opts.current) {
+			return fmt.Errorf("this is a development build (%s), which self-update would replace with %s\n  Suggestion: Pass --force to replace it anyway", opts.current, release.TagName)
+		}
+		if cmp == 0 || (cmp > 0 && opts.version == "") {
+			output.Printf("✅ blues-traveler %s is up to date (latest release %s)\n", opts.current, release.TagName)
+			return nil
+		}
+	}
+
+	target, err := selfUpdateTarget(opts.to)
+	if err != nil {
+		return err
+	}
+	output.Printf("⬇️  Downloading blues-traveler %s for this platform...\n", release.TagName)
+	binary, err := core.DownloadReleaseBinary(ctx, release)
+	if err != nil {
+		return err
+	}
+	if err := core.ReplaceExecutable(target, binary); err != nil {
+		return err
+	}
+	output.Printf("✅ Installed blues-traveler %s at %s (checksum verified)\n", release.TagName, target)
+
+	if opts.skipSettings {
+		return nil
+	}
+	return retargetSettings(opts, target)
+}
+
+// isReleaseVersion reports whether v is a release version rather than a
+// development build such as "dev"
+func isReleaseVersion(v string) bool {
+	return core.CompareVersions(v, "0.0.0") >= 0
+}
+
+// selfUpdateTarget is the file to replace: to, or the running binary with
+// symlinks resolved so a symlink on PATH keeps pointing at it
+func selfUpdateTarget(to string) (string, error) {
+	if to != "" {
+		return filepath.Abs(to)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get executable path: %w\n  Suggestion: Use --to to name the binary to replace", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
+
+// retargetSettings offers to point settings entries that run blues-traveler
+// from another absolute path at the updated binary, for both scopes
+func retargetSettings(opts selfUpdateOptions, target string) error {
+	for _, global := range []bool{false, true} {
+		scope := "project"
+		if global {
+			scope = "global"
+		}
+		exe := target
+		if opts.to == "" {
+			resolved, err := resolveHookExecutable(global, false)
+			if err != nil {
+				output.Printf("⚠️  Skipping %s settings: %v\n", scope, err)
+				continue
+			}
+			exe = resolved
+		} else if strings.ContainsRune(exe, ' ') {
+			exe = `"` + exe + `"`
+		}
+		if err := retargetSettingsFile(global, scope, exe, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func retargetSettingsFile(global bool, scope, exe string, opts selfUpdateOptions) error {
+	settingsPath, err := config.GetSettingsPath(global)
+	if err != nil {
+		return fmt.Errorf("failed to get %s settings path: %w", scope, err)
+	}
+	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+		return nil
+	}
+	release, err := config.LockFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("error loading %s settings: %w", scope, err)
+	}
+
+	changed := config.RetargetExecutables(settings, exe)
+	if len(changed) == 0 {
+		return nil
+	}
+	output.Printf("\n🔗 Hook entries in %s settings (%s) that run blues-traveler from another path:\n", scope, settingsPath)
+	for _, c := range changed {
+		where := c.Event
+		if c.Matcher != "" {
+			where += " [" + c.Matcher + "]"
+		}
+		output.Printf("  • %s  %s\n", where, c.From)
+	}
+	prompt := fmt.Sprintf("Point them at %s?", exe)
+	if !opts.yes && (opts.confirm == nil || !opts.confirm(prompt)) {
+		output.Printf("Left %s settings unchanged. 'blues-traveler doctor --fix' repairs entries whose binary is gone.\n", scope)
+		return nil
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("error saving %s settings: %w", scope, err)
+	}
+	output.Printf("✅ Updated %d %s hook entr%s\n", len(changed), scope, pluralY(len(changed)))
+	return nil
+}
+
+// pluralY returns the ending of "entry" for n
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// defaultSupportLogLines is how much of each hook log a support bundle keeps
+const defaultSupportLogLines = 500
+
+// supportFile is one entry of a support bundle
+type supportFile struct {
+	Name string
+	Data []byte
+}
+
+// NewSupportCmd creates the support command
+func NewSupportCmd(versionInfo VersionInfo) *cli.Command {
+	return &cli.Command{
+		Name:  "support",
+		Usage: "Collect diagnostics for bug reports",
+		Commands: []*cli.Command{
+			{
+				Name:  "bundle",
+				Usage: "Write a redacted tarball of settings, config, logs and doctor output",
+				Description: `Collect what maintainers need to triage an issue into one .tar.gz:
+
+  versions.txt      blues-traveler, Go, cchooks and platform versions
+  settings/         project and global settings.json
+  config/           blues-traveler-config.json (project and global) and the
+                    effective custom hooks config after merging
+  logs/             the last --log-lines lines of each hook log in .claude/hooks
+  doctor.txt        'blues-traveler doctor --verbose' output
+
+Every file is redacted before it is written: values of keys such as token,
+secret, password and api_key, authorization headers, URL credentials and
+common token formats are replaced with [REDACTED], and your home directory
+with ~. Redaction is best effort; look through the bundle before sharing it.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: "Bundle file to write (default: blues-traveler-support-<time>.tar.gz)"},
+					&cli.IntFlag{Name: "log-lines", Value: defaultSupportLogLines, Usage: "Lines kept from the end of each hook log (0 leaves logs out)"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					out := cmd.String("out")
+					if out == "" {
+						out = fmt.Sprintf("blues-traveler-support-%s.tar.gz", time.Now().Format("20060102-150405"))
+					}
+					files := collectSupportFiles(versionInfo, cmd.Int("log-lines"))
+					if err := writeSupportBundle(out, files); err != nil {
+						return err
+					}
+					output.Printf("✅ Wrote support bundle %s (%d files)\n", out, len(files))
+					for _, f := range files {
+						output.Printf("   %s\n", f.Name)
+					}
+					output.Println("   Secrets were redacted on a best-effort basis; review the contents before attaching it to an issue.")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// collectSupportFiles gathers the bundle's files, redacted. Sources that
+// don't exist are left out; sources that fail to read are noted in place.
+func collectSupportFiles(info VersionInfo, logLines int) []supportFile {
+	var files []supportFile
+	add := func(name string, data []byte) {
+		files = append(files, supportFile{Name: name, Data: []byte(core.RedactText(string(data)))})
+	}
+
+	add("versions.txt", []byte(supportVersions(info)))
+
+	for _, scope := range []struct {
+		name   string
+		global bool
+	}{{ScopeProject, false}, {ScopeGlobal, true}} {
+		if path, err := config.GetSettingsPath(scope.global); err == nil {
+			if data, ok := readSupportSource(path); ok {
+				add("settings/"+scope.name+"-settings.json", data)
+			}
+		}
+		if path, err := config.GetLogConfigPath(scope.global); err == nil {
+			if data, ok := readSupportSource(path); ok {
+				add("config/"+scope.name+"-blues-traveler-config.json", data)
+			}
+		}
+	}
+
+	if cfg, err := config.LoadHooksConfig(); err != nil {
+		add("config/effective-hooks.yml", []byte(fmt.Sprintf("# failed to load hooks config: %v\n", err)))
+	} else if cfg != nil && len(*cfg) > 0 {
+		if data, err := config.EncodeHooksConfig(*cfg, config.FormatYAML); err == nil {
+			add("config/effective-hooks.yml", data)
+		}
+	}
+
+	if logLines > 0 {
+		for _, path := range hookLogFiles() {
+			if strings.HasSuffix(path, ".gz") {
+				continue
+			}
+			if data, err := tailFile(path, logLines); err == nil {
+				add("logs/"+filepath.Base(path), data)
+			}
+		}
+	}
+
+	add("doctor.txt", captureStdout(func() { _ = runDoctorCheck(true) }))
+	return files
+}
+
+// supportVersions describes the binary and platform
+func supportVersions(info VersionInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "blues-traveler: %s\n", info.Version)
+	fmt.Fprintf(&b, "commit: %s\n", info.Commit)
+	fmt.Fprintf(&b, "date: %s\n", info.Date)
+	fmt.Fprintf(&b, "go: %s\n", info.GoVer)
+	if v := core.CchooksVersion(); v != "" {
+		fmt.Fprintf(&b, "cchooks: %s\n", v)
+	}
+	fmt.Fprintf(&b, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "generated: %s\n", time.Now().UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// readSupportSource reads path; ok is false when it doesn't exist. Read
+// errors are returned as the file's content so the bundle records them.
+func readSupportSource(path string) ([]byte, bool) {
+	data, err := os.ReadFile(path) // #nosec G304 - known settings and config paths
+	if os.IsNotExist(err) {
+		return nil, false
+	}
+	if err != nil {
+		return []byte(fmt.Sprintf("failed to read %s: %v\n", path, err)), true
+	}
+	return data, true
+}
+
+// tailFile returns the last n lines of path
+func tailFile(path string, n int) ([]byte, error) {
+	f, err := os.Open(path) // #nosec G304 - hook log under .claude/hooks
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(fn func()) []byte {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return []byte(fmt.Sprintf("failed to capture output: %v\n", err))
+	}
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	orig := os.Stdout
+	os.Stdout = w
+	func() {
+		defer func() { os.Stdout = orig }()
+		fn()
+	}()
+	_ = w.Close()
+	data := <-done
+	_ = r.Close()
+	return data
+}
+
+// writeSupportBundle writes files to a gzipped tar at path
+func writeSupportBundle(path string, files []supportFile) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600) // #nosec G304 - user-chosen output file
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	now := time.Now()
+	for _, sf := range files {
+		hdr := &tar.Header{Name: sf.Name, Mode: 0o600, Size: int64(len(sf.Data)), ModTime: now, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write %s: %w", sf.Name, err)
+		}
+		if _, err := tw.Write(sf.Data); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write %s: %w", sf.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to finish %s: %w", path, err)
+	}
+	if err := gz.Close(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to finish %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSupportBundle_RedactsAndTailsLogs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	t.Chdir(project)
+
+	hooksDir := filepath.Join(project, ".claude", "hooks")
+	if err := os.MkdirAll(hooksDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ".claude", "settings.json"), []byte(`{"env":{"API_TOKEN":"abc123secret"},"hooks":{}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var log strings.Builder
+	for i := 0; i < 20; i++ {
+		log.WriteString("entry\n")
+	}
+	log.WriteString("last password=hunter22\n")
+	if err := os.WriteFile(filepath.Join(hooksDir, "security.log"), []byte(log.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "support.tar.gz")
+	if err := writeSupportBundle(out, collectSupportFiles(VersionInfo{Version: "1.2.3"}, 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	contents := readTarGz(t, out)
+	for _, name := range []string{"versions.txt", "settings/project-settings.json", "logs/security.log", "doctor.txt"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("bundle is missing %s: %v", name, contents)
+		}
+	}
+	if !strings.Contains(contents["versions.txt"], "blues-traveler: 1.2.3") {
+		t.Errorf("unexpected versions.txt %q", contents["versions.txt"])
+	}
+	if settings := contents["settings/project-settings.json"]; strings.Contains(settings, "abc123secret") || !strings.Contains(settings, "[REDACTED]") {
+		t.Errorf("settings not redacted: %s", settings)
+	}
+	logText := contents["logs/security.log"]
+	if strings.Count(logText, "\n") != 5 || strings.Contains(logText, "hunter22") {
+		t.Errorf("expected the last 5 redacted log lines, got %q", logText)
+	}
+}
+
+func readTarGz(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[hdr.Name] = string(data)
+	}
+	return contents
+}
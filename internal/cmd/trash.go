@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+	"github.com/klauern/blues-traveler/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NewTrashCmd creates the trash command for files the delete-guard hook kept
+func NewTrashCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "trash",
+		Usage: "List and restore files the delete-guard hook moved to trash",
+		Description: `With "deleteGuard": {"trash": true} in blues-traveler-config.json, deletions
+made by tools are moved to .claude/trash/<id>/ instead. BT_TRASH_DIR overrides
+the directory.`,
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "Show trash entries, newest first",
+				Action: func(_ context.Context, _ *cli.Command) error {
+					return listTrash()
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "Move an entry's files back to where they were deleted from",
+				ArgsUsage: "<id>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "force", Usage: "Overwrite files that exist at the original paths"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return fmt.Errorf("restore requires exactly one entry id\n  Suggestion: Run 'blues-traveler trash list' to see entries")
+					}
+					entry, err := core.RestoreTrash(cmd.Args().First(), cmd.Bool("force"))
+					if err != nil {
+						return err
+					}
+					for _, item := range entry.Items {
+						output.Say("trash.restored", map[string]any{"Path": item.Original})
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "empty",
+				Usage: "Permanently delete trash entries",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "older-than", Usage: "Only entries older than a duration (e.g. 7d, 12h)"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					var before time.Time
+					if s := cmd.String("older-than"); s != "" {
+						t, err := parseSince(s, time.Now())
+						if err != nil {
+							return err
+						}
+						before = t
+					}
+					n, err := core.EmptyTrash(before)
+					if err != nil {
+						return err
+					}
+					output.Say("trash.emptied", map[string]any{"Count": n})
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func listTrash() error {
+	entries, err := core.ListTrash()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		output.Say("trash.empty", nil)
+		return nil
+	}
+	for _, e := range entries {
+		source := e.Tool
+		if e.Command != "" {
+			source = fmt.Sprintf("%s: %s", e.Tool, e.Command)
+		}
+		output.Printf("%s  %s  (%s)\n", e.ID, e.Time.Local().Format("2006-01-02 15:04:05"), source)
+		for _, item := range e.Items {
+			output.Printf("    %s\n", relToCwd(item.Original))
+		}
+	}
+	return nil
+}
+
+// relToCwd shows paths under the working directory relative to it
+func relToCwd(p string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return p
+}
@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -22,10 +22,10 @@ func NewVersionCmd(versionInfo VersionInfo) *cli.Command {
 		Aliases: []string{"v"},
 		Usage:   "Show version information",
 		Action: func(_ context.Context, _ *cli.Command) error {
-			fmt.Printf("blues-traveler version %s\n", versionInfo.Version)
-			fmt.Printf("commit: %s\n", versionInfo.Commit)
-			fmt.Printf("date: %s\n", versionInfo.Date)
-			fmt.Printf("go: %s\n", versionInfo.GoVer)
+			output.Printf("blues-traveler version %s\n", versionInfo.Version)
+			output.Printf("commit: %s\n", versionInfo.Commit)
+			output.Printf("date: %s\n", versionInfo.Date)
+			output.Printf("go: %s\n", versionInfo.GoVer)
 			return nil
 		},
 	}
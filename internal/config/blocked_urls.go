@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultBlockedURLsCacheTTL is how long a downloaded denylist is reused
+// when blockedUrlsRemote sets no cacheTtl
+const DefaultBlockedURLsCacheTTL = time.Hour
+
+// CacheTTLDuration parses CacheTTL, defaulting to DefaultBlockedURLsCacheTTL
+func (r *BlockedURLsRemote) CacheTTLDuration() (time.Duration, error) {
+	if r.CacheTTL == "" {
+		return DefaultBlockedURLsCacheTTL, nil
+	}
+	d, err := time.ParseDuration(r.CacheTTL)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid blockedUrlsRemote cacheTtl '%s': use a duration such as 30m or 6h", r.CacheTTL)
+	}
+	return d, nil
+}
+
+// GetBlockedURLsRemote returns blockedUrlsRemote from the project config,
+// falling back to the global config, or nil when neither sets a URL
+func GetBlockedURLsRemote() *BlockedURLsRemote {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.BlockedURLsRemote == nil || cfg.BlockedURLsRemote.URL == "" {
+			continue
+		}
+		return cfg.BlockedURLsRemote
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlockedURLsRemote_CacheTTLDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", DefaultBlockedURLsCacheTTL, false},
+		{"6h", 6 * time.Hour, false},
+		{"0s", 0, false},
+		{"daily", 0, true},
+		{"-1m", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := (&BlockedURLsRemote{CacheTTL: tt.value}).CacheTTLDuration()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CacheTTLDuration(%q) = (%v, %v), want (%v, err=%v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
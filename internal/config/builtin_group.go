@@ -0,0 +1,263 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// BuiltinGroupName is the reserved group that built-in hook installs are
+	// tracked under, so group operations (sync, pruning) cover them the
+	// same way as config:<group>:<job> entries
+	BuiltinGroupName = "bt-builtin"
+	// builtinManifestFile lives in .claude/hooks next to per-group files; it
+	// is JSON so the hooks config loader never mistakes it for a group file
+	builtinManifestFile    = BuiltinGroupName + ".json"
+	builtinManifestVersion = 1
+)
+
+// BuiltinInstall is one built-in hook installed into settings
+type BuiltinInstall struct {
+	Hook    string `json:"hook"`
+	Event   string `json:"event"`
+	Matcher string `json:"matcher,omitempty"`
+	Command string `json:"command"`
+	Timeout *int   `json:"timeout,omitempty"`
+	// InstalledAt is zero for entries adopted from settings written before
+	// installs were tracked
+	InstalledAt time.Time `json:"installedAt,omitempty"`
+}
+
+// BuiltinManifest records the built-in hooks installed in one scope
+type BuiltinManifest struct {
+	Version int              `json:"version"`
+	Hooks   []BuiltinInstall `json:"hooks"`
+}
+
+// IsReservedGroupName reports whether name may not be used for a custom group
+func IsReservedGroupName(name string) bool {
+	return name == BuiltinGroupName
+}
+
+// BuiltinManifestPath returns where built-in installs are recorded for the scope
+func BuiltinManifestPath(global bool) (string, error) {
+	dir, err := GroupHooksDir(global)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, builtinManifestFile), nil
+}
+
+// LoadBuiltinManifest reads the manifest at path. A missing file yields nil
+// and no error, so callers can tell "never tracked" from "tracked, empty".
+func LoadBuiltinManifest(path string) (*BuiltinManifest, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - fixed file under .claude/hooks
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var m BuiltinManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w\n  Suggestion: Delete the file; the next 'hooks install' rebuilds it from settings", path, err)
+	}
+	return &m, nil
+}
+
+// SaveBuiltinManifest writes m to path, sorted by event and hook
+func SaveBuiltinManifest(path string, m *BuiltinManifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	m.Version = builtinManifestVersion
+	if m.Hooks == nil {
+		m.Hooks = []BuiltinInstall{}
+	}
+	sort.SliceStable(m.Hooks, func(i, j int) bool {
+		if m.Hooks[i].Event != m.Hooks[j].Event {
+			return m.Hooks[i].Event < m.Hooks[j].Event
+		}
+		return m.Hooks[i].Hook < m.Hooks[j].Hook
+	})
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// BuiltinInstallsInSettings lists the built-in hook commands in settings,
+// leaving out config:<group>:<job> commands
+func BuiltinInstallsInSettings(settings *Settings) []BuiltinInstall {
+	if settings == nil {
+		return nil
+	}
+	var installs []BuiltinInstall
+	events := SettingsEventNames()
+	for i, matchers := range getAllHookMatchers(&settings.Hooks) {
+		for _, m := range matchers {
+			for _, h := range m.Hooks {
+				hookType := extractHookType(h.Command)
+				if hookType == "" || configGroupFromHookType(hookType) != "" {
+					continue
+				}
+				installs = append(installs, BuiltinInstall{Hook: hookType, Event: events[i], Matcher: m.Matcher, Command: h.Command, Timeout: h.Timeout})
+			}
+		}
+	}
+	return installs
+}
+
+// Record adds an install, replacing an entry for the same hook, event and
+// matcher
+func (m *BuiltinManifest) Record(install BuiltinInstall) {
+	for i, existing := range m.Hooks {
+		if existing.Hook == install.Hook && existing.Event == install.Event && existing.Matcher == install.Matcher {
+			m.Hooks[i] = install
+			return
+		}
+	}
+	m.Hooks = append(m.Hooks, install)
+}
+
+// Forget removes entries for hookType (every hook when empty) in event
+// (every event when empty) and returns how many were removed
+func (m *BuiltinManifest) Forget(hookType, event string) int {
+	return m.ForgetMatching(hookType, event, "")
+}
+
+// ForgetMatching is Forget limited to entries installed with matcher (any
+// matcher when empty)
+func (m *BuiltinManifest) ForgetMatching(hookType, event, matcher string) int {
+	kept := m.Hooks[:0]
+	removed := 0
+	for _, h := range m.Hooks {
+		if (hookType == "" || h.Hook == hookType) && (event == "" || h.Event == event) && (matcher == "" || h.Matcher == matcher) {
+			removed++
+			continue
+		}
+		kept = append(kept, h)
+	}
+	m.Hooks = kept
+	return removed
+}
+
+// RecordBuiltinInstall adds install to the manifest for the scope. The first
+// time a scope is tracked, built-in hooks already in settings are adopted
+// so group operations don't treat them as strays.
+func RecordBuiltinInstall(global bool, settings *Settings, install BuiltinInstall) error {
+	return updateBuiltinManifest(global, settings, func(m *BuiltinManifest) {
+		if install.InstalledAt.IsZero() {
+			install.InstalledAt = time.Now().UTC()
+		}
+		m.Record(install)
+	})
+}
+
+// ForgetBuiltinInstalls removes hookType (every hook when empty) from the
+// manifest for the scope. Nothing is written when the scope isn't tracked.
+func ForgetBuiltinInstalls(global bool, hookType string) error {
+	return ForgetBuiltinInstallsIn(global, hookType, "", "")
+}
+
+// ForgetBuiltinInstallsIn is ForgetBuiltinInstalls limited to installs in
+// event with matcher; empty values match any
+func ForgetBuiltinInstallsIn(global bool, hookType, event, matcher string) error {
+	path, err := BuiltinManifestPath(global)
+	if err != nil {
+		return err
+	}
+	release, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	m, err := LoadBuiltinManifest(path)
+	if err != nil || m == nil {
+		return err
+	}
+	if m.ForgetMatching(hookType, event, matcher) == 0 {
+		return nil
+	}
+	return SaveBuiltinManifest(path, m)
+}
+
+func updateBuiltinManifest(global bool, settings *Settings, update func(*BuiltinManifest)) error {
+	path, err := BuiltinManifestPath(global)
+	if err != nil {
+		return err
+	}
+	release, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	m, err := LoadBuiltinManifest(path)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = &BuiltinManifest{}
+		for _, adopted := range BuiltinInstallsInSettings(settings) {
+			m.Record(adopted)
+		}
+	}
+	update(m)
+	return SaveBuiltinManifest(path, m)
+}
+
+// RemoveBuiltinHooksFromSettings removes every built-in hook command from
+// event (from all events when empty), leaving config:<group>:<job> commands
+// and other tools' hooks in place. It returns how many were removed.
+func RemoveBuiltinHooksFromSettings(settings *Settings, event string) int {
+	if settings == nil {
+		return 0
+	}
+	removed := 0
+	filter := func(matchers []HookMatcher) []HookMatcher {
+		var result []HookMatcher
+		for _, m := range matchers {
+			var hooks []HookCommand
+			for _, h := range m.Hooks {
+				if hookType := extractHookType(h.Command); hookType != "" && configGroupFromHookType(hookType) == "" {
+					removed++
+					continue
+				}
+				hooks = append(hooks, h)
+			}
+			if len(hooks) > 0 {
+				m.Hooks = hooks
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+	if event == "" {
+		filterAllEvents(settings, filter)
+	} else {
+		filterSingleEvent(settings, event, filter)
+	}
+	return removed
+}
+
+// HookCommandGroup returns the group a settings command belongs to: its
+// config group, BuiltinGroupName for built-in hooks, or "" for commands
+// blues-traveler doesn't manage
+func HookCommandGroup(command string) string {
+	hookType := extractHookType(command)
+	if hookType == "" {
+		return ""
+	}
+	if group := configGroupFromHookType(hookType); group != "" {
+		return group
+	}
+	return BuiltinGroupName
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func builtinTestSettings() *Settings {
+	return &Settings{Hooks: HooksConfig{
+		PreToolUse: []HookMatcher{{Matcher: "Bash", Hooks: []HookCommand{
+			{Type: "command", Command: "blues-traveler hooks run security"},
+			{Type: "command", Command: "blues-traveler hooks run config:go:vet"},
+			{Type: "command", Command: "other-tool check"},
+		}}},
+		PostToolUse: []HookMatcher{{Matcher: "Edit|Write", Hooks: []HookCommand{
+			{Type: "command", Command: "/usr/local/bin/blues-traveler hooks run format --log"},
+		}}},
+	}}
+}
+
+func TestBuiltinInstallsInSettings(t *testing.T) {
+	installs := BuiltinInstallsInSettings(builtinTestSettings())
+	if len(installs) != 2 {
+		t.Fatalf("expected security and format, got %+v", installs)
+	}
+	if installs[0].Hook != "security" || installs[0].Event != "PreToolUse" || installs[0].Matcher != "Bash" {
+		t.Errorf("unexpected first install %+v", installs[0])
+	}
+	if installs[1].Hook != "format" || installs[1].Event != "PostToolUse" {
+		t.Errorf("unexpected second install %+v", installs[1])
+	}
+}
+
+func TestRemoveBuiltinHooksFromSettings(t *testing.T) {
+	settings := builtinTestSettings()
+	if removed := RemoveBuiltinHooksFromSettings(settings, "PostToolUse"); removed != 1 {
+		t.Fatalf("expected 1 removed from PostToolUse, got %d", removed)
+	}
+	if len(settings.Hooks.PostToolUse) != 0 || len(settings.Hooks.PreToolUse[0].Hooks) != 3 {
+		t.Fatalf("event filter not honored: %+v", settings.Hooks)
+	}
+	if removed := RemoveBuiltinHooksFromSettings(settings, ""); removed != 1 {
+		t.Fatalf("expected security removed, got %d", removed)
+	}
+	hooks := settings.Hooks.PreToolUse[0].Hooks
+	if len(hooks) != 2 || !strings.Contains(hooks[0].Command, "config:go:vet") || hooks[1].Command != "other-tool check" {
+		t.Fatalf("config and foreign commands must stay, got %+v", hooks)
+	}
+}
+
+func TestBuiltinManifest_RecordAndForget(t *testing.T) {
+	m := &BuiltinManifest{}
+	m.Record(BuiltinInstall{Hook: "format", Event: "PostToolUse", Matcher: "Edit", Command: "bt hooks run format"})
+	m.Record(BuiltinInstall{Hook: "format", Event: "PostToolUse", Matcher: "Edit", Command: "bt hooks run format --log"})
+	m.Record(BuiltinInstall{Hook: "security", Event: "PreToolUse", Matcher: "Bash", Command: "bt hooks run security"})
+	if len(m.Hooks) != 2 || m.Hooks[0].Command != "bt hooks run format --log" {
+		t.Fatalf("expected reinstall to replace the entry, got %+v", m.Hooks)
+	}
+	if n := m.Forget("format", "PreToolUse"); n != 0 {
+		t.Fatalf("event filter ignored: removed %d", n)
+	}
+	if n := m.ForgetMatching("format", "PostToolUse", "Write"); n != 0 {
+		t.Fatalf("matcher filter ignored: removed %d", n)
+	}
+	if n := m.Forget("format", ""); n != 1 || len(m.Hooks) != 1 {
+		t.Fatalf("expected format forgotten, got %d %+v", n, m.Hooks)
+	}
+	if n := m.Forget("", ""); n != 1 || len(m.Hooks) != 0 {
+		t.Fatalf("expected everything forgotten, got %d %+v", n, m.Hooks)
+	}
+}
+
+func TestRecordBuiltinInstall_AdoptsExisting(t *testing.T) {
+	t.Chdir(t.TempDir())
+	path, err := BuiltinManifestPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err := LoadBuiltinManifest(path); err != nil || m != nil {
+		t.Fatalf("missing manifest should load as nil, got %+v, %v", m, err)
+	}
+
+	settings := builtinTestSettings()
+	install := BuiltinInstall{Hook: "secrets", Event: "PreToolUse", Matcher: "Write", Command: "blues-traveler hooks run secrets"}
+	if err := RecordBuiltinInstall(false, settings, install); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadBuiltinManifest(path)
+	if err != nil || m == nil {
+		t.Fatalf("load after record: %+v, %v", m, err)
+	}
+	if m.Version != builtinManifestVersion || len(m.Hooks) != 3 {
+		t.Fatalf("expected security and format adopted plus secrets, got %+v", m)
+	}
+	for _, h := range m.Hooks {
+		if h.Hook == "secrets" && h.InstalledAt.IsZero() {
+			t.Errorf("new install should be timestamped: %+v", h)
+		}
+		if h.Hook == "security" && !h.InstalledAt.IsZero() {
+			t.Errorf("adopted install should not be timestamped: %+v", h)
+		}
+	}
+
+	if err := ForgetBuiltinInstalls(false, "security"); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ = LoadBuiltinManifest(path); len(m.Hooks) != 2 {
+		t.Fatalf("expected security forgotten, got %+v", m.Hooks)
+	}
+}
+
+func TestValidateHooksConfig_ReservedGroup(t *testing.T) {
+	cfg := CustomHooksConfig{BuiltinGroupName: &HookGroup{}}
+	if err := ValidateHooksConfig(&cfg); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Fatalf("expected reserved group error, got %v", err)
+	}
+}
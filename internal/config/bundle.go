@@ -0,0 +1,258 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Entries of a group bundle archive
+const (
+	bundleManifestName = "bundle.json"
+	bundleGroupName    = "group.yml"
+	bundleFilesDir     = "files/"
+	// BundleVersion is the bundle format written by WriteGroupBundle
+	BundleVersion = 1
+	// maxBundleFileSize bounds each file read from a bundle
+	maxBundleFileSize = 16 << 20
+)
+
+// GroupBundle is a hook group packaged with the scripts its jobs run, so it
+// can be shared between repositories
+type GroupBundle struct {
+	Group string
+	// Config holds the one group, keyed by Group
+	Config CustomHooksConfig
+	Files  []BundleFile
+}
+
+// BundleFile is a script from the exporting project, at its path relative
+// to the project root
+type BundleFile struct {
+	Path string
+	Mode os.FileMode
+	Data []byte
+}
+
+// bundleManifest is bundle.json, describing the archive's contents
+type bundleManifest struct {
+	Version int      `json:"version"`
+	Group   string   `json:"group"`
+	Files   []string `json:"files"`
+}
+
+// GroupScripts returns the files under root that group's jobs run, as
+// slash-separated paths relative to root. A run: word counts when it names
+// an existing file relative to the job's workdir or root, or under
+// $PROJECT_ROOT; absolute paths and paths outside root are left out.
+func GroupScripts(group *HookGroup, root string) []string {
+	if group == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, ev := range group.Events {
+		if ev == nil {
+			continue
+		}
+		for _, job := range ev.Jobs {
+			for _, tok := range tokenizeCommand(job.Run) {
+				rel, ok := projectScriptPath(tok.Value, job.WorkDir, root)
+				if ok && !seen[rel] {
+					seen[rel] = true
+					files = append(files, rel)
+				}
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// projectScriptPath resolves one command word to a file under root
+func projectScriptPath(word, workDir, root string) (string, bool) {
+	for _, prefix := range []string{"${PROJECT_ROOT}/", "$PROJECT_ROOT/"} {
+		if rest, ok := strings.CutPrefix(word, prefix); ok {
+			word, workDir = rest, ""
+		}
+	}
+	if word == "" || strings.ContainsAny(word, "$*?`") || filepath.IsAbs(word) {
+		return "", false
+	}
+	candidates := []string{filepath.Join(root, word)}
+	if workDir != "" && !filepath.IsAbs(workDir) {
+		candidates = append([]string{filepath.Join(root, workDir, word)}, candidates...)
+	}
+	for _, full := range candidates {
+		rel, err := filepath.Rel(root, full)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if info, err := os.Stat(full); err == nil && info.Mode().IsRegular() {
+			return filepath.ToSlash(rel), true
+		}
+	}
+	return "", false
+}
+
+// NewGroupBundle packages group with the scripts GroupScripts finds under root
+func NewGroupBundle(name string, group *HookGroup, root string) (*GroupBundle, error) {
+	b := &GroupBundle{Group: name, Config: CustomHooksConfig{name: group}}
+	for _, rel := range GroupScripts(group, root) {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(full)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script %s: %w", rel, err)
+		}
+		data, err := os.ReadFile(full) // #nosec G304 - script referenced by the group, under the project root
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script %s: %w", rel, err)
+		}
+		b.Files = append(b.Files, BundleFile{Path: rel, Mode: info.Mode().Perm(), Data: data})
+	}
+	return b, nil
+}
+
+// WriteGroupBundle writes b as a gzipped tar: bundle.json, group.yml and
+// the scripts under files/
+func WriteGroupBundle(w io.Writer, b *GroupBundle) error {
+	manifest := bundleManifest{Version: BundleVersion, Group: b.Group, Files: []string{}}
+	for _, f := range b.Files {
+		manifest.Files = append(manifest.Files, f.Path)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	groupData, err := EncodeHooksConfig(b.Config, FormatYAML)
+	if err != nil {
+		return fmt.Errorf("failed to encode group: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, mode os.FileMode, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: int64(mode), Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		return nil
+	}
+	if err := add(bundleManifestName, 0o644, append(manifestData, '\n')); err != nil {
+		return err
+	}
+	if err := add(bundleGroupName, 0o644, groupData); err != nil {
+		return err
+	}
+	for _, f := range b.Files {
+		if err := add(bundleFilesDir+f.Path, f.Mode, f.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// ReadGroupBundle reads a bundle written by WriteGroupBundle. File paths
+// are checked to stay relative, so extracting them cannot escape the target.
+func ReadGroupBundle(r io.Reader) (*GroupBundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzipped bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var manifest *bundleManifest
+	var groupData []byte
+	files := map[string]BundleFile{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxBundleFileSize {
+			return nil, fmt.Errorf("bundle entry %s is too large (%d bytes)", hdr.Name, hdr.Size)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", hdr.Name, err)
+		}
+		switch {
+		case hdr.Name == bundleManifestName:
+			manifest = &bundleManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", bundleManifestName, err)
+			}
+		case hdr.Name == bundleGroupName:
+			groupData = data
+		case strings.HasPrefix(hdr.Name, bundleFilesDir):
+			rel := strings.TrimPrefix(hdr.Name, bundleFilesDir)
+			if !isSafeBundlePath(rel) {
+				return nil, fmt.Errorf("bundle entry %s has an unsafe path", hdr.Name)
+			}
+			files[rel] = BundleFile{Path: rel, Mode: os.FileMode(hdr.Mode).Perm(), Data: data} // #nosec G115 - tar modes fit in FileMode
+		}
+	}
+
+	if manifest == nil || groupData == nil {
+		return nil, fmt.Errorf("bundle is missing %s or %s", bundleManifestName, bundleGroupName)
+	}
+	if manifest.Version > BundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this blues-traveler supports (%d)\n  Suggestion: Upgrade blues-traveler", manifest.Version, BundleVersion)
+	}
+	cfg, err := ParseHooksConfig(groupData, FormatYAML)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", bundleGroupName, err)
+	}
+	if cfg[manifest.Group] == nil || len(cfg) != 1 {
+		return nil, fmt.Errorf("%s must define exactly the group '%s'", bundleGroupName, manifest.Group)
+	}
+
+	b := &GroupBundle{Group: manifest.Group, Config: cfg}
+	for _, rel := range manifest.Files {
+		f, ok := files[rel]
+		if !ok {
+			return nil, fmt.Errorf("bundle lists %s but does not contain it", rel)
+		}
+		b.Files = append(b.Files, f)
+	}
+	return b, nil
+}
+
+// isSafeBundlePath reports whether rel is a clean relative path inside the
+// extraction root
+func isSafeBundlePath(rel string) bool {
+	if rel == "" || strings.Contains(rel, "\\") || path.IsAbs(rel) || filepath.IsAbs(rel) {
+		return false
+	}
+	clean := path.Clean(rel)
+	return clean == rel && clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// Rename stores the bundled group under name
+func (b *GroupBundle) Rename(name string) {
+	if name == b.Group {
+		return
+	}
+	b.Config = CustomHooksConfig{name: b.Config[b.Group]}
+	b.Group = name
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGroupScripts(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"scripts/lint.sh", "tools/web/check.py", "ci/notify.sh"} {
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("#!/bin/sh\n"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	group := &HookGroup{Events: map[string]*EventConfig{
+		"PostToolUse": {Jobs: []HookJob{
+			{Name: "lint", Run: "./scripts/lint.sh ${TOOL_FILE}"},
+			{Name: "web", Run: "python3 check.py --strict", WorkDir: "tools/web"},
+			{Name: "go", Run: "go vet ./..."},
+		}},
+		"Stop": {Jobs: []HookJob{
+			{Name: "notify", Run: `"${PROJECT_ROOT}/ci/notify.sh" done`},
+			{Name: "again", Run: "sh scripts/lint.sh"},
+			{Name: "outside", Run: "../elsewhere.sh /etc/passwd"},
+		}},
+	}}
+	got := strings.Join(GroupScripts(group, root), ",")
+	if got != "ci/notify.sh,scripts/lint.sh,tools/web/check.py" {
+		t.Errorf("unexpected scripts %s", got)
+	}
+}
+
+func TestGroupBundle_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "scripts"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "scripts", "lint.sh"), []byte("#!/bin/sh\necho lint\n"), 0o755); err != nil { // #nosec G306 - test script
+		t.Fatal(err)
+	}
+	group := &HookGroup{Events: map[string]*EventConfig{"PostToolUse": {Jobs: []HookJob{{Name: "lint", Run: "./scripts/lint.sh"}}}}}
+	bundle, err := NewGroupBundle("lint", group, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGroupBundle(&buf, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadGroupBundle(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Group != "lint" || read.Config["lint"].Events["PostToolUse"].Jobs[0].Run != "./scripts/lint.sh" {
+		t.Errorf("unexpected group %+v", read.Config)
+	}
+	if len(read.Files) != 1 || read.Files[0].Path != "scripts/lint.sh" || read.Files[0].Mode != 0o755 || !strings.Contains(string(read.Files[0].Data), "echo lint") {
+		t.Errorf("unexpected files %+v", read.Files)
+	}
+
+	read.Rename("shared")
+	if read.Group != "shared" || read.Config["shared"] == nil || len(read.Config) != 1 {
+		t.Errorf("rename failed: %+v", read.Config)
+	}
+}
+
+func TestReadGroupBundle_RejectsUnsafePaths(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range map[string]string{
+		"bundle.json":            `{"version":1,"group":"g","files":["../evil.sh"]}`,
+		"group.yml":              "g:\n  Stop:\n    jobs:\n      - name: x\n        run: ../evil.sh\n",
+		"files/../../../evil.sh": "rm -rf /",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+
+	if _, err := ReadGroupBundle(&buf); err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Errorf("expected an unsafe path error, got %v", err)
+	}
+}
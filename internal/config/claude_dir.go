@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+// Environment variables that relocate the .claude directories. The
+// --claude-dir and --global-claude-dir flags set them, so hook jobs and
+// nested blues-traveler commands resolve the same directories.
+const (
+	// ClaudeDirEnv replaces the project's ./.claude directory
+	ClaudeDirEnv = "BT_CLAUDE_DIR"
+	// GlobalClaudeDirEnv replaces ~/.claude
+	GlobalClaudeDirEnv = "BT_GLOBAL_CLAUDE_DIR"
+	// claudeConfigDirEnv is Claude Code's own relocation of ~/.claude,
+	// honored when GlobalClaudeDirEnv and ConfigDirEnv are unset
+	claudeConfigDirEnv = "CLAUDE_CONFIG_DIR"
+	// ConfigDirEnv moves every config location into one sandbox directory,
+	// so nothing is read from $HOME or the working directory; see
+	// SandboxDir. ClaudeDirEnv and GlobalClaudeDirEnv still win per scope.
+	ConfigDirEnv = "BT_CONFIG_DIR"
+)
+
+// Subdirectories of the ConfigDirEnv sandbox
+const (
+	// SandboxProjectDir stands in for the project's ./.claude
+	SandboxProjectDir = "project"
+	// SandboxGlobalDir stands in for ~/.claude
+	SandboxGlobalDir = "global"
+	// SandboxXDGConfigDir stands in for $XDG_CONFIG_HOME/blues-traveler
+	SandboxXDGConfigDir = "xdg"
+	// SandboxXDGCacheDir stands in for $XDG_CACHE_HOME/blues-traveler
+	SandboxXDGCacheDir = "cache"
+)
+
+// SandboxDir returns the sandbox subdirectory sub of BT_CONFIG_DIR, or ""
+// when no sandbox is set
+func SandboxDir(sub string) string {
+	dir := os.Getenv(ConfigDirEnv)
+	if dir == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return filepath.Join(dir, sub)
+}
+
+// SetConfigDirOverride points every config location at the sandbox dir; an
+// empty dir leaves them unchanged. A relative path is made absolute against
+// the current directory.
+func SetConfigDirOverride(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid directory '%s': %w", dir, err)
+	}
+	if err := os.Setenv(ConfigDirEnv, abs); err != nil {
+		return fmt.Errorf("failed to set %s: %w", ConfigDirEnv, err)
+	}
+	return nil
+}
+
+// SetClaudeDirOverrides points the project and global .claude directories
+// at project and global; empty values leave a scope unchanged. Relative
+// paths are made absolute against the current directory.
+func SetClaudeDirOverrides(project, global string) error {
+	for _, o := range []struct{ env, dir string }{{ClaudeDirEnv, project}, {GlobalClaudeDirEnv, global}} {
+		if o.dir == "" {
+			continue
+		}
+		abs, err := filepath.Abs(o.dir)
+		if err != nil {
+			return fmt.Errorf("invalid directory '%s': %w", o.dir, err)
+		}
+		if err := os.Setenv(o.env, abs); err != nil {
+			return fmt.Errorf("failed to set %s: %w", o.env, err)
+		}
+	}
+	return nil
+}
+
+// ProjectClaudeDir returns the project's .claude directory: BT_CLAUDE_DIR
+// when set, else project/ in the BT_CONFIG_DIR sandbox, else ./.claude
+func ProjectClaudeDir() (string, error) {
+	if dir := projectClaudeDirOverride(); dir != "" {
+		return filepath.Abs(dir)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return filepath.Join(cwd, constants.ClaudeDir), nil
+}
+
+// GlobalClaudeDir returns the user's .claude directory: BT_GLOBAL_CLAUDE_DIR
+// when set, else global/ in the BT_CONFIG_DIR sandbox, else CLAUDE_CONFIG_DIR,
+// else ~/.claude
+func GlobalClaudeDir() (string, error) {
+	if dir := os.Getenv(GlobalClaudeDirEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if dir := SandboxDir(SandboxGlobalDir); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv(claudeConfigDirEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, constants.ClaudeDir), nil
+}
+
+// ProjectClaudeDirOverridden reports whether the project .claude directory
+// is relocated by BT_CLAUDE_DIR or a BT_CONFIG_DIR sandbox rather than
+// found in the current directory
+func ProjectClaudeDirOverridden() bool {
+	return projectClaudeDirOverride() != ""
+}
+
+// projectClaudeDirOverride returns the relocated project .claude directory,
+// or "" when it is ./.claude
+func projectClaudeDirOverride() string {
+	if dir := os.Getenv(ClaudeDirEnv); dir != "" {
+		return dir
+	}
+	return SandboxDir(SandboxProjectDir)
+}
+
+// ClaudeDirFor returns the global or project .claude directory
+func ClaudeDirFor(global bool) (string, error) {
+	if global {
+		return GlobalClaudeDir()
+	}
+	return ProjectClaudeDir()
+}
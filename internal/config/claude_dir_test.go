@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClaudeDirOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ClaudeDirEnv, "")
+	t.Setenv(GlobalClaudeDirEnv, "")
+	t.Setenv(claudeConfigDirEnv, "")
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	cwd, _ = os.Getwd()
+
+	path, err := GetSettingsPath(false)
+	if err != nil || path != filepath.Join(cwd, ".claude", "settings.json") {
+		t.Fatalf("unexpected default project settings path %s (%v)", path, err)
+	}
+	path, err = GetLogConfigPath(true)
+	if err != nil || path != filepath.Join(home, ".claude", "hooks", "blues-traveler-config.json") {
+		t.Fatalf("unexpected default global config path %s (%v)", path, err)
+	}
+
+	t.Setenv(claudeConfigDirEnv, filepath.Join(home, "claude-config"))
+	if dir, _ := GlobalClaudeDir(); dir != filepath.Join(home, "claude-config") {
+		t.Errorf("expected CLAUDE_CONFIG_DIR to relocate the global directory, got %s", dir)
+	}
+
+	if err := SetClaudeDirOverrides("alt/.claude", filepath.Join(home, "global")); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(ClaudeDirEnv); got != filepath.Join(cwd, "alt", ".claude") {
+		t.Errorf("expected an absolute project override, got %s", got)
+	}
+	path, _ = GetSettingsPath(false)
+	if path != filepath.Join(cwd, "alt", ".claude", "settings.json") {
+		t.Errorf("unexpected project settings path %s", path)
+	}
+	path, _ = GetSettingsPath(true)
+	if path != filepath.Join(home, "global", "settings.json") {
+		t.Errorf("expected the flag to win over CLAUDE_CONFIG_DIR, got %s", path)
+	}
+	if got := GetLogPath("debug"); got != filepath.Join(cwd, "alt", ".claude", "hooks", "debug.log") {
+		t.Errorf("unexpected log path %s", got)
+	}
+
+	dir, err := EnsureClaudeDir(false)
+	if err != nil || dir != filepath.Join(cwd, "alt", ".claude") {
+		t.Fatalf("unexpected sample directory %s (%v)", dir, err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "hooks")); err != nil || !info.IsDir() {
+		t.Errorf("expected %s/hooks to be created", dir)
+	}
+}
+
+func TestConfigDirSandbox(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv(ClaudeDirEnv, "")
+	t.Setenv(GlobalClaudeDirEnv, "")
+	t.Setenv(claudeConfigDirEnv, filepath.Join(home, "claude-config"))
+	t.Setenv(ConfigDirEnv, "")
+	t.Chdir(t.TempDir())
+
+	sandbox := t.TempDir()
+	if err := SetConfigDirOverride(sandbox); err != nil {
+		t.Fatal(err)
+	}
+	for name, tt := range map[string]struct {
+		got  func() (string, error)
+		want string
+	}{
+		"project settings": {func() (string, error) { return GetSettingsPath(false) }, filepath.Join(sandbox, "project", "settings.json")},
+		"global settings":  {func() (string, error) { return GetSettingsPath(true) }, filepath.Join(sandbox, "global", "settings.json")},
+		"global config":    {func() (string, error) { return GetLogConfigPath(true) }, filepath.Join(sandbox, "global", "hooks", "blues-traveler-config.json")},
+		"log":              {func() (string, error) { return GetLogPath("debug"), nil }, filepath.Join(sandbox, "project", "hooks", "debug.log")},
+		"xdg config":       {func() (string, error) { return NewXDGConfig().GetConfigDir(), nil }, filepath.Join(sandbox, "xdg")},
+		"xdg cache":        {func() (string, error) { return XDGCacheDir(), nil }, filepath.Join(sandbox, "cache")},
+	} {
+		if got, err := tt.got(); err != nil || got != tt.want {
+			t.Errorf("%s: got %s (%v), want %s", name, got, err, tt.want)
+		}
+	}
+
+	// A per-scope override still wins over the sandbox
+	if err := SetClaudeDirOverrides("", filepath.Join(home, "global")); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _ := GlobalClaudeDir(); dir != filepath.Join(home, "global") {
+		t.Errorf("expected --global-claude-dir to win over the sandbox, got %s", dir)
+	}
+}
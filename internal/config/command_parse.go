@@ -0,0 +1,157 @@
+package config
+
+import (
+	"strings"
+)
+
+// commandToken is one word of a hook command line along with its byte span
+// in the original string, so callers can rewrite commands in place without
+// disturbing the user's quoting.
+type commandToken struct {
+	Value string
+	Start int
+	End   int
+}
+
+// tokenizeCommand splits a hook command line into words, shellwords-style.
+// Single and double quotes group words. Backslash only escapes a quote,
+// whitespace, or another backslash that follows it inside double quotes;
+// everywhere else it is literal so Windows paths like C:\Tools\bt.exe survive
+// intact. Unterminated quotes run to the end of the line.
+func tokenizeCommand(command string) []commandToken {
+	var tokens []commandToken
+	var cur strings.Builder
+	start := -1
+	inSingle, inDouble := false, false
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, commandToken{Value: cur.String(), Start: start, End: end})
+		}
+		cur.Reset()
+		start = -1
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			} else {
+				cur.WriteByte(c)
+			}
+		case inDouble:
+			if c == '"' {
+				inDouble = false
+			} else if c == '\\' && i+1 < len(command) && (command[i+1] == '"' || command[i+1] == '\\') {
+				i++
+				cur.WriteByte(command[i])
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush(i)
+		default:
+			if start < 0 {
+				start = i
+			}
+			switch {
+			case c == '\'':
+				inSingle = true
+			case c == '"':
+				inDouble = true
+			case c == '\\' && i+1 < len(command) && isEscapableOutsideQuotes(command[i+1]):
+				i++
+				cur.WriteByte(command[i])
+			default:
+				cur.WriteByte(c)
+			}
+		}
+	}
+	flush(len(command))
+	return tokens
+}
+
+// isEscapableOutsideQuotes reports whether a backslash before c is a POSIX
+// escape (e.g. "/My\ Tools/blues-traveler") rather than a Windows separator
+func isEscapableOutsideQuotes(c byte) bool {
+	return c == ' ' || c == '\t' || c == '"' || c == '\''
+}
+
+// parsedHookCommand is the structured form of a blues-traveler hook command
+type parsedHookCommand struct {
+	// Executable is the binary path as written (unquoted); empty when the
+	// command only matched on "hooks run"
+	Executable string
+	// HookType is the plugin key being run, e.g. "security" or "config:g:j"
+	HookType string
+	// Legacy is true for the deprecated "blues-traveler run X" form
+	Legacy bool
+	// Args holds everything after the hook type (flags such as --log)
+	Args []string
+	// runToken is the "run" word, used to rewrite legacy commands in place
+	runToken commandToken
+	// execToken is the executable word, used to repoint stale binary paths
+	execToken commandToken
+}
+
+// parseHookCommand recognizes "<exe> hooks run <key> [flags]" and the legacy
+// "<blues-traveler> run <key> [flags]". The canonical form is accepted with
+// any executable name so renamed or wrapped binaries still match; the legacy
+// form requires the executable to be blues-traveler itself, since "x run y" is
+// too common to claim.
+func parseHookCommand(command string) (parsedHookCommand, bool) {
+	tokens := tokenizeCommand(command)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].Value == "hooks" && tokens[i+1].Value == "run" {
+			p := parsedHookCommand{runToken: tokens[i+1]}
+			if i > 0 {
+				p.Executable = tokens[i-1].Value
+				p.execToken = tokens[i-1]
+			}
+			fillHookTypeAndArgs(&p, tokens[i+2:])
+			return p, true
+		}
+		if isBluesTravelerExecutable(tokens[i].Value) && tokens[i+1].Value == "run" {
+			p := parsedHookCommand{Executable: tokens[i].Value, Legacy: true, runToken: tokens[i+1], execToken: tokens[i]}
+			fillHookTypeAndArgs(&p, tokens[i+2:])
+			return p, true
+		}
+	}
+	return parsedHookCommand{}, false
+}
+
+func fillHookTypeAndArgs(p *parsedHookCommand, rest []commandToken) {
+	if len(rest) == 0 {
+		return
+	}
+	p.HookType = rest[0].Value
+	for _, t := range rest[1:] {
+		p.Args = append(p.Args, t.Value)
+	}
+}
+
+// isBluesTravelerExecutable reports whether path names the blues-traveler
+// binary, using both / and \ as separators regardless of host OS
+func isBluesTravelerExecutable(path string) bool {
+	base := path
+	if idx := strings.LastIndexAny(base, `/\`); idx >= 0 {
+		base = base[idx+1:]
+	}
+	base = strings.TrimSuffix(strings.ToLower(base), ".exe")
+	return base == "blues-traveler"
+}
+
+// configGroupFromHookType returns the group of a "config:<group>:<job>" key
+func configGroupFromHookType(hookType string) string {
+	rest, ok := strings.CutPrefix(hookType, "config:")
+	if !ok {
+		return ""
+	}
+	group, _, ok := strings.Cut(rest, ":")
+	if !ok || group == "" {
+		return ""
+	}
+	return group
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+	}{
+		{"simple", "/usr/bin/blues-traveler hooks run security", []string{"/usr/bin/blues-traveler", "hooks", "run", "security"}},
+		{"extra whitespace", "  bt\thooks   run x  ", []string{"bt", "hooks", "run", "x"}},
+		{"double quoted path with spaces", `"/Users/me/My Tools/blues-traveler" hooks run format`, []string{"/Users/me/My Tools/blues-traveler", "hooks", "run", "format"}},
+		{"single quoted path", `'/opt/my bin/blues-traveler' run vet`, []string{"/opt/my bin/blues-traveler", "run", "vet"}},
+		{"posix escaped space", `/opt/my\ bin/blues-traveler hooks run vet`, []string{"/opt/my bin/blues-traveler", "hooks", "run", "vet"}},
+		{"windows backslashes kept", `C:\Tools\blues-traveler.exe hooks run audit`, []string{`C:\Tools\blues-traveler.exe`, "hooks", "run", "audit"}},
+		{"windows quoted program files", `"C:\Program Files\blues-traveler\blues-traveler.exe" hooks run debug --log`, []string{`C:\Program Files\blues-traveler\blues-traveler.exe`, "hooks", "run", "debug", "--log"}},
+		{"unc path", `\\server\share\blues-traveler.exe hooks run x`, []string{`\\server\share\blues-traveler.exe`, "hooks", "run", "x"}},
+		{"escaped quote inside double quotes", `"a \"b\" c" d`, []string{`a "b" c`, "d"}},
+		{"adjacent quoted segments", `"C:\My Dir"\blues-traveler.exe run x`, []string{`C:\My Dir\blues-traveler.exe`, "run", "x"}},
+		{"empty quoted arg", `bt hooks run x --log-format ""`, []string{"bt", "hooks", "run", "x", "--log-format", ""}},
+		{"unterminated quote", `"C:\Program Files\bt hooks run x`, []string{`C:\Program Files\bt hooks run x`}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tok := range tokenizeCommand(tt.command) {
+				got = append(got, tok.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeCommand(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+//nolint:funlen // Cross-platform test matrix
+func TestCommandParsingHelpers_CrossPlatform(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		isBT       bool   // IsBluesTravelerCommand
+		hookType   string // extractHookType
+		group      string // extractConfigGroupName
+		matchesKey string // matchesHookType should be true for this key
+	}{
+		{
+			name:       "unix canonical",
+			command:    "/usr/local/bin/blues-traveler hooks run security --log",
+			isBT:       true,
+			hookType:   "security",
+			matchesKey: "security",
+		},
+		{
+			name:       "unix legacy",
+			command:    "/usr/local/bin/blues-traveler run security",
+			isBT:       true,
+			hookType:   "security",
+			matchesKey: "security",
+		},
+		{
+			name:       "macOS path with spaces quoted",
+			command:    `"/Users/me/Library/Application Support/bt/blues-traveler" hooks run config:py:lint`,
+			isBT:       true,
+			hookType:   "config:py:lint",
+			group:      "py",
+			matchesKey: "config:py:lint",
+		},
+		{
+			name:       "windows drive letter",
+			command:    `C:\bin\blues-traveler.exe hooks run config:web:eslint --log-format pretty`,
+			isBT:       true,
+			hookType:   "config:web:eslint",
+			group:      "web",
+			matchesKey: "config:web:eslint",
+		},
+		{
+			name:       "windows program files quoted",
+			command:    `"C:\Program Files\Blues Traveler\blues-traveler.exe" hooks run format`,
+			isBT:       true,
+			hookType:   "format",
+			matchesKey: "format",
+		},
+		{
+			name:       "windows program files unquoted",
+			command:    `C:\Program Files\Blues Traveler\blues-traveler.exe hooks run audit`,
+			isBT:       true,
+			hookType:   "audit",
+			matchesKey: "audit",
+		},
+		{
+			name:       "windows legacy quoted exe",
+			command:    `"C:\Program Files\bt\BLUES-TRAVELER.EXE" run vet`,
+			isBT:       true,
+			hookType:   "vet",
+			matchesKey: "vet",
+		},
+		{
+			name:       "windows drive in config dir does not confuse group",
+			command:    `D:\config\blues-traveler.exe hooks run config:ci:test`,
+			isBT:       true,
+			hookType:   "config:ci:test",
+			group:      "ci",
+			matchesKey: "config:ci:test",
+		},
+		{
+			name:       "renamed binary canonical form",
+			command:    "/opt/bt hooks run debug",
+			isBT:       true,
+			matchesKey: "debug",
+		},
+		{
+			name:    "unrelated run command",
+			command: "npm run build",
+		},
+		{
+			name:    "hooks run inside quoted echo",
+			command: `echo "hooks run security"`,
+		},
+		{
+			name:    "config-like text in an unrelated command",
+			command: `C:\tools\other.exe --config:foo:bar`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBluesTravelerCommand(tt.command); got != tt.isBT {
+				t.Errorf("IsBluesTravelerCommand = %v, want %v", got, tt.isBT)
+			}
+			if got := extractHookType(tt.command); got != tt.hookType {
+				t.Errorf("extractHookType = %q, want %q", got, tt.hookType)
+			}
+			if got := extractConfigGroupName(tt.command); got != tt.group {
+				t.Errorf("extractConfigGroupName = %q, want %q", got, tt.group)
+			}
+			if tt.matchesKey != "" && !matchesHookType(tt.command, tt.matchesKey) {
+				t.Errorf("matchesHookType(%q) = false, want true", tt.matchesKey)
+			}
+			if matchesHookType(tt.command, tt.matchesKey+"-other") {
+				t.Errorf("matchesHookType should not match a different key")
+			}
+		})
+	}
+}
+
+func TestNormalizeLegacyCommand_PreservesQuoting(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{`"C:\Program Files\bt\blues-traveler.exe" run vet --log`, `"C:\Program Files\bt\blues-traveler.exe" hooks run vet --log`},
+		{`/opt/my\ bin/blues-traveler   run x`, `/opt/my\ bin/blues-traveler   hooks run x`},
+		{`'/opt/bin/blues-traveler' run config:g:j`, `'/opt/bin/blues-traveler' hooks run config:g:j`},
+	}
+	for _, tt := range tests {
+		got, changed := NormalizeLegacyCommand(tt.command)
+		if !changed || got != tt.want {
+			t.Errorf("NormalizeLegacyCommand(%q) = (%q, %v), want %q", tt.command, got, changed, tt.want)
+		}
+	}
+}
+
+func TestRemoveConfigGroupFromSettings_WindowsPaths(t *testing.T) {
+	s := &Settings{Hooks: HooksConfig{PostToolUse: []HookMatcher{{Matcher: "*", Hooks: []HookCommand{
+		{Type: "command", Command: `"C:\Program Files\bt\blues-traveler.exe" hooks run config:web:lint`},
+		{Type: "command", Command: `C:\bin\blues-traveler.exe hooks run config:webapp:lint`},
+		{Type: "command", Command: `C:\bin\blues-traveler.exe hooks run format`},
+	}}}}}
+
+	if removed := RemoveConfigGroupFromSettings(s, "web", ""); removed != 1 {
+		t.Fatalf("removed = %d, want 1", removed)
+	}
+	if got := len(s.Hooks.PostToolUse[0].Hooks); got != 2 {
+		t.Errorf("remaining hooks = %d, want 2", got)
+	}
+	groups := GetConfigGroupsInSettings(s)
+	if !groups["webapp"] || groups["web"] || len(groups) != 1 {
+		t.Errorf("groups = %v, want only webapp", groups)
+	}
+}
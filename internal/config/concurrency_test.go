@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// These tests simulate several Claude sessions updating the same files at
+// once. Run them with -race (task test-concurrent) to also catch data races.
+
+const concurrentWriters = 12
+
+// runConcurrently starts n goroutines at the same moment and waits for them
+func runConcurrently(t *testing.T, n int, fn func(i int) error) {
+	t.Helper()
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := fn(i); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentSettingsUpdates_NoLostWrites(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), ".claude", "settings.json")
+
+	runConcurrently(t, concurrentWriters, func(i int) error {
+		release, err := LockFile(path)
+		if err != nil {
+			return err
+		}
+		defer release()
+		settings, err := LoadSettings(path)
+		if err != nil {
+			return fmt.Errorf("writer %d: %w", i, err)
+		}
+		AddHookToSettings(settings, "PreToolUse", "*", fmt.Sprintf("blues-traveler hooks run hook-%d", i), nil)
+		return SaveSettings(path, settings)
+	})
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("settings corrupted: %v", err)
+	}
+	if got := len(InstalledHooks(settings.Hooks)); got != concurrentWriters {
+		t.Errorf("expected %d hooks after concurrent installs, got %d", concurrentWriters, got)
+	}
+}
+
+func TestConcurrentSettingsReads_NeverSeePartialWrites(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "settings.json")
+	settings := &Settings{}
+	for i := range 50 {
+		AddHookToSettings(settings, "PostToolUse", "Edit", fmt.Sprintf("blues-traveler hooks run fmt-%d", i), nil)
+	}
+	if err := SaveSettings(path, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	runConcurrently(t, concurrentWriters, func(i int) error {
+		for range 20 {
+			if i%2 == 0 {
+				if err := SaveSettings(path, settings); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := LoadSettings(path); err != nil {
+				return fmt.Errorf("reader saw a partial write: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func TestConcurrentRegisterProject(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	x := &XDGConfig{BaseDir: t.TempDir()}
+
+	runConcurrently(t, concurrentWriters, func(i int) error {
+		return x.RegisterProject(fmt.Sprintf("/work/project-%d", i), FormatJSON)
+	})
+
+	registry, err := x.LoadRegistry()
+	if err != nil {
+		t.Fatalf("registry corrupted: %v", err)
+	}
+	if len(registry.Projects) != concurrentWriters {
+		t.Errorf("expected %d projects, got %d", concurrentWriters, len(registry.Projects))
+	}
+}
+
+func TestConcurrentBuiltinManifestUpdates(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	runConcurrently(t, concurrentWriters, func(i int) error {
+		hook := fmt.Sprintf("hook-%d", i)
+		return RecordBuiltinInstall(false, &Settings{}, BuiltinInstall{Hook: hook, Event: "PreToolUse", Command: "blues-traveler hooks run " + hook})
+	})
+
+	path, err := BuiltinManifestPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadBuiltinManifest(path)
+	if err != nil || m == nil {
+		t.Fatalf("manifest unreadable: %v", err)
+	}
+	if len(m.Hooks) != concurrentWriters {
+		t.Errorf("expected %d tracked installs, got %d", concurrentWriters, len(m.Hooks))
+	}
+}
@@ -204,8 +204,36 @@ func (e *EnhancedConfigLoader) convertToLogConfig(configData map[string]interfac
 
 	// Remove known fields from raw data
 	delete(raw, "logRotation")
+	delete(raw, "logging")
 	delete(raw, "customHooks")
 	delete(raw, "blockedUrls")
+	delete(raw, "blockedUrlsRemote")
+	delete(raw, "codeOwners")
+	delete(raw, "largeFiles")
+	delete(raw, "deleteGuard")
+	delete(raw, "secrets")
+	delete(raw, "lockfileChurn")
+	delete(raw, "gitGuard")
+	delete(raw, "mcpGuard")
+	delete(raw, "denyTools")
+	delete(raw, "pathGuard")
+	delete(raw, "prReadiness")
+	delete(raw, "coverageGate")
+	delete(raw, "notify")
+	delete(raw, "changelog")
+	delete(raw, "format")
+	delete(raw, "audit")
+	delete(raw, "context")
+	delete(raw, "mergePolicy")
+	delete(raw, "latencyThreshold")
+	delete(raw, "experiments")
+	delete(raw, "metrics")
+	delete(raw, "terseOutput")
+	delete(raw, "redaction")
+	delete(raw, "retention")
+	delete(raw, "execPath")
+	delete(raw, "extendsPath")
+	delete(raw, "includes")
 	config.Other = raw
 
 	return config, nil
@@ -520,8 +520,8 @@ func TestConvertFromLogConfig(t *testing.T) {
 			Compress:   false,
 		},
 		CustomHooks: CustomHooksConfig{
-			"test-group": HookGroup{
-				"PreToolUse": &EventConfig{
+			"test-group": &HookGroup{Events: map[string]*EventConfig{
+				"PreToolUse": {
 					Jobs: []HookJob{
 						{
 							Name: "test-job",
@@ -529,7 +529,7 @@ func TestConvertFromLogConfig(t *testing.T) {
 						},
 					},
 				},
-			},
+			}},
 		},
 		Other: map[string]interface{}{
 			"preservedField": "preservedValue",
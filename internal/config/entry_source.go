@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntrySourceKind says how a settings entry got there
+type EntrySourceKind string
+
+const (
+	// SourceBuiltin is a built-in hook added by 'hooks install'
+	SourceBuiltin EntrySourceKind = "built-in"
+	// SourceConfigGroup is a job written by 'config sync' or 'hooks custom install'
+	SourceConfigGroup EntrySourceKind = "config"
+	// SourceExternal is anything blues-traveler didn't write
+	SourceExternal EntrySourceKind = "external"
+)
+
+// EntrySource is the origin of one settings entry
+type EntrySource struct {
+	Kind EntrySourceKind `json:"kind"`
+	// Hook is the built-in hook key
+	Hook string `json:"hook,omitempty"`
+	// Group and Job are set for config group entries
+	Group string `json:"group,omitempty"`
+	Job   string `json:"job,omitempty"`
+	// Missing is set for config group entries whose job is no longer in the
+	// hooks config for the entry's event, so the next sync prunes them
+	Missing bool `json:"missing,omitempty"`
+}
+
+// EntrySourceOf classifies command, an entry under event, by parsing it and
+// looking its group and job up in hooksCfg. With a nil hooksCfg jobs are
+// never reported missing.
+func EntrySourceOf(command, event string, hooksCfg *CustomHooksConfig) EntrySource {
+	hookType := extractHookType(command)
+	if hookType == "" {
+		return EntrySource{Kind: SourceExternal}
+	}
+	group := configGroupFromHookType(hookType)
+	if group == "" {
+		return EntrySource{Kind: SourceBuiltin, Hook: hookType}
+	}
+	job := strings.TrimPrefix(hookType, "config:"+group+":")
+	return EntrySource{Kind: SourceConfigGroup, Group: group, Job: job, Missing: hooksCfg != nil && !hasGroupJob(hooksCfg, group, event, job)}
+}
+
+// hasGroupJob reports whether group defines job for event
+func hasGroupJob(hooksCfg *CustomHooksConfig, group, event, job string) bool {
+	g := (*hooksCfg)[group]
+	if g == nil || g.Events[event] == nil {
+		return false
+	}
+	for _, j := range g.Events[event].Jobs {
+		if j.Name == job {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the source for listings
+func (s EntrySource) String() string {
+	switch s.Kind {
+	case SourceBuiltin:
+		return fmt.Sprintf("built-in install (%s)", s.Hook)
+	case SourceConfigGroup:
+		desc := fmt.Sprintf("config group %s, job %s", s.Group, s.Job)
+		if s.Missing {
+			desc += "; not in hooks config"
+		}
+		return desc
+	}
+	return "added externally"
+}
+
+// InGroup reports whether the entry belongs to group. Built-in installs
+// belong to BuiltinGroupName, as they do for 'config sync --group'.
+func (s EntrySource) InGroup(group string) bool {
+	switch s.Kind {
+	case SourceBuiltin:
+		return group == BuiltinGroupName
+	case SourceConfigGroup:
+		return group == s.Group
+	}
+	return false
+}
+
+// OrphanedEntry is a config group entry in settings whose job is no longer
+// defined for its event in the hooks config
+type OrphanedEntry struct {
+	Event   string `json:"event"`
+	Matcher string `json:"matcher"`
+	Group   string `json:"group"`
+	Job     string `json:"job"`
+	Command string `json:"command"`
+}
+
+// OrphanedEntries lists the orphaned config group entries in settings,
+// limited to group and event when they are set
+func OrphanedEntries(settings *Settings, hooksCfg *CustomHooksConfig, group, event string) []OrphanedEntry {
+	if settings == nil {
+		return nil
+	}
+	if hooksCfg == nil {
+		hooksCfg = &CustomHooksConfig{}
+	}
+	var orphans []OrphanedEntry
+	for _, e := range SettingsEntries(settings.Hooks) {
+		if event != "" && e.Event != event {
+			continue
+		}
+		src := EntrySourceOf(e.Command, e.Event, hooksCfg)
+		if !src.Missing || (group != "" && src.Group != group) {
+			continue
+		}
+		orphans = append(orphans, OrphanedEntry{Event: e.Event, Matcher: e.Matcher, Group: src.Group, Job: src.Job, Command: e.Command})
+	}
+	return orphans
+}
+
+// RemoveOrphanedEntries removes orphans from settings and returns how many
+// entries were removed
+func RemoveOrphanedEntries(settings *Settings, orphans []OrphanedEntry) int {
+	removed := 0
+	for _, o := range orphans {
+		removed += RemoveHookTypeFromScope(settings, "config:"+o.Group+":"+o.Job, o.Event, o.Matcher)
+	}
+	return removed
+}
@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestEntrySourceOf(t *testing.T) {
+	hooksCfg := &CustomHooksConfig{
+		"lint": {Events: map[string]*EventConfig{
+			"PostToolUse": {Jobs: []HookJob{{Name: "eslint", Run: "eslint ."}}},
+		}},
+	}
+	tests := []struct {
+		command, event string
+		want           EntrySource
+		desc           string
+	}{
+		{"/usr/local/bin/blues-traveler hooks run security", "PreToolUse", EntrySource{Kind: SourceBuiltin, Hook: "security"}, "built-in install (security)"},
+		{"blues-traveler hooks run config:lint:eslint", "PostToolUse", EntrySource{Kind: SourceConfigGroup, Group: "lint", Job: "eslint"}, "config group lint, job eslint"},
+		{"blues-traveler hooks run config:lint:eslint", "Stop", EntrySource{Kind: SourceConfigGroup, Group: "lint", Job: "eslint", Missing: true}, "config group lint, job eslint; not in hooks config"},
+		{"blues-traveler hooks run config:gone:job", "Stop", EntrySource{Kind: SourceConfigGroup, Group: "gone", Job: "job", Missing: true}, "config group gone, job job; not in hooks config"},
+		{"./scripts/notify.sh", "Stop", EntrySource{Kind: SourceExternal}, "added externally"},
+	}
+	for _, tt := range tests {
+		got := EntrySourceOf(tt.command, tt.event, hooksCfg)
+		if got != tt.want {
+			t.Errorf("EntrySourceOf(%q, %s) = %+v, want %+v", tt.command, tt.event, got, tt.want)
+		}
+		if got.String() != tt.desc {
+			t.Errorf("String() = %q, want %q", got.String(), tt.desc)
+		}
+	}
+
+	if got := EntrySourceOf("blues-traveler hooks run config:gone:job", "Stop", nil); got.Missing {
+		t.Error("expected no missing flag without a hooks config")
+	}
+	if !(EntrySource{Kind: SourceBuiltin, Hook: "audit"}).InGroup(BuiltinGroupName) {
+		t.Errorf("expected built-ins to be in %s", BuiltinGroupName)
+	}
+	if (EntrySource{Kind: SourceExternal}).InGroup("lint") {
+		t.Error("expected external entries to be in no group")
+	}
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// EnvFiles lists .env-style files for a job. In hooks.yml (or .json/.toml)
+// it is written as a single path or a list of paths.
+type EnvFiles []string
+
+// UnmarshalYAML accepts a string or a sequence of strings
+func (e *EnvFiles) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var s string
+		if err := node.Decode(&s); err != nil {
+			return err
+		}
+		*e = EnvFiles{s}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return fmt.Errorf("env_file must be a path or a list of paths: %w", err)
+	}
+	*e = list
+	return nil
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (e *EnvFiles) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*e = EnvFiles{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("env_file must be a path or a list of paths: %w", err)
+	}
+	*e = list
+	return nil
+}
+
+// UnmarshalTOML accepts a string or an array of strings
+func (e *EnvFiles) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		*e = EnvFiles{v}
+		return nil
+	case []interface{}:
+		list := make(EnvFiles, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("env_file must be a path or a list of paths, found %T in the list", item)
+			}
+			list = append(list, s)
+		}
+		*e = list
+		return nil
+	default:
+		return fmt.Errorf("env_file must be a path or a list of paths, got %T", v)
+	}
+}
+
+// envKeyPattern matches the variable names accepted in env files
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidEnvKey reports whether key can name an environment variable
+func ValidEnvKey(key string) bool {
+	return envKeyPattern.MatchString(key)
+}
+
+// LoadEnvFiles reads paths in order and returns the variables they define,
+// later files overriding earlier ones. Relative paths resolve against dir
+// (the current directory when empty). $VAR and ${VAR} in unquoted and
+// double-quoted values expand to variables defined earlier in the files,
+// then to lookup.
+func LoadEnvFiles(paths []string, dir string, lookup func(string) (string, bool)) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, p := range paths {
+		path := ResolveEnvFilePath(p, dir)
+		data, err := os.ReadFile(path) // #nosec G304 - env files are named in the user's hooks config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read env_file: %w\n  Suggestion: Create the file or remove it from the job's env_file list", err)
+		}
+		if err := parseEnvFile(data, path, vars, lookup); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// ResolveEnvFilePath returns path as a job running in dir would see it;
+// a leading ~/ is expanded to the home directory
+func ResolveEnvFilePath(path, dir string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// parseEnvFile adds the KEY=VALUE lines in data to vars. Blank lines,
+// '#' comments and a leading "export " are ignored. Single-quoted values
+// are taken literally; double-quoted values support \n, \t, \" and \\.
+func parseEnvFile(data []byte, name string, vars map[string]string, lookup func(string) (string, bool)) error {
+	expand := func(s string) string {
+		return os.Expand(s, func(key string) string {
+			if key == "$" {
+				return "$"
+			}
+			if v, ok := vars[key]; ok {
+				return v
+			}
+			if lookup != nil {
+				if v, ok := lookup(key); ok {
+					return v
+				}
+			}
+			return ""
+		})
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKeyPattern.MatchString(key) {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", name, lineNo)
+		}
+		value, err := parseEnvValue(strings.TrimSpace(raw), expand)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", name, lineNo, key, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// parseEnvValue unquotes and expands one value
+func parseEnvValue(raw string, expand func(string) string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, `"`):
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			if c == '"' {
+				return expand(b.String()), nil
+			}
+			if c == '\\' && i+1 < len(raw) {
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\':
+					b.WriteByte(raw[i])
+				case '$':
+					// Keep the escape so expansion leaves a literal '$'
+					b.WriteString("$$")
+				default:
+					b.WriteByte('\\')
+					b.WriteByte(raw[i])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	}
+	// Unquoted: an inline comment starts at " #"
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	return expand(raw), nil
+}
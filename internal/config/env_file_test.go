@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	data := `# comment
+export PLAIN=value # trailing comment
+SPACED = padded
+SINGLE='literal $PLAIN'
+DOUBLE="line\nnext \"quoted\" \$PLAIN"
+EXPANDED=${PLAIN}-$FROM_LOOKUP
+BRACED="${MISSING}x"
+EMPTY=
+`
+	vars := map[string]string{}
+	lookup := func(key string) (string, bool) {
+		if key == "FROM_LOOKUP" {
+			return "looked-up", true
+		}
+		return "", false
+	}
+	if err := parseEnvFile([]byte(data), "test.env", vars, lookup); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"PLAIN":    "value",
+		"SPACED":   "padded",
+		"SINGLE":   "literal $PLAIN",
+		"DOUBLE":   "line\nnext \"quoted\" $PLAIN",
+		"EXPANDED": "value-looked-up",
+		"BRACED":   "x",
+		"EMPTY":    "",
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+	if len(vars) != len(want) {
+		t.Errorf("got %d variables, want %d: %v", len(vars), len(want), vars)
+	}
+}
+
+func TestParseEnvFileErrors(t *testing.T) {
+	for _, data := range []string{
+		"NOEQUALS\n",
+		"1BAD=x\n",
+		"OPEN=\"unterminated\n",
+		"OPEN='unterminated\n",
+	} {
+		err := parseEnvFile([]byte(data), "bad.env", map[string]string{}, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), "bad.env:1:") {
+			t.Errorf("%q: expected a bad.env:1 error, got %v", data, err)
+		}
+	}
+}
+
+func TestLoadEnvFilesOrder(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.env"), []byte("A=1\nB=from-a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.env"), []byte("B=from-b\nC=${A}${B}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vars, err := LoadEnvFiles([]string{"a.env", filepath.Join(dir, "b.env")}, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["A"] != "1" || vars["B"] != "from-b" || vars["C"] != "1from-b" {
+		t.Errorf("unexpected variables %v", vars)
+	}
+	if _, err := LoadEnvFiles([]string{"nope.env"}, dir, nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestEnvFilesUnmarshal(t *testing.T) {
+	var y struct {
+		One  EnvFiles `yaml:"one"`
+		Many EnvFiles `yaml:"many"`
+	}
+	if err := yaml.Unmarshal([]byte("one: .env\nmany: [.env, .env.local]\n"), &y); err != nil {
+		t.Fatal(err)
+	}
+	if len(y.One) != 1 || y.One[0] != ".env" || len(y.Many) != 2 || y.Many[1] != ".env.local" {
+		t.Errorf("unexpected YAML result %+v", y)
+	}
+
+	var j struct {
+		One  EnvFiles `json:"one"`
+		Many EnvFiles `json:"many"`
+	}
+	if err := json.Unmarshal([]byte(`{"one":".env","many":[".env",".env.local"]}`), &j); err != nil {
+		t.Fatal(err)
+	}
+	if len(j.One) != 1 || len(j.Many) != 2 {
+		t.Errorf("unexpected JSON result %+v", j)
+	}
+	if err := yaml.Unmarshal([]byte("one: {a: b}\n"), &y); err == nil {
+		t.Error("expected an error for a mapping")
+	}
+}
+
+func TestCheckJobEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ok.env"), []byte("A=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := CustomHooksConfig{
+		"g": &HookGroup{Events: map[string]*EventConfig{
+			"PreToolUse": {Jobs: []HookJob{
+				{Name: "ok", Run: "true", WorkDir: dir, EnvFile: EnvFiles{"ok.env"}},
+				{Name: "missing", Run: "true", WorkDir: dir, EnvFile: EnvFiles{"missing.env"}},
+			}},
+		}},
+	}
+	errs := CheckJobEnvFiles(&cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "job 'missing'") {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	cfg["g"].Events["PreToolUse"].Jobs[0].EnvFile = EnvFiles{" "}
+	if err := ValidateHooksConfig(&cfg); err == nil {
+		t.Error("expected an error for an empty env_file entry")
+	}
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ExecPathStrategy controls how installed hook commands reference the
+// blues-traveler binary
+type ExecPathStrategy string
+
+const (
+	// ExecPathAbsolute writes the running binary's absolute path (default)
+	ExecPathAbsolute ExecPathStrategy = "absolute"
+	// ExecPathPATH writes the bare name, resolved through PATH when hooks run
+	ExecPathPATH ExecPathStrategy = "path"
+	// ExecPathSymlink writes ~/.claude/bin/blues-traveler, a symlink that
+	// install and sync repoint at the current binary
+	ExecPathSymlink ExecPathStrategy = "symlink"
+
+	// execBinaryName is the bare command used by ExecPathPATH
+	execBinaryName = "blues-traveler"
+	// execSymlinkSubDir is the directory under ~/.claude holding the symlink
+	execSymlinkSubDir = "bin"
+)
+
+// ValidExecPathStrategies lists the accepted execPath values
+func ValidExecPathStrategies() []string {
+	return []string{string(ExecPathAbsolute), string(ExecPathPATH), string(ExecPathSymlink)}
+}
+
+// ParseExecPathStrategy validates an execPath value; empty selects absolute
+func ParseExecPathStrategy(name string) (ExecPathStrategy, error) {
+	switch ExecPathStrategy(strings.TrimSpace(name)) {
+	case "", ExecPathAbsolute:
+		return ExecPathAbsolute, nil
+	case ExecPathPATH:
+		return ExecPathPATH, nil
+	case ExecPathSymlink:
+		return ExecPathSymlink, nil
+	}
+	return "", fmt.Errorf("invalid execPath '%s'. Valid: %s", name, strings.Join(ValidExecPathStrategies(), ", "))
+}
+
+// ConfiguredExecPathStrategy returns the execPath setting for the scope.
+// Project installs fall back to the global config when the project sets none.
+func ConfiguredExecPathStrategy(global bool) (ExecPathStrategy, error) {
+	scopes := []bool{global}
+	if !global {
+		scopes = append(scopes, true)
+	}
+	for _, g := range scopes {
+		path, err := GetLogConfigPath(g)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil {
+			return ExecPathAbsolute, err
+		}
+		if cfg.ExecPath == "" {
+			continue
+		}
+		strategy, err := ParseExecPathStrategy(cfg.ExecPath)
+		if err != nil {
+			return ExecPathAbsolute, fmt.Errorf("%s: %w", path, err)
+		}
+		return strategy, nil
+	}
+	return ExecPathAbsolute, nil
+}
+
+// ExecSymlinkPath returns ~/.claude/bin/blues-traveler
+func ExecSymlinkPath() (string, error) {
+	dir, err := GlobalClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, execSymlinkSubDir, execBinaryName), nil
+}
+
+// HookExecutable returns the binary reference to write into hook commands,
+// quoted when it contains spaces (commands run via bash -lc). With the
+// symlink strategy the link is refreshed unless refreshLink is false (dry runs).
+func HookExecutable(strategy ExecPathStrategy, refreshLink bool) (string, error) {
+	var path string
+	switch strategy {
+	case ExecPathPATH:
+		if _, err := exec.LookPath(execBinaryName); err != nil {
+			return "", fmt.Errorf("execPath is 'path' but %s is not on PATH: %w\n  Suggestion: Install blues-traveler onto PATH or use execPath 'absolute'", execBinaryName, err)
+		}
+		return execBinaryName, nil
+	case ExecPathSymlink:
+		link, err := ExecSymlinkPath()
+		if refreshLink {
+			link, err = RefreshExecSymlink()
+		}
+		if err != nil {
+			return "", err
+		}
+		path = link
+	default:
+		exe, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to get executable path: %w", err)
+		}
+		path = exe
+	}
+	if strings.ContainsRune(path, ' ') {
+		path = `"` + path + `"`
+	}
+	return path, nil
+}
+
+// RefreshExecSymlink points ~/.claude/bin/blues-traveler at the running
+// binary and returns the symlink path. When the binary is reachable through
+// a stable PATH entry (such as Homebrew's bin symlink) that entry is used as
+// the target, so the link keeps working after package upgrades.
+func RefreshExecSymlink() (string, error) {
+	link, err := ExecSymlinkPath()
+	if err != nil {
+		return "", err
+	}
+	target, err := execSymlinkTarget()
+	if err != nil {
+		return "", err
+	}
+	if current, err := os.Readlink(link); err == nil && current == target {
+		return link, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(link), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(link), err)
+	}
+	// Build the new link beside the old one and rename it into place so a
+	// hook starting mid-refresh never sees the link missing
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return "", fmt.Errorf("failed to create symlink %s: %w\n  Suggestion: Use execPath 'path' or 'absolute' where symlinks are unavailable", link, err)
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("failed to update symlink %s: %w", link, err)
+	}
+	return link, nil
+}
+
+// execSymlinkTarget prefers the PATH entry for blues-traveler when it
+// resolves to the running binary, falling back to the binary itself
+func execSymlinkTarget() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get executable path: %w", err)
+	}
+	resolvedExe, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		resolvedExe = exe
+	}
+	link, _ := ExecSymlinkPath()
+	if onPath, err := exec.LookPath(execBinaryName); err == nil {
+		if abs, err := filepath.Abs(onPath); err == nil && abs != link {
+			if resolved, err := filepath.EvalSymlinks(abs); err == nil && resolved == resolvedExe {
+				return abs, nil
+			}
+		}
+	}
+	return exe, nil
+}
+
+// ExecSymlinkBroken reports a ~/.claude/bin/blues-traveler symlink whose
+// target no longer exists, e.g. after the binary was removed
+func ExecSymlinkBroken() (string, bool) {
+	link, err := ExecSymlinkPath()
+	if err != nil {
+		return "", false
+	}
+	if _, err := os.Lstat(link); err != nil {
+		return "", false
+	}
+	if _, err := os.Stat(link); err != nil {
+		return link, true
+	}
+	return link, false
+}
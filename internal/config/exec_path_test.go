@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+func TestParseExecPathStrategy(t *testing.T) {
+	for in, want := range map[string]ExecPathStrategy{"": ExecPathAbsolute, "absolute": ExecPathAbsolute, "path": ExecPathPATH, "symlink": ExecPathSymlink} {
+		if got, err := ParseExecPathStrategy(in); err != nil || got != want {
+			t.Errorf("ParseExecPathStrategy(%q) = (%q, %v), want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseExecPathStrategy("relative"); err == nil {
+		t.Error("unknown strategy should be rejected")
+	}
+}
+
+func TestConfiguredExecPathStrategy_FallsBackToGlobal(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+
+	if got, err := ConfiguredExecPathStrategy(false); err != nil || got != ExecPathAbsolute {
+		t.Fatalf("no config = (%q, %v), want absolute", got, err)
+	}
+
+	saveExecPath(t, home, "symlink")
+	if got, _ := ConfiguredExecPathStrategy(false); got != ExecPathSymlink {
+		t.Errorf("project should inherit global execPath, got %q", got)
+	}
+	saveExecPath(t, project, "path")
+	if got, _ := ConfiguredExecPathStrategy(false); got != ExecPathPATH {
+		t.Errorf("project execPath should win, got %q", got)
+	}
+	if got, _ := ConfiguredExecPathStrategy(true); got != ExecPathSymlink {
+		t.Errorf("global installs read only the global config, got %q", got)
+	}
+}
+
+func TestHookExecutable_Symlink(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+	link, err := ExecSymlinkPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := HookExecutable(ExecPathSymlink, false)
+	if err != nil || got != link {
+		t.Fatalf("dry run = (%q, %v), want %q", got, err, link)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Fatal("dry run must not create the symlink")
+	}
+
+	if got, err = HookExecutable(ExecPathSymlink, true); err != nil || got != link {
+		t.Fatalf("HookExecutable = (%q, %v), want %q", got, err, link)
+	}
+	exe, _ := os.Executable()
+	if target, err := os.Readlink(link); err != nil || target != exe {
+		t.Errorf("symlink target = (%q, %v), want %q", target, err, exe)
+	}
+	if _, broken := ExecSymlinkBroken(); broken {
+		t.Error("fresh symlink should not be reported broken")
+	}
+
+	// Simulate an upgrade that removed the old binary
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(t.TempDir(), "gone"), link); err != nil {
+		t.Fatal(err)
+	}
+	if _, broken := ExecSymlinkBroken(); !broken {
+		t.Error("dangling symlink should be reported")
+	}
+	if _, err := RefreshExecSymlink(); err != nil {
+		t.Fatal(err)
+	}
+	if target, _ := os.Readlink(link); target != exe {
+		t.Errorf("refresh should repoint the link, got %q", target)
+	}
+}
+
+func TestHookExecutable_PATH(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	if _, err := HookExecutable(ExecPathPATH, true); err == nil {
+		t.Fatal("expected an error when blues-traveler is not on PATH")
+	}
+	if err := os.WriteFile(filepath.Join(bin, "blues-traveler"), []byte("#!/bin/sh\n"), 0o755); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	if got, err := HookExecutable(ExecPathPATH, true); err != nil || got != "blues-traveler" {
+		t.Errorf("HookExecutable = (%q, %v), want bare name", got, err)
+	}
+}
+
+func saveExecPath(t *testing.T, root, strategy string) {
+	t.Helper()
+	path := constants.GetConfigPath(root)
+	if err := SaveLogConfig(path, &LogConfig{LogRotation: DefaultLogRotationConfig(), ExecPath: strategy}); err != nil {
+		t.Fatal(err)
+	}
+}
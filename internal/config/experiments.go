@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Experiment decisions: which responses an experiment rewrites
+const (
+	ExperimentDecisionBlock   = "block"
+	ExperimentDecisionApprove = "approve"
+)
+
+// Experiment A/B tests the wording of one hook's messages to the agent.
+// Each session is assigned one variant, whose template replaces the
+// message of every matching response from the hook in that session.
+type Experiment struct {
+	Name string `json:"name"`
+	// Hook is the key of the hook whose messages are rewritten
+	Hook string `json:"hook"`
+	// Decision is "block" (the default) or "approve"
+	Decision string `json:"decision,omitempty"`
+	// Variants maps a variant name to a text/template. Templates see
+	// .Message (the hook's own message), .Hook and .Tool.
+	Variants map[string]string `json:"variants"`
+	// Disabled stops assigning variants while keeping recorded results
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// MatchesDecision reports whether the experiment rewrites responses with
+// decision (block or approve)
+func (e Experiment) MatchesDecision(decision string) bool {
+	want := e.Decision
+	if want == "" {
+		want = ExperimentDecisionBlock
+	}
+	return want == decision
+}
+
+// VariantNames returns the variant names in sorted order, the order used to
+// assign them
+func (e Experiment) VariantNames() []string {
+	names := make([]string, 0, len(e.Variants))
+	for name := range e.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateExperiments checks names, decisions and templates
+func ValidateExperiments(experiments []Experiment) error {
+	seen := map[string]bool{}
+	for i, e := range experiments {
+		name := strings.TrimSpace(e.Name)
+		if name == "" {
+			return fmt.Errorf("experiment[%d] is missing a name", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("experiment '%s' is defined twice", name)
+		}
+		seen[name] = true
+		if strings.TrimSpace(e.Hook) == "" {
+			return fmt.Errorf("experiment '%s' is missing a hook", name)
+		}
+		if e.Decision != "" && e.Decision != ExperimentDecisionBlock && e.Decision != ExperimentDecisionApprove {
+			return fmt.Errorf("experiment '%s' has invalid decision '%s' (use %s or %s)", name, e.Decision, ExperimentDecisionBlock, ExperimentDecisionApprove)
+		}
+		if len(e.Variants) < 2 {
+			return fmt.Errorf("experiment '%s' needs at least two variants", name)
+		}
+		for variant, text := range e.Variants {
+			if _, err := template.New(variant).Option("missingkey=zero").Parse(text); err != nil {
+				return fmt.Errorf("experiment '%s' variant '%s': %w", name, variant, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadExperiments returns the experiments from the project config,
+// falling back to the global config, and fails if the list is invalid
+func LoadExperiments() ([]Experiment, error) {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || len(cfg.Experiments) == 0 {
+			continue
+		}
+		if err := ValidateExperiments(cfg.Experiments); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return cfg.Experiments, nil
+	}
+	return nil, nil
+}
+
+// GetExperiments is LoadExperiments for hook runs: an invalid list disables
+// all experiments rather than failing the hook
+func GetExperiments() []Experiment {
+	experiments, _ := LoadExperiments()
+	return experiments
+}
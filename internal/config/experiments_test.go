@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExperiments(t *testing.T) {
+	valid := Experiment{Name: "x", Hook: "security", Variants: map[string]string{"a": "{{.Message}}", "b": "{{.Message}}!"}}
+	if err := ValidateExperiments([]Experiment{valid}); err != nil {
+		t.Fatalf("valid experiment rejected: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		exps []Experiment
+		want string
+	}{
+		{"missing name", []Experiment{{Hook: "security", Variants: valid.Variants}}, "missing a name"},
+		{"duplicate", []Experiment{valid, valid}, "defined twice"},
+		{"missing hook", []Experiment{{Name: "x", Variants: valid.Variants}}, "missing a hook"},
+		{"bad decision", []Experiment{{Name: "x", Hook: "h", Decision: "ask", Variants: valid.Variants}}, "invalid decision"},
+		{"one variant", []Experiment{{Name: "x", Hook: "h", Variants: map[string]string{"a": "x"}}}, "at least two"},
+		{"bad template", []Experiment{{Name: "x", Hook: "h", Variants: map[string]string{"a": "{{", "b": "x"}}}, "variant 'a'"},
+	}
+	for _, tt := range tests {
+		err := ValidateExperiments(tt.exps)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want error containing %q", tt.name, err, tt.want)
+		}
+	}
+
+	if !valid.MatchesDecision(ExperimentDecisionBlock) || valid.MatchesDecision(ExperimentDecisionApprove) {
+		t.Error("experiments should rewrite blocks by default")
+	}
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+// ExtendsAuto makes extendsPath inherit from every ancestor directory with a
+// .claude directory, up to the git root
+const ExtendsAuto = "auto"
+
+// Hooks config layer scopes, as reported by LoadHooksConfigLayers
+const (
+	LayerScopeProject = "project"
+	LayerScopeParent  = "parent"
+	LayerScopeGlobal  = "global"
+)
+
+// HooksConfigLayer is one source merged into the effective hooks config
+type HooksConfigLayer struct {
+	// Scope is project, parent or global
+	Scope string
+	// Source is the file the layer was read from
+	Source string
+	// Include is the includes pattern that named Source, if one did
+	Include string
+	Config  CustomHooksConfig
+}
+
+// parentSource is an inherited config: a whole project directory (whose own
+// extendsPath is followed) or a single hooks file
+type parentSource struct {
+	Dir  string
+	File string
+}
+
+// LoadHooksConfigLayers returns the config sources behind LoadHooksConfig,
+// highest precedence first. Without an extendsPath in the project config the
+// layers are the project's and the global config, as always; with one, the
+// inherited parents sit between the two, nearest first.
+func LoadHooksConfigLayers() ([]HooksConfigLayer, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %v", err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	projectDir, err := ProjectClaudeDir()
+	if err != nil {
+		return nil, err
+	}
+	globalDir, err := GlobalClaudeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := resolveParentSources(cwd, projectDir, home)
+	if err != nil {
+		return nil, err
+	}
+	if len(parents) == 0 {
+		return legacyHooksConfigLayers(projectDir, globalDir)
+	}
+
+	layers, err := scopeLayers(LayerScopeProject, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range parents {
+		var parentLayers []HooksConfigLayer
+		if p.File != "" {
+			parentLayers, err = fileLayers(LayerScopeParent, []string{p.File})
+		} else {
+			parentLayers, err = scopeLayers(LayerScopeParent, filepath.Join(p.Dir, constants.ClaudeDir))
+		}
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, parentLayers...)
+	}
+	globalLayers, err := scopeLayers(LayerScopeGlobal, globalDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(layers, globalLayers...), nil
+}
+
+// legacyHooksConfigLayers keeps the original precedence: customHooks embedded
+// in the project (then global) main config replaces file discovery entirely.
+// projectDir and globalDir are the two .claude directories.
+func legacyHooksConfigLayers(projectDir, globalDir string) ([]HooksConfigLayer, error) {
+	for _, base := range []struct{ scope, dir string }{{LayerScopeProject, projectDir}, {LayerScopeGlobal, globalDir}} {
+		if layer, ok := embeddedLayer(base.scope, base.dir); ok {
+			return withEmbeddedIncludes(layer, base.dir)
+		}
+	}
+	project, err := hooksFileLayers(LayerScopeProject, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	global, err := hooksFileLayers(LayerScopeGlobal, globalDir)
+	if err != nil {
+		return nil, err
+	}
+	return append(project, global...), nil
+}
+
+// scopeLayers reads one .claude directory's config: its embedded
+// customHooks when present, otherwise its hooks files
+func scopeLayers(scope, claudeDir string) ([]HooksConfigLayer, error) {
+	if layer, ok := embeddedLayer(scope, claudeDir); ok {
+		return withEmbeddedIncludes(layer, claudeDir)
+	}
+	return hooksFileLayers(scope, claudeDir)
+}
+
+// withEmbeddedIncludes follows an embedded customHooks layer with the files
+// named by the same main config's includes, which embedding doesn't replace
+func withEmbeddedIncludes(layer HooksConfigLayer, claudeDir string) ([]HooksConfigLayer, error) {
+	patterns := hooksIncludes(claudeDir)
+	if len(patterns) == 0 {
+		return []HooksConfigLayer{layer}, nil
+	}
+	included, err := includedLayers(layer.Scope, claudeDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+	return append([]HooksConfigLayer{layer}, included...), nil
+}
+
+// embeddedLayer returns the customHooks embedded in claudeDir's main config file
+func embeddedLayer(scope, claudeDir string) (HooksConfigLayer, bool) {
+	path := filepath.Join(claudeDir, constants.HooksSubDir, constants.ConfigFileName)
+	cfg, err := LoadLogConfig(path)
+	if err != nil || cfg == nil || len(cfg.CustomHooks) == 0 {
+		return HooksConfigLayer{}, false
+	}
+	return HooksConfigLayer{Scope: scope, Source: path, Config: cloneHooksConfig(cfg.CustomHooks)}, true
+}
+
+// fileLayers parses each existing candidate file into its own layer
+func fileLayers(scope string, candidates []string) ([]HooksConfigLayer, error) {
+	var layers []HooksConfigLayer
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		cfg, err := parseHooksConfigFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+		layers = append(layers, HooksConfigLayer{Scope: scope, Source: p, Config: cfg})
+	}
+	return layers, nil
+}
+
+// resolveParentSources follows extendsPath from the project in dir, whose
+// .claude directory is claudeDir, nearest parent first. Directory targets
+// are followed in turn, so a service can extend a team directory that
+// extends the repository root.
+func resolveParentSources(dir, claudeDir, home string) ([]parentSource, error) {
+	var parents []parentSource
+	visited := map[string]bool{filepath.Clean(dir): true}
+	for {
+		next, explicit, err := extendsTargets(dir, claudeDir, home)
+		if err != nil {
+			return nil, err
+		}
+		var followed string
+		for _, p := range next {
+			key := p.Dir + p.File
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			parents = append(parents, p)
+			followed = p.Dir
+		}
+		// "auto" already walked every ancestor; only an explicit chain continues
+		if !explicit || followed == "" {
+			return parents, nil
+		}
+		dir, claudeDir = followed, filepath.Join(followed, constants.ClaudeDir)
+	}
+}
+
+// extendsTargets reads the extendsPath in claudeDir, the .claude directory
+// of the project in dir, and resolves it to parent sources. explicit is
+// false for "auto", whose result is already complete.
+func extendsTargets(dir, claudeDir, home string) (sources []parentSource, explicit bool, err error) {
+	configPath := filepath.Join(claudeDir, constants.HooksSubDir, constants.ConfigFileName)
+	cfg, err := LoadLogConfig(configPath)
+	if err != nil || cfg == nil || strings.TrimSpace(cfg.ExtendsPath) == "" {
+		return nil, false, nil
+	}
+	ext := strings.TrimSpace(cfg.ExtendsPath)
+	if ext == ExtendsAuto {
+		return ancestorProjects(dir, home), false, nil
+	}
+
+	target := ext
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	target = filepath.Clean(target)
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, false, fmt.Errorf("extendsPath '%s' in %s: %w", ext, configPath, err)
+	}
+	if !info.IsDir() {
+		return []parentSource{{File: target}}, true, nil
+	}
+	// Accept the project root, its .claude directory or .claude/hooks
+	if filepath.Base(target) == constants.HooksSubDir && filepath.Base(filepath.Dir(target)) == constants.ClaudeDir {
+		target = filepath.Dir(target)
+	}
+	if filepath.Base(target) == constants.ClaudeDir {
+		target = filepath.Dir(target)
+	}
+	return []parentSource{{Dir: target}}, true, nil
+}
+
+// ancestorProjects lists ancestors of dir that have a .claude directory,
+// nearest first, stopping at the git root. Outside a git repository there is
+// no boundary, so nothing is inherited. The home directory is skipped because
+// its .claude is already the global scope.
+func ancestorProjects(dir, home string) []parentSource {
+	root := gitRoot(dir)
+	if root == "" || root == filepath.Clean(dir) {
+		return nil
+	}
+	var parents []parentSource
+	for cur := filepath.Dir(filepath.Clean(dir)); ; cur = filepath.Dir(cur) {
+		if cur != filepath.Clean(home) {
+			if info, err := os.Stat(filepath.Join(cur, constants.ClaudeDir)); err == nil && info.IsDir() {
+				parents = append(parents, parentSource{Dir: cur})
+			}
+		}
+		if cur == root || cur == filepath.Dir(cur) {
+			return parents
+		}
+	}
+}
+
+// gitRoot returns the nearest directory at or above dir containing .git
+func gitRoot(dir string) string {
+	for cur := filepath.Clean(dir); ; cur = filepath.Dir(cur) {
+		if _, err := os.Stat(filepath.Join(cur, ".git")); err == nil {
+			return cur
+		}
+		if cur == filepath.Dir(cur) {
+			return ""
+		}
+	}
+}
+
+// MergeHooksConfigLayers merges layers given highest precedence first
+func MergeHooksConfigLayers(layers []HooksConfigLayer) CustomHooksConfig {
+	eff := CustomHooksConfig{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		eff = mergeHooksConfigs(eff, layers[i].Config)
+	}
+	return eff
+}
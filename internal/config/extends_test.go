@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeHooksFile writes a hooks.yml under dir/.claude/hooks
+func writeHooksFile(t *testing.T, dir, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", "hooks", "hooks.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setExtendsPath(t *testing.T, dir, ext string) {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json")
+	if err := SaveLogConfig(path, &LogConfig{LogRotation: DefaultLogRotationConfig(), ExtendsPath: ext}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const rootHooks = `
+go:
+  PostToolUse:
+    jobs:
+      - name: fmt
+        run: gofmt -w "$TOOL_FILE"
+      - name: vet
+        run: go vet ./...
+`
+
+func TestLoadHooksConfig_ExtendsPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	service := filepath.Join(root, "services", "api")
+	writeHooksFile(t, root, rootHooks)
+	writeHooksFile(t, service, `
+go:
+  PostToolUse:
+    jobs:
+      - name: vet
+        run: go vet ./services/api/...
+      - name: test
+        run: go test ./services/api/...
+`)
+	setExtendsPath(t, service, "../..")
+	t.Chdir(service)
+
+	cfg, err := LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := (*cfg)["go"].Events["PostToolUse"].Jobs
+	got := map[string]string{}
+	for _, j := range jobs {
+		got[j.Name] = j.Run
+	}
+	if len(jobs) != 3 || got["fmt"] == "" || got["vet"] != "go vet ./services/api/..." || got["test"] == "" {
+		t.Fatalf("expected root jobs with local override and addition, got %+v", jobs)
+	}
+
+	layers, err := LoadHooksConfigLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 2 || layers[0].Scope != LayerScopeProject || layers[1].Scope != LayerScopeParent {
+		t.Fatalf("unexpected layers: %+v", layers)
+	}
+}
+
+func TestLoadHooksConfig_ExtendsChainAndFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	team := filepath.Join(root, "team")
+	service := filepath.Join(team, "svc")
+	writeHooksFile(t, root, rootHooks)
+	writeHooksFile(t, team, "lint:\n  PreToolUse:\n    jobs:\n      - name: check\n        run: 'true'\n")
+	writeHooksFile(t, service, "")
+	setExtendsPath(t, service, "../.claude")
+	setExtendsPath(t, team, filepath.Join(root, ".claude", "hooks", "hooks.yml"))
+	// A cycle back to the service is ignored
+	setExtendsPath(t, root, service)
+	t.Chdir(service)
+
+	cfg, err := LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*cfg)["lint"] == nil || (*cfg)["go"] == nil {
+		t.Fatalf("expected groups from team and root, got %+v", *cfg)
+	}
+}
+
+func TestLoadHooksConfig_ExtendsAuto(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	outer := t.TempDir()
+	root := filepath.Join(outer, "repo")
+	service := filepath.Join(root, "services", "api")
+	// Config above the git root must not be inherited
+	writeHooksFile(t, outer, "outside:\n  Stop:\n    jobs:\n      - name: x\n        run: 'true'\n")
+	writeHooksFile(t, root, rootHooks)
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	writeHooksFile(t, service, "")
+	setExtendsPath(t, service, ExtendsAuto)
+	t.Chdir(service)
+
+	cfg, err := LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*cfg)["go"] == nil || (*cfg)["outside"] != nil {
+		t.Fatalf("expected only the repo root config inherited, got %+v", *cfg)
+	}
+}
+
+func TestLoadHooksConfig_ExtendsPathMissing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	service := t.TempDir()
+	setExtendsPath(t, service, "../does-not-exist")
+	t.Chdir(service)
+
+	if _, err := LoadHooksConfig(); err == nil {
+		t.Fatal("expected an error for a missing extendsPath target")
+	}
+}
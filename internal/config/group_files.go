@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+// StaleGroupFile is a per-group hooks file (.claude/hooks/<name>.yml) whose
+// groups are all either superseded by the main config or no longer installed
+type StaleGroupFile struct {
+	Path   string
+	Groups []string
+	// Reasons explains, per group, why the file is not needed
+	Reasons []string
+}
+
+// GroupHooksDir returns the .claude/hooks directory for the given scope
+func GroupHooksDir(global bool) (string, error) {
+	dir, err := ClaudeDirFor(global)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, constants.HooksSubDir), nil
+}
+
+// PerGroupFiles lists the per-group YAML and TOML files in hooksDir,
+// excluding the canonical hooks.yml/hooks.yaml/hooks.toml and the main config file
+func PerGroupFiles(hooksDir string) []string {
+	return collectPerGroupFiles(hooksDir)
+}
+
+// FindStaleGroupFiles reports files in which every group is either defined in
+// mainGroups (the customHooks embedded in the main config, which takes
+// precedence over per-group files) or absent from installed (groups referenced
+// by settings.json). A group that an installed or main-config group extends,
+// directly or through other groups, counts as installed. Files that fail to
+// parse are returned as errors and never reported as stale.
+func FindStaleGroupFiles(files []string, mainGroups CustomHooksConfig, installed map[string]bool) ([]StaleGroupFile, []error) {
+	var errs []error
+	parsed := make(map[string]CustomHooksConfig, len(files))
+	for _, path := range files {
+		cfg, err := parseHooksConfigFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		parsed[path] = cfg
+	}
+	needed := extendedGroups(parsed, mainGroups, installed)
+
+	var stale []StaleGroupFile
+	for _, path := range files {
+		cfg, ok := parsed[path]
+		if !ok {
+			continue
+		}
+		groups := make([]string, 0, len(cfg))
+		for name := range cfg {
+			groups = append(groups, name)
+		}
+		sort.Strings(groups)
+
+		entry := StaleGroupFile{Path: path, Groups: groups}
+		if len(groups) == 0 {
+			entry.Reasons = []string{"defines no groups"}
+			stale = append(stale, entry)
+			continue
+		}
+
+		live := false
+		for _, g := range groups {
+			switch {
+			case mainGroups[g] != nil:
+				entry.Reasons = append(entry.Reasons, fmt.Sprintf("%s: duplicated by the main config", g))
+			case !installed[g] && !needed[g]:
+				entry.Reasons = append(entry.Reasons, fmt.Sprintf("%s: not installed in any settings.json", g))
+			default:
+				live = true
+			}
+		}
+		if !live {
+			stale = append(stale, entry)
+		}
+	}
+	return stale, errs
+}
+
+// extendedGroups returns the groups that installed groups and the main
+// config's groups extend, directly or transitively. Layers merge a group's
+// definitions, so every extends any of them declares is followed.
+func extendedGroups(parsed map[string]CustomHooksConfig, mainGroups CustomHooksConfig, installed map[string]bool) map[string]bool {
+	extends := map[string][]string{}
+	addExtends := func(cfg CustomHooksConfig) {
+		for name, g := range cfg {
+			if g != nil && g.Extends != "" {
+				extends[name] = append(extends[name], g.Extends)
+			}
+		}
+	}
+	for _, cfg := range parsed {
+		addExtends(cfg)
+	}
+	addExtends(mainGroups)
+
+	needed := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		for _, base := range extends[name] {
+			// needed doubles as the visited set, so an extends cycle ends
+			if !needed[base] {
+				needed[base] = true
+				visit(base)
+			}
+		}
+	}
+	for name := range installed {
+		visit(name)
+	}
+	for name := range mainGroups {
+		visit(name)
+	}
+	return needed
+}
+
+// InstalledConfigGroups returns the custom hook groups referenced by the
+// project and global settings files
+func InstalledConfigGroups() map[string]bool {
+	groups := map[string]bool{}
+	for _, global := range []bool{false, true} {
+		path, err := GetSettingsPath(global)
+		if err != nil {
+			continue
+		}
+		settings, err := LoadSettings(path)
+		if err != nil {
+			continue
+		}
+		for g := range GetConfigGroupsInSettings(settings) {
+			groups[g] = true
+		}
+	}
+	return groups
+}
+
+// ArchiveGroupFiles moves files into archiveDir, keeping their base names
+func ArchiveGroupFiles(files []string, archiveDir string) error {
+	if err := os.MkdirAll(archiveDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	var failed []string
+	for _, f := range files {
+		if err := os.Rename(f, filepath.Join(archiveDir, filepath.Base(f))); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", f, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to archive: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindStaleGroupFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	job := ":\n  PostToolUse:\n    jobs:\n      - name: j\n        run: true\n"
+	live := write("live.yml", "web"+job)
+	dup := write("dup.yml", "ci"+job)
+	unused := write("unused.yml", "old"+job)
+	mixed := write("mixed.yml", "old"+job+"web2"+job)
+	empty := write("empty.yml", "")
+	broken := write("broken.yml", "web: [")
+
+	main := CustomHooksConfig{"ci": &HookGroup{}}
+	installed := map[string]bool{"web": true, "ci": true, "web2": true}
+
+	stale, errs := FindStaleGroupFiles([]string{live, dup, unused, mixed, empty, broken}, main, installed)
+	if len(errs) != 1 {
+		t.Errorf("expected one parse error for broken.yml, got %v", errs)
+	}
+
+	got := map[string][]string{}
+	for _, s := range stale {
+		got[filepath.Base(s.Path)] = s.Reasons
+	}
+	want := map[string][]string{
+		"dup.yml":    {"ci: duplicated by the main config"},
+		"unused.yml": {"old: not installed in any settings.json"},
+		"empty.yml":  {"defines no groups"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stale = %v, want %v", got, want)
+	}
+}
+
+func TestFindStaleGroupFiles_KeepsExtendedBases(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	job := ":\n  PostToolUse:\n    jobs:\n      - name: j\n        run: true\n"
+	base := write("base.yml", "base"+job)
+	core := write("core.yml", "core:\n  extends: base\n"+"  Stop:\n    jobs:\n      - name: k\n        run: true\n")
+	web := write("web.yml", "web:\n  extends: core\n")
+	mainBase := write("main-base.yml", "lint"+job)
+	orphan := write("orphan.yml", "orphan"+job)
+	unusedChild := write("unused-child.yml", "child:\n  extends: orphan\n")
+
+	main := CustomHooksConfig{"ci": &HookGroup{Extends: "lint"}}
+	installed := map[string]bool{"web": true}
+
+	stale, errs := FindStaleGroupFiles([]string{base, core, web, mainBase, orphan, unusedChild}, main, installed)
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	var got []string
+	for _, s := range stale {
+		got = append(got, filepath.Base(s.Path))
+	}
+	// base is kept through web -> core -> base, lint through the main config;
+	// orphan is only extended by a group that is itself unused
+	if want := []string{"orphan.yml", "unused-child.yml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stale = %v, want %v", got, want)
+	}
+}
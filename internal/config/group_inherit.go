@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolveGroupExtends returns cfg with every group's extends flattened: a
+// group starts from the resolved events and jobs of the group it extends,
+// then its own are merged on top the way a project layer merges over the
+// global one, so a job of the same name replaces the inherited job. Bases
+// may extend further groups; an unknown base or a cycle is an error.
+func ResolveGroupExtends(cfg CustomHooksConfig) (CustomHooksConfig, error) {
+	out := make(CustomHooksConfig, len(cfg))
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var resolve func(name string, chain []string) (*HookGroup, error)
+	resolve = func(name string, chain []string) (*HookGroup, error) {
+		if g, ok := out[name]; ok {
+			return g, nil
+		}
+		for i, seen := range chain {
+			if seen == name {
+				cycle := append(append([]string{}, chain[i:]...), name)
+				return nil, fmt.Errorf("group '%s' extends itself through %s", name, strings.Join(cycle, " -> "))
+			}
+		}
+		grp := cfg[name]
+		if grp == nil || grp.Extends == "" {
+			out[name] = cloneHookGroup(grp)
+			return out[name], nil
+		}
+		if _, ok := cfg[grp.Extends]; !ok {
+			return nil, fmt.Errorf("group '%s' extends unknown group '%s'", name, grp.Extends)
+		}
+		base, err := resolve(grp.Extends, append(chain, name))
+		if err != nil {
+			return nil, err
+		}
+		own := cloneHookGroup(grp)
+		own.Extends = ""
+		inherited := cloneHookGroup(base)
+		if inherited == nil {
+			inherited = NewHookGroup(nil)
+		}
+		inherited.Description = ""
+		out[name] = mergeHooksConfigs(CustomHooksConfig{name: inherited}, CustomHooksConfig{name: own})[name]
+		return out[name], nil
+	}
+
+	for _, name := range names {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
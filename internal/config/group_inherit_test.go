@@ -0,0 +1,93 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveGroupExtends(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			base := CustomHooksConfig{
+				"security": NewHookGroup(map[string]*EventConfig{
+					"PreToolUse":  {Jobs: []HookJob{{Name: "secrets", Run: "scan"}, {Name: "licenses", Run: "check-licenses"}}},
+					"PostToolUse": {Jobs: []HookJob{{Name: "audit", Run: "audit"}}},
+				}),
+				"api": {Extends: "security", Description: "API service", Events: map[string]*EventConfig{
+					"PreToolUse": {Jobs: []HookJob{{Name: "licenses", Run: "true"}, {Name: "lint", Run: "golangci-lint run"}}},
+				}},
+				"strict": {Extends: "api", Events: map[string]*EventConfig{}},
+			}
+			data, err := EncodeHooksConfig(base, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := ParseHooksConfig(data, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg["api"].Extends != "security" {
+				t.Fatalf("extends did not round-trip through %s:\n%s", format, data)
+			}
+
+			resolved, err := ResolveGroupExtends(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{"api", "strict"} {
+				g := resolved[name]
+				if g.Extends != "" {
+					t.Errorf("%s: resolved group should not keep extends", name)
+				}
+				var jobs []string
+				for _, j := range g.Events["PreToolUse"].Jobs {
+					jobs = append(jobs, j.Name+"="+j.Run)
+				}
+				if got := strings.Join(jobs, ","); got != "secrets=scan,licenses=true,lint=golangci-lint run" {
+					t.Errorf("%s PreToolUse jobs = %s", name, got)
+				}
+				if g.Events["PostToolUse"] == nil || len(g.Events["PostToolUse"].Jobs) != 1 {
+					t.Errorf("%s should inherit PostToolUse", name)
+				}
+			}
+			if resolved["api"].Description != "API service" || resolved["strict"].Description != "" {
+				t.Errorf("descriptions should not be inherited: %q, %q", resolved["api"].Description, resolved["strict"].Description)
+			}
+			if cfg["api"].Events["PostToolUse"] != nil {
+				t.Error("resolving should not modify the input config")
+			}
+		})
+	}
+}
+
+func TestResolveGroupExtends_Errors(t *testing.T) {
+	tests := map[string]struct {
+		cfg  CustomHooksConfig
+		want string
+	}{
+		"unknown base": {
+			cfg:  CustomHooksConfig{"api": {Extends: "missing"}},
+			want: "group 'api' extends unknown group 'missing'",
+		},
+		"cycle": {
+			cfg: CustomHooksConfig{
+				"a": {Extends: "b"},
+				"b": {Extends: "c"},
+				"c": {Extends: "a"},
+			},
+			want: "group 'a' extends itself through a -> b -> c -> a",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ResolveGroupExtends(tt.cfg)
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := ValidateHooksConfig(&CustomHooksConfig{"a": {Extends: "a"}}); err == nil || !strings.Contains(err.Error(), "extends itself") {
+		t.Errorf("expected a self-extends error, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Keys under a group holding the group's own settings rather than an event
+const (
+	groupDescriptionKey = "description"
+	groupExtendsKey     = "extends"
+	groupLockKey        = "lock"
+	groupLockTimeoutKey = "lock_timeout"
+)
+
+// NewHookGroup returns a group with the given events and no description
+func NewHookGroup(events map[string]*EventConfig) *HookGroup {
+	if events == nil {
+		events = map[string]*EventConfig{}
+	}
+	return &HookGroup{Events: events}
+}
+
+// flatten lays the group out as it appears in config files
+func (g HookGroup) flatten() map[string]interface{} {
+	out := make(map[string]interface{}, len(g.Events)+4)
+	for name, ev := range g.Events {
+		out[name] = ev
+	}
+	if g.Description != "" {
+		out[groupDescriptionKey] = g.Description
+	}
+	if g.Extends != "" {
+		out[groupExtendsKey] = g.Extends
+	}
+	if g.Lock != "" {
+		out[groupLockKey] = g.Lock
+	}
+	if g.LockTimeout > 0 {
+		out[groupLockTimeoutKey] = g.LockTimeout
+	}
+	return out
+}
+
+// MarshalJSON writes the events and the group's keys side by side
+func (g HookGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.flatten())
+}
+
+// UnmarshalJSON reads a group's keys and treats every other key as an event
+func (g *HookGroup) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	g.Events = make(map[string]*EventConfig, len(raw))
+	for key, value := range raw {
+		switch key {
+		case groupDescriptionKey:
+			if err := json.Unmarshal(value, &g.Description); err != nil {
+				return fmt.Errorf("group description must be a string: %w", err)
+			}
+			continue
+		case groupExtendsKey:
+			if err := json.Unmarshal(value, &g.Extends); err != nil {
+				return fmt.Errorf("group extends must be a group name: %w", err)
+			}
+			continue
+		case groupLockKey:
+			if err := json.Unmarshal(value, &g.Lock); err != nil {
+				return fmt.Errorf("group lock must be a lock name: %w", err)
+			}
+			continue
+		case groupLockTimeoutKey:
+			if err := json.Unmarshal(value, &g.LockTimeout); err != nil {
+				return fmt.Errorf("group lock_timeout must be a number of seconds: %w", err)
+			}
+			continue
+		}
+		var ev *EventConfig
+		if err := json.Unmarshal(value, &ev); err != nil {
+			return fmt.Errorf("event %s: %w", key, err)
+		}
+		g.Events[key] = ev
+	}
+	return nil
+}
+
+// UnmarshalTOML reads a group's keys and treats every other key as an event
+func (g *HookGroup) UnmarshalTOML(v interface{}) error {
+	raw, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("a group must be a table of events, got %T", v)
+	}
+	g.Events = make(map[string]*EventConfig, len(raw))
+	for key, value := range raw {
+		switch key {
+		case groupDescriptionKey:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("group description must be a string, got %T", value)
+			}
+			g.Description = s
+			continue
+		case groupExtendsKey:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("group extends must be a group name, got %T", value)
+			}
+			g.Extends = s
+			continue
+		case groupLockKey:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("group lock must be a lock name, got %T", value)
+			}
+			g.Lock = s
+			continue
+		case groupLockTimeoutKey:
+			n, ok := value.(int64)
+			if !ok {
+				return fmt.Errorf("group lock_timeout must be a number of seconds, got %T", value)
+			}
+			g.LockTimeout = int(n)
+			continue
+		}
+		// Re-encode the event's table so its fields decode with their toml tags
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(value); err != nil {
+			return fmt.Errorf("event %s: %w", key, err)
+		}
+		ev := &EventConfig{}
+		if _, err := toml.Decode(buf.String(), ev); err != nil {
+			return fmt.Errorf("event %s: %w", key, err)
+		}
+		g.Events[key] = ev
+	}
+	return nil
+}
+
+// flattenHooksConfig lays cfg out as it appears in config files, for
+// encoders that cannot be taught the group layout
+func flattenHooksConfig(cfg CustomHooksConfig) map[string]interface{} {
+	out := make(map[string]interface{}, len(cfg))
+	for name, g := range cfg {
+		if g == nil {
+			out[name] = map[string]interface{}{}
+			continue
+		}
+		out[name] = g.flatten()
+	}
+	return out
+}
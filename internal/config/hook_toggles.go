@@ -0,0 +1,121 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// EntryToggle switches off one installed entry of a plugin, named by the
+// event and matcher it was installed under, while it stays in settings
+type EntryToggle struct {
+	Event   string `json:"event"`
+	Matcher string `json:"matcher,omitempty"`
+}
+
+// SetEntryEnabled disables or re-enables the plugin's entry for event and
+// matcher, reporting whether anything changed
+func (s *Settings) SetEntryEnabled(key, event, matcher string, enabled bool) bool {
+	if s.Plugins == nil {
+		s.Plugins = map[string]PluginConfig{}
+	}
+	cfg := s.Plugins[key]
+	toggle := EntryToggle{Event: event, Matcher: matcher}
+	for i, t := range cfg.DisabledEntries {
+		if t != toggle {
+			continue
+		}
+		if !enabled {
+			return false
+		}
+		cfg.DisabledEntries = append(cfg.DisabledEntries[:i:i], cfg.DisabledEntries[i+1:]...)
+		s.setOrDropPlugin(key, cfg)
+		return true
+	}
+	if enabled {
+		return false
+	}
+	cfg.DisabledEntries = append(cfg.DisabledEntries, toggle)
+	sort.Slice(cfg.DisabledEntries, func(i, j int) bool {
+		a, b := cfg.DisabledEntries[i], cfg.DisabledEntries[j]
+		if a.Event != b.Event {
+			return a.Event < b.Event
+		}
+		return a.Matcher < b.Matcher
+	})
+	s.Plugins[key] = cfg
+	return true
+}
+
+// EntryDisabled reports whether the installed command under event and
+// matcher is switched off, for the whole hook or for this entry alone
+func (s *Settings) EntryDisabled(event, matcher, command string) bool {
+	key := extractHookType(command)
+	if key == "" {
+		return false
+	}
+	if !s.IsPluginEnabled(key) {
+		return true
+	}
+	return slices.Contains(s.Plugins[key].DisabledEntries, EntryToggle{Event: event, Matcher: matcher})
+}
+
+// InstalledMatchers lists the matchers key is installed under for event
+func (s *Settings) InstalledMatchers(key, event string) []string {
+	var matchers []string
+	seen := map[string]bool{}
+	for _, e := range SettingsEntries(s.Hooks) {
+		if e.HookType == key && e.Event == event && !seen[e.Matcher] {
+			seen[e.Matcher] = true
+			matchers = append(matchers, e.Matcher)
+		}
+	}
+	return matchers
+}
+
+// EntryToggles returns the entries of key disabled in project or global
+// settings. The entry Claude Code ran is not known, so a toggle in either
+// scope applies.
+func EntryToggles(key string) []EntryToggle {
+	var toggles []EntryToggle
+	for _, global := range []bool{false, true} {
+		path, err := GetSettingsPath(global)
+		if err != nil {
+			continue
+		}
+		s, err := LoadSettings(path)
+		if err != nil {
+			continue
+		}
+		toggles = append(toggles, s.Plugins[key].DisabledEntries...)
+	}
+	return toggles
+}
+
+// DisabledEntryFor returns the toggle that switches off a run for event
+// and tool. Events without a tool are matched on the event alone.
+func DisabledEntryFor(toggles []EntryToggle, event, tool string) (EntryToggle, bool) {
+	for _, t := range toggles {
+		if t.Event != event {
+			continue
+		}
+		if tool == "" || MatcherMatchesTool(t.Matcher, tool) {
+			return t, true
+		}
+	}
+	return EntryToggle{}, false
+}
+
+// MatcherMatchesTool reports whether a settings matcher selects tool, as
+// Claude Code matches it: empty and "*" match everything, anything else is
+// a regular expression over the whole tool name
+func MatcherMatchesTool(matcher, tool string) bool {
+	if matcher == "" || matcher == "*" {
+		return true
+	}
+	re, err := regexp.Compile("^(?:" + matcher + ")$")
+	if err != nil {
+		return matcher == tool
+	}
+	return re.MatchString(tool)
+}
@@ -0,0 +1,77 @@
+package config
+
+import "testing"
+
+func TestSetEntryEnabled(t *testing.T) {
+	s := &Settings{}
+	if !s.SetEntryEnabled("security", "PreToolUse", "Bash", false) {
+		t.Fatal("expected disabling to change settings")
+	}
+	if s.SetEntryEnabled("security", "PreToolUse", "Bash", false) {
+		t.Error("disabling twice must not add a second toggle")
+	}
+	s.SetEntryEnabled("security", "PostToolUse", "", false)
+	if got := s.Plugins["security"].DisabledEntries; len(got) != 2 || got[0].Event != "PostToolUse" {
+		t.Errorf("toggles = %+v, want two sorted by event", got)
+	}
+
+	s.SetEntryEnabled("security", "PreToolUse", "Bash", true)
+	s.SetEntryEnabled("security", "PostToolUse", "", true)
+	if _, ok := s.Plugins["security"]; ok {
+		t.Error("expected the plugin entry to be dropped once no toggles remain")
+	}
+}
+
+func TestDisabledEntryFor(t *testing.T) {
+	toggles := []EntryToggle{{Event: "PreToolUse", Matcher: "Edit|Write"}, {Event: "Stop"}}
+	cases := []struct {
+		event, tool string
+		off         bool
+	}{
+		{"PreToolUse", "Write", true},
+		{"PreToolUse", "Bash", false},
+		{"PreToolUse", "MultiEdit", false},
+		{"PostToolUse", "Write", false},
+		{"Stop", "", true},
+	}
+	for _, c := range cases {
+		if _, off := DisabledEntryFor(toggles, c.event, c.tool); off != c.off {
+			t.Errorf("DisabledEntryFor(%s, %s) = %v, want %v", c.event, c.tool, off, c.off)
+		}
+	}
+}
+
+func TestMatcherMatchesTool(t *testing.T) {
+	cases := []struct {
+		matcher, tool string
+		want          bool
+	}{
+		{"", "Bash", true},
+		{"*", "Bash", true},
+		{"Bash", "Bash", true},
+		{"Bash", "BashOutput", false},
+		{"mcp__.*", "mcp__github__create_issue", true},
+		{"(", "(", true},
+	}
+	for _, c := range cases {
+		if got := MatcherMatchesTool(c.matcher, c.tool); got != c.want {
+			t.Errorf("MatcherMatchesTool(%q, %q) = %v, want %v", c.matcher, c.tool, got, c.want)
+		}
+	}
+}
+
+func TestEntryDisabled(t *testing.T) {
+	s := &Settings{}
+	cmd := "/usr/local/bin/blues-traveler hooks run security"
+	s.SetEntryEnabled("security", "PreToolUse", "Bash", false)
+	if !s.EntryDisabled("PreToolUse", "Bash", cmd) || s.EntryDisabled("PreToolUse", "Write", cmd) {
+		t.Error("expected only the Bash entry to be disabled")
+	}
+	s.SetPluginEnabled("security", false)
+	if !s.EntryDisabled("PostToolUse", "", cmd) {
+		t.Error("expected every entry of a disabled plugin to be disabled")
+	}
+	if s.EntryDisabled("PreToolUse", "Bash", "echo other") {
+		t.Error("other commands are never disabled")
+	}
+}
@@ -1,54 +1,239 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/klauern/blues-traveler/internal/constants"
 	yaml "gopkg.in/yaml.v3"
 )
 
 // HookJob represents a single job within an event in a named group
 type HookJob struct {
-	Name    string            `yaml:"name" json:"name"`
-	Run     string            `yaml:"run" json:"run"`
-	Glob    []string          `yaml:"glob,omitempty" json:"glob,omitempty"`
-	Skip    string            `yaml:"skip,omitempty" json:"skip,omitempty"`
-	Only    string            `yaml:"only,omitempty" json:"only,omitempty"`
-	Timeout int               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
-	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
-	WorkDir string            `yaml:"workdir,omitempty" json:"workdir,omitempty"`
+	Name    string            `yaml:"name" json:"name" toml:"name"`
+	Run     string            `yaml:"run" json:"run" toml:"run"`
+	Glob    []string          `yaml:"glob,omitempty" json:"glob,omitempty" toml:"glob,omitempty"`
+	Skip    string            `yaml:"skip,omitempty" json:"skip,omitempty" toml:"skip,omitempty"`
+	Only    string            `yaml:"only,omitempty" json:"only,omitempty" toml:"only,omitempty"`
+	Timeout int               `yaml:"timeout,omitempty" json:"timeout,omitempty" toml:"timeout,omitempty,omitzero"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty" toml:"env,omitempty"`
+	WorkDir string            `yaml:"workdir,omitempty" json:"workdir,omitempty" toml:"workdir,omitempty"`
+	// Description says what the job does; listings show it in place of the
+	// generated one
+	Description string `yaml:"description,omitempty" json:"description,omitempty" toml:"description,omitempty"`
+	// EnvFile names .env-style files loaded when the job runs, relative to
+	// its workdir. Later files override earlier ones, and env overrides them all.
+	EnvFile EnvFiles `yaml:"env_file,omitempty" json:"env_file,omitempty" toml:"env_file,omitempty"`
+	// OnlyNewFindings reports only output lines that were not present the
+	// last time this job failed for the same file
+	OnlyNewFindings bool `yaml:"only_new_findings,omitempty" json:"only_new_findings,omitempty" toml:"only_new_findings,omitempty"`
+	// LogLevel limits this job's log entries to error, warn, info or debug
+	LogLevel string `yaml:"log_level,omitempty" json:"log_level,omitempty" toml:"log_level,omitempty"`
+	// QuietSuccess suppresses all log lines for successful runs; nil inherits
+	// the logging.quietSuccess default
+	QuietSuccess *bool `yaml:"quiet_success,omitempty" json:"quiet_success,omitempty" toml:"quiet_success,omitempty"`
+	// Scope "git" runs the job once over every file changed in git since the
+	// session started (GIT_CHANGED_FILES, filtered by glob) instead of the
+	// event's files
+	Scope string `yaml:"scope,omitempty" json:"scope,omitempty" toml:"scope,omitempty"`
+	// MaxInputBytes caps the event payload on stdin and each environment
+	// value; overrides the event's max_input_bytes
+	MaxInputBytes int64 `yaml:"max_input_bytes,omitempty" json:"max_input_bytes,omitempty" toml:"max_input_bytes,omitempty,omitzero"`
+	// Shell runs Run with bash, sh, pwsh or cmd. Empty uses bash, or on
+	// Windows without bash on PATH, pwsh.
+	Shell string `yaml:"shell,omitempty" json:"shell,omitempty" toml:"shell,omitempty"`
+	// Serialize runs one instance of the job at a time in the project,
+	// holding a lock under .claude/hooks/locks while it runs
+	Serialize bool `yaml:"serialize,omitempty" json:"serialize,omitempty" toml:"serialize,omitempty"`
+	// SerializeTimeout is how long (seconds) to wait for the serialize lock
+	// before failing; 0 waits DefaultLockWait
+	SerializeTimeout int `yaml:"serialize_timeout,omitempty" json:"serialize_timeout,omitempty" toml:"serialize_timeout,omitempty,omitzero"`
+	// Cache skips the job while its inputs are unchanged, replaying the
+	// outcome of the run that last saw them
+	Cache *JobCache `yaml:"cache,omitempty" json:"cache,omitempty" toml:"cache,omitempty"`
+	// CaptureOutput keeps the stdout and stderr of every run under
+	// .claude/hooks/artifacts/<group>/<job>/<timestamp>/, pruned by the log
+	// rotation policy
+	CaptureOutput bool `yaml:"capture_output,omitempty" json:"capture_output,omitempty" toml:"capture_output,omitempty"`
+	// Sandbox restricts the environment, resources, network and workdir
+	// the job's command gets
+	Sandbox *JobSandbox `yaml:"sandbox,omitempty" json:"sandbox,omitempty" toml:"sandbox,omitempty"`
 }
 
+// JobSandbox restricts what a job's command can reach. Resource limits need
+// a Unix system; no_network and read_only_workdir run the job in Linux
+// namespaces through unshare(1), so they need Linux with unprivileged user
+// namespaces. A job whose sandbox can't be set up fails instead of running
+// unrestricted.
+type JobSandbox struct {
+	// ClearEnv starts the job without the environment blues-traveler runs
+	// in, keeping only the variables EnvAllow names. The event's variables,
+	// env_file and env are still set.
+	ClearEnv bool `yaml:"clear_env,omitempty" json:"clear_env,omitempty" toml:"clear_env,omitempty"`
+	// EnvAllow names inherited variables kept by clear_env, e.g. PATH; a
+	// trailing * matches a prefix, as in LC_*
+	EnvAllow []string `yaml:"env_allow,omitempty" json:"env_allow,omitempty" toml:"env_allow,omitempty"`
+	// MaxMemoryMB caps the address space of the job and each process it
+	// starts (ulimit -v)
+	MaxMemoryMB int `yaml:"max_memory_mb,omitempty" json:"max_memory_mb,omitempty" toml:"max_memory_mb,omitempty,omitzero"`
+	// MaxCPUSeconds caps the CPU time of the job and each process it starts
+	// (ulimit -t)
+	MaxCPUSeconds int `yaml:"max_cpu_seconds,omitempty" json:"max_cpu_seconds,omitempty" toml:"max_cpu_seconds,omitempty,omitzero"`
+	// NoNetwork runs the job in a network namespace with only loopback
+	NoNetwork bool `yaml:"no_network,omitempty" json:"no_network,omitempty" toml:"no_network,omitempty"`
+	// ReadOnlyWorkdir mounts the job's workdir, or the project when it has
+	// none, read-only for the job
+	ReadOnlyWorkdir bool `yaml:"read_only_workdir,omitempty" json:"read_only_workdir,omitempty" toml:"read_only_workdir,omitempty"`
+}
+
+// Namespaced reports whether the sandbox runs the job in Linux namespaces
+func (s *JobSandbox) Namespaced() bool {
+	return s != nil && (s.NoNetwork || s.ReadOnlyWorkdir)
+}
+
+// Limited reports whether the sandbox sets resource limits
+func (s *JobSandbox) Limited() bool {
+	return s != nil && (s.MaxMemoryMB > 0 || s.MaxCPUSeconds > 0)
+}
+
+// DefaultJobCacheTTL is how long (seconds) a cached job outcome stays valid
+// when the job sets no ttl
+const DefaultJobCacheTTL = 600
+
+// JobCache keys a job's cached outcome on part of its environment
+type JobCache struct {
+	// Key names the environment variables the outcome depends on, e.g.
+	// FILES_CHANGED or TOOL_FILE. Words in their values that name files add
+	// the files' contents, so editing a file invalidates the entry.
+	Key []string `yaml:"key" json:"key" toml:"key"`
+	// TTL is how long (seconds) an outcome is reused; 0 uses DefaultJobCacheTTL
+	TTL int `yaml:"ttl,omitempty" json:"ttl,omitempty" toml:"ttl,omitempty,omitzero"`
+}
+
+// TTLDuration returns how long an outcome is reused
+func (c *JobCache) TTLDuration() time.Duration {
+	if c.TTL > 0 {
+		return time.Duration(c.TTL) * time.Second
+	}
+	return DefaultJobCacheTTL * time.Second
+}
+
+// Job shells
+const (
+	ShellBash = "bash"
+	ShellSh   = "sh"
+	ShellPwsh = "pwsh"
+	ShellCmd  = "cmd"
+)
+
+// ValidShells lists the values a job's shell may take
+var ValidShells = []string{ShellBash, ShellSh, ShellPwsh, ShellCmd}
+
+// Job scopes
+const (
+	// JobScopeEvent targets the files in the event payload (the default)
+	JobScopeEvent = "event"
+	// JobScopeGit targets files changed in git since the session started
+	JobScopeGit = "git"
+)
+
 // EventConfig contains jobs for a given Claude Code event, and execution hints
 type EventConfig struct {
-	Parallel bool      `yaml:"parallel,omitempty" json:"parallel,omitempty"`
-	Jobs     []HookJob `yaml:"jobs" json:"jobs"`
+	Parallel bool `yaml:"parallel,omitempty" json:"parallel,omitempty" toml:"parallel,omitempty"`
+	// Chain runs the event's jobs one after another in the order listed,
+	// each seeing the KEY=VALUE lines earlier jobs wrote to $BT_OUTPUT as
+	// environment variables
+	Chain bool `yaml:"chain,omitempty" json:"chain,omitempty" toml:"chain,omitempty"`
+	// MaxInputBytes opts the event's jobs into truncated input: payload
+	// strings and environment values beyond the limit are cut, and the full
+	// payload is saved to the file named by BT_PAYLOAD_FILE. 0 passes input
+	// through unchanged.
+	MaxInputBytes int64 `yaml:"max_input_bytes,omitempty" json:"max_input_bytes,omitempty" toml:"max_input_bytes,omitempty,omitzero"`
+	// BeforeAll runs once per event occurrence before any of the jobs, and
+	// AfterAll once after the last of them finishes
+	BeforeAll *LifecycleCommand `yaml:"before_all,omitempty" json:"before_all,omitempty" toml:"before_all,omitempty"`
+	AfterAll  *LifecycleCommand `yaml:"after_all,omitempty" json:"after_all,omitempty" toml:"after_all,omitempty"`
+	Jobs      []HookJob         `yaml:"jobs" json:"jobs" toml:"jobs"`
+}
+
+// LifecycleCommand is an event's before_all or after_all command, for setup
+// and teardown shared by its jobs
+type LifecycleCommand struct {
+	Run     string            `yaml:"run" json:"run" toml:"run"`
+	Timeout int               `yaml:"timeout,omitempty" json:"timeout,omitempty" toml:"timeout,omitempty,omitzero"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty" toml:"env,omitempty"`
+	WorkDir string            `yaml:"workdir,omitempty" json:"workdir,omitempty" toml:"workdir,omitempty"`
+	// OnFailure is LifecycleFail (the default) or LifecycleContinue
+	OnFailure string `yaml:"on_failure,omitempty" json:"on_failure,omitempty" toml:"on_failure,omitempty"`
 }
 
-// HookGroup is a set of EventName -> EventConfig
-type HookGroup map[string]*EventConfig
+// Lifecycle failure modes
+const (
+	// LifecycleFail reports a failure like a failed job; a failed
+	// before_all also skips the event's jobs
+	LifecycleFail = "fail"
+	// LifecycleContinue only logs a failure and runs the jobs anyway
+	LifecycleContinue = "continue"
+)
+
+// Continues reports whether a failure of the command is only logged
+func (c *LifecycleCommand) Continues() bool {
+	return c.OnFailure == LifecycleContinue
+}
+
+// HookGroup is a set of EventName -> EventConfig plus settings for the group
+// as a whole. In config files the events sit directly under the group name,
+// next to the group's own keys.
+type HookGroup struct {
+	// Description says what the group is for; listings show it
+	Description string `yaml:"description,omitempty"`
+	// Extends names a group whose events and jobs this group inherits; its
+	// own jobs replace inherited ones of the same name (see ResolveGroupExtends)
+	Extends string `yaml:"extends,omitempty"`
+	// Lock names a machine-wide mutex held while each of the group's jobs
+	// runs, so jobs of groups sharing the name in other terminals, worktrees,
+	// or projects run one at a time
+	Lock string `yaml:"lock,omitempty"`
+	// LockTimeout is how long (seconds) to wait for Lock before failing
+	LockTimeout int                     `yaml:"lock_timeout,omitempty"`
+	Events      map[string]*EventConfig `yaml:",inline"`
+}
 
 // CustomHooksConfig is the root structure mapping group names to hook groups
-type CustomHooksConfig map[string]HookGroup
+type CustomHooksConfig map[string]*HookGroup
 
 // isValidHookConfigFile checks if a file should be included as a hook config
 func isValidHookConfigFile(name string) bool {
 	if name == constants.ConfigFileName {
 		return false // skip app config files
 	}
-	low := strings.ToLower(name)
-	return strings.HasSuffix(low, ".yml") || strings.HasSuffix(low, ".yaml")
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yml", ".yaml", ".toml":
+		return true
+	}
+	return false
+}
+
+// IsHooksConfigFileName reports whether a file named name can hold custom
+// hooks: a hooks file, a per-group file, or the main config with embedded
+// customHooks
+func IsHooksConfigFileName(name string) bool {
+	return name == constants.ConfigFileName || name == "hooks.json" || isValidHookConfigFile(name)
 }
 
 // collectPerGroupFiles collects per-group config files from a directory
-// Skips canonical hooks.yml and hooks.yaml files to avoid duplicates
+// Skips canonical hooks.yml, hooks.yaml and hooks.toml files to avoid duplicates
 func collectPerGroupFiles(hooksDir string) []string {
 	var paths []string
 
@@ -62,9 +247,9 @@ func collectPerGroupFiles(hooksDir string) []string {
 		if e.IsDir() {
 			continue
 		}
-		// Skip canonical hooks.yml/hooks.yaml files as they're added explicitly
+		// Skip canonical hooks.yml/hooks.yaml/hooks.toml files as they're added explicitly
 		name := e.Name()
-		if name == "hooks.yml" || name == "hooks.yaml" {
+		if name == "hooks.yml" || name == "hooks.yaml" || name == "hooks.toml" {
 			continue
 		}
 		if isValidHookConfigFile(name) {
@@ -80,146 +265,72 @@ func collectPerGroupFiles(hooksDir string) []string {
 	return paths
 }
 
-// addProjectPaths adds project-scoped config paths
-func addProjectPaths(baseDir string) []string {
-	var paths []string
-
-	// Project local override (highest precedence)
-	paths = append(paths, filepath.Join(baseDir, "hooks-local.yml"))
-
-	// Prefer new canonical file under hooks/
-	paths = append(paths,
+// canonicalHooksPaths lists the hooks files of a .claude directory read
+// before any per-group file, highest precedence first
+func canonicalHooksPaths(baseDir string) []string {
+	return []string{
+		// Local override (highest precedence)
+		filepath.Join(baseDir, "hooks-local.yml"),
+		filepath.Join(baseDir, "hooks-local.toml"),
+		// Prefer new canonical file under hooks/
 		filepath.Join(baseDir, "hooks", "hooks.yml"),
 		filepath.Join(baseDir, "hooks", "hooks.yaml"),
-	)
-
-	// Legacy locations for backward compatibility
-	paths = append(paths,
+		filepath.Join(baseDir, "hooks", "hooks.toml"),
+		// Legacy locations for backward compatibility
 		filepath.Join(baseDir, "hooks.yml"),
 		filepath.Join(baseDir, "hooks.yaml"),
 		filepath.Join(baseDir, "hooks.json"),
-	)
+		filepath.Join(baseDir, "hooks.toml"),
+	}
+}
 
+// addProjectPaths adds project-scoped config paths
+func addProjectPaths(baseDir string) []string {
 	// Per-group files in .claude/hooks/
-	paths = append(paths, collectPerGroupFiles(filepath.Join(baseDir, "hooks"))...)
-
-	return paths
+	return append(canonicalHooksPaths(baseDir), collectPerGroupFiles(filepath.Join(baseDir, "hooks"))...)
 }
 
 // addGlobalPaths adds global-scoped config paths
 func addGlobalPaths(baseDir string) []string {
-	var paths []string
-
-	// Global local override (highest precedence)
-	paths = append(paths, filepath.Join(baseDir, "hooks-local.yml"))
-
-	paths = append(paths,
-		filepath.Join(baseDir, "hooks", "hooks.yml"),
-		filepath.Join(baseDir, "hooks", "hooks.yaml"),
-	)
-
-	// Legacy top-level
-	paths = append(paths,
-		filepath.Join(baseDir, "hooks.yml"),
-		filepath.Join(baseDir, "hooks.yaml"),
-		filepath.Join(baseDir, "hooks.json"),
-	)
-
 	// Per-group files in ~/.claude/hooks/
-	paths = append(paths, collectPerGroupFiles(filepath.Join(baseDir, "hooks"))...)
-
-	return paths
+	return append(canonicalHooksPaths(baseDir), collectPerGroupFiles(filepath.Join(baseDir, "hooks"))...)
 }
 
-// candidateConfigPaths returns the list of possible config file locations in
-// priority order (earlier paths have higher precedence).
-// The loader will merge from lowest to highest priority so earlier entries win.
-func candidateConfigPaths() ([]string, error) {
-	cwd, err := os.Getwd()
+// HooksConfigFiles lists the existing custom hooks files in the project's
+// (or with global, the home directory's) .claude directory, highest
+// precedence first
+func HooksConfigFiles(global bool) ([]string, error) {
+	dir, err := ClaudeDirFor(global)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %v", err)
+		return nil, err
 	}
-
-	home, err := os.UserHomeDir()
+	candidates, err := hooksFileCandidates(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %v", err)
+		return nil, err
 	}
-
-	var paths []string
-
-	// Project scope
-	paths = append(paths, addProjectPaths(filepath.Join(cwd, ".claude"))...)
-
-	// Global scope
-	paths = append(paths, addGlobalPaths(filepath.Join(home, ".claude"))...)
-
-	return paths, nil
+	var files []string
+	for _, p := range candidates {
+		if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+	return files, nil
 }
 
 // LoadHooksConfig discovers, parses, and merges all available config files.
-// Higher-priority files (earlier in candidate list) override lower-priority ones.
+// Higher-priority layers (see LoadHooksConfigLayers) override lower-priority ones.
 func LoadHooksConfig() (*CustomHooksConfig, error) {
-	// 1) Try embedded in main config first
-	if embedded := loadEmbeddedHooksConfig(); embedded != nil {
-		return embedded, nil
-	}
-	// 2) Fallback to file discovery (legacy)
-	candidates, err := candidateConfigPaths()
+	layers, err := LoadHooksConfigLayers()
 	if err != nil {
 		return nil, err
 	}
-
-	type parsed struct {
-		path string
-		cfg  CustomHooksConfig
-	}
-	var found []parsed
-	for _, p := range candidates {
-		if _, err := os.Stat(p); err == nil {
-			cfg, err := parseHooksConfigFile(p)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse %s: %w", p, err)
-			}
-			found = append(found, parsed{path: p, cfg: cfg})
-		}
-	}
-
-	if len(found) == 0 {
-		empty := CustomHooksConfig{}
-		return &empty, nil
-	}
-
-	// Merge from lowest to highest priority so first candidate wins on conflicts
-	// i.e., apply in reverse order, then overlay forward.
-	eff := CustomHooksConfig{}
-	for i := len(found) - 1; i >= 0; i-- {
-		eff = mergeHooksConfigs(eff, found[i].cfg)
+	eff, err := ResolveGroupExtends(MergeHooksConfigLayers(layers))
+	if err != nil {
+		return nil, err
 	}
 	return &eff, nil
 }
 
-// loadEmbeddedHooksConfig attempts to read custom hooks embedded in the
-// Blues Traveler main config file (blues-traveler-config.json) under
-// the key "customHooks". Project scope is checked before global.
-func loadEmbeddedHooksConfig() *CustomHooksConfig {
-	// project then global
-	for _, global := range []bool{false, true} {
-		path, err := GetLogConfigPath(global)
-		if err != nil {
-			continue
-		}
-		cfg, err := LoadLogConfig(path)
-		if err != nil || cfg == nil {
-			continue
-		}
-		if len(cfg.CustomHooks) > 0 {
-			cp := cloneHooksConfig(cfg.CustomHooks)
-			return &cp
-		}
-	}
-	return nil
-}
-
 // MergeHooksConfigs merges two HooksConfig structures.
 // base provides existing values; override entries replace or extend base.
 func MergeHooksConfigs(base, override *CustomHooksConfig) *CustomHooksConfig {
@@ -250,22 +361,50 @@ func mergeHooksConfigs(base CustomHooksConfig, override CustomHooksConfig) Custo
 			out[groupName] = cloneHookGroup(oGroup)
 			continue
 		}
+		if oGroup.Description != "" {
+			bGroup.Description = oGroup.Description
+		}
+		if oGroup.Extends != "" {
+			bGroup.Extends = oGroup.Extends
+		}
+		if oGroup.Lock != "" {
+			bGroup.Lock = oGroup.Lock
+		}
+		if oGroup.LockTimeout > 0 {
+			bGroup.LockTimeout = oGroup.LockTimeout
+		}
+		if bGroup.Events == nil {
+			bGroup.Events = map[string]*EventConfig{}
+		}
 		// Merge events under the group
-		for eventName, oEvent := range oGroup {
+		for eventName, oEvent := range oGroup.Events {
 			if oEvent == nil {
 				continue
 			}
-			bEvent, exists := bGroup[eventName]
+			bEvent, exists := bGroup.Events[eventName]
 			if !exists || bEvent == nil {
-				bGroup[eventName] = cloneEventConfig(oEvent)
+				bGroup.Events[eventName] = cloneEventConfig(oEvent)
 				continue
 			}
 			// Merge EventConfig: override Parallel flag, merge Jobs by name
 			merged := &EventConfig{
-				Parallel: oEvent.Parallel || bEvent.Parallel, // prefer true if any requests it
-				Jobs:     mergeJobsByName(bEvent.Jobs, oEvent.Jobs),
+				Parallel:      oEvent.Parallel || bEvent.Parallel, // prefer true if any requests it
+				Chain:         oEvent.Chain || bEvent.Chain,
+				MaxInputBytes: bEvent.MaxInputBytes,
+				BeforeAll:     cloneLifecycleCommand(bEvent.BeforeAll),
+				AfterAll:      cloneLifecycleCommand(bEvent.AfterAll),
+				Jobs:          mergeJobsByName(bEvent.Jobs, oEvent.Jobs),
+			}
+			if oEvent.MaxInputBytes > 0 {
+				merged.MaxInputBytes = oEvent.MaxInputBytes
+			}
+			if oEvent.BeforeAll != nil {
+				merged.BeforeAll = cloneLifecycleCommand(oEvent.BeforeAll)
 			}
-			bGroup[eventName] = merged
+			if oEvent.AfterAll != nil {
+				merged.AfterAll = cloneLifecycleCommand(oEvent.AfterAll)
+			}
+			bGroup.Events[eventName] = merged
 		}
 	}
 	return out
@@ -279,13 +418,13 @@ func cloneHooksConfig(in CustomHooksConfig) CustomHooksConfig {
 	return out
 }
 
-func cloneHookGroup(in HookGroup) HookGroup {
+func cloneHookGroup(in *HookGroup) *HookGroup {
 	if in == nil {
 		return nil
 	}
-	out := HookGroup{}
-	for e, ec := range in {
-		out[e] = cloneEventConfig(ec)
+	out := &HookGroup{Description: in.Description, Extends: in.Extends, Lock: in.Lock, LockTimeout: in.LockTimeout, Events: make(map[string]*EventConfig, len(in.Events))}
+	for e, ec := range in.Events {
+		out.Events[e] = cloneEventConfig(ec)
 	}
 	return out
 }
@@ -294,7 +433,9 @@ func cloneEventConfig(in *EventConfig) *EventConfig {
 	if in == nil {
 		return nil
 	}
-	out := &EventConfig{Parallel: in.Parallel}
+	out := &EventConfig{Parallel: in.Parallel, Chain: in.Chain, MaxInputBytes: in.MaxInputBytes}
+	out.BeforeAll = cloneLifecycleCommand(in.BeforeAll)
+	out.AfterAll = cloneLifecycleCommand(in.AfterAll)
 	if len(in.Jobs) > 0 {
 		out.Jobs = make([]HookJob, len(in.Jobs))
 		copy(out.Jobs, in.Jobs)
@@ -302,6 +443,20 @@ func cloneEventConfig(in *EventConfig) *EventConfig {
 	return out
 }
 
+func cloneLifecycleCommand(in *LifecycleCommand) *LifecycleCommand {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	if in.Env != nil {
+		out.Env = make(map[string]string, len(in.Env))
+		for k, v := range in.Env {
+			out.Env[k] = v
+		}
+	}
+	return &out
+}
+
 func mergeJobsByName(base, override []HookJob) []HookJob {
 	result := make([]HookJob, 0, len(base)+len(override))
 	index := map[string]int{}
@@ -327,25 +482,51 @@ func mergeJobsByName(base, override []HookJob) []HookJob {
 	return result
 }
 
-// parseHooksConfigFile decodes YAML or JSON based on extension
+// HooksConfigFormat returns the format of a hooks config file from its
+// extension: FormatYAML, FormatJSON, FormatTOML, or "" when unsupported
+func HooksConfigFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return ""
+}
+
+// parseHooksConfigFile decodes YAML, JSON or TOML based on extension
 func parseHooksConfigFile(path string) (CustomHooksConfig, error) {
+	format := HooksConfigFormat(path)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported config file extension: %s", path)
+	}
 	data, err := os.ReadFile(path) // #nosec G304 - paths are restricted to known .claude dirs
 	if err != nil {
 		return nil, err
 	}
+	return ParseHooksConfig(data, format)
+}
 
+// ParseHooksConfig decodes custom hook groups written in format
+func ParseHooksConfig(data []byte, format string) (CustomHooksConfig, error) {
 	var cfg CustomHooksConfig
-	switch strings.ToLower(filepath.Ext(path)) {
-	case ".yml", ".yaml":
+	switch format {
+	case FormatYAML:
 		if err := yaml.Unmarshal(data, &cfg); err != nil {
 			return nil, err
 		}
-	case ".json":
+	case FormatJSON:
 		if err := json.Unmarshal(data, &cfg); err != nil {
 			return nil, err
 		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
 	default:
-		return nil, fmt.Errorf("unsupported config file extension: %s", path)
+		return nil, fmt.Errorf("unsupported hooks config format: %q", format)
 	}
 	if cfg == nil {
 		cfg = CustomHooksConfig{}
@@ -353,19 +534,68 @@ func parseHooksConfigFile(path string) (CustomHooksConfig, error) {
 	return cfg, nil
 }
 
+// EncodeHooksConfig renders custom hook groups in format
+func EncodeHooksConfig(cfg CustomHooksConfig, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(cfg)
+	case FormatJSON:
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+		if err := encoder.Encode(flattenHooksConfig(cfg)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported hooks config format: %q", format)
+}
+
+// lockNamePattern restricts lock names to characters safe for lock file names
+var lockNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
 // ValidateHooksConfig performs basic checks for structure and required fields.
 func ValidateHooksConfig(cfg *CustomHooksConfig) error {
 	if cfg == nil {
 		return errors.New("nil config")
 	}
 	for groupName, grp := range *cfg {
+		if IsReservedGroupName(groupName) {
+			return fmt.Errorf("group name '%s' is reserved for built-in hook installs; rename the group", groupName)
+		}
 		if grp == nil {
 			continue
 		}
-		for eventName, ec := range grp {
+		if grp.Extends == groupName {
+			return fmt.Errorf("group '%s' extends itself", groupName)
+		}
+		if grp.Lock != "" && !lockNamePattern.MatchString(grp.Lock) {
+			return fmt.Errorf("group '%s' has invalid lock name '%s' (use letters, digits, '.', '_' or '-')", groupName, grp.Lock)
+		}
+		if grp.LockTimeout < 0 {
+			return fmt.Errorf("group '%s' has negative lock_timeout", groupName)
+		}
+		for eventName, ec := range grp.Events {
 			if ec == nil {
 				return fmt.Errorf("group '%s' event '%s' has nil config", groupName, eventName)
 			}
+			if ec.Chain && ec.Parallel {
+				return fmt.Errorf("group '%s' event '%s' sets both chain and parallel; chained jobs run one after another", groupName, eventName)
+			}
+			if ec.MaxInputBytes < 0 {
+				return fmt.Errorf("group '%s' event '%s' has negative max_input_bytes", groupName, eventName)
+			}
+			for key, cmd := range map[string]*LifecycleCommand{"before_all": ec.BeforeAll, "after_all": ec.AfterAll} {
+				if err := validateLifecycleCommand(cmd); err != nil {
+					return fmt.Errorf("group '%s' event '%s' %s: %w", groupName, eventName, key, err)
+				}
+			}
 			for i, j := range ec.Jobs {
 				if strings.TrimSpace(j.Name) == "" {
 					return fmt.Errorf("group '%s' event '%s' job[%d] missing name", groupName, eventName, i)
@@ -373,12 +603,130 @@ func ValidateHooksConfig(cfg *CustomHooksConfig) error {
 				if strings.TrimSpace(j.Run) == "" {
 					return fmt.Errorf("group '%s' event '%s' job '%s' missing run command", groupName, eventName, j.Name)
 				}
+				if _, err := ParseLogLevel(j.LogLevel); err != nil {
+					return fmt.Errorf("group '%s' event '%s' job '%s': %w", groupName, eventName, j.Name, err)
+				}
+				if j.Scope != "" && j.Scope != JobScopeEvent && j.Scope != JobScopeGit {
+					return fmt.Errorf("group '%s' event '%s' job '%s' has invalid scope '%s' (use %s or %s)", groupName, eventName, j.Name, j.Scope, JobScopeEvent, JobScopeGit)
+				}
+				if j.Shell != "" && !slices.Contains(ValidShells, j.Shell) {
+					return fmt.Errorf("group '%s' event '%s' job '%s' has invalid shell '%s' (use %s)", groupName, eventName, j.Name, j.Shell, strings.Join(ValidShells, ", "))
+				}
+				if j.MaxInputBytes < 0 {
+					return fmt.Errorf("group '%s' event '%s' job '%s' has negative max_input_bytes", groupName, eventName, j.Name)
+				}
+				if j.SerializeTimeout < 0 {
+					return fmt.Errorf("group '%s' event '%s' job '%s' has negative serialize_timeout", groupName, eventName, j.Name)
+				}
+				if err := validateJobCache(j.Cache); err != nil {
+					return fmt.Errorf("group '%s' event '%s' job '%s' cache: %w", groupName, eventName, j.Name, err)
+				}
+				if err := validateJobSandbox(j.Sandbox); err != nil {
+					return fmt.Errorf("group '%s' event '%s' job '%s' sandbox: %w", groupName, eventName, j.Name, err)
+				}
+				for _, f := range j.EnvFile {
+					if strings.TrimSpace(f) == "" {
+						return fmt.Errorf("group '%s' event '%s' job '%s' has an empty env_file entry", groupName, eventName, j.Name)
+					}
+				}
 			}
 		}
 	}
 	return nil
 }
 
+func validateJobCache(c *JobCache) error {
+	if c == nil {
+		return nil
+	}
+	if len(c.Key) == 0 {
+		return errors.New("key must name at least one environment variable, e.g. [FILES_CHANGED]")
+	}
+	for _, k := range c.Key {
+		if strings.TrimSpace(k) == "" {
+			return errors.New("key has an empty entry")
+		}
+	}
+	if c.TTL < 0 {
+		return errors.New("negative ttl")
+	}
+	return nil
+}
+
+func validateJobSandbox(s *JobSandbox) error {
+	if s == nil {
+		return nil
+	}
+	if len(s.EnvAllow) > 0 && !s.ClearEnv {
+		return errors.New("env_allow needs clear_env: true; without it the job inherits the whole environment")
+	}
+	for _, name := range s.EnvAllow {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("env_allow has an empty entry")
+		}
+	}
+	if s.MaxMemoryMB < 0 {
+		return errors.New("negative max_memory_mb")
+	}
+	if s.MaxCPUSeconds < 0 {
+		return errors.New("negative max_cpu_seconds")
+	}
+	return nil
+}
+
+func validateLifecycleCommand(cmd *LifecycleCommand) error {
+	if cmd == nil {
+		return nil
+	}
+	if strings.TrimSpace(cmd.Run) == "" {
+		return errors.New("missing run command")
+	}
+	if cmd.Timeout < 0 {
+		return errors.New("negative timeout")
+	}
+	switch cmd.OnFailure {
+	case "", LifecycleFail, LifecycleContinue:
+	default:
+		return fmt.Errorf("invalid on_failure '%s' (use %s or %s)", cmd.OnFailure, LifecycleFail, LifecycleContinue)
+	}
+	return nil
+}
+
+// CheckJobEnvFiles loads every job's env files, relative to the job's
+// workdir, and returns one error per job whose files are missing or malformed
+func CheckJobEnvFiles(cfg *CustomHooksConfig) []error {
+	if cfg == nil {
+		return nil
+	}
+	var errs []error
+	for _, groupName := range ListHookGroups(cfg) {
+		grp := (*cfg)[groupName]
+		if grp == nil {
+			continue
+		}
+		events := make([]string, 0, len(grp.Events))
+		for eventName := range grp.Events {
+			events = append(events, eventName)
+		}
+		sort.Strings(events)
+		for _, eventName := range events {
+			ec := grp.Events[eventName]
+			if ec == nil {
+				continue
+			}
+			for _, j := range ec.Jobs {
+				if len(j.EnvFile) == 0 {
+					continue
+				}
+				if _, err := LoadEnvFiles(j.EnvFile, j.WorkDir, os.LookupEnv); err != nil {
+					errs = append(errs, fmt.Errorf("group '%s' event '%s' job '%s': %w", groupName, eventName, j.Name, err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
 // ListHookGroups returns sorted group names from the config
 func ListHookGroups(cfg *CustomHooksConfig) []string {
 	if cfg == nil {
@@ -394,19 +742,9 @@ func ListHookGroups(cfg *CustomHooksConfig) []string {
 
 // EnsureClaudeDir ensures the .claude directory exists in the chosen scope
 func EnsureClaudeDir(global bool) (string, error) {
-	var dir string
-	if global {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", err
-		}
-		dir = filepath.Join(home, constants.ClaudeDir)
-	} else {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return "", err
-		}
-		dir = filepath.Join(cwd, constants.ClaudeDir)
+	dir, err := ClaudeDirFor(global)
+	if err != nil {
+		return "", err
 	}
 	if err := os.MkdirAll(filepath.Join(dir, constants.HooksSubDir), 0o750); err != nil {
 		return "", err
@@ -417,8 +755,8 @@ func EnsureClaudeDir(global bool) (string, error) {
 // WriteSampleHooksConfig writes a minimal sample hooks.yml to the chosen scope
 func WriteSampleHooksConfig(global bool, content string, overwrite bool) (string, error) {
 	// Parse YAML content into CustomHooksConfig
-	var fromYAML CustomHooksConfig
-	if err := yaml.Unmarshal([]byte(content), &fromYAML); err != nil {
+	fromYAML, err := ParseHooksConfig([]byte(content), FormatYAML)
+	if err != nil {
 		return "", fmt.Errorf("invalid sample yaml: %w", err)
 	}
 
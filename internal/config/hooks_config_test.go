@@ -3,23 +3,28 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestMergeHooksConfigs_GroupEventJobMerge(t *testing.T) {
 	base := CustomHooksConfig{
-		"ruby": HookGroup{
-			"PreToolUse": &EventConfig{Jobs: []HookJob{{Name: "rubocop", Run: "rubocop"}}},
-		},
+		"ruby": &HookGroup{Events: map[string]*EventConfig{
+			"PreToolUse": {Jobs: []HookJob{{Name: "rubocop", Run: "rubocop"}}},
+		}},
 	}
 	override := CustomHooksConfig{
-		"ruby": HookGroup{
-			"PreToolUse": &EventConfig{Jobs: []HookJob{{Name: "rubocop", Run: "bundle exec rubocop"}, {Name: "brakeman", Run: "brakeman"}}},
-		},
+		"ruby": &HookGroup{Description: "Ruby linters", Events: map[string]*EventConfig{
+			"PreToolUse": {Jobs: []HookJob{{Name: "rubocop", Run: "bundle exec rubocop"}, {Name: "brakeman", Run: "brakeman"}}},
+		}},
 	}
 
 	merged := MergeHooksConfigs(&base, &override)
-	ev := (*merged)["ruby"]["PreToolUse"]
+	if got := (*merged)["ruby"].Description; got != "Ruby linters" {
+		t.Errorf("group description = %q, want the override's", got)
+	}
+	ev := (*merged)["ruby"].Events["PreToolUse"]
 	if len(ev.Jobs) != 2 {
 		t.Fatalf("expected 2 jobs, got %d", len(ev.Jobs))
 	}
@@ -32,7 +37,7 @@ func TestParseHooksConfigFile_YAMLAndJSON(t *testing.T) {
 	dir := t.TempDir()
 	yml := filepath.Join(dir, "hooks.yml")
 	jsonp := filepath.Join(dir, "hooks.json")
-	yamlContent := []byte("ruby:\n  PreToolUse:\n    jobs:\n      - name: rubocop\n        run: rubocop\n")
+	yamlContent := []byte("ruby:\n  lock: bundle\n  PreToolUse:\n    jobs:\n      - name: rubocop\n        run: rubocop\n")
 	jsonContent := []byte(`{"ruby":{"PostToolUse":{"jobs":[{"name":"rspec","run":"rspec"}]}}}`)
 
 	if err := os.WriteFile(yml, yamlContent, 0o600); err != nil {
@@ -46,12 +51,83 @@ func TestParseHooksConfigFile_YAMLAndJSON(t *testing.T) {
 	if err != nil || cfgY["ruby"] == nil {
 		t.Fatalf("yaml parse failed: %v", err)
 	}
+	if g := cfgY["ruby"]; g.Lock != "bundle" || len(g.Events) != 1 {
+		t.Errorf("expected lock bundle beside one event, got %q and %v", g.Lock, g.Events)
+	}
 	cfgJ, err := parseHooksConfigFile(jsonp)
 	if err != nil || cfgJ["ruby"] == nil {
 		t.Fatalf("json parse failed: %v", err)
 	}
 }
 
+func TestParseHooksConfigFile_TOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks.toml")
+	content := []byte(`[ruby]
+lock = "bundle"
+lock_timeout = 60
+
+[[ruby.PreToolUse.jobs]]
+name = "rubocop"
+run = "rubocop ${TOOL_OUTPUT_FILE}"
+glob = ["*.rb"]
+timeout = 30
+env_file = ".env"
+
+[[ruby.PreToolUse.jobs]]
+name = "rspec"
+run = "rspec"
+env_file = [".env", ".env.test"]
+`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseHooksConfigFile(path)
+	if err != nil {
+		t.Fatalf("toml parse failed: %v", err)
+	}
+	if g := cfg["ruby"]; g.Lock != "bundle" || g.LockTimeout != 60 {
+		t.Errorf("unexpected group lock %q, timeout %d", g.Lock, g.LockTimeout)
+	}
+	ev := cfg["ruby"].Events["PreToolUse"]
+	if ev == nil || len(ev.Jobs) != 2 {
+		t.Fatalf("unexpected event config %+v", ev)
+	}
+	if j := ev.Jobs[0]; j.Timeout != 30 || len(j.Glob) != 1 || len(j.EnvFile) != 1 || j.EnvFile[0] != ".env" {
+		t.Errorf("unexpected first job %+v", j)
+	}
+	if j := ev.Jobs[1]; len(j.EnvFile) != 2 || j.EnvFile[1] != ".env.test" {
+		t.Errorf("unexpected second job %+v", j)
+	}
+
+	if _, err := ParseHooksConfig([]byte("[ruby.PreToolUse]\n[[ruby.PreToolUse.jobs]]\nenv_file = 3\n"), FormatTOML); err == nil {
+		t.Error("expected error for a non-string env_file")
+	}
+}
+
+func TestEncodeHooksConfig_RoundTrip(t *testing.T) {
+	cfg := CustomHooksConfig{"go": &HookGroup{Description: "Go checks", Lock: "go-build", LockTimeout: 30, Events: map[string]*EventConfig{"PostToolUse": {Jobs: []HookJob{
+		{Name: "vet", Run: "go vet ./...", Description: "Reports suspicious constructs", Glob: []string{"*.go"}, Timeout: 60, EnvFile: EnvFiles{".env"}},
+	}}}}}
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		data, err := EncodeHooksConfig(cfg, format)
+		if err != nil {
+			t.Fatalf("%s: encode failed: %v", format, err)
+		}
+		got, err := ParseHooksConfig(data, format)
+		if err != nil {
+			t.Fatalf("%s: parse failed: %v\n%s", format, err, data)
+		}
+		if !reflect.DeepEqual(got, cfg) {
+			t.Errorf("%s: round trip changed config:\n%s", format, data)
+		}
+	}
+	data, _ := EncodeHooksConfig(cfg, FormatTOML)
+	if strings.Contains(string(data), "max_input_bytes") {
+		t.Errorf("zero values should be omitted from TOML:\n%s", data)
+	}
+}
+
 func TestIsValidHookConfigFile(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -61,6 +137,7 @@ func TestIsValidHookConfigFile(t *testing.T) {
 		{"yml file", "hooks.yml", true},
 		{"yaml file", "hooks.yaml", true},
 		{"uppercase YML", "HOOKS.YML", true},
+		{"toml file", "hooks.toml", true},
 		{"config file to skip", "blues-traveler-config.json", false},
 		{"json file", "hooks.json", false},
 		{"txt file", "hooks.txt", false},
@@ -88,6 +165,8 @@ func TestCollectPerGroupFiles(t *testing.T) {
 		"ruby.yml",
 		"python.yaml",
 		"go.yml",
+		"rust.toml",
+		"hooks.toml",                 // canonical, should be skipped
 		"blues-traveler-config.json", // should be skipped
 		"readme.txt",                 // should be skipped
 	}
@@ -106,14 +185,14 @@ func TestCollectPerGroupFiles(t *testing.T) {
 
 	paths := collectPerGroupFiles(hooksDir)
 
-	// Should only include yml/yaml files, sorted alphabetically
-	expectedCount := 3 // ruby.yml, python.yaml, go.yml
+	// Should only include yml/yaml/toml files, sorted alphabetically
+	expectedCount := 4 // ruby.yml, python.yaml, go.yml, rust.toml
 	if len(paths) != expectedCount {
 		t.Errorf("collectPerGroupFiles() returned %d files, want %d", len(paths), expectedCount)
 	}
 
 	// Verify files are sorted
-	expectedFiles := []string{"go.yml", "python.yaml", "ruby.yml"}
+	expectedFiles := []string{"go.yml", "python.yaml", "ruby.yml", "rust.toml"}
 	for i, expected := range expectedFiles {
 		if filepath.Base(paths[i]) != expected {
 			t.Errorf("collectPerGroupFiles()[%d] basename = %s, want %s", i, filepath.Base(paths[i]), expected)
@@ -132,6 +211,9 @@ func TestAddProjectPaths(t *testing.T) {
 		filepath.Join(baseDir, "hooks.yml"),
 		filepath.Join(baseDir, "hooks.yaml"),
 		filepath.Join(baseDir, "hooks.json"),
+		filepath.Join(baseDir, "hooks", "hooks.toml"),
+		filepath.Join(baseDir, "hooks.toml"),
+		filepath.Join(baseDir, "hooks-local.toml"),
 	}
 
 	for _, expected := range expectedPaths {
@@ -178,3 +260,137 @@ func TestAddGlobalPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeHooksConfigs_LockSettings(t *testing.T) {
+	base := CustomHooksConfig{
+		"infra": &HookGroup{Lock: "compose", LockTimeout: 30, Events: map[string]*EventConfig{
+			"PostToolUse": {Jobs: []HookJob{{Name: "up", Run: "docker compose up -d"}}},
+		}},
+	}
+	override := CustomHooksConfig{
+		"infra": &HookGroup{LockTimeout: 90, Events: map[string]*EventConfig{
+			"PostToolUse": {Jobs: []HookJob{{Name: "migrate", Run: "make migrate"}}},
+		}},
+	}
+
+	g := (*MergeHooksConfigs(&base, &override))["infra"]
+	if g.Lock != "compose" {
+		t.Errorf("expected base lock to be kept, got %q", g.Lock)
+	}
+	if g.LockTimeout != 90 {
+		t.Errorf("expected override lock_timeout 90, got %d", g.LockTimeout)
+	}
+}
+
+func TestValidateHooksConfig_LockName(t *testing.T) {
+	tests := []struct {
+		name    string
+		lock    string
+		timeout int
+		wantErr bool
+	}{
+		{"no lock", "", 0, false},
+		{"simple name", "shared-db", 0, false},
+		{"dotted name", "compose.stack_1", 10, false},
+		{"path separator", "../etc", 0, true},
+		{"whitespace", "my lock", 0, true},
+		{"negative timeout", "db", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CustomHooksConfig{
+				"g": &HookGroup{Lock: tt.lock, LockTimeout: tt.timeout, Events: map[string]*EventConfig{
+					"PreToolUse": {Jobs: []HookJob{{Name: "j", Run: "true"}}},
+				}},
+			}
+			err := ValidateHooksConfig(&cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHooksConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHooksConfig_Shell(t *testing.T) {
+	for shell, wantErr := range map[string]bool{"": false, "bash": false, "pwsh": false, "cmd": false, "zsh": true, "PowerShell": true} {
+		cfg := CustomHooksConfig{
+			"g": &HookGroup{Events: map[string]*EventConfig{
+				"PreToolUse": {Jobs: []HookJob{{Name: "j", Run: "true", Shell: shell}}},
+			}},
+		}
+		if err := ValidateHooksConfig(&cfg); (err != nil) != wantErr {
+			t.Errorf("shell %q: error = %v, wantErr %v", shell, err, wantErr)
+		}
+	}
+}
+
+func TestValidateHooksConfig_Sandbox(t *testing.T) {
+	tests := []struct {
+		name    string
+		sandbox *JobSandbox
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"clear env", &JobSandbox{ClearEnv: true, EnvAllow: []string{"PATH", "LC_*"}}, false},
+		{"limits", &JobSandbox{MaxMemoryMB: 512, MaxCPUSeconds: 30, NoNetwork: true, ReadOnlyWorkdir: true}, false},
+		{"allow without clear", &JobSandbox{EnvAllow: []string{"PATH"}}, true},
+		{"empty allow entry", &JobSandbox{ClearEnv: true, EnvAllow: []string{" "}}, true},
+		{"negative memory", &JobSandbox{MaxMemoryMB: -1}, true},
+		{"negative cpu", &JobSandbox{MaxCPUSeconds: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CustomHooksConfig{
+				"g": &HookGroup{Events: map[string]*EventConfig{
+					"PreToolUse": {Jobs: []HookJob{{Name: "j", Run: "true", Sandbox: tt.sandbox}}},
+				}},
+			}
+			if err := ValidateHooksConfig(&cfg); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHooksConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHooksConfig_LifecycleCommands(t *testing.T) {
+	data := []byte(`
+infra:
+  PostToolUse:
+    before_all:
+      run: docker compose up -d db
+      timeout: 60
+    after_all:
+      run: docker compose down
+      on_failure: continue
+    jobs:
+      - name: migrate
+        run: make migrate
+`)
+	cfg, err := ParseHooksConfig(data, FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := cfg["infra"].Events["PostToolUse"]
+	if ev.BeforeAll == nil || ev.BeforeAll.Timeout != 60 || ev.BeforeAll.Continues() {
+		t.Errorf("before_all = %+v", ev.BeforeAll)
+	}
+	if ev.AfterAll == nil || !ev.AfterAll.Continues() {
+		t.Errorf("after_all = %+v", ev.AfterAll)
+	}
+	if err := ValidateHooksConfig(&cfg); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+
+	override := CustomHooksConfig{"infra": &HookGroup{Events: map[string]*EventConfig{"PostToolUse": {AfterAll: &LifecycleCommand{Run: "make down"}}}}}
+	merged := (*MergeHooksConfigs(&cfg, &override))["infra"].Events["PostToolUse"]
+	if merged.BeforeAll == nil || merged.BeforeAll.Run != "docker compose up -d db" || merged.AfterAll.Run != "make down" {
+		t.Errorf("merged lifecycle = %+v / %+v, want base before_all and override after_all", merged.BeforeAll, merged.AfterAll)
+	}
+
+	for _, bad := range []*LifecycleCommand{{Run: " "}, {Run: "true", Timeout: -1}, {Run: "true", OnFailure: "retry"}} {
+		cfg := CustomHooksConfig{"g": &HookGroup{Events: map[string]*EventConfig{"Stop": {BeforeAll: bad, Jobs: []HookJob{{Name: "j", Run: "true"}}}}}}
+		if err := ValidateHooksConfig(&cfg); err == nil {
+			t.Errorf("expected before_all %+v to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// hooksSchemaID identifies the schema emitted by HooksConfigSchema
+const hooksSchemaID = "https://github.com/klauern/blues-traveler/schemas/hooks-config.json"
+
+// Formats used by HooksConfigSchema for values JSON Schema can't describe.
+// ValidateHooksYAML checks them with the FormatCheckers it is given.
+const (
+	// SchemaFormatCondition is a skip/only expression
+	SchemaFormatCondition = "condition"
+	// SchemaFormatGlob is a file glob
+	SchemaFormatGlob = "glob"
+)
+
+// FormatCheckers validate string values by their schema format
+type FormatCheckers map[string]func(string) error
+
+// SchemaIssue is a schema violation at a position in a hooks file
+type SchemaIssue struct {
+	Line   int
+	Column int
+	// Path locates the value, e.g. go.PostToolUse.jobs[0].timeout
+	Path    string
+	Message string
+}
+
+func (i SchemaIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Path, i.Message)
+}
+
+// HooksConfigSchema returns a JSON Schema (draft 2020-12) for hooks.yml and
+// the other custom hook files: groups of events, each with execution hints
+// and jobs. events lists the event names groups may use. Job and event
+// fields come from HookJob and EventConfig, with the constraints
+// ValidateHooksConfig enforces layered on.
+func HooksConfigSchema(events []string) map[string]interface{} {
+	job := typeSchema(reflect.TypeOf(HookJob{}))
+	jobProps := job["properties"].(map[string]interface{})
+	describe := func(props map[string]interface{}, name, description string, extra map[string]interface{}) {
+		p := props[name].(map[string]interface{})
+		p["description"] = description
+		for k, v := range extra {
+			p[k] = v
+		}
+	}
+	describe(jobProps, "name", "Job name, unique within the group", map[string]interface{}{"minLength": 1})
+	describe(jobProps, "run", "Shell command to run", map[string]interface{}{"minLength": 1})
+	describe(jobProps, "description", "What the job does, shown in listings", nil)
+	jobProps["glob"].(map[string]interface{})["items"] = map[string]interface{}{"type": "string", "format": SchemaFormatGlob}
+	describe(jobProps, "glob", "Run only when a changed file matches one of these globs", nil)
+	describe(jobProps, "skip", "Skip the job when this expression is true", map[string]interface{}{"format": SchemaFormatCondition})
+	describe(jobProps, "only", "Run the job only when this expression is true", map[string]interface{}{"format": SchemaFormatCondition})
+	describe(jobProps, "timeout", "Seconds before the job is cancelled", map[string]interface{}{"minimum": 0})
+	describe(jobProps, "log_level", "Most verbose level this job logs", map[string]interface{}{"enum": ValidLogLevels})
+	describe(jobProps, "scope", "event (default) runs on the event's files; git on files changed since the session started", map[string]interface{}{"enum": []string{JobScopeEvent, JobScopeGit}})
+	describe(jobProps, "shell", "Shell that runs the command; defaults to bash, or pwsh on Windows without bash", map[string]interface{}{"enum": ValidShells})
+	describe(jobProps, "max_input_bytes", "Truncate the payload and environment values beyond this size", map[string]interface{}{"minimum": 0})
+	describe(jobProps, "serialize", "Run one instance of the job at a time in the project, behind a lock in .claude/hooks/locks", nil)
+	describe(jobProps, "serialize_timeout", "Seconds to wait for the serialize lock", map[string]interface{}{"minimum": 0})
+	describe(jobProps, "cache", "Reuse the job's last outcome while the environment variables in key, and the files they name, are unchanged", nil)
+	cacheProps := jobProps["cache"].(map[string]interface{})["properties"].(map[string]interface{})
+	cacheProps["key"].(map[string]interface{})["items"] = map[string]interface{}{"type": "string", "minLength": 1}
+	describe(cacheProps, "key", "Environment variables the outcome depends on, e.g. FILES_CHANGED", map[string]interface{}{"minItems": 1})
+	describe(cacheProps, "ttl", "Seconds an outcome is reused (default 600)", map[string]interface{}{"minimum": 0})
+	describe(jobProps, "capture_output", "Keep each run's stdout and stderr in .claude/hooks/artifacts, pruned like the hook logs", nil)
+	describe(jobProps, "sandbox", "Restrict the job's environment, resources, network and workdir; a sandbox that can't be set up fails the job", nil)
+	sandboxProps := jobProps["sandbox"].(map[string]interface{})["properties"].(map[string]interface{})
+	describe(sandboxProps, "clear_env", "Start the job without the inherited environment, except the variables in env_allow", nil)
+	sandboxProps["env_allow"].(map[string]interface{})["items"] = map[string]interface{}{"type": "string", "minLength": 1}
+	describe(sandboxProps, "env_allow", "Inherited variables clear_env keeps, e.g. PATH; a trailing * matches a prefix", nil)
+	describe(sandboxProps, "max_memory_mb", "Address space limit, in MB, for the job and each process it starts (Unix)", map[string]interface{}{"minimum": 0})
+	describe(sandboxProps, "max_cpu_seconds", "CPU time limit, in seconds, for the job and each process it starts (Unix)", map[string]interface{}{"minimum": 0})
+	describe(sandboxProps, "no_network", "Run the job without network access, in a Linux network namespace", nil)
+	describe(sandboxProps, "read_only_workdir", "Mount the job's workdir read-only, in a Linux mount namespace", nil)
+	jobProps["env_file"] = map[string]interface{}{
+		"description": ".env files loaded before the job runs, relative to its workdir",
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": "string", "minLength": 1},
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "minLength": 1}},
+		},
+	}
+
+	event := typeSchema(reflect.TypeOf(EventConfig{}))
+	eventProps := event["properties"].(map[string]interface{})
+	describe(eventProps, "parallel", "Run the event's jobs concurrently", nil)
+	describe(eventProps, "chain", "Run the event's jobs in order, passing the KEY=VALUE lines each writes to $BT_OUTPUT to later jobs", nil)
+	describe(eventProps, "max_input_bytes", "Truncate the payload and environment values beyond this size", map[string]interface{}{"minimum": 0})
+	for _, name := range []string{"before_all", "after_all"} {
+		lifecycleProps := eventProps[name].(map[string]interface{})["properties"].(map[string]interface{})
+		describe(lifecycleProps, "run", "Shell command to run", map[string]interface{}{"minLength": 1})
+		describe(lifecycleProps, "timeout", "Seconds before the command is cancelled", map[string]interface{}{"minimum": 0})
+		describe(lifecycleProps, "on_failure", "fail (default) reports a failure like a failed job; continue only logs it", map[string]interface{}{"enum": []string{LifecycleFail, LifecycleContinue}})
+	}
+	describe(eventProps, "before_all", "Setup command run once per event before any job; a failure skips the jobs unless on_failure is continue", nil)
+	describe(eventProps, "after_all", "Teardown command run once per event after the last job finishes", nil)
+	eventProps["jobs"] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/job"}}
+
+	sorted := append([]string{groupDescriptionKey, groupExtendsKey, groupLockKey, groupLockTimeoutKey}, events...)
+	sort.Strings(sorted)
+	group := map[string]interface{}{
+		"type":          "object",
+		"description":   "Jobs by event name",
+		"propertyNames": map[string]interface{}{"enum": sorted},
+		"properties": map[string]interface{}{
+			groupDescriptionKey: map[string]interface{}{"type": "string", "description": "What the group is for, shown in listings"},
+			groupExtendsKey:     map[string]interface{}{"type": "string", "minLength": 1, "description": "Group whose events and jobs this group inherits; jobs of the same name replace inherited ones"},
+			groupLockKey:        map[string]interface{}{"type": "string", "pattern": lockNamePattern.String(), "description": "Machine-wide mutex held while each of the group's jobs runs"},
+			groupLockTimeoutKey: map[string]interface{}{"type": "integer", "minimum": 0, "description": "Seconds to wait for the lock"},
+		},
+		"additionalProperties": map[string]interface{}{"$ref": "#/$defs/event"},
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  hooksSchemaID,
+		"title":                "blues-traveler custom hooks",
+		"description":          "Custom hook groups in .claude/hooks.yml, .claude/hooks/*.yml and related files",
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"$ref": "#/$defs/group"},
+		"$defs":                map[string]interface{}{"group": group, "event": event, "job": job},
+	}
+}
+
+// ValidateHooksYAML checks a YAML or JSON hooks file against schema and
+// returns each violation with its line and column. An error means the file
+// could not be parsed at all.
+func ValidateHooksYAML(data []byte, schema map[string]interface{}, formats FormatCheckers) ([]SchemaIssue, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	v := &schemaValidator{root: schema, formats: formats}
+	v.validate(doc.Content[0], schema, "")
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].Line != v.issues[j].Line {
+			return v.issues[i].Line < v.issues[j].Line
+		}
+		return v.issues[i].Column < v.issues[j].Column
+	})
+	return v.issues, nil
+}
+
+// schemaValidator walks YAML nodes against the subset of JSON Schema that
+// HooksConfigSchema uses
+type schemaValidator struct {
+	root    map[string]interface{}
+	formats FormatCheckers
+	issues  []SchemaIssue
+}
+
+func (v *schemaValidator) report(n *yaml.Node, path, format string, args ...interface{}) {
+	if path == "" {
+		path = "(root)"
+	}
+	v.issues = append(v.issues, SchemaIssue{Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve follows a "#/$defs/name" reference
+func (v *schemaValidator) resolve(schema map[string]interface{}) map[string]interface{} {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	defs, _ := v.root["$defs"].(map[string]interface{})
+	def, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+	return def
+}
+
+func (v *schemaValidator) validate(n *yaml.Node, schema map[string]interface{}, path string) {
+	schema = v.resolve(schema)
+	if schema == nil {
+		return
+	}
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+
+	if branches, ok := schema["anyOf"].([]interface{}); ok {
+		var kinds []string
+		for _, b := range branches {
+			branch, _ := b.(map[string]interface{})
+			sub := &schemaValidator{root: v.root, formats: v.formats}
+			sub.validate(n, branch, path)
+			if len(sub.issues) == 0 {
+				return
+			}
+			if t, ok := branch["type"].(string); ok {
+				kinds = append(kinds, t)
+			}
+		}
+		v.report(n, path, "must be a %s", strings.Join(kinds, " or "))
+		return
+	}
+
+	want, _ := schema["type"].(string)
+	if want != "" && !nodeHasType(n, want) {
+		v.report(n, path, "must be %s %s, got %s", article(want), want, nodeTypeName(n))
+		return
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		v.validateObject(n, schema, path)
+	case yaml.SequenceNode:
+		if min, ok := schemaInt(schema["minItems"]); ok && len(n.Content) < min {
+			v.report(n, path, "must have at least %d item(s)", min)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range n.Content {
+				v.validate(item, items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case yaml.ScalarNode:
+		v.validateScalar(n, schema, path)
+	}
+}
+
+func (v *schemaValidator) validateObject(n *yaml.Node, schema map[string]interface{}, path string) {
+	props, _ := schema["properties"].(map[string]interface{})
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.ShortTag() == "!!merge" {
+			// YAML merge keys (<<: *defaults) bring in fields checked where the anchor is defined
+			continue
+		}
+		name := key.Value
+		seen[name] = true
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+
+		if names, ok := schema["propertyNames"].(map[string]interface{}); ok {
+			if enum, ok := names["enum"].([]string); ok && !containsString(enum, name) {
+				v.report(key, childPath, "unknown key '%s'%s", name, suggestName(name, enum))
+				continue
+			}
+		}
+		if prop, ok := props[name].(map[string]interface{}); ok {
+			v.validate(value, prop, childPath)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				known := make([]string, 0, len(props))
+				for k := range props {
+					known = append(known, k)
+				}
+				v.report(key, childPath, "unknown field '%s'%s", name, suggestName(name, known))
+			}
+		case map[string]interface{}:
+			v.validate(value, extra, childPath)
+		}
+	}
+	if required, ok := schema["required"].([]string); ok {
+		for _, r := range required {
+			if !seen[r] {
+				v.report(n, path, "missing required field '%s'", r)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateScalar(n *yaml.Node, schema map[string]interface{}, path string) {
+	if enum, ok := schema["enum"].([]string); ok && !containsString(enum, n.Value) {
+		v.report(n, path, "'%s' is not one of %s", n.Value, strings.Join(enum, ", "))
+	}
+	if min, ok := schemaInt(schema["minLength"]); ok && len(n.Value) < min {
+		v.report(n, path, "must not be empty")
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(n.Value) {
+			v.report(n, path, "'%s' does not match %s", n.Value, pattern)
+		}
+	}
+	if min, ok := schemaInt(schema["minimum"]); ok {
+		var value int
+		if err := n.Decode(&value); err == nil && value < min {
+			v.report(n, path, "must be at least %d", min)
+		}
+	}
+	if format, ok := schema["format"].(string); ok {
+		if check := v.formats[format]; check != nil {
+			if err := check(n.Value); err != nil {
+				v.report(n, path, "invalid %s: %v", format, err)
+			}
+		}
+	}
+}
+
+// nodeHasType reports whether n holds a value of JSON Schema type want
+func nodeHasType(n *yaml.Node, want string) bool {
+	switch want {
+	case "object":
+		return n.Kind == yaml.MappingNode
+	case "array":
+		return n.Kind == yaml.SequenceNode
+	case "string":
+		// Unquoted scalars such as true or 30 decode into strings too
+		return n.Kind == yaml.ScalarNode && n.ShortTag() != "!!null"
+	case "integer":
+		return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!int"
+	case "number":
+		return n.Kind == yaml.ScalarNode && (n.ShortTag() == "!!int" || n.ShortTag() == "!!float")
+	case "boolean":
+		return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!bool"
+	}
+	return true
+}
+
+// nodeTypeName names n's type for messages
+func nodeTypeName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "an array"
+	}
+	switch n.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!int":
+		return "an integer"
+	case "!!float":
+		return "a number"
+	case "!!bool":
+		return "a boolean"
+	}
+	return "a string"
+}
+
+func article(word string) string {
+	if strings.ContainsRune("aeiou", rune(word[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+func schemaInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestName proposes the known name closest to a misspelled one: equal
+// up to case and separators, or at most two edits away
+func suggestName(name string, known []string) string {
+	normalize := strings.NewReplacer("_", "", "-", "").Replace
+	best, bestDist := "", 3
+	for _, k := range known {
+		if strings.EqualFold(normalize(k), normalize(name)) {
+			return fmt.Sprintf(" (did you mean '%s'?)", k)
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(k)); d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", best)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
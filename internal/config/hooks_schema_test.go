@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testSchemaFormats() FormatCheckers {
+	return FormatCheckers{
+		SchemaFormatCondition: func(expr string) error {
+			if strings.Count(expr, "(") != strings.Count(expr, ")") {
+				return errors.New("unbalanced parentheses")
+			}
+			return nil
+		},
+		SchemaFormatGlob: func(pattern string) error {
+			if strings.Contains(pattern, "[") && !strings.Contains(pattern, "]") {
+				return errors.New("syntax error in pattern")
+			}
+			return nil
+		},
+	}
+}
+
+func TestValidateHooksYAML(t *testing.T) {
+	schema := HooksConfigSchema([]string{"PreToolUse", "PostToolUse"})
+
+	valid := `go:
+  description: Checks for Go sources
+  PostToolUse:
+    parallel: true
+    jobs:
+      - name: vet
+        description: Reports suspicious constructs
+        run: go vet ./...
+        glob: ["*.go"]
+        only: matches(TOOL_FILE, "*.go")
+        timeout: 30
+`
+	issues, err := ValidateHooksYAML([]byte(valid), schema, testSchemaFormats())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+
+	invalid := `go:
+  PostToolUse:
+    parallel: "sometimes"
+    jobs:
+      - name: vet
+        run: ""
+        glob: ["src/[a"]
+        skip: len(TOOL_FILE
+        timeout: -1
+        Only: "x"
+  PreToolUze:
+    jobs: []
+`
+	issues, err = ValidateHooksYAML([]byte(invalid), schema, testSchemaFormats())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		"3:15: go.PostToolUse.parallel: must be a boolean",
+		"6:14: go.PostToolUse.jobs[0].run:",
+		"7:16: go.PostToolUse.jobs[0].glob[0]:",
+		"8:15: go.PostToolUse.jobs[0].skip:",
+		"9:18: go.PostToolUse.jobs[0].timeout:",
+		"10:9: go.PostToolUse.jobs[0].Only: unknown field 'Only' (did you mean 'only'?)",
+		"11:3: go.PreToolUze: unknown key 'PreToolUze' (did you mean 'PreToolUse'?)",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing issue %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestValidateHooksYAML_SyntaxError(t *testing.T) {
+	_, err := ValidateHooksYAML([]byte("go: [unclosed"), HooksConfigSchema(nil), nil)
+	if err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
+
+func TestSuggestName(t *testing.T) {
+	known := []string{"PreToolUse", "PostToolUse", "env_file"}
+	if got := suggestName("envFile", known); got != " (did you mean 'env_file'?)" {
+		t.Errorf("unexpected suggestion %q", got)
+	}
+	if got := suggestName("nothing_alike_at_all", known); got != "" {
+		t.Errorf("expected no suggestion, got %q", got)
+	}
+}
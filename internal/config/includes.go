@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+// IncludedHooksFile is a hooks file named by an includes pattern
+type IncludedHooksFile struct {
+	Path string
+	// Pattern is the includes entry that matched the file
+	Pattern string
+}
+
+// hooksIncludes returns the includes set in claudeDir's main config
+func hooksIncludes(claudeDir string) []string {
+	cfg, err := LoadLogConfig(filepath.Join(claudeDir, constants.HooksSubDir, constants.ConfigFileName))
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.Includes
+}
+
+// ResolveHooksIncludes expands includes patterns, relative to claudeDir, into
+// the hooks files they name. Files come in pattern order and each pattern's
+// matches sorted by path, so the merge order never depends on the directory
+// listing; a file matched again keeps its first place. The canonical hooks
+// files, which are always read, the main config and files without a hooks
+// config extension are left out.
+func ResolveHooksIncludes(claudeDir string, patterns []string) ([]IncludedHooksFile, error) {
+	seen := map[string]bool{}
+	for _, p := range canonicalHooksPaths(claudeDir) {
+		seen[filepath.Clean(p)] = true
+	}
+	var files []IncludedHooksFile
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		pattern := filepath.FromSlash(p)
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(claudeDir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid includes pattern '%s': %w\n  Suggestion: Use a glob relative to the .claude directory, e.g. hooks/*.yml", p, err)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			m = filepath.Clean(m)
+			if seen[m] || filepath.Base(m) == constants.ConfigFileName || HooksConfigFormat(m) == "" {
+				continue
+			}
+			if info, err := os.Stat(m); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			seen[m] = true
+			files = append(files, IncludedHooksFile{Path: m, Pattern: p})
+		}
+	}
+	return files, nil
+}
+
+// hooksFileCandidates lists the hooks files claudeDir's config may be read
+// from, highest precedence first: the canonical files, then the files its
+// includes name or, without includes, every per-group file in .claude/hooks
+func hooksFileCandidates(claudeDir string) ([]string, error) {
+	patterns := hooksIncludes(claudeDir)
+	if len(patterns) == 0 {
+		return addProjectPaths(claudeDir), nil
+	}
+	included, err := ResolveHooksIncludes(claudeDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+	paths := canonicalHooksPaths(claudeDir)
+	for _, f := range included {
+		paths = append(paths, f.Path)
+	}
+	return paths, nil
+}
+
+// hooksFileLayers reads claudeDir's hooks files into layers, as listed by
+// hooksFileCandidates
+func hooksFileLayers(scope, claudeDir string) ([]HooksConfigLayer, error) {
+	patterns := hooksIncludes(claudeDir)
+	if len(patterns) == 0 {
+		return fileLayers(scope, addProjectPaths(claudeDir))
+	}
+	layers, err := fileLayers(scope, canonicalHooksPaths(claudeDir))
+	if err != nil {
+		return nil, err
+	}
+	included, err := includedLayers(scope, claudeDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+	return append(layers, included...), nil
+}
+
+// includedLayers reads the files claudeDir's includes name, one layer each.
+// A group may be defined in only one included file; overriding it belongs
+// in hooks-local.yml, which takes precedence over every included file.
+func includedLayers(scope, claudeDir string, patterns []string) ([]HooksConfigLayer, error) {
+	files, err := ResolveHooksIncludes(claudeDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string]string{}
+	layers := make([]HooksConfigLayer, 0, len(files))
+	for _, f := range files {
+		cfg, err := parseHooksConfigFile(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Path, err)
+		}
+		for _, g := range ListHookGroups(&cfg) {
+			if prev, ok := owners[g]; ok {
+				return nil, fmt.Errorf("group '%s' is defined in both %s and %s, which are both included\n  Suggestion: Keep each group in one included file, and put overrides in hooks-local.yml", g, prev, f.Path)
+			}
+			owners[g] = f.Path
+		}
+		layers = append(layers, HooksConfigLayer{Scope: scope, Source: f.Path, Include: f.Pattern, Config: cfg})
+	}
+	return layers, nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setIncludes(t *testing.T, dir string, includes ...string) {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json")
+	if err := SaveLogConfig(path, &LogConfig{LogRotation: DefaultLogRotationConfig(), Includes: includes}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeClaudeFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".claude", filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func groupYAML(group, job string) string {
+	return group + ":\n  Stop:\n    jobs:\n      - name: " + job + "\n        run: true\n"
+}
+
+func TestResolveHooksIncludes_Order(t *testing.T) {
+	root := t.TempDir()
+	claudeDir := filepath.Join(root, ".claude")
+	writeClaudeFile(t, root, "hooks/hooks.yml", groupYAML("main", "j"))
+	writeClaudeFile(t, root, "hooks/python.yml", groupYAML("python", "ruff"))
+	writeClaudeFile(t, root, "hooks/go.yml", groupYAML("go", "vet"))
+	writeClaudeFile(t, root, "hooks/notes.txt", "not hooks")
+	writeClaudeFile(t, root, "teams/infra.yml", groupYAML("infra", "plan"))
+
+	files, err := ResolveHooksIncludes(claudeDir, []string{"teams/*.yml", "hooks/*", "hooks/go.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(claudeDir, f.Path)
+		got = append(got, filepath.ToSlash(rel)+" <- "+f.Pattern)
+	}
+	want := []string{"teams/infra.yml <- teams/*.yml", "hooks/go.yml <- hooks/*", "hooks/python.yml <- hooks/*"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("includes resolved to\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := ResolveHooksIncludes(claudeDir, []string{"hooks/[.yml"}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+func TestLoadHooksConfig_Includes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	writeClaudeFile(t, root, "hooks/hooks.yml", groupYAML("main", "j"))
+	writeClaudeFile(t, root, "hooks/go.yml", groupYAML("go", "vet"))
+	writeClaudeFile(t, root, "hooks-local.yml", groupYAML("go", "local"))
+	writeClaudeFile(t, root, "teams/infra.yml", groupYAML("infra", "plan"))
+	writeClaudeFile(t, root, "hooks/unlisted.yml", groupYAML("unlisted", "j"))
+	setIncludes(t, root, "hooks/go.yml", "teams/*.yml")
+	t.Chdir(root)
+
+	cfg, err := LoadHooksConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []string{"main", "go", "infra"} {
+		if (*cfg)[g] == nil {
+			t.Errorf("group %s missing from merged config", g)
+		}
+	}
+	if (*cfg)["unlisted"] != nil {
+		t.Error("a per-group file not named by includes should not be read")
+	}
+	if jobs := (*cfg)["go"].Events["Stop"].Jobs; len(jobs) != 2 {
+		t.Errorf("expected hooks-local.yml to merge over go.yml, got %+v", jobs)
+	}
+
+	layers, err := LoadHooksConfigLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var infra *HooksConfigLayer
+	for i := range layers {
+		if filepath.Base(layers[i].Source) == "infra.yml" {
+			infra = &layers[i]
+		}
+	}
+	if infra == nil || infra.Include != "teams/*.yml" {
+		t.Errorf("expected a layer for infra.yml from teams/*.yml, got %+v", layers)
+	}
+}
+
+func TestLoadHooksConfig_IncludesDuplicateGroup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	writeClaudeFile(t, root, "hooks/a.yml", groupYAML("go", "vet"))
+	writeClaudeFile(t, root, "hooks/b.yml", groupYAML("go", "test"))
+	setIncludes(t, root, "hooks/*.yml")
+	t.Chdir(root)
+
+	_, err := LoadHooksConfig()
+	if err == nil || !strings.Contains(err.Error(), "group 'go' is defined in both") {
+		t.Fatalf("expected a duplicate group error, got %v", err)
+	}
+}
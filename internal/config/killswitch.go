@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// GlobalKillSwitchFile disables every hook on the machine when present in ~/.claude
+	GlobalKillSwitchFile = "blues-traveler.disabled"
+	// ProjectKillSwitchFile disables every hook in a project when present in .claude
+	ProjectKillSwitchFile = "DISABLE_HOOKS"
+)
+
+// KillSwitchPaths returns the project and global kill-switch file locations,
+// in the order they are checked. Locations that cannot be resolved are omitted.
+func KillSwitchPaths() []string {
+	var paths []string
+	if dir, err := ProjectClaudeDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, ProjectKillSwitchFile))
+	}
+	if dir, err := GlobalClaudeDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, GlobalKillSwitchFile))
+	}
+	return paths
+}
+
+// ActiveKillSwitch reports the first kill-switch file that exists. Its
+// contents are ignored; presence alone turns every hook into a no-op allow.
+func ActiveKillSwitch() (string, bool) {
+	for _, p := range KillSwitchPaths() {
+		if _, err := os.Stat(p); err == nil {
+			return p, true
+		}
+	}
+	return "", false
+}
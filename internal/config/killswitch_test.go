@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+func TestActiveKillSwitch(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+
+	if path, ok := ActiveKillSwitch(); ok {
+		t.Fatalf("no kill switch expected, got %s", path)
+	}
+
+	global := filepath.Join(home, constants.ClaudeDir, GlobalKillSwitchFile)
+	writeKillSwitch(t, global)
+	if path, ok := ActiveKillSwitch(); !ok || path != global {
+		t.Fatalf("ActiveKillSwitch = (%q, %v), want global %q", path, ok, global)
+	}
+
+	local := filepath.Join(project, constants.ClaudeDir, ProjectKillSwitchFile)
+	writeKillSwitch(t, local)
+	if path, ok := ActiveKillSwitch(); !ok || path != local {
+		t.Fatalf("ActiveKillSwitch = (%q, %v), want project %q first", path, ok, local)
+	}
+
+	for _, p := range []string{local, global} {
+		if err := os.Remove(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := ActiveKillSwitch(); ok {
+		t.Error("removing the files should re-enable hooks")
+	}
+}
+
+func writeKillSwitch(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseLatencyThreshold validates a latencyThreshold value; empty turns slow
+// notes off
+func ParseLatencyThreshold(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid latencyThreshold '%s': use a duration such as 1.5s or 800ms", value)
+	}
+	return d, nil
+}
+
+// GetLatencyThreshold returns the latencyThreshold from the project config,
+// falling back to the global config. Missing or invalid values turn slow notes
+// off.
+func GetLatencyThreshold() time.Duration {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.LatencyThreshold == "" {
+			continue
+		}
+		d, err := ParseLatencyThreshold(cfg.LatencyThreshold)
+		if err != nil {
+			return 0
+		}
+		return d
+	}
+	return 0
+}
+
+// GetMetricsEnabled reports whether hook runs are recorded for 'hooks stats'
+// and 'hooks latency':
+// the project config's metrics setting, else the global one, else off
+func GetMetricsEnabled() bool {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.Metrics == nil {
+			continue
+		}
+		return *cfg.Metrics
+	}
+	return false
+}
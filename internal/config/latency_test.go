@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLatencyThreshold(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1.5s", 1500 * time.Millisecond, false},
+		{"800ms", 800 * time.Millisecond, false},
+		{"fast", 0, true},
+		{"-1s", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLatencyThreshold(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLatencyThreshold(%q) = (%v, %v), want (%v, err=%v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
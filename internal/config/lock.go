@@ -0,0 +1,200 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+const (
+	// DefaultLockWait is how long AcquireNamedLock waits when no wait is given
+	DefaultLockWait = 5 * time.Minute
+	// lockPollInterval is how often a waiting process retries the lock
+	lockPollInterval = 100 * time.Millisecond
+	// lockStaleAfter is how long a lock file without a pid is honored.
+	// Holders write their pid right after creating the file, so one still
+	// missing it this long after belongs to a process that died in between.
+	lockStaleAfter = 30 * time.Minute
+	// fileLockWait bounds how long a settings or registry update waits for
+	// another process editing the same file
+	fileLockWait = 30 * time.Second
+)
+
+// ErrLockTimeout is returned when a named lock could not be acquired in time
+var ErrLockTimeout = errors.New("timed out waiting for lock")
+
+// LockDir returns the machine-wide directory used for named locks. It lives
+// under the OS temp dir so every project and worktree on the host shares it.
+// BT_LOCK_DIR overrides the location (mainly for tests).
+func LockDir() string {
+	if dir := os.Getenv("BT_LOCK_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "blues-traveler", "locks")
+}
+
+// AcquireNamedLock blocks until the cross-process lock called name is held or
+// wait elapses. The returned release func removes the lock and is safe to
+// call more than once.
+func AcquireNamedLock(name string, wait time.Duration) (func(), error) {
+	return acquireLockIn(LockDir(), name, wait)
+}
+
+// JobLockDir returns the project directory holding the locks of serialize
+// jobs, .claude/hooks/locks
+func JobLockDir() (string, error) {
+	dir, err := ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, constants.HooksSubDir, "locks"), nil
+}
+
+// AcquireJobLock is AcquireNamedLock for a serialize job: the lock lives in
+// JobLockDir, so it only excludes runs of the job in the same project
+func AcquireJobLock(name string, wait time.Duration) (func(), error) {
+	dir, err := JobLockDir()
+	if err != nil {
+		return nil, err
+	}
+	return acquireLockIn(dir, name, wait)
+}
+
+// JobLockName turns a group and job name into a lock file name
+func JobLockName(group, job string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && lockNamePattern.MatchString(string(r)) {
+			return r
+		}
+		return '_'
+	}, group+"."+job)
+}
+
+func acquireLockIn(dir, name string, wait time.Duration) (func(), error) {
+	if wait <= 0 {
+		wait = DefaultLockWait
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	path := filepath.Join(dir, name+".lock")
+
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 - lock names are validated
+		if err == nil {
+			// The token is the pid plus a nonce, so release can tell this
+			// acquisition's file from one a later holder created at the same path
+			token := fmt.Sprintf("%d %d", os.Getpid(), time.Now().UnixNano())
+			_, _ = f.WriteString(token)
+			_ = f.Close()
+			released := false
+			return func() {
+				if released {
+					return
+				}
+				released = true
+				if data, err := os.ReadFile(path); err == nil && string(data) == token { // #nosec G304 - lock names are validated
+					_ = os.Remove(path)
+				}
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if reclaimStaleLock(path) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w '%s' after %s (held via %s)", ErrLockTimeout, name, wait, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// reclaimStaleLock removes a lock file whose holder exited without
+// releasing it (killed, or interrupted). A live holder keeps its lock however
+// long it runs; only a file with no pid falls back to its age.
+func reclaimStaleLock(path string) bool {
+	stale, err := lockIsStale(path)
+	if err != nil {
+		// Holder released between our attempts; retry immediately
+		return os.IsNotExist(err)
+	}
+	if !stale {
+		return false
+	}
+
+	// Another waiter may reclaim the same file and a new holder take the lock
+	// before we get to it, so move the file aside under a name only we use and
+	// check again what we actually took
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		return os.IsNotExist(err)
+	}
+	if stale, err := lockIsStale(aside); err == nil && !stale {
+		// It belongs to a live holder: put it back, unless the lock has been
+		// taken again meanwhile (a link never replaces an existing file)
+		_ = os.Link(aside, path)
+		_ = os.Remove(aside)
+		return false
+	}
+	_ = os.Remove(aside)
+	return true
+}
+
+// lockIsStale reports whether the holder of the lock file at path is gone
+func lockIsStale(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if pid, ok := lockHolderPID(path); ok {
+		return !processAlive(pid), nil
+	}
+	return time.Since(info.ModTime()) >= lockStaleAfter, nil
+}
+
+// lockHolderPID returns the pid recorded in the lock file, the first field of
+// its token. A file without one is still being written, or its holder died
+// before writing it.
+func lockHolderPID(path string) (int, bool) {
+	data, err := os.ReadFile(path) // #nosec G304 - lock file under the lock dir
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// LockFile takes the lock guarding read-modify-write updates of path, so two
+// sessions installing or syncing at once don't overwrite each other's
+// changes. Every path (by its absolute form) has its own lock.
+func LockFile(path string) (func(), error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	sum := sha256.Sum256([]byte(abs))
+	release, err := AcquireNamedLock("file-"+hex.EncodeToString(sum[:8]), fileLockWait)
+	if err != nil {
+		return nil, fmt.Errorf("%w\n  Suggestion: Another blues-traveler process is updating %s; retry when it finishes", err, path)
+	}
+	return release, nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAcquireNamedLock_Exclusive(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	release, err := AcquireNamedLock("shared-db", time.Second)
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	if _, err := AcquireNamedLock("shared-db", 150*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout while held, got %v", err)
+	}
+
+	release()
+	release() // idempotent
+
+	release2, err := AcquireNamedLock("shared-db", time.Second)
+	if err != nil {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+	release2()
+}
+
+func TestAcquireNamedLock_IndependentNames(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	a, err := AcquireNamedLock("a", time.Second)
+	if err != nil {
+		t.Fatalf("acquire a: %v", err)
+	}
+	defer a()
+	b, err := AcquireNamedLock("b", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("acquire b should not wait on a: %v", err)
+	}
+	b()
+}
+
+func TestAcquireNamedLock_ReclaimsStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_LOCK_DIR", dir)
+
+	// A file whose holder never wrote its pid is reclaimed once old
+	path := filepath.Join(dir, "stale.lock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := AcquireNamedLock("stale", 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected stale lock to be reclaimed: %v", err)
+	}
+	release()
+
+	// A live holder keeps its lock however old the file is, so long jobs
+	// stay fenced
+	held := filepath.Join(dir, "long.lock")
+	if err := os.WriteFile(held, []byte(strconv.Itoa(os.Getpid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(held, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireNamedLock("long", 150*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout for a long-running live holder, got %v", err)
+	}
+}
+
+func TestAcquireNamedLock_ReclaimsLockOfExitedHolder(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_LOCK_DIR", dir)
+
+	cmd := exec.Command("go", "version")
+	if err := cmd.Run(); err != nil {
+		t.Skip("no child process available")
+	}
+	exited := cmd.Process.Pid
+	if err := os.WriteFile(filepath.Join(dir, "orphan.lock"), []byte(strconv.Itoa(exited)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := AcquireNamedLock("orphan", 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected the exited holder's lock to be reclaimed: %v", err)
+	}
+	release()
+
+	// A live holder keeps its lock
+	if err := os.WriteFile(filepath.Join(dir, "live.lock"), []byte(strconv.Itoa(os.Getpid())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireNamedLock("live", 150*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout for a live holder, got %v", err)
+	}
+}
+
+func TestAcquireNamedLock_ReleaseKeepsLaterHoldersLock(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_LOCK_DIR", dir)
+
+	release, err := AcquireNamedLock("taken", time.Second)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	// Our lock was reclaimed and another process holds it now
+	path := filepath.Join(dir, "taken.lock")
+	other := strconv.Itoa(os.Getpid()) + " 1"
+	if err := os.WriteFile(path, []byte(other), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != other {
+		t.Fatalf("release removed another holder's lock: data=%q err=%v", data, err)
+	}
+}
+
+func TestReclaimStaleLock_LeavesNoFilesBehind(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gone.lock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reclaimStaleLock(path) {
+		t.Fatal("expected the stale lock to be reclaimed")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the lock dir to be empty, found %v", entries)
+	}
+
+	// A live holder's file stays where it is
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+" 1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if reclaimStaleLock(path) {
+		t.Fatal("a live holder's lock must not be reclaimed")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("live lock disappeared: %v", err)
+	}
+}
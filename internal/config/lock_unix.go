@@ -0,0 +1,15 @@
+//go:build !windows
+
+package config
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether pid is a running process. Signal 0 checks
+// without delivering anything; EPERM means it exists under another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
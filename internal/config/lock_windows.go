@@ -0,0 +1,16 @@
+//go:build windows
+
+package config
+
+import "os"
+
+// processAlive reports whether pid is a running process; on Windows
+// FindProcess fails for pids that don't exist
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// DefaultLogMaxTotalSize caps all hook logs in a directory together, in megabytes
+const DefaultLogMaxTotalSize = 100
+
+// rotatedLogPattern matches backups named by StreamingLogWriter.backupName,
+// compressed or not
+var rotatedLogPattern = regexp.MustCompile(backupStampPattern + `\.log(\.gz)?$`)
+
+// logFile is a hook log considered for size-cap cleanup
+type logFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+	rotated bool
+}
+
+// EnforceLogSizeCap deletes log files under logDir until together they take
+// at most maxTotalMB megabytes. Rotated backups go first, oldest first, then
+// active logs that were written to least recently. A non-positive cap
+// disables the check. It returns how many files were removed and the bytes
+// freed.
+func EnforceLogSizeCap(logDir string, maxTotalMB int) (int, int64, error) {
+	if maxTotalMB <= 0 {
+		return 0, 0, nil
+	}
+	limit := int64(maxTotalMB) * 1024 * 1024
+
+	var files []logFile
+	var total int64
+	err := filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".log" && ext != ".gz" {
+			return nil
+		}
+		files = append(files, logFile{path: path, size: info.Size(), modTime: info.ModTime(), rotated: rotatedLogPattern.MatchString(path)})
+		total += info.Size()
+		return nil
+	})
+	if err != nil || total <= limit {
+		return 0, 0, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].rotated != files[j].rotated {
+			return files[i].rotated
+		}
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	removed := 0
+	var freed int64
+	for _, f := range files {
+		if total <= limit {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		removed++
+		freed += f.size
+		total -= f.size
+	}
+	return removed, freed, nil
+}
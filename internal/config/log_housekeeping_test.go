@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnforceLogSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	mb := int64(1024 * 1024)
+	now := time.Now()
+	write := func(name string, size int64, age time.Duration) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, make([]byte, size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	oldActive := write("format.log", mb, 48*time.Hour)
+	backupOld := write("security-20260101T000000.000.log.gz", mb, 24*time.Hour)
+	backupNew := write("security-20260102T000000.000.log", mb, time.Hour)
+	active := write("security.log", mb, 0)
+	other := write("notes.txt", 5*mb, 72*time.Hour)
+
+	// Under the cap: nothing happens
+	if n, _, err := EnforceLogSizeCap(dir, 10); err != nil || n != 0 {
+		t.Fatalf("expected no removals under the cap, got %d (%v)", n, err)
+	}
+
+	// Over the cap: rotated backups go before the older active log
+	n, freed, err := EnforceLogSizeCap(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || freed != 2*mb {
+		t.Fatalf("expected 2 files and 2MB removed, got %d and %d", n, freed)
+	}
+	for _, p := range []string{backupOld, backupNew} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", filepath.Base(p))
+		}
+	}
+	for _, p := range []string{oldActive, active, other} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s to be kept: %v", filepath.Base(p), err)
+		}
+	}
+
+	// Zero disables the cap
+	if n, _, err := EnforceLogSizeCap(dir, 0); err != nil || n != 0 {
+		t.Fatalf("expected a zero cap to be ignored, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Log levels for hook log entries, from least to most verbose
+const (
+	LogLevelError = "error"
+	LogLevelWarn  = "warn"
+	LogLevelInfo  = "info"
+	LogLevelDebug = "debug"
+)
+
+// ValidLogLevels lists the accepted log_level values
+var ValidLogLevels = []string{LogLevelError, LogLevelWarn, LogLevelInfo, LogLevelDebug}
+
+// LoggingDefaults holds the project- or machine-wide defaults that jobs
+// inherit when they don't set log_level or quiet_success themselves
+type LoggingDefaults struct {
+	// Level is the most verbose level written; empty keeps every entry
+	Level string `json:"level,omitempty"`
+	// QuietSuccess drops all log lines for runs that succeed
+	QuietSuccess bool `json:"quietSuccess,omitempty"`
+}
+
+// ParseLogLevel validates a log level; empty means no filtering
+func ParseLogLevel(value string) (string, error) {
+	level := strings.ToLower(strings.TrimSpace(value))
+	if level == "" {
+		return "", nil
+	}
+	for _, valid := range ValidLogLevels {
+		if level == valid {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("invalid log level '%s' (valid: %s)", value, strings.Join(ValidLogLevels, ", "))
+}
+
+// LogLevelEnabled reports whether an entry at level passes the threshold.
+// An empty or unknown threshold lets everything through.
+func LogLevelEnabled(threshold, level string) bool {
+	t, ok := logLevelRank(threshold)
+	if !ok {
+		return true
+	}
+	l, ok := logLevelRank(level)
+	if !ok {
+		return true
+	}
+	return l <= t
+}
+
+func logLevelRank(level string) (int, bool) {
+	for i, valid := range ValidLogLevels {
+		if level == valid {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// GetLoggingDefaults returns the logging section from the project config,
+// falling back to the global config. Invalid levels are ignored.
+func GetLoggingDefaults() LoggingDefaults {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.Logging == nil {
+			continue
+		}
+		defaults := *cfg.Logging
+		if level, err := ParseLogLevel(defaults.Level); err == nil {
+			defaults.Level = level
+		} else {
+			defaults.Level = ""
+		}
+		return defaults
+	}
+	return LoggingDefaults{}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	if level, err := ParseLogLevel(" WARN "); err != nil || level != LogLevelWarn {
+		t.Errorf("ParseLogLevel(WARN) = %q, %v", level, err)
+	}
+	if level, err := ParseLogLevel(""); err != nil || level != "" {
+		t.Errorf("ParseLogLevel(\"\") = %q, %v", level, err)
+	}
+	if _, err := ParseLogLevel("trace"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestLogLevelEnabled(t *testing.T) {
+	tests := []struct {
+		threshold, level string
+		want             bool
+	}{
+		{"", LogLevelDebug, true},
+		{LogLevelInfo, LogLevelDebug, false},
+		{LogLevelInfo, LogLevelInfo, true},
+		{LogLevelInfo, LogLevelError, true},
+		{LogLevelError, LogLevelWarn, false},
+		{LogLevelWarn, "", true},
+	}
+	for _, tt := range tests {
+		if got := LogLevelEnabled(tt.threshold, tt.level); got != tt.want {
+			t.Errorf("LogLevelEnabled(%q, %q) = %v, want %v", tt.threshold, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestGetLoggingDefaults_ProjectThenGlobal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+
+	if got := GetLoggingDefaults(); got != (LoggingDefaults{}) {
+		t.Fatalf("expected zero defaults without config, got %+v", got)
+	}
+
+	globalPath, err := GetLogConfigPath(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeLogConfig(t, globalPath, `{"logging":{"level":"warn","quietSuccess":true}}`)
+	if got := GetLoggingDefaults(); got.Level != LogLevelWarn || !got.QuietSuccess {
+		t.Errorf("expected global defaults, got %+v", got)
+	}
+
+	projectPath, err := GetLogConfigPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeLogConfig(t, projectPath, `{"logging":{"level":"Debug"}}`)
+	if got := GetLoggingDefaults(); got.Level != LogLevelDebug || got.QuietSuccess {
+		t.Errorf("expected project defaults to win, got %+v", got)
+	}
+
+	cfg, err := LoadLogConfig(projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Other["logging"]; ok {
+		t.Error("logging should be a known key, not preserved in Other")
+	}
+}
+
+func writeLogConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
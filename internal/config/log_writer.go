@@ -0,0 +1,391 @@
+package config
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRotationCheckInterval is how often the background goroutine re-checks
+// the tracked file size when no write has crossed the threshold. Writes over
+// the limit signal the goroutine directly and Close rotates before it
+// returns, so the periodic check only matters for long-lived writers: it
+// retries a rotation that failed, e.g. while another process held the file.
+const DefaultRotationCheckInterval = 2 * time.Second
+
+const megabyte = 1024 * 1024
+
+// StreamingLogWriter is a size-aware io.WriteCloser that streams writes
+// straight to the active log file. Rotation is not evaluated on every write:
+// an in-memory byte counter is compared against the limit and, once crossed,
+// a background goroutine performs the rotation. Rotated files are compressed
+// on a separate goroutine so bursts of PostToolUse events never wait on gzip.
+// Hook processes are short-lived, so Close finishes any rotation and
+// compression still pending rather than leaving it for a later run.
+type StreamingLogWriter struct {
+	path          string
+	maxBytes      int64
+	maxBackups    int
+	maxAge        time.Duration
+	compress      bool
+	checkInterval time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+	size atomic.Int64
+
+	rotateCh   chan struct{}
+	compressCh chan string
+	done       chan struct{}
+	closeOnce  sync.Once
+	wg         sync.WaitGroup
+}
+
+// NewStreamingLogWriter creates a streaming writer for logPath using the
+// size, backup and age limits from cfg. A zero MaxSize disables rotation.
+func NewStreamingLogWriter(logPath string, cfg LogRotationConfig, checkInterval time.Duration) (*StreamingLogWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if checkInterval <= 0 {
+		checkInterval = DefaultRotationCheckInterval
+	}
+
+	w := &StreamingLogWriter{
+		path:          logPath,
+		maxBytes:      int64(cfg.MaxSize) * megabyte,
+		maxBackups:    cfg.MaxBackups,
+		maxAge:        time.Duration(cfg.MaxAge) * 24 * time.Hour,
+		compress:      cfg.Compress,
+		checkInterval: checkInterval,
+		rotateCh:      make(chan struct{}, 1),
+		compressCh:    make(chan string, 16),
+		done:          make(chan struct{}),
+	}
+	if err := w.openExisting(); err != nil {
+		return nil, err
+	}
+
+	w.wg.Add(2)
+	go w.rotationLoop()
+	go w.compressionLoop()
+	if w.maxBytes > 0 && w.size.Load() >= w.maxBytes {
+		// The file was already over the limit when a previous run exited
+		w.rotateCh <- struct{}{}
+	}
+	return w, nil
+}
+
+// Write appends p to the active log file and schedules a rotation when the
+// tracked size crosses the configured limit.
+func (w *StreamingLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.file == nil {
+		w.mu.Unlock()
+		return 0, os.ErrClosed
+	}
+	n, err := w.file.Write(p)
+	w.mu.Unlock()
+
+	if w.maxBytes > 0 && w.size.Add(int64(n)) >= w.maxBytes {
+		select {
+		case w.rotateCh <- struct{}{}:
+		default:
+			// A rotation is already pending
+		}
+	}
+	return n, err
+}
+
+// Close stops the background goroutines, performs a rotation still due,
+// waits for pending compression to finish and closes the active file.
+func (w *StreamingLogWriter) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		// A signal the rotation goroutine had not picked up yet; with the
+		// goroutines stopped, the backup is compressed here
+		w.rotateIfNeeded()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.file != nil {
+			err = w.file.Close()
+			w.file = nil
+		}
+	})
+	return err
+}
+
+// Rotate forces a rotation of the active file regardless of its size.
+func (w *StreamingLogWriter) Rotate() error {
+	backup, err := w.rotate()
+	if err != nil || backup == "" {
+		return err
+	}
+	w.queueBackup(backup)
+	return nil
+}
+
+// openExisting opens the log file for appending and seeds the size counter.
+func (w *StreamingLogWriter) openExisting() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304 - controlled log path
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	w.file = f
+	w.size.Store(info.Size())
+	return nil
+}
+
+// rotationLoop waits for a threshold signal or the periodic tick and rotates
+// the file once it is over the limit.
+func (w *StreamingLogWriter) rotationLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.rotateCh:
+			w.rotateIfNeeded()
+		case <-ticker.C:
+			w.rotateIfNeeded()
+		}
+	}
+}
+
+func (w *StreamingLogWriter) rotateIfNeeded() {
+	if w.maxBytes <= 0 || w.size.Load() < w.maxBytes {
+		return
+	}
+	backup, err := w.rotate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to rotate log file %s: %v\n", w.path, err)
+		return
+	}
+	if backup != "" {
+		w.queueBackup(backup)
+	}
+}
+
+// rotate renames the active file to a timestamped backup and reopens a fresh
+// file. It returns the backup path, or "" if the writer is closed.
+func (w *StreamingLogWriter) rotate() (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return "", nil
+	}
+	if err := w.file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close log file: %w", err)
+	}
+	w.file = nil
+
+	backup := w.backupName(time.Now())
+	if err := os.Rename(w.path, backup); err != nil {
+		// Keep writing to the original file rather than dropping entries
+		if openErr := w.openExisting(); openErr != nil {
+			return "", openErr
+		}
+		return "", fmt.Errorf("failed to rename log file: %w", err)
+	}
+	if err := w.openExisting(); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// queueBackup hands a rotated file to the compression goroutine. When
+// compression is disabled only backup pruning is performed.
+func (w *StreamingLogWriter) queueBackup(backup string) {
+	if !w.compress {
+		w.pruneBackups()
+		return
+	}
+	select {
+	case <-w.done:
+		// Background goroutines are stopping; finish the work here
+		w.compressAndPrune(backup)
+		return
+	default:
+	}
+	select {
+	case w.compressCh <- backup:
+	default:
+		// Queue is full; compress inline rather than leaving the file behind
+		w.compressAndPrune(backup)
+	}
+}
+
+// compressionLoop compresses rotated files until the writer is closed, then
+// drains anything still queued so no backup is left uncompressed.
+func (w *StreamingLogWriter) compressionLoop() {
+	defer w.wg.Done()
+	for {
+		select {
+		case backup := <-w.compressCh:
+			w.compressAndPrune(backup)
+		case <-w.done:
+			for {
+				select {
+				case backup := <-w.compressCh:
+					w.compressAndPrune(backup)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *StreamingLogWriter) compressAndPrune(backup string) {
+	if err := gzipFile(backup); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to compress log file %s: %v\n", backup, err)
+	}
+	w.pruneBackups()
+}
+
+// backupName returns a unique backup path alongside the active file, e.g.
+// audit-20250101T120000.000.log.
+func (w *StreamingLogWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+	stamp := t.Format("20060102T150405.000")
+	name := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+// pruneBackups removes backups older than MaxAge days, then the oldest
+// backups beyond MaxBackups.
+func (w *StreamingLogWriter) pruneBackups() {
+	backups := w.listBackups()
+	var kept []string
+	for _, b := range backups {
+		if w.maxAge > 0 {
+			if info, err := os.Stat(b); err == nil && time.Since(info.ModTime()) > w.maxAge {
+				removeBackup(b)
+				continue
+			}
+		}
+		kept = append(kept, b)
+	}
+	if w.maxBackups <= 0 || len(kept) <= w.maxBackups {
+		return
+	}
+	for _, old := range kept[:len(kept)-w.maxBackups] {
+		removeBackup(old)
+	}
+}
+
+func removeBackup(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove old log backup %s: %v", path, err)
+	}
+}
+
+// backupStampPattern matches the timestamp, and the counter added when two
+// rotations share a millisecond, that backupName puts in a backup's name
+const backupStampPattern = `-(\d{8}T\d{6}\.\d{3})(?:\.(\d+))?`
+
+// listBackups returns rotated files for this writer sorted oldest first.
+// Only names backupName produces count, so another log sharing the prefix
+// (audit-extra.log next to audit.log) is never pruned.
+func (w *StreamingLogWriter) listBackups() []string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(filepath.Base(w.path), ext)
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(base) + backupStampPattern + regexp.QuoteMeta(ext) + `(?:\.gz)?$`)
+
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil
+	}
+	type backup struct {
+		path  string
+		stamp string
+		n     int
+	}
+	var found []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := pattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		found = append(found, backup{filepath.Join(filepath.Dir(w.path), e.Name()), m[1], n})
+	}
+	// Timestamps are fixed-width, so lexical order is chronological; the
+	// counter orders rotations within the same millisecond
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].stamp != found[j].stamp {
+			return found[i].stamp < found[j].stamp
+		}
+		return found[i].n < found[j].n
+	})
+	backups := make([]string, len(found))
+	for i, b := range found {
+		backups[i] = b.path
+	}
+	return backups
+}
+
+// gzipFile compresses path into path.gz and removes the original.
+func gzipFile(path string) error {
+	src, err := os.Open(path) // #nosec G304 - rotated log path
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304 - rotated log path
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = gz.Close()
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
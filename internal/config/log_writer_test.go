@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamingLogWriter_RotatesAndCompresses(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "audit.log")
+
+	w, err := NewStreamingLogWriter(logPath, LogRotationConfig{MaxSize: 1, MaxBackups: 2, Compress: true}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	w.maxBytes = 64 // keep the test small
+
+	line := []byte(strings.Repeat("x", 40) + "\n")
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write(line); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if err := w.Rotate(); err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	backups := w.listBackups()
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups after pruning, got %d: %v", len(backups), backups)
+	}
+	for _, b := range backups {
+		if !strings.HasSuffix(b, ".gz") {
+			t.Errorf("expected compressed backup, got %s", b)
+		}
+	}
+}
+
+func TestStreamingLogWriter_ThresholdTriggersBackgroundRotation(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "burst.log")
+
+	w, err := NewStreamingLogWriter(logPath, LogRotationConfig{MaxSize: 1, MaxBackups: 5}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	w.maxBytes = 32
+
+	if _, err := w.Write(bytes.Repeat([]byte("y"), 40)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(w.listBackups()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := len(w.listBackups()); got != 1 {
+		t.Fatalf("expected 1 rotated backup, got %d", got)
+	}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatalf("active log missing after rotation: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("expected fresh active log, got size %d", info.Size())
+	}
+}
+
+func TestStreamingLogWriter_SeedsSizeFromExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "existing.log")
+	if err := os.WriteFile(logPath, []byte("previous\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewStreamingLogWriter(logPath, DefaultLogRotationConfig(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	if got := w.size.Load(); got != int64(len("previous\n")) {
+		t.Errorf("expected size seeded from existing file, got %d", got)
+	}
+}
+
+func TestStreamingLogWriter_WriteAfterClose(t *testing.T) {
+	w, err := NewStreamingLogWriter(filepath.Join(t.TempDir(), "closed.log"), DefaultLogRotationConfig(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Error("expected error writing to closed writer")
+	}
+}
+
+func TestStreamingLogWriter_CloseRotatesPendingThreshold(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "short.log")
+	w, err := NewStreamingLogWriter(logPath, LogRotationConfig{MaxSize: 1, MaxBackups: 5, Compress: true}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	w.maxBytes = 32
+
+	// A hook process writes once and exits straight away
+	if _, err := w.Write(bytes.Repeat([]byte("z"), 40)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	backups := w.listBackups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("expected one compressed backup after Close, got %v", backups)
+	}
+}
+
+func TestStreamingLogWriter_ListBackupsMatchesOwnNamesOnly(t *testing.T) {
+	dir := t.TempDir()
+	w := &StreamingLogWriter{path: filepath.Join(dir, "audit.log")}
+	for _, name := range []string{
+		"audit-20250101T120000.000.log.gz",
+		"audit-20250101T120000.000.1.log",
+		"audit-20250101T120000.000.log",
+		"audit-extra.log",
+		"audit-extra-20250101T120000.000.log",
+		"audit-20250101.log",
+		"audit-20250101T120000.000.log.bak",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, b := range w.listBackups() {
+		got = append(got, filepath.Base(b))
+	}
+	want := []string{"audit-20250101T120000.000.log.gz", "audit-20250101T120000.000.log", "audit-20250101T120000.000.1.log"}
+	// The two unnumbered backups share a timestamp, so only the last
+	// position is fixed
+	if len(got) != len(want) || got[2] != want[2] {
+		t.Fatalf("listBackups() = %v, want %v", got, want)
+	}
+}
+
+func TestStreamingLogWriter_PruneRemovesBackupsPastMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	w := &StreamingLogWriter{path: filepath.Join(dir, "audit.log"), maxBackups: 5, maxAge: 24 * time.Hour}
+	old := filepath.Join(dir, "audit-20240101T120000.000.log.gz")
+	recent := filepath.Join(dir, "audit-20250101T120000.000.log.gz")
+	for _, p := range []string{old, recent} {
+		if err := os.WriteFile(p, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	w.pruneBackups()
+
+	if fileExists(old) {
+		t.Error("expected the backup past MaxAge to be removed")
+	}
+	if !fileExists(recent) {
+		t.Error("expected the recent backup to be kept")
+	}
+}
+
+// BenchmarkStreamingLogWriter_PostToolUseBurst simulates a burst of small
+// JSONL entries such as those produced by rapid PostToolUse events.
+func BenchmarkStreamingLogWriter_PostToolUseBurst(b *testing.B) {
+	w, err := NewStreamingLogWriter(filepath.Join(b.TempDir(), "bench.log"),
+		LogRotationConfig{MaxSize: 1, MaxBackups: 3, Compress: true}, DefaultRotationCheckInterval)
+	if err != nil {
+		b.Fatalf("NewStreamingLogWriter: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	entry := []byte(`{"timestamp":"2025-01-01T00:00:00Z","hook_key":"audit","event":"post_tool_use","tool_name":"Edit"}` + "\n")
+	b.SetBytes(int64(len(entry)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := w.Write(entry); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -9,33 +9,93 @@ import (
 	"time"
 
 	"github.com/klauern/blues-traveler/internal/constants"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // LogRotationConfig holds configuration for log rotation
 type LogRotationConfig struct {
-	MaxAge     int  // Maximum number of days to retain log files
-	MaxSize    int  // Maximum size in megabytes before rotation
-	MaxBackups int  // Maximum number of backup files to retain
-	Compress   bool // Whether to compress rotated files
+	MaxAge       int  // Maximum number of days to retain log files
+	MaxSize      int  // Maximum size in megabytes before rotation
+	MaxBackups   int  // Maximum number of backup files to retain
+	Compress     bool // Whether to compress rotated files
+	MaxTotalSize int  // Maximum megabytes across all hook logs; 0 disables the cap
 }
 
 // DefaultLogRotationConfig returns sensible defaults for log rotation
 func DefaultLogRotationConfig() LogRotationConfig {
 	return LogRotationConfig{
-		MaxAge:     30,   // 30 days default retention
-		MaxSize:    10,   // 10MB per file
-		MaxBackups: 5,    // Keep 5 backup files
-		Compress:   true, // Compress old files
+		MaxAge:       30,                     // 30 days default retention
+		MaxSize:      10,                     // 10MB per file
+		MaxBackups:   5,                      // Keep 5 backup files
+		Compress:     true,                   // Compress old files
+		MaxTotalSize: DefaultLogMaxTotalSize, // 100MB across all hook logs
 	}
 }
 
 // LogConfig represents our application's logging configuration
 type LogConfig struct {
-	LogRotation LogRotationConfig      `json:"logRotation"`
-	CustomHooks CustomHooksConfig      `json:"customHooks,omitempty"`
-	BlockedURLs []BlockedURL           `json:"blockedUrls,omitempty"`
-	Other       map[string]interface{} `json:"-"`
+	LogRotation LogRotationConfig `json:"logRotation"`
+	// Logging sets the default log level and quiet-success mode for jobs
+	Logging     *LoggingDefaults  `json:"logging,omitempty"`
+	CustomHooks CustomHooksConfig `json:"customHooks,omitempty"`
+	BlockedURLs []BlockedURL      `json:"blockedUrls,omitempty"`
+	CodeOwners  *CodeOwnersConfig `json:"codeOwners,omitempty"`
+	LargeFiles  *LargeFilesConfig `json:"largeFiles,omitempty"`
+	// BlockedURLsRemote adds a shared denylist fetched over HTTP to blockedUrls
+	BlockedURLsRemote *BlockedURLsRemote `json:"blockedUrlsRemote,omitempty"`
+	// DeleteGuard configures the delete-guard hook
+	DeleteGuard *DeleteGuardConfig `json:"deleteGuard,omitempty"`
+	// Secrets configures the secrets hook
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+	// LockfileChurn configures the lockfile-churn hook
+	LockfileChurn *LockfileChurnConfig `json:"lockfileChurn,omitempty"`
+	// GitGuard configures the git-guard hook
+	GitGuard *GitGuardConfig `json:"gitGuard,omitempty"`
+	// MCPGuard configures the mcp-guard hook
+	MCPGuard *MCPGuardConfig `json:"mcpGuard,omitempty"`
+	// DenyTools configures the deny-tools hook
+	DenyTools *DenyToolsConfig `json:"denyTools,omitempty"`
+	// PathGuard configures the path-guard hook
+	PathGuard *PathGuardConfig `json:"pathGuard,omitempty"`
+	// PRReadiness configures the pr-readiness hook
+	PRReadiness *PRReadinessConfig `json:"prReadiness,omitempty"`
+	// CoverageGate configures the coverage-gate hook
+	CoverageGate *CoverageGateConfig `json:"coverageGate,omitempty"`
+	// Notify configures the notify hook
+	Notify *NotifyConfig `json:"notify,omitempty"`
+	// Context configures the context hook
+	Context *ContextConfig `json:"context,omitempty"`
+	// Changelog configures the changelog hook
+	Changelog *ChangelogConfig `json:"changelog,omitempty"`
+	// Format configures the format hook's formatters
+	Format *FormatConfig `json:"format,omitempty"`
+	// Audit configures where the audit hook sends records besides .claude/audit
+	Audit       *AuditConfig `json:"audit,omitempty"`
+	MergePolicy string       `json:"mergePolicy,omitempty"`
+	// LatencyThreshold is a Go duration (e.g. "1.5s"); hook responses slower
+	// than this note how long the hook took
+	LatencyThreshold string `json:"latencyThreshold,omitempty"`
+	// Experiments A/B test the wording of hook messages to the agent
+	Experiments []Experiment `json:"experiments,omitempty"`
+	// Metrics records every hook run to .claude/hooks/metrics for 'hooks stats'
+	Metrics *bool `json:"metrics,omitempty"`
+	// TerseOutput keeps what hooks print for Claude Code short, plain ASCII
+	// and free of color codes and timing notes
+	TerseOutput *bool `json:"terseOutput,omitempty"`
+	// Redaction scrubs secrets from hook logs, audit records and exports
+	Redaction *RedactionConfig `json:"redaction,omitempty"`
+	// Retention bounds local data by category, keyed by RetentionCategories
+	Retention map[string]RetentionPolicy `json:"retention,omitempty"`
+	// ExecPath selects how hook commands reference the binary: absolute, path or symlink
+	ExecPath string `json:"execPath,omitempty"`
+	// ExtendsPath inherits custom hooks from a parent project (a directory or
+	// hooks file, relative to the project root), or "auto" for every ancestor
+	// .claude directory up to the git root
+	ExtendsPath string `json:"extendsPath,omitempty"`
+	// Includes are globs, relative to the .claude directory, naming the
+	// per-group hooks files to load (e.g. "hooks/*.yml") in place of every
+	// file in .claude/hooks
+	Includes []string               `json:"includes,omitempty"`
+	Other    map[string]interface{} `json:"-"`
 }
 
 // BlockedURL represents a blocked URL prefix + optional suggestion
@@ -44,22 +104,322 @@ type BlockedURL struct {
 	Suggestion string `json:"suggestion,omitempty"`
 }
 
+// BlockedURLsRemote is a denylist the fetch-blocker hook downloads and
+// caches. The body is either blocked-urls.txt lines ("prefix|suggestion")
+// or a JSON array of blockedUrls entries. URL and header values expand
+// ${ENV} variables, so tokens can stay out of the config file.
+type BlockedURLsRemote struct {
+	URL string `json:"url"`
+	// Headers are added to the request, e.g. Authorization
+	Headers map[string]string `json:"headers,omitempty"`
+	// CacheTTL is a Go duration (e.g. "6h") the download is reused for;
+	// defaults to 1h
+	CacheTTL string `json:"cacheTtl,omitempty"`
+	// Timeout in seconds for the download; defaults to 5
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// CodeOwnersConfig configures the codeowners hook
+type CodeOwnersConfig struct {
+	// File overrides CODEOWNERS discovery; relative paths are resolved from the project root
+	File string `json:"file,omitempty"`
+	// RestrictedOwners lists owners (e.g. "@org/security") whose paths agents may not edit
+	RestrictedOwners []string `json:"restrictedOwners,omitempty"`
+}
+
+// LargeFilesConfig configures the large-files hook
+type LargeFilesConfig struct {
+	// MaxBytes is the largest file a Write may create; zero uses the default (1 MiB)
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// AllowedDirs lists project-relative directories or globs where large and binary files are fine
+	AllowedDirs []string `json:"allowedDirs,omitempty"`
+	// Action is "block" (default) or "ask" to let the user decide
+	Action string `json:"action,omitempty"`
+}
+
+// DeleteGuardConfig configures the delete-guard hook
+type DeleteGuardConfig struct {
+	// ProtectedPaths lists project-relative directories or globs that may not
+	// be deleted; .git is always protected
+	ProtectedPaths []string `json:"protectedPaths,omitempty"`
+	// Trash moves deleted files to .claude/trash instead of letting them go
+	Trash bool `json:"trash,omitempty"`
+}
+
+// SecretsConfig configures the secrets hook
+type SecretsConfig struct {
+	// Patterns are checked in addition to the built-in credential patterns
+	Patterns []SecretPattern `json:"patterns,omitempty"`
+	// EntropyThreshold is the Shannon entropy, in bits per character, above
+	// which a long token counts as a secret; zero uses the default (4.3) and
+	// a negative value turns the check off
+	EntropyThreshold float64 `json:"entropyThreshold,omitempty"`
+	// MinTokenLength is the shortest token the entropy check looks at; zero
+	// uses the default (32)
+	MinTokenLength int `json:"minTokenLength,omitempty"`
+	// AllowlistFile overrides .claude/secrets-allowlist.txt, relative to the
+	// project root
+	AllowlistFile string `json:"allowlistFile,omitempty"`
+}
+
+// SecretPattern is a named regular expression for a kind of secret
+type SecretPattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// LockfileChurnConfig configures the lockfile-churn hook
+type LockfileChurnConfig struct {
+	// MaxLines is how many changed lines a lockfile, or a vendored directory
+	// in total, may have before staging it needs confirmation; zero uses
+	// the default (500)
+	MaxLines int `json:"maxLines,omitempty"`
+	// Lockfiles adds file names or globs to the built-in lockfile list
+	Lockfiles []string `json:"lockfiles,omitempty"`
+	// VendorDirs adds project-relative directories to vendor, node_modules
+	// and third_party
+	VendorDirs []string `json:"vendorDirs,omitempty"`
+	// Action is "ask" (default) or "block"
+	Action string `json:"action,omitempty"`
+}
+
+// GitGuardConfig configures the git-guard hook
+type GitGuardConfig struct {
+	// ProtectedBranches replaces the default protected branches (main,
+	// master, release, release/* and release-*); entries may be globs
+	ProtectedBranches []string `json:"protectedBranches,omitempty"`
+	// AllowForcePush lets --force pushes to unprotected branches through;
+	// --force-with-lease is always allowed there
+	AllowForcePush bool `json:"allowForcePush,omitempty"`
+	// AllowNoVerify lets git commit and git push --no-verify through
+	AllowNoVerify bool `json:"allowNoVerify,omitempty"`
+	// Action is "block" (default) or "ask"
+	Action string `json:"action,omitempty"`
+}
+
+// MCPGuardConfig configures the mcp-guard hook. Entries name a server
+// ("github"), a server's tool ("github/create_issue") or a full MCP tool name
+// (mcp__github__create_issue); both parts may use glob wildcards.
+type MCPGuardConfig struct {
+	// AllowedServers allows every tool of these servers. When neither
+	// allowlist is set, every MCP tool not denied is allowed.
+	AllowedServers []string `json:"allowedServers,omitempty"`
+	// AllowedTools allows individual tools of other servers
+	AllowedTools []string `json:"allowedTools,omitempty"`
+	// DeniedTools are refused even when allowed above
+	DeniedTools []string `json:"deniedTools,omitempty"`
+	// Action is "block" (default) or "ask"
+	Action string `json:"action,omitempty"`
+}
+
+// DenyToolsConfig configures the deny-tools hook. Entries are tool names
+// (Bash, WebFetch), glob patterns (Notebook*) or mcp:<server>[/<tool>] for
+// MCP tools; names match without regard to case.
+type DenyToolsConfig struct {
+	// Allowed lists the only tools the agent may use. When empty, every tool
+	// not denied is allowed.
+	Allowed []string `json:"allowed,omitempty"`
+	// Denied tools are refused even when allowed above
+	Denied []string `json:"denied,omitempty"`
+	// Reason says why the policy exists; both messages include it
+	Reason string `json:"reason,omitempty"`
+	// Action is "block" (default) or "ask"
+	Action string `json:"action,omitempty"`
+}
+
+// PathGuardConfig configures the path-guard hook. Entries are globs
+// relative to the project root where ** spans directories (deploy/**); a
+// pattern without a slash matches the base name anywhere (.env, *.lock).
+type PathGuardConfig struct {
+	// Protected paths may not be edited. Defaults to .env files, lockfiles,
+	// deploy/** and .github/workflows/** when unset.
+	Protected []string `json:"protected,omitempty"`
+	// Allowed paths may be edited even when protected, e.g. .env.example
+	Allowed []string `json:"allowed,omitempty"`
+	// Action is "block" (default) or "ask"
+	Action string `json:"action,omitempty"`
+}
+
+// PRReadinessConfig configures the pr-readiness hook
+type PRReadinessConfig struct {
+	// Checks replaces the default checklist (build, test, todo, changelog)
+	Checks []PRReadinessCheck `json:"checks,omitempty"`
+	// Report is where the pass/fail report is written, relative to the
+	// project root; defaults to .claude/pr-readiness.md
+	Report string `json:"report,omitempty"`
+	// Block stops the agent once with the gaps so it can close them before
+	// finishing
+	Block bool `json:"block,omitempty"`
+}
+
+// PRReadinessCheck is one item of the readiness checklist. A check with Run
+// passes when the command exits 0. Without Run, Name selects a built-in:
+// build and test (detected from the project), todo (no TODO/FIXME added
+// this session) or changelog (the changelog changed this session).
+type PRReadinessCheck struct {
+	Name string `json:"name"`
+	Run  string `json:"run,omitempty"`
+	// File is the changelog the changelog check looks for; defaults to CHANGELOG.md
+	File string `json:"file,omitempty"`
+	// Timeout in seconds for Run; defaults to 300
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// CoverageGateConfig configures the coverage-gate hook
+type CoverageGateConfig struct {
+	// Threshold is the lowest statement coverage, in percent, the touched
+	// package may have; zero uses the default (80)
+	Threshold float64 `json:"threshold,omitempty"`
+	// Commands replace the coverage command for "go", "python" or
+	// "typescript"; {package} is replaced with the touched package's
+	// project-relative directory
+	Commands map[string]string `json:"commands,omitempty"`
+	// Action is "block" (default) or "warn"
+	Action string `json:"action,omitempty"`
+	// Timeout in seconds for the coverage command; defaults to 300
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// ChangelogConfig configures the changelog hook. Patterns are globs matched
+// against project-relative paths and base names; a pattern ending in "/"
+// matches everything under that directory.
+type ChangelogConfig struct {
+	// Paths are the changelog files and news fragment globs that count as an
+	// entry; defaults to CHANGELOG.md, CHANGES.md, NEWS.md, HISTORY.md,
+	// changelog.d/*, changes/*, newsfragments/* and .changeset/*.md
+	Paths []string `json:"paths,omitempty"`
+	// Sources limits which changed files need an entry; empty means any file
+	Sources []string `json:"sources,omitempty"`
+	// Ignore lists changed files that never need an entry; defaults to
+	// *.md, docs/ and .github/
+	Ignore []string `json:"ignore,omitempty"`
+	// Action is "warn" (default) or "block", which stops the agent once so
+	// it can add the entry
+	Action string `json:"action,omitempty"`
+}
+
+// FormatConfig configures the format hook. With Formatters set, they
+// replace the built-in Go, JS/TS, Python and YAML formatters.
+type FormatConfig struct {
+	// Formatters run in order; every one whose globs match the edited file
+	// runs until one with Stop set has run
+	Formatters []Formatter `json:"formatters,omitempty"`
+	// OnError is the default for formatters without their own: "block"
+	// (default) reports the failure to the agent, "allow" logs and moves on
+	OnError string `json:"onError,omitempty"`
+	// Timeout is the default per-formatter timeout in seconds; defaults to 30
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Formatter maps file globs to a formatter command. Globs match the
+// project-relative path or, without a "/", the base name; a glob ending
+// in "/" matches everything under that directory.
+type Formatter struct {
+	// Name identifies the formatter in messages; defaults to the command's
+	// first word
+	Name  string   `json:"name,omitempty"`
+	Globs []string `json:"globs"`
+	// Run is a bash command line. "{file}" is replaced with the quoted file
+	// path; without it the path is appended.
+	Run string `json:"run"`
+	// Timeout in seconds; defaults to the section's timeout
+	Timeout int `json:"timeout,omitempty"`
+	// OnError is "block" or "allow"; defaults to the section's onError
+	OnError string `json:"onError,omitempty"`
+	// Stop skips the formatters after this one for files it matched
+	Stop bool `json:"stop,omitempty"`
+}
+
+// AuditConfig configures the audit hook
+type AuditConfig struct {
+	// OTLP also exports each record to an OpenTelemetry collector
+	OTLP *AuditOTLPConfig `json:"otlp,omitempty"`
+}
+
+// AuditOTLPConfig exports audit records over OTLP/HTTP with JSON encoding.
+// Endpoint and header values expand ${ENV} variables.
+type AuditOTLPConfig struct {
+	// Endpoint is the collector's base URL, e.g. http://localhost:4318;
+	// /v1/logs and /v1/traces are appended. Defaults to
+	// OTEL_EXPORTER_OTLP_ENDPOINT.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Signals lists "logs" and "traces"; defaults to logs
+	Signals []string `json:"signals,omitempty"`
+	// Headers are added to every request, e.g. an API key
+	Headers map[string]string `json:"headers,omitempty"`
+	// ServiceName is the service.name resource attribute; defaults to
+	// blues-traveler
+	ServiceName string `json:"serviceName,omitempty"`
+	// ResourceAttributes are added to the resource, e.g. deployment.environment
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+	// Timeout in seconds for each request; defaults to 2
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// ContextConfig configures what the context hook tells the agent when a
+// session starts
+type ContextConfig struct {
+	// Include lists the sections to inject: branch, status, commits, todos
+	// and toolchains; defaults to all of them
+	Include []string `json:"include,omitempty"`
+	// Commits is how many recent commits are listed; defaults to 5
+	Commits int `json:"commits,omitempty"`
+	// Toolchains are commands whose first output line is reported, e.g.
+	// "go version"; defaults to those of the project's go.mod, Cargo.toml,
+	// package.json, pyproject.toml and Gemfile
+	Toolchains []string `json:"toolchains,omitempty"`
+	// Notes is free text added after the project metadata
+	Notes string `json:"notes,omitempty"`
+}
+
+// NotifyConfig configures the notify hook
+type NotifyConfig struct {
+	// Sinks are the destinations each notification is sent to
+	Sinks []NotifySink `json:"sinks,omitempty"`
+}
+
+// NotifySink is one notification destination. Title and Template are Go
+// text/template strings over the event: .Event, .Title, .Message, .Type,
+// .SessionID, .Project, .Cwd, .Time and .Count (notifications in a
+// digest). URL and header values expand ${ENV} variables, so webhook
+// secrets can stay out of the config file.
+type NotifySink struct {
+	// Type is "desktop", "slack" or "webhook"
+	Type string `json:"type"`
+	// Name identifies the sink in logs; defaults to its type
+	Name string `json:"name,omitempty"`
+	// Events limits the sink to these hook events (Notification, Stop);
+	// empty means both
+	Events []string `json:"events,omitempty"`
+	// URL is the Slack incoming webhook or HTTP endpoint
+	URL string `json:"url,omitempty"`
+	// Method is the webhook's HTTP method; defaults to POST
+	Method string `json:"method,omitempty"`
+	// Headers are added to webhook requests
+	Headers map[string]string `json:"headers,omitempty"`
+	// Title templates the desktop notification title
+	Title string `json:"title,omitempty"`
+	// Template renders the message body: the desktop text, the Slack text,
+	// or the whole webhook request body (the event as JSON when unset)
+	Template string `json:"template,omitempty"`
+	// Timeout in seconds for each request or command; defaults to 5
+	Timeout int `json:"timeout,omitempty"`
+	// DigestWindow, in seconds, batches notifications: one sent less than
+	// this long after the previous is held, and the held ones go out together
+	// as a digest with the next notification after the window, or on Stop.
+	// 0 sends each notification right away.
+	DigestWindow int `json:"digestWindow,omitempty"`
+}
+
 // GetLogConfigPath returns the path to our log configuration file
 func GetLogConfigPath(global bool) (string, error) {
-	if global {
-		// Global config: ~/.claude/hooks/blues-traveler-config.json
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %v", err)
-		}
-		return constants.GetConfigPath(homeDir), nil
-	}
-	// Project config: ./.claude/hooks/blues-traveler-config.json
-	cwd, err := os.Getwd()
+	// Global config: ~/.claude/hooks/blues-traveler-config.json; project:
+	// ./.claude/hooks/blues-traveler-config.json
+	dir, err := ClaudeDirFor(global)
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %v", err)
+		return "", err
 	}
-	return constants.GetConfigPath(cwd), nil
+	return filepath.Join(dir, constants.HooksSubDir, constants.ConfigFileName), nil
 }
 
 // LoadLogConfig loads the log configuration, returning defaults if file doesn't exist
@@ -87,8 +447,36 @@ func LoadLogConfig(configPath string) (*LogConfig, error) {
 	}
 	// Remove known
 	delete(raw, "logRotation")
+	delete(raw, "logging")
 	delete(raw, "customHooks")
 	delete(raw, "blockedUrls")
+	delete(raw, "blockedUrlsRemote")
+	delete(raw, "codeOwners")
+	delete(raw, "largeFiles")
+	delete(raw, "deleteGuard")
+	delete(raw, "secrets")
+	delete(raw, "lockfileChurn")
+	delete(raw, "gitGuard")
+	delete(raw, "mcpGuard")
+	delete(raw, "denyTools")
+	delete(raw, "pathGuard")
+	delete(raw, "prReadiness")
+	delete(raw, "coverageGate")
+	delete(raw, "notify")
+	delete(raw, "changelog")
+	delete(raw, "format")
+	delete(raw, "audit")
+	delete(raw, "context")
+	delete(raw, "mergePolicy")
+	delete(raw, "latencyThreshold")
+	delete(raw, "experiments")
+	delete(raw, "metrics")
+	delete(raw, "terseOutput")
+	delete(raw, "redaction")
+	delete(raw, "retention")
+	delete(raw, "execPath")
+	delete(raw, "extendsPath")
+	delete(raw, "includes")
 	config.Other = raw
 
 	return config, nil
@@ -108,12 +496,96 @@ func SaveLogConfig(configPath string, config *LogConfig) error {
 		out[k] = v
 	}
 	out["logRotation"] = config.LogRotation
+	if config.Logging != nil {
+		out["logging"] = config.Logging
+	}
 	if len(config.CustomHooks) > 0 {
 		out["customHooks"] = config.CustomHooks
 	}
 	if len(config.BlockedURLs) > 0 {
 		out["blockedUrls"] = config.BlockedURLs
 	}
+	if config.BlockedURLsRemote != nil {
+		out["blockedUrlsRemote"] = config.BlockedURLsRemote
+	}
+	if config.CodeOwners != nil {
+		out["codeOwners"] = config.CodeOwners
+	}
+	if config.LargeFiles != nil {
+		out["largeFiles"] = config.LargeFiles
+	}
+	if config.DeleteGuard != nil {
+		out["deleteGuard"] = config.DeleteGuard
+	}
+	if config.Secrets != nil {
+		out["secrets"] = config.Secrets
+	}
+	if config.LockfileChurn != nil {
+		out["lockfileChurn"] = config.LockfileChurn
+	}
+	if config.GitGuard != nil {
+		out["gitGuard"] = config.GitGuard
+	}
+	if config.MCPGuard != nil {
+		out["mcpGuard"] = config.MCPGuard
+	}
+	if config.DenyTools != nil {
+		out["denyTools"] = config.DenyTools
+	}
+	if config.PathGuard != nil {
+		out["pathGuard"] = config.PathGuard
+	}
+	if config.PRReadiness != nil {
+		out["prReadiness"] = config.PRReadiness
+	}
+	if config.CoverageGate != nil {
+		out["coverageGate"] = config.CoverageGate
+	}
+	if config.Notify != nil {
+		out["notify"] = config.Notify
+	}
+	if config.Changelog != nil {
+		out["changelog"] = config.Changelog
+	}
+	if config.Format != nil {
+		out["format"] = config.Format
+	}
+	if config.Audit != nil {
+		out["audit"] = config.Audit
+	}
+	if config.Context != nil {
+		out["context"] = config.Context
+	}
+	if config.MergePolicy != "" {
+		out["mergePolicy"] = config.MergePolicy
+	}
+	if config.LatencyThreshold != "" {
+		out["latencyThreshold"] = config.LatencyThreshold
+	}
+	if len(config.Experiments) > 0 {
+		out["experiments"] = config.Experiments
+	}
+	if config.Metrics != nil {
+		out["metrics"] = *config.Metrics
+	}
+	if config.TerseOutput != nil {
+		out["terseOutput"] = *config.TerseOutput
+	}
+	if config.Redaction != nil {
+		out["redaction"] = config.Redaction
+	}
+	if len(config.Retention) > 0 {
+		out["retention"] = config.Retention
+	}
+	if config.ExecPath != "" {
+		out["execPath"] = config.ExecPath
+	}
+	if config.ExtendsPath != "" {
+		out["extendsPath"] = config.ExtendsPath
+	}
+	if len(config.Includes) > 0 {
+		out["includes"] = config.Includes
+	}
 
 	data, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
@@ -142,37 +614,45 @@ func GetLogRotationConfigFromFile(global bool) LogRotationConfig {
 	return config.LogRotation
 }
 
-// SetupLogRotation configures log rotation for a given log file path
-func SetupLogRotation(logPath string, config LogRotationConfig) *lumberjack.Logger {
-	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(logPath), 0o750); err != nil {
-		log.Printf("Failed to create log directory: %v", err)
-		return nil
+// ResolveLogRotationConfig returns the project's log rotation settings, or
+// the global ones when the project leaves them unset
+func ResolveLogRotationConfig() LogRotationConfig {
+	logConfig := GetLogRotationConfigFromFile(false)
+	// Treat an entirely zeroed config as "not configured"; otherwise respect zeros intentionally set
+	if logConfig.MaxAge == 0 && logConfig.MaxSize == 0 && logConfig.MaxBackups == 0 {
+		logConfig = GetLogRotationConfigFromFile(true)
 	}
+	return logConfig
+}
 
-	logger := &lumberjack.Logger{
-		Filename:   logPath,
-		MaxSize:    config.MaxSize,
-		MaxBackups: config.MaxBackups,
-		MaxAge:     config.MaxAge,
-		Compress:   config.Compress,
-		LocalTime:  true, // Use local time for timestamps
+// SetupLogRotation configures log rotation for a given log file path. The
+// returned writer must be closed so pending compression can finish.
+func SetupLogRotation(logPath string, config LogRotationConfig) *StreamingLogWriter {
+	writer, err := NewStreamingLogWriter(logPath, config, DefaultRotationCheckInterval)
+	if err != nil {
+		log.Printf("Failed to set up log rotation: %v", err)
+		return nil
 	}
-
-	return logger
+	return writer
 }
 
-// CleanupOldLogs manually removes log files older than the specified number of days
-// This provides additional cleanup beyond lumberjack's built-in MaxAge
-func CleanupOldLogs(logDir string, maxAgeDays int) error {
+// CleanupOldLogs removes log files older than the specified number of days.
+// This provides age-based cleanup on top of the size-based rotation. It
+// returns how many files were removed and the bytes freed.
+func CleanupOldLogs(logDir string, maxAgeDays int) (int, int64, error) {
 	if maxAgeDays <= 0 {
-		return nil // No cleanup if maxAge is 0 or negative
+		return 0, 0, nil // No cleanup if maxAge is 0 or negative
 	}
 
 	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+	removed := 0
+	var freed int64
 
 	err := filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
 			return err
 		}
 
@@ -187,7 +667,8 @@ func CleanupOldLogs(logDir string, maxAgeDays int) error {
 				if err := os.Remove(path); err != nil {
 					log.Printf("Failed to remove old log file %s: %v", path, err)
 				} else {
-					log.Printf("Removed old log file: %s", path)
+					removed++
+					freed += info.Size()
 				}
 			}
 		}
@@ -195,12 +676,18 @@ func CleanupOldLogs(logDir string, maxAgeDays int) error {
 		return nil
 	})
 
-	return err
+	return removed, freed, err
 }
 
-// GetLogPath returns the standard log path for a given plugin key
+// GetLogPath returns the standard log path for a given plugin key, relative
+// to the project unless BT_CLAUDE_DIR or BT_CONFIG_DIR relocates the .claude
+// directory
 func GetLogPath(pluginKey string) string {
-	return filepath.Join(".claude", "hooks", fmt.Sprintf("%s.log", pluginKey))
+	name := fmt.Sprintf("%s.log", pluginKey)
+	if dir := projectClaudeDirOverride(); dir != "" {
+		return filepath.Join(dir, constants.HooksSubDir, name)
+	}
+	return filepath.Join(constants.ClaudeDir, constants.HooksSubDir, name)
 }
 
 // Logging format constants
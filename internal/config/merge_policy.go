@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergePolicy controls how AddHookToSettings treats a blues-traveler hook
+// that is already installed under the same matcher
+type MergePolicy string
+
+const (
+	// MergePolicyByHookType replaces an existing hook of the same type, so
+	// reinstalling with new flags updates the command in place (default)
+	MergePolicyByHookType MergePolicy = "by-hook-type"
+	// MergePolicyExact only skips identical commands; the same hook with
+	// different flags is installed alongside the existing one
+	MergePolicyExact MergePolicy = "exact"
+	// MergePolicyNeverReplace keeps an existing hook of the same type and
+	// leaves settings unchanged
+	MergePolicyNeverReplace MergePolicy = "never-replace"
+)
+
+// ValidMergePolicies lists the accepted merge policy names
+func ValidMergePolicies() []string {
+	return []string{string(MergePolicyByHookType), string(MergePolicyExact), string(MergePolicyNeverReplace)}
+}
+
+// ParseMergePolicy validates a policy name; empty selects the default
+func ParseMergePolicy(name string) (MergePolicy, error) {
+	switch MergePolicy(strings.TrimSpace(name)) {
+	case "", MergePolicyByHookType:
+		return MergePolicyByHookType, nil
+	case MergePolicyExact:
+		return MergePolicyExact, nil
+	case MergePolicyNeverReplace:
+		return MergePolicyNeverReplace, nil
+	}
+	return "", fmt.Errorf("invalid merge policy '%s'. Valid: %s", name, strings.Join(ValidMergePolicies(), ", "))
+}
+
+// ConfiguredMergePolicy returns the mergePolicy from the blues-traveler
+// config for the given scope, falling back to the default when unset
+func ConfiguredMergePolicy(global bool) (MergePolicy, error) {
+	path, err := GetLogConfigPath(global)
+	if err != nil {
+		return MergePolicyByHookType, err
+	}
+	cfg, err := LoadLogConfig(path)
+	if err != nil {
+		return MergePolicyByHookType, err
+	}
+	policy, err := ParseMergePolicy(cfg.MergePolicy)
+	if err != nil {
+		return MergePolicyByHookType, fmt.Errorf("%s: %w", path, err)
+	}
+	return policy, nil
+}
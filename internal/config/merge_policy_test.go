@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+func TestAddHookToSettingsWithPolicy(t *testing.T) {
+	const (
+		plain  = "/usr/local/bin/blues-traveler hooks run security"
+		logged = "/usr/local/bin/blues-traveler hooks run security --log"
+	)
+
+	tests := []struct {
+		policy    MergePolicy
+		wantCmds  []string
+		wantDup   bool
+		wantNoted string
+	}{
+		{MergePolicyByHookType, []string{logged}, true, "Replaced existing"},
+		{MergePolicyExact, []string{plain, logged}, false, ""},
+		{MergePolicyNeverReplace, []string{plain}, true, "never-replace"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			s := &Settings{}
+			AddHookToSettingsWithPolicy(s, "PreToolUse", "*", plain, nil, tt.policy)
+			result := AddHookToSettingsWithPolicy(s, "PreToolUse", "*", logged, nil, tt.policy)
+
+			if result.WasDuplicate != tt.wantDup {
+				t.Errorf("WasDuplicate = %v, want %v (%s)", result.WasDuplicate, tt.wantDup, result.DuplicateInfo)
+			}
+			if tt.wantNoted != "" && !strings.Contains(result.DuplicateInfo, tt.wantNoted) {
+				t.Errorf("DuplicateInfo = %q, want it to mention %q", result.DuplicateInfo, tt.wantNoted)
+			}
+			if len(s.Hooks.PreToolUse) != 1 {
+				t.Fatalf("expected one matcher, got %d", len(s.Hooks.PreToolUse))
+			}
+			var got []string
+			for _, h := range s.Hooks.PreToolUse[0].Hooks {
+				got = append(got, h.Command)
+			}
+			if len(got) != len(tt.wantCmds) {
+				t.Fatalf("commands = %v, want %v", got, tt.wantCmds)
+			}
+			for i := range got {
+				if got[i] != tt.wantCmds[i] {
+					t.Errorf("commands = %v, want %v", got, tt.wantCmds)
+				}
+			}
+
+			// Identical commands are skipped under every policy
+			again := AddHookToSettingsWithPolicy(s, "PreToolUse", "*", tt.wantCmds[0], nil, tt.policy)
+			if !again.WasDuplicate || len(s.Hooks.PreToolUse[0].Hooks) != len(tt.wantCmds) {
+				t.Errorf("identical command should be reported as duplicate without changes")
+			}
+		})
+	}
+}
+
+func TestParseMergePolicy(t *testing.T) {
+	if p, err := ParseMergePolicy(""); err != nil || p != MergePolicyByHookType {
+		t.Errorf("empty policy = (%q, %v), want default", p, err)
+	}
+	if p, err := ParseMergePolicy("exact"); err != nil || p != MergePolicyExact {
+		t.Errorf("exact = (%q, %v)", p, err)
+	}
+	if _, err := ParseMergePolicy("sometimes"); err == nil {
+		t.Error("unknown policy should be rejected")
+	}
+}
+
+func TestConfiguredMergePolicy(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+
+	if p, err := ConfiguredMergePolicy(false); err != nil || p != MergePolicyByHookType {
+		t.Fatalf("missing config = (%q, %v), want default", p, err)
+	}
+
+	path := constants.GetConfigPath(project)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLogConfig(path, &LogConfig{LogRotation: DefaultLogRotationConfig(), MergePolicy: "never-replace"}); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := ConfiguredMergePolicy(false); err != nil || p != MergePolicyNeverReplace {
+		t.Errorf("configured policy = (%q, %v), want never-replace", p, err)
+	}
+}
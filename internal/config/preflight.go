@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// minPreflightFreeBytes is the least free space we accept before rewriting a
+// config file, even when the file is tiny
+const minPreflightFreeBytes = 1 << 20
+
+// PreflightTarget is a file a destructive operation is about to write
+type PreflightTarget struct {
+	// Path is the file that will be created or replaced
+	Path string
+	// AllowedRoot, when set, is where Path may resolve to if it is a symlink.
+	// A link pointing elsewhere is treated as unexpected.
+	AllowedRoot string
+}
+
+// PreflightIssue is one reason a target is unsafe to write
+type PreflightIssue struct {
+	Path       string
+	Problem    string
+	Suggestion string
+}
+
+// PreflightError aggregates every issue found so users can fix them in one pass
+type PreflightError struct {
+	Operation string
+	Issues    []PreflightIssue
+}
+
+func (e *PreflightError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pre-flight checks failed for %s; no changes were made", e.Operation)
+	for _, issue := range e.Issues {
+		fmt.Fprintf(&b, "\n  ✗ %s: %s", issue.Path, issue.Problem)
+		if issue.Suggestion != "" {
+			fmt.Fprintf(&b, "\n    Suggestion: %s", issue.Suggestion)
+		}
+	}
+	return b.String()
+}
+
+// Preflight verifies that every target can be written safely: the file is not
+// a symlink to an unexpected place, its directory is writable and not on a
+// read-only filesystem, and there is enough free disk space. It returns a
+// *PreflightError listing all problems, or nil when it is safe to proceed.
+func Preflight(operation string, targets ...PreflightTarget) error {
+	var issues []PreflightIssue
+	for _, t := range targets {
+		issues = append(issues, checkPreflightTarget(t)...)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return &PreflightError{Operation: operation, Issues: issues}
+}
+
+func checkPreflightTarget(t PreflightTarget) []PreflightIssue {
+	path := t.Path
+	var issues []PreflightIssue
+
+	info, err := os.Lstat(path)
+	exists := err == nil
+	if exists && info.Mode()&os.ModeSymlink != 0 {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return []PreflightIssue{{
+				Path:       path,
+				Problem:    "symlink target does not exist",
+				Suggestion: "Remove the dangling link or restore the file it points to",
+			}}
+		}
+		if t.AllowedRoot != "" && !pathWithin(resolved, t.AllowedRoot) {
+			issues = append(issues, PreflightIssue{
+				Path:       path,
+				Problem:    fmt.Sprintf("symlink resolves outside %s (to %s)", t.AllowedRoot, resolved),
+				Suggestion: "Edit the linked file directly, or replace the link with a regular file if this is unintended",
+			})
+		}
+		path = resolved
+		info, _ = os.Stat(path)
+	}
+
+	if exists && info != nil && info.IsDir() {
+		return append(issues, PreflightIssue{Path: path, Problem: "expected a file but found a directory"})
+	}
+
+	if exists {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0) // #nosec G304 - probing a known config path
+		if err != nil {
+			issues = append(issues, writeIssue(path, err))
+		} else {
+			_ = f.Close()
+		}
+	}
+
+	dir := nearestExistingDir(filepath.Dir(path))
+	if err := probeWritableDir(dir); err != nil {
+		issues = append(issues, writeIssue(dir, err))
+		return issues
+	}
+
+	need := uint64(minPreflightFreeBytes)
+	if info != nil && uint64(info.Size())*2 > need { // #nosec G115 - file sizes are non-negative
+		need = uint64(info.Size()) * 2 // #nosec G115 - file sizes are non-negative
+	}
+	if free, ok := freeDiskBytes(dir); ok && free < need {
+		issues = append(issues, PreflightIssue{
+			Path:       dir,
+			Problem:    fmt.Sprintf("only %d KiB free, need at least %d KiB", free/1024, need/1024),
+			Suggestion: "Free up disk space and retry",
+		})
+	}
+	return issues
+}
+
+// writeIssue turns a failed write probe into a user-facing issue
+func writeIssue(path string, err error) PreflightIssue {
+	switch {
+	case errors.Is(err, syscall.EROFS):
+		return PreflightIssue{
+			Path:       path,
+			Problem:    "located on a read-only filesystem",
+			Suggestion: "Remount the filesystem read-write or run from a writable checkout",
+		}
+	case errors.Is(err, os.ErrPermission):
+		return PreflightIssue{
+			Path:       path,
+			Problem:    "not writable (permission denied)",
+			Suggestion: fmt.Sprintf("Check ownership and permissions (e.g. 'ls -ld %s')", path),
+		}
+	default:
+		return PreflightIssue{Path: path, Problem: fmt.Sprintf("not writable: %v", err)}
+	}
+}
+
+// probeWritableDir creates and removes a temp file to prove dir accepts writes.
+// This catches read-only mounts that permission bits alone would not reveal.
+func probeWritableDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".bt-preflight-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+// nearestExistingDir walks up from dir until it finds a directory that
+// exists; that is where MkdirAll will need write access
+func nearestExistingDir(dir string) string {
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// pathWithin reports whether path is root or lies beneath it
+func pathWithin(path, root string) bool {
+	if r, err := filepath.EvalSymlinks(root); err == nil {
+		root = r
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// SettingsPreflightTarget describes the Claude settings file for a scope.
+// Project settings are expected to resolve inside the project; global
+// settings anywhere under the home directory (dotfile managers often link
+// ~/.claude/settings.json into a dotfiles repo).
+func SettingsPreflightTarget(global bool) (PreflightTarget, error) {
+	path, err := GetSettingsPath(global)
+	if err != nil {
+		return PreflightTarget{}, err
+	}
+	var root string
+	if global {
+		root, err = os.UserHomeDir()
+	} else {
+		root, err = os.Getwd()
+	}
+	if err != nil {
+		return PreflightTarget{}, err
+	}
+	return PreflightTarget{Path: path, AllowedRoot: root}, nil
+}
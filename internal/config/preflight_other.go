@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package config
+
+// freeDiskBytes is not implemented on this platform; the disk space check is skipped
+func freeDiskBytes(string) (uint64, bool) {
+	return 0, false
+}
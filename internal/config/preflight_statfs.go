@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package config
+
+import "syscall"
+
+// freeDiskBytes reports the space available to unprivileged users at dir
+func freeDiskBytes(dir string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), true // #nosec G115 - block size is positive
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPreflight_WritableTargets(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(existing, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Preflight("sync",
+		PreflightTarget{Path: existing, AllowedRoot: dir},
+		PreflightTarget{Path: filepath.Join(dir, "not", "yet", "created.json")},
+	)
+	if err != nil {
+		t.Fatalf("expected writable targets to pass, got %v", err)
+	}
+}
+
+func TestPreflight_Symlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	project := t.TempDir()
+	elsewhere := t.TempDir()
+	outside := filepath.Join(elsewhere, "settings.json")
+	if err := os.WriteFile(outside, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(project, "settings.json")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+	err := Preflight("uninstall all", PreflightTarget{Path: link, AllowedRoot: project})
+	var pe *PreflightError
+	if !errors.As(err, &pe) || len(pe.Issues) != 1 || !strings.Contains(pe.Issues[0].Problem, "symlink resolves outside") {
+		t.Fatalf("expected unexpected-symlink issue, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "no changes were made") || !strings.Contains(err.Error(), "Suggestion:") {
+		t.Errorf("error should explain nothing changed and how to fix it: %v", err)
+	}
+
+	// Links that stay inside the allowed root are fine
+	if err := Preflight("sync", PreflightTarget{Path: link, AllowedRoot: elsewhere}); err != nil {
+		t.Errorf("link inside allowed root should pass: %v", err)
+	}
+
+	dangling := filepath.Join(project, "dangling.json")
+	if err := os.Symlink(filepath.Join(elsewhere, "missing.json"), dangling); err != nil {
+		t.Fatal(err)
+	}
+	if err := Preflight("sync", PreflightTarget{Path: dangling}); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected dangling symlink issue, got %v", err)
+	}
+}
+
+func TestPreflight_UnwritableAndDirectoryTargets(t *testing.T) {
+	dir := t.TempDir()
+	if err := Preflight("migrate", PreflightTarget{Path: dir}); err == nil || !strings.Contains(err.Error(), "found a directory") {
+		t.Errorf("expected directory issue, got %v", err)
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced for this user")
+	}
+	locked := filepath.Join(dir, "locked")
+	if err := os.Mkdir(locked, 0o500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(locked, 0o700) })
+
+	err := Preflight("sync", PreflightTarget{Path: filepath.Join(locked, "settings.json")})
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected permission issue, got %v", err)
+	}
+}
+
+func TestPathWithin(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{root, true},
+		{filepath.Join(root, "a", "b"), true},
+		{filepath.Join(root, "..", "other"), false},
+		{filepath.Join(root, "..x"), true},
+	}
+	for _, tt := range tests {
+		if got := pathWithin(tt.path, root); got != tt.want {
+			t.Errorf("pathWithin(%q, %q) = %v, want %v", tt.path, root, got, tt.want)
+		}
+	}
+}
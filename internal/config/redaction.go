@@ -0,0 +1,45 @@
+package config
+
+// RedactionRules name what to scrub from log output
+type RedactionRules struct {
+	// Builtins are named rule sets, e.g. "aws-keys" or "emails"
+	Builtins []string `json:"builtins,omitempty"`
+	// Patterns are regular expressions. A match is replaced, except for a
+	// pattern's first capture group, which is kept as context.
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// RedactionConfig scrubs matches from every log sink before they are
+// written: hook logs, the debug log, audit records and their exports
+type RedactionConfig struct {
+	// Builtins and Patterns apply to every hook
+	Builtins []string `json:"builtins,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	// Hooks adds rules for single hooks, keyed by hook key (e.g. "debug"
+	// or "config:lint:eslint")
+	Hooks map[string]RedactionRules `json:"hooks,omitempty"`
+}
+
+// GetRedactionRules returns every rule that applies to hookKey. Rules from
+// the global and project configs add up, so a project can't switch off
+// redaction the machine's config asks for.
+func GetRedactionRules(hookKey string) RedactionRules {
+	var rules RedactionRules
+	for _, global := range []bool{true, false} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.Redaction == nil {
+			continue
+		}
+		rules.Builtins = append(rules.Builtins, cfg.Redaction.Builtins...)
+		rules.Patterns = append(rules.Patterns, cfg.Redaction.Patterns...)
+		if own, ok := cfg.Redaction.Hooks[hookKey]; ok {
+			rules.Builtins = append(rules.Builtins, own.Builtins...)
+			rules.Patterns = append(rules.Patterns, own.Patterns...)
+		}
+	}
+	return rules
+}
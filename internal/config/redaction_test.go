@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetRedactionRules_AddsUpScopesAndHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	t.Chdir(project)
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(home, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"redaction":{"builtins":["secrets"]}}`)
+	write(filepath.Join(project, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"redaction":{"builtins":["emails"],"hooks":{"debug":{"patterns":["id=\\d+"]}}}}`)
+
+	if got := GetRedactionRules("debug"); strings.Join(got.Builtins, ",") != "secrets,emails" || strings.Join(got.Patterns, ",") != `id=\d+` {
+		t.Errorf("debug rules = %+v", got)
+	}
+	if got := GetRedactionRules("audit"); len(got.Patterns) != 0 || len(got.Builtins) != 2 {
+		t.Errorf("audit rules = %+v", got)
+	}
+
+	cfg, err := LoadLogConfig(filepath.Join(project, ".claude", "hooks", "blues-traveler-config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, leaked := cfg.Other["redaction"]; leaked || cfg.Redaction == nil {
+		t.Errorf("redaction should load into its own field, got %+v", cfg)
+	}
+}
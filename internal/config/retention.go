@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Retention categories of local data hooks record in a project
+const (
+	// RetentionPayloads is the audit log of tool calls and their inputs (.claude/audit)
+	RetentionPayloads = "payloads"
+	// RetentionRunHistory is what hooks keep between runs: finding
+	// baselines, compatibility counts and experiment tallies (.claude/cache)
+	RetentionRunHistory = "runHistory"
+	// RetentionArtifacts is files the delete-guard hook kept (.claude/trash)
+	RetentionArtifacts = "artifacts"
+	// RetentionMetrics is the hook run log behind 'hooks stats' (.claude/hooks/metrics)
+	RetentionMetrics = "metrics"
+	// RetentionState is per-session key-value state (.claude/state)
+	RetentionState = "state"
+)
+
+// RetentionCategories lists every category, in the order housekeeping prunes them
+var RetentionCategories = []string{RetentionPayloads, RetentionRunHistory, RetentionArtifacts, RetentionMetrics, RetentionState}
+
+// defaultRetention applies to categories the config leaves unset
+var defaultRetention = map[string]RetentionPolicy{
+	RetentionPayloads:   {MaxAgeDays: 30, MaxSizeMB: 100},
+	RetentionRunHistory: {MaxAgeDays: 30, MaxSizeMB: 50},
+	RetentionArtifacts:  {MaxAgeDays: 14, MaxSizeMB: 500},
+	RetentionMetrics:    {MaxAgeDays: 90, MaxSizeMB: 50},
+	RetentionState:      {MaxAgeDays: 7, MaxSizeMB: 50},
+}
+
+// RetentionPolicy bounds one category of local data. Zero keeps the
+// default; a negative value turns that limit off.
+type RetentionPolicy struct {
+	// MaxAgeDays removes entries not written to for this many days
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+	// MaxSizeMB removes the oldest entries until the category fits
+	MaxSizeMB int `json:"maxSizeMB,omitempty"`
+}
+
+// DefaultRetentionPolicy returns the built-in policy for category
+func DefaultRetentionPolicy(category string) RetentionPolicy {
+	return defaultRetention[category]
+}
+
+// ValidateRetention rejects categories that don't exist, so a typo doesn't
+// silently leave data unbounded
+func ValidateRetention(retention map[string]RetentionPolicy) error {
+	var unknown []string
+	for category := range retention {
+		if _, ok := defaultRetention[category]; !ok {
+			unknown = append(unknown, category)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown retention categories: %s\n  Suggestion: Use %s", strings.Join(unknown, ", "), strings.Join(RetentionCategories, ", "))
+}
+
+// GetRetentionPolicies resolves every category's policy. Each limit comes
+// from the project config, else the global config, else the default.
+func GetRetentionPolicies() map[string]RetentionPolicy {
+	policies := make(map[string]RetentionPolicy, len(defaultRetention))
+	for category, p := range defaultRetention {
+		policies[category] = p
+	}
+	// Global first so project values override them
+	for _, global := range []bool{true, false} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil {
+			continue
+		}
+		for category, p := range cfg.Retention {
+			resolved, ok := policies[category]
+			if !ok {
+				continue
+			}
+			if p.MaxAgeDays != 0 {
+				resolved.MaxAgeDays = p.MaxAgeDays
+			}
+			if p.MaxSizeMB != 0 {
+				resolved.MaxSizeMB = p.MaxSizeMB
+			}
+			policies[category] = resolved
+		}
+	}
+	return policies
+}
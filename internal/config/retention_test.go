@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetRetentionPolicies_ProjectOverridesGlobal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	t.Chdir(project)
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(home, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"retention":{"metrics":{"maxAgeDays":10,"maxSizeMB":5}}}`)
+	write(filepath.Join(project, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"retention":{"metrics":{"maxAgeDays":3},"state":{"maxSizeMB":-1}}}`)
+
+	policies := GetRetentionPolicies()
+	if got := policies[RetentionMetrics]; got.MaxAgeDays != 3 || got.MaxSizeMB != 5 {
+		t.Errorf("metrics: got %+v", got)
+	}
+	if got := policies[RetentionState]; got.MaxSizeMB != -1 || got.MaxAgeDays != DefaultRetentionPolicy(RetentionState).MaxAgeDays {
+		t.Errorf("state: got %+v", got)
+	}
+	if got := policies[RetentionArtifacts]; got != DefaultRetentionPolicy(RetentionArtifacts) {
+		t.Errorf("artifacts should keep the default, got %+v", got)
+	}
+}
+
+func TestValidateRetention(t *testing.T) {
+	if err := ValidateRetention(map[string]RetentionPolicy{RetentionPayloads: {MaxAgeDays: 1}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := ValidateRetention(map[string]RetentionPolicy{"payload": {MaxAgeDays: 1}})
+	if err == nil || !strings.Contains(err.Error(), "payload") {
+		t.Errorf("expected unknown category error, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// sampleManifestFile records the sample files blues-traveler created in
+	// a scope; like the built-in manifest it is JSON so it is never loaded
+	// as a group file
+	sampleManifestFile    = "bt-samples.json"
+	sampleManifestVersion = 1
+)
+
+// SampleArtifact is a sample file written for a hook or config, such as
+// the blocked-urls.txt fetch-blocker's install creates
+type SampleArtifact struct {
+	Path string `json:"path"`
+	// Owner is the hook type or "config:<group>" the sample was made for
+	Owner string `json:"owner"`
+	// SHA256 is the content as written, to tell untouched samples from
+	// files the user has since edited
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SampleManifest records the sample artifacts created in one scope
+type SampleManifest struct {
+	Version   int              `json:"version"`
+	Artifacts []SampleArtifact `json:"artifacts"`
+}
+
+// SampleCleanup reports what RemoveSampleArtifacts did
+type SampleCleanup struct {
+	Removed []string
+	// Edited are samples kept because they changed since they were written
+	Edited []string
+}
+
+// SampleManifestPath returns where sample artifacts are recorded for the scope
+func SampleManifestPath(global bool) (string, error) {
+	dir, err := GroupHooksDir(global)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sampleManifestFile), nil
+}
+
+// LoadSampleManifest reads the manifest at path; a missing file is an
+// empty manifest
+func LoadSampleManifest(path string) (*SampleManifest, error) {
+	m := &SampleManifest{}
+	data, err := os.ReadFile(path) // #nosec G304 - fixed file under .claude/hooks
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w\n  Suggestion: Delete the file; samples created later are tracked again", path, err)
+	}
+	return m, nil
+}
+
+// SaveSampleManifest writes m to path, sorted by owner and path. An empty
+// manifest removes the file.
+func SaveSampleManifest(path string, m *SampleManifest) error {
+	if len(m.Artifacts) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	m.Version = sampleManifestVersion
+	sort.SliceStable(m.Artifacts, func(i, j int) bool {
+		if m.Artifacts[i].Owner != m.Artifacts[j].Owner {
+			return m.Artifacts[i].Owner < m.Artifacts[j].Owner
+		}
+		return m.Artifacts[i].Path < m.Artifacts[j].Path
+	})
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RecordSampleArtifact notes that path was written with content for owner
+func RecordSampleArtifact(global bool, owner, path string, content []byte) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	return updateSampleManifest(global, func(m *SampleManifest) {
+		artifact := SampleArtifact{Path: abs, Owner: owner, SHA256: sampleDigest(content), CreatedAt: time.Now().UTC()}
+		for i, existing := range m.Artifacts {
+			if existing.Path == abs {
+				m.Artifacts[i] = artifact
+				return
+			}
+		}
+		m.Artifacts = append(m.Artifacts, artifact)
+	})
+}
+
+// RemoveSampleArtifacts deletes the samples recorded for owner (every
+// owner when empty). Samples edited since they were written are kept
+// unless force is set. Files already gone are forgotten.
+func RemoveSampleArtifacts(global bool, owner string, force bool) (SampleCleanup, error) {
+	var cleanup SampleCleanup
+	var removeErr error
+	err := updateSampleManifest(global, func(m *SampleManifest) {
+		kept := m.Artifacts[:0]
+		for _, a := range m.Artifacts {
+			if owner != "" && a.Owner != owner {
+				kept = append(kept, a)
+				continue
+			}
+			data, err := os.ReadFile(a.Path) // #nosec G304 - path recorded when the sample was written
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err == nil && !force && sampleDigest(data) != a.SHA256 {
+				cleanup.Edited = append(cleanup.Edited, a.Path)
+				kept = append(kept, a)
+				continue
+			}
+			if err := os.Remove(a.Path); err != nil {
+				removeErr = fmt.Errorf("failed to remove %s: %w", a.Path, err)
+				kept = append(kept, a)
+				continue
+			}
+			cleanup.Removed = append(cleanup.Removed, a.Path)
+		}
+		m.Artifacts = kept
+	})
+	if err != nil {
+		return cleanup, err
+	}
+	return cleanup, removeErr
+}
+
+func updateSampleManifest(global bool, update func(*SampleManifest)) error {
+	path, err := SampleManifestPath(global)
+	if err != nil {
+		return err
+	}
+	release, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	m, err := LoadSampleManifest(path)
+	if err != nil {
+		return err
+	}
+	update(m)
+	return SaveSampleManifest(path, m)
+}
+
+func sampleDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveSampleArtifacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	write := func(name, content, owner string) string {
+		t.Helper()
+		path := filepath.Join(dir, ".claude", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := RecordSampleArtifact(false, owner, path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	blocked := write("blocked-urls.txt", "# sample\n", "fetch-blocker")
+	group := write("hooks/lint.yml", "lint: {}\n", "config:lint")
+	edited := write("hooks/docs.yml", "docs: {}\n", "config:docs")
+	if err := os.WriteFile(edited, []byte("docs:\n  Stop: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cleanup, err := RemoveSampleArtifacts(false, "fetch-blocker", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleanup.Removed) != 1 || cleanup.Removed[0] != blocked {
+		t.Errorf("owner filter: removed %v, want only %s", cleanup.Removed, blocked)
+	}
+	if _, err := os.Stat(group); err != nil {
+		t.Errorf("other owners' samples must stay: %v", err)
+	}
+
+	cleanup, err = RemoveSampleArtifacts(false, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleanup.Removed) != 1 || len(cleanup.Edited) != 1 || cleanup.Edited[0] != edited {
+		t.Errorf("expected lint removed and docs kept as edited, got %+v", cleanup)
+	}
+	if _, err := os.Stat(edited); err != nil {
+		t.Errorf("edited sample must be kept: %v", err)
+	}
+
+	if cleanup, err = RemoveSampleArtifacts(false, "", true); err != nil || len(cleanup.Removed) != 1 {
+		t.Fatalf("force: %+v, %v", cleanup, err)
+	}
+	path, err := SampleManifestPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("manifest should be removed once nothing is tracked, stat err %v", err)
+	}
+}
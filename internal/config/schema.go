@@ -0,0 +1,177 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Hook command timeouts accepted in settings.json, in seconds. Longer
+// timeouts let a stuck hook stall the agent for too long.
+const (
+	MinHookTimeout = 1
+	MaxHookTimeout = 600
+)
+
+// settingsSchemaID identifies the schema emitted by SettingsHooksSchema
+const settingsSchemaID = "https://github.com/klauern/blues-traveler/schemas/settings-hooks.json"
+
+// toolEvents are the events whose matcher selects tools
+var toolEvents = map[string]bool{"PreToolUse": true, "PostToolUse": true}
+
+// SettingsEventNames lists the hook events settings.json can hold, in the
+// order of HooksConfig
+func SettingsEventNames() []string {
+	t := reflect.TypeOf(HooksConfig{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// SettingsHooksSchema returns a JSON Schema (draft 2020-12) for the hooks
+// section of settings.json as blues-traveler reads and writes it. Other
+// top-level settings are allowed but not described.
+func SettingsHooksSchema() map[string]interface{} {
+	command := map[string]interface{}{
+		"type":                 "object",
+		"required":             []string{"type", "command"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"const":       "command",
+				"description": "Hook kind; only shell commands are supported",
+			},
+			"command": map[string]interface{}{
+				"type":        "string",
+				"minLength":   1,
+				"description": "Shell command to run. blues-traveler installs 'blues-traveler hooks run <key>' commands.",
+				"examples":    []string{"blues-traveler hooks run security", "blues-traveler hooks run config:go:fmt"},
+			},
+			"timeout": map[string]interface{}{
+				"type":        "integer",
+				"minimum":     MinHookTimeout,
+				"maximum":     MaxHookTimeout,
+				"description": "Seconds before the command is cancelled; omit for Claude Code's default",
+			},
+		},
+	}
+
+	events := map[string]interface{}{}
+	for _, name := range SettingsEventNames() {
+		matcherDesc := "Ignored for this event; omit it or use \"*\""
+		if toolEvents[name] {
+			matcherDesc = "Tools this entry runs for, matched by Claude Code against the tool name: an exact name, a '|'-separated list or regular expression (\"Edit|Write\", \"mcp__.*\"), or \"*\"/empty for every tool"
+		}
+		events[name] = map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":                 "object",
+				"required":             []string{"hooks"},
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"matcher": map[string]interface{}{"type": "string", "description": matcherDesc},
+					"hooks":   map[string]interface{}{"type": "array", "minItems": 1, "items": map[string]interface{}{"$ref": "#/$defs/hookCommand"}},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         settingsSchemaID,
+		"title":       "Claude Code settings.json hooks",
+		"description": "The hooks section of .claude/settings.json as blues-traveler installs and reads it",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"hooks": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Hook entries by event name",
+				"additionalProperties": false,
+				"properties":           events,
+			},
+		},
+		"$defs":    map[string]interface{}{"hookCommand": command},
+		"examples": []interface{}{ExampleSettingsHooks()},
+	}
+}
+
+// ExampleSettingsHooks returns a settings document with typical entries
+func ExampleSettingsHooks() map[string]interface{} {
+	timeout := 60
+	hooks := HooksConfig{
+		PreToolUse: []HookMatcher{{
+			Matcher: "Bash",
+			Hooks:   []HookCommand{{Type: "command", Command: "blues-traveler hooks run security"}},
+		}},
+		PostToolUse: []HookMatcher{{
+			Matcher: "Edit|MultiEdit|Write",
+			Hooks:   []HookCommand{{Type: "command", Command: "blues-traveler hooks run format", Timeout: &timeout}},
+		}},
+		Stop: []HookMatcher{{
+			Hooks: []HookCommand{{Type: "command", Command: "blues-traveler hooks run config:go:test"}},
+		}},
+	}
+	return map[string]interface{}{"hooks": hooks}
+}
+
+// SectionSchema returns a JSON Schema for a config section struct such as
+// LargeFilesConfig, built from its json tags. Hooks publish it in their
+// manifest so tools can check the settings they read.
+func SectionSchema(section interface{}) map[string]interface{} {
+	return typeSchema(reflect.TypeOf(section))
+}
+
+// typeSchema maps a Go type to the JSON Schema of its encoding/json form
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			parts := strings.Split(f.Tag.Get("json"), ",")
+			name := parts[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = typeSchema(f.Type)
+			if !slices.Contains(parts[1:], "omitempty") {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
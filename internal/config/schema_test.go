@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSettingsHooksSchema_CoversEventsAndExample(t *testing.T) {
+	schema := SettingsHooksSchema()
+	hooks := schema["properties"].(map[string]interface{})["hooks"].(map[string]interface{})
+	events := hooks["properties"].(map[string]interface{})
+
+	names := SettingsEventNames()
+	if len(names) != 9 || names[0] != "PreToolUse" || names[len(names)-1] != "SessionEnd" {
+		t.Fatalf("unexpected event names: %v", names)
+	}
+	for _, name := range names {
+		if events[name] == nil {
+			t.Errorf("schema missing event %s", name)
+		}
+	}
+
+	// The example must only use events and fields the schema describes
+	data, err := json.Marshal(ExampleSettingsHooks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var example struct {
+		Hooks map[string][]map[string]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &example); err != nil {
+		t.Fatal(err)
+	}
+	for event, matchers := range example.Hooks {
+		if events[event] == nil {
+			t.Errorf("example uses unknown event %s", event)
+		}
+		for _, m := range matchers {
+			for key := range m {
+				if key != "matcher" && key != "hooks" {
+					t.Errorf("example matcher has unknown field %s", key)
+				}
+			}
+		}
+	}
+
+	timeout := schema["$defs"].(map[string]interface{})["hookCommand"].(map[string]interface{})["properties"].(map[string]interface{})["timeout"].(map[string]interface{})
+	if timeout["maximum"] != MaxHookTimeout {
+		t.Errorf("timeout maximum = %v, want %d", timeout["maximum"], MaxHookTimeout)
+	}
+}
+
+func TestSectionSchema(t *testing.T) {
+	schema := SectionSchema(PRReadinessConfig{})
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok || schema["type"] != "object" || schema["additionalProperties"] != false {
+		t.Fatalf("expected a closed object schema, got %v", schema)
+	}
+	if got := props["block"].(map[string]interface{})["type"]; got != "boolean" {
+		t.Errorf("block type = %v, want boolean", got)
+	}
+	checks := props["checks"].(map[string]interface{})
+	item := checks["items"].(map[string]interface{})
+	if checks["type"] != "array" || item["type"] != "object" {
+		t.Fatalf("checks should be an array of objects, got %v", checks)
+	}
+	if req, _ := item["required"].([]string); len(req) != 1 || req[0] != "name" {
+		t.Errorf("only name should be required in a check, got %v", item["required"])
+	}
+	if got := item["properties"].(map[string]interface{})["timeout"].(map[string]interface{})["type"]; got != "integer" {
+		t.Errorf("timeout type = %v, want integer", got)
+	}
+}
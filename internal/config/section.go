@@ -0,0 +1,24 @@
+package config
+
+// LoadSection returns the settings section picks from the project config,
+// falling back to the global config when the project doesn't set it. The
+// zero value is returned when neither does. Hooks read their settings with
+// it, e.g.
+// LoadSection(func(c *LogConfig) *CodeOwnersConfig { return c.CodeOwners }).
+func LoadSection[T any](section func(*LogConfig) *T) T {
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		lc, err := LoadLogConfig(path)
+		if err != nil || lc == nil {
+			continue
+		}
+		if s := section(lc); s != nil {
+			return *s
+		}
+	}
+	var zero T
+	return zero
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSection_FallsBackToGlobal(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+	codeOwners := func(c *LogConfig) *CodeOwnersConfig { return c.CodeOwners }
+
+	if got := LoadSection(codeOwners); got.File != "" {
+		t.Fatalf("no config should give the zero value, got %+v", got)
+	}
+
+	writeConfig := func(dir, body string) {
+		t.Helper()
+		path := filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(home, `{"codeOwners":{"file":"GLOBAL_OWNERS"}}`)
+	if got := LoadSection(codeOwners); got.File != "GLOBAL_OWNERS" {
+		t.Errorf("project without the section should use the global one, got %+v", got)
+	}
+
+	writeConfig(project, `{"codeOwners":{"file":"PROJECT_OWNERS"}}`)
+	if got := LoadSection(codeOwners); got.File != "PROJECT_OWNERS" {
+		t.Errorf("project section should win, got %+v", got)
+	}
+}
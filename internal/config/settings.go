@@ -5,8 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
+	"time"
 )
 
 // HookCommand represents a single hook command configuration with type, command, and optional timeout
@@ -39,6 +38,16 @@ type HooksConfig struct {
 // A nil Enabled means default (enabled). If Enabled=false, the plugin is disabled.
 type PluginConfig struct {
 	Enabled *bool `json:"enabled,omitempty"`
+	// Languages toggles language-specific behavior for plugins that support it
+	// (e.g. {"python": false}). Languages not listed default to enabled.
+	Languages map[string]bool `json:"languages,omitempty"`
+	// SnoozedUntil silences the plugin until the given time; see SetSnooze
+	SnoozedUntil *time.Time `json:"snoozedUntil,omitempty"`
+	// OnlyNewFindings makes lint-style plugins report only findings that
+	// were not present on their previous run for the same file
+	OnlyNewFindings bool `json:"onlyNewFindings,omitempty"`
+	// DisabledEntries switches off single installed entries; see SetEntryEnabled
+	DisabledEntries []EntryToggle `json:"disabledEntries,omitempty"`
 }
 
 // Settings represents the complete settings structure including hooks, plugins, and other configuration
@@ -51,20 +60,12 @@ type Settings struct {
 
 // GetSettingsPath returns the path to the settings file (global or project-specific)
 func GetSettingsPath(global bool) (string, error) {
-	if global {
-		// Global settings: ~/.claude/settings.json
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %w", err)
-		}
-		return filepath.Join(homeDir, ".claude", "settings.json"), nil
-	}
-	// Project settings: ./.claude/settings.json
-	cwd, err := os.Getwd()
+	// Global settings: ~/.claude/settings.json; project: ./.claude/settings.json
+	dir, err := ClaudeDirFor(global)
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(cwd, ".claude", "settings.json"), nil
+	return filepath.Join(dir, "settings.json"), nil
 }
 
 // LoadSettings loads settings from the specified path, preserving unknown JSON fields
@@ -174,6 +175,9 @@ func SaveSettings(settingsPath string, settings *Settings) error {
 		output[k] = v
 	}
 
+	// Rewrite deprecated "blues-traveler run X" commands to the canonical form
+	NormalizeLegacyCommands(settings)
+
 	// Add known fields
 	if settings.DefaultModel != "" {
 		output["defaultModel"] = settings.DefaultModel
@@ -194,6 +198,9 @@ func SaveSettings(settingsPath string, settings *Settings) error {
 		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
 
+	if err := backupSettings(settingsPath, data); err != nil {
+		return fmt.Errorf("failed to back up settings before writing: %w", err)
+	}
 	if err := writeFileAtomic(settingsPath, data, 0o600); err != nil {
 		return fmt.Errorf("failed to write settings file: %w", err)
 	}
@@ -232,6 +239,13 @@ func (s *Settings) IsPluginEnabled(key string) bool {
 
 // AddHookToSettings adds or merges a hook into settings for the specified event
 func AddHookToSettings(settings *Settings, event, matcher, command string, timeout *int) MergeResult {
+	return AddHookToSettingsWithPolicy(settings, event, matcher, command, timeout, MergePolicyByHookType)
+}
+
+// AddHookToSettingsWithPolicy adds a hook like AddHookToSettings, using policy
+// to decide what happens when the same blues-traveler hook type is already
+// installed under the matcher
+func AddHookToSettingsWithPolicy(settings *Settings, event, matcher, command string, timeout *int, policy MergePolicy) MergeResult {
 	hookCmd := HookCommand{
 		Type:    "command",
 		Command: command,
@@ -252,31 +266,31 @@ func AddHookToSettings(settings *Settings, event, matcher, command string, timeo
 	var result MergeResult
 	switch event {
 	case "PreToolUse":
-		result = mergeHookMatcher(settings.Hooks.PreToolUse, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.PreToolUse, hookMatcher, policy)
 		settings.Hooks.PreToolUse = result.Matchers
 	case "PostToolUse":
-		result = mergeHookMatcher(settings.Hooks.PostToolUse, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.PostToolUse, hookMatcher, policy)
 		settings.Hooks.PostToolUse = result.Matchers
 	case "UserPromptSubmit":
-		result = mergeHookMatcher(settings.Hooks.UserPromptSubmit, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.UserPromptSubmit, hookMatcher, policy)
 		settings.Hooks.UserPromptSubmit = result.Matchers
 	case "Notification":
-		result = mergeHookMatcher(settings.Hooks.Notification, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.Notification, hookMatcher, policy)
 		settings.Hooks.Notification = result.Matchers
 	case "Stop":
-		result = mergeHookMatcher(settings.Hooks.Stop, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.Stop, hookMatcher, policy)
 		settings.Hooks.Stop = result.Matchers
 	case "SubagentStop":
-		result = mergeHookMatcher(settings.Hooks.SubagentStop, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.SubagentStop, hookMatcher, policy)
 		settings.Hooks.SubagentStop = result.Matchers
 	case "PreCompact":
-		result = mergeHookMatcher(settings.Hooks.PreCompact, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.PreCompact, hookMatcher, policy)
 		settings.Hooks.PreCompact = result.Matchers
 	case "SessionStart":
-		result = mergeHookMatcher(settings.Hooks.SessionStart, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.SessionStart, hookMatcher, policy)
 		settings.Hooks.SessionStart = result.Matchers
 	case "SessionEnd":
-		result = mergeHookMatcher(settings.Hooks.SessionEnd, hookMatcher)
+		result = mergeHookMatcher(settings.Hooks.SessionEnd, hookMatcher, policy)
 		settings.Hooks.SessionEnd = result.Matchers
 	}
 	return result
@@ -293,19 +307,60 @@ type MergeResult struct {
 // Example: "/path/to/blues-traveler run debug --log" -> "debug"
 // Also handles: "/path/to/blues-traveler hooks run debug --log" -> "debug"
 func extractHookType(command string) string {
-	// Match both "blues-traveler run" and "blues-traveler hooks run" patterns
-	// This correctly captures config hooks like 'config:python:post-sample'.
-	re := regexp.MustCompile(`blues-traveler\s+(?:hooks\s+)?run\s+([^\s]+)`) // capture until whitespace
-	matches := re.FindStringSubmatch(command)
-	if len(matches) > 1 {
-		return matches[1]
+	p, ok := parseHookCommand(command)
+	if !ok || (!p.Legacy && !isBluesTravelerExecutable(p.Executable)) {
+		return ""
 	}
-	return ""
+	return p.HookType
 }
 
-// isBluesTravelerCommand checks if a command is a blues-traveler command
+// isBluesTravelerCommand checks if a command runs the blues-traveler binary
 func isBluesTravelerCommand(command string) bool {
-	return strings.Contains(command, "blues-traveler run") || strings.Contains(command, "blues-traveler hooks run")
+	p, ok := parseHookCommand(command)
+	return ok && isBluesTravelerExecutable(p.Executable)
+}
+
+// NormalizeLegacyCommand rewrites "blues-traveler run X" to the canonical
+// "blues-traveler hooks run X". It reports whether the command changed.
+// Only the "run" word is touched, so the executable's quoting is preserved.
+func NormalizeLegacyCommand(command string) (string, bool) {
+	p, ok := parseHookCommand(command)
+	if !ok || !p.Legacy {
+		return command, false
+	}
+	return command[:p.runToken.Start] + "hooks " + command[p.runToken.Start:], true
+}
+
+// NormalizeLegacyCommands rewrites every legacy command form in settings and
+// returns the number of commands changed.
+func NormalizeLegacyCommands(settings *Settings) int {
+	changed := 0
+	for _, matchers := range getAllHookMatchers(&settings.Hooks) {
+		for i := range matchers {
+			for j := range matchers[i].Hooks {
+				if cmd, ok := NormalizeLegacyCommand(matchers[i].Hooks[j].Command); ok {
+					matchers[i].Hooks[j].Command = cmd
+					changed++
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// CountLegacyCommands returns how many commands still use the deprecated form
+func CountLegacyCommands(settings *Settings) int {
+	count := 0
+	for _, matchers := range getAllHookMatchers(&settings.Hooks) {
+		for _, m := range matchers {
+			for _, h := range m.Hooks {
+				if p, ok := parseHookCommand(h.Command); ok && p.Legacy {
+					count++
+				}
+			}
+		}
+	}
+	return count
 }
 
 // checkExactDuplicate checks if a hook command is an exact duplicate
@@ -322,7 +377,10 @@ func checkExactDuplicate(existingHook HookCommand, newHook HookCommand, matcherN
 
 // checkBluesTravelerConflict checks if two blues-traveler hooks conflict
 // Creates a copy of the input slice to avoid side effects on the original data
-func checkBluesTravelerConflict(existingHook HookCommand, newHook HookCommand, matcherName string, matcherIndex, hookIndex int, existing []HookMatcher) *MergeResult {
+func checkBluesTravelerConflict(existingHook HookCommand, newHook HookCommand, matcherName string, matcherIndex, hookIndex int, existing []HookMatcher, policy MergePolicy) *MergeResult {
+	if policy == MergePolicyExact {
+		return nil
+	}
 	if !isBluesTravelerCommand(existingHook.Command) || !isBluesTravelerCommand(newHook.Command) {
 		return nil
 	}
@@ -331,6 +389,14 @@ func checkBluesTravelerConflict(existingHook HookCommand, newHook HookCommand, m
 	newType := extractHookType(newHook.Command)
 
 	if existingType != "" && existingType == newType {
+		if policy == MergePolicyNeverReplace {
+			return &MergeResult{
+				Matchers:      existing,
+				WasDuplicate:  true,
+				DuplicateInfo: fmt.Sprintf("%s hook already exists for matcher '%s' (merge policy %s keeps '%s')", newType, matcherName, policy, existingHook.Command),
+			}
+		}
+
 		// Create a copy of the existing matchers to avoid mutating the input
 		result := make([]HookMatcher, len(existing))
 		copy(result, existing)
@@ -353,7 +419,7 @@ func checkBluesTravelerConflict(existingHook HookCommand, newHook HookCommand, m
 }
 
 // checkHookConflicts checks for conflicts between existing and new hooks
-func checkHookConflicts(existing []HookMatcher, newMatcher HookMatcher, matcherIndex int) *MergeResult {
+func checkHookConflicts(existing []HookMatcher, newMatcher HookMatcher, matcherIndex int, policy MergePolicy) *MergeResult {
 	for j, existingHook := range existing[matcherIndex].Hooks {
 		for _, newHook := range newMatcher.Hooks {
 			// Exact duplicate check
@@ -363,7 +429,7 @@ func checkHookConflicts(existing []HookMatcher, newMatcher HookMatcher, matcherI
 			}
 
 			// Check if both are blues-traveler commands with the same hook type
-			if result := checkBluesTravelerConflict(existingHook, newHook, existing[matcherIndex].Matcher, matcherIndex, j, existing); result != nil {
+			if result := checkBluesTravelerConflict(existingHook, newHook, existing[matcherIndex].Matcher, matcherIndex, j, existing, policy); result != nil {
 				return result
 			}
 		}
@@ -371,12 +437,12 @@ func checkHookConflicts(existing []HookMatcher, newMatcher HookMatcher, matcherI
 	return nil
 }
 
-func mergeHookMatcher(existing []HookMatcher, newMatcher HookMatcher) MergeResult {
+func mergeHookMatcher(existing []HookMatcher, newMatcher HookMatcher, policy MergePolicy) MergeResult {
 	// Look for existing matcher
 	for i, matcher := range existing {
 		if matcher.Matcher == newMatcher.Matcher {
 			// Check for conflicts
-			if result := checkHookConflicts(existing, newMatcher, i); result != nil {
+			if result := checkHookConflicts(existing, newMatcher, i, policy); result != nil {
 				return *result
 			}
 
@@ -407,9 +473,50 @@ func RemoveHookFromSettings(settings *Settings, command string) bool {
 // This handles cases where hooks were installed with flags (--log, --format) or
 // when the executable path has changed.
 func RemoveHookTypeFromSettings(settings *Settings, hookType string) bool {
-	return removeFromAllEvents(settings, func(matchers []HookMatcher, removed *bool) []HookMatcher {
-		return removeHookTypeFromMatchers(matchers, hookType, removed)
-	})
+	return RemoveHookTypeFromScope(settings, hookType, "", "") > 0
+}
+
+// RemoveHookTypeFromScope removes hooks matching a hook type pattern from the
+// given event and matcher only. An empty event covers every event and an
+// empty matcher every matcher; otherwise the matcher must equal the one in
+// settings exactly. Returns the number of hooks removed.
+func RemoveHookTypeFromScope(settings *Settings, hookType, event, matcher string) int {
+	if settings == nil || hookType == "" {
+		return 0
+	}
+
+	removed := 0
+	filter := func(matchers []HookMatcher) []HookMatcher {
+		var result []HookMatcher
+		for _, m := range matchers {
+			if matcher != "" && m.Matcher != matcher {
+				result = append(result, m)
+				continue
+			}
+			var hooks []HookCommand
+			for _, h := range m.Hooks {
+				// Ignores executable path and flags like --log, --log-format
+				if matchesHookType(h.Command, hookType) {
+					removed++
+					continue
+				}
+				hooks = append(hooks, h)
+			}
+			// Only keep matcher if it still has hooks
+			if len(hooks) > 0 {
+				m.Hooks = hooks
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+
+	if event == "" {
+		filterAllEvents(settings, filter)
+	} else {
+		filterSingleEvent(settings, event, filter)
+	}
+	return removed
 }
 
 // removeFromAllEvents applies a removal function to all event types in settings
@@ -452,70 +559,13 @@ func removeHookFromMatchers(matchers []HookMatcher, command string, removed *boo
 	return result
 }
 
-func removeHookTypeFromMatchers(matchers []HookMatcher, hookType string, removed *bool) []HookMatcher {
-	var result []HookMatcher
-
-	for _, matcher := range matchers {
-		var filteredHooks []HookCommand
-		for _, hook := range matcher.Hooks {
-			// Check if this is a blues-traveler command matching the hook type
-			// Matches: "hooks run <hookType>" or "blues-traveler run <hookType>"
-			// Ignores: executable path, flags like --log, --log-format
-			if !matchesHookType(hook.Command, hookType) {
-				filteredHooks = append(filteredHooks, hook)
-			} else {
-				*removed = true
-			}
-		}
-
-		// Only keep matcher if it still has hooks
-		if len(filteredHooks) > 0 {
-			matcher.Hooks = filteredHooks
-			result = append(result, matcher)
-		}
-	}
-
-	return result
-}
-
 // matchesHookType checks if a command matches a hook type pattern
 // Example: matchesHookType("/path/blues-traveler hooks run security --log", "security") -> true
 // Example: matchesHookType("/path/blues-traveler run security --log", "security") -> true
 // Example: matchesHookType("/path/blues-traveler hooks run config:group:job", "config:group:job") -> true
 func matchesHookType(command, hookType string) bool {
-	// Must be a blues-traveler command
-	if !IsBluesTravelerCommand(command) {
-		return false
-	}
-
-	// Look for "hooks run <hookType>" or "blues-traveler run <hookType>" pattern
-	// The command may have additional flags after the hook type
-	patterns := []string{"hooks run " + hookType, "blues-traveler run " + hookType}
-	idx := -1
-	patternLen := 0
-
-	for _, pattern := range patterns {
-		if foundIdx := strings.Index(command, pattern); foundIdx != -1 {
-			idx = foundIdx
-			patternLen = len(pattern)
-			break
-		}
-	}
-
-	if idx == -1 {
-		return false
-	}
-
-	// Verify it's either at the end or followed by a space (for flags)
-	endIdx := idx + patternLen
-	if endIdx == len(command) {
-		return true
-	}
-	if endIdx < len(command) && (command[endIdx] == ' ' || command[endIdx] == '\t') {
-		return true
-	}
-
-	return false
+	p, ok := parseHookCommand(command)
+	return ok && hookType != "" && p.HookType == hookType
 }
 
 // CountBluesTravelerInSettings counts all blues-traveler commands in the settings
@@ -547,9 +597,11 @@ func CountBluesTravelerInSettings(settings *Settings) int {
 	return count
 }
 
-// IsBluesTravelerCommand checks if a command is from blues-traveler
+// IsBluesTravelerCommand checks if a command is from blues-traveler. Any
+// "<exe> hooks run <key>" counts, so renamed binaries are still recognized.
 func IsBluesTravelerCommand(command string) bool {
-	return strings.Contains(command, "blues-traveler run") || strings.Contains(command, "hooks run")
+	_, ok := parseHookCommand(command)
+	return ok
 }
 
 // PrintBluesTravelerToRemove shows which blues-traveler hooks will be removed
@@ -634,10 +686,9 @@ func RemoveConfigGroupFromSettings(settings *Settings, group string, event strin
 	}
 
 	removed := 0
-	matchPattern := "config:" + group + ":"
 
 	// Create filter function that removes matching hooks
-	filter := makeConfigGroupFilter(matchPattern, &removed)
+	filter := makeConfigGroupFilter(group, &removed)
 
 	// Apply filter to specified event or all events
 	if event == "" {
@@ -650,13 +701,13 @@ func RemoveConfigGroupFromSettings(settings *Settings, group string, event strin
 }
 
 // makeConfigGroupFilter creates a filter function that removes hooks matching a config group
-func makeConfigGroupFilter(matchPattern string, removed *int) func([]HookMatcher) []HookMatcher {
+func makeConfigGroupFilter(group string, removed *int) func([]HookMatcher) []HookMatcher {
 	return func(matchers []HookMatcher) []HookMatcher {
 		var result []HookMatcher
 		for _, m := range matchers {
 			var hooks []HookCommand
 			for _, h := range m.Hooks {
-				if IsBluesTravelerCommand(h.Command) && strings.Contains(h.Command, matchPattern) {
+				if extractConfigGroupName(h.Command) == group {
 					*removed++
 					continue
 				}
@@ -726,6 +777,30 @@ func GetConfigGroupsInSettings(settings *Settings) map[string]bool {
 	return groups
 }
 
+// InstalledHook is one blues-traveler hook command found in settings
+type InstalledHook struct {
+	Event    string
+	Matcher  string
+	HookType string
+	Command  string
+}
+
+// InstalledHooks lists the blues-traveler hook commands in hooks, in event order
+func InstalledHooks(hooks HooksConfig) []InstalledHook {
+	var installed []InstalledHook
+	events := SettingsEventNames()
+	for i, matchers := range getAllHookMatchers(&hooks) {
+		for _, m := range matchers {
+			for _, hook := range m.Hooks {
+				if hookType := extractHookType(hook.Command); hookType != "" {
+					installed = append(installed, InstalledHook{Event: events[i], Matcher: m.Matcher, HookType: hookType, Command: hook.Command})
+				}
+			}
+		}
+	}
+	return installed
+}
+
 // getAllHookMatchers returns all hook matcher slices from a HooksConfig
 func getAllHookMatchers(hooks *HooksConfig) [][]HookMatcher {
 	return [][]HookMatcher{
@@ -755,24 +830,11 @@ func extractGroupsFromMatchers(matchers []HookMatcher, groups map[string]bool) {
 // extractConfigGroupName extracts the group name from a config hook command
 // Returns empty string if not a config hook command
 func extractConfigGroupName(command string) string {
-	if !IsBluesTravelerCommand(command) || !strings.Contains(command, "config:") {
-		return ""
-	}
-
-	// Extract group name from "blues-traveler run config:groupname:jobname"
-	// Find the "config:" substring to avoid splitting Windows paths like "C:\..."
-	configIdx := strings.Index(command, "config:")
-	if configIdx == -1 {
+	p, ok := parseHookCommand(command)
+	if !ok {
 		return ""
 	}
-
-	// Parse only from the "config:" part onwards
-	configPart := command[configIdx+len("config:"):]
-	parts := strings.Split(configPart, ":")
-	if len(parts) >= 2 && parts[0] != "" {
-		return parts[0]
-	}
-	return ""
+	return configGroupFromHookType(p.HookType)
 }
 
 // IsPluginEnabled checks (project first, then global) settings to see if a plugin is enabled.
@@ -796,3 +858,56 @@ func IsPluginEnabled(pluginKey string) bool {
 	}
 	return true
 }
+
+// IsPluginLanguageEnabled checks a per-language toggle for a plugin. Project
+// settings take precedence over global; unlisted languages are enabled.
+func IsPluginLanguageEnabled(pluginKey, language string) bool {
+	for _, global := range []bool{false, true} {
+		path, err := GetSettingsPath(global)
+		if err != nil {
+			continue
+		}
+		s, err := LoadSettings(path)
+		if err != nil {
+			continue
+		}
+		if cfg, ok := s.Plugins[pluginKey]; ok {
+			if enabled, set := cfg.Languages[language]; set {
+				return enabled
+			}
+		}
+	}
+	return true
+}
+
+// PluginOnlyNewFindings reports whether a plugin should report only new
+// findings. Project settings take precedence over global.
+func PluginOnlyNewFindings(pluginKey string) bool {
+	for _, global := range []bool{false, true} {
+		path, err := GetSettingsPath(global)
+		if err != nil {
+			continue
+		}
+		s, err := LoadSettings(path)
+		if err != nil {
+			continue
+		}
+		if cfg, ok := s.Plugins[pluginKey]; ok {
+			return cfg.OnlyNewFindings
+		}
+	}
+	return false
+}
+
+// SameFile reports whether a and b name the same existing file
+func SameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SettingsBackupDirName is the directory next to settings.json that
+	// holds the versions SaveSettings replaced
+	SettingsBackupDirName = ".settings-backups"
+	// maxSettingsBackups is how many snapshots are kept per settings file
+	maxSettingsBackups = 20
+	// settingsBackupStamp names snapshots, so they sort by time
+	settingsBackupStamp = "20060102T150405.000"
+)
+
+// SettingsBackup is one snapshot of a settings file
+type SettingsBackup struct {
+	// ID is the snapshot's timestamp, as passed to 'config rollback --to'
+	ID   string
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// SettingsBackupDir returns the snapshot directory for settingsPath
+func SettingsBackupDir(settingsPath string) string {
+	return filepath.Join(filepath.Dir(settingsPath), SettingsBackupDirName)
+}
+
+// backupSettings copies the settings file about to be replaced by data into
+// the snapshot directory. Nothing is kept when the file is new or unchanged.
+func backupSettings(settingsPath string, data []byte) error {
+	current, err := os.ReadFile(settingsPath) // #nosec G304 - settings path is controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+	dir := SettingsBackupDir(settingsPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	// Snapshots are local undo history, not something to commit
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o600)
+	}
+	if err := writeFileAtomic(settingsBackupName(settingsPath, time.Now()), current, 0o600); err != nil {
+		return err
+	}
+	return pruneSettingsBackups(settingsPath)
+}
+
+func settingsBackupName(settingsPath string, t time.Time) string {
+	ext := filepath.Ext(settingsPath)
+	base := strings.TrimSuffix(filepath.Base(settingsPath), ext)
+	dir := SettingsBackupDir(settingsPath)
+	stamp := t.Format(settingsBackupStamp)
+	name := filepath.Join(dir, fmt.Sprintf("%s-%s%s", base, stamp, ext))
+	for i := 1; fileExists(name); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext))
+	}
+	return name
+}
+
+// ListSettingsBackups returns the snapshots of settingsPath, newest first
+func ListSettingsBackups(settingsPath string) ([]SettingsBackup, error) {
+	entries, err := os.ReadDir(SettingsBackupDir(settingsPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ext := filepath.Ext(settingsPath)
+	prefix := strings.TrimSuffix(filepath.Base(settingsPath), ext) + "-"
+	var backups []SettingsBackup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		t, ok := parseSettingsBackupID(id)
+		if !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, SettingsBackup{
+			ID:   id,
+			Path: filepath.Join(SettingsBackupDir(settingsPath), name),
+			Time: t,
+			Size: info.Size(),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Time.Equal(backups[j].Time) {
+			return backups[i].Time.After(backups[j].Time)
+		}
+		// Same millisecond: a longer ".N" suffix was taken later
+		if len(backups[i].ID) != len(backups[j].ID) {
+			return len(backups[i].ID) > len(backups[j].ID)
+		}
+		return backups[i].ID > backups[j].ID
+	})
+	return backups, nil
+}
+
+// parseSettingsBackupID reads the time from a snapshot id, which is a
+// timestamp with an optional ".N" suffix for snapshots taken within 1ms
+func parseSettingsBackupID(id string) (time.Time, bool) {
+	if len(id) < len(settingsBackupStamp) {
+		return time.Time{}, false
+	}
+	stamp, suffix := id[:len(settingsBackupStamp)], id[len(settingsBackupStamp):]
+	if suffix != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(suffix, "."))
+		if err != nil || n < 1 || !strings.HasPrefix(suffix, ".") {
+			return time.Time{}, false
+		}
+	}
+	t, err := time.ParseInLocation(settingsBackupStamp, stamp, time.Local)
+	return t, err == nil
+}
+
+func pruneSettingsBackups(settingsPath string) error {
+	backups, err := ListSettingsBackups(settingsPath)
+	if err != nil {
+		return err
+	}
+	for _, b := range backups[min(len(backups), maxSettingsBackups):] {
+		if err := os.Remove(b.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreSettingsBackup replaces settingsPath with the snapshot id, or the
+// newest snapshot when id is empty. The replaced version is itself kept as
+// a snapshot, so a rollback can be undone. Callers hold LockFile.
+func RestoreSettingsBackup(settingsPath, id string) (SettingsBackup, error) {
+	backups, err := ListSettingsBackups(settingsPath)
+	if err != nil {
+		return SettingsBackup{}, fmt.Errorf("failed to list settings backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return SettingsBackup{}, fmt.Errorf("no backups of %s\n  Suggestion: Backups are taken when install, uninstall or sync change settings; there is nothing to roll back yet", settingsPath)
+	}
+	target := backups[0]
+	if id != "" {
+		found := false
+		for _, b := range backups {
+			if b.ID == id {
+				target, found = b, true
+				break
+			}
+		}
+		if !found {
+			return SettingsBackup{}, fmt.Errorf("no backup %q of %s\n  Suggestion: Run 'blues-traveler config rollback --list' to see the available timestamps", id, settingsPath)
+		}
+	}
+
+	data, err := os.ReadFile(target.Path) // #nosec G304 - backup path is controlled
+	if err != nil {
+		return SettingsBackup{}, fmt.Errorf("failed to read backup: %w", err)
+	}
+	if !json.Valid(data) {
+		return SettingsBackup{}, fmt.Errorf("backup %s is not valid JSON\n  Suggestion: Pick another backup with --to", target.ID)
+	}
+	if err := backupSettings(settingsPath, data); err != nil {
+		return SettingsBackup{}, fmt.Errorf("failed to back up current settings: %w", err)
+	}
+	if err := writeFileAtomic(settingsPath, data, 0o600); err != nil {
+		return SettingsBackup{}, fmt.Errorf("failed to write settings file: %w", err)
+	}
+	return target, nil
+}
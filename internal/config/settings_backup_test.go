@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveSettingsBacksUpAndRollsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+
+	first := &Settings{Hooks: HooksConfig{Stop: []HookMatcher{{Hooks: []HookCommand{{Type: "command", Command: "first"}}}}}}
+	if err := SaveSettings(path, first); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := ListSettingsBackups(path); len(backups) != 0 {
+		t.Fatalf("a new file must not be snapshotted, got %d", len(backups))
+	}
+	if err := SaveSettings(path, first); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := ListSettingsBackups(path); len(backups) != 0 {
+		t.Fatalf("an unchanged write must not be snapshotted, got %d", len(backups))
+	}
+
+	second := &Settings{Hooks: HooksConfig{Stop: []HookMatcher{{Hooks: []HookCommand{{Type: "command", Command: "second"}}}}}}
+	if err := SaveSettings(path, second); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := ListSettingsBackups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v, %v; want one", backups, err)
+	}
+
+	restored, err := RestoreSettingsBackup(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ID != backups[0].ID {
+		t.Errorf("restored %s, want newest %s", restored.ID, backups[0].ID)
+	}
+	got, err := LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd := got.Hooks.Stop[0].Hooks[0].Command; cmd != "first" {
+		t.Errorf("after rollback command = %q, want first", cmd)
+	}
+	if backups, _ := ListSettingsBackups(path); len(backups) != 2 {
+		t.Errorf("rollback must snapshot the replaced version, got %d backups", len(backups))
+	}
+
+	if _, err := RestoreSettingsBackup(path, "19990101T000000.000"); err == nil {
+		t.Error("expected an unknown backup id to fail")
+	}
+}
+
+func TestSettingsBackupsArePruned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	for i := 0; i < maxSettingsBackups+5; i++ {
+		if err := os.WriteFile(path, []byte{'[', byte('0' + i%10), ']'}, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := backupSettings(path, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups, err := ListSettingsBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != maxSettingsBackups {
+		t.Errorf("kept %d backups, want %d", len(backups), maxSettingsBackups)
+	}
+}
+
+func TestParseSettingsBackupID(t *testing.T) {
+	for id, want := range map[string]bool{
+		"20250101T120000.000":   true,
+		"20250101T120000.000.2": true,
+		"20250101T120000.000.x": false,
+		"20250101T120000.000.0": false,
+		"2025":                  false,
+	} {
+		if _, ok := parseSettingsBackupID(id); ok != want {
+			t.Errorf("parseSettingsBackupID(%q) = %v, want %v", id, ok, want)
+		}
+	}
+}
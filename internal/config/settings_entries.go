@@ -0,0 +1,133 @@
+package config
+
+// SettingsEntry is one hook command in settings.json, addressed by its
+// event and position so it can be edited in place
+type SettingsEntry struct {
+	Event        string
+	MatcherIndex int
+	HookIndex    int
+	Matcher      string
+	Command      string
+	Timeout      *int
+	// HookType is the blues-traveler hook key, empty for other commands
+	HookType string
+}
+
+// SettingsEntries lists every hook command in hooks, blues-traveler's and
+// others', in event order
+func SettingsEntries(hooks HooksConfig) []SettingsEntry {
+	var entries []SettingsEntry
+	events := SettingsEventNames()
+	for i, matchers := range getAllHookMatchers(&hooks) {
+		for mi, m := range matchers {
+			for hi, hook := range m.Hooks {
+				entries = append(entries, SettingsEntry{
+					Event:        events[i],
+					MatcherIndex: mi,
+					HookIndex:    hi,
+					Matcher:      m.Matcher,
+					Command:      hook.Command,
+					Timeout:      hook.Timeout,
+					HookType:     extractHookType(hook.Command),
+				})
+			}
+		}
+	}
+	return entries
+}
+
+// eventMatchers returns the matcher list for event, or nil for an unknown event
+func eventMatchers(hooks *HooksConfig, event string) *[]HookMatcher {
+	switch event {
+	case "PreToolUse":
+		return &hooks.PreToolUse
+	case "PostToolUse":
+		return &hooks.PostToolUse
+	case "UserPromptSubmit":
+		return &hooks.UserPromptSubmit
+	case "Notification":
+		return &hooks.Notification
+	case "Stop":
+		return &hooks.Stop
+	case "SubagentStop":
+		return &hooks.SubagentStop
+	case "PreCompact":
+		return &hooks.PreCompact
+	case "SessionStart":
+		return &hooks.SessionStart
+	case "SessionEnd":
+		return &hooks.SessionEnd
+	}
+	return nil
+}
+
+// hookAt returns the matcher list holding e, after checking e still points
+// at a hook
+func (s *Settings) hookAt(e SettingsEntry) (*[]HookMatcher, bool) {
+	matchers := eventMatchers(&s.Hooks, e.Event)
+	if matchers == nil || e.MatcherIndex < 0 || e.MatcherIndex >= len(*matchers) {
+		return nil, false
+	}
+	if e.HookIndex < 0 || e.HookIndex >= len((*matchers)[e.MatcherIndex].Hooks) {
+		return nil, false
+	}
+	return matchers, true
+}
+
+// SetHookTimeout sets the timeout of the hook at e; nil clears it
+func (s *Settings) SetHookTimeout(e SettingsEntry, timeout *int) bool {
+	matchers, ok := s.hookAt(e)
+	if !ok {
+		return false
+	}
+	(*matchers)[e.MatcherIndex].Hooks[e.HookIndex].Timeout = timeout
+	return true
+}
+
+// SetHookMatcher moves the hook at e under matcher. A hook alone in its
+// matcher entry keeps its place; one sharing it moves to a new entry at
+// the end of the event's list.
+func (s *Settings) SetHookMatcher(e SettingsEntry, matcher string) bool {
+	matchers, ok := s.hookAt(e)
+	if !ok {
+		return false
+	}
+	m := &(*matchers)[e.MatcherIndex]
+	if len(m.Hooks) == 1 {
+		m.Matcher = matcher
+		return true
+	}
+	hook := m.Hooks[e.HookIndex]
+	m.Hooks = append(m.Hooks[:e.HookIndex:e.HookIndex], m.Hooks[e.HookIndex+1:]...)
+	*matchers = append(*matchers, HookMatcher{Matcher: matcher, Hooks: []HookCommand{hook}})
+	return true
+}
+
+// RemoveHookAt removes the hook at e, dropping its matcher entry once empty
+func (s *Settings) RemoveHookAt(e SettingsEntry) bool {
+	matchers, ok := s.hookAt(e)
+	if !ok {
+		return false
+	}
+	m := &(*matchers)[e.MatcherIndex]
+	m.Hooks = append(m.Hooks[:e.HookIndex:e.HookIndex], m.Hooks[e.HookIndex+1:]...)
+	if len(m.Hooks) == 0 {
+		*matchers = append((*matchers)[:e.MatcherIndex:e.MatcherIndex], (*matchers)[e.MatcherIndex+1:]...)
+	}
+	return true
+}
+
+// SetPluginEnabled enables or disables a plugin. Enabling removes the
+// override, since plugins are enabled by default.
+func (s *Settings) SetPluginEnabled(key string, enabled bool) {
+	if s.Plugins == nil {
+		s.Plugins = map[string]PluginConfig{}
+	}
+	cfg := s.Plugins[key]
+	cfg.Enabled = nil
+	if !enabled {
+		disabled := false
+		cfg.Enabled = &disabled
+	}
+	s.setOrDropPlugin(key, cfg)
+}
@@ -0,0 +1,308 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SettingsLintKind names a class of settings entry that works but likely
+// does not do what was meant
+type SettingsLintKind string
+
+// Problems doctor settings reports
+const (
+	// LintShadowedMatcher is a command installed under two matchers of an
+	// event where one matcher covers tools the other also matches, so the
+	// command runs twice for those tools
+	LintShadowedMatcher SettingsLintKind = "shadowed-matcher"
+	// LintDuplicateCommand is a command installed under several events
+	// that it does not handle
+	LintDuplicateCommand SettingsLintKind = "duplicate-command"
+	// LintStaleBinary is a blues-traveler command that runs a missing or
+	// outdated binary
+	LintStaleBinary SettingsLintKind = "stale-binary"
+	// LintTimeoutLimit is a timeout outside the range Claude Code accepts
+	LintTimeoutLimit SettingsLintKind = "timeout-limit"
+)
+
+// SettingsFinding is one problem found by LintSettings with a hint on how
+// to fix it
+type SettingsFinding struct {
+	Kind    SettingsLintKind `json:"kind"`
+	Event   string           `json:"event"`
+	Matcher string           `json:"matcher,omitempty"`
+	Command string           `json:"command,omitempty"`
+	Problem string           `json:"problem"`
+	Hint    string           `json:"hint"`
+}
+
+// SettingsLintOptions supplies what the checks need from outside config
+type SettingsLintOptions struct {
+	// Executable is the binary blues-traveler commands should run; when
+	// empty only missing binaries are reported
+	Executable string
+	// HandlesEvent reports whether a blues-traveler hook type handles an
+	// event; nil treats every repeated command as a duplicate
+	HandlesEvent func(hookType, event string) bool
+}
+
+// simpleToolName matches a literal tool name in a matcher alternation
+var simpleToolName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// LintSettings checks the hooks in settings for entries that are valid but
+// misbehave: shadowed matchers, commands repeated across events, stale
+// binaries and out-of-range timeouts. Findings are in event order.
+func LintSettings(settings *Settings, opts SettingsLintOptions) []SettingsFinding {
+	var findings []SettingsFinding
+	entries := SettingsEntries(settings.Hooks)
+
+	events := map[string][]string{}
+	var commands []string
+	for _, e := range entries {
+		if f := lintStaleBinary(e, opts); f != nil {
+			findings = append(findings, *f)
+		}
+		if f := lintTimeout(e); f != nil {
+			findings = append(findings, *f)
+		}
+		if _, seen := events[e.Command]; !seen {
+			commands = append(commands, e.Command)
+		}
+		if !containsString(events[e.Command], e.Event) {
+			events[e.Command] = append(events[e.Command], e.Event)
+		}
+	}
+
+	for _, event := range SettingsEventNames() {
+		if toolEvents[event] {
+			findings = append(findings, lintShadowedMatchers(event, *eventMatchers(&settings.Hooks, event))...)
+		}
+	}
+
+	for _, command := range commands {
+		if f := lintDuplicateCommand(command, events[command], opts); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+// lintShadowedMatchers reports commands that two matchers of one event
+// both run for some tool
+func lintShadowedMatchers(event string, matchers []HookMatcher) []SettingsFinding {
+	var findings []SettingsFinding
+	for i := 0; i < len(matchers); i++ {
+		for j := i + 1; j < len(matchers); j++ {
+			a, b := matchers[i], matchers[j]
+			if a.Matcher == b.Matcher {
+				// Same pattern twice is a duplicate matcher, which doctor --fix merges
+				continue
+			}
+			for _, cmd := range sharedCommands(a.Hooks, b.Hooks) {
+				wide, narrow, overlap := matcherOverlap(a.Matcher, b.Matcher)
+				if narrow == "" {
+					continue
+				}
+				problem := fmt.Sprintf("matcher %q already covers %q, so the command runs twice for %s", displayMatcher(wide), narrow, overlap)
+				if wide == "" {
+					problem = fmt.Sprintf("matchers %q and %q both match %s, so the command runs twice for it", displayMatcher(a.Matcher), displayMatcher(b.Matcher), overlap)
+				}
+				hint := fmt.Sprintf("Remove the command from the %q matcher, or merge both into one matcher that lists each tool once", narrow)
+				if p, ok := parseHookCommand(cmd); ok && wide != "" {
+					hint = fmt.Sprintf("Run 'blues-traveler hooks uninstall %s --event %s --matcher \"%s\"' to keep only the wider matcher", p.HookType, event, narrow)
+				}
+				findings = append(findings, SettingsFinding{
+					Kind: LintShadowedMatcher, Event: event, Matcher: narrow, Command: cmd,
+					Problem: problem, Hint: hint,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// matcherOverlap compares two matchers. When one covers every tool the
+// other matches, wide is that matcher and narrow the other; when they only
+// share some tools, wide is empty and narrow names the second matcher.
+// overlap describes the shared tools. narrow is empty when the matchers
+// share no tool, or when that can't be told (one is a complex regex).
+func matcherOverlap(a, b string) (wide, narrow, overlap string) {
+	if matchesAllTools(a) && matchesAllTools(b) {
+		return a, b, "every tool"
+	}
+	if matchesAllTools(a) {
+		return a, b, describeTools(b)
+	}
+	if matchesAllTools(b) {
+		return b, a, describeTools(a)
+	}
+	toolsA, okA := literalTools(a)
+	toolsB, okB := literalTools(b)
+	if !okA && !okB {
+		return "", "", ""
+	}
+	if !okA {
+		return coveredBy(a, b, toolsB)
+	}
+	if !okB {
+		return coveredBy(b, a, toolsA)
+	}
+	var shared []string
+	for _, t := range toolsB {
+		if containsString(toolsA, t) {
+			shared = append(shared, t)
+		}
+	}
+	switch len(shared) {
+	case 0:
+		return "", "", ""
+	case len(toolsB):
+		return a, b, strings.Join(shared, ", ")
+	case len(toolsA):
+		return b, a, strings.Join(shared, ", ")
+	}
+	return "", b, strings.Join(shared, ", ")
+}
+
+// coveredBy handles a complex regex pattern against a list of literal tools
+func coveredBy(pattern, literal string, tools []string) (wide, narrow, overlap string) {
+	var shared []string
+	for _, t := range tools {
+		if MatcherMatchesTool(pattern, t) {
+			shared = append(shared, t)
+		}
+	}
+	switch len(shared) {
+	case 0:
+		return "", "", ""
+	case len(tools):
+		return pattern, literal, strings.Join(shared, ", ")
+	}
+	return "", literal, strings.Join(shared, ", ")
+}
+
+// literalTools splits a matcher such as "Edit|Write" or "Edit,Write" into
+// tool names; ok is false for patterns that are real regular expressions
+func literalTools(matcher string) ([]string, bool) {
+	var tools []string
+	for _, part := range strings.FieldsFunc(matcher, func(r rune) bool { return r == '|' || r == ',' }) {
+		part = strings.TrimSpace(part)
+		if !simpleToolName.MatchString(part) {
+			return nil, false
+		}
+		tools = append(tools, part)
+	}
+	return tools, true
+}
+
+func matchesAllTools(matcher string) bool {
+	return matcher == "" || matcher == "*" || matcher == ".*"
+}
+
+func describeTools(matcher string) string {
+	if tools, ok := literalTools(matcher); ok {
+		return strings.Join(tools, ", ")
+	}
+	return "every tool " + displayMatcher(matcher) + " matches"
+}
+
+func displayMatcher(matcher string) string {
+	if matcher == "" {
+		return "*"
+	}
+	return matcher
+}
+
+// sharedCommands returns the commands present in both lists
+func sharedCommands(a, b []HookCommand) []string {
+	var shared []string
+	for _, x := range a {
+		for _, y := range b {
+			if x.Command == y.Command && !containsString(shared, x.Command) {
+				shared = append(shared, x.Command)
+			}
+		}
+	}
+	return shared
+}
+
+// lintDuplicateCommand reports a command installed under several events.
+// A blues-traveler hook that handles each of them is expected to be there.
+func lintDuplicateCommand(command string, events []string, opts SettingsLintOptions) *SettingsFinding {
+	if len(events) < 2 {
+		return nil
+	}
+	if p, ok := parseHookCommand(command); ok && opts.HandlesEvent != nil {
+		var unhandled []string
+		for _, ev := range events {
+			if !opts.HandlesEvent(p.HookType, ev) {
+				unhandled = append(unhandled, ev)
+			}
+		}
+		if len(unhandled) == 0 {
+			return nil
+		}
+		return &SettingsFinding{
+			Kind: LintDuplicateCommand, Event: strings.Join(events, ","), Command: command,
+			Problem: fmt.Sprintf("'%s' is installed for %s but does not handle %s", p.HookType, strings.Join(events, ", "), strings.Join(unhandled, ", ")),
+			Hint:    fmt.Sprintf("Uninstall it from %s with 'blues-traveler hooks uninstall %s --event <event>'", strings.Join(unhandled, ", "), p.HookType),
+		}
+	}
+	sorted := append([]string{}, events...)
+	sort.Strings(sorted)
+	return &SettingsFinding{
+		Kind: LintDuplicateCommand, Event: strings.Join(sorted, ","), Command: command,
+		Problem: fmt.Sprintf("the same command runs for %d events: %s", len(events), strings.Join(sorted, ", ")),
+		Hint:    "Keep it only under the events it needs; a command that can't tell the events apart does its work once per event",
+	}
+}
+
+// lintStaleBinary reports blues-traveler commands whose binary is missing
+// or is not the one new installs use
+func lintStaleBinary(e SettingsEntry, opts SettingsLintOptions) *SettingsFinding {
+	p, ok := parseHookCommand(e.Command)
+	if !ok || !(p.Legacy || isBluesTravelerExecutable(p.Executable)) || p.Executable == "" {
+		return nil
+	}
+	finding := SettingsFinding{Kind: LintStaleBinary, Event: e.Event, Matcher: e.Matcher, Command: e.Command}
+	if !executableExists(p.Executable) {
+		finding.Problem = fmt.Sprintf("%s does not exist; the hook fails on every run", p.Executable)
+		finding.Hint = "Run 'blues-traveler doctor --fix' to repoint it at the current binary"
+		return &finding
+	}
+	if opts.Executable == "" || sameExecutable(p.Executable, opts.Executable) {
+		return nil
+	}
+	finding.Problem = fmt.Sprintf("runs %s, but new installs run %s", p.Executable, opts.Executable)
+	finding.Hint = fmt.Sprintf("Reinstall with 'blues-traveler hooks install %s' (or 'hooks custom sync' for config groups) so every hook runs the same version", p.HookType)
+	return &finding
+}
+
+// sameExecutable compares two binary paths after expanding $VARS and ~/
+func sameExecutable(a, b string) bool {
+	expand := func(p string) string {
+		p = os.ExpandEnv(p)
+		if rest, ok := strings.CutPrefix(p, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				p = home + "/" + rest
+			}
+		}
+		return p
+	}
+	return expand(a) == expand(b)
+}
+
+// lintTimeout reports timeouts Claude Code would reject or cap
+func lintTimeout(e SettingsEntry) *SettingsFinding {
+	if e.Timeout == nil || (*e.Timeout >= MinHookTimeout && *e.Timeout <= MaxHookTimeout) {
+		return nil
+	}
+	return &SettingsFinding{
+		Kind: LintTimeoutLimit, Event: e.Event, Matcher: e.Matcher, Command: e.Command,
+		Problem: fmt.Sprintf("timeout %ds is outside the %d-%ds Claude Code allows for a hook", *e.Timeout, MinHookTimeout, MaxHookTimeout),
+		Hint:    fmt.Sprintf("Set a timeout between %d and %d with 'blues-traveler hooks manage', or remove it to use the default", MinHookTimeout, MaxHookTimeout),
+	}
+}
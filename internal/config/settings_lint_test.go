@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintSettings(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "blues-traveler")
+	old := filepath.Join(dir, "old", "blues-traveler")
+	for _, p := range []string{exe, old} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("#!/bin/sh\n"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	security := exe + " hooks run security"
+	audit := exe + " hooks run audit"
+	over := 900
+
+	settings := &Settings{Hooks: HooksConfig{
+		PreToolUse: []HookMatcher{
+			{Matcher: "*", Hooks: []HookCommand{{Type: "command", Command: security}, {Type: "command", Command: audit}}},
+			{Matcher: "Edit,Write", Hooks: []HookCommand{{Type: "command", Command: security}}},
+			{Matcher: "Bash|Write", Hooks: []HookCommand{{Type: "command", Command: "./lint.sh", Timeout: &over}}},
+			{Matcher: "Write|Read", Hooks: []HookCommand{{Type: "command", Command: "./lint.sh"}}},
+			{Matcher: "Bash", Hooks: []HookCommand{{Type: "command", Command: "/nonexistent/blues-traveler hooks run git-guard"}}},
+		},
+		PostToolUse: []HookMatcher{
+			{Matcher: "Edit", Hooks: []HookCommand{{Type: "command", Command: old + " hooks run format"}}},
+			{Matcher: "*", Hooks: []HookCommand{{Type: "command", Command: audit}, {Type: "command", Command: security}}},
+		},
+		Stop: []HookMatcher{
+			{Hooks: []HookCommand{{Type: "command", Command: "./lint.sh"}}},
+		},
+	}}
+	handles := map[string]bool{
+		"audit:PreToolUse": true, "audit:PostToolUse": true,
+		"security:PreToolUse": true,
+	}
+	findings := LintSettings(settings, SettingsLintOptions{
+		Executable:   exe,
+		HandlesEvent: func(hookType, event string) bool { return handles[hookType+":"+event] },
+	})
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, string(f.Kind)+" "+f.Event+" "+f.Matcher)
+		if f.Hint == "" {
+			t.Errorf("%s has no hint", f.Kind)
+		}
+	}
+	want := []string{
+		"timeout-limit PreToolUse Bash|Write",
+		"stale-binary PreToolUse Bash",
+		"stale-binary PostToolUse Edit",
+		"shadowed-matcher PreToolUse Edit,Write",
+		"shadowed-matcher PreToolUse Write|Read",
+		"duplicate-command PreToolUse,PostToolUse ",
+		"duplicate-command PreToolUse,Stop ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(findings[3].Problem, `"*" already covers "Edit,Write"`) {
+		t.Errorf("unexpected shadow problem: %s", findings[3].Problem)
+	}
+	if !strings.Contains(findings[3].Hint, `hooks uninstall security --event PreToolUse --matcher "Edit,Write"`) {
+		t.Errorf("unexpected shadow hint: %s", findings[3].Hint)
+	}
+	if !strings.Contains(findings[4].Problem, "both match Write") {
+		t.Errorf("unexpected overlap problem: %s", findings[4].Problem)
+	}
+	if !strings.Contains(findings[5].Problem, "does not handle PostToolUse") {
+		t.Errorf("unexpected duplicate problem: %s", findings[5].Problem)
+	}
+}
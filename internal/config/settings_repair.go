@@ -0,0 +1,316 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SettingsRepairKind names a class of broken settings entry
+type SettingsRepairKind string
+
+// Problems doctor --fix repairs
+const (
+	// RepairInvalidEvent is a hooks key that is not a Claude Code event
+	RepairInvalidEvent SettingsRepairKind = "invalid-event"
+	// RepairMissingGroup is a config:<group>:<job> command whose group is
+	// not defined in any hooks config file
+	RepairMissingGroup SettingsRepairKind = "missing-group"
+	// RepairStaleCommand is a command whose executable no longer exists
+	RepairStaleCommand SettingsRepairKind = "stale-command"
+	// RepairDuplicateMatcher is a matcher listed more than once for an
+	// event, or a command repeated within a matcher
+	RepairDuplicateMatcher SettingsRepairKind = "duplicate-matcher"
+)
+
+// SettingsRepair is one problem found in a settings file and what was (or,
+// in a dry run, would be) done about it
+type SettingsRepair struct {
+	Kind    SettingsRepairKind `json:"kind"`
+	Event   string             `json:"event"`
+	Matcher string             `json:"matcher,omitempty"`
+	Command string             `json:"command,omitempty"`
+	Action  string             `json:"action"`
+	// Fixed is false for problems that need a manual fix
+	Fixed bool `json:"fixed"`
+}
+
+// SettingsRepairOptions supplies what the repairs need from outside config
+type SettingsRepairOptions struct {
+	// Executable replaces missing blues-traveler binary paths; when empty
+	// those commands are only reported
+	Executable string
+	// Groups holds the groups defined in the hooks config; nil skips the
+	// missing-group check (e.g. when the hooks config failed to load)
+	Groups map[string]bool
+	// ResolveEvent maps an alias to its canonical event name, or returns ""
+	ResolveEvent func(string) string
+}
+
+// RepairSettingsFile checks the settings file at path and repairs what it
+// can. When apply is false, or nothing was fixed, the file is left
+// untouched. A missing file has nothing to repair.
+func RepairSettingsFile(path string, opts SettingsRepairOptions, apply bool) ([]SettingsRepair, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	if apply {
+		release, err := LockFile(path)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	settings, err := LoadSettings(path)
+	if err != nil {
+		return nil, err
+	}
+	unknown, err := splitHookEvents(path, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	repairs := RepairSettings(settings, unknown, opts)
+	if !apply {
+		return repairs, nil
+	}
+	for _, r := range repairs {
+		if r.Fixed {
+			if err := SaveSettings(path, settings); err != nil {
+				return repairs, err
+			}
+			break
+		}
+	}
+	return repairs, nil
+}
+
+// splitHookEvents re-reads the hooks in the settings file by exact event
+// name into settings, returning the keys that aren't event names with their
+// matchers. LoadSettings matches keys case-insensitively, so a "stop" key
+// would otherwise overwrite "Stop", and drops other unknown keys silently.
+func splitHookEvents(path string, settings *Settings) (map[string][]HookMatcher, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - controlled settings paths
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	var raw struct {
+		Hooks map[string]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse settings JSON: %w", err)
+	}
+	settings.Hooks = HooksConfig{}
+	slots := hookMatcherSlots(&settings.Hooks)
+	index := map[string]int{}
+	for i, name := range SettingsEventNames() {
+		index[name] = i
+	}
+	unknown := map[string][]HookMatcher{}
+	for key, value := range raw.Hooks {
+		var matchers []HookMatcher
+		if i, ok := index[key]; ok {
+			if err := json.Unmarshal(value, &matchers); err != nil {
+				return nil, fmt.Errorf("failed to parse %s hooks: %w", key, err)
+			}
+			*slots[i] = matchers
+			continue
+		}
+		// Entries that don't even parse as matchers are dropped along with the key
+		_ = json.Unmarshal(value, &matchers)
+		unknown[key] = matchers
+	}
+	return unknown, nil
+}
+
+// RepairSettings fixes settings in place. unknown holds matchers found under
+// hooks keys that are not event names: they move to the event the key was
+// meant to be (by alias or case-insensitive match) or are dropped.
+func RepairSettings(settings *Settings, unknown map[string][]HookMatcher, opts SettingsRepairOptions) []SettingsRepair {
+	var repairs []SettingsRepair
+	events := SettingsEventNames()
+	slots := hookMatcherSlots(&settings.Hooks)
+
+	// Invalid event names
+	keys := make([]string, 0, len(unknown))
+	for key := range unknown {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		target := -1
+		resolved := ""
+		if opts.ResolveEvent != nil {
+			resolved = opts.ResolveEvent(key)
+		}
+		for i, name := range events {
+			if name == resolved || strings.EqualFold(name, key) {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			repairs = append(repairs, SettingsRepair{
+				Kind: RepairInvalidEvent, Event: key, Fixed: true,
+				Action: fmt.Sprintf("removed %d matcher(s) under an unknown event", len(unknown[key])),
+			})
+			continue
+		}
+		*slots[target] = append(*slots[target], unknown[key]...)
+		repairs = append(repairs, SettingsRepair{
+			Kind: RepairInvalidEvent, Event: key, Fixed: true,
+			Action: fmt.Sprintf("moved %d matcher(s) to %s", len(unknown[key]), events[target]),
+		})
+	}
+
+	for i, slot := range slots {
+		event := events[i]
+		var kept []HookMatcher
+		for _, m := range *slot {
+			var hooks []HookCommand
+			for _, h := range m.Hooks {
+				repair, keep := repairHookCommand(&h, opts)
+				if repair != nil {
+					repair.Event, repair.Matcher = event, m.Matcher
+					repairs = append(repairs, *repair)
+				}
+				if keep {
+					hooks = append(hooks, h)
+				}
+			}
+			if len(hooks) > 0 {
+				m.Hooks = hooks
+				kept = append(kept, m)
+			}
+		}
+		merged, dupRepairs := mergeDuplicateMatchers(event, kept)
+		repairs = append(repairs, dupRepairs...)
+		*slot = merged
+	}
+	return repairs
+}
+
+// repairHookCommand checks one command. keep is false when the command
+// should be removed.
+func repairHookCommand(h *HookCommand, opts SettingsRepairOptions) (repair *SettingsRepair, keep bool) {
+	p, isHook := parseHookCommand(h.Command)
+	isOurs := isHook && (p.Legacy || isBluesTravelerExecutable(p.Executable))
+
+	if isOurs && opts.Groups != nil {
+		if group := configGroupFromHookType(p.HookType); group != "" && !opts.Groups[group] {
+			return &SettingsRepair{
+				Kind: RepairMissingGroup, Command: h.Command, Fixed: true,
+				Action: fmt.Sprintf("removed: group '%s' is not defined in any hooks config", group),
+			}, false
+		}
+	}
+
+	if isOurs {
+		if executableExists(p.Executable) {
+			return nil, true
+		}
+		if opts.Executable == "" {
+			return &SettingsRepair{
+				Kind: RepairStaleCommand, Command: h.Command,
+				Action: fmt.Sprintf("%s not found; reinstall the hook", p.Executable),
+			}, true
+		}
+		old := h.Command
+		h.Command = old[:p.execToken.Start] + opts.Executable + old[p.execToken.End:]
+		return &SettingsRepair{
+			Kind: RepairStaleCommand, Command: old, Fixed: true,
+			Action: fmt.Sprintf("%s not found; now runs %s", p.Executable, opts.Executable),
+		}, true
+	}
+
+	// Other tools' commands are only reported: their fix is not ours to guess
+	if tokens := tokenizeCommand(h.Command); len(tokens) > 0 {
+		exe := tokens[0].Value
+		if !strings.Contains(exe, "=") && strings.ContainsAny(exe, `/\`) && !executableExists(exe) {
+			return &SettingsRepair{
+				Kind: RepairStaleCommand, Command: h.Command,
+				Action: fmt.Sprintf("%s not found; fix or remove the entry manually", exe),
+			}, true
+		}
+	}
+	return nil, true
+}
+
+// executableExists reports whether a command's executable can be found.
+// Bare names are looked up on PATH; $VARS and a leading ~/ are expanded.
+func executableExists(exe string) bool {
+	if exe == "" {
+		return true
+	}
+	exe = os.ExpandEnv(exe)
+	if rest, ok := strings.CutPrefix(exe, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			exe = filepath.Join(home, rest)
+		}
+	}
+	if !strings.ContainsAny(exe, `/\`) {
+		_, err := exec.LookPath(exe)
+		return err == nil
+	}
+	_, err := os.Stat(exe)
+	return err == nil
+}
+
+// mergeDuplicateMatchers folds matchers with the same pattern into the first
+// one and drops commands repeated within a matcher
+func mergeDuplicateMatchers(event string, matchers []HookMatcher) ([]HookMatcher, []SettingsRepair) {
+	var repairs []SettingsRepair
+	var result []HookMatcher
+	index := map[string]int{}
+	for _, m := range matchers {
+		idx, seen := index[m.Matcher]
+		if !seen {
+			index[m.Matcher] = len(result)
+			result = append(result, HookMatcher{Matcher: m.Matcher})
+			idx = len(result) - 1
+		} else {
+			repairs = append(repairs, SettingsRepair{
+				Kind: RepairDuplicateMatcher, Event: event, Matcher: m.Matcher, Fixed: true,
+				Action: "merged a repeated matcher entry into the first one",
+			})
+		}
+		for _, h := range m.Hooks {
+			duplicate := false
+			for _, existing := range result[idx].Hooks {
+				if existing.Command == h.Command {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				repairs = append(repairs, SettingsRepair{
+					Kind: RepairDuplicateMatcher, Event: event, Matcher: m.Matcher, Command: h.Command, Fixed: true,
+					Action: "removed a repeated command",
+				})
+				continue
+			}
+			result[idx].Hooks = append(result[idx].Hooks, h)
+		}
+	}
+	return result, repairs
+}
+
+// hookMatcherSlots returns pointers to the matcher slices of hooks, in
+// SettingsEventNames order
+func hookMatcherSlots(hooks *HooksConfig) []*[]HookMatcher {
+	return []*[]HookMatcher{
+		&hooks.PreToolUse,
+		&hooks.PostToolUse,
+		&hooks.UserPromptSubmit,
+		&hooks.Notification,
+		&hooks.Stop,
+		&hooks.SubagentStop,
+		&hooks.PreCompact,
+		&hooks.SessionStart,
+		&hooks.SessionEnd,
+	}
+}
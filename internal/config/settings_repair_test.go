@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepairSettings(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "blues-traveler")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	gone := "/nonexistent/bin/blues-traveler"
+
+	settings := &Settings{Hooks: HooksConfig{
+		PreToolUse: []HookMatcher{
+			{Matcher: "Bash", Hooks: []HookCommand{{Type: "command", Command: exe + " hooks run security"}}},
+			{Matcher: "Bash", Hooks: []HookCommand{
+				{Type: "command", Command: exe + " hooks run security"},
+				{Type: "command", Command: gone + " hooks run find-blocker --log"},
+			}},
+			{Matcher: "*", Hooks: []HookCommand{{Type: "command", Command: exe + " hooks run config:gone:lint"}}},
+			{Matcher: "Write", Hooks: []HookCommand{{Type: "command", Command: "/nonexistent/other-tool check"}}},
+		},
+	}}
+	unknown := map[string][]HookMatcher{
+		"posttooluse":   {{Matcher: "Edit", Hooks: []HookCommand{{Type: "command", Command: exe + " hooks run format"}}}},
+		"afterFileEdit": {{Matcher: "", Hooks: []HookCommand{{Type: "command", Command: exe + " hooks run config:kept:fmt"}}}},
+		"Bogus":         {{Matcher: "", Hooks: []HookCommand{{Type: "command", Command: "true"}}}},
+	}
+	opts := SettingsRepairOptions{
+		Executable: exe,
+		Groups:     map[string]bool{"kept": true},
+		ResolveEvent: func(name string) string {
+			if name == "afterFileEdit" {
+				return "PostToolUse"
+			}
+			return ""
+		},
+	}
+
+	repairs := RepairSettings(settings, unknown, opts)
+	counts := map[SettingsRepairKind]int{}
+	manual := 0
+	for _, r := range repairs {
+		counts[r.Kind]++
+		if !r.Fixed {
+			manual++
+		}
+	}
+	want := map[SettingsRepairKind]int{
+		RepairInvalidEvent:     3,
+		RepairMissingGroup:     1,
+		RepairStaleCommand:     2,
+		RepairDuplicateMatcher: 2,
+	}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("%s repairs = %d, want %d (%+v)", kind, counts[kind], n, repairs)
+		}
+	}
+	if manual != 1 {
+		t.Errorf("manual repairs = %d, want 1", manual)
+	}
+
+	pre := settings.Hooks.PreToolUse
+	if len(pre) != 2 || pre[0].Matcher != "Bash" || pre[1].Matcher != "Write" {
+		t.Fatalf("unexpected PreToolUse matchers %+v", pre)
+	}
+	if len(pre[0].Hooks) != 2 || pre[0].Hooks[1].Command != exe+" hooks run find-blocker --log" {
+		t.Errorf("duplicates not merged or stale path not repointed: %+v", pre[0].Hooks)
+	}
+	if pre[1].Hooks[0].Command != "/nonexistent/other-tool check" {
+		t.Errorf("another tool's command was changed: %+v", pre[1].Hooks)
+	}
+	if post := settings.Hooks.PostToolUse; len(post) != 2 {
+		t.Errorf("expected both misnamed events moved to PostToolUse, got %+v", post)
+	}
+}
+
+func TestRepairSettingsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	content := `{
+  "model": "keep-me",
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "blues-traveler hooks run config:old:check"}]}
+    ],
+    "stop": [
+      {"hooks": [{"type": "command", "command": "echo done"}]}
+    ]
+  }
+}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := SettingsRepairOptions{Groups: map[string]bool{}}
+
+	repairs, err := RepairSettingsFile(path, opts, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repairs) != 2 {
+		t.Fatalf("expected 2 repairs, got %+v", repairs)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Error("dry run modified the settings file")
+	}
+
+	if _, err := RepairSettingsFile(path, opts, true); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := settings.Hooks.Stop
+	if len(stop) != 1 || len(stop[0].Hooks) != 1 || stop[0].Hooks[0].Command != "echo done" {
+		t.Errorf("unexpected Stop hooks after repair: %+v", stop)
+	}
+	if settings.Other["model"] != "keep-me" {
+		t.Error("unrelated settings were lost")
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"stop"`) {
+		t.Error("invalid event key was not removed")
+	}
+
+	repairs, err = RepairSettingsFile(path, opts, true)
+	if err != nil || len(repairs) != 0 {
+		t.Errorf("second repair should find nothing, got %+v %v", repairs, err)
+	}
+	if repairs, err := RepairSettingsFile(filepath.Join(dir, "missing.json"), opts, true); err != nil || repairs != nil {
+		t.Errorf("missing file: %+v %v", repairs, err)
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// RetargetedCommand is a settings entry whose blues-traveler binary changes
+type RetargetedCommand struct {
+	Event   string `json:"event"`
+	Matcher string `json:"matcher,omitempty"`
+	// From is the binary the entry ran; Command is the entry as it was
+	From    string `json:"from"`
+	Command string `json:"command"`
+}
+
+// RetargetExecutables points every blues-traveler command in settings that
+// runs the binary by an absolute path other than exe at exe, and returns
+// what changed. Bare names resolved through PATH, $VAR and ~/ paths, and
+// paths that resolve to the same file as exe are left alone.
+func RetargetExecutables(settings *Settings, exe string) []RetargetedCommand {
+	if settings == nil || exe == "" {
+		return nil
+	}
+	var changed []RetargetedCommand
+	events := SettingsEventNames()
+	for i, slot := range hookMatcherSlots(&settings.Hooks) {
+		for mi := range *slot {
+			m := &(*slot)[mi]
+			for hi := range m.Hooks {
+				h := &m.Hooks[hi]
+				p, ok := parseHookCommand(h.Command)
+				if !ok || !(p.Legacy || isBluesTravelerExecutable(p.Executable)) || !isAbsoluteExecutable(p.Executable) {
+					continue
+				}
+				if sameExecutable(p.Executable, strings.Trim(exe, `"`)) || SameFile(p.Executable, strings.Trim(exe, `"`)) {
+					continue
+				}
+				changed = append(changed, RetargetedCommand{Event: events[i], Matcher: m.Matcher, From: p.Executable, Command: h.Command})
+				h.Command = h.Command[:p.execToken.Start] + exe + h.Command[p.execToken.End:]
+			}
+		}
+	}
+	return changed
+}
+
+// isAbsoluteExecutable reports whether exe is written as an absolute path,
+// on this OS or in Windows form
+func isAbsoluteExecutable(exe string) bool {
+	if filepath.IsAbs(exe) || strings.HasPrefix(exe, "/") {
+		return true
+	}
+	return len(exe) > 2 && exe[1] == ':' && (exe[2] == '\\' || exe[2] == '/')
+}
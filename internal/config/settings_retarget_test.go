@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRetargetExecutables(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "bin", "blues-traveler")
+	if err := os.MkdirAll(filepath.Dir(current), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(current, []byte("bin"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(current, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	settings := &Settings{Hooks: HooksConfig{
+		PreToolUse: []HookMatcher{{Matcher: "*", Hooks: []HookCommand{
+			{Type: "command", Command: "/old/cellar/1.0/blues-traveler hooks run security"},
+			{Type: "command", Command: current + " hooks run debug --log"},
+			{Type: "command", Command: link + " hooks run audit"},
+			{Type: "command", Command: "blues-traveler hooks run format"},
+			{Type: "command", Command: "/usr/bin/other-tool --flag"},
+		}}},
+		Stop: []HookMatcher{{Hooks: []HookCommand{
+			{Type: "command", Command: `"/Old Place/blues-traveler" hooks run pr-readiness`},
+		}}},
+	}}
+
+	changed := RetargetExecutables(settings, current)
+	if len(changed) != 2 {
+		t.Fatalf("expected 2 retargeted entries, got %+v", changed)
+	}
+	if changed[0].Event != "PreToolUse" || changed[0].From != "/old/cellar/1.0/blues-traveler" {
+		t.Errorf("unexpected first change %+v", changed[0])
+	}
+	if got := settings.Hooks.PreToolUse[0].Hooks[0].Command; got != current+" hooks run security" {
+		t.Errorf("security entry = %q", got)
+	}
+	if got := settings.Hooks.Stop[0].Hooks[0].Command; got != current+" hooks run pr-readiness" {
+		t.Errorf("quoted entry = %q", got)
+	}
+	for i, want := range []string{current + " hooks run debug --log", link + " hooks run audit", "blues-traveler hooks run format", "/usr/bin/other-tool --flag"} {
+		if got := settings.Hooks.PreToolUse[0].Hooks[i+1].Command; got != want {
+			t.Errorf("entry %d changed to %q, want %q", i+1, got, want)
+		}
+	}
+}
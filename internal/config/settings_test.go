@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -293,3 +294,141 @@ func TestRemoveHookTypeFromSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveHookTypeFromScope(t *testing.T) {
+	newSettings := func() *Settings {
+		return &Settings{Hooks: HooksConfig{
+			PreToolUse: []HookMatcher{
+				{Matcher: "*", Hooks: []HookCommand{{Type: "command", Command: "/bin/blues-traveler hooks run security"}}},
+			},
+			PostToolUse: []HookMatcher{
+				{Matcher: "Edit,Write", Hooks: []HookCommand{
+					{Type: "command", Command: "/bin/blues-traveler hooks run security --log"},
+					{Type: "command", Command: "/bin/blues-traveler hooks run format"},
+				}},
+				{Matcher: "Bash", Hooks: []HookCommand{{Type: "command", Command: "/bin/blues-traveler hooks run security"}}},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name           string
+		event, matcher string
+		want           int
+		pre, post      int // hooks left per event
+	}{
+		{name: "any event", want: 3, pre: 0, post: 1},
+		{name: "event only", event: "PostToolUse", want: 2, pre: 1, post: 1},
+		{name: "event and matcher", event: "PostToolUse", matcher: "Edit,Write", want: 1, pre: 1, post: 2},
+		{name: "matcher only", matcher: "*", want: 1, pre: 0, post: 3},
+		{name: "matcher must be exact", event: "PostToolUse", matcher: "Edit", want: 0, pre: 1, post: 3},
+	}
+	count := func(matchers []HookMatcher) int {
+		n := 0
+		for _, m := range matchers {
+			n += len(m.Hooks)
+		}
+		return n
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSettings()
+			if got := RemoveHookTypeFromScope(s, "security", tt.event, tt.matcher); got != tt.want {
+				t.Errorf("removed %d, want %d", got, tt.want)
+			}
+			if pre, post := count(s.Hooks.PreToolUse), count(s.Hooks.PostToolUse); pre != tt.pre || post != tt.post {
+				t.Errorf("left pre=%d post=%d, want pre=%d post=%d", pre, post, tt.pre, tt.post)
+			}
+		})
+	}
+}
+
+func TestIsPluginLanguageEnabled(t *testing.T) {
+	project := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(project)
+
+	disabled := false
+	s := &Settings{Plugins: map[string]PluginConfig{
+		"imports": {Languages: map[string]bool{"python": false, "go": true}},
+		"format":  {Enabled: &disabled},
+	}}
+	if err := SaveSettings(filepath.Join(project, ".claude", "settings.json"), s); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+
+	tests := []struct {
+		plugin, language string
+		want             bool
+	}{
+		{"imports", "python", false},
+		{"imports", "go", true},
+		{"imports", "javascript", true},
+		{"format", "python", true},
+		{"unknown", "go", true},
+	}
+	for _, tt := range tests {
+		if got := IsPluginLanguageEnabled(tt.plugin, tt.language); got != tt.want {
+			t.Errorf("IsPluginLanguageEnabled(%q, %q) = %v, want %v", tt.plugin, tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLegacyCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     string
+		want        string
+		wantChanged bool
+	}{
+		{"bare legacy", "blues-traveler run security", "blues-traveler hooks run security", true},
+		{"path with flags", "/usr/local/bin/blues-traveler run debug --log", "/usr/local/bin/blues-traveler hooks run debug --log", true},
+		{"quoted path", `"/Users/me/My Tools/blues-traveler" run format`, `"/Users/me/My Tools/blues-traveler" hooks run format`, true},
+		{"windows exe", `C:\bin\blues-traveler.exe run vet`, `C:\bin\blues-traveler.exe hooks run vet`, true},
+		{"config hook", "blues-traveler run config:python:lint", "blues-traveler hooks run config:python:lint", true},
+		{"already canonical", "blues-traveler hooks run security", "blues-traveler hooks run security", false},
+		{"unrelated command", "npm run build", "npm run build", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := NormalizeLegacyCommand(tt.command)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("NormalizeLegacyCommand(%q) = (%q, %v), want (%q, %v)", tt.command, got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestSaveSettings_NormalizesLegacyCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	s := &Settings{Hooks: HooksConfig{
+		PreToolUse: []HookMatcher{{Matcher: "*", Hooks: []HookCommand{
+			{Type: "command", Command: "/bin/blues-traveler run security"},
+			{Type: "command", Command: "echo unrelated"},
+		}}},
+		SessionEnd: []HookMatcher{{Hooks: []HookCommand{{Type: "command", Command: "blues-traveler run audit"}}}},
+	}}
+	if n := CountLegacyCommands(s); n != 2 {
+		t.Fatalf("CountLegacyCommands() = %d, want 2", n)
+	}
+
+	if err := SaveSettings(path, s); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if got := loaded.Hooks.PreToolUse[0].Hooks[0].Command; got != "/bin/blues-traveler hooks run security" {
+		t.Errorf("PreToolUse command = %q, want canonical form", got)
+	}
+	if got := loaded.Hooks.PreToolUse[0].Hooks[1].Command; got != "echo unrelated" {
+		t.Errorf("unrelated command changed to %q", got)
+	}
+	if got := loaded.Hooks.SessionEnd[0].Hooks[0].Command; got != "blues-traveler hooks run audit" {
+		t.Errorf("SessionEnd command = %q, want canonical form", got)
+	}
+	if n := CountLegacyCommands(loaded); n != 0 {
+		t.Errorf("expected no legacy commands after save, got %d", n)
+	}
+}
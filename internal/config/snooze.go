@@ -0,0 +1,107 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnoozeEntry describes an active snooze window
+type SnoozeEntry struct {
+	Key   string
+	Until time.Time
+}
+
+// GroupSnoozeKey returns the plugins entry used to snooze every job of a
+// custom hook group at once
+func GroupSnoozeKey(group string) string {
+	return "config:" + group
+}
+
+// SetSnooze silences key until the given time. Expired snoozes are dropped
+// while we are here so the settings file does not accumulate stale entries.
+func (s *Settings) SetSnooze(key string, until time.Time) {
+	s.PruneExpiredSnoozes(time.Now())
+	if s.Plugins == nil {
+		s.Plugins = make(map[string]PluginConfig)
+	}
+	cfg := s.Plugins[key]
+	u := until.UTC().Truncate(time.Second)
+	cfg.SnoozedUntil = &u
+	s.Plugins[key] = cfg
+}
+
+// ClearSnooze removes a snooze for key, reporting whether one was set
+func (s *Settings) ClearSnooze(key string) bool {
+	cfg, ok := s.Plugins[key]
+	if !ok || cfg.SnoozedUntil == nil {
+		return false
+	}
+	cfg.SnoozedUntil = nil
+	s.setOrDropPlugin(key, cfg)
+	return true
+}
+
+// PruneExpiredSnoozes removes snoozes that ended before now and returns how
+// many were removed
+func (s *Settings) PruneExpiredSnoozes(now time.Time) int {
+	removed := 0
+	for key, cfg := range s.Plugins {
+		if cfg.SnoozedUntil != nil && !cfg.SnoozedUntil.After(now) {
+			cfg.SnoozedUntil = nil
+			s.setOrDropPlugin(key, cfg)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ActiveSnoozes lists unexpired snoozes sorted by key
+func (s *Settings) ActiveSnoozes(now time.Time) []SnoozeEntry {
+	var entries []SnoozeEntry
+	for key, cfg := range s.Plugins {
+		if cfg.SnoozedUntil != nil && cfg.SnoozedUntil.After(now) {
+			entries = append(entries, SnoozeEntry{Key: key, Until: *cfg.SnoozedUntil})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// SnoozeFor returns the active snooze covering key, if any. A custom hook key
+// (config:<group>:<job>) is also covered by a snooze on its group.
+func (s *Settings) SnoozeFor(key string, now time.Time) (SnoozeEntry, bool) {
+	candidates := []string{key}
+	if parts := strings.SplitN(key, ":", 3); len(parts) == 3 && parts[0] == "config" {
+		candidates = append(candidates, GroupSnoozeKey(parts[1]))
+	}
+	for _, c := range candidates {
+		if cfg, ok := s.Plugins[c]; ok && cfg.SnoozedUntil != nil && cfg.SnoozedUntil.After(now) {
+			return SnoozeEntry{Key: c, Until: *cfg.SnoozedUntil}, true
+		}
+	}
+	return SnoozeEntry{}, false
+}
+
+// ActiveSnooze checks project settings for a snooze covering key. Snoozes are
+// project-scoped; errors loading settings mean "not snoozed".
+func ActiveSnooze(key string) (SnoozeEntry, bool) {
+	path, err := GetSettingsPath(false)
+	if err != nil {
+		return SnoozeEntry{}, false
+	}
+	s, err := LoadSettings(path)
+	if err != nil {
+		return SnoozeEntry{}, false
+	}
+	return s.SnoozeFor(key, time.Now())
+}
+
+// setOrDropPlugin stores cfg, removing the entry entirely once it carries no settings
+func (s *Settings) setOrDropPlugin(key string, cfg PluginConfig) {
+	if cfg.Enabled == nil && len(cfg.Languages) == 0 && cfg.SnoozedUntil == nil && !cfg.OnlyNewFindings && len(cfg.DisabledEntries) == 0 {
+		delete(s.Plugins, key)
+		return
+	}
+	s.Plugins[key] = cfg
+}
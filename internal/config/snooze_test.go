@@ -0,0 +1,83 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnoozeFor_KeyAndGroup(t *testing.T) {
+	now := time.Now()
+	s := &Settings{}
+	s.SetSnooze("format", now.Add(time.Hour))
+	s.SetSnooze(GroupSnoozeKey("lint"), now.Add(time.Hour))
+
+	tests := []struct {
+		key     string
+		want    bool
+		wantKey string
+	}{
+		{"format", true, "format"},
+		{"security", false, ""},
+		{"config:lint:eslint", true, "config:lint"},
+		{"config:other:job", false, ""},
+	}
+	for _, tt := range tests {
+		entry, ok := s.SnoozeFor(tt.key, now)
+		if ok != tt.want || entry.Key != tt.wantKey {
+			t.Errorf("SnoozeFor(%q) = (%q, %v), want (%q, %v)", tt.key, entry.Key, ok, tt.wantKey, tt.want)
+		}
+	}
+
+	if _, ok := s.SnoozeFor("format", now.Add(2*time.Hour)); ok {
+		t.Error("snooze should have expired")
+	}
+}
+
+func TestSnooze_ExpiryAndClearKeepOtherSettings(t *testing.T) {
+	disabled := false
+	now := time.Now()
+	s := &Settings{Plugins: map[string]PluginConfig{
+		"audit": {Enabled: &disabled},
+		"stale": {SnoozedUntil: ptrTime(now.Add(-time.Minute))},
+	}}
+
+	s.SetSnooze("audit", now.Add(time.Hour))
+	if _, ok := s.Plugins["stale"]; ok {
+		t.Error("SetSnooze should prune expired entries")
+	}
+	if got := s.ActiveSnoozes(now); len(got) != 1 || got[0].Key != "audit" {
+		t.Fatalf("ActiveSnoozes = %+v, want [audit]", got)
+	}
+
+	if !s.ClearSnooze("audit") {
+		t.Fatal("ClearSnooze should report an existing snooze")
+	}
+	if cfg, ok := s.Plugins["audit"]; !ok || cfg.Enabled == nil || *cfg.Enabled {
+		t.Error("clearing a snooze must keep the enabled toggle")
+	}
+	if s.ClearSnooze("audit") {
+		t.Error("second ClearSnooze should report nothing to clear")
+	}
+}
+
+func TestSnooze_RoundTripsThroughSettingsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	until := time.Now().Add(90 * time.Minute)
+
+	s := &Settings{}
+	s.SetSnooze("format", until)
+	if err := SaveSettings(path, s); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := loaded.SnoozeFor("format", time.Now())
+	if !ok || !entry.Until.Equal(until.UTC().Truncate(time.Second)) {
+		t.Errorf("loaded snooze = (%v, %v), want until %v", entry.Until, ok, until)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+)
+
+// SyncOptions controls how SyncHooksToSettings writes the hooks config into
+// settings
+type SyncOptions struct {
+	// Group and Event limit the sync to one group or event when set
+	Group string
+	Event string
+	// Matcher is used for every event but PostToolUse, which uses PostMatcher
+	Matcher     string
+	PostMatcher string
+	// Timeout overrides each job's timeout, in seconds, when positive
+	Timeout int
+	// ExecPath is the blues-traveler binary the entries run
+	ExecPath string
+	// Global selects the global scope's record of built-in installs
+	Global bool
+}
+
+// SyncChangeKind says what a SyncChange did to settings
+type SyncChangeKind string
+
+const (
+	// SyncAdded is an entry written for a job or a recorded built-in
+	SyncAdded SyncChangeKind = "added"
+	// SyncPruned is a group's old entries, removed before it is written again
+	SyncPruned SyncChangeKind = "pruned"
+	// SyncCleaned is the entries of a group no longer in the hooks config
+	SyncCleaned SyncChangeKind = "cleaned"
+	// SyncSkipped is a step that could not run; Err says why
+	SyncSkipped SyncChangeKind = "skipped"
+)
+
+// SyncChange is one step of a sync, reported as it happens so callers can
+// show progress
+type SyncChange struct {
+	Kind    SyncChangeKind
+	Group   string
+	Event   string
+	Matcher string
+	Command string
+	// Count is the entries a pruned or cleaned change removed
+	Count int
+	// Duplicate is set on an added entry settings already had
+	Duplicate bool
+	Err       error
+}
+
+// ConfigHookCommand is the settings command that runs job of group
+func ConfigHookCommand(execPath, group, job string) string {
+	return fmt.Sprintf("%s hooks run config:%s:%s", execPath, group, job)
+}
+
+// JobTimeout returns override when positive, else the job's own timeout,
+// else nil for Claude Code's default
+func JobTimeout(override, jobTimeout int) *int {
+	if override > 0 {
+		return &override
+	}
+	if jobTimeout > 0 {
+		return &jobTimeout
+	}
+	return nil
+}
+
+// MatcherForEvent picks the matcher for event's entries
+func MatcherForEvent(event, postMatcher, matcher string) string {
+	if event == "PostToolUse" {
+		return postMatcher
+	}
+	return matcher
+}
+
+// SyncHooksToSettings makes settings match the hooks config: entries of
+// groups gone from the config are removed, each group's entries are
+// rewritten from its current jobs, and built-in installs recorded by
+// 'hooks install' are restored. report, when not nil, sees every change.
+// It returns how many entries changed.
+func SyncHooksToSettings(settings *Settings, hooksCfg *CustomHooksConfig, opts SyncOptions, report func(SyncChange)) int {
+	if report == nil {
+		report = func(SyncChange) {}
+	}
+	changed := cleanupStaleGroups(settings, hooksCfg, opts, report)
+	if hooksCfg != nil {
+		for name, group := range *hooksCfg {
+			if opts.Group != "" && name != opts.Group {
+				continue
+			}
+			if removed := RemoveConfigGroupFromSettings(settings, name, opts.Event); removed > 0 {
+				report(SyncChange{Kind: SyncPruned, Group: name, Event: opts.Event, Count: removed})
+			}
+			changed += syncGroup(settings, name, group, opts, report)
+		}
+	}
+	if opts.Group == "" || opts.Group == BuiltinGroupName {
+		changed += SyncBuiltinGroup(settings, opts, report)
+	}
+	return changed
+}
+
+// cleanupStaleGroups removes the entries of groups in settings that the
+// hooks config no longer defines
+func cleanupStaleGroups(settings *Settings, hooksCfg *CustomHooksConfig, opts SyncOptions, report func(SyncChange)) int {
+	defined := configGroupNames(hooksCfg)
+	changed := 0
+	for name := range GetConfigGroupsInSettings(settings) {
+		if opts.Group != "" && name != opts.Group {
+			continue
+		}
+		if _, ok := defined[name]; ok {
+			continue
+		}
+		if removed := RemoveConfigGroupFromSettings(settings, name, opts.Event); removed > 0 {
+			report(SyncChange{Kind: SyncCleaned, Group: name, Event: opts.Event, Count: removed})
+			changed += removed
+		}
+	}
+	return changed
+}
+
+// configGroupNames lists the groups the hooks config names, including
+// ones declared without a body
+func configGroupNames(hooksCfg *CustomHooksConfig) map[string]struct{} {
+	names := map[string]struct{}{}
+	if hooksCfg != nil {
+		for name := range *hooksCfg {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// syncGroup writes one group's jobs into settings
+func syncGroup(settings *Settings, name string, group *HookGroup, opts SyncOptions, report func(SyncChange)) int {
+	if group == nil {
+		return 0
+	}
+	changed := 0
+	for event, ev := range group.Events {
+		if opts.Event != "" && event != opts.Event {
+			continue
+		}
+		for _, job := range ev.Jobs {
+			if job.Name == "" {
+				continue
+			}
+			command := ConfigHookCommand(opts.ExecPath, name, job.Name)
+			matcher := MatcherForEvent(event, opts.PostMatcher, opts.Matcher)
+			result := AddHookToSettings(settings, event, matcher, command, JobTimeout(opts.Timeout, job.Timeout))
+			if !result.WasDuplicate {
+				changed++
+			}
+			report(SyncChange{Kind: SyncAdded, Group: name, Event: event, Matcher: matcher, Command: command, Duplicate: result.WasDuplicate})
+		}
+	}
+	return changed
+}
+
+// SyncBuiltinGroup makes the built-in hooks in settings match those
+// recorded by 'hooks install': recorded entries that went missing are
+// restored and unrecorded ones are pruned. Scopes with no record are left
+// alone, since their built-ins were installed before installs were tracked.
+func SyncBuiltinGroup(settings *Settings, opts SyncOptions, report func(SyncChange)) int {
+	if report == nil {
+		report = func(SyncChange) {}
+	}
+	path, err := BuiltinManifestPath(opts.Global)
+	if err != nil {
+		report(SyncChange{Kind: SyncSkipped, Group: BuiltinGroupName, Err: err})
+		return 0
+	}
+	manifest, err := LoadBuiltinManifest(path)
+	if err != nil {
+		report(SyncChange{Kind: SyncSkipped, Group: BuiltinGroupName, Err: err})
+		return 0
+	}
+	if manifest == nil {
+		return 0
+	}
+
+	key := func(h BuiltinInstall) string { return h.Event + "\x00" + h.Matcher + "\x00" + h.Command }
+	before := map[string]bool{}
+	for _, h := range BuiltinInstallsInSettings(settings) {
+		before[key(h)] = true
+	}
+	removed := RemoveBuiltinHooksFromSettings(settings, opts.Event)
+
+	changed := 0
+	kept := 0
+	for _, h := range manifest.Hooks {
+		if opts.Event != "" && h.Event != opts.Event {
+			continue
+		}
+		AddHookToSettings(settings, h.Event, h.Matcher, h.Command, h.Timeout)
+		if before[key(h)] {
+			kept++
+			continue
+		}
+		changed++
+		report(SyncChange{Kind: SyncAdded, Group: BuiltinGroupName, Event: h.Event, Matcher: h.Matcher, Command: h.Command})
+	}
+	if pruned := removed - kept; pruned > 0 {
+		report(SyncChange{Kind: SyncPruned, Group: BuiltinGroupName, Event: opts.Event, Count: pruned})
+		changed += pruned
+	}
+	return changed
+}
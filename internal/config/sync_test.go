@@ -0,0 +1,80 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSyncHooksToSettings(t *testing.T) {
+	t.Chdir(t.TempDir())
+	settings := &Settings{}
+	AddHookToSettings(settings, "PreToolUse", "*", "blues-traveler hooks run config:go:old", nil)
+	AddHookToSettings(settings, "Stop", "*", "blues-traveler hooks run config:gone:lint", nil)
+	AddHookToSettings(settings, "PreToolUse", "Bash", "blues-traveler hooks run security", nil)
+
+	hooksCfg := &CustomHooksConfig{
+		"go": &HookGroup{Events: map[string]*EventConfig{
+			"PreToolUse":  {Jobs: []HookJob{{Name: "vet", Run: "go vet ./..."}}},
+			"PostToolUse": {Jobs: []HookJob{{Name: "fmt", Run: "gofmt -l .", Timeout: 5}, {Name: ""}}},
+		}},
+	}
+	opts := SyncOptions{Matcher: "*", PostMatcher: "Edit,Write", ExecPath: "blues-traveler"}
+
+	var changes []string
+	changed := SyncHooksToSettings(settings, hooksCfg, opts, func(c SyncChange) {
+		changes = append(changes, string(c.Kind)+" "+c.Group)
+	})
+	sort.Strings(changes)
+	if want := "added go,added go,cleaned gone,pruned go"; strings.Join(changes, ",") != want {
+		t.Errorf("reported %v, want %s", changes, want)
+	}
+	// gone's entry and the two new jobs; go's pruned entry is rewritten
+	if changed != 3 {
+		t.Errorf("expected 3 changes, got %d", changed)
+	}
+
+	var got []string
+	for _, h := range InstalledHooks(settings.Hooks) {
+		got = append(got, h.Event+" "+h.Matcher+" "+h.Command)
+	}
+	sort.Strings(got)
+	want := []string{
+		"PostToolUse Edit,Write blues-traveler hooks run config:go:fmt",
+		"PreToolUse * blues-traveler hooks run config:go:vet",
+		"PreToolUse Bash blues-traveler hooks run security",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("settings have\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A second sync prunes the group and writes its entries again
+	if changed := SyncHooksToSettings(settings, hooksCfg, opts, nil); changed != 2 {
+		t.Errorf("expected the 2 rewritten entries on resync, got %d", changed)
+	}
+}
+
+func TestSyncHooksToSettings_Filters(t *testing.T) {
+	t.Chdir(t.TempDir())
+	settings := &Settings{}
+	AddHookToSettings(settings, "Stop", "*", "blues-traveler hooks run config:gone:lint", nil)
+	hooksCfg := &CustomHooksConfig{
+		"go": &HookGroup{Events: map[string]*EventConfig{
+			"PreToolUse": {Jobs: []HookJob{{Name: "vet", Run: "go vet ./..."}}},
+			"Stop":       {Jobs: []HookJob{{Name: "test", Run: "go test ./..."}}},
+		}},
+	}
+
+	changed := SyncHooksToSettings(settings, hooksCfg, SyncOptions{Group: "go", Event: "Stop", Matcher: "*", ExecPath: "blues-traveler"}, nil)
+	if changed != 1 {
+		t.Fatalf("expected 1 change, got %d", changed)
+	}
+	var got []string
+	for _, h := range InstalledHooks(settings.Hooks) {
+		got = append(got, h.Event+" "+h.HookType)
+	}
+	sort.Strings(got)
+	if want := "Stop config:go:test,Stop config:gone:lint"; strings.Join(got, ",") != want {
+		t.Errorf("settings have %v, want %s", got, want)
+	}
+}
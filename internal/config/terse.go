@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerseEnv overrides the terseOutput setting for one run, e.g. BT_TERSE=1
+const TerseEnv = "BT_TERSE"
+
+// GetTerseOutput reports whether hooks keep their output terse: BT_TERSE
+// when it holds a boolean, else the project config's terseOutput, else the
+// global one, else off
+func GetTerseOutput() bool {
+	if on, err := strconv.ParseBool(os.Getenv(TerseEnv)); err == nil {
+		return on
+	}
+	for _, global := range []bool{false, true} {
+		path, err := GetLogConfigPath(global)
+		if err != nil {
+			continue
+		}
+		cfg, err := LoadLogConfig(path)
+		if err != nil || cfg.TerseOutput == nil {
+			continue
+		}
+		return *cfg.TerseOutput
+	}
+	return false
+}
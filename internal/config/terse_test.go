@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetTerseOutput(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(TerseEnv, "")
+	project := t.TempDir()
+	t.Chdir(project)
+
+	if GetTerseOutput() {
+		t.Fatal("terse output should be off without config")
+	}
+
+	global := filepath.Join(home, ".claude", "hooks", "blues-traveler-config.json")
+	if err := os.MkdirAll(filepath.Dir(global), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(global, []byte(`{"terseOutput":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !GetTerseOutput() {
+		t.Error("global terseOutput should apply to a project without the key")
+	}
+
+	t.Setenv(TerseEnv, "0")
+	if GetTerseOutput() {
+		t.Errorf("%s=0 should override the config", TerseEnv)
+	}
+}
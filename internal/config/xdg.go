@@ -16,6 +16,8 @@ import (
 const (
 	FormatJSON = "json"
 	FormatTOML = "toml"
+	// FormatYAML is only used for hooks config files
+	FormatYAML = "yaml"
 )
 
 // XDGConfig handles XDG Base Directory Specification compliant configuration
@@ -36,8 +38,12 @@ type ProjectConfig struct {
 	ConfigFormat string `json:"configFormat"`
 }
 
-// NewXDGConfig creates a new XDG configuration manager
+// NewXDGConfig creates a new XDG configuration manager. A BT_CONFIG_DIR
+// sandbox replaces the XDG directory with its xdg/ subdirectory.
 func NewXDGConfig() *XDGConfig {
+	if dir := SandboxDir(SandboxXDGConfigDir); dir != "" {
+		return &XDGConfig{BaseDir: dir}
+	}
 	baseDir := os.Getenv("XDG_CONFIG_HOME")
 	if baseDir == "" {
 		homeDir, err := os.UserHomeDir()
@@ -54,6 +60,25 @@ func NewXDGConfig() *XDGConfig {
 	}
 }
 
+// XDGCacheDir returns blues-traveler's directory under $XDG_CACHE_HOME
+// (~/.cache by default) for data that can be downloaded again, or cache/ in
+// a BT_CONFIG_DIR sandbox
+func XDGCacheDir() string {
+	if dir := SandboxDir(SandboxXDGCacheDir); dir != "" {
+		return dir
+	}
+	baseDir := os.Getenv("XDG_CACHE_HOME")
+	if baseDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			baseDir = ".cache"
+		} else {
+			baseDir = filepath.Join(homeDir, ".cache")
+		}
+	}
+	return filepath.Join(baseDir, "blues-traveler")
+}
+
 // GetConfigDir returns the XDG configuration directory for blues-traveler
 func (x *XDGConfig) GetConfigDir() string {
 	return x.BaseDir
@@ -171,7 +196,8 @@ func (x *XDGConfig) SaveRegistry(registry *ProjectRegistry) error {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
 
-	if err := os.WriteFile(registryPath, data, 0o600); err != nil {
+	// Readers in other processes must never see a half-written registry
+	if err := writeFileAtomic(registryPath, data, 0o600); err != nil {
 		return fmt.Errorf("failed to write registry file: %w", err)
 	}
 
@@ -180,6 +206,12 @@ func (x *XDGConfig) SaveRegistry(registry *ProjectRegistry) error {
 
 // RegisterProject adds or updates a project in the registry
 func (x *XDGConfig) RegisterProject(projectPath, configFormat string) error {
+	release, err := LockFile(x.GetRegistryPath())
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	registry, err := x.LoadRegistry()
 	if err != nil {
 		return err
@@ -391,6 +423,12 @@ func (x *XDGConfig) SaveGlobalConfig(configData map[string]interface{}, format s
 
 // CleanupOrphanedConfigs removes configuration files for projects that no longer exist
 func (x *XDGConfig) CleanupOrphanedConfigs() ([]string, error) {
+	release, err := LockFile(x.GetRegistryPath())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	registry, err := x.LoadRegistry()
 	if err != nil {
 		return nil, err
@@ -44,12 +44,14 @@ const (
 	ScopeGlobal  = "global"
 
 	// Tool names
-	ToolBash  = "Bash"
-	ToolEdit  = "Edit"
-	ToolWrite = "Write"
-	ToolRead  = "Read"
-	ToolGlob  = "Glob"
-	ToolGrep  = "Grep"
+	ToolBash         = "Bash"
+	ToolEdit         = "Edit"
+	ToolMultiEdit    = "MultiEdit"
+	ToolWrite        = "Write"
+	ToolNotebookEdit = "NotebookEdit"
+	ToolRead         = "Read"
+	ToolGlob         = "Glob"
+	ToolGrep         = "Grep"
 )
 
 // GetConfigPath returns the full config file path
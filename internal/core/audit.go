@@ -0,0 +1,312 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// AuditSchemaVersion is written to every audit record and the index.
+	// Bump it when a field changes meaning so old records can still be read.
+	AuditSchemaVersion = 1
+	// AuditActorAgent is the actor for every tool call the agent makes
+	AuditActorAgent = "agent"
+
+	// auditSubDir is the directory under .claude/ holding audit records
+	auditSubDir = "audit"
+	// auditIndexFile summarizes each day file so queries can skip it
+	auditIndexFile = "index.json"
+	// auditDayLayout names day files (audit-2006-01-02.jsonl)
+	auditDayLayout = "2006-01-02"
+	// auditLockName serializes appends from hooks running in parallel
+	auditLockName = "audit-log"
+	auditLockWait = 2 * time.Second
+)
+
+// Audit decisions describe what happened to the tool call
+const (
+	// AuditDecisionRequested is recorded before the tool runs
+	AuditDecisionRequested = "requested"
+	// AuditDecisionExecuted is recorded after the tool ran
+	AuditDecisionExecuted = "executed"
+	// AuditDecisionFailed is recorded after the tool reported an error
+	AuditDecisionFailed = "failed"
+)
+
+// AuditRecord is one normalized audit entry, stored one per line in the
+// day file for its timestamp
+type AuditRecord struct {
+	Schema    int       `json:"schema"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	SessionID string    `json:"session_id,omitempty"`
+	// Event is the Claude Code event (PreToolUse, PostToolUse)
+	Event string `json:"event"`
+	Tool  string `json:"tool"`
+	// Inputs summarizes the tool input: commands, paths and patterns, with
+	// file contents reduced to their length
+	Inputs   map[string]interface{} `json:"inputs,omitempty"`
+	Decision string                 `json:"decision"`
+	Cwd      string                 `json:"cwd,omitempty"`
+}
+
+// AuditDay summarizes one day file in the index
+type AuditDay struct {
+	File    string         `json:"file"`
+	Records int            `json:"records"`
+	First   time.Time      `json:"first"`
+	Last    time.Time      `json:"last"`
+	Tools   map[string]int `json:"tools"`
+}
+
+// AuditIndex lists the day files, keyed by date
+type AuditIndex struct {
+	Schema int                  `json:"schema"`
+	Days   map[string]*AuditDay `json:"days"`
+}
+
+// AuditFilter selects records in QueryAuditRecords. Zero fields match all.
+type AuditFilter struct {
+	Tool      string
+	Event     string
+	Decision  string
+	SessionID string
+	Since     time.Time
+	Until     time.Time
+	// Limit keeps only the most recent records when positive
+	Limit int
+}
+
+// Matches reports whether rec passes the filter
+func (f AuditFilter) Matches(rec AuditRecord) bool {
+	if f.Tool != "" && !strings.EqualFold(rec.Tool, f.Tool) {
+		return false
+	}
+	if f.Event != "" && !strings.EqualFold(rec.Event, f.Event) {
+		return false
+	}
+	if f.Decision != "" && !strings.EqualFold(rec.Decision, f.Decision) {
+		return false
+	}
+	if f.SessionID != "" && rec.SessionID != f.SessionID {
+		return false
+	}
+	if !f.Since.IsZero() && rec.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && rec.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// skipsDay reports whether no record in day can pass the filter
+func (f AuditFilter) skipsDay(day *AuditDay) bool {
+	if !f.Since.IsZero() && day.Last.Before(f.Since) {
+		return true
+	}
+	if !f.Until.IsZero() && day.First.After(f.Until) {
+		return true
+	}
+	if f.Tool != "" {
+		for tool := range day.Tools {
+			if strings.EqualFold(tool, f.Tool) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// AuditDir returns the project's audit directory (.claude/audit).
+// BT_AUDIT_DIR overrides the location.
+func AuditDir() (string, error) {
+	if dir := os.Getenv("BT_AUDIT_DIR"); dir != "" {
+		return dir, nil
+	}
+	claudeDir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(claudeDir, auditSubDir), nil
+}
+
+// AppendAuditRecord writes rec to its day file and updates the index
+func AppendAuditRecord(rec AuditRecord) error {
+	dir, err := AuditDir()
+	if err != nil {
+		return err
+	}
+	if rec.Schema == 0 {
+		rec.Schema = AuditSchemaVersion
+	}
+	if rec.Actor == "" {
+		rec.Actor = AuditActorAgent
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	rec.Time = rec.Time.UTC()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	release, err := AcquireNamedLock(auditLockName, auditLockWait)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := ensureCacheDir(dir); err != nil {
+		return err
+	}
+	date := rec.Time.Format(auditDayLayout)
+	name := "audit-" + date + ".jsonl"
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 - name built from a date
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+
+	index, err := readAuditIndex(dir)
+	if err != nil {
+		return err
+	}
+	day := index.Days[date]
+	if day == nil {
+		day = &AuditDay{File: name, First: rec.Time, Tools: map[string]int{}}
+		index.Days[date] = day
+	}
+	day.Records++
+	if rec.Time.Before(day.First) {
+		day.First = rec.Time
+	}
+	if rec.Time.After(day.Last) {
+		day.Last = rec.Time
+	}
+	day.Tools[rec.Tool]++
+	return writeAuditIndex(dir, index)
+}
+
+// LoadAuditIndex returns the index of day files
+func LoadAuditIndex() (*AuditIndex, error) {
+	dir, err := AuditDir()
+	if err != nil {
+		return nil, err
+	}
+	return readAuditIndex(dir)
+}
+
+// QueryAuditRecords returns records passing filter, oldest first. The index
+// is used to skip day files outside the time range or without the tool.
+func QueryAuditRecords(filter AuditFilter) ([]AuditRecord, error) {
+	dir, err := AuditDir()
+	if err != nil {
+		return nil, err
+	}
+	index, err := readAuditIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]string, 0, len(index.Days))
+	for date := range index.Days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var out []AuditRecord
+	for _, date := range dates {
+		day := index.Days[date]
+		if filter.skipsDay(day) {
+			continue
+		}
+		recs, err := readAuditDay(filepath.Join(dir, filepath.Base(day.File)))
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range recs {
+			if filter.Matches(rec) {
+				out = append(out, rec)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[len(out)-filter.Limit:]
+	}
+	return out, nil
+}
+
+func readAuditDay(path string) ([]AuditRecord, error) {
+	f, err := os.Open(path) // #nosec G304 - day file listed in the audit index
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var recs []AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var rec AuditRecord
+		// Skip torn or hand-edited lines rather than failing the whole query
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Schema == 0 {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return recs, nil
+}
+
+func readAuditIndex(dir string) (*AuditIndex, error) {
+	index := &AuditIndex{Schema: AuditSchemaVersion, Days: map[string]*AuditDay{}}
+	data, err := os.ReadFile(filepath.Join(dir, auditIndexFile)) // #nosec G304 - fixed file under the audit dir
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit index: %w", err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse audit index: %w", err)
+	}
+	if index.Days == nil {
+		index.Days = map[string]*AuditDay{}
+	}
+	return index, nil
+}
+
+func writeAuditIndex(dir string, index *AuditIndex) error {
+	index.Schema = AuditSchemaVersion
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode audit index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, auditIndexFile), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write audit index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuditRecords_AppendAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_AUDIT_DIR", dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	now := time.Now().UTC()
+	old := now.AddDate(0, 0, -3)
+	for _, rec := range []AuditRecord{
+		{Time: old, Event: "PreToolUse", Tool: "Bash", Decision: AuditDecisionRequested, Inputs: map[string]interface{}{"command": "ls"}},
+		{Time: now.Add(-time.Hour), Event: "PreToolUse", Tool: "Bash", Decision: AuditDecisionRequested},
+		{Time: now.Add(-time.Hour), Event: "PostToolUse", Tool: "Bash", Decision: AuditDecisionFailed},
+		{Time: now, Event: "PreToolUse", Tool: "Write", Decision: AuditDecisionRequested},
+	} {
+		if err := AppendAuditRecord(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := LoadAuditIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldDay := index.Days[old.Format(auditDayLayout)]
+	if len(index.Days) < 2 || oldDay == nil || oldDay.Records != 1 || oldDay.Tools["Bash"] != 1 {
+		t.Fatalf("unexpected index: %+v", index.Days)
+	}
+
+	recent, err := QueryAuditRecords(AuditFilter{Tool: "bash", Since: now.Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 || recent[0].Schema != AuditSchemaVersion || recent[0].Actor != AuditActorAgent {
+		t.Fatalf("expected 2 recent Bash records, got %+v", recent)
+	}
+
+	failed, _ := QueryAuditRecords(AuditFilter{Decision: AuditDecisionFailed})
+	if len(failed) != 1 || failed[0].Event != "PostToolUse" {
+		t.Errorf("unexpected failed records: %+v", failed)
+	}
+	last, _ := QueryAuditRecords(AuditFilter{Limit: 1})
+	if len(last) != 1 || last[0].Tool != "Write" {
+		t.Errorf("limit should keep the most recent record, got %+v", last)
+	}
+}
+
+func TestQueryAuditRecords_SkipsBadLines(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_AUDIT_DIR", dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	if err := AppendAuditRecord(AuditRecord{Event: "PreToolUse", Tool: "Read"}); err != nil {
+		t.Fatal(err)
+	}
+	name := "audit-" + time.Now().UTC().Format(auditDayLayout) + ".jsonl"
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{\"timestamp\":\"free-form\"}\nnot json\n")
+	_ = f.Close()
+
+	recs, err := QueryAuditRecords(AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 {
+		t.Errorf("expected only the valid record, got %+v", recs)
+	}
+}
@@ -4,8 +4,10 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/brads3290/cchooks"
 	"github.com/klauern/blues-traveler/internal/config"
@@ -23,6 +25,12 @@ type Hook interface {
 	Run() error
 	// IsEnabled checks if this hook is enabled in the current context
 	IsEnabled() bool
+	// SetRunContext sets the context Run passes to handlers and the commands
+	// they start, so cancelling it (Ctrl+C, a parent timeout) stops them
+	SetRunContext(ctx context.Context)
+	// Manifest describes the hook's events, install defaults, settings and
+	// capabilities
+	Manifest() Manifest
 }
 
 // BaseHook provides common functionality for all hooks
@@ -31,6 +39,10 @@ type BaseHook struct {
 	name        string
 	description string
 	context     *HookContext
+	// logLevel overrides the context's LogLevel for this hook when set
+	logLevel string
+	// runCtx is the context handlers run under; nil means context.Background
+	runCtx context.Context
 }
 
 // Key returns the hook key
@@ -48,11 +60,39 @@ func (h *BaseHook) Description() string {
 	return h.description
 }
 
+// Manifest returns a minimal manifest: a PreToolUse hook for every tool with
+// no settings or declared capabilities. Hooks override it to describe
+// themselves.
+func (h *BaseHook) Manifest() Manifest {
+	return Manifest{
+		Key:            h.key,
+		Name:           h.name,
+		Description:    h.description,
+		Version:        DefaultManifestVersion,
+		Events:         []string{string(PreToolUseEvent)},
+		DefaultEvent:   string(PreToolUseEvent),
+		DefaultMatcher: "*",
+	}
+}
+
 // IsEnabled checks if the hook is enabled by consulting settings
 func (h *BaseHook) IsEnabled() bool {
 	return h.context.SettingsChecker(h.key)
 }
 
+// SetRunContext sets the context the hook's handlers run under
+func (h *BaseHook) SetRunContext(ctx context.Context) {
+	h.runCtx = ctx
+}
+
+// RunContext returns the context set by SetRunContext, or context.Background
+func (h *BaseHook) RunContext() context.Context {
+	if h.runCtx == nil {
+		return context.Background()
+	}
+	return h.runCtx
+}
+
 // Context returns the hook context
 func (h *BaseHook) Context() *HookContext {
 	return h.context
@@ -98,7 +138,8 @@ func (fs *RealFileSystem) Stat(name string) (os.FileInfo, error) {
 
 // CommandExecutor interface for dependency injection in testing
 type CommandExecutor interface {
-	ExecuteCommand(name string, args ...string) ([]byte, error)
+	// ExecuteCommand runs name with args, killing it if ctx is cancelled
+	ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
 // RealCommandExecutor implements CommandExecutor using real system commands
@@ -106,14 +147,17 @@ type RealCommandExecutor struct{}
 
 // ExecuteCommand executes a system command with the specified arguments and returns the combined output
 // #nosec G204 - Command name is controlled by hooks, not user input; args are hook-defined
-func (ce *RealCommandExecutor) ExecuteCommand(name string, args ...string) ([]byte, error) {
-	cmd := exec.Command(name, args...)
+func (ce *RealCommandExecutor) ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	ConfigureCancel(cmd)
 	return cmd.CombinedOutput()
 }
 
 // Runner interface allows for mocking in tests
 type Runner interface {
-	Run()
+	// RunContext reads one event from stdin and dispatches it to the
+	// handlers, passing them ctx
+	RunContext(ctx context.Context)
 }
 
 // RunnerFactory creates a Runner with the provided handlers
@@ -148,8 +192,30 @@ type HookContext struct {
 	LoggingEnabled  bool
 	LoggingDir      string
 	LoggingFormat   string
+	// LogWriter, when set, receives structured log entries instead of
+	// reopening the per-hook log file for every event
+	LogWriter io.Writer
 	// Platform identifies the runtime environment (e.g., Claude, Cursor)
 	Platform Platform
+	// LatencyThreshold enables hook timing; responses slower than this note
+	// how long the hook took. Zero disables timing entirely.
+	LatencyThreshold time.Duration
+	// LogLevel is the most verbose level written (error, warn, info or
+	// debug); empty keeps every entry
+	LogLevel string
+	// QuietSuccess is the default for jobs that don't set quiet_success
+	QuietSuccess bool
+	// Experiments A/B test hook messages; see withExperiments
+	Experiments []config.Experiment
+	// MetricsEnabled records each run's duration, exit status and decision
+	// for 'hooks stats'
+	MetricsEnabled bool
+	// TerseOutput keeps responses plain ASCII without color codes or
+	// timing notes, and silences progress lines
+	TerseOutput bool
+	// Redactor scrubs configured patterns from log entries and audit
+	// records before they are written; nil writes them as is
+	Redactor *Redactor
 }
 
 // DefaultHookContext returns a context with real implementations
@@ -177,10 +243,29 @@ func defaultIsPluginEnabled(_ string) bool {
 
 // LogHookEvent delegates to shared logging utility (see logging.go)
 func (h *BaseHook) LogHookEvent(event string, toolName string, rawData map[string]interface{}, details map[string]interface{}) {
-	if !h.context.LoggingEnabled {
+	h.LogHookEventAt(config.LogLevelInfo, event, toolName, rawData, details)
+}
+
+// LogHookEventAt logs an entry at level, dropping it when the hook's
+// log level is less verbose
+func (h *BaseHook) LogHookEventAt(level, event string, toolName string, rawData map[string]interface{}, details map[string]interface{}) {
+	if !h.context.LoggingEnabled || !config.LogLevelEnabled(h.LogLevel(), level) {
 		return
 	}
-	logHookEvent(h.context, h.key, event, toolName, rawData, details)
+	logHookEventAt(h.context, h.key, level, event, toolName, rawData, details)
+}
+
+// LogLevel returns the most verbose level this hook writes; empty means all
+func (h *BaseHook) LogLevel() string {
+	if h.logLevel != "" {
+		return h.logLevel
+	}
+	return h.context.LogLevel
+}
+
+// SetLogLevel overrides the context's log level for this hook
+func (h *BaseHook) SetLogLevel(level string) {
+	h.logLevel = level
 }
 
 // CreateRawHandler creates a raw handler that logs all incoming JSON data when logging is enabled
@@ -194,7 +279,7 @@ func (h *BaseHook) CreateRawHandler() func(context.Context, string) *cchooks.Raw
 		var rawEvent map[string]interface{}
 		if err := json.Unmarshal([]byte(rawJSON), &rawEvent); err != nil {
 			// Log parsing error but continue
-			h.LogHookEvent("raw_event_parse_error", "unknown", map[string]interface{}{
+			h.LogHookEventAt(config.LogLevelWarn, "raw_event_parse_error", "unknown", map[string]interface{}{
 				"raw_json_string": rawJSON,
 				"error":           err.Error(),
 			}, nil)
@@ -206,7 +291,7 @@ func (h *BaseHook) CreateRawHandler() func(context.Context, string) *cchooks.Raw
 		toolName, _ := rawEvent["tool_name"].(string)
 
 		// Log the complete raw event data with the parsed JSON as a nested object
-		h.LogHookEvent("raw_event", toolName, map[string]interface{}{
+		h.LogHookEventAt(config.LogLevelDebug, "raw_event", toolName, map[string]interface{}{
 			"hook_event_name": eventName,
 		}, rawEvent) // Pass the parsed JSON directly as details for readable formatting
 
@@ -226,15 +311,28 @@ func (h *BaseHook) StandardRun(
 		return nil
 	}
 
-	runner := h.Context().RunnerFactory(preHandler, postHandler, h.CreateRawHandler())
-	runner.Run()
+	runner := h.Runner(preHandler, postHandler, h.CreateRawHandler())
+	runner.RunContext(h.RunContext())
+	return nil
+}
+
+// RunRaw executes the hook with handler seeing every event's raw JSON.
+// Hooks for events other than PreToolUse and PostToolUse, which the
+// standard runner doesn't dispatch (Stop, SessionStart, Notification),
+// call this in their Run() method instead of StandardRun.
+func (h *BaseHook) RunRaw(handler func(context.Context, string) *cchooks.RawResponse) error {
+	if !h.IsEnabled() {
+		return nil
+	}
+	runner := h.Runner(nil, nil, handler)
+	runner.RunContext(h.RunContext())
 	return nil
 }
 
 // LogError logs a standard error event
 func (h *BaseHook) LogError(eventType, toolName string, err error) {
 	if h.Context().LoggingEnabled {
-		h.LogHookEvent(eventType, toolName, map[string]interface{}{"error": err.Error()}, nil)
+		h.LogHookEventAt(config.LogLevelError, eventType, toolName, map[string]interface{}{"error": err.Error()}, nil)
 	}
 }
 
@@ -248,7 +346,7 @@ func (h *BaseHook) LogApproval(eventType, toolName string, details map[string]in
 // LogBlock logs a standard block event
 func (h *BaseHook) LogBlock(eventType, toolName string, details map[string]interface{}) {
 	if h.Context().LoggingEnabled {
-		h.LogHookEvent(eventType, toolName, details, nil)
+		h.LogHookEventAt(config.LogLevelWarn, eventType, toolName, details, nil)
 	}
 }
 
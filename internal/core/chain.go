@@ -0,0 +1,210 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// OutputEnv names the file a job of a chained event writes KEY=VALUE
+	// lines to; the event's later jobs get them as environment variables
+	OutputEnv = "BT_OUTPUT"
+
+	// chainSubDir holds one directory per chained event under the
+	// session's state directory
+	chainSubDir = ".chains"
+	// chainPollInterval is how often a waiting job checks on other jobs
+	chainPollInterval = 50 * time.Millisecond
+	// chainStaleAfter drops the outputs of events long finished
+	chainStaleAfter = time.Hour
+)
+
+// chainReservedKeys are event variables a job's outputs cannot replace
+var chainReservedKeys = map[string]bool{
+	"EVENT_NAME": true, "TOOL_NAME": true, "MCP_SERVER": true, "MCP_TOOL": true,
+	"FILES_CHANGED": true, "TOOL_FILE": true, "TOOL_OUTPUT_FILE": true,
+	"PROJECT_ROOT": true, "USER_PROMPT": true,
+}
+
+// JobChain shares outputs between the jobs of one chained event. Claude
+// Code starts every hook of an event as its own process, so the jobs meet
+// in a directory named for the event and record when they finish there.
+type JobChain struct {
+	dir string
+}
+
+// ChainID identifies one occurrence of group's event. Every hook of the
+// event receives the same payload, so its hash is the same in each process.
+func ChainID(group, event, payload string) string {
+	sum := sha256.Sum256([]byte(group + "\x00" + event + "\x00" + payload))
+	return hex.EncodeToString(sum[:12])
+}
+
+// OpenJobChain returns the chain id in sessionID's state, creating it.
+// Chains of events finished more than an hour ago are removed.
+func OpenJobChain(sessionID, id string) (*JobChain, error) {
+	state, err := OpenSessionState(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(state.Dir(), chainSubDir)
+	pruneRunDirs(root, time.Now())
+	dir := filepath.Join(root, id)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create chain directory: %w", err)
+	}
+	return &JobChain{dir: dir}, nil
+}
+
+// pruneRunDirs removes the per-event directories under root of events
+// finished more than chainStaleAfter ago
+func pruneRunDirs(root string, now time.Time) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && now.Sub(info.ModTime()) > chainStaleAfter {
+			_ = os.RemoveAll(filepath.Join(root, e.Name()))
+		}
+	}
+}
+
+// chainFileName maps a job name to a file name in the chain directory
+func chainFileName(job, ext string) string {
+	if !stateNamePattern.MatchString(job) {
+		sum := sha256.Sum256([]byte(job))
+		job = "job-" + hex.EncodeToString(sum[:8])
+	}
+	return job + ext
+}
+
+// PrepareOutput creates job's empty output file and returns its path, the
+// value of BT_OUTPUT for the job
+func (c *JobChain) PrepareOutput(job string) (string, error) {
+	path := filepath.Join(c.dir, chainFileName(job, ".out"))
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+	return path, nil
+}
+
+// Wait blocks until every job in jobs has finished, ctx ends, or wait
+// passes, and returns the jobs that did not finish
+func (c *JobChain) Wait(ctx context.Context, jobs []string, wait time.Duration) []string {
+	deadline := time.Now().Add(wait)
+	for {
+		var pending []string
+		for _, job := range jobs {
+			if _, err := os.Stat(filepath.Join(c.dir, chainFileName(job, ".env"))); err != nil {
+				pending = append(pending, job)
+			}
+		}
+		if len(pending) == 0 || !time.Now().Before(deadline) {
+			return pending
+		}
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-time.After(chainPollInterval):
+		}
+	}
+}
+
+// Outputs merges what jobs recorded, later jobs overriding earlier ones
+func (c *JobChain) Outputs(jobs []string) map[string]string {
+	merged := map[string]string{}
+	for _, job := range jobs {
+		data, err := os.ReadFile(filepath.Join(c.dir, chainFileName(job, ".env"))) // #nosec G304 - path inside the chain directory
+		if err != nil {
+			continue
+		}
+		var vars map[string]string
+		if json.Unmarshal(data, &vars) != nil {
+			continue
+		}
+		for k, v := range vars {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// Finish records the outputs job wrote and marks it done, releasing the
+// jobs waiting on it. It runs whether or not the job ran, so a skipped job
+// never holds up the chain. Lines that are not KEY=VALUE with a usable key
+// are dropped and reported in the error.
+func (c *JobChain) Finish(job string) error {
+	outPath := filepath.Join(c.dir, chainFileName(job, ".out"))
+	data, _ := os.ReadFile(outPath) // #nosec G304 - path inside the chain directory
+	_ = os.Remove(outPath)
+	vars, parseErr := ParseJobOutputs(data)
+	encoded, err := json.Marshal(vars)
+	if err != nil {
+		return err
+	}
+	if err := writeRunFile(c.dir, chainFileName(job, ".env"), encoded); err != nil {
+		return fmt.Errorf("failed to record outputs: %w", err)
+	}
+	return parseErr
+}
+
+// writeRunFile replaces dir/name with data in one step, so processes
+// polling for the file never read it half-written
+func writeRunFile(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, ".write-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// ParseJobOutputs reads KEY=VALUE lines as a job writes them to
+// BT_OUTPUT. Values are literal; blank lines and '#' comments are skipped.
+// Keys naming event variables or starting with BT_ are rejected.
+func ParseJobOutputs(data []byte) (map[string]string, error) {
+	vars := map[string]string{}
+	var bad []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !config.ValidEnvKey(key) || chainReservedKeys[key] || strings.HasPrefix(key, "BT_") {
+			bad = append(bad, fmt.Sprintf("line %d", lineNo))
+			continue
+		}
+		vars[key] = value
+	}
+	if len(bad) > 0 {
+		return vars, fmt.Errorf("ignored %s of %s: expected KEY=VALUE with a key that is not an event or BT_ variable", strings.Join(bad, ", "), OutputEnv)
+	}
+	return vars, nil
+}
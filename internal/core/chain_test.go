@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseJobOutputs(t *testing.T) {
+	vars, err := ParseJobOutputs([]byte("# build info\nVERSION=1.2\n\nURL=https://x/?a=b\nTOOL_NAME=spoofed\nBT_STATE_DIR=/tmp\nnot a pair\n"))
+	if err == nil {
+		t.Error("expected an error naming the ignored lines")
+	}
+	if len(vars) != 2 || vars["VERSION"] != "1.2" || vars["URL"] != "https://x/?a=b" {
+		t.Errorf("vars = %v", vars)
+	}
+}
+
+func TestJobChain_FinishReleasesWaiters(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	chain, err := OpenJobChain("s-1", ChainID("g", "Stop", "{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if missing := chain.Wait(context.Background(), []string{"lint"}, 100*time.Millisecond); len(missing) != 1 {
+		t.Fatalf("unfinished job should still be missing after the wait, got %v", missing)
+	}
+
+	out, err := chain.PrepareOutput("lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(out, []byte("COUNT=3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Finish("lint"); err != nil {
+		t.Fatal(err)
+	}
+	if missing := chain.Wait(context.Background(), []string{"lint"}, time.Second); len(missing) != 0 {
+		t.Errorf("finished job reported missing: %v", missing)
+	}
+	if got := chain.Outputs([]string{"lint"}); got["COUNT"] != "3" {
+		t.Errorf("outputs = %v", got)
+	}
+}
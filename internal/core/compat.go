@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/brads3290/cchooks"
+)
+
+const (
+	// cchooksModule is the module path looked up in build info
+	cchooksModule = "github.com/brads3290/cchooks"
+	// compatStatsFile records payload mismatches seen at runtime
+	compatStatsFile = "compat.json"
+	// compatLockName serializes mismatch updates from parallel hooks
+	compatLockName = "compat-stats"
+	// compatLockWait keeps a busy stats file from slowing hooks down further
+	compatLockWait = 2 * time.Second
+)
+
+// CchooksTestedVersions lists the cchooks releases CI runs the built-in hooks
+// against. Other versions may work but are not verified.
+var CchooksTestedVersions = []string{"v0.7.0"}
+
+// payloadRequiredFields lists the top-level fields built-in hooks rely on,
+// per event. A payload missing one means Claude Code changed its schema.
+var payloadRequiredFields = map[string][]string{
+	string(PreToolUseEvent):       {"session_id", "tool_name", "tool_input"},
+	string(PostToolUseEvent):      {"session_id", "tool_name", "tool_input", "tool_response"},
+	string(UserPromptSubmitEvent): {"session_id", "prompt"},
+	string(StopEvent):             {"session_id"},
+	string(SubagentStopEvent):     {"session_id"},
+	string(PreCompactEvent):       {"session_id"},
+	string(SessionStartEvent):     {"session_id"},
+	string(SessionEndEvent):       {"session_id"},
+}
+
+// PayloadMismatch summarizes payloads for one event that lacked required fields
+type PayloadMismatch struct {
+	Event    string    `json:"event"`
+	Missing  []string  `json:"missing"`
+	Count    int       `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// CchooksVersion returns the cchooks version linked into the binary, or ""
+// when build info is unavailable (e.g. in tests)
+func CchooksVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == cchooksModule {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// IsTestedCchooksVersion reports whether version is in CchooksTestedVersions
+func IsTestedCchooksVersion(version string) bool {
+	for _, v := range CchooksTestedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPayloadSchema returns the event name and any required fields the raw
+// payload lacks. Unknown events and unparseable payloads report nothing;
+// cchooks rejects those on its own.
+func CheckPayloadSchema(rawJSON string) (string, []string) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rawJSON), &payload); err != nil {
+		return "", nil
+	}
+	var event string
+	if err := json.Unmarshal(payload["hook_event_name"], &event); err != nil {
+		return "", nil
+	}
+	var missing []string
+	for _, field := range payloadRequiredFields[event] {
+		if _, ok := payload[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return event, missing
+}
+
+// withSchemaCheck records payload mismatches before handing the payload to
+// next (which may be nil, meaning normal cchooks dispatch)
+func withSchemaCheck(next func(context.Context, string) *cchooks.RawResponse) func(context.Context, string) *cchooks.RawResponse {
+	return func(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+		if event, missing := CheckPayloadSchema(rawJSON); len(missing) > 0 {
+			_ = RecordPayloadMismatch(event, missing)
+		}
+		if next == nil {
+			return nil
+		}
+		return next(ctx, rawJSON)
+	}
+}
+
+// CheckCchooksDecoding decodes representative Claude Code payloads with the
+// linked cchooks types and reports fields that did not come through. A
+// problem here means cchooks and Claude Code disagree on field names.
+func CheckCchooksDecoding() []string {
+	var problems []string
+
+	var pre cchooks.PreToolUseEvent
+	prePayload := `{"session_id":"s","tool_name":"Edit","tool_input":{"file_path":"a.go"}}`
+	if err := json.Unmarshal([]byte(prePayload), &pre); err != nil {
+		problems = append(problems, fmt.Sprintf("PreToolUse: %v", err))
+	} else {
+		problems = append(problems, missingDecoded("PreToolUse", map[string]bool{
+			"session_id": pre.SessionID != "",
+			"tool_name":  pre.ToolName != "",
+			"tool_input": len(pre.ToolInput) > 0,
+		})...)
+	}
+
+	var post cchooks.PostToolUseEvent
+	postPayload := `{"session_id":"s","tool_name":"Edit","tool_input":{"file_path":"a.go"},"tool_response":{"success":true}}`
+	if err := json.Unmarshal([]byte(postPayload), &post); err != nil {
+		problems = append(problems, fmt.Sprintf("PostToolUse: %v", err))
+	} else {
+		problems = append(problems, missingDecoded("PostToolUse", map[string]bool{
+			"session_id":    post.SessionID != "",
+			"tool_name":     post.ToolName != "",
+			"tool_input":    len(post.ToolInput) > 0,
+			"tool_response": len(post.ToolResponse) > 0,
+		})...)
+	}
+	return problems
+}
+
+func missingDecoded(event string, decoded map[string]bool) []string {
+	var problems []string
+	for field, ok := range decoded {
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: cchooks did not decode '%s'", event, field))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// RecordPayloadMismatch counts a payload for event that lacked missing fields
+func RecordPayloadMismatch(event string, missing []string) error {
+	path, err := compatStatsPath()
+	if err != nil {
+		return err
+	}
+	release, err := AcquireNamedLock(compatLockName, compatLockWait)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	byEvent, err := readPayloadMismatches(path)
+	if err != nil {
+		return err
+	}
+	m := byEvent[event]
+	m.Event = event
+	m.Missing = missing
+	m.Count++
+	m.LastSeen = time.Now().UTC()
+	byEvent[event] = m
+
+	if err := ensureCacheDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(byEvent, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode compat stats: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write compat stats: %w", err)
+	}
+	return nil
+}
+
+// LoadPayloadMismatches returns recorded mismatches sorted by event
+func LoadPayloadMismatches() ([]PayloadMismatch, error) {
+	path, err := compatStatsPath()
+	if err != nil {
+		return nil, err
+	}
+	byEvent, err := readPayloadMismatches(path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PayloadMismatch, 0, len(byEvent))
+	for _, m := range byEvent {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Event < out[j].Event })
+	return out, nil
+}
+
+// ClearPayloadMismatches forgets recorded mismatches
+func ClearPayloadMismatches() error {
+	path, err := compatStatsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear compat stats: %w", err)
+	}
+	return nil
+}
+
+func compatStatsPath() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, compatStatsFile), nil
+}
+
+func readPayloadMismatches(path string) (map[string]PayloadMismatch, error) {
+	byEvent := map[string]PayloadMismatch{}
+	data, err := os.ReadFile(path) // #nosec G304 - fixed file under the cache dir
+	if os.IsNotExist(err) {
+		return byEvent, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compat stats: %w", err)
+	}
+	if json.Unmarshal(data, &byEvent) != nil {
+		return map[string]PayloadMismatch{}, nil
+	}
+	return byEvent, nil
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCheckPayloadSchema(t *testing.T) {
+	tests := []struct {
+		raw         string
+		wantEvent   string
+		wantMissing []string
+	}{
+		{`{"hook_event_name":"PreToolUse","session_id":"s","tool_name":"Bash","tool_input":{}}`, "PreToolUse", nil},
+		{`{"hook_event_name":"PostToolUse","session_id":"s","tool_name":"Edit","tool_input":{}}`, "PostToolUse", []string{"tool_response"}},
+		{`{"hook_event_name":"UserPromptSubmit","session_id":"s","user_prompt":"hi"}`, "UserPromptSubmit", []string{"prompt"}},
+		{`{"hook_event_name":"SomethingNew"}`, "SomethingNew", nil},
+		{`not json`, "", nil},
+	}
+	for _, tt := range tests {
+		event, missing := CheckPayloadSchema(tt.raw)
+		if event != tt.wantEvent || !reflect.DeepEqual(missing, tt.wantMissing) {
+			t.Errorf("CheckPayloadSchema(%s) = (%q, %v), want (%q, %v)", tt.raw, event, missing, tt.wantEvent, tt.wantMissing)
+		}
+	}
+}
+
+func TestWithSchemaCheck_RecordsMismatches(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	raw := withSchemaCheck(nil)
+	if resp := raw(context.Background(), `{"hook_event_name":"PreToolUse","session_id":"s","tool_name":"Bash","tool_input":{}}`); resp != nil {
+		t.Fatal("schema check must not answer for the hook")
+	}
+	raw(context.Background(), `{"hook_event_name":"PreToolUse","session_id":"s","tool":"Bash"}`)
+	raw(context.Background(), `{"hook_event_name":"PreToolUse","session_id":"s","tool":"Bash"}`)
+
+	got, err := LoadPayloadMismatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Event != "PreToolUse" || got[0].Count != 2 ||
+		!reflect.DeepEqual(got[0].Missing, []string{"tool_name", "tool_input"}) {
+		t.Fatalf("mismatches = %+v, want two PreToolUse payloads missing tool_name and tool_input", got)
+	}
+
+	if err := ClearPayloadMismatches(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LoadPayloadMismatches(); len(got) != 0 {
+		t.Errorf("clear should forget mismatches, got %+v", got)
+	}
+}
+
+func TestCheckCchooksDecoding(t *testing.T) {
+	if problems := CheckCchooksDecoding(); len(problems) != 0 {
+		t.Errorf("linked cchooks drops payload fields: %v", problems)
+	}
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ConditionFunc implements a named helper callable from skip/only
+// expressions, e.g. in_directory(file, "vendor"). Arguments arrive with
+// variables expanded and quotes removed, and nested conditions as "true" or
+// "false"; vars holds the job environment.
+type ConditionFunc func(args []string, vars map[string]string) (bool, error)
+
+// conditionCallPattern validates condition function names
+var conditionCallPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\((.*)\)$`)
+
+// generatedHeaderPattern recognizes Go's "Code generated ... DO NOT EDIT."
+// convention and the @generated marker used by many other generators
+var generatedHeaderPattern = regexp.MustCompile(`(?m)^\s*(//|#|/\*|\*|--)?\s*(Code generated .* DO NOT EDIT\.|.*@generated\b)`)
+
+// generatedNamePatterns are file names that are generated by convention
+var generatedNamePatterns = []string{
+	"*.pb.go", "*_gen.go", "*.gen.*", "*_generated.*", "*.generated.*",
+	"zz_generated*", "*.min.js", "*.min.css",
+}
+
+// generatedHeaderBytes bounds how much of a file is read looking for a marker
+const generatedHeaderBytes = 4096
+
+var conditionFuncs = struct {
+	mu    sync.RWMutex
+	funcs map[string]ConditionFunc
+}{funcs: map[string]ConditionFunc{
+	"is_generated":   isGeneratedCondition,
+	"in_directory":   inDirectoryCondition,
+	"branch_matches": branchMatchesCondition,
+	"contains":       containsCondition,
+	"startswith":     startsWithCondition,
+	"endswith":       endsWithCondition,
+	"matches":        matchesCondition,
+}}
+
+// RegisterConditionFunc makes fn callable as name(...) in skip/only
+// expressions. Registering an existing name replaces it.
+func RegisterConditionFunc(name string, fn ConditionFunc) error {
+	if !conditionCallPattern.MatchString(name+"()") || fn == nil {
+		return fmt.Errorf("invalid condition function '%s'", name)
+	}
+	conditionFuncs.mu.Lock()
+	defer conditionFuncs.mu.Unlock()
+	conditionFuncs.funcs[name] = fn
+	return nil
+}
+
+// ConditionFuncNames lists the registered condition functions
+func ConditionFuncNames() []string {
+	conditionFuncs.mu.RLock()
+	defer conditionFuncs.mu.RUnlock()
+	names := make([]string, 0, len(conditionFuncs.funcs))
+	for name := range conditionFuncs.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func currentFile(vars map[string]string) string {
+	if f := vars["TOOL_FILE"]; f != "" {
+		return f
+	}
+	return vars["TOOL_OUTPUT_FILE"]
+}
+
+func wantArgs(args []string, n int, usage string) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %s", usage)
+	}
+	return nil
+}
+
+// containsCondition reports whether a value contains some text
+func containsCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 2, `contains(value, "text")`); err != nil {
+		return false, err
+	}
+	return strings.Contains(args[0], args[1]), nil
+}
+
+// startsWithCondition reports whether a value starts with a prefix
+func startsWithCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 2, `startswith(value, "prefix")`); err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(args[0], args[1]), nil
+}
+
+// endsWithCondition reports whether a value ends with a suffix
+func endsWithCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 2, `endswith(value, "suffix")`); err != nil {
+		return false, err
+	}
+	return strings.HasSuffix(args[0], args[1]), nil
+}
+
+// matchesCondition is the function form of the matches operator: true when
+// any space-separated word of the value matches the glob
+func matchesCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 2, `matches(value, "glob")`); err != nil {
+		return false, err
+	}
+	return globMatchAny(args[0], args[1]), nil
+}
+
+// isGeneratedCondition reports whether a file is generated, by name or by a
+// generated-code marker near the top of the file
+func isGeneratedCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 1, "is_generated(file)"); err != nil {
+		return false, err
+	}
+	file := args[0]
+	if file == "" {
+		return false, nil
+	}
+	base := filepath.Base(file)
+	for _, p := range generatedNamePatterns {
+		if ok, _ := filepath.Match(p, base); ok {
+			return true, nil
+		}
+	}
+
+	f, err := os.Open(file) // #nosec G304 - file comes from the hook event being evaluated
+	if err != nil {
+		// A deleted or unreadable file has no header to inspect
+		return false, nil
+	}
+	defer func() { _ = f.Close() }()
+	head, _ := io.ReadAll(io.LimitReader(f, generatedHeaderBytes))
+	return generatedHeaderPattern.Match(head), nil
+}
+
+// inDirectoryCondition reports whether file lies under a directory named dir
+// at any depth, e.g. in_directory(file, "vendor") matches a/vendor/b.go
+func inDirectoryCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 2, `in_directory(file, "dir")`); err != nil {
+		return false, err
+	}
+	file, dir := args[0], strings.Trim(filepath.ToSlash(filepath.Clean(args[1])), "/")
+	if file == "" || dir == "" || dir == "." {
+		return false, nil
+	}
+	if filepath.IsAbs(file) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+	}
+	path := "/" + strings.Trim(filepath.ToSlash(filepath.Clean(file)), "/") + "/"
+	return strings.Contains(path, "/"+dir+"/"), nil
+}
+
+// branchMatchesCondition reports whether the current git branch matches a
+// regular expression. Outside a repository it is always false.
+func branchMatchesCondition(args []string, _ map[string]string) (bool, error) {
+	if err := wantArgs(args, 1, `branch_matches("regex")`); err != nil {
+		return false, err
+	}
+	rx, err := regexp.Compile(args[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid regex pattern: %v", err)
+	}
+	branch := currentGitBranch()
+	if branch == "" {
+		return false, nil
+	}
+	return rx.MatchString(branch), nil
+}
+
+var (
+	gitBranchOnce   sync.Once
+	gitBranchCached string
+)
+
+// currentGitBranch is looked up once per process; one hook run only ever
+// sees one checkout
+func currentGitBranch() string {
+	gitBranchOnce.Do(func() {
+		out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+		if err == nil {
+			gitBranchCached = string(bytes.TrimSpace(out))
+		}
+	})
+	return gitBranchCached
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConditionFunctions(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("api.go", []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		file string
+		want bool
+	}{
+		{"is_generated(file)", "api.go", true},
+		{"is_generated(file)", "main.go", false},
+		{"is_generated(file)", "model_gen.go", true},
+		{"is_generated(file)", "", false},
+		{"in_directory(file, \"vendor\")", "vendor/github.com/x/y.go", true},
+		{"in_directory(file, \"vendor\")", "pkg/vendored/y.go", false},
+		{"in_directory(file, \"internal/gen\")", filepath.Join(dir, "internal", "gen", "a.go"), true},
+		{"!in_directory(file, \"vendor\") && ${TOOL_NAME} == Edit", "main.go", true},
+		{"in_directory(${TOOL_FILE}, 'cmd')", "cmd/tool/main.go", true},
+	}
+	for _, tt := range tests {
+		vars := map[string]string{"TOOL_NAME": "Edit", "TOOL_FILE": tt.file}
+		got, err := EvalExpression(tt.expr, vars)
+		if err != nil {
+			t.Fatalf("%s with %q: %v", tt.expr, tt.file, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s with %q = %v, want %v", tt.expr, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestConditionFunctionErrors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"no_such_helper(file)":         "unknown condition function 'no_such_helper'",
+		"in_directory(file)":           "in_directory(file, \"dir\")",
+		"branch_matches(\"release(\")": "invalid regex",
+	} {
+		_, err := EvalExpression(expr, map[string]string{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want it to mention %q", expr, err, want)
+		}
+	}
+}
+
+func TestRegisterConditionFunc(t *testing.T) {
+	if err := RegisterConditionFunc("bad name", func([]string, map[string]string) (bool, error) { return true, nil }); err == nil {
+		t.Error("names must be identifiers")
+	}
+	err := RegisterConditionFunc("test_tool_is", func(args []string, vars map[string]string) (bool, error) {
+		return len(args) == 1 && vars["TOOL_NAME"] == args[0], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := EvalExpression(`test_tool_is("Write")`, map[string]string{"TOOL_NAME": "Write"})
+	if err != nil || !got {
+		t.Errorf("registered function = (%v, %v), want true", got, err)
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DryRunner is implemented by hooks that can report what they would do for
+// an event without running any commands
+type DryRunner interface {
+	// DryRun evaluates payload as the hook would. A nil payload asks the
+	// hook to synthesize one that exercises it.
+	DryRun(ctx context.Context, payload []byte) (*DryRunReport, error)
+}
+
+// DryRunReport is what a hook would have done for one event
+type DryRunReport struct {
+	Event   string
+	Tool    string
+	Payload json.RawMessage
+	// Synthesized is set when the hook made up the payload
+	Synthesized bool
+	// Steps holds one entry per command the hook would consider, such as
+	// one per changed file
+	Steps []DryRunStep
+	// Notes explain behavior outside the steps, such as an event the hook
+	// ignores or commands run before and after it
+	Notes []string
+}
+
+// DryRunStep is one command a hook would or would not run
+type DryRunStep struct {
+	// File is the changed file the step is for, when there are several
+	File     string
+	WouldRun bool
+	// Reason says why the command would not run
+	Reason string
+	// Command is the command with the step's variables expanded
+	Command string
+	Env     map[string]string
+}
@@ -23,17 +23,23 @@ func NewClaudeCodeEnvironmentProvider() EnvironmentProvider {
 }
 
 // GetEnvironment builds a set of common environment variables from loosely typed context
-// ctxData may contain: "tool_name" string, "files_changed" []string, "project_root" string, "user_prompt" string
+// ctxData may contain: "tool_name" string, "files_changed" []string, "project_root" string, "user_prompt" string,
+// "session_id" string
 func (p *claudeCodeEnvironmentProvider) GetEnvironment(event string, ctxData map[string]interface{}) map[string]string {
 	env := map[string]string{
 		"EVENT_NAME": event,
 	}
 	if v, ok := ctxData["tool_name"].(string); ok && v != "" {
 		env["TOOL_NAME"] = v
+		if server, tool, ok := ParseMCPToolName(v); ok {
+			env["MCP_SERVER"] = server
+			env["MCP_TOOL"] = tool
+		}
 	}
 	if v, ok := ctxData["files_changed"].([]string); ok && len(v) > 0 {
 		env["FILES_CHANGED"] = strings.Join(v, " ")
-		// Provide convenience aliases for single-file tools like Edit/Write
+		// Convenience aliases for the primary file. Multi-file tools get these
+		// per file from PerFileEnvironments.
 		env["TOOL_FILE"] = v[0]
 		env["TOOL_OUTPUT_FILE"] = v[0]
 	}
@@ -43,6 +49,9 @@ func (p *claudeCodeEnvironmentProvider) GetEnvironment(event string, ctxData map
 	if v, ok := ctxData["user_prompt"].(string); ok && v != "" {
 		env["USER_PROMPT"] = v
 	}
+	if v, ok := ctxData["session_id"].(string); ok && v != "" {
+		env[SessionIDEnv] = v
+	}
 	return env
 }
 
@@ -51,7 +60,8 @@ func (p *claudeCodeEnvironmentProvider) GetEnvironment(event string, ctxData map
 // BuildPreToolUseContext extracts a minimal context map from a PreToolUseEvent
 func BuildPreToolUseContext(_ context.Context, ev *cchooks.PreToolUseEvent) map[string]interface{} {
 	ctx := map[string]interface{}{
-		"tool_name": ev.ToolName,
+		"tool_name":  ev.ToolName,
+		"session_id": ev.SessionID,
 	}
 	if wd, err := os.Getwd(); err == nil {
 		ctx["project_root"] = wd
@@ -64,29 +74,37 @@ func BuildPreToolUseContext(_ context.Context, ev *cchooks.PreToolUseEvent) map[
 // BuildPostToolUseContext extracts a minimal context map from a PostToolUseEvent
 func BuildPostToolUseContext(_ context.Context, ev *cchooks.PostToolUseEvent) map[string]interface{} {
 	ctx := map[string]interface{}{
-		"tool_name": ev.ToolName,
+		"tool_name":  ev.ToolName,
+		"session_id": ev.SessionID,
 	}
 	if wd, err := os.Getwd(); err == nil {
 		ctx["project_root"] = wd
 	}
-	// Extract edited/written file when applicable
-	var files []string
-	switch ev.ToolName {
-	case "Edit":
-		if edit, err := ev.InputAsEdit(); err == nil {
-			if edit.FilePath != "" {
-				files = append(files, edit.FilePath)
-			}
-		}
-	case "Write":
-		if write, err := ev.InputAsWrite(); err == nil {
-			if write.FilePath != "" {
-				files = append(files, write.FilePath)
-			}
-		}
-	}
+	// Extract every file touched by Edit/Write/MultiEdit/NotebookEdit
+	files := ParseToolPayload(ev.ToolName, ev.ToolInput).Files()
 	if len(files) > 0 {
 		ctx["files_changed"] = files
 	}
 	return ctx
 }
+
+// PerFileEnvironments expands env into one environment per file in
+// FILES_CHANGED, with TOOL_FILE and TOOL_OUTPUT_FILE pointing at that file.
+// FILES_CHANGED keeps the full list. Environments with zero or one file are
+// returned unchanged as a single entry.
+func PerFileEnvironments(env map[string]string, files []string) []map[string]string {
+	if len(files) <= 1 {
+		return []map[string]string{env}
+	}
+	envs := make([]map[string]string, 0, len(files))
+	for _, f := range files {
+		e := make(map[string]string, len(env))
+		for k, v := range env {
+			e[k] = v
+		}
+		e["TOOL_FILE"] = f
+		e["TOOL_OUTPUT_FILE"] = f
+		envs = append(envs, e)
+	}
+	return envs
+}
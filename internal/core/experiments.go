@@ -0,0 +1,379 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// experimentsFile holds one ExperimentRecord per line under the cache dir
+	experimentsFile = "experiments.jsonl"
+	// experimentsLockName serializes appends from hooks running in parallel
+	experimentsLockName = "experiments-log"
+	experimentsLockWait = 2 * time.Second
+)
+
+// ExperimentRecord is one response from a hook under experiment: which
+// variant the session was assigned and what the hook decided
+type ExperimentRecord struct {
+	Time       time.Time `json:"time"`
+	Experiment string    `json:"experiment"`
+	Variant    string    `json:"variant"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Hook       string    `json:"hook"`
+	Event      string    `json:"event"`
+	Tool       string    `json:"tool"`
+	// Decision is block or approve
+	Decision string `json:"decision"`
+	// Rewritten is true when the variant's template replaced the message
+	Rewritten bool `json:"rewritten,omitempty"`
+}
+
+// VariantResult compares one variant of an experiment. A block counts as
+// recovered when the next call of the same tool through the same hook in
+// that session was approved, and as repeated when it was blocked again.
+type VariantResult struct {
+	Experiment string
+	Variant    string
+	Sessions   int
+	Blocks     int
+	Approvals  int
+	Recovered  int
+	Repeated   int
+	// NoRetry counts blocks the agent never followed with the same tool
+	NoRetry int
+}
+
+// RetrySuccessRate is the share of blocks the agent recovered from, or -1
+// when the variant has no blocks
+func (r VariantResult) RetrySuccessRate() float64 {
+	if r.Blocks == 0 {
+		return -1
+	}
+	return float64(r.Recovered) / float64(r.Blocks)
+}
+
+// AssignVariant picks the session's variant of e. The choice is a hash of
+// the session id, so it is stable for a session and spread evenly across
+// sessions; without a session id a variant is picked at random.
+func AssignVariant(e config.Experiment, sessionID string) string {
+	names := e.VariantNames()
+	if len(names) == 0 {
+		return ""
+	}
+	if sessionID == "" {
+		return names[rand.IntN(len(names))] // #nosec G404 - variant assignment, not security
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Name + "\x00" + sessionID))
+	return names[int(h.Sum32()%uint32(len(names)))]
+}
+
+// experimentMessage renders a variant template; a template that fails
+// keeps the hook's own message
+func experimentMessage(text, message, hook, tool string) string {
+	tmpl, err := template.New("variant").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return message
+	}
+	var b bytes.Buffer
+	data := map[string]string{"Message": message, "Hook": hook, "Tool": tool}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return message
+	}
+	return b.String()
+}
+
+// activeExperiments returns the enabled experiments for this hook
+func (h *BaseHook) activeExperiments() []config.Experiment {
+	var out []config.Experiment
+	for _, e := range h.Context().Experiments {
+		if !e.Disabled && e.Hook == h.Key() && len(e.Variants) > 0 {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// withExperiments wraps the handlers so that responses in sessions under
+// an experiment carry the assigned variant's message and are recorded
+func (h *BaseHook) withExperiments(
+	preHandler func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	postHandler func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+) (
+	func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+) {
+	experiments := h.activeExperiments()
+	if len(experiments) == 0 {
+		return preHandler, postHandler
+	}
+
+	pre := preHandler
+	if preHandler != nil {
+		pre = func(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			resp := preHandler(ctx, event)
+			for _, e := range experiments {
+				resp = h.applyPreExperiment(e, event, resp)
+			}
+			return resp
+		}
+	}
+	post := postHandler
+	if postHandler != nil {
+		post = func(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			resp := postHandler(ctx, event)
+			for _, e := range experiments {
+				resp = h.applyPostExperiment(e, event, resp)
+			}
+			return resp
+		}
+	}
+	return pre, post
+}
+
+func (h *BaseHook) applyPreExperiment(e config.Experiment, event *cchooks.PreToolUseEvent, resp cchooks.PreToolUseResponseInterface) cchooks.PreToolUseResponseInterface {
+	decision := config.ExperimentDecisionApprove
+	message := ""
+	switch r := resp.(type) {
+	case *AskPreToolResponse:
+		return resp
+	case *DualMessagePreToolResponse:
+		message = r.agentMessage
+		if r.Decision == cchooks.PreToolUseBlock {
+			decision = config.ExperimentDecisionBlock
+		}
+	case *cchooks.PreToolUseResponse:
+		message = r.Reason
+		switch r.Decision {
+		case cchooks.PreToolUseBlock:
+			decision = config.ExperimentDecisionBlock
+		case PreToolUseAsk:
+			return resp
+		}
+	case nil:
+	default:
+		return resp
+	}
+
+	variant := AssignVariant(e, event.SessionID)
+	rewritten := e.MatchesDecision(decision)
+	if rewritten {
+		text := experimentMessage(e.Variants[variant], message, h.Key(), event.ToolName)
+		switch r := resp.(type) {
+		case *DualMessagePreToolResponse:
+			r.Reason, r.agentMessage = text, text
+		case *cchooks.PreToolUseResponse:
+			r.Reason = text
+		case nil:
+			resp = ApproveWithMessages(text)
+		}
+	}
+	recordExperimentBestEffort(ExperimentRecord{
+		Experiment: e.Name, Variant: variant, SessionID: event.SessionID, Hook: h.Key(),
+		Event: string(PreToolUseEvent), Tool: event.ToolName, Decision: decision, Rewritten: rewritten,
+	})
+	return resp
+}
+
+func (h *BaseHook) applyPostExperiment(e config.Experiment, event *cchooks.PostToolUseEvent, resp cchooks.PostToolUseResponseInterface) cchooks.PostToolUseResponseInterface {
+	decision := config.ExperimentDecisionApprove
+	message := ""
+	switch r := resp.(type) {
+	case *DualMessagePostToolResponse:
+		message = r.agentMessage
+		if r.Decision == cchooks.PostToolUseBlock {
+			decision = config.ExperimentDecisionBlock
+		}
+	case *cchooks.PostToolUseResponse:
+		message = r.Reason
+		switch r.Decision {
+		case cchooks.PostToolUseBlock:
+			decision = config.ExperimentDecisionBlock
+		case PostToolUseAsk:
+			return resp
+		}
+	case nil:
+	default:
+		return resp
+	}
+
+	variant := AssignVariant(e, event.SessionID)
+	rewritten := e.MatchesDecision(decision)
+	if rewritten {
+		text := experimentMessage(e.Variants[variant], message, h.Key(), event.ToolName)
+		switch r := resp.(type) {
+		case *DualMessagePostToolResponse:
+			r.Reason, r.agentMessage = text, text
+		case *cchooks.PostToolUseResponse:
+			r.Reason = text
+		case nil:
+			resp = AllowWithMessages(text)
+		}
+	}
+	recordExperimentBestEffort(ExperimentRecord{
+		Experiment: e.Name, Variant: variant, SessionID: event.SessionID, Hook: h.Key(),
+		Event: string(PostToolUseEvent), Tool: event.ToolName, Decision: decision, Rewritten: rewritten,
+	})
+	return resp
+}
+
+func recordExperimentBestEffort(rec ExperimentRecord) {
+	_ = RecordExperiment(rec)
+}
+
+// RecordExperiment appends rec to the experiments log in the cache dir
+func RecordExperiment(rec ExperimentRecord) error {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	path, err := experimentsPath()
+	if err != nil {
+		return err
+	}
+	release, err := AcquireNamedLock(experimentsLockName, experimentsLockWait)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := ensureCacheDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode experiment record: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 - fixed file under the cache dir
+	if err != nil {
+		return fmt.Errorf("failed to open experiments log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write experiments log: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadExperimentRecords returns the recorded responses, oldest first.
+// Lines that don't parse are skipped.
+func LoadExperimentRecords() ([]ExperimentRecord, error) {
+	path, err := experimentsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path) // #nosec G304 - fixed file under the cache dir
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiments log: %w", err)
+	}
+	defer f.Close()
+
+	var records []ExperimentRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec ExperimentRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+			records = append(records, rec)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read experiments log: %w", err)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+// ResetExperimentRecords discards recorded results
+func ResetExperimentRecords() error {
+	path, err := experimentsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset experiment results: %w", err)
+	}
+	return nil
+}
+
+// SummarizeExperiments groups records (oldest first) by experiment and
+// variant, sorted by name
+func SummarizeExperiments(records []ExperimentRecord) []VariantResult {
+	type key struct{ experiment, variant string }
+	results := map[key]*VariantResult{}
+	sessions := map[key]map[string]bool{}
+	for i, rec := range records {
+		k := key{rec.Experiment, rec.Variant}
+		r := results[k]
+		if r == nil {
+			r = &VariantResult{Experiment: rec.Experiment, Variant: rec.Variant}
+			results[k] = r
+			sessions[k] = map[string]bool{}
+		}
+		sessions[k][rec.SessionID] = true
+		if rec.Decision != config.ExperimentDecisionBlock {
+			r.Approvals++
+			continue
+		}
+		r.Blocks++
+		switch nextExperimentDecision(records[i+1:], rec) {
+		case config.ExperimentDecisionApprove:
+			r.Recovered++
+		case config.ExperimentDecisionBlock:
+			r.Repeated++
+		default:
+			r.NoRetry++
+		}
+	}
+
+	out := make([]VariantResult, 0, len(results))
+	for k, r := range results {
+		r.Sessions = len(sessions[k])
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Experiment != out[j].Experiment {
+			return out[i].Experiment < out[j].Experiment
+		}
+		return out[i].Variant < out[j].Variant
+	})
+	return out
+}
+
+// nextExperimentDecision finds the decision for the agent's next call of
+// the same tool through the same hook in the same session
+func nextExperimentDecision(later []ExperimentRecord, rec ExperimentRecord) string {
+	if rec.SessionID == "" {
+		return ""
+	}
+	for _, next := range later {
+		if next.Experiment == rec.Experiment && next.SessionID == rec.SessionID &&
+			next.Hook == rec.Hook && next.Event == rec.Event && next.Tool == rec.Tool {
+			return next.Decision
+		}
+	}
+	return ""
+}
+
+func experimentsPath() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, experimentsFile), nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func testExperiment() config.Experiment {
+	return config.Experiment{
+		Name: "wording",
+		Hook: "security",
+		Variants: map[string]string{
+			"a": "{{.Message}}",
+			"b": "{{.Message}} Try a safer {{.Tool}} command.",
+		},
+	}
+}
+
+func TestAssignVariant(t *testing.T) {
+	e := testExperiment()
+	seen := map[string]int{}
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		v := AssignVariant(e, id)
+		if v != AssignVariant(e, id) {
+			t.Fatalf("variant for %s is not stable", id)
+		}
+		seen[v]++
+	}
+	if len(seen) != 2 || seen["a"] < 50 || seen["b"] < 50 {
+		t.Errorf("variants are not spread across sessions: %v", seen)
+	}
+	if v := AssignVariant(e, ""); v != "a" && v != "b" {
+		t.Errorf("unexpected variant %q without a session", v)
+	}
+}
+
+func TestRunner_ExperimentRewritesBlocksAndRecords(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	ctx := TestHookContext(nil)
+	ctx.Experiments = []config.Experiment{testExperiment()}
+	hook := NewBaseHook("security", "Security", "", ctx)
+
+	block := true
+	runner := hook.Runner(func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+		if block {
+			return BlockWithMessages("rm -rf is not allowed")
+		}
+		return cchooks.Approve()
+	}, nil, nil).(*MockRunner)
+
+	// Find a session assigned to variant b so the rewrite is visible
+	session := ""
+	for i := 0; session == ""; i++ {
+		if id := fmt.Sprintf("s%d", i); AssignVariant(testExperiment(), id) == "b" {
+			session = id
+		}
+	}
+	event := &cchooks.PreToolUseEvent{SessionID: session, ToolName: "Bash"}
+	got := SummarizeResponse(runner.PreToolUse(context.Background(), event))
+	if got.Decision != cchooks.PreToolUseBlock || got.AgentMessage != "rm -rf is not allowed Try a safer Bash command." {
+		t.Errorf("block = %+v, want variant b message", got)
+	}
+	if got.UserMessage != "rm -rf is not allowed" {
+		t.Errorf("user message should be untouched, got %q", got.UserMessage)
+	}
+
+	block = false
+	if resp := SummarizeResponse(runner.PreToolUse(context.Background(), event)); resp.Decision != cchooks.PreToolUseApprove || resp.AgentMessage != "" {
+		t.Errorf("approve should pass through a block experiment, got %+v", resp)
+	}
+
+	records, err := LoadExperimentRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Decision != "block" || !records[0].Rewritten || records[1].Decision != "approve" || records[1].Rewritten {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	results := SummarizeExperiments(records)
+	if len(results) != 1 || results[0].Variant != "b" || results[0].Recovered != 1 || results[0].RetrySuccessRate() != 1 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	other := NewBaseHook("format", "Format", "", ctx)
+	plain := other.Runner(func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+		return cchooks.Block("no")
+	}, nil, nil).(*MockRunner)
+	if resp := SummarizeResponse(plain.PreToolUse(context.Background(), event)); resp.AgentMessage != "no" {
+		t.Errorf("hooks outside the experiment should be untouched, got %+v", resp)
+	}
+}
+
+func TestSummarizeExperiments(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec := func(i int, variant, session, tool, decision string) ExperimentRecord {
+		return ExperimentRecord{
+			Time: base.Add(time.Duration(i) * time.Second), Experiment: "x", Variant: variant,
+			SessionID: session, Hook: "security", Event: "PreToolUse", Tool: tool, Decision: decision,
+		}
+	}
+	records := []ExperimentRecord{
+		rec(0, "a", "s1", "Bash", "block"),
+		rec(1, "a", "s1", "Write", "approve"),
+		rec(2, "a", "s1", "Bash", "block"),
+		rec(3, "a", "s1", "Bash", "approve"),
+		rec(4, "a", "s2", "Bash", "block"),
+		rec(5, "b", "s3", "Bash", "approve"),
+	}
+	results := SummarizeExperiments(records)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 variants, got %+v", results)
+	}
+	a := results[0]
+	if a.Sessions != 2 || a.Blocks != 3 || a.Repeated != 1 || a.Recovered != 1 || a.NoRetry != 1 || a.Approvals != 2 {
+		t.Errorf("unexpected variant a result %+v", a)
+	}
+	if b := results[1]; b.Blocks != 0 || b.RetrySuccessRate() != -1 {
+		t.Errorf("unexpected variant b result %+v", b)
+	}
+}
@@ -4,40 +4,43 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-// EvalExpression evaluates a minimal boolean expression used for skip/only conditions.
-// Supported:
-// - variable substitution: ${VAR}
-// - operators: ==, !=, matches
-// - boolean: &&, ||, ! (unary)
-// - glob matching for right-hand side of matches
-// This is intentionally simple; not a full parser. Expressions should be small.
+// EvalExpression evaluates a boolean expression used for skip/only
+// conditions. An empty expression is true. Supported, loosest binding first:
+//   - boolean: ||, &&, then unary ! or not
+//   - comparisons: ==, !=, matches (glob), regex, and <, <=, >, >= on numbers
+//   - parentheses for grouping, and condition functions: name(args), see
+//     RegisterConditionFunc
+//
+// Negation binds looser than comparisons, so !${A} == b means !(${A} == b).
+// ${VAR} references are replaced inside words and quoted strings. The right
+// side of matches and regex may be left unquoted and is read up to the next
+// space, so patterns like ^(Edit|Write)$ need no quoting.
 func EvalExpression(expr string, vars map[string]string) (bool, error) {
-	s := strings.TrimSpace(expr)
-	if s == "" {
+	node, err := parseExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	if node == nil {
 		return true, nil
 	}
+	return node.evalBool(vars)
+}
 
-	// Expand variables: ${VAR}
-	expanded := expandVars(s, vars)
-	// Tokenize by || first (lowest precedence)
-	orParts := splitRespectingQuotes(expanded, "||")
-	anyMatch := false
-	for _, orp := range orParts {
-		andParts := splitRespectingQuotes(orp, "&&")
-		all := true
-		for _, ap := range andParts {
-			v, err := evalSimple(strings.TrimSpace(ap))
-			if err != nil {
-				return false, err
-			}
-			all = all && v
-		}
-		anyMatch = anyMatch || all
+// CheckExpression reports syntax errors in a skip/only expression without
+// evaluating it: unbalanced quotes or parentheses, empty operands around
+// && and ||, operators missing a side, unknown condition functions, and
+// regex, glob or number literals that don't parse. Operands holding ${VAR}
+// are only known at run time and are not checked.
+func CheckExpression(expr string) error {
+	node, err := parseExpression(expr)
+	if err != nil || node == nil {
+		return err
 	}
-	return anyMatch, nil
+	return node.check()
 }
 
 func expandVars(s string, vars map[string]string) string {
@@ -52,87 +55,240 @@ func expandVars(s string, vars map[string]string) string {
 
 var varPattern = regexp.MustCompile(`\$\{[A-Za-z_][A-Za-z0-9_]*\}`)
 
-func evalSimple(s string) (bool, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return true, nil
+// exprNode is one node of a parsed expression
+type exprNode interface {
+	evalBool(vars map[string]string) (bool, error)
+	// evalString is the node's value as a comparison operand
+	evalString(vars map[string]string) (string, error)
+	// check reports problems visible before run time
+	check() error
+}
+
+// exprLogical is a && b or a || b
+type exprLogical struct {
+	op          string
+	left, right exprNode
+}
+
+// exprNot is !a or not a
+type exprNot struct {
+	operand exprNode
+}
+
+// exprCompare is a comparison such as a == b or a regex b
+type exprCompare struct {
+	op          string
+	left, right exprNode
+}
+
+// exprCall is a condition function call
+type exprCall struct {
+	name string
+	args []exprNode
+}
+
+// exprValue is a word or quoted string, possibly holding ${VAR} references
+type exprValue struct {
+	text   string
+	quoted bool
+}
+
+func (n *exprLogical) evalBool(vars map[string]string) (bool, error) {
+	left, err := n.left.evalBool(vars)
+	if err != nil {
+		return false, err
 	}
+	if (n.op == "&&") != left {
+		return left, nil
+	}
+	return n.right.evalBool(vars)
+}
 
-	// Handle unary negation
-	if result, handled, err := handleNegation(s); handled {
-		return result, err
+func (n *exprNot) evalBool(vars map[string]string) (bool, error) {
+	v, err := n.operand.evalBool(vars)
+	return !v, err
+}
+
+func (n *exprCompare) evalBool(vars map[string]string) (bool, error) {
+	left, err := n.left.evalString(vars)
+	if err != nil {
+		return false, err
+	}
+	right, err := n.right.evalString(vars)
+	if err != nil {
+		return false, err
+	}
+	switch n.op {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	case "matches":
+		return globMatchAny(left, right), nil
+	case "regex":
+		return regexMatchAny(left, right)
+	}
+	a, errA := strconv.ParseFloat(strings.TrimSpace(left), 64)
+	b, errB := strconv.ParseFloat(strings.TrimSpace(right), 64)
+	if errA != nil || errB != nil {
+		return false, fmt.Errorf("'%s' compares numbers, got %q and %q", n.op, left, right)
 	}
+	switch n.op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	default:
+		return a >= b, nil
+	}
+}
 
-	// Try operator-based evaluation
-	if result, handled, err := evalOperator(s); handled {
-		return result, err
+func (n *exprCall) evalBool(vars map[string]string) (bool, error) {
+	conditionFuncs.mu.RLock()
+	fn, ok := conditionFuncs.funcs[n.name]
+	conditionFuncs.mu.RUnlock()
+	if !ok {
+		return false, unknownConditionFunc(n.name)
 	}
+	args := make([]string, len(n.args))
+	for i, a := range n.args {
+		if isFileArg(a) {
+			args[i] = currentFile(vars)
+			continue
+		}
+		v, err := a.evalString(vars)
+		if err != nil {
+			return false, err
+		}
+		args[i] = v
+	}
+	result, err := fn(args, vars)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return result, nil
+}
 
-	// Bareword/literal evaluation
-	return evalLiteral(s)
+func (n *exprValue) evalBool(vars map[string]string) (bool, error) {
+	v, _ := n.evalString(vars)
+	return truthy(v), nil
 }
 
-// handleNegation handles unary ! operator with recursion
-func handleNegation(s string) (bool, bool, error) {
-	negated := false
-	for strings.HasPrefix(s, "!") {
-		negated = !negated
-		s = strings.TrimPrefix(s, "!")
-	}
+func (n *exprLogical) evalString(vars map[string]string) (string, error) { return boolString(n, vars) }
+func (n *exprNot) evalString(vars map[string]string) (string, error)     { return boolString(n, vars) }
+func (n *exprCompare) evalString(vars map[string]string) (string, error) { return boolString(n, vars) }
+func (n *exprCall) evalString(vars map[string]string) (string, error)    { return boolString(n, vars) }
+
+func (n *exprValue) evalString(vars map[string]string) (string, error) {
+	return expandVars(n.text, vars), nil
+}
+
+// boolString renders a boolean node as "true" or "false" for comparisons
+func boolString(n exprNode, vars map[string]string) (string, error) {
+	v, err := n.evalBool(vars)
+	return strconv.FormatBool(v), err
+}
 
-	if !negated {
-		return false, false, nil // Not a negation case
+// truthy is false for "", "false" and "0" in any case, true otherwise
+func truthy(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "false", "0":
+		return false
 	}
+	return true
+}
 
-	inner, err := evalSimple(s)
-	if err != nil {
-		return false, true, err
+func (n *exprLogical) check() error {
+	if err := n.left.check(); err != nil {
+		return err
 	}
-	return !inner, true, nil
+	return n.right.check()
 }
 
-// evalOperator evaluates expressions with operators ==, !=, matches, regex
-func evalOperator(s string) (bool, bool, error) {
-	operators := []string{"==", "!=", "matches", "regex"}
-	for _, op := range operators {
-		idx := indexOutsideQuotes(s, op)
-		if idx < 0 {
-			continue
+func (n *exprNot) check() error { return n.operand.check() }
+
+func (n *exprCompare) check() error {
+	if err := n.left.check(); err != nil {
+		return err
+	}
+	if err := n.right.check(); err != nil {
+		return err
+	}
+	switch n.op {
+	case "==", "!=":
+		return nil
+	case "matches", "regex":
+		return checkPattern(n.op, n.right)
+	}
+	for _, side := range []exprNode{n.left, n.right} {
+		if v, ok := literalValue(side); ok {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return fmt.Errorf("'%s' compares numbers, got %q", n.op, v)
+			}
 		}
+	}
+	return nil
+}
 
-		left := strings.TrimSpace(s[:idx])
-		right := strings.Trim(strings.TrimSpace(s[idx+len(op):]), "\"'")
-
-		switch op {
-		case "==":
-			return left == right, true, nil
-		case "!=":
-			return left != right, true, nil
-		case "matches":
-			return globMatchAny(left, right), true, nil
-		case "regex":
-			result, err := regexMatchAny(left, right)
-			return result, true, err
+func (n *exprCall) check() error {
+	conditionFuncs.mu.RLock()
+	_, ok := conditionFuncs.funcs[n.name]
+	conditionFuncs.mu.RUnlock()
+	if !ok {
+		return unknownConditionFunc(n.name)
+	}
+	for _, a := range n.args {
+		if err := a.check(); err != nil {
+			return err
 		}
 	}
-	return false, false, nil // No operator found
+	if n.name == "matches" && len(n.args) == 2 {
+		return checkPattern("matches", n.args[1])
+	}
+	return nil
 }
 
-// evalLiteral evaluates a literal value as truthy/falsy
-func evalLiteral(s string) (bool, error) {
-	l := strings.ToLower(strings.Trim(s, "\"'"))
+func (n *exprValue) check() error { return nil }
 
-	if l == "false" || l == "0" {
-		return false, nil
+// checkPattern compiles the glob or regex pattern of op when it is known
+// before run time
+func checkPattern(op string, pattern exprNode) error {
+	p, ok := literalValue(pattern)
+	if !ok {
+		return nil
 	}
-	if l == "true" || l == "1" {
-		return true, nil
+	if op == "regex" {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid regex pattern %q: %v", p, err)
+		}
+		return nil
 	}
-	if l != "" {
-		return true, nil
+	if _, err := filepath.Match(p, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %q: %v", p, err)
 	}
+	return nil
+}
+
+// literalValue returns the text of a value without ${VAR} references
+func literalValue(n exprNode) (string, bool) {
+	v, ok := n.(*exprValue)
+	if !ok || varPattern.MatchString(v.text) {
+		return "", false
+	}
+	return v.text, true
+}
 
-	return false, fmt.Errorf("could not evaluate expression: %q", s)
+// isFileArg reports whether a function argument is the bare word file,
+// which stands for the file the job is running for
+func isFileArg(n exprNode) bool {
+	v, ok := n.(*exprValue)
+	return ok && !v.quoted && v.text == "file"
+}
+
+func unknownConditionFunc(name string) error {
+	return fmt.Errorf("unknown condition function '%s' (available: %s)", name, strings.Join(ConditionFuncNames(), ", "))
 }
 
 func globMatchAny(left string, pattern string) bool {
@@ -165,52 +321,3 @@ func regexMatchAny(left string, pattern string) (bool, error) {
 	}
 	return false, nil
 }
-
-// splitRespectingQuotes splits on a delimiter while keeping quoted substrings intact
-func splitRespectingQuotes(s, delim string) []string {
-	var parts []string
-	var cur strings.Builder
-	inSingle, inDouble := false, false
-	i := 0
-	for i < len(s) {
-		if s[i] == '\'' && !inDouble {
-			inSingle = !inSingle
-			cur.WriteByte(s[i])
-			i++
-			continue
-		}
-		if s[i] == '"' && !inSingle {
-			inDouble = !inDouble
-			cur.WriteByte(s[i])
-			i++
-			continue
-		}
-		if !inSingle && !inDouble && strings.HasPrefix(s[i:], delim) {
-			parts = append(parts, cur.String())
-			cur.Reset()
-			i += len(delim)
-			continue
-		}
-		cur.WriteByte(s[i])
-		i++
-	}
-	parts = append(parts, cur.String())
-	return parts
-}
-
-// indexOutsideQuotes finds index of substr outside quotes
-func indexOutsideQuotes(s, sub string) int {
-	inSingle, inDouble := false, false
-	for i := 0; i+len(sub) <= len(s); i++ {
-		c := s[i]
-		if c == '\'' && !inDouble {
-			inSingle = !inSingle
-		} else if c == '"' && !inSingle {
-			inDouble = !inDouble
-		}
-		if !inSingle && !inDouble && strings.HasPrefix(s[i:], sub) {
-			return i
-		}
-	}
-	return -1
-}
@@ -0,0 +1,353 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expression grammar, loosest binding first:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = ( "!" | "not" ) unary | compare
+//	compare = operand [ op operand ]     op: == != < <= > >= matches regex
+//	operand = "(" or ")" | name "(" [ or { "," or } ] ")" | word | string
+
+// exprTokenKind classifies a lexed token
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokOp                // && || ! == != < <= > >=
+	tokLParen
+	tokRParen
+	tokComma
+	tokWord
+	tokString
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+}
+
+// exprSymbols are the operator tokens, longest first so "<=" wins over "<"
+var exprSymbols = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!"}
+
+// compareWords are the comparison operators spelled as words
+var compareWords = map[string]bool{"matches": true, "regex": true}
+
+// exprParser is a recursive descent parser that lexes on demand, so the
+// right side of matches and regex can be read as a raw pattern
+type exprParser struct {
+	src    string
+	pos    int
+	peeked *exprToken
+}
+
+// parseExpression parses expr; an empty expression parses to nil
+func parseExpression(expr string) (exprNode, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	p := &exprParser{src: expr}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case tokEOF:
+		return node, nil
+	case tokRParen:
+		return nil, fmt.Errorf("unbalanced parentheses in %q", expr)
+	}
+	return nil, fmt.Errorf("unexpected %q in %q", tok.text, expr)
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseLogical("&&", p.parseUnary)
+}
+
+func (p *exprParser) parseLogical(op string, operand func() (exprNode, error)) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind != tokOp || tok.text != op {
+			return left, nil
+		}
+		p.peeked = nil
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprLogical{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if (tok.kind == tokOp && tok.text == "!") || (tok.kind == tokWord && tok.text == "not") {
+		p.peeked = nil
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	isOp := tok.kind == tokOp && tok.text != "&&" && tok.text != "||" && tok.text != "!"
+	if !isOp && (tok.kind != tokWord || !compareWords[tok.text]) {
+		return left, nil
+	}
+	p.peeked = nil
+
+	var right exprNode
+	if compareWords[tok.text] {
+		right, err = p.rawPattern(tok.text)
+	} else {
+		if next, err := p.peek(); err != nil {
+			return nil, err
+		} else if next.kind == tokEOF || next.kind == tokRParen || next.kind == tokComma || (next.kind == tokOp && next.text != "!") {
+			return nil, fmt.Errorf("'%s' needs a value on both sides in %q", tok.text, p.src)
+		}
+		right, err = p.parseOperand()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exprCompare{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseOperand() (exprNode, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case tokString:
+		return &exprValue{text: tok.text, quoted: true}, nil
+	case tokWord:
+		if compareWords[tok.text] && !p.atByte('(') {
+			return nil, fmt.Errorf("'%s' needs a value on both sides in %q", tok.text, p.src)
+		}
+		if p.atByte('(') {
+			return p.parseCall(tok.text)
+		}
+		return &exprValue{text: tok.text}, nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectRParen(); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokOp:
+		if tok.text == "&&" || tok.text == "||" {
+			return nil, fmt.Errorf("empty operand around && or || in %q", p.src)
+		}
+		return nil, fmt.Errorf("'%s' needs a value on both sides in %q", tok.text, p.src)
+	case tokRParen:
+		return nil, fmt.Errorf("unbalanced parentheses in %q", p.src)
+	case tokComma:
+		return nil, fmt.Errorf("unexpected ',' in %q", p.src)
+	}
+	return nil, fmt.Errorf("empty operand around && or || in %q", p.src)
+}
+
+// parseCall parses the argument list of name(...)
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	if !conditionCallPattern.MatchString(name + "()") {
+		return nil, fmt.Errorf("invalid function name %q in %q", name, p.src)
+	}
+	p.peeked = nil
+	if _, err := p.next(); err != nil { // the '('
+		return nil, err
+	}
+	call := &exprCall{name: name}
+	if tok, err := p.peek(); err != nil {
+		return nil, err
+	} else if tok.kind == tokRParen {
+		p.peeked = nil
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return call, nil
+		case tokEOF:
+			return nil, fmt.Errorf("unbalanced parentheses in %q", p.src)
+		}
+		return nil, fmt.Errorf("unexpected %q in arguments of %s() in %q", tok.text, name, p.src)
+	}
+}
+
+func (p *exprParser) expectRParen() error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokRParen {
+		return fmt.Errorf("unbalanced parentheses in %q", p.src)
+	}
+	return nil
+}
+
+// rawPattern reads the right side of matches or regex: a quoted string, or
+// everything up to the next space, && or || outside parentheses, so
+// unquoted patterns may hold ( ) | and [ ]
+func (p *exprParser) rawPattern(op string) (exprNode, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		return &exprValue{text: tok.text, quoted: true}, nil
+	}
+	start, depth := p.pos, 0
+scan:
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch c := p.src[p.pos]; {
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n' || c == ','):
+			break scan
+		case depth == 0 && (strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||")):
+			break scan
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				break scan
+			}
+			depth--
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, fmt.Errorf("'%s' needs a value on both sides in %q", op, p.src)
+	}
+	return &exprValue{text: p.src[start:p.pos]}, nil
+}
+
+func (p *exprParser) peek() (exprToken, error) {
+	if p.peeked == nil {
+		tok, err := p.lex()
+		if err != nil {
+			return exprToken{}, err
+		}
+		p.peeked = &tok
+	}
+	return *p.peeked, nil
+}
+
+func (p *exprParser) next() (exprToken, error) {
+	tok, err := p.peek()
+	p.peeked = nil
+	return tok, err
+}
+
+// atByte reports whether the unread input starts with c, with no space
+// before it
+func (p *exprParser) atByte(c byte) bool {
+	return p.peeked == nil && p.pos < len(p.src) && p.src[p.pos] == c
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) lex() (exprToken, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return exprToken{kind: tokEOF}, nil
+	}
+	rest := p.src[p.pos:]
+	switch c := rest[0]; c {
+	case '(':
+		p.pos++
+		return exprToken{kind: tokLParen, text: "("}, nil
+	case ')':
+		p.pos++
+		return exprToken{kind: tokRParen, text: ")"}, nil
+	case ',':
+		p.pos++
+		return exprToken{kind: tokComma, text: ","}, nil
+	case '"', '\'':
+		end := strings.IndexByte(rest[1:], c)
+		if end < 0 {
+			return exprToken{}, fmt.Errorf("unterminated %c quote", c)
+		}
+		p.pos += end + 2
+		return exprToken{kind: tokString, text: rest[1 : end+1]}, nil
+	}
+	for _, sym := range exprSymbols {
+		if strings.HasPrefix(rest, sym) {
+			p.pos += len(sym)
+			return exprToken{kind: tokOp, text: sym}, nil
+		}
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !p.wordEnd() {
+		p.pos++
+	}
+	return exprToken{kind: tokWord, text: p.src[start:p.pos]}, nil
+}
+
+// wordEnd reports whether a bare word stops at the current position
+func (p *exprParser) wordEnd() bool {
+	c := p.src[p.pos]
+	if unicode.IsSpace(rune(c)) || strings.IndexByte("(),\"'", c) >= 0 {
+		return true
+	}
+	rest := p.src[p.pos:]
+	for _, sym := range exprSymbols {
+		if sym != "!" && strings.HasPrefix(rest, sym) {
+			return true
+		}
+	}
+	return false
+}
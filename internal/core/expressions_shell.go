@@ -0,0 +1,202 @@
+package core
+
+import (
+	"strings"
+)
+
+// ExpressionToShell translates a skip/only expression into an equivalent
+// bash condition. The result relies on the helper functions emitted by
+// ShellExpressionHelpers (bt_glob_any, bt_regex_any, bt_truthy, ...).
+//
+// The parsed expression keeps its grouping and precedence. Globs use shell
+// case patterns, where '*' also matches '/', and regex patterns are handed
+// to `grep -E`, so RE2-only syntax may behave differently in the shell. An
+// expression that does not parse becomes a condition that reports the
+// error and is false.
+func ExpressionToShell(expr string) string {
+	node, err := parseExpression(expr)
+	if err != nil {
+		return "{ echo " + shellSingleQuote("invalid condition: "+err.Error()) + " >&2; false; }"
+	}
+	if node == nil {
+		return "true"
+	}
+	return nodeToShell(node)
+}
+
+// nodeToShell renders one node as a shell condition
+func nodeToShell(n exprNode) string {
+	switch n := n.(type) {
+	case *exprLogical:
+		return "{ " + nodeToShell(n.left) + " " + n.op + " " + nodeToShell(n.right) + "; }"
+	case *exprNot:
+		return "! { " + nodeToShell(n.operand) + "; }"
+	case *exprCompare:
+		return compareToShell(n)
+	case *exprCall:
+		return callToShell(n)
+	case *exprValue:
+		return "bt_truthy " + shellDoubleQuote(n.text)
+	}
+	return "false"
+}
+
+// shellWord renders a node as one shell word: values keep their ${VAR}
+// references live, and conditions become "true" or "false"
+func shellWord(n exprNode) string {
+	if v, ok := n.(*exprValue); ok {
+		return shellDoubleQuote(v.text)
+	}
+	return `"$(if ` + nodeToShell(n) + `; then echo true; else echo false; fi)"`
+}
+
+func compareToShell(n *exprCompare) string {
+	left, right := shellWord(n.left), shellWord(n.right)
+	switch n.op {
+	case "==":
+		return "[ " + left + " = " + right + " ]"
+	case "!=":
+		return "[ " + left + " != " + right + " ]"
+	case "matches":
+		return "bt_glob_any " + left + " " + right
+	case "regex":
+		return "bt_regex_any " + left + " " + right
+	}
+	return "bt_num_cmp " + left + " " + shellSingleQuote(n.op) + " " + right
+}
+
+// ShellExpressionHelpers returns bash function definitions used by conditions
+// produced by ExpressionToShell.
+func ShellExpressionHelpers() string {
+	return `# bt_glob_any VALUE PATTERN: true if any whitespace-separated token matches the glob
+bt_glob_any() (
+  set -f
+  # shellcheck disable=SC2254
+  if [ -z "$1" ]; then case "" in $2) exit 0 ;; esac; exit 1; fi
+  for tok in $1; do
+    # shellcheck disable=SC2254
+    case "$tok" in $2) exit 0 ;; esac
+  done
+  exit 1
+)
+
+# bt_regex_any VALUE REGEX: true if any whitespace-separated token matches the regex
+bt_regex_any() (
+  set -f
+  if [ -z "$1" ]; then printf '\n' | grep -Eq -- "$2"; exit $?; fi
+  for tok in $1; do
+    printf '%s\n' "$tok" | grep -Eq -- "$2" && exit 0
+  done
+  exit 1
+)
+
+# bt_truthy VALUE: false for "", "false" and "0"; true otherwise
+bt_truthy() {
+  case "$(printf '%s' "$1" | tr '[:upper:]' '[:lower:]')" in
+    ""|false|0) return 1 ;;
+  esac
+  return 0
+}
+
+# bt_num_cmp A OP B: numeric comparison; false with a message when A or B is not a number
+bt_num_cmp() {
+  for n in "$1" "$3"; do
+    printf '%s\n' "$n" | grep -Eq '^[[:space:]]*[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?[[:space:]]*$' || {
+      echo "'$2' compares numbers, got '$n'" >&2
+      return 1
+    }
+  done
+  awk -v a="$1" -v b="$3" -v op="$2" 'BEGIN {
+    a += 0; b += 0
+    if (op == "<") r = a < b; else if (op == "<=") r = a <= b; else if (op == ">") r = a > b; else r = a >= b
+    exit !r
+  }'
+}
+
+# bt_contains VALUE TEXT, bt_startswith VALUE PREFIX, bt_endswith VALUE SUFFIX
+bt_contains() { case "$1" in *"$2"*) return 0 ;; esac; return 1; }
+bt_startswith() { case "$1" in "$2"*) return 0 ;; esac; return 1; }
+bt_endswith() { case "$1" in *"$2") return 0 ;; esac; return 1; }
+
+# bt_is_generated FILE: true for generated file names or a generated-code marker
+bt_is_generated() {
+  [ -n "$1" ] || return 1
+  case "${1##*/}" in
+    *.pb.go|*_gen.go|*.gen.*|*_generated.*|*.generated.*|zz_generated*|*.min.js|*.min.css) return 0 ;;
+  esac
+  [ -f "$1" ] && head -c 4096 "$1" | grep -Eq 'Code generated .* DO NOT EDIT\.|@generated'
+}
+
+# bt_in_directory FILE DIR: true if FILE lies under a directory named DIR at any depth
+bt_in_directory() {
+  [ -n "$1" ] && [ -n "$2" ] || return 1
+  f="${1#"${PROJECT_ROOT:-$PWD}"/}"
+  d="${2#/}"; d="${d%/}"
+  case "/${f#/}/" in */"$d"/*) return 0 ;; esac
+  return 1
+}
+
+# bt_branch_matches REGEX: true if the current git branch matches REGEX
+bt_branch_matches() {
+  git rev-parse --abbrev-ref HEAD 2>/dev/null | grep -Eq -- "$1"
+}
+`
+}
+
+// shellDoubleQuote wraps an operand in double quotes, keeping ${VAR}
+// references live (with a default so `set -u` does not abort) and escaping
+// anything else the shell would interpret.
+func shellDoubleQuote(operand string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	last := 0
+	for _, loc := range varPattern.FindAllStringIndex(operand, -1) {
+		b.WriteString(escapeForDoubleQuotes(operand[last:loc[0]]))
+		name := operand[loc[0]+2 : loc[1]-1]
+		b.WriteString("${" + name + ":-}")
+		last = loc[1]
+	}
+	b.WriteString(escapeForDoubleQuotes(operand[last:]))
+	b.WriteByte('"')
+	return b.String()
+}
+
+func escapeForDoubleQuotes(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return r.Replace(s)
+}
+
+// shellSingleQuote quotes a literal so the shell passes it through unchanged
+func shellSingleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellConditionFuncs maps built-in condition functions to their helpers in
+// ShellExpressionHelpers. Functions registered at runtime have no shell form.
+var shellConditionFuncs = map[string]string{
+	"is_generated":   "bt_is_generated",
+	"in_directory":   "bt_in_directory",
+	"branch_matches": "bt_branch_matches",
+	"contains":       "bt_contains",
+	"startswith":     "bt_startswith",
+	"endswith":       "bt_endswith",
+	"matches":        "bt_glob_any",
+}
+
+// callToShell converts name(args) to a helper call
+func callToShell(n *exprCall) string {
+	helper, ok := shellConditionFuncs[n.name]
+	if !ok {
+		return "{ echo " + shellSingleQuote("condition function '"+n.name+"' is not available in exported scripts") + " >&2; false; }"
+	}
+
+	call := helper
+	for _, arg := range n.args {
+		if isFileArg(arg) {
+			call += ` "${TOOL_FILE:-${TOOL_OUTPUT_FILE:-}}"`
+			continue
+		}
+		call += " " + shellWord(arg)
+	}
+	return call
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestExpressionToShell_MatchesEvalExpression(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	env := map[string]string{
+		"TOOL_NAME":     "Edit",
+		"EVENT_NAME":    "PostToolUse",
+		"FILES_CHANGED": "foo.go bar.txt",
+		"USER_PROMPT":   "it's $HOME",
+		"TOOL_FILE":     "vendor/lib/foo_gen.go",
+		"LINES":         "120",
+	}
+
+	exprs := []string{
+		"",
+		"${TOOL_NAME} == \"Edit\"",
+		"${TOOL_NAME} != \"Write\"",
+		"${EVENT_NAME} == PostToolUse",
+		"${FILES_CHANGED} matches *.go",
+		"${FILES_CHANGED} matches *.rb",
+		"${FILES_CHANGED} regex ^ba[rz]\\.txt$",
+		"${FILES_CHANGED} regex \\.py$",
+		"${TOOL_NAME} == \"Write\" || ${TOOL_NAME} == \"Edit\"",
+		"${TOOL_NAME} == Edit && ${FILES_CHANGED} matches *.rb",
+		"!${TOOL_NAME} == Write",
+		"!!${TOOL_NAME} == Write",
+		"${UNSET} == \"\"",
+		"${UNSET} matches *",
+		"${USER_PROMPT} == \"it's $HOME\"",
+		"false",
+		"0",
+		"${TOOL_NAME}",
+		"'true' && ${TOOL_NAME} != x",
+		"in_directory(file, \"vendor\")",
+		"in_directory(file, 'lib/foo')",
+		"!in_directory(${TOOL_FILE}, \"lib\") || ${TOOL_NAME} == Edit",
+		"is_generated(file)",
+		"is_generated(${FILES_CHANGED})",
+		"!(${TOOL_NAME} == Write || ${TOOL_NAME} == Read) && ${EVENT_NAME} == PostToolUse",
+		"not in_directory(file, \"vendor\") || ${TOOL_NAME} == Write",
+		"contains(${TOOL_FILE}, \"/lib/\")",
+		"startswith(file, 'vendor/') && endswith(file, \".go\")",
+		"matches(${FILES_CHANGED}, \"*.txt\")",
+		"contains(${TOOL_FILE}, x) == false",
+		"${LINES} > 99",
+		"${LINES} <= 99.5",
+		"${TOOL_NAME} regex ^(Edit|Write)$",
+		"${UNSET}",
+	}
+
+	helpers := ShellExpressionHelpers()
+	for _, expr := range exprs {
+		want, err := EvalExpression(expr, env)
+		if err != nil {
+			t.Fatalf("eval %q error: %v", expr, err)
+		}
+
+		script := "set -u\n" + helpers + "\nif " + ExpressionToShell(expr) + "; then exit 0; else exit 1; fi\n"
+		cmd := exec.Command(bash, "-c", script) // #nosec G204 - test input
+		cmd.Env = append(os.Environ(), "HOME=/should-not-expand")
+		for k, v := range env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+		out, runErr := cmd.CombinedOutput()
+		got := runErr == nil
+		if exitErr, ok := runErr.(*exec.ExitError); runErr != nil && (!ok || exitErr.ExitCode() != 1) {
+			t.Fatalf("shell for %q failed: %v\n%s\nscript condition: %s", expr, runErr, out, ExpressionToShell(expr))
+		}
+		if got != want {
+			t.Errorf("shell %q = %v, EvalExpression = %v (condition: %s)", expr, got, want, ExpressionToShell(expr))
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestEvalExpression_Basics(t *testing.T) {
 	env := map[string]string{
@@ -31,3 +34,98 @@ func TestEvalExpression_Basics(t *testing.T) {
 		}
 	}
 }
+
+func TestEvalExpression_Grammar(t *testing.T) {
+	env := map[string]string{
+		"TOOL_NAME":     "Edit",
+		"TOOL_FILE":     "internal/api/server.go",
+		"FILES_CHANGED": "internal/api/server.go README.md",
+		"LINES":         "120",
+		"SPACED":        "a && b",
+	}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		// Grouping and precedence
+		{`${TOOL_NAME} == Write || ${TOOL_NAME} == Edit && ${LINES} > 500`, false},
+		{`(${TOOL_NAME} == Write || ${TOOL_NAME} == Edit) && ${LINES} > 100`, true},
+		{`!(${TOOL_NAME} == Write || ${TOOL_NAME} == Read)`, true},
+		{`not ${TOOL_NAME} == Edit`, false},
+		{`not (${LINES} < 10) && not is_generated(file)`, true},
+		{`!!${TOOL_NAME} == Edit`, true},
+		// Functions
+		{`contains(${TOOL_FILE}, "/api/")`, true},
+		{`startswith(${TOOL_FILE}, 'internal/')`, true},
+		{`endswith(file, ".go")`, true},
+		{`matches(${FILES_CHANGED}, "*.md")`, true},
+		{`matches(file, "*.md")`, false},
+		{`contains(${TOOL_FILE}, "cmd") == false`, true},
+		// Numbers
+		{`${LINES} >= 120`, true},
+		{`${LINES} < 99.5`, false},
+		{`${LINES} <= 1e3`, true},
+		// Unquoted patterns and values holding operators
+		{`${TOOL_NAME} regex ^(Edit|Write)$`, true},
+		{`${TOOL_NAME} regex ^(Read|Write)$ || ${LINES} != 0`, true},
+		{`${SPACED} == "a && b"`, true},
+		{`${UNSET}`, false},
+	}
+
+	for _, tc := range cases {
+		got, err := EvalExpression(tc.expr, env)
+		if err != nil {
+			t.Fatalf("eval %q error: %v", tc.expr, err)
+		}
+		if got != tc.want {
+			t.Errorf("eval %q = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	if _, err := EvalExpression(`${TOOL_NAME} > 3`, env); err == nil || !strings.Contains(err.Error(), "compares numbers") {
+		t.Errorf("expected a number error, got %v", err)
+	}
+}
+
+func TestCheckExpression(t *testing.T) {
+	valid := []string{
+		"",
+		`${TOOL_NAME} == "Edit"`,
+		`${FILES_CHANGED} matches "*.go" && !is_generated(file)`,
+		`${TOOL_NAME} regex "^(Edit|Write)$" || ${CI} == 1`,
+		`${FILE} regex ${PATTERN}`,
+		"true",
+		`(${A} == 1 || ${B} == 2) && not contains(${C}, "x")`,
+		`${LINES} > 10 && ${LINES} <= ${MAX}`,
+		`matches(file, "*.go")`,
+	}
+	for _, expr := range valid {
+		if err := CheckExpression(expr); err != nil {
+			t.Errorf("CheckExpression(%q) = %v, want nil", expr, err)
+		}
+	}
+
+	invalid := map[string]string{
+		`${TOOL_NAME} == "Edit`:          "unterminated",
+		`${A} == 1 && `:                  "empty operand",
+		`== "Edit"`:                      "both sides",
+		`no_such_func(file)`:             "unknown condition function",
+		`${TOOL_NAME} regex "(unclosed"`: "invalid regex",
+		`${FILES_CHANGED} matches "[a-"`: "invalid glob",
+		`in_directory(file, "vendor"`:    "unbalanced parentheses",
+		`(${A} == 1 || ${B} == 2`:        "unbalanced parentheses",
+		`${A} == 1)`:                     "unbalanced parentheses",
+		`${A} ==`:                        "both sides",
+		`${A} regex`:                     "both sides",
+		`${LINES} > ten`:                 "compares numbers",
+		`matches(file, "[a-")`:           "invalid glob",
+		`${A} == 1 ${B}`:                 "unexpected",
+	}
+	for expr, want := range invalid {
+		err := CheckExpression(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckExpression(%q) = %v, want error containing %q", expr, err, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// cacheSubDir is the directory under .claude/ for data hooks keep between runs
+const cacheSubDir = "cache"
+
+var (
+	// findingPositionPattern matches ":line" or ":line:col" suffixes so a
+	// finding is still recognized after code above it moves
+	findingPositionPattern = regexp.MustCompile(`:\d+(:\d+)?`)
+	// findingLineWordPattern matches "line 12" style positions
+	findingLineWordPattern = regexp.MustCompile(`(?i)\bline \d+`)
+)
+
+// CacheDir returns the project's hook cache directory (.claude/cache).
+// BT_CACHE_DIR overrides the location (mainly for tests).
+func CacheDir() (string, error) {
+	if dir := os.Getenv("BT_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	claudeDir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(claudeDir, cacheSubDir), nil
+}
+
+// ensureCacheDir creates dir (the cache dir) and keeps it out of git status
+func ensureCacheDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o600)
+	}
+	return nil
+}
+
+// Finding is one line of lint or test output
+type Finding struct {
+	// Text is the line as the tool printed it
+	Text string `json:"text"`
+	// Key identifies the finding independent of its line/column position
+	Key string `json:"key"`
+}
+
+// ParseFindings splits tool output into findings, one per non-blank line
+func ParseFindings(output string) []Finding {
+	var findings []Finding
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key := findingPositionPattern.ReplaceAllString(line, ":#")
+		key = findingLineWordPattern.ReplaceAllString(key, "line #")
+		findings = append(findings, Finding{Text: line, Key: key})
+	}
+	return findings
+}
+
+// NewFindings compares findings for subject (typically hook key plus file)
+// against the previous run, records them as the new baseline, and returns
+// only those not seen last time. Repeated findings are counted, so a second
+// copy of an existing warning is reported as new. Without a baseline every
+// finding is new.
+func NewFindings(subject string, current []Finding) ([]Finding, error) {
+	path, err := findingsPath(subject)
+	if err != nil {
+		return current, err
+	}
+
+	previous := map[string]int{}
+	if data, err := os.ReadFile(path); err == nil { // #nosec G304 - path derived from a hash under the cache dir
+		var stored []Finding
+		if json.Unmarshal(data, &stored) == nil {
+			for _, f := range stored {
+				previous[f.Key]++
+			}
+		}
+	}
+
+	var fresh []Finding
+	for _, f := range current {
+		if previous[f.Key] > 0 {
+			previous[f.Key]--
+			continue
+		}
+		fresh = append(fresh, f)
+	}
+
+	if err := saveFindings(path, current); err != nil {
+		return fresh, err
+	}
+	return fresh, nil
+}
+
+func findingsPath(subject string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(subject))
+	return filepath.Join(dir, "findings", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func saveFindings(path string, findings []Finding) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create findings cache: %w", err)
+	}
+	// Cached data is machine-local; keep it out of the project's git status
+	ignore := filepath.Join(filepath.Dir(filepath.Dir(path)), ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o600)
+	}
+	if findings == nil {
+		findings = []Finding{}
+	}
+	data, err := json.Marshal(findings)
+	if err != nil {
+		return fmt.Errorf("failed to encode findings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write findings cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestParseFindings_KeysIgnorePositions(t *testing.T) {
+	a := ParseFindings("main.py:10:5: error: undefined name 'x'\n\n  \n")
+	b := ParseFindings("main.py:42:1: error: undefined name 'x'")
+	if len(a) != 1 || len(b) != 1 {
+		t.Fatalf("expected one finding each, got %v and %v", a, b)
+	}
+	if a[0].Key != b[0].Key {
+		t.Errorf("keys differ after a line shift: %q vs %q", a[0].Key, b[0].Key)
+	}
+	if a[0].Text != "main.py:10:5: error: undefined name 'x'" {
+		t.Errorf("Text should keep the original line, got %q", a[0].Text)
+	}
+	c := ParseFindings("Error on line 7: bad indent")
+	d := ParseFindings("Error on line 9: bad indent")
+	if c[0].Key != d[0].Key {
+		t.Errorf("'line N' positions should be ignored: %q vs %q", c[0].Key, d[0].Key)
+	}
+}
+
+func TestNewFindings(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	texts := func(fs []Finding) []string {
+		var out []string
+		for _, f := range fs {
+			out = append(out, f.Text)
+		}
+		return out
+	}
+
+	first := ParseFindings("a.go:1: unused x\na.go:2: unused y")
+	fresh, err := NewFindings("lint|a.go", first)
+	if err != nil || len(fresh) != 2 {
+		t.Fatalf("without a baseline all findings are new, got %v (%v)", texts(fresh), err)
+	}
+
+	// Same findings shifted down plus a duplicate and one new issue
+	second := ParseFindings("a.go:5: unused x\na.go:6: unused y\na.go:9: unused y\na.go:10: shadowed err")
+	fresh, err = NewFindings("lint|a.go", second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a.go:9: unused y", "a.go:10: shadowed err"}
+	if got := texts(fresh); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("fresh = %v, want %v", got, want)
+	}
+
+	// Baselines are per subject
+	if fresh, _ := NewFindings("lint|b.go", first); len(fresh) != 2 {
+		t.Errorf("other files should not share a baseline, got %v", texts(fresh))
+	}
+
+	// An empty run resets the baseline
+	if _, err := NewFindings("lint|a.go", nil); err != nil {
+		t.Fatal(err)
+	}
+	if fresh, _ := NewFindings("lint|a.go", first); len(fresh) != 2 {
+		t.Errorf("findings after a clean run should be new again, got %v", texts(fresh))
+	}
+}
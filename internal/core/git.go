@@ -0,0 +1,25 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GitOutput runs git in dir and returns its stdout unmodified. A failure
+// reports git's stderr when it wrote any.
+func GitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...) // #nosec G204 - fixed git subcommands
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// GitChangedFilesEnv is set for git-scoped jobs to the space-separated
+	// files changed since the session started
+	GitChangedFilesEnv = "GIT_CHANGED_FILES"
+	// GitBaseEnv is set for git-scoped jobs to the commit changes are measured from
+	GitBaseEnv = "GIT_BASE"
+
+	// gitBaseStateKey stores the session's starting commit in session state
+	gitBaseStateKey = "git_base"
+	// gitEmptyTree is git's well-known empty tree, the base for repositories
+	// without commits
+	gitEmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+)
+
+// SessionGitBase returns the commit HEAD pointed at when the session was
+// first seen in dir, recording the current HEAD on first use. Without a
+// session (or its state store) the current HEAD is returned.
+func SessionGitBase(dir, sessionID string) (string, error) {
+	var state *SessionState
+	if sessionID != "" {
+		if s, err := OpenSessionState(sessionID); err == nil {
+			state = s
+			if base, err := s.Get(gitBaseStateKey); err == nil && base != "" {
+				return base, nil
+			}
+		}
+	}
+	base, err := GitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		if _, repoErr := GitOutput(dir, "rev-parse", "--git-dir"); repoErr != nil {
+			return "", fmt.Errorf("not a git repository: %s", dir)
+		}
+		// A repository without commits: everything is new
+		base = gitEmptyTree
+	}
+	base = strings.TrimSpace(base)
+	if state != nil {
+		_ = state.Set(gitBaseStateKey, base)
+	}
+	return base, nil
+}
+
+// GitChangedFiles lists files under dir that differ from base: committed,
+// staged and unstaged changes plus untracked files that are not ignored.
+// Deleted files are left out. Paths are relative to dir and sorted.
+func GitChangedFiles(dir, base string) ([]string, error) {
+	if base == "" {
+		return nil, errors.New("git base is required")
+	}
+	diff, err := GitOutput(dir, "diff", "--name-only", "--relative", "--diff-filter=d", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := GitOutput(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// AddedLine is a line added since a git base
+type AddedLine struct {
+	File string
+	Line int
+	Text string
+}
+
+// GitAddedLines returns the lines added under dir since base, including
+// every line of untracked files that are not ignored. Paths are relative
+// to dir.
+func GitAddedLines(dir, base string) ([]AddedLine, error) {
+	if base == "" {
+		return nil, errors.New("git base is required")
+	}
+	diff, err := GitOutput(dir, "diff", "-U0", "--no-color", "--no-ext-diff", "--relative", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	var lines []AddedLine
+	file, next := "", 0
+	for _, l := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(l, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(l, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(l, "@@ "):
+			// @@ -a,b +c,d @@: added lines start at c
+			if fields := strings.Fields(l); len(fields) > 2 {
+				start := strings.SplitN(strings.TrimPrefix(fields[2], "+"), ",", 2)[0]
+				next, _ = strconv.Atoi(start)
+			}
+		case strings.HasPrefix(l, "+") && file != "":
+			lines = append(lines, AddedLine{File: file, Line: next, Text: l[1:]})
+			next++
+		}
+	}
+
+	untracked, err := GitOutput(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(untracked, "\n") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, name)) // #nosec G304 - file listed by git
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for n := 1; scanner.Scan(); n++ {
+			lines = append(lines, AddedLine{File: name, Line: n, Text: scanner.Text()})
+		}
+		_ = f.Close()
+	}
+	return lines, nil
+}
+
+// DiffStat is the size of one file's change, as git diff --numstat reports it
+type DiffStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	// Binary changes have no line counts
+	Binary bool
+}
+
+// Lines is the number of lines added and deleted
+func (d DiffStat) Lines() int {
+	return d.Added + d.Deleted
+}
+
+// GitDiffStats runs "git diff --numstat" in dir with args, such as
+// "--cached" or a commit followed by "--" and pathspecs. Renames count as a
+// deletion plus an addition. Paths are relative to dir.
+func GitDiffStats(dir string, args ...string) ([]DiffStat, error) {
+	out, err := GitOutput(dir, append([]string{"diff", "--numstat", "--no-renames", "--relative", "-z"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var stats []DiffStat
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stat := DiffStat{Path: strings.TrimSpace(fields[2])}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(strings.TrimSpace(fields[0]))
+			stat.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// GitUntrackedFiles lists files under dir that git doesn't track and
+// doesn't ignore, limited to pathspecs when given
+func GitUntrackedFiles(dir string, pathspecs ...string) ([]string, error) {
+	out, err := GitOutput(dir, append([]string{"ls-files", "--others", "--exclude-standard", "-z", "--"}, pathspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// GitCurrentBranch returns the branch checked out in dir, including one
+// without commits yet, or "HEAD" when it is detached
+func GitCurrentBranch(dir string) (string, error) {
+	if out, err := GitOutput(dir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	out, err := GitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// FilterFilesByGlob keeps files whose path or base name matches any of globs.
+// No globs keeps every file.
+func FilterFilesByGlob(files, globs []string) []string {
+	if len(globs) == 0 {
+		return files
+	}
+	var out []string
+	for _, f := range files {
+		for _, g := range globs {
+			if ok, _ := path.Match(g, f); ok {
+				out = append(out, f)
+				break
+			}
+			if ok, _ := path.Match(g, path.Base(f)); ok {
+				out = append(out, f)
+				break
+			}
+		}
+	}
+	return out
+}
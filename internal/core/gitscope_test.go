@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGitChangedFiles_SinceSessionBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("a.go")
+	write("old.go")
+	write(".gitignore")
+	git("add", "-A")
+	git("commit", "-q", "-m", "start")
+
+	base, err := SessionGitBase(dir, "s-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A commit during the session still counts as changed since the base
+	write("pkg/b.go")
+	git("add", "-A")
+	git("commit", "-q", "-m", "mid-session")
+	if again, _ := SessionGitBase(dir, "s-1"); again != base {
+		t.Fatalf("session base moved from %s to %s", base, again)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("changed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	write("new_test.go")
+	write("README.md")
+	git("rm", "-q", "old.go")
+
+	files, err := GitChangedFiles(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"README.md", "a.go", "new_test.go", "pkg/b.go"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("GitChangedFiles = %v, want %v", files, want)
+	}
+
+	goFiles := FilterFilesByGlob(files, []string{"*.go"})
+	if strings.Join(goFiles, " ") != "a.go new_test.go pkg/b.go" {
+		t.Errorf("glob by base name = %v", goFiles)
+	}
+	if pkg := FilterFilesByGlob(files, []string{"pkg/*"}); len(pkg) != 1 || pkg[0] != "pkg/b.go" {
+		t.Errorf("glob by path = %v", pkg)
+	}
+}
+
+func TestSessionGitBase_NotARepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	if _, err := SessionGitBase(t.TempDir(), ""); err == nil {
+		t.Error("expected an error outside a git repository")
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// HousekeepingInterval is how often housekeeping runs at most per project
+	HousekeepingInterval = 24 * time.Hour
+
+	// housekeepingFile records the last run in the project's state directory
+	housekeepingFile = "housekeeping.json"
+	// housekeepingLockWait is short: when another process holds the lock it
+	// is already doing the work
+	housekeepingLockWait = 10 * time.Millisecond
+)
+
+// HousekeepingRecord describes the last housekeeping run
+type HousekeepingRecord struct {
+	LastRun time.Time `json:"last_run"`
+	// Removed and FreedBytes count the log files the run deleted
+	Removed    int    `json:"removed"`
+	FreedBytes int64  `json:"freed_bytes"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HousekeepingTask does the cleanup and reports what it removed
+type HousekeepingTask func() (removed int, freedBytes int64, err error)
+
+// LoadHousekeepingRecord returns the last run, or nil if none was recorded
+func LoadHousekeepingRecord() (*HousekeepingRecord, error) {
+	root, err := StateRoot()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(root, housekeepingFile)) // #nosec G304 - fixed file under the state dir
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read housekeeping record: %w", err)
+	}
+	var rec HousekeepingRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse housekeeping record: %w", err)
+	}
+	return &rec, nil
+}
+
+// HousekeepingDue reports whether the last run is older than
+// HousekeepingInterval. It only reads one small file, so it is cheap enough
+// to call on every hook run.
+func HousekeepingDue(now time.Time) bool {
+	rec, err := LoadHousekeepingRecord()
+	if err != nil || rec == nil {
+		return true
+	}
+	return now.Sub(rec.LastRun) >= HousekeepingInterval
+}
+
+// RunHousekeeping runs task and records the outcome, unless the last run is
+// recent (ignored with force) or another process is running it right now.
+// ran reports whether task was called.
+func RunHousekeeping(force bool, task HousekeepingTask) (rec *HousekeepingRecord, ran bool, err error) {
+	root, err := StateRoot()
+	if err != nil {
+		return nil, false, err
+	}
+	release, err := AcquireNamedLock(housekeepingLockName(root), housekeepingLockWait)
+	if errors.Is(err, ErrLockTimeout) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	now := time.Now()
+	if !force && !HousekeepingDue(now) {
+		rec, err := LoadHousekeepingRecord()
+		return rec, false, err
+	}
+
+	removed, freed, taskErr := task()
+	rec = &HousekeepingRecord{LastRun: now.UTC(), Removed: removed, FreedBytes: freed}
+	if taskErr != nil {
+		rec.Error = taskErr.Error()
+	}
+	if err := ensureCacheDir(root); err != nil {
+		return rec, true, err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return rec, true, fmt.Errorf("failed to encode housekeeping record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, housekeepingFile), data, 0o600); err != nil {
+		return rec, true, fmt.Errorf("failed to write housekeeping record: %w", err)
+	}
+	return rec, true, taskErr
+}
+
+// housekeepingLockName scopes the machine-wide lock to one project
+func housekeepingLockName(stateRoot string) string {
+	sum := sha256.Sum256([]byte(stateRoot))
+	return "housekeeping-" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunHousekeeping_OncePerInterval(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	calls := 0
+	task := func() (int, int64, error) {
+		calls++
+		return 2, 2048, nil
+	}
+
+	if !HousekeepingDue(time.Now()) {
+		t.Fatal("expected housekeeping to be due before the first run")
+	}
+	rec, ran, err := RunHousekeeping(false, task)
+	if err != nil || !ran {
+		t.Fatalf("first run: ran=%v err=%v", ran, err)
+	}
+	if rec.Removed != 2 || rec.FreedBytes != 2048 {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	if HousekeepingDue(time.Now()) {
+		t.Fatal("expected housekeeping not to be due right after a run")
+	}
+	if !HousekeepingDue(time.Now().Add(HousekeepingInterval)) {
+		t.Fatal("expected housekeeping to be due after the interval")
+	}
+
+	if _, ran, err := RunHousekeeping(false, task); err != nil || ran {
+		t.Fatalf("second run should be skipped: ran=%v err=%v", ran, err)
+	}
+	if _, ran, err := RunHousekeeping(true, task); err != nil || !ran {
+		t.Fatalf("forced run: ran=%v err=%v", ran, err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected task to run twice, ran %d times", calls)
+	}
+}
+
+func TestRunHousekeeping_RecordsTaskError(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	_, ran, err := RunHousekeeping(false, func() (int, int64, error) {
+		return 1, 10, errors.New("permission denied")
+	})
+	if !ran || err == nil {
+		t.Fatalf("expected the task error to be returned: ran=%v err=%v", ran, err)
+	}
+	rec, err := LoadHousekeepingRecord()
+	if err != nil || rec == nil {
+		t.Fatalf("expected a record: %v", err)
+	}
+	if rec.Error != "permission denied" || rec.Removed != 1 {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	// A failed run still counts, so a broken log dir is not retried on every hook
+	if HousekeepingDue(time.Now()) {
+		t.Fatal("expected housekeeping not to be due after a failed run")
+	}
+}
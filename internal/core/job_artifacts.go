@@ -0,0 +1,306 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+const (
+	// jobArtifactsSubDir is the directory under .claude/hooks holding the
+	// output captured from capture_output jobs
+	jobArtifactsSubDir = "artifacts"
+	// jobArtifactManifest describes one captured run inside its directory
+	jobArtifactManifest = "meta.json"
+	// JobArtifactStdout and JobArtifactStderr hold a run's output
+	JobArtifactStdout   = "stdout.log"
+	JobArtifactStderr   = "stderr.log"
+	jobArtifactIDLayout = "20060102-150405.000"
+)
+
+// JobArtifact is the captured output of one run of a custom job
+type JobArtifact struct {
+	Group string    `json:"group"`
+	Job   string    `json:"job"`
+	ID    string    `json:"id"`
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	Tool  string    `json:"tool,omitempty"`
+	File  string    `json:"file,omitempty"`
+	// Command is the job's run line as configured
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	// Error says why the run failed when it didn't get an exit code, e.g.
+	// a timeout
+	Error string `json:"error,omitempty"`
+	// StdoutBytes and StderrBytes are the output sizes before Truncated
+	// cut them to the rotation max size
+	StdoutBytes int64 `json:"stdout_bytes"`
+	StderrBytes int64 `json:"stderr_bytes"`
+	Truncated   bool  `json:"truncated,omitempty"`
+}
+
+// Ref names the artifact as 'hooks artifacts show' accepts it
+func (a JobArtifact) Ref() string {
+	return a.Group + "/" + a.Job + "/" + a.ID
+}
+
+// JobArtifactsDir returns the project's job artifact directory,
+// .claude/hooks/artifacts
+func JobArtifactsDir() (string, error) {
+	dir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, constants.HooksSubDir, jobArtifactsSubDir), nil
+}
+
+// JobArtifactDir returns the directory holding the files of a
+func JobArtifactDir(a JobArtifact) (string, error) {
+	root, err := JobArtifactsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, a.Group, a.Job, a.ID), nil
+}
+
+// validArtifactName reports whether name can be one path element
+func validArtifactName(name string) bool {
+	return name != "" && filepath.Base(name) == name && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`)
+}
+
+// SaveJobArtifact writes a run's output to a new artifact directory, then
+// prunes the job's older runs by rotation: runs older than MaxAge days go,
+// and at most MaxBackups runs are kept. Each stream is cut to its last
+// MaxSize megabytes. A zero limit is not enforced.
+func SaveJobArtifact(a JobArtifact, stdout, stderr string, rotation config.LogRotationConfig) (*JobArtifact, error) {
+	if !validArtifactName(a.Group) || !validArtifactName(a.Job) {
+		return nil, fmt.Errorf("cannot store output of job '%s' in group '%s' as an artifact", a.Job, a.Group)
+	}
+	root, err := JobArtifactsDir()
+	if err != nil {
+		return nil, err
+	}
+	jobDir := filepath.Join(root, a.Group, a.Job)
+	if err := os.MkdirAll(jobDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	a.Time = a.Time.UTC()
+	dir, err := newJobArtifactDir(jobDir, &a)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(max(rotation.MaxSize, 0)) << 20
+	a.StdoutBytes, a.StderrBytes = int64(len(stdout)), int64(len(stderr))
+	for name, out := range map[string]string{JobArtifactStdout: stdout, JobArtifactStderr: stderr} {
+		if limit > 0 && int64(len(out)) > limit {
+			out = fmt.Sprintf("[truncated %d bytes]\n", int64(len(out))-limit) + out[int64(len(out))-limit:]
+			a.Truncated = true
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(out), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write artifact: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode artifact: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, jobArtifactManifest), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	if _, _, err := PruneJobArtifacts(a.Group, a.Job, rotation, time.Now()); err != nil {
+		return &a, err
+	}
+	return &a, nil
+}
+
+// newJobArtifactDir creates a uniquely named run directory and sets a.ID
+func newJobArtifactDir(jobDir string, a *JobArtifact) (string, error) {
+	base := a.Time.Format(jobArtifactIDLayout)
+	for n := 0; n < 1000; n++ {
+		id := base
+		if n > 0 {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		dir := filepath.Join(jobDir, id)
+		if err := os.Mkdir(dir, 0o750); err == nil {
+			a.ID = id
+			return dir, nil
+		} else if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create artifact directory: %w", err)
+		}
+	}
+	return "", fmt.Errorf("failed to create artifact directory: too many runs at %s", base)
+}
+
+// ListJobArtifacts returns the captured runs, newest first. A non-empty
+// group or job limits the list to it.
+func ListJobArtifacts(group, job string) ([]JobArtifact, error) {
+	root, err := JobArtifactsDir()
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []JobArtifact
+	for _, g := range subDirs(root) {
+		if group != "" && g != group {
+			continue
+		}
+		for _, j := range subDirs(filepath.Join(root, g)) {
+			if job != "" && j != job {
+				continue
+			}
+			for _, id := range subDirs(filepath.Join(root, g, j)) {
+				a, err := readJobArtifact(filepath.Join(root, g, j, id))
+				if err != nil {
+					continue
+				}
+				// The path, not the manifest, says where the files are
+				a.Group, a.Job, a.ID = g, j, id
+				artifacts = append(artifacts, *a)
+			}
+		}
+	}
+	sort.SliceStable(artifacts, func(i, k int) bool {
+		if !artifacts[i].Time.Equal(artifacts[k].Time) {
+			return artifacts[i].Time.After(artifacts[k].Time)
+		}
+		return artifacts[i].ID > artifacts[k].ID
+	})
+	return artifacts, nil
+}
+
+// FindJobArtifact resolves ref, either group/job/id or group/job for the
+// job's latest run
+func FindJobArtifact(ref string) (*JobArtifact, error) {
+	parts := strings.Split(strings.Trim(filepath.ToSlash(ref), "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid artifact '%s'\n  Suggestion: Use <group>/<job> for the latest run or <group>/<job>/<id> from 'blues-traveler hooks artifacts list'", ref)
+	}
+	artifacts, err := ListJobArtifacts(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range artifacts {
+		if len(parts) == 2 || a.ID == parts[2] {
+			return &a, nil
+		}
+	}
+	return nil, fmt.Errorf("no captured output for '%s'\n  Suggestion: Run 'blues-traveler hooks artifacts list' to see captured runs, and set capture_output: true on the job", ref)
+}
+
+// PruneJobArtifacts applies rotation to the captured runs of group and job
+// (every group or job when empty): runs older than MaxAge days are removed,
+// then all but the newest MaxBackups of each job. It returns how many runs
+// were removed and the bytes freed.
+func PruneJobArtifacts(group, job string, rotation config.LogRotationConfig, now time.Time) (int, int64, error) {
+	artifacts, err := ListJobArtifacts(group, job)
+	if err != nil {
+		return 0, 0, err
+	}
+	var cutoff time.Time
+	if rotation.MaxAge > 0 {
+		cutoff = now.AddDate(0, 0, -rotation.MaxAge)
+	}
+	kept := map[string]int{}
+	var expired []JobArtifact
+	for _, a := range artifacts {
+		key := a.Group + "/" + a.Job
+		if (!cutoff.IsZero() && a.Time.Before(cutoff)) || (rotation.MaxBackups > 0 && kept[key] >= rotation.MaxBackups) {
+			expired = append(expired, a)
+			continue
+		}
+		kept[key]++
+	}
+	return removeJobArtifacts(expired)
+}
+
+// RemoveJobArtifacts deletes the captured runs of group and job (every group
+// or job when empty) older than before, or all of them when before is zero
+func RemoveJobArtifacts(group, job string, before time.Time) (int, int64, error) {
+	artifacts, err := ListJobArtifacts(group, job)
+	if err != nil {
+		return 0, 0, err
+	}
+	var matched []JobArtifact
+	for _, a := range artifacts {
+		if before.IsZero() || a.Time.Before(before) {
+			matched = append(matched, a)
+		}
+	}
+	return removeJobArtifacts(matched)
+}
+
+// removeJobArtifacts deletes the run directories of artifacts, and job and
+// group directories left empty
+func removeJobArtifacts(artifacts []JobArtifact) (int, int64, error) {
+	removed := 0
+	var freed int64
+	for _, a := range artifacts {
+		dir, err := JobArtifactDir(a)
+		if err != nil {
+			return removed, freed, err
+		}
+		size := dirSize(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, freed, fmt.Errorf("failed to remove artifact %s: %w", a.Ref(), err)
+		}
+		removed++
+		freed += size
+		// Remove fails on directories that still hold runs, which is fine
+		_ = os.Remove(filepath.Dir(dir))
+		_ = os.Remove(filepath.Dir(filepath.Dir(dir)))
+	}
+	return removed, freed, nil
+}
+
+func readJobArtifact(dir string) (*JobArtifact, error) {
+	data, err := os.ReadFile(filepath.Join(dir, jobArtifactManifest)) // #nosec G304 - run dir under the artifacts dir
+	if err != nil {
+		return nil, err
+	}
+	var a JobArtifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact %s: %w", filepath.Base(dir), err)
+	}
+	return &a, nil
+}
+
+// subDirs lists the names of the directories in dir, skipping hidden ones
+func subDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// dirSize adds up the sizes of the files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestSaveJobArtifact_TruncatesAndPrunes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	rotation := config.LogRotationConfig{MaxAge: 7, MaxSize: 1, MaxBackups: 2}
+	now := time.Now()
+
+	old, err := SaveJobArtifact(JobArtifact{Group: "go", Job: "test", Time: now.AddDate(0, 0, -10)}, "old", "", config.LogRotationConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("x", 1<<20) + "tail"
+	a, err := SaveJobArtifact(JobArtifact{Group: "go", Job: "test", Time: now.Add(-time.Minute)}, big, "err", rotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Truncated || a.StdoutBytes != int64(len(big)) {
+		t.Errorf("expected a truncated run with the original size, got %+v", a)
+	}
+	dir, _ := JobArtifactDir(*a)
+	data, err := os.ReadFile(filepath.Join(dir, JobArtifactStdout))
+	if err != nil || !strings.HasPrefix(string(data), "[truncated 4 bytes]") || !strings.HasSuffix(string(data), "tail") {
+		t.Errorf("expected the tail of stdout to be kept, got %d bytes (%v)", len(data), err)
+	}
+
+	// The expired run went when the second was saved
+	if _, err := FindJobArtifact(old.Ref()); err == nil {
+		t.Error("expected the run older than maxAge to be pruned")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := SaveJobArtifact(JobArtifact{Group: "go", Job: "test", Time: now.Add(time.Duration(i) * time.Second)}, "new", "", rotation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	artifacts, err := ListJobArtifacts("", "")
+	if err != nil || len(artifacts) != 2 {
+		t.Fatalf("expected maxBackups runs to be kept, got %d (%v)", len(artifacts), err)
+	}
+	latest, err := FindJobArtifact("go/test")
+	if err != nil || latest.ID != artifacts[0].ID {
+		t.Fatalf("expected go/test to find the latest run, got %+v (%v)", latest, err)
+	}
+
+	if _, err := SaveJobArtifact(JobArtifact{Group: "../x", Job: "test"}, "", "", rotation); err == nil {
+		t.Error("expected a group that isn't a path element to be refused")
+	}
+	removed, _, err := RemoveJobArtifacts("go", "", time.Time{})
+	if err != nil || removed != 2 {
+		t.Fatalf("expected both runs removed, got %d (%v)", removed, err)
+	}
+	if root, _ := JobArtifactsDir(); len(subDirs(root)) != 0 {
+		t.Error("expected empty group directories to be removed")
+	}
+}
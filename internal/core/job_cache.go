@@ -0,0 +1,108 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// jobCacheSubDir is the directory under the XDG cache dir for job outcomes
+const jobCacheSubDir = "jobs"
+
+// JobOutcome is a finished job run, as replayed from the cache
+type JobOutcome struct {
+	ExitCode int       `json:"exit_code"`
+	Stdout   string    `json:"stdout,omitempty"`
+	Stderr   string    `json:"stderr,omitempty"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// JobCacheDigest hashes the inputs a cached job outcome depends on: the
+// job's key and command, the project, and each variable in keys. A word in
+// a variable's value that names a file (relative to PROJECT_ROOT) adds the
+// file's contents, so an edit changes the digest even when the file list
+// doesn't; a file that can't be read adds only its name.
+func JobCacheDigest(jobKey, run string, keys []string, env map[string]string) string {
+	h := sha256.New()
+	root := env["PROJECT_ROOT"]
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00", jobKey, run, root)
+	for _, key := range keys {
+		value := env[key]
+		_, _ = fmt.Fprintf(h, "%s=%s\x00", key, value)
+		for _, word := range strings.Fields(value) {
+			hashFileInto(h, root, word)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFileInto adds the contents of the file at name, if it is one
+func hashFileInto(w io.Writer, root, name string) {
+	path := name
+	if !filepath.IsAbs(path) && root != "" {
+		path = filepath.Join(root, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	f, err := os.Open(path) // #nosec G304 - a file the job is keyed on
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = fmt.Fprintf(w, "%s\x00", name)
+	_, _ = io.Copy(w, f)
+}
+
+// jobCachePath returns where the outcome for digest is stored
+func jobCachePath(digest string) string {
+	return filepath.Join(config.XDGCacheDir(), jobCacheSubDir, digest+".json")
+}
+
+// LoadJobOutcome returns the outcome cached for digest if it was saved
+// within ttl. Expired entries are removed.
+func LoadJobOutcome(digest string, ttl time.Duration) (*JobOutcome, bool) {
+	path := jobCachePath(digest)
+	data, err := os.ReadFile(path) // #nosec G304 - path derived from a hash under the cache dir
+	if err != nil {
+		return nil, false
+	}
+	var outcome JobOutcome
+	if json.Unmarshal(data, &outcome) != nil {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	if time.Since(outcome.SavedAt) > ttl {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return &outcome, true
+}
+
+// SaveJobOutcome caches outcome under digest
+func SaveJobOutcome(digest string, outcome JobOutcome) error {
+	path := jobCachePath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create job cache: %w", err)
+	}
+	if outcome.SavedAt.IsZero() {
+		outcome.SavedAt = time.Now()
+	}
+	data, err := json.Marshal(outcome)
+	if err != nil {
+		return fmt.Errorf("failed to encode job outcome: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write job cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJobCacheDigest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("one"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"PROJECT_ROOT": dir, "FILES_CHANGED": "a.go missing.go", "TOOL_NAME": "Edit"}
+	keys := []string{"FILES_CHANGED"}
+	base := JobCacheDigest("config:g:j", "make test", keys, env)
+
+	if got := JobCacheDigest("config:g:j", "make test", keys, map[string]string{"PROJECT_ROOT": dir, "FILES_CHANGED": "a.go missing.go", "TOOL_NAME": "Write"}); got != base {
+		t.Error("a variable outside the key changed the digest")
+	}
+	if got := JobCacheDigest("config:g:j", "make lint", keys, env); got == base {
+		t.Error("a different command kept the digest")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("two"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := JobCacheDigest("config:g:j", "make test", keys, env); got == base {
+		t.Error("editing a keyed file kept the digest")
+	}
+}
+
+func TestJobOutcomeTTL(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	if err := SaveJobOutcome("fresh", JobOutcome{ExitCode: 2, Stdout: "out"}); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := LoadJobOutcome("fresh", time.Minute)
+	if !ok || got.ExitCode != 2 || got.Stdout != "out" {
+		t.Fatalf("LoadJobOutcome = %+v, %v", got, ok)
+	}
+
+	if err := SaveJobOutcome("old", JobOutcome{SavedAt: time.Now().Add(-2 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LoadJobOutcome("old", time.Minute); ok {
+		t.Error("expected an outcome older than the ttl to be ignored")
+	}
+	if _, err := os.Stat(jobCachePath("old")); !os.IsNotExist(err) {
+		t.Error("expected the expired entry to be removed")
+	}
+}
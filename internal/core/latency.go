@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brads3290/cchooks"
+)
+
+// HookOverheadBudget is the most 'hooks run' should add to a tool call, not
+// counting the commands hooks run themselves. The hot path benchmarks and
+// 'hooks run --bench-self' measure against it.
+const HookOverheadBudget = 50 * time.Millisecond
+
+// FormatHookLatency renders the note appended to slow hook responses
+func FormatHookLatency(key string, d time.Duration) string {
+	return fmt.Sprintf("(%s hook took %.1fs)", key, d.Seconds())
+}
+
+// Runner builds the hook's runner through the context's RunnerFactory. Raw
+// payloads are checked against the fields built-in hooks expect. When the
+// context has a latency threshold, responses slower than it carry a note.
+// Hooks under an experiment get the session's variant message first. With
+// metrics enabled, the run is recorded when it exits; 'hooks stats' and
+// 'hooks latency' read those records.
+// With terse output, the latency note is dropped and every message the hook
+// returns is cleaned for the agent.
+func (h *BaseHook) Runner(
+	preHandler func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	postHandler func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	rawHandler func(context.Context, string) *cchooks.RawResponse,
+) Runner {
+	preHandler, postHandler = h.withExperiments(preHandler, postHandler)
+	preHandler, postHandler, rawHandler = h.withLatency(preHandler, postHandler, rawHandler)
+	preHandler, postHandler, rawHandler = h.withTerse(preHandler, postHandler, rawHandler)
+	if !h.Context().MetricsEnabled {
+		return h.Context().RunnerFactory(preHandler, postHandler, withSchemaCheck(rawHandler))
+	}
+	run := newHookRun(h.Key())
+	preHandler, postHandler, rawHandler = run.observe(preHandler, postHandler, rawHandler)
+	return run.attach(h.Context().RunnerFactory(preHandler, postHandler, withSchemaCheck(rawHandler)))
+}
+
+// withLatency notes slow responses when the context has a latency threshold
+func (h *BaseHook) withLatency(
+	preHandler func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	postHandler func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	rawHandler func(context.Context, string) *cchooks.RawResponse,
+) (
+	func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	func(context.Context, string) *cchooks.RawResponse,
+) {
+	threshold := h.Context().LatencyThreshold
+	if threshold <= 0 {
+		return preHandler, postHandler, rawHandler
+	}
+
+	var pre func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface
+	if preHandler != nil {
+		pre = func(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			start := time.Now()
+			resp := preHandler(ctx, event)
+			elapsed := time.Since(start)
+			if elapsed < threshold || h.Context().TerseOutput {
+				return resp
+			}
+			return annotatePreLatency(resp, FormatHookLatency(h.Key(), elapsed))
+		}
+	}
+
+	var post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface
+	if postHandler != nil {
+		post = func(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			start := time.Now()
+			resp := postHandler(ctx, event)
+			elapsed := time.Since(start)
+			if elapsed < threshold || h.Context().TerseOutput {
+				return resp
+			}
+			return annotatePostLatency(resp, FormatHookLatency(h.Key(), elapsed))
+		}
+	}
+
+	return pre, post, rawHandler
+}
+
+// annotatePreLatency appends note to the user-facing message of resp
+func annotatePreLatency(resp cchooks.PreToolUseResponseInterface, note string) cchooks.PreToolUseResponseInterface {
+	switch r := resp.(type) {
+	case *AskPreToolResponse:
+		r.userMessage = appendNote(r.userMessage, note)
+		r.Reason = appendNote(r.Reason, note)
+	case *DualMessagePreToolResponse:
+		r.userMessage = appendNote(r.userMessage, note)
+		r.Reason = appendNote(r.Reason, note)
+	case *cchooks.PreToolUseResponse:
+		r.Reason = appendNote(r.Reason, note)
+	case nil:
+		return ApproveWithMessages(note)
+	}
+	return resp
+}
+
+// annotatePostLatency appends note to the user-facing message of resp
+func annotatePostLatency(resp cchooks.PostToolUseResponseInterface, note string) cchooks.PostToolUseResponseInterface {
+	switch r := resp.(type) {
+	case *DualMessagePostToolResponse:
+		r.userMessage = appendNote(r.userMessage, note)
+		r.Reason = appendNote(r.Reason, note)
+	case *cchooks.PostToolUseResponse:
+		r.Reason = appendNote(r.Reason, note)
+	case nil:
+		return AllowWithMessages(note)
+	}
+	return resp
+}
+
+func appendNote(msg, note string) string {
+	if strings.TrimSpace(msg) == "" {
+		return note
+	}
+	return msg + " " + note
+}
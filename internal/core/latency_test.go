@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brads3290/cchooks"
+)
+
+func TestRunner_NoThresholdPassesHandlersThrough(t *testing.T) {
+	hook := NewBaseHook("fmt", "Format", "", TestHookContext(nil))
+
+	runner := hook.Runner(nil, func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+		time.Sleep(5 * time.Millisecond)
+		return cchooks.Allow()
+	}, nil).(*MockRunner)
+
+	got := SummarizeResponse(runner.PostToolUse(context.Background(), &cchooks.PostToolUseEvent{}))
+	if got.UserMessage != "" {
+		t.Errorf("timing disabled, got message %q", got.UserMessage)
+	}
+}
+
+func TestRunner_AnnotatesSlowResponses(t *testing.T) {
+	ctx := TestHookContext(nil)
+	ctx.LatencyThreshold = 20 * time.Millisecond
+	hook := NewBaseHook("fmt", "Format", "", ctx)
+
+	slow := true
+	runner := hook.Runner(
+		func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			if slow {
+				time.Sleep(30 * time.Millisecond)
+			}
+			return BlockWithMessages("blocked")
+		},
+		func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			time.Sleep(30 * time.Millisecond)
+			return nil
+		},
+		nil,
+	).(*MockRunner)
+
+	pre := SummarizeResponse(runner.PreToolUse(context.Background(), &cchooks.PreToolUseEvent{}))
+	if pre.Decision != cchooks.PreToolUseBlock || !strings.HasPrefix(pre.UserMessage, "blocked (fmt hook took ") {
+		t.Errorf("slow block = %+v, want decision kept and timing appended", pre)
+	}
+	if pre.AgentMessage != "blocked" {
+		t.Errorf("agent message should be untouched, got %q", pre.AgentMessage)
+	}
+
+	post := SummarizeResponse(runner.PostToolUse(context.Background(), &cchooks.PostToolUseEvent{}))
+	if post.Decision != "" || !strings.Contains(post.UserMessage, "fmt hook took") {
+		t.Errorf("slow allow without response = %+v, want allow with timing note", post)
+	}
+
+	slow = false
+	fast := SummarizeResponse(runner.PreToolUse(context.Background(), &cchooks.PreToolUseEvent{}))
+	if fast.UserMessage != "blocked" {
+		t.Errorf("fast response should not be annotated, got %q", fast.UserMessage)
+	}
+}
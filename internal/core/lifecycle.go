@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lifecycleSubDir holds one directory per occurrence of an event with
+	// before_all or after_all commands, under the session's state directory
+	lifecycleSubDir = ".lifecycle"
+
+	beforeClaimFile  = "before_all.claim"
+	beforeResultFile = "before_all.json"
+	afterClaimFile   = "after_all.claim"
+	afterResultFile  = "after_all.json"
+)
+
+// LifecycleResult is the outcome of a before_all or after_all command
+type LifecycleResult struct {
+	OK     bool   `json:"ok"`
+	Output string `json:"output,omitempty"`
+}
+
+// EventRun runs an event's before_all and after_all commands once for all
+// of its jobs. Each job is its own process, so the jobs meet in a directory
+// named for the event occurrence (see ChainID): the first to arrive claims
+// before_all, and the last to finish claims after_all.
+type EventRun struct {
+	dir string
+}
+
+// OpenEventRun returns the run id in sessionID's state, creating it. Runs
+// of events finished more than an hour ago are removed.
+func OpenEventRun(sessionID, id string) (*EventRun, error) {
+	state, err := OpenSessionState(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(state.Dir(), lifecycleSubDir)
+	pruneRunDirs(root, time.Now())
+	dir := filepath.Join(root, id)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create event run directory: %w", err)
+	}
+	return &EventRun{dir: dir}, nil
+}
+
+// claim reports whether this process is the first to ask for name
+func (r *EventRun) claim(name string) bool {
+	f, err := os.OpenFile(filepath.Join(r.dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 - path inside the run directory
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+}
+
+func (r *EventRun) record(name string, result LifecycleResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return writeRunFile(r.dir, name, data)
+}
+
+func (r *EventRun) result(name string) (LifecycleResult, bool) {
+	var result LifecycleResult
+	data, err := os.ReadFile(filepath.Join(r.dir, name)) // #nosec G304 - path inside the run directory
+	if err != nil || json.Unmarshal(data, &result) != nil {
+		return result, false
+	}
+	return result, true
+}
+
+// Before runs setup if no other job of the event has, and otherwise waits
+// up to wait for the job that did. ran is true in the job that ran setup;
+// err is set when a waiting job gave up before the result arrived.
+func (r *EventRun) Before(ctx context.Context, wait time.Duration, setup func() LifecycleResult) (result LifecycleResult, ran bool, err error) {
+	if r.claim(beforeClaimFile) {
+		result = setup()
+		// If this fails, waiting jobs time out rather than run without setup
+		_ = r.record(beforeResultFile, result)
+		return result, true, nil
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		if result, ok := r.result(beforeResultFile); ok {
+			return result, false, nil
+		}
+		if !time.Now().Before(deadline) {
+			return LifecycleResult{}, false, fmt.Errorf("before_all did not finish within %s", wait)
+		}
+		select {
+		case <-ctx.Done():
+			return LifecycleResult{}, false, fmt.Errorf("cancelled waiting for before_all: %w", ctx.Err())
+		case <-time.After(chainPollInterval):
+		}
+	}
+}
+
+// Leave marks job done. When every job in jobs is done it runs teardown,
+// in exactly one of them; ran is true there.
+func (r *EventRun) Leave(job string, jobs []string, teardown func() LifecycleResult) (result LifecycleResult, ran bool) {
+	if err := writeRunFile(r.dir, chainFileName(job, ".done"), nil); err != nil {
+		return LifecycleResult{Output: fmt.Sprintf("failed to record job completion: %v", err)}, false
+	}
+	for _, other := range jobs {
+		if _, err := os.Stat(filepath.Join(r.dir, chainFileName(other, ".done"))); err != nil {
+			return LifecycleResult{OK: true}, false
+		}
+	}
+	if !r.claim(afterClaimFile) {
+		return LifecycleResult{OK: true}, false
+	}
+	result = teardown()
+	_ = r.record(afterResultFile, result)
+	return result, true
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventRun_BeforeOnceAfterLast(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	id := ChainID("db", "PreToolUse", "{}")
+	jobs := []string{"a", "b", "c"}
+
+	var setups, teardowns atomic.Int32
+	var wg sync.WaitGroup
+	results := make([]LifecycleResult, len(jobs))
+	for i := range jobs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			run, err := OpenEventRun("s-1", id)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results[i], _, err = run.Before(context.Background(), 5*time.Second, func() LifecycleResult {
+				setups.Add(1)
+				time.Sleep(100 * time.Millisecond)
+				return LifecycleResult{OK: true}
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := setups.Load(); n != 1 {
+		t.Fatalf("before_all ran %d times, want once", n)
+	}
+	for i, r := range results {
+		if !r.OK {
+			t.Errorf("job %s saw %+v, want the shared success", jobs[i], r)
+		}
+	}
+
+	run, err := OpenEventRun("s-1", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teardown := func() LifecycleResult {
+		teardowns.Add(1)
+		return LifecycleResult{Output: "down failed"}
+	}
+	for _, job := range jobs[:2] {
+		if _, ran := run.Leave(job, jobs, teardown); ran {
+			t.Fatalf("after_all ran when %s left before the last job", job)
+		}
+	}
+	result, ran := run.Leave("c", jobs, teardown)
+	if !ran || result.OK || result.Output != "down failed" {
+		t.Errorf("last job Leave = %+v, %v; want after_all's failure", result, ran)
+	}
+	if _, ran := run.Leave("c", jobs, teardown); ran || teardowns.Load() != 1 {
+		t.Errorf("after_all ran %d times, want once", teardowns.Load())
+	}
+}
+
+func TestEventRun_BeforeWaitTimesOut(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	run, err := OpenEventRun("s-1", ChainID("db", "Stop", "{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !run.claim(beforeClaimFile) {
+		t.Fatal("expected to claim before_all")
+	}
+	// The claiming job never records a result
+	if _, ran, err := run.Before(context.Background(), 100*time.Millisecond, func() LifecycleResult {
+		t.Error("setup must not run twice")
+		return LifecycleResult{}
+	}); ran || err == nil {
+		t.Errorf("Before = ran %v, err %v; want a timeout error", ran, err)
+	}
+}
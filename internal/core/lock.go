@@ -0,0 +1,33 @@
+package core
+
+import (
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// The named lock lives in config so settings and registry writes can use it
+// too; these forward to it for the hooks and commands built on core.
+
+// DefaultLockWait is how long AcquireNamedLock waits when no wait is given
+const DefaultLockWait = config.DefaultLockWait
+
+// ErrLockTimeout is returned when a named lock could not be acquired in time
+var ErrLockTimeout = config.ErrLockTimeout
+
+// LockDir returns the machine-wide directory used for named locks
+func LockDir() string {
+	return config.LockDir()
+}
+
+// AcquireNamedLock blocks until the cross-process lock called name is held or
+// wait elapses; see config.AcquireNamedLock
+func AcquireNamedLock(name string, wait time.Duration) (func(), error) {
+	return config.AcquireNamedLock(name, wait)
+}
+
+// AcquireJobLock takes the project-scoped lock of a serialize job; see
+// config.AcquireJobLock
+func AcquireJobLock(name string, wait time.Duration) (func(), error) {
+	return config.AcquireJobLock(name, wait)
+}
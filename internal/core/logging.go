@@ -14,16 +14,25 @@ import (
 type LogEntry struct {
 	Timestamp string                 `json:"timestamp"`
 	HookKey   string                 `json:"hook_key"`
+	Level     string                 `json:"level,omitempty"`
 	Event     string                 `json:"event"`
 	ToolName  string                 `json:"tool_name"`
 	RawData   map[string]interface{} `json:"raw_data,omitempty"`
 	Details   map[string]interface{} `json:"details,omitempty"`
 }
 
-// logHookEvent centralizes structured hook event logging.
+// logHookEvent centralizes structured hook event logging at info level.
 // It is a no-op if LoggingEnabled is false.
 func logHookEvent(ctx *HookContext, hookKey, event, toolName string,
 	rawData map[string]interface{}, details map[string]interface{},
+) {
+	logHookEventAt(ctx, hookKey, config.LogLevelInfo, event, toolName, rawData, details)
+}
+
+// logHookEventAt writes an entry tagged with level. Level filtering is left
+// to the caller, which knows any per-hook override.
+func logHookEventAt(ctx *HookContext, hookKey, level, event, toolName string,
+	rawData map[string]interface{}, details map[string]interface{},
 ) {
 	if ctx == nil || !ctx.LoggingEnabled {
 		return
@@ -32,10 +41,11 @@ func logHookEvent(ctx *HookContext, hookKey, event, toolName string,
 	entry := LogEntry{
 		Timestamp: time.Now().Format(time.RFC3339),
 		HookKey:   hookKey,
+		Level:     level,
 		Event:     event,
 		ToolName:  toolName,
-		RawData:   rawData,
-		Details:   details,
+		RawData:   ctx.Redactor.Map(rawData),
+		Details:   ctx.Redactor.Map(details),
 	}
 
 	// Ensure logging directory exists
@@ -61,6 +71,13 @@ func logHookEvent(ctx *HookContext, hookKey, event, toolName string,
 		return
 	}
 
+	if ctx.LogWriter != nil {
+		if _, err := ctx.LogWriter.Write(append(jsonData, '\n')); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to log file: %v\n", err)
+		}
+		return
+	}
+
 	// Append to log file
 	file, err := ctx.FileSystem.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 	if err != nil {
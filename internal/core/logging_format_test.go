@@ -90,3 +90,21 @@ func TestLogHookEvent_PrettyFormat(t *testing.T) {
 		t.Errorf("expected event 'pretty_event', got %v", obj["event"])
 	}
 }
+
+func TestLogHookEvent_UsesLogWriter(t *testing.T) {
+	ctx := DefaultHookContext()
+	ctx.LoggingEnabled = true
+	ctx.LoggingDir = t.TempDir()
+	ctx.LoggingFormat = config.LoggingFormatJSONL
+	var buf strings.Builder
+	ctx.LogWriter = &buf
+
+	logHookEvent(ctx, "writerhook", "writer_event", "ToolZ", nil, nil)
+
+	if !strings.Contains(buf.String(), `"event":"writer_event"`) {
+		t.Errorf("expected entry written to LogWriter, got %q", buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(ctx.LoggingDir, "writerhook.log")); !os.IsNotExist(err) {
+		t.Errorf("expected no direct file write when LogWriter is set")
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import "slices"
+
+// Capability is a behavior a hook declares in its manifest, so commands can
+// warn about it or decide where the hook may run
+type Capability string
+
+// Capabilities declared by hooks
+const (
+	// CapabilityBlocks means the hook can stop a tool call or the agent
+	CapabilityBlocks Capability = "can-block"
+	// CapabilityModifiesFiles means the hook writes, rewrites or moves project files
+	CapabilityModifiesFiles Capability = "modifies-files"
+	// CapabilityRunsCommands means the hook starts external programs
+	CapabilityRunsCommands Capability = "runs-commands"
+	// CapabilityNeedsNetwork means the hook makes network requests
+	CapabilityNeedsNetwork Capability = "needs-network"
+	// CapabilityWritesState means the hook keeps logs, caches or reports under .claude
+	CapabilityWritesState Capability = "writes-state"
+)
+
+// DefaultManifestVersion is the version of hooks that don't declare one
+const DefaultManifestVersion = "1.0.0"
+
+// Manifest describes a hook: what it handles, how it is installed by
+// default, what it reads from the config and what it may do
+type Manifest struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	// Events lists the events the hook handles
+	Events []string `json:"events"`
+	// DefaultEvent and DefaultMatcher are what 'hooks install' uses when
+	// --event and --matcher are not given
+	DefaultEvent   string `json:"defaultEvent"`
+	DefaultMatcher string `json:"defaultMatcher"`
+	// SettingsKey is the blues-traveler-config.json key the hook reads, and
+	// SettingsSchema its JSON Schema; both are empty for hooks without settings
+	SettingsKey    string                 `json:"settingsKey,omitempty"`
+	SettingsSchema map[string]interface{} `json:"settingsSchema,omitempty"`
+	Capabilities   []Capability           `json:"capabilities,omitempty"`
+}
+
+// Has reports whether the manifest declares c
+func (m Manifest) Has(c Capability) bool {
+	return slices.Contains(m.Capabilities, c)
+}
+
+// HandlesEvent reports whether the hook does anything for event. Aliases
+// such as Cursor event names are resolved first.
+func (m Manifest) HandlesEvent(event string) bool {
+	if resolved := ResolveEventAlias(event); resolved != "" {
+		event = resolved
+	}
+	return slices.Contains(m.Events, event)
+}
@@ -0,0 +1,21 @@
+package core
+
+import "testing"
+
+func TestManifest_HandlesEventAndCapabilities(t *testing.T) {
+	m := NewBaseHook("guard", "Guard", "test", TestHookContext(nil)).Manifest()
+	if m.DefaultEvent != string(PreToolUseEvent) || m.DefaultMatcher != "*" || m.Version != DefaultManifestVersion {
+		t.Fatalf("unexpected base manifest %+v", m)
+	}
+	if !m.HandlesEvent("PreToolUse") || !m.HandlesEvent("beforeShellExecution") {
+		t.Error("expected PreToolUse and its Cursor alias to be handled")
+	}
+	if m.HandlesEvent("Stop") {
+		t.Error("expected Stop not to be handled")
+	}
+
+	m.Capabilities = []Capability{CapabilityBlocks}
+	if !m.Has(CapabilityBlocks) || m.Has(CapabilityNeedsNetwork) {
+		t.Errorf("unexpected capability checks for %v", m.Capabilities)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MCPToolPrefix starts the name of every MCP tool: mcp__<server>__<tool>
+	MCPToolPrefix = "mcp__"
+	// MCPAnyToolMatcher is the settings matcher for every MCP tool
+	MCPAnyToolMatcher = "mcp__.*"
+	// MCPMatcherPrefix starts the shorthand ExpandMCPMatcher turns into a
+	// settings matcher, e.g. mcp:github
+	MCPMatcherPrefix = "mcp:"
+)
+
+// IsMCPTool reports whether toolName is an MCP tool invocation
+func IsMCPTool(toolName string) bool {
+	_, _, ok := ParseMCPToolName(toolName)
+	return ok
+}
+
+// ParseMCPToolName splits mcp__<server>__<tool> into its server and tool.
+// The server ends at the first "__", so tool names may contain "__".
+func ParseMCPToolName(toolName string) (server, tool string, ok bool) {
+	rest, found := strings.CutPrefix(toolName, MCPToolPrefix)
+	if !found {
+		return "", "", false
+	}
+	server, tool, found = strings.Cut(rest, "__")
+	if !found || server == "" || tool == "" {
+		return "", "", false
+	}
+	return server, tool, true
+}
+
+// MCPMatcher builds a settings matcher for a server's tools: every MCP tool
+// when server is empty, every tool of server when tool is empty, otherwise
+// the one tool
+func MCPMatcher(server, tool string) string {
+	if server == "" {
+		return MCPAnyToolMatcher
+	}
+	if tool == "" {
+		return MCPToolPrefix + regexp.QuoteMeta(server) + "__.*"
+	}
+	return MCPToolPrefix + regexp.QuoteMeta(server) + "__" + regexp.QuoteMeta(tool)
+}
+
+// ExpandMCPMatcher turns mcp:<server> shorthand in a matcher into the
+// regular expression Claude Code matches tool names with: mcp:github is
+// every tool of the github server, mcp:github/create_issue one tool,
+// mcp:*/create_issue that tool on any server, and mcp:* (or mcp:) every MCP
+// tool. Each '|'-separated alternative is expanded
+// on its own, so "Bash|mcp:github" works; anything else is left as is.
+func ExpandMCPMatcher(matcher string) string {
+	if !strings.Contains(matcher, MCPMatcherPrefix) {
+		return matcher
+	}
+	parts := strings.Split(matcher, "|")
+	for i, part := range parts {
+		spec, ok := strings.CutPrefix(strings.TrimSpace(part), MCPMatcherPrefix)
+		if !ok {
+			continue
+		}
+		server, tool, _ := strings.Cut(spec, "/")
+		if server == "*" {
+			server = ""
+		}
+		if tool == "*" {
+			tool = ""
+		}
+		if server == "" && tool != "" {
+			// One tool name on any server
+			parts[i] = MCPToolPrefix + ".*__" + regexp.QuoteMeta(tool)
+			continue
+		}
+		parts[i] = MCPMatcher(server, tool)
+	}
+	return strings.Join(parts, "|")
+}
+
+// MatchMCPPattern reports whether server and tool match pattern, written
+// "<server>" for every tool of a server, "<server>/<tool>", or a full
+// mcp__<server>__<tool> name. Both parts may use glob wildcards.
+func MatchMCPPattern(pattern, server, tool string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if s, t, ok := ParseMCPToolName(pattern); ok {
+		pattern = s + "/" + t
+	}
+	serverPattern, toolPattern, hasTool := strings.Cut(pattern, "/")
+	if ok, _ := path.Match(serverPattern, server); !ok {
+		return false
+	}
+	if !hasTool {
+		return true
+	}
+	ok, _ := path.Match(toolPattern, tool)
+	return ok
+}
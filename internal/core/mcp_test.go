@@ -0,0 +1,71 @@
+package core
+
+import "testing"
+
+func TestParseMCPToolName(t *testing.T) {
+	tests := []struct {
+		name, server, tool string
+		ok                 bool
+	}{
+		{"mcp__github__create_issue", "github", "create_issue", true},
+		{"mcp__db__run__query", "db", "run__query", true},
+		{"mcp__github", "", "", false},
+		{"mcp____tool", "", "", false},
+		{"Bash", "", "", false},
+	}
+	for _, tt := range tests {
+		server, tool, ok := ParseMCPToolName(tt.name)
+		if server != tt.server || tool != tt.tool || ok != tt.ok {
+			t.Errorf("ParseMCPToolName(%q) = %q, %q, %v", tt.name, server, tool, ok)
+		}
+	}
+}
+
+func TestMCPMatcher(t *testing.T) {
+	if got := MCPMatcher("", ""); got != "mcp__.*" {
+		t.Errorf("any tool: got %q", got)
+	}
+	if got := MCPMatcher("my.server", ""); got != `mcp__my\.server__.*` {
+		t.Errorf("server: got %q", got)
+	}
+	if got := MCPMatcher("github", "create_issue"); got != "mcp__github__create_issue" {
+		t.Errorf("tool: got %q", got)
+	}
+}
+
+func TestExpandMCPMatcher(t *testing.T) {
+	tests := map[string]string{
+		"mcp:github":              "mcp__github__.*",
+		"mcp:github/create_issue": "mcp__github__create_issue",
+		"mcp:*":                   "mcp__.*",
+		"mcp:":                    "mcp__.*",
+		"mcp:*/search":            "mcp__.*__search",
+		"Bash|mcp:linear":         "Bash|mcp__linear__.*",
+		"Edit,Write":              "Edit,Write",
+		"mcp__github__.*":         "mcp__github__.*",
+	}
+	for in, want := range tests {
+		if got := ExpandMCPMatcher(in); got != want {
+			t.Errorf("ExpandMCPMatcher(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchMCPPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"github", true},
+		{"git*", true},
+		{"github/create_*", true},
+		{"github/delete_repo", false},
+		{"mcp__github__create_issue", true},
+		{"slack", false},
+	}
+	for _, tt := range tests {
+		if got := MatchMCPPattern(tt.pattern, "github", "create_issue"); got != tt.want {
+			t.Errorf("MatchMCPPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
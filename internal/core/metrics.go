@@ -0,0 +1,372 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+const (
+	// metricsSubDir is the directory under .claude/hooks holding run records
+	metricsSubDir = "metrics"
+	// metricsDayLayout names day files (metrics-2006-01-02.jsonl)
+	metricsDayLayout = "2006-01-02"
+	// metricsLockName serializes appends from hooks running in parallel
+	metricsLockName = "hook-metrics"
+	metricsLockWait = 2 * time.Second
+)
+
+// Run decisions recorded in HookRunRecord.Decision
+const (
+	RunDecisionApprove = "approve"
+	RunDecisionBlock   = "block"
+	RunDecisionAsk     = "ask"
+)
+
+// HookRunRecord is one hook process: which hook handled which event, how
+// long it ran and what it decided. Records are stored one per line in the
+// day file for their timestamp.
+type HookRunRecord struct {
+	Time time.Time `json:"time"`
+	Hook string    `json:"hook"`
+	// Event is empty when the hook exited before reading a valid payload
+	Event      string `json:"event,omitempty"`
+	Tool       string `json:"tool,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	ExitStatus int    `json:"exit_status"`
+	Decision   string `json:"decision"`
+}
+
+// MetricsDir returns the project's metrics directory (.claude/hooks/metrics).
+// BT_METRICS_DIR overrides the location.
+func MetricsDir() (string, error) {
+	if dir := os.Getenv("BT_METRICS_DIR"); dir != "" {
+		return dir, nil
+	}
+	claudeDir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(claudeDir, constants.HooksSubDir, metricsSubDir), nil
+}
+
+// hookRun collects what one hook process did, to be recorded at exit
+type hookRun struct {
+	mu       sync.Mutex
+	start    time.Time
+	hook     string
+	event    string
+	tool     string
+	decision string
+}
+
+func newHookRun(hook string) *hookRun {
+	return &hookRun{start: time.Now(), hook: hook, decision: RunDecisionApprove}
+}
+
+// observe wraps the handlers to note the event, tool and decision
+func (r *hookRun) observe(
+	preHandler func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	postHandler func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	rawHandler func(context.Context, string) *cchooks.RawResponse,
+) (
+	func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	func(context.Context, string) *cchooks.RawResponse,
+) {
+	var pre func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface
+	if preHandler != nil {
+		pre = func(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			resp := preHandler(ctx, event)
+			r.note(string(PreToolUseEvent), event.ToolName, runDecision(SummarizeResponse(resp).Decision))
+			return resp
+		}
+	}
+	var post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface
+	if postHandler != nil {
+		post = func(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			resp := postHandler(ctx, event)
+			r.note(string(PostToolUseEvent), event.ToolName, runDecision(SummarizeResponse(resp).Decision))
+			return resp
+		}
+	}
+	// The raw handler sees every payload first, so it names the event even
+	// for events without a typed handler
+	raw := func(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+		var payload struct {
+			Event string `json:"hook_event_name"`
+			Tool  string `json:"tool_name"`
+		}
+		_ = json.Unmarshal([]byte(rawJSON), &payload)
+		var resp *cchooks.RawResponse
+		if rawHandler != nil {
+			resp = rawHandler(ctx, rawJSON)
+		}
+		decision := RunDecisionApprove
+		if resp != nil && resp.ExitCode == 2 {
+			decision = RunDecisionBlock
+		}
+		r.note(payload.Event, payload.Tool, decision)
+		return resp
+	}
+	return pre, post, raw
+}
+
+func (r *hookRun) note(event, tool, decision string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event != "" {
+		r.event = event
+	}
+	if tool != "" {
+		r.tool = tool
+	}
+	r.decision = decision
+}
+
+// attach records the run when runner exits. Only cchooks runners report
+// their exit status; other runners are returned unchanged.
+func (r *hookRun) attach(runner Runner) Runner {
+	cr, ok := runner.(*cchooks.Runner)
+	if !ok {
+		return runner
+	}
+	exit := cr.ExitFn
+	if exit == nil {
+		exit = os.Exit
+	}
+	cr.ExitFn = func(code int) {
+		_ = AppendHookRunRecord(r.record(code))
+		exit(code)
+	}
+	return cr
+}
+
+func (r *hookRun) record(exitStatus int) HookRunRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return HookRunRecord{
+		Time:       r.start.UTC(),
+		Hook:       r.hook,
+		Event:      r.event,
+		Tool:       r.tool,
+		DurationMs: time.Since(r.start).Milliseconds(),
+		ExitStatus: exitStatus,
+		Decision:   r.decision,
+	}
+}
+
+// runDecision maps a response decision to approve, block or ask
+func runDecision(decision string) string {
+	switch decision {
+	case cchooks.PreToolUseBlock:
+		return RunDecisionBlock
+	case PreToolUseAsk:
+		return RunDecisionAsk
+	}
+	return RunDecisionApprove
+}
+
+// AppendHookRunRecord writes rec to its day file in the metrics directory
+func AppendHookRunRecord(rec HookRunRecord) error {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	dir, err := MetricsDir()
+	if err != nil {
+		return err
+	}
+	release, err := AcquireNamedLock(metricsLockName, metricsLockWait)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	// Keep run records out of git status, like the cache dir
+	ignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o600)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook run: %w", err)
+	}
+	path := filepath.Join(dir, "metrics-"+rec.Time.Format(metricsDayLayout)+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 - day file under the metrics dir
+	if err != nil {
+		return fmt.Errorf("failed to open metrics log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write metrics log: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadHookRunRecords returns runs recorded at or after since (all runs when
+// zero), oldest first. Day files before since are not read, and lines that
+// don't parse are skipped.
+func LoadHookRunRecords(since time.Time) ([]HookRunRecord, error) {
+	dir, err := MetricsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics directory: %w", err)
+	}
+
+	firstDay := ""
+	if !since.IsZero() {
+		firstDay = since.UTC().Format(metricsDayLayout)
+	}
+	var out []HookRunRecord
+	for _, e := range entries {
+		day, ok := strings.CutPrefix(e.Name(), "metrics-")
+		if !ok || e.IsDir() {
+			continue
+		}
+		day, ok = strings.CutSuffix(day, ".jsonl")
+		if !ok || day < firstDay {
+			continue
+		}
+		recs, err := readHookRunDay(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range recs {
+			if since.IsZero() || !rec.Time.Before(since) {
+				out = append(out, rec)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
+
+func readHookRunDay(path string) ([]HookRunRecord, error) {
+	f, err := os.Open(path) // #nosec G304 - day file under the metrics dir
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var recs []HookRunRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec HookRunRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) != nil || rec.Hook == "" {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics log %s: %w", path, err)
+	}
+	return recs, nil
+}
+
+// ResetHookRunRecords deletes the recorded runs
+func ResetHookRunRecords() error {
+	dir, err := MetricsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to reset hook metrics: %w", err)
+	}
+	return nil
+}
+
+// HookRunStat aggregates the runs of one hook key and/or event
+type HookRunStat struct {
+	Hook    string
+	Event   string
+	Runs    int
+	TotalMs int64
+	MaxMs   int64
+	// P95Ms is the 95th percentile duration
+	P95Ms  int64
+	Blocks int
+	Asks   int
+	// Errors counts runs that exited with a status other than 0 or 2
+	Errors int
+}
+
+// AverageMs returns the mean duration per run
+func (s HookRunStat) AverageMs() int64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.TotalMs / int64(s.Runs)
+}
+
+// SummarizeHookRuns groups records by hook key, event, or both, and returns
+// the groups by total time, highest first
+func SummarizeHookRuns(records []HookRunRecord, byHook, byEvent bool) []HookRunStat {
+	type key struct{ hook, event string }
+	stats := map[key]*HookRunStat{}
+	durations := map[key][]int64{}
+	for _, rec := range records {
+		k := key{}
+		if byHook {
+			k.hook = rec.Hook
+		}
+		if byEvent {
+			k.event = rec.Event
+		}
+		s := stats[k]
+		if s == nil {
+			s = &HookRunStat{Hook: k.hook, Event: k.event}
+			stats[k] = s
+		}
+		s.Runs++
+		s.TotalMs += rec.DurationMs
+		s.MaxMs = max(s.MaxMs, rec.DurationMs)
+		switch rec.Decision {
+		case RunDecisionBlock:
+			s.Blocks++
+		case RunDecisionAsk:
+			s.Asks++
+		}
+		if rec.ExitStatus != 0 && rec.ExitStatus != 2 {
+			s.Errors++
+		}
+		durations[k] = append(durations[k], rec.DurationMs)
+	}
+
+	out := make([]HookRunStat, 0, len(stats))
+	for k, s := range stats {
+		d := durations[k]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.P95Ms = d[(len(d)*95+99)/100-1]
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalMs != out[j].TotalMs {
+			return out[i].TotalMs > out[j].TotalMs
+		}
+		if out[i].Hook != out[j].Hook {
+			return out[i].Hook < out[j].Hook
+		}
+		return out[i].Event < out[j].Event
+	})
+	return out
+}
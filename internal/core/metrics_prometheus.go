@@ -0,0 +1,196 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// hookDurationBuckets are the upper bounds, in seconds, of the hook duration
+// histogram: from fast built-ins to jobs that run a test suite
+var hookDurationBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// HookRunCollector keeps running totals of the recorded hook runs for a
+// Prometheus scrape. It reads the metrics day files once, then only the
+// lines appended since the last Collect, so its counters never go down
+// when retention removes old day files.
+type HookRunCollector struct {
+	mu      sync.Mutex
+	offsets map[string]int64
+	series  map[hookRunSeries]*hookRunTotals
+}
+
+// hookRunSeries is the label set shared by the per-hook metrics
+type hookRunSeries struct {
+	hook, event string
+}
+
+type hookRunTotals struct {
+	decisions map[string]int64
+	errors    int64
+	// buckets counts runs at or under each hookDurationBuckets bound
+	buckets []int64
+	count   int64
+	sumMs   int64
+}
+
+// NewHookRunCollector creates a collector with no runs counted yet
+func NewHookRunCollector() *HookRunCollector {
+	return &HookRunCollector{offsets: map[string]int64{}, series: map[hookRunSeries]*hookRunTotals{}}
+}
+
+// Collect adds the runs recorded since the last call. A day file that got
+// shorter was reset and is read again from the start.
+func (c *HookRunCollector) Collect() error {
+	dir, err := MetricsDir()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics directory: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "metrics-") || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		if err := c.collectFile(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFile reads the complete lines appended to path since its offset
+func (c *HookRunCollector) collectFile(path string) error {
+	f, err := os.Open(path) // #nosec G304 - day file under the metrics dir
+	if err != nil {
+		return fmt.Errorf("failed to open metrics log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read metrics log %s: %w", path, err)
+	}
+	offset := c.offsets[path]
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return nil
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return fmt.Errorf("failed to read metrics log %s: %w", path, err)
+	}
+	// A line still being written is left for the next scrape
+	end := bytes.LastIndexByte(data, '\n') + 1
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		var rec HookRunRecord
+		if json.Unmarshal(line, &rec) != nil || rec.Hook == "" {
+			continue
+		}
+		c.add(rec)
+	}
+	c.offsets[path] = offset + int64(end)
+	return nil
+}
+
+func (c *HookRunCollector) add(rec HookRunRecord) {
+	k := hookRunSeries{hook: rec.Hook, event: rec.Event}
+	t := c.series[k]
+	if t == nil {
+		t = &hookRunTotals{decisions: map[string]int64{}, buckets: make([]int64, len(hookDurationBuckets))}
+		c.series[k] = t
+	}
+	t.decisions[rec.Decision]++
+	if rec.ExitStatus != 0 && rec.ExitStatus != 2 {
+		t.errors++
+	}
+	seconds := float64(rec.DurationMs) / 1000
+	for i, le := range hookDurationBuckets {
+		if seconds <= le {
+			t.buckets[i]++
+		}
+	}
+	t.count++
+	t.sumMs += rec.DurationMs
+}
+
+// WritePrometheus writes the totals in the Prometheus text exposition
+// format, series sorted by hook and event
+func (c *HookRunCollector) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]hookRunSeries, 0, len(c.series))
+	for k := range c.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].hook != keys[j].hook {
+			return keys[i].hook < keys[j].hook
+		}
+		return keys[i].event < keys[j].event
+	})
+	labels := func(k hookRunSeries, extra ...string) string {
+		pairs := append([]string{"hook", k.hook, "event", k.event}, extra...)
+		parts := make([]string, 0, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			parts = append(parts, fmt.Sprintf("%s=\"%s\"", pairs[i], escapePrometheusLabel(pairs[i+1])))
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP blues_traveler_hook_runs_total Hook runs recorded, by decision.\n")
+	b.WriteString("# TYPE blues_traveler_hook_runs_total counter\n")
+	for _, k := range keys {
+		t := c.series[k]
+		decisions := make([]string, 0, len(t.decisions))
+		for d := range t.decisions {
+			decisions = append(decisions, d)
+		}
+		sort.Strings(decisions)
+		for _, d := range decisions {
+			fmt.Fprintf(&b, "blues_traveler_hook_runs_total%s %d\n", labels(k, "decision", d), t.decisions[d])
+		}
+	}
+	b.WriteString("# HELP blues_traveler_hook_errors_total Hook runs that exited with a status other than 0 or 2.\n")
+	b.WriteString("# TYPE blues_traveler_hook_errors_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "blues_traveler_hook_errors_total%s %d\n", labels(k), c.series[k].errors)
+	}
+	b.WriteString("# HELP blues_traveler_hook_duration_seconds How long hook runs took.\n")
+	b.WriteString("# TYPE blues_traveler_hook_duration_seconds histogram\n")
+	for _, k := range keys {
+		t := c.series[k]
+		for i, le := range hookDurationBuckets {
+			fmt.Fprintf(&b, "blues_traveler_hook_duration_seconds_bucket%s %d\n", labels(k, "le", fmt.Sprint(le)), t.buckets[i])
+		}
+		fmt.Fprintf(&b, "blues_traveler_hook_duration_seconds_bucket%s %d\n", labels(k, "le", "+Inf"), t.count)
+		fmt.Fprintf(&b, "blues_traveler_hook_duration_seconds_sum%s %s\n", labels(k), fmt.Sprint(float64(t.sumMs)/1000))
+		fmt.Fprintf(&b, "blues_traveler_hook_duration_seconds_count%s %d\n", labels(k), t.count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapePrometheusLabel escapes a label value for the text format
+func escapePrometheusLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
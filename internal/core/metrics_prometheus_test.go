@@ -0,0 +1,83 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookRunCollector_CountsAppendedRuns(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_METRICS_DIR", dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	now := time.Now().UTC()
+
+	for _, rec := range []HookRunRecord{
+		{Time: now, Hook: "format", Event: "PostToolUse", DurationMs: 40, Decision: RunDecisionApprove},
+		{Time: now, Hook: "security", Event: "PreToolUse", DurationMs: 700, ExitStatus: 2, Decision: RunDecisionBlock},
+	} {
+		if err := AppendHookRunRecord(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewHookRunCollector()
+	if err := c.Collect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run and a line still being written: only the complete one counts
+	if err := AppendHookRunRecord(HookRunRecord{Time: now, Hook: "security", Event: "PreToolUse", DurationMs: 3000, ExitStatus: 1, Decision: RunDecisionApprove}); err != nil {
+		t.Fatal(err)
+	}
+	day := filepath.Join(dir, "metrics-"+now.Format(metricsDayLayout)+".jsonl")
+	f, err := os.OpenFile(day, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"hook":"format","event":"PostToolUse"`)
+	_ = f.Close()
+	if err := c.Collect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.WritePrometheus(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`blues_traveler_hook_runs_total{hook="format",event="PostToolUse",decision="approve"} 1`,
+		`blues_traveler_hook_runs_total{hook="security",event="PreToolUse",decision="approve"} 1`,
+		`blues_traveler_hook_runs_total{hook="security",event="PreToolUse",decision="block"} 1`,
+		`blues_traveler_hook_errors_total{hook="security",event="PreToolUse"} 1`,
+		`blues_traveler_hook_duration_seconds_bucket{hook="security",event="PreToolUse",le="1"} 1`,
+		`blues_traveler_hook_duration_seconds_bucket{hook="security",event="PreToolUse",le="+Inf"} 2`,
+		`blues_traveler_hook_duration_seconds_sum{hook="security",event="PreToolUse"} 3.7`,
+		`blues_traveler_hook_duration_seconds_count{hook="format",event="PostToolUse"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+
+	// Counters keep their totals once retention removes the day file
+	if err := ResetHookRunRecords(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	_ = c.WritePrometheus(&b)
+	if !strings.Contains(b.String(), `blues_traveler_hook_duration_seconds_count{hook="security",event="PreToolUse"} 2`) {
+		t.Errorf("counters went down after the day file was removed:\n%s", b.String())
+	}
+}
+
+func TestEscapePrometheusLabel(t *testing.T) {
+	if got := escapePrometheusLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapePrometheusLabel = %q", got)
+	}
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/brads3290/cchooks"
+)
+
+func TestHookRun_RecordsDecisionAndExitStatus(t *testing.T) {
+	t.Setenv("BT_METRICS_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	run := newHookRun("security")
+	pre, _, raw := run.observe(func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+		return BlockWithMessages("blocked", "blocked")
+	}, nil, nil)
+	exited := -1
+	runner := DefaultRunnerFactory(pre, nil, raw).(*cchooks.Runner)
+	runner.ExitFn = func(code int) { exited = code }
+	run.attach(runner)
+
+	payload, _ := json.Marshal(map[string]string{"hook_event_name": "PreToolUse", "tool_name": "Bash"})
+	if resp := runner.Raw(context.Background(), string(payload)); resp != nil {
+		t.Fatalf("raw observer should fall through, got %+v", resp)
+	}
+	runner.PreToolUse(context.Background(), &cchooks.PreToolUseEvent{ToolName: "Bash"})
+	runner.ExitFn(0)
+	if exited != 0 {
+		t.Fatalf("original exit not called, got %d", exited)
+	}
+
+	records, err := LoadHookRunRecords(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %+v", records)
+	}
+	rec := records[0]
+	if rec.Hook != "security" || rec.Event != "PreToolUse" || rec.Tool != "Bash" || rec.Decision != RunDecisionBlock || rec.ExitStatus != 0 {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
+
+func TestRunner_MetricsDisabledRecordsNothing(t *testing.T) {
+	t.Setenv("BT_METRICS_DIR", t.TempDir())
+	hook := NewBaseHook("fmt", "Format", "", TestHookContext(nil))
+	if _, ok := hook.Runner(nil, nil, nil).(*MockRunner); !ok {
+		t.Fatal("expected the context's runner unchanged")
+	}
+	if records, _ := LoadHookRunRecords(time.Time{}); len(records) != 0 {
+		t.Errorf("metrics disabled, got %+v", records)
+	}
+}
+
+func TestLoadHookRunRecords_Since(t *testing.T) {
+	t.Setenv("BT_METRICS_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	now := time.Now().UTC()
+	for _, age := range []time.Duration{72 * time.Hour, 2 * time.Hour, time.Minute} {
+		if err := AppendHookRunRecord(HookRunRecord{Time: now.Add(-age), Hook: "vet", Event: "PostToolUse", Decision: RunDecisionApprove}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := LoadHookRunRecords(time.Time{})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 records, got %d, %v", len(all), err)
+	}
+	if !all[0].Time.Before(all[2].Time) {
+		t.Errorf("expected oldest first, got %+v", all)
+	}
+	recent, err := LoadHookRunRecords(now.Add(-3 * time.Hour))
+	if err != nil || len(recent) != 2 {
+		t.Fatalf("expected 2 records in the window, got %d, %v", len(recent), err)
+	}
+}
+
+func TestSummarizeHookRuns(t *testing.T) {
+	var records []HookRunRecord
+	for i := int64(1); i <= 20; i++ {
+		records = append(records, HookRunRecord{Hook: "format", Event: "PostToolUse", DurationMs: i * 100, Decision: RunDecisionApprove})
+	}
+	records = append(records,
+		HookRunRecord{Hook: "security", Event: "PreToolUse", DurationMs: 10, Decision: RunDecisionBlock, ExitStatus: 0},
+		HookRunRecord{Hook: "security", Event: "PreToolUse", DurationMs: 30, Decision: RunDecisionAsk, ExitStatus: 1},
+		HookRunRecord{Hook: "format", Event: "PreToolUse", DurationMs: 5, Decision: RunDecisionApprove},
+	)
+
+	stats := SummarizeHookRuns(records, true, true)
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", stats)
+	}
+	top := stats[0]
+	if top.Hook != "format" || top.Event != "PostToolUse" || top.Runs != 20 || top.TotalMs != 21000 || top.MaxMs != 2000 || top.P95Ms != 1900 || top.AverageMs() != 1050 {
+		t.Errorf("unexpected format stats %+v", top)
+	}
+	if sec := stats[1]; sec.Hook != "security" || sec.Blocks != 1 || sec.Asks != 1 || sec.Errors != 1 {
+		t.Errorf("unexpected security stats %+v", sec)
+	}
+
+	byHook := SummarizeHookRuns(records, true, false)
+	if len(byHook) != 2 || byHook[0].Hook != "format" || byHook[0].Runs != 21 || byHook[0].Event != "" {
+		t.Errorf("unexpected per-hook stats %+v", byHook)
+	}
+}
@@ -117,7 +117,10 @@ func NewMockCommandExecutor() *MockCommandExecutor {
 }
 
 // ExecuteCommand executes a mock command and returns the pre-configured response
-func (m *MockCommandExecutor) ExecuteCommand(name string, args ...string) ([]byte, error) {
+func (m *MockCommandExecutor) ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -205,8 +208,12 @@ type MockRunner struct {
 
 // Run marks the runner as called (mock implementation for testing)
 func (m *MockRunner) Run() {
+	m.RunContext(context.Background())
+}
+
+// RunContext marks the runner as called without reading stdin
+func (m *MockRunner) RunContext(context.Context) {
 	m.RunCalled = true
-	// Don't actually read from stdin in tests
 }
 
 // MockRunnerFactory creates MockRunner instances
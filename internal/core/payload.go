@@ -0,0 +1,118 @@
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/klauern/blues-traveler/internal/constants"
+)
+
+// Kinds of file modification reported in a ToolPayload
+const (
+	FileEditKindEdit     = "edit"
+	FileEditKindWrite    = "write"
+	FileEditKindNotebook = "notebook"
+)
+
+// FileEdit is a single file modification within a tool call. MultiEdit calls
+// produce one FileEdit per sub-edit so hooks can see every change.
+type FileEdit struct {
+	Kind      string
+	FilePath  string
+	OldString string
+	NewString string
+	// CellID identifies the notebook cell for NotebookEdit
+	CellID string
+}
+
+// ToolPayload is a tool-agnostic view of the files a tool call modifies
+type ToolPayload struct {
+	ToolName string
+	Edits    []FileEdit
+}
+
+// multiEditInput mirrors the MultiEdit tool input. Sub-edits may name their
+// own file_path; otherwise they apply to the top-level file.
+type multiEditInput struct {
+	FilePath string `json:"file_path"`
+	Edits    []struct {
+		FilePath   string `json:"file_path"`
+		OldString  string `json:"old_string"`
+		NewString  string `json:"new_string"`
+		ReplaceAll bool   `json:"replace_all"`
+	} `json:"edits"`
+}
+
+type editInput struct {
+	FilePath  string `json:"file_path"`
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+	Content   string `json:"content"`
+}
+
+type notebookEditInput struct {
+	NotebookPath string `json:"notebook_path"`
+	CellID       string `json:"cell_id"`
+	NewSource    string `json:"new_source"`
+}
+
+// ParseToolPayload extracts file modifications from a tool input. Parsing is
+// lenient (unlike cchooks' validated InputAs* helpers) so that, for example,
+// an edit that deletes text with an empty new_string is still reported.
+// Tools that do not modify files yield an empty payload.
+func ParseToolPayload(toolName string, input json.RawMessage) ToolPayload {
+	p := ToolPayload{ToolName: toolName}
+	if len(input) == 0 {
+		return p
+	}
+
+	switch toolName {
+	case constants.ToolEdit, constants.ToolWrite:
+		var in editInput
+		if json.Unmarshal(input, &in) != nil || in.FilePath == "" {
+			return p
+		}
+		kind, newString := FileEditKindEdit, in.NewString
+		if toolName == constants.ToolWrite {
+			kind, newString = FileEditKindWrite, in.Content
+		}
+		p.Edits = append(p.Edits, FileEdit{Kind: kind, FilePath: in.FilePath, OldString: in.OldString, NewString: newString})
+	case constants.ToolMultiEdit:
+		var in multiEditInput
+		if json.Unmarshal(input, &in) != nil {
+			return p
+		}
+		for _, e := range in.Edits {
+			path := e.FilePath
+			if path == "" {
+				path = in.FilePath
+			}
+			if path == "" {
+				continue
+			}
+			p.Edits = append(p.Edits, FileEdit{Kind: FileEditKindEdit, FilePath: path, OldString: e.OldString, NewString: e.NewString})
+		}
+		if len(p.Edits) == 0 && in.FilePath != "" {
+			p.Edits = append(p.Edits, FileEdit{Kind: FileEditKindEdit, FilePath: in.FilePath})
+		}
+	case constants.ToolNotebookEdit:
+		var in notebookEditInput
+		if json.Unmarshal(input, &in) != nil || in.NotebookPath == "" {
+			return p
+		}
+		p.Edits = append(p.Edits, FileEdit{Kind: FileEditKindNotebook, FilePath: in.NotebookPath, NewString: in.NewSource, CellID: in.CellID})
+	}
+	return p
+}
+
+// Files returns the distinct file paths touched, in first-seen order
+func (p ToolPayload) Files() []string {
+	seen := make(map[string]bool, len(p.Edits))
+	var files []string
+	for _, e := range p.Edits {
+		if !seen[e.FilePath] {
+			seen[e.FilePath] = true
+			files = append(files, e.FilePath)
+		}
+	}
+	return files
+}
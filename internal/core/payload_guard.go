@@ -0,0 +1,221 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"unicode/utf8"
+)
+
+const (
+	// PayloadFileEnv names the file holding the full event payload when a
+	// job's input was truncated
+	PayloadFileEnv = "BT_PAYLOAD_FILE"
+	// PayloadTruncatedEnv is "1" when a job's stdin or environment was truncated
+	PayloadTruncatedEnv = "BT_PAYLOAD_TRUNCATED"
+
+	// minTruncatedString is the shortest a string is cut to; shorter strings
+	// are left alone since cutting them saves little
+	minTruncatedString = 64
+)
+
+// GuardedInput is the event input handed to a job after applying a size
+// limit. Call Cleanup once the job has finished.
+type GuardedInput struct {
+	// Stdin is the payload for the job's stdin, still valid JSON
+	Stdin string
+	// Env is the job environment with oversized values cut
+	Env map[string]string
+	// File holds the untruncated payload; empty when nothing was cut
+	File      string
+	Truncated bool
+}
+
+// GuardInput limits the payload and each environment value to maxBytes.
+// Oversized string fields in the payload (Write contents, edit strings,
+// prompts) are cut, largest first, and end with a marker naming the bytes
+// removed, so stdin stays parseable JSON. When anything is cut, the full
+// payload is written to a private temp file exposed as BT_PAYLOAD_FILE. A
+// non-positive maxBytes returns the input unchanged.
+func GuardInput(payload string, env map[string]string, maxBytes int64) (*GuardedInput, error) {
+	g := &GuardedInput{Stdin: payload, Env: env}
+	if maxBytes <= 0 {
+		return g, nil
+	}
+
+	stdin, payloadCut := truncatePayloadJSON(payload, maxBytes)
+	envCut := false
+	for _, v := range env {
+		if int64(len(v)) > maxBytes {
+			envCut = true
+			break
+		}
+	}
+	if !payloadCut && !envCut {
+		return g, nil
+	}
+
+	g.Truncated = true
+	g.Stdin = stdin
+	if payload != "" {
+		f, err := os.CreateTemp("", "bt-payload-*.json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to save full payload: %w", err)
+		}
+		g.File = f.Name()
+		_, werr := f.WriteString(payload)
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			g.Cleanup()
+			return nil, fmt.Errorf("failed to save full payload: %w", werr)
+		}
+	}
+
+	g.Env = make(map[string]string, len(env)+2)
+	for k, v := range env {
+		if int64(len(v)) > maxBytes {
+			v = cutString(v, int(maxBytes))
+		}
+		g.Env[k] = v
+	}
+	g.Env[PayloadTruncatedEnv] = "1"
+	if g.File != "" {
+		g.Env[PayloadFileEnv] = g.File
+	}
+	return g, nil
+}
+
+// Cleanup removes the full-payload file, if any
+func (g *GuardedInput) Cleanup() {
+	if g != nil && g.File != "" {
+		_ = os.Remove(g.File)
+	}
+}
+
+// truncationMarker ends a cut string
+func truncationMarker(removed int) string {
+	return fmt.Sprintf("…[truncated %d bytes; full payload in $%s]", removed, PayloadFileEnv)
+}
+
+// cutString shortens s so that, with its marker, it takes about keep bytes.
+// The cut lands on a rune boundary.
+func cutString(s string, keep int) string {
+	keep -= len(truncationMarker(len(s)))
+	if keep < 0 {
+		keep = 0
+	}
+	if keep >= len(s) {
+		return s
+	}
+	for keep > 0 && !utf8.RuneStart(s[keep]) {
+		keep--
+	}
+	return s[:keep] + truncationMarker(len(s)-keep)
+}
+
+// truncatePayloadJSON cuts the largest string values in a JSON payload until
+// it encodes to at most maxBytes, or nothing worth cutting is left. Payloads
+// that are not JSON objects are returned unchanged.
+func truncatePayloadJSON(payload string, maxBytes int64) (string, bool) {
+	if int64(len(payload)) <= maxBytes {
+		return payload, false
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(payload)))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return payload, false
+	}
+
+	var leaves []*stringLeaf
+	collectStringLeaves(doc, &leaves)
+	cut := false
+	for {
+		encoded, err := encodePayload(doc)
+		if err != nil {
+			return payload, false
+		}
+		excess := int64(len(encoded)) - maxBytes
+		if excess <= 0 {
+			return encoded, cut
+		}
+		largest := largestLeaf(leaves)
+		if largest == nil {
+			return encoded, cut
+		}
+		s := largest.get()
+		keep := len(s) - int(excess)
+		if keep < minTruncatedString {
+			keep = minTruncatedString
+		}
+		largest.set(cutString(s, keep))
+		largest.done = true
+		cut = true
+	}
+}
+
+// stringLeaf is a string value inside a decoded JSON document
+type stringLeaf struct {
+	get  func() string
+	set  func(string)
+	done bool
+}
+
+func collectStringLeaves(v interface{}, leaves *[]*stringLeaf) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, child := range node {
+			if _, ok := child.(string); ok {
+				key := k
+				*leaves = append(*leaves, &stringLeaf{
+					get: func() string { return node[key].(string) },
+					set: func(s string) { node[key] = s },
+				})
+				continue
+			}
+			collectStringLeaves(child, leaves)
+		}
+	case []interface{}:
+		for i, child := range node {
+			if _, ok := child.(string); ok {
+				idx := i
+				*leaves = append(*leaves, &stringLeaf{
+					get: func() string { return node[idx].(string) },
+					set: func(s string) { node[idx] = s },
+				})
+				continue
+			}
+			collectStringLeaves(child, leaves)
+		}
+	}
+}
+
+// largestLeaf returns the longest string not yet cut that is worth cutting
+func largestLeaf(leaves []*stringLeaf) *stringLeaf {
+	var best *stringLeaf
+	bestLen := minTruncatedString + len(truncationMarker(0))
+	for _, l := range leaves {
+		if l.done {
+			continue
+		}
+		if n := len(l.get()); n > bestLen {
+			best, bestLen = l, n
+		}
+	}
+	return best
+}
+
+// encodePayload marshals doc compactly without HTML escaping, which would
+// inflate code in Write contents
+func encodePayload(doc interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
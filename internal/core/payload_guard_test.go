@@ -0,0 +1,70 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGuardInput(t *testing.T) {
+	content := strings.Repeat("<é>", 20000)
+	payload := `{"hook_event_name":"PreToolUse","tool_name":"Write","tool_input":{"file_path":"big.html","content":"` + content + `"}}`
+	env := map[string]string{"TOOL_NAME": "Write", "USER_PROMPT": strings.Repeat("p", 5000)}
+
+	g, err := GuardInput(payload, env, 4096)
+	if err != nil {
+		t.Fatalf("GuardInput: %v", err)
+	}
+	defer g.Cleanup()
+
+	if !g.Truncated || len(g.Stdin) > 4096 {
+		t.Fatalf("expected stdin cut to 4096 bytes, got %d (truncated=%v)", len(g.Stdin), g.Truncated)
+	}
+	var ev struct {
+		ToolInput struct {
+			FilePath string `json:"file_path"`
+			Content  string `json:"content"`
+		} `json:"tool_input"`
+	}
+	if err := json.Unmarshal([]byte(g.Stdin), &ev); err != nil {
+		t.Fatalf("truncated stdin is not valid JSON: %v", err)
+	}
+	if ev.ToolInput.FilePath != "big.html" {
+		t.Errorf("short fields must survive, got file_path %q", ev.ToolInput.FilePath)
+	}
+	if !strings.HasPrefix(ev.ToolInput.Content, "<é>") || !strings.Contains(ev.ToolInput.Content, "…[truncated ") {
+		t.Errorf("expected a cut content with a marker, got %q", ev.ToolInput.Content[:40])
+	}
+
+	full, err := os.ReadFile(g.Env[PayloadFileEnv])
+	if err != nil || string(full) != payload {
+		t.Fatalf("expected the full payload in %s: %v", PayloadFileEnv, err)
+	}
+	if g.Env[PayloadTruncatedEnv] != "1" || g.Env["TOOL_NAME"] != "Write" {
+		t.Errorf("unexpected env: %v", g.Env)
+	}
+	if len(g.Env["USER_PROMPT"]) > 4096 || !strings.Contains(g.Env["USER_PROMPT"], "truncated") {
+		t.Errorf("expected USER_PROMPT cut to the limit, got %d bytes", len(g.Env["USER_PROMPT"]))
+	}
+
+	g.Cleanup()
+	if _, err := os.Stat(g.File); !os.IsNotExist(err) {
+		t.Errorf("expected Cleanup to remove %s", g.File)
+	}
+}
+
+func TestGuardInput_PassThrough(t *testing.T) {
+	payload := `{"hook_event_name":"PreToolUse","tool_input":{"content":"` + strings.Repeat("x", 1000) + `"}}`
+	env := map[string]string{"TOOL_NAME": "Write"}
+
+	for _, limit := range []int64{0, 1 << 20} {
+		g, err := GuardInput(payload, env, limit)
+		if err != nil {
+			t.Fatalf("GuardInput(%d): %v", limit, err)
+		}
+		if g.Truncated || g.Stdin != payload || g.File != "" || len(g.Env) != 1 {
+			t.Errorf("limit %d: expected input unchanged, got %+v", limit, g)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+)
+
+func TestParseToolPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		input     string
+		wantFiles []string
+		wantEdits int
+	}{
+		{"edit", "Edit", `{"file_path":"a.go","old_string":"x","new_string":"y"}`, []string{"a.go"}, 1},
+		{"write", "Write", `{"file_path":"b.go","content":"package b"}`, []string{"b.go"}, 1},
+		{"multiedit single file", "MultiEdit", `{"file_path":"c.go","edits":[{"old_string":"a","new_string":"b"},{"old_string":"c","new_string":""}]}`, []string{"c.go"}, 2},
+		{"multiedit per-edit files", "MultiEdit", `{"file_path":"c.go","edits":[{"old_string":"a","new_string":"b"},{"file_path":"d.go","old_string":"c","new_string":"d"},{"file_path":"c.go","old_string":"e","new_string":"f"}]}`, []string{"c.go", "d.go"}, 3},
+		{"multiedit without edits", "MultiEdit", `{"file_path":"e.go","edits":[]}`, []string{"e.go"}, 1},
+		{"notebook", "NotebookEdit", `{"notebook_path":"nb.ipynb","cell_id":"c1","new_source":"print(1)"}`, []string{"nb.ipynb"}, 1},
+		{"bash ignored", "Bash", `{"command":"ls"}`, nil, 0},
+		{"malformed", "MultiEdit", `{"edits":"nope"}`, nil, 0},
+		{"empty input", "Edit", ``, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ParseToolPayload(tt.tool, json.RawMessage(tt.input))
+			if got := p.Files(); !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("Files() = %v, want %v", got, tt.wantFiles)
+			}
+			if len(p.Edits) != tt.wantEdits {
+				t.Errorf("len(Edits) = %d, want %d", len(p.Edits), tt.wantEdits)
+			}
+		})
+	}
+
+	nb := ParseToolPayload("NotebookEdit", json.RawMessage(`{"notebook_path":"nb.ipynb","cell_id":"c1","new_source":"x"}`))
+	if e := nb.Edits[0]; e.Kind != FileEditKindNotebook || e.CellID != "c1" || e.NewString != "x" {
+		t.Errorf("notebook edit = %+v", e)
+	}
+}
+
+func TestBuildPostToolUseContext_MultiFileEnvironment(t *testing.T) {
+	ev := &cchooks.PostToolUseEvent{
+		ToolName:  "MultiEdit",
+		ToolInput: json.RawMessage(`{"file_path":"a.py","edits":[{"old_string":"1","new_string":"2"},{"file_path":"b.py","old_string":"3","new_string":"4"}]}`),
+	}
+	ctx := BuildPostToolUseContext(context.Background(), ev)
+	env := NewClaudeCodeEnvironmentProvider().GetEnvironment("PostToolUse", ctx)
+	if env["FILES_CHANGED"] != "a.py b.py" || env["TOOL_OUTPUT_FILE"] != "a.py" {
+		t.Fatalf("env = %v", env)
+	}
+
+	files, _ := ctx["files_changed"].([]string)
+	envs := PerFileEnvironments(env, files)
+	if len(envs) != 2 {
+		t.Fatalf("PerFileEnvironments returned %d envs, want 2", len(envs))
+	}
+	for i, want := range []string{"a.py", "b.py"} {
+		if envs[i]["TOOL_OUTPUT_FILE"] != want || envs[i]["TOOL_FILE"] != want || envs[i]["FILES_CHANGED"] != "a.py b.py" {
+			t.Errorf("env[%d] = %v", i, envs[i])
+		}
+	}
+	if env["TOOL_OUTPUT_FILE"] != "a.py" {
+		t.Error("PerFileEnvironments must not mutate the input env")
+	}
+
+	if single := PerFileEnvironments(env, []string{"a.py"}); len(single) != 1 {
+		t.Errorf("single file should yield one env, got %d", len(single))
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !windows
+
+package core
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// cancelWaitDelay is how long a cancelled command may take to exit before
+// it is killed outright
+const cancelWaitDelay = 3 * time.Second
+
+// ConfigureCancel makes cancelling cmd's context stop everything it
+// started. The command runs in its own process group, which receives
+// SIGTERM on cancel, so children of a shell job stop with it.
+func ConfigureCancel(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = cancelWaitDelay
+}
+
+// setRawCommandLine is a no-op off Windows, where arguments reach the
+// program as given
+func setRawCommandLine(_ *exec.Cmd, _ string, _ []string) {}
+
+// exceededCPULimit reports whether a job was stopped by its CPU time limit:
+// killed by SIGXCPU itself, or a shell reporting a child that was
+func exceededCPULimit(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() && ws.Signal() == syscall.SIGXCPU {
+		return true
+	}
+	return exitErr.ExitCode() == 128+int(syscall.SIGXCPU)
+}
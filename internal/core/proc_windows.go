@@ -0,0 +1,34 @@
+//go:build windows
+
+package core
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// cancelWaitDelay is how long a cancelled command may take to exit before
+// its pipes are closed
+const cancelWaitDelay = 3 * time.Second
+
+// ConfigureCancel bounds how long a cancelled cmd can keep running. Windows
+// has no process groups to signal, so only the command itself is killed.
+func ConfigureCancel(cmd *exec.Cmd) {
+	cmd.WaitDelay = cancelWaitDelay
+}
+
+// setRawCommandLine passes cmd.exe its arguments verbatim. cmd does not
+// follow the quoting Go applies to arguments, so "/s /c" takes the script
+// wrapped in one pair of quotes, which it strips before running it.
+func setRawCommandLine(cmd *exec.Cmd, name string, args []string) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	last := len(args) - 1
+	cmd.SysProcAttr.CmdLine = name + " " + strings.Join(args[:last], " ") + ` "` + args[last] + `"`
+}
+
+// exceededCPULimit is always false on Windows, which has no CPU time limit
+func exceededCPULimit(_ error) bool { return false }
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// EventJSONEnv names a private temp file holding the event's payload,
+	// set for jobs of events cchooks does not parse
+	EventJSONEnv = "BT_EVENT_JSON"
+	// EventFieldEnvPrefix starts the variables that carry a raw event's
+	// top-level fields, e.g. BT_EVENT_TRIGGER for PreCompact's "trigger"
+	EventFieldEnvPrefix = "BT_EVENT_"
+)
+
+// RawEvent is a hook payload parsed generically, for events the cchooks
+// library has no type for (PreCompact, SessionEnd, ...)
+type RawEvent struct {
+	// Name is hook_event_name
+	Name      string
+	SessionID string
+	// ToolName is set for tool events only
+	ToolName string
+	// Fields holds every top-level field of the payload
+	Fields map[string]any
+	// JSON is the payload as received
+	JSON string
+}
+
+// ParseRawEvent parses a hook payload without knowing its event
+func ParseRawEvent(data string) (*RawEvent, error) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse event JSON: %w", err)
+	}
+	ev := &RawEvent{Fields: fields, JSON: data}
+	ev.Name, _ = fields["hook_event_name"].(string)
+	ev.SessionID, _ = fields["session_id"].(string)
+	ev.ToolName, _ = fields["tool_name"].(string)
+	return ev, nil
+}
+
+// ReadRawEvent reads one hook payload from r, normally stdin
+func ReadRawEvent(r io.Reader) (*RawEvent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event: %w", err)
+	}
+	return ParseRawEvent(string(data))
+}
+
+// RunRawEvent reads one payload from stdin and passes it to handle. This
+// is how custom jobs run for events the cchooks runner rejects. A payload
+// that can't be read or parsed is ignored, so the agent carries on.
+func RunRawEvent(ctx context.Context, stdin io.Reader, handle func(context.Context, *RawEvent)) {
+	ev, err := ReadRawEvent(stdin)
+	if err != nil {
+		return
+	}
+	handle(ctx, ev)
+}
+
+// Context returns the loosely typed context EnvironmentProvider takes
+func (e *RawEvent) Context() map[string]any {
+	ctxData := map[string]any{"session_id": e.SessionID}
+	if e.ToolName != "" {
+		ctxData["tool_name"] = e.ToolName
+	}
+	// UserPromptSubmit sends "prompt"; older payloads used "user_prompt"
+	for _, key := range []string{"prompt", "user_prompt"} {
+		if v, ok := e.Fields[key].(string); ok && v != "" {
+			ctxData["user_prompt"] = v
+			break
+		}
+	}
+	if cwd, ok := e.Fields["cwd"].(string); ok && cwd != "" {
+		ctxData["project_root"] = cwd
+	} else if wd, err := os.Getwd(); err == nil {
+		ctxData["project_root"] = wd
+	}
+	return ctxData
+}
+
+// Environment returns a BT_EVENT_<FIELD> variable for every top-level
+// field. Field names become upper snake case (custom_instructions is
+// BT_EVENT_CUSTOM_INSTRUCTIONS, stopHookActive is BT_EVENT_STOP_HOOK_ACTIVE);
+// strings, numbers and booleans are passed as is and objects and arrays as
+// JSON. A field that would shadow BT_EVENT_JSON is left out.
+func (e *RawEvent) Environment() map[string]string {
+	env := make(map[string]string, len(e.Fields))
+	for key, value := range e.Fields {
+		name := EventFieldEnvPrefix + envName(key)
+		if name == EventJSONEnv || name == EventFieldEnvPrefix {
+			continue
+		}
+		env[name] = envValue(value)
+	}
+	return env
+}
+
+// WriteFile saves the payload to a private temp file for EventJSONEnv.
+// The returned func removes it.
+func (e *RawEvent) WriteFile() (string, func(), error) {
+	f, err := os.CreateTemp("", "bt-event-*.json")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to save event payload: %w", err)
+	}
+	path := f.Name()
+	cleanup := func() { _ = os.Remove(path) }
+	_, werr := f.WriteString(e.JSON)
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("failed to save event payload: %w", werr)
+	}
+	return path, cleanup, nil
+}
+
+// envName turns a JSON field name into an environment variable suffix
+func envName(key string) string {
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range key {
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			b.WriteByte('_')
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteByte('_')
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+func envValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRawEvent_Environment(t *testing.T) {
+	payload := `{"hook_event_name":"PreCompact","session_id":"s-1","cwd":"/repo","trigger":"manual",` +
+		`"custom_instructions":"keep the plan","stopHookActive":false,"tokens":1200,"files":["a.go","b.go"],"json":"shadow"}`
+
+	var ev *RawEvent
+	RunRawEvent(context.Background(), strings.NewReader(payload), func(_ context.Context, e *RawEvent) { ev = e })
+	if ev == nil || ev.Name != "PreCompact" || ev.SessionID != "s-1" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	env := ev.Environment()
+	want := map[string]string{
+		"BT_EVENT_HOOK_EVENT_NAME":     "PreCompact",
+		"BT_EVENT_TRIGGER":             "manual",
+		"BT_EVENT_CUSTOM_INSTRUCTIONS": "keep the plan",
+		"BT_EVENT_STOP_HOOK_ACTIVE":    "false",
+		"BT_EVENT_TOKENS":              "1200",
+		"BT_EVENT_FILES":               `["a.go","b.go"]`,
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+	if _, ok := env[EventJSONEnv]; ok {
+		t.Errorf("a payload field must not shadow %s", EventJSONEnv)
+	}
+	if ev.Context()["project_root"] != "/repo" {
+		t.Errorf("project root should come from cwd, got %v", ev.Context()["project_root"])
+	}
+
+	path, cleanup, err := ev.WriteFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != payload {
+		t.Errorf("event file holds %q, %v", data, err)
+	}
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("cleanup should remove the event file")
+	}
+
+	called := false
+	RunRawEvent(context.Background(), strings.NewReader("not json"), func(context.Context, *RawEvent) { called = true })
+	if called {
+		t.Error("an unparseable payload should be ignored")
+	}
+}
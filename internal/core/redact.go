@@ -0,0 +1,234 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// RedactedValue replaces secrets removed by RedactText
+const RedactedValue = "[REDACTED]"
+
+// redactionBuiltins are the named rule sets the redaction config can turn
+// on. Each pattern's first capture group is kept as context and the rest
+// of the match is replaced.
+var redactionBuiltins = map[string][]*regexp.Regexp{
+	"private-keys": {
+		regexp.MustCompile(`(-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----)[\s\S]*?-----END (?:[A-Z]+ )?PRIVATE KEY-----`),
+	},
+	// "token": "value" in JSON and YAML, and TOKEN=value in env files
+	"credentials": {
+		regexp.MustCompile(`(?im)((?:^|[\s"'{,])(?:[A-Za-z0-9_.-]*(?:token|secret|passw(?:or)?d|api[_-]?key|credential)[A-Za-z0-9_.-]*|[A-Za-z0-9_.-]*auth(?:orization)?)["']?\s*[:=]\s*)(?:(?:bearer|basic)\s+)?(?:"[^"\n]*"|'[^'\n]*'|[^\s,}'"]+)`),
+	},
+	"auth-headers": {
+		regexp.MustCompile(`(?i)((?:bearer|basic)\s+)[A-Za-z0-9._~+/=-]{8,}`),
+	},
+	"url-credentials": {
+		regexp.MustCompile(`([a-z][a-z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`),
+	},
+	"aws-keys": {
+		regexp.MustCompile(`()\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		regexp.MustCompile(`(?i)((?:aws_secret_access_key|aws_secret_key)["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`),
+	},
+	"github-tokens": {
+		regexp.MustCompile(`()\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	},
+	"slack-tokens": {
+		regexp.MustCompile(`()\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+	},
+	"stripe-keys": {
+		regexp.MustCompile(`()\b(?:sk|rk)_(?:live|test)_[A-Za-z0-9]{16,}\b`),
+	},
+	"openai-keys": {
+		regexp.MustCompile(`()\bsk-[A-Za-z0-9_-]{20,}`),
+	},
+	"google-api-keys": {
+		regexp.MustCompile(`()\bAIza[0-9A-Za-z_-]{35}\b`),
+	},
+	"jwts": {
+		regexp.MustCompile(`()\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`),
+	},
+	"emails": {
+		regexp.MustCompile(`()\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+	},
+}
+
+// secretBuiltins make up RedactText and the "secrets" built-in, in the
+// order they are applied
+var secretBuiltins = []string{
+	"private-keys", "credentials", "auth-headers", "url-credentials", "aws-keys",
+	"github-tokens", "slack-tokens", "stripe-keys", "openai-keys", "google-api-keys",
+}
+
+// redactPatterns match credentials in text
+var redactPatterns = builtinPatterns(secretBuiltins)
+
+func builtinPatterns(names []string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, name := range names {
+		patterns = append(patterns, redactionBuiltins[name]...)
+	}
+	return patterns
+}
+
+// RedactionBuiltins lists the names the redaction config accepts
+func RedactionBuiltins() []string {
+	names := []string{"secrets"}
+	for name := range redactionBuiltins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedactText replaces credentials in s with RedactedValue and the user's
+// home directory with "~". It is best effort: it catches common key names
+// and token formats, not every secret.
+func RedactText(s string) string {
+	s = redactWith(redactPatterns, s)
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
+
+func redactWith(patterns []*regexp.Regexp, s string) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			keep := ""
+			if re.NumSubexp() > 0 {
+				keep = re.FindStringSubmatch(match)[1]
+			}
+			// Keep quotes so redacted JSON and YAML still parse
+			if value := match[len(keep):]; len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				return keep + value[:1] + RedactedValue + value[:1]
+			}
+			return keep + RedactedValue
+		})
+	}
+	return s
+}
+
+// Redactor scrubs the configured patterns from log output. A nil Redactor
+// leaves everything as is.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles rules, returning nil when there are none. Unknown
+// built-ins and invalid patterns are reported in the error, but the valid
+// rules are still returned so one typo doesn't switch redaction off.
+func NewRedactor(rules config.RedactionRules) (*Redactor, error) {
+	r := &Redactor{}
+	var problems []string
+	seen := map[string]bool{}
+	for _, name := range rules.Builtins {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		switch patterns, ok := redactionBuiltins[name]; {
+		case name == "secrets":
+			r.patterns = append(r.patterns, redactPatterns...)
+		case ok:
+			r.patterns = append(r.patterns, patterns...)
+		default:
+			problems = append(problems, fmt.Sprintf("unknown built-in '%s'", name))
+		}
+	}
+	for _, p := range rules.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid pattern %q: %v", p, err))
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	var err error
+	if len(problems) > 0 {
+		err = fmt.Errorf("redaction: %s\n  Suggestion: Built-ins are %s", strings.Join(problems, "; "), strings.Join(RedactionBuiltins(), ", "))
+	}
+	if len(r.patterns) == 0 {
+		return nil, err
+	}
+	return r, err
+}
+
+// Text scrubs s
+func (r *Redactor) Text(s string) string {
+	if r == nil {
+		return s
+	}
+	return redactWith(r.patterns, s)
+}
+
+// Value scrubs every string in v, descending into maps and slices. Maps
+// and slices are copied, never modified.
+func (r *Redactor) Value(v interface{}) interface{} {
+	if r == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case string:
+		return r.Text(v)
+	case map[string]interface{}:
+		return r.Map(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, x := range v {
+			out[i] = r.Value(x)
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, x := range v {
+			out[i] = r.Text(x)
+		}
+		return out
+	case json.RawMessage:
+		return json.RawMessage(r.Text(string(v)))
+	}
+	return v
+}
+
+// Map scrubs the values of m into a copy
+func (r *Redactor) Map(m map[string]interface{}) map[string]interface{} {
+	if r == nil || m == nil {
+		return m
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = r.Value(v)
+	}
+	return out
+}
+
+// Writer wraps w so every write is scrubbed first. Each write should hold
+// whole lines, as log.Logger's do, or a secret split across writes slips
+// through.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+	return &redactingWriter{r: r, w: w}
+}
+
+type redactingWriter struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, rw.r.Text(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/klauern/blues-traveler/internal/config"
 )
@@ -147,6 +149,34 @@ func CreateHook(key string) (Hook, error) {
 	return globalRegistry.Create(key)
 }
 
+// CreateHookWithRunner creates a hook from the global registry whose runner is
+// built by runnerFactory instead of the global context's factory. This lets
+// callers capture a hook's handlers and drive them with a synthetic event.
+func CreateHookWithRunner(key string, runnerFactory RunnerFactory) (Hook, error) {
+	globalRegistry.mu.RLock()
+	factory, exists := globalRegistry.factories[key]
+	base := globalRegistry.context
+	globalRegistry.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("hook with key '%s' not found", key)
+	}
+
+	ctx := DefaultHookContext()
+	if base != nil {
+		copied := *base
+		ctx = &copied
+	}
+	ctx.RunnerFactory = runnerFactory
+	return factory(ctx), nil
+}
+
+// RegisterHook adds one hook to the global registry, failing when the key
+// is taken
+func RegisterHook(key string, factory HookFactory) error {
+	return globalRegistry.Register(key, factory)
+}
+
 // GetHookKeys returns all registered hook keys from the global registry
 func GetHookKeys() []string {
 	return globalRegistry.Keys()
@@ -173,6 +203,70 @@ func SetGlobalLoggingConfig(enabled bool, logDir string, format string) {
 	}
 }
 
+// SetGlobalLatencyThreshold enables hook timing; zero turns it off
+func SetGlobalLatencyThreshold(threshold time.Duration) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.LatencyThreshold = threshold
+	}
+}
+
+// SetGlobalExperiments sets the message experiments hooks take part in
+func SetGlobalExperiments(experiments []config.Experiment) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.Experiments = experiments
+	}
+}
+
+// SetGlobalMetricsEnabled turns recording of hook runs on or off
+func SetGlobalMetricsEnabled(enabled bool) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.MetricsEnabled = enabled
+	}
+}
+
+// SetGlobalTerseOutput turns terse hook output on or off
+func SetGlobalTerseOutput(enabled bool) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.TerseOutput = enabled
+	}
+}
+
+// SetGlobalRedactor sets what hooks scrub from their logs and records
+func SetGlobalRedactor(r *Redactor) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.Redactor = r
+	}
+}
+
+// SetGlobalLoggingDefaults applies the logging section of the project config
+func SetGlobalLoggingDefaults(defaults config.LoggingDefaults) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.LogLevel = defaults.Level
+		globalRegistry.context.QuietSuccess = defaults.QuietSuccess
+	}
+}
+
+// SetGlobalLogWriter routes structured hook logging through w (e.g. a rotating writer)
+func SetGlobalLogWriter(w io.Writer) {
+	globalRegistry.mu.Lock()
+	defer globalRegistry.mu.Unlock()
+	if globalRegistry.context != nil {
+		globalRegistry.context.LogWriter = w
+	}
+}
+
 // (removed) GetGlobalRegistry unused; keep internal-only access.
 
 // RegisterBuiltinHooks can be called by the hooks package to register all built-in hooks
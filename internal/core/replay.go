@@ -0,0 +1,209 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/brads3290/cchooks"
+)
+
+// replayExit is the panic value cchooks passes through untouched; the replay
+// runner raises it instead of exiting the process
+const replayExit = "exit"
+
+// replayMu serializes replays, which swap the process-wide stdio
+var replayMu sync.Mutex
+
+// ReplayEvent is one captured hook input
+type ReplayEvent struct {
+	// Source locates the event, as "<file>:<line>"
+	Source  string
+	Event   string
+	Tool    string
+	Payload json.RawMessage
+}
+
+// ReplayResult is what a hook produced for one replayed event: the output
+// Claude Code would have read and the exit code it would have seen
+type ReplayResult struct {
+	Event    ReplayEvent
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	// Err is the error Run returned, which makes 'hooks run' exit 1
+	Err error
+}
+
+// LoadReplayEvents reads captured payloads from path. It accepts a single
+// payload as Claude Code sends it, JSONL of payloads, and hook logs written
+// with --log (jsonl or pretty), whose raw_event entries hold the payload.
+// Other log entries and non-JSON lines are skipped.
+func LoadReplayEvents(path string) ([]ReplayEvent, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path named by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file: %w", err)
+	}
+	events, err := ParseReplayEvents(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no hook events found in %s\n  Suggestion: Capture events with 'hooks run <key> --log' (raw_event entries need log level debug) or pass a payload file", path)
+	}
+	return events, nil
+}
+
+// ParseReplayEvents extracts captured payloads from data; name prefixes each
+// event's Source
+func ParseReplayEvents(data []byte, name string) ([]ReplayEvent, error) {
+	var events []ReplayEvent
+	line := 1
+	for pos := 0; pos < len(data); {
+		switch c := data[pos]; {
+		case c == '\n':
+			line++
+			pos++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			pos++
+			continue
+		case c != '{':
+			// Plain text, such as the debug hook's own log lines
+			next := bytes.IndexByte(data[pos:], '\n')
+			if next < 0 {
+				return events, nil
+			}
+			pos += next
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data[pos:]))
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return events, fmt.Errorf("%s:%d: invalid JSON: %w", name, line, err)
+		}
+		if ev, ok := replayEventFrom(raw); ok {
+			ev.Source = fmt.Sprintf("%s:%d", name, line)
+			events = append(events, ev)
+		}
+		end := pos + int(dec.InputOffset())
+		line += bytes.Count(data[pos:end], []byte("\n"))
+		pos = end
+	}
+	return events, nil
+}
+
+// replayEventFrom recognizes a payload or a raw_event log entry
+func replayEventFrom(raw json.RawMessage) (ReplayEvent, bool) {
+	var probe struct {
+		HookEventName string          `json:"hook_event_name"`
+		ToolName      string          `json:"tool_name"`
+		Event         string          `json:"event"`
+		Details       json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return ReplayEvent{}, false
+	}
+	if probe.HookEventName != "" {
+		return ReplayEvent{Event: probe.HookEventName, Tool: probe.ToolName, Payload: raw}, true
+	}
+	if probe.Event == "raw_event" && len(probe.Details) > 0 {
+		return replayEventFrom(probe.Details)
+	}
+	return ReplayEvent{}, false
+}
+
+// ReplayHook runs a fresh instance of the hook registered as key with the
+// event's payload on stdin and captures what it writes, as one 'hooks run'
+// process would. The hook's runner exits by unwinding instead of ending the
+// process, so events can be replayed back to back.
+func ReplayHook(ctx context.Context, key string, ev ReplayEvent) (*ReplayResult, error) {
+	result := &ReplayResult{Event: ev}
+	hook, err := CreateHookWithRunner(key, func(
+		pre func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+		post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+		raw func(context.Context, string) *cchooks.RawResponse,
+	) Runner {
+		runner := DefaultRunnerFactory(pre, post, raw).(*cchooks.Runner)
+		runner.ExitFn = func(code int) {
+			result.ExitCode = code
+			panic(replayExit)
+		}
+		return runner
+	})
+	if err != nil {
+		return nil, err
+	}
+	hook.SetRunContext(ctx)
+
+	stdout, stderr, err := withReplayStdio(ev.Payload, func() {
+		defer func() {
+			if p := recover(); p != nil && p != replayExit {
+				panic(p)
+			}
+		}()
+		result.Err = hook.Run()
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Stdout, result.Stderr = stdout, stderr
+	return result, nil
+}
+
+// withReplayStdio runs fn with payload on os.Stdin and os.Stdout and
+// os.Stderr captured
+func withReplayStdio(payload []byte, fn func()) (string, string, error) {
+	replayMu.Lock()
+	defer replayMu.Unlock()
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create replay stdin: %w", err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		_ = inR.Close()
+		_ = inW.Close()
+		return "", "", fmt.Errorf("failed to create replay stdout: %w", err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		_ = inR.Close()
+		_ = inW.Close()
+		_ = outR.Close()
+		_ = outW.Close()
+		return "", "", fmt.Errorf("failed to create replay stderr: %w", err)
+	}
+
+	go func() {
+		_, _ = inW.Write(payload)
+		_ = inW.Close()
+	}()
+	var outBuf, errBuf strings.Builder
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); _, _ = io.Copy(&outBuf, outR) }()
+	go func() { defer wg.Done(); _, _ = io.Copy(&errBuf, errR) }()
+
+	origIn, origOut, origErr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = inR, outW, errW
+	func() {
+		defer func() { os.Stdin, os.Stdout, os.Stderr = origIn, origOut, origErr }()
+		fn()
+	}()
+
+	_ = outW.Close()
+	_ = errW.Close()
+	wg.Wait()
+	_ = inR.Close()
+	_ = outR.Close()
+	_ = errR.Close()
+	return outBuf.String(), errBuf.String(), nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseReplayEvents(t *testing.T) {
+	data := strings.Join([]string{
+		`2026/10/16 10:00:00 PRE-TOOL: Bash`,
+		`{"timestamp":"t","hook_key":"debug","event":"raw_event","tool_name":"Bash","details":{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}}`,
+		`{"timestamp":"t","hook_key":"debug","event":"pre_tool_use","tool_name":"Bash"}`,
+		`{`,
+		`  "timestamp": "t",`,
+		`  "event": "raw_event",`,
+		`  "details": {`,
+		`    "hook_event_name": "Stop",`,
+		`    "stop_hook_active": false`,
+		`  }`,
+		`}`,
+		`{"hook_event_name":"PostToolUse","tool_name":"Write","tool_input":{"file_path":"a.go"}}`,
+	}, "\n")
+
+	events, err := ParseReplayEvents([]byte(data), "debug.log")
+	if err != nil {
+		t.Fatalf("ParseReplayEvents: %v", err)
+	}
+	want := []struct{ source, event, tool string }{
+		{"debug.log:2", "PreToolUse", "Bash"},
+		{"debug.log:4", "Stop", ""},
+		{"debug.log:12", "PostToolUse", "Write"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		ev := events[i]
+		if ev.Source != w.source || ev.Event != w.event || ev.Tool != w.tool {
+			t.Errorf("event %d = %s %s %s, want %s %s %s", i, ev.Source, ev.Event, ev.Tool, w.source, w.event, w.tool)
+		}
+	}
+	if !strings.Contains(string(events[0].Payload), `"command":"ls"`) {
+		t.Errorf("expected the raw_event details as payload, got %s", events[0].Payload)
+	}
+}
+
+func TestLoadReplayEvents_Errors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.log")
+	if err := os.WriteFile(empty, []byte(`{"event":"pre_tool_use"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplayEvents(empty); err == nil || !strings.Contains(err.Error(), "no hook events") {
+		t.Fatalf("expected a no-events error, got %v", err)
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte("\n{\"hook_event_name\": "), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplayEvents(broken); err == nil || !strings.Contains(err.Error(), "broken.json:2") {
+		t.Fatalf("expected an error naming the line, got %v", err)
+	}
+}
@@ -278,3 +278,30 @@ func (r *DualMessagePostToolResponse) GetUserMessage() string {
 func (r *DualMessagePostToolResponse) GetAgentMessage() string {
 	return r.agentMessage
 }
+
+// ResponseSummary is a flattened view of a Pre/PostToolUse response, used
+// where the decision needs to be inspected outside the cchooks runner.
+type ResponseSummary struct {
+	Decision     string
+	UserMessage  string
+	AgentMessage string
+}
+
+// SummarizeResponse extracts the decision and messages from any response
+// produced by the helpers in this file or by cchooks directly. An empty
+// decision means the hook approved/allowed without an explicit decision.
+func SummarizeResponse(resp any) ResponseSummary {
+	switch r := resp.(type) {
+	case *AskPreToolResponse:
+		return ResponseSummary{Decision: PreToolUseAsk, UserMessage: r.GetUserMessage(), AgentMessage: r.GetAgentMessage()}
+	case *DualMessagePreToolResponse:
+		return ResponseSummary{Decision: r.Decision, UserMessage: r.userMessage, AgentMessage: r.agentMessage}
+	case *DualMessagePostToolResponse:
+		return ResponseSummary{Decision: r.Decision, UserMessage: r.userMessage, AgentMessage: r.agentMessage}
+	case *cchooks.PreToolUseResponse:
+		return ResponseSummary{Decision: r.Decision, UserMessage: r.Reason, AgentMessage: r.Reason}
+	case *cchooks.PostToolUseResponse:
+		return ResponseSummary{Decision: r.Decision, UserMessage: r.Reason, AgentMessage: r.Reason}
+	}
+	return ResponseSummary{}
+}
@@ -503,3 +503,32 @@ func TestAskInterfaceCompliance(_ *testing.T) {
 	var _ cchooks.PreToolUseResponseInterface = AskWithMessages("test")
 	var _ cchooks.PostToolUseResponseInterface = AskPostWithMessages("test")
 }
+
+func TestSummarizeResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		resp      any
+		wantDec   string
+		wantUser  string
+		wantAgent string
+	}{
+		{"plain approve", cchooks.Approve(), "approve", "", ""},
+		{"plain block", cchooks.Block("bad"), "block", "bad", "bad"},
+		{"plain allow", cchooks.Allow(), "", "", ""},
+		{"dual block", BlockWithMessages("user", "agent"), "block", "user", "agent"},
+		{"dual ask", AskWithMessages("confirm?", "why"), PreToolUseAsk, "confirm?", "why"},
+		{"post ask", AskPostWithMessages("review"), PostToolUseAsk, "review", "review"},
+		{"cursor ask", &AskPreToolResponse{DualMessagePreToolResponse: &DualMessagePreToolResponse{
+			PreToolUseResponse: cchooks.Approve(), userMessage: "u", agentMessage: "a",
+		}}, PreToolUseAsk, "u", "a"},
+		{"nil", nil, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SummarizeResponse(tt.resp)
+			if got.Decision != tt.wantDec || got.UserMessage != tt.wantUser || got.AgentMessage != tt.wantAgent {
+				t.Errorf("SummarizeResponse() = %+v, want {%q %q %q}", got, tt.wantDec, tt.wantUser, tt.wantAgent)
+			}
+		})
+	}
+}
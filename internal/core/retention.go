@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// retentionLockWait matches how long appends wait for the same locks
+const retentionLockWait = 2 * time.Second
+
+// RetentionTarget is the directory holding one category of local data.
+// Its top-level files and directories (day files, trash entries, session
+// directories) are the units retention removes.
+type RetentionTarget struct {
+	Category string
+	Dir      string
+	// keep names files that describe the directory rather than hold data
+	keep []string
+	// lock is held while pruning so appends don't race removal
+	lock string
+	// afterPrune brings bookkeeping in line with what was removed
+	afterPrune func(dir string) error
+}
+
+// RetentionResult is what pruning one target removed
+type RetentionResult struct {
+	Category   string
+	Dir        string
+	Removed    int
+	FreedBytes int64
+}
+
+// RetentionTargets returns the directories of every retention category,
+// named as in config.RetentionCategories
+func RetentionTargets() ([]RetentionTarget, error) {
+	audit, err := AuditDir()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	trash, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := MetricsDir()
+	if err != nil {
+		return nil, err
+	}
+	state, err := StateRoot()
+	if err != nil {
+		return nil, err
+	}
+	return []RetentionTarget{
+		{Category: "payloads", Dir: audit, keep: []string{auditIndexFile}, lock: auditLockName, afterPrune: pruneAuditIndex},
+		{Category: "runHistory", Dir: cache},
+		{Category: "artifacts", Dir: trash},
+		{Category: "metrics", Dir: metrics, lock: metricsLockName},
+		{Category: "state", Dir: state, keep: []string{housekeepingFile}},
+	}, nil
+}
+
+// retentionEntry is one removable unit of a target
+type retentionEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// PruneRetention removes entries of t not written to within maxAge, then the
+// oldest entries until the rest take at most maxBytes. A non-positive limit
+// is not enforced.
+func PruneRetention(t RetentionTarget, maxAge time.Duration, maxBytes int64, now time.Time) (RetentionResult, error) {
+	res := RetentionResult{Category: t.Category, Dir: t.Dir}
+	if t.lock != "" {
+		release, err := AcquireNamedLock(t.lock, retentionLockWait)
+		if err != nil {
+			return res, err
+		}
+		defer release()
+	}
+	entries, total, err := retentionEntries(t)
+	if err != nil || len(entries) == 0 {
+		return res, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, e := range entries {
+		expired := maxAge > 0 && now.Sub(e.modTime) > maxAge
+		oversized := maxBytes > 0 && total > maxBytes
+		if !expired && !oversized {
+			continue
+		}
+		if err := os.RemoveAll(e.path); err != nil {
+			return res, fmt.Errorf("failed to remove %s: %w", e.path, err)
+		}
+		res.Removed++
+		res.FreedBytes += e.size
+		total -= e.size
+	}
+	if res.Removed > 0 && t.afterPrune != nil {
+		return res, t.afterPrune(t.Dir)
+	}
+	return res, nil
+}
+
+// ClearRetentionTarget removes the whole directory of t
+func ClearRetentionTarget(t RetentionTarget) (RetentionResult, error) {
+	res := RetentionResult{Category: t.Category, Dir: t.Dir}
+	entries, total, err := retentionEntries(t)
+	if err != nil {
+		return res, err
+	}
+	if err := os.RemoveAll(t.Dir); err != nil {
+		return res, fmt.Errorf("failed to remove %s: %w", t.Dir, err)
+	}
+	res.Removed = len(entries)
+	res.FreedBytes = total
+	return res, nil
+}
+
+// retentionEntries lists the removable entries of t with their total size.
+// A directory's time is the newest modification inside it, so a session
+// still being written to is not mistaken for an old one.
+func retentionEntries(t RetentionTarget) ([]retentionEntry, int64, error) {
+	dirEntries, err := os.ReadDir(t.Dir)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", t.Dir, err)
+	}
+	var entries []retentionEntry
+	var total int64
+	for _, d := range dirEntries {
+		name := d.Name()
+		if strings.HasPrefix(name, ".") || slices.Contains(t.keep, name) {
+			continue
+		}
+		e := retentionEntry{path: filepath.Join(t.Dir, name)}
+		_ = filepath.WalkDir(e.path, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				e.size += info.Size()
+			}
+			if info.ModTime().After(e.modTime) {
+				e.modTime = info.ModTime()
+			}
+			return nil
+		})
+		entries = append(entries, e)
+		total += e.size
+	}
+	return entries, total, nil
+}
+
+// pruneAuditIndex drops index entries whose day files were removed
+func pruneAuditIndex(dir string) error {
+	index, err := readAuditIndex(dir)
+	if err != nil {
+		return err
+	}
+	for date, day := range index.Days {
+		if _, err := os.Stat(filepath.Join(dir, filepath.Base(day.File))); os.IsNotExist(err) {
+			delete(index.Days, date)
+		}
+	}
+	return writeAuditIndex(dir, index)
+}
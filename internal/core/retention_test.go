@@ -0,0 +1,99 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeRetentionFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneRetention_AgeThenSize(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeRetentionFile(t, filepath.Join(dir, "old.jsonl"), 100, now.Add(-40*24*time.Hour))
+	writeRetentionFile(t, filepath.Join(dir, "older-recent.jsonl"), 600, now.Add(-3*time.Hour))
+	writeRetentionFile(t, filepath.Join(dir, "newest.jsonl"), 600, now.Add(-time.Hour))
+	// A session directory is as recent as its newest file
+	writeRetentionFile(t, filepath.Join(dir, "session", "key"), 10, now.Add(-time.Minute))
+	if err := os.Chtimes(filepath.Join(dir, "session"), now.Add(-50*24*time.Hour), now.Add(-50*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	writeRetentionFile(t, filepath.Join(dir, ".gitignore"), 2, now.Add(-50*24*time.Hour))
+	writeRetentionFile(t, filepath.Join(dir, "index.json"), 2, now.Add(-50*24*time.Hour))
+
+	target := RetentionTarget{Category: "payloads", Dir: dir, keep: []string{"index.json"}}
+	res, err := PruneRetention(target, 30*24*time.Hour, 1000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Removed != 2 || res.FreedBytes != 700 {
+		t.Fatalf("expected old.jsonl and older-recent.jsonl removed, got %+v", res)
+	}
+	for name, want := range map[string]bool{"old.jsonl": false, "older-recent.jsonl": false, "newest.jsonl": true, "session": true, ".gitignore": true, "index.json": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s: exists=%v, want %v", name, exists, want)
+		}
+	}
+
+	if res, err := PruneRetention(target, 0, 0, now); err != nil || res.Removed != 0 {
+		t.Errorf("no limits should remove nothing, got %+v, %v", res, err)
+	}
+}
+
+func TestPruneRetention_DropsAuditIndexDays(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_AUDIT_DIR", dir)
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC()
+	for _, ts := range []time.Time{old, time.Now().UTC()} {
+		if err := AppendAuditRecord(AuditRecord{Time: ts, Event: "PreToolUse", Tool: "Bash", Decision: AuditDecisionRequested}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldFile := filepath.Join(dir, "audit-"+old.Format(auditDayLayout)+".jsonl")
+	if err := os.Chtimes(oldFile, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := RetentionTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := PruneRetention(targets[0], 30*24*time.Hour, 0, time.Now()); err != nil || res.Removed != 1 {
+		t.Fatalf("expected the old day file removed, got %+v, %v", res, err)
+	}
+	index, err := LoadAuditIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Days) != 1 {
+		t.Errorf("expected the removed day dropped from the index, got %+v", index.Days)
+	}
+}
+
+func TestClearRetentionTarget(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "metrics")
+	writeRetentionFile(t, filepath.Join(dir, "metrics-2026-01-01.jsonl"), 50, time.Now())
+	res, err := ClearRetentionTarget(RetentionTarget{Category: "metrics", Dir: dir})
+	if err != nil || res.Removed != 1 || res.FreedBytes != 50 {
+		t.Fatalf("unexpected result %+v, %v", res, err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s removed", dir)
+	}
+}
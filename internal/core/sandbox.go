@@ -0,0 +1,157 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// sandboxSetupMarker starts the line a sandbox prints when one of its
+// limits or mounts can't be applied
+const sandboxSetupMarker = "blues-traveler sandbox: "
+
+// sandboxSetupExit is the status a sandbox that couldn't be set up exits with
+const sandboxSetupExit = 125
+
+// SandboxEnv keeps the variables of environ that allow names; a name ending
+// in * keeps every variable with that prefix
+func SandboxEnv(environ, allow []string) []string {
+	var kept []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, a := range allow {
+			a = strings.TrimSpace(a)
+			if prefix, ok := strings.CutSuffix(a, "*"); (ok && strings.HasPrefix(name, prefix)) || name == a {
+				kept = append(kept, kv)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// CheckSandboxSupported fails for sandbox settings this platform can't
+// enforce, so such a job never runs unrestricted
+func CheckSandboxSupported(sb *config.JobSandbox) error {
+	if sb.Limited() && runtime.GOOS == "windows" {
+		return errors.New("sandbox max_memory_mb and max_cpu_seconds are not supported on Windows")
+	}
+	if sb.Namespaced() && runtime.GOOS != "linux" {
+		return fmt.Errorf("sandbox no_network and read_only_workdir need Linux namespaces, not available on %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// SandboxCommand rewrites cmd to start under sb: sh applies the resource
+// limits and read-only mount and then execs the original command, inside
+// unshare(1) when the sandbox needs namespaces. workdir is the directory
+// read_only_workdir protects. Call it after cmd's program is resolved and
+// before Start.
+func SandboxCommand(cmd *exec.Cmd, sb *config.JobSandbox, workdir string) error {
+	if !sb.Limited() && !sb.Namespaced() {
+		return nil
+	}
+	if err := CheckSandboxSupported(sb); err != nil {
+		return err
+	}
+
+	var prelude []string
+	if sb.ReadOnlyWorkdir {
+		dir, err := filepath.Abs(workdir)
+		if err != nil {
+			return fmt.Errorf("sandbox workdir: %w", err)
+		}
+		// cd again so the job's working directory is the read-only mount
+		// rather than the directory under it
+		d := ShellQuote(dir)
+		prelude = append(prelude, sandboxStep("mount --bind "+d+" "+d+" && mount -o remount,bind,ro "+d+" && cd "+d, "read-only mount of "+dir))
+	}
+	if sb.MaxMemoryMB > 0 {
+		prelude = append(prelude, sandboxStep(fmt.Sprintf("ulimit -v %d", sb.MaxMemoryMB*1024), "memory limit"))
+	}
+	if sb.MaxCPUSeconds > 0 {
+		// The hard limit is a second above the soft one, so the job gets
+		// SIGXCPU, which says why it stopped, before SIGKILL
+		prelude = append(prelude, sandboxStep(fmt.Sprintf("ulimit -S -t %d && ulimit -H -t %d", sb.MaxCPUSeconds, sb.MaxCPUSeconds+1), "CPU time limit"))
+	}
+	prelude = append(prelude, `exec "$@"`)
+
+	args := []string{"sh", "-c", strings.Join(prelude, "\n"), "blues-traveler-sandbox", cmd.Path}
+	args = append(args, cmd.Args[1:]...)
+	if sb.Namespaced() {
+		ns := []string{"unshare", "--map-root-user"}
+		if sb.NoNetwork {
+			ns = append(ns, "--net")
+		}
+		if sb.ReadOnlyWorkdir {
+			ns = append(ns, "--mount")
+		}
+		args = append(append(ns, "--"), args...)
+	}
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("sandbox needs %s on PATH: %w", args[0], err)
+	}
+	cmd.Path = path
+	cmd.Args = args
+	return nil
+}
+
+// sandboxStep runs script, or reports what failed and exits
+func sandboxStep(script, what string) string {
+	return fmt.Sprintf("%s || { echo %s >&2; exit %d; }", script, ShellQuote(sandboxSetupMarker+what+" failed"), sandboxSetupExit)
+}
+
+// SandboxViolation explains, for the agent, how sb stopped a job that
+// failed with err and wrote stderr, or returns "" when the failure doesn't
+// look like the sandbox's doing
+func SandboxViolation(sb *config.JobSandbox, err error, stderr string) string {
+	if sb == nil || err == nil {
+		return ""
+	}
+	if line := sandboxSetupLine(stderr); line != "" {
+		msg := fmt.Sprintf("The job's sandbox could not be set up (%s), so the job did not run.", line)
+		if sb.Namespaced() {
+			msg += " no_network and read_only_workdir need unshare from util-linux and unprivileged user namespaces."
+		}
+		return msg + " This is an environment problem, not a problem with the code; tell the user rather than changing files to work around it."
+	}
+	lower := strings.ToLower(stderr)
+	containsAny := func(subs ...string) bool {
+		for _, s := range subs {
+			if strings.Contains(lower, s) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case sb.MaxCPUSeconds > 0 && (exceededCPULimit(err) || containsAny("cpu time limit exceeded")):
+		return fmt.Sprintf("The job was killed after using its sandbox CPU time limit of %ds (sandbox.max_cpu_seconds). The command may be stuck in a loop, or the limit may be too low for it; don't retry the same command, and tell the user if the limit needs raising.", sb.MaxCPUSeconds)
+	case sb.MaxMemoryMB > 0 && containsAny("cannot allocate memory", "out of memory", "memoryerror", "bad_alloc"):
+		return fmt.Sprintf("The job ran out of memory under its sandbox limit of %d MB (sandbox.max_memory_mb). Some runtimes reserve more address space than they use; tell the user if the limit needs raising.", sb.MaxMemoryMB)
+	case sb.ReadOnlyWorkdir && containsAny("read-only file system"):
+		return "The job tried to write to its workdir, which its sandbox mounts read-only (sandbox.read_only_workdir). The job is meant to check files, not change them; make any changes yourself rather than through the job."
+	case sb.NoNetwork && containsAny("network is unreachable", "could not resolve host", "temporary failure in name resolution", "name or service not known", "no address associated with hostname"):
+		return "The job tried to reach the network, which its sandbox turns off (sandbox.no_network). The job has to work offline; don't retry it expecting network access, and tell the user if it needs the network."
+	}
+	return ""
+}
+
+// sandboxSetupLine returns the line in stderr where the sandbox or unshare
+// reported a setup failure
+func sandboxSetupLine(stderr string) string {
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, sandboxSetupMarker) || strings.HasPrefix(line, "unshare: ") {
+			return line
+		}
+	}
+	return ""
+}
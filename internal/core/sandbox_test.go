@@ -0,0 +1,76 @@
+package core
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+func TestSandboxEnv(t *testing.T) {
+	environ := []string{"PATH=/usr/bin", "HOME=/home/u", "LC_ALL=C", "LC_CTYPE=UTF-8", "AWS_SECRET_ACCESS_KEY=x", "LANG=en"}
+	got := SandboxEnv(environ, []string{"PATH", " LC_* ", "LAN"})
+	if want := "PATH=/usr/bin LC_ALL=C LC_CTYPE=UTF-8"; strings.Join(got, " ") != want {
+		t.Errorf("kept %v, want %s", got, want)
+	}
+	if got := SandboxEnv(environ, nil); len(got) != 0 {
+		t.Errorf("an empty allowlist kept %v", got)
+	}
+}
+
+func TestSandboxCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	cmd := exec.Command("bash", "-lc", "echo hi")
+	if err := SandboxCommand(cmd, &config.JobSandbox{ClearEnv: true}, "."); err != nil || cmd.Args[0] != "bash" {
+		t.Fatalf("a sandbox without limits or namespaces should leave the command alone, got %v %v", cmd.Args, err)
+	}
+
+	if err := SandboxCommand(cmd, &config.JobSandbox{MaxMemoryMB: 256, MaxCPUSeconds: 5}, "."); err != nil {
+		if CheckSandboxSupported(&config.JobSandbox{MaxCPUSeconds: 5}) != nil {
+			t.Skip("resource limits not supported here")
+		}
+		t.Fatal(err)
+	}
+	if cmd.Args[0] != "sh" || cmd.Args[3] != "blues-traveler-sandbox" || !strings.HasSuffix(cmd.Args[4], "bash") || strings.Join(cmd.Args[5:], " ") != "-lc echo hi" {
+		t.Fatalf("unexpected wrapped command %q", cmd.Args)
+	}
+	for _, want := range []string{"ulimit -v 262144", "ulimit -S -t 5", `exec "$@"`} {
+		if !strings.Contains(cmd.Args[2], want) {
+			t.Errorf("prelude %q is missing %q", cmd.Args[2], want)
+		}
+	}
+}
+
+func TestSandboxViolation(t *testing.T) {
+	failed := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		sb     *config.JobSandbox
+		err    error
+		stderr string
+		want   string
+	}{
+		{"no sandbox", nil, failed, "Read-only file system", ""},
+		{"succeeded", &config.JobSandbox{ReadOnlyWorkdir: true}, nil, "Read-only file system", ""},
+		{"setup", &config.JobSandbox{NoNetwork: true}, failed, "unshare: unshare failed: Operation not permitted\n", "could not be set up (unshare: unshare failed: Operation not permitted)"},
+		{"limit setup", &config.JobSandbox{MaxCPUSeconds: 1}, failed, sandboxSetupMarker + "CPU time limit failed\n", "could not be set up"},
+		{"read-only", &config.JobSandbox{ReadOnlyWorkdir: true}, failed, "touch: cannot touch 'x': Read-only file system", "sandbox.read_only_workdir"},
+		{"read-only not set", &config.JobSandbox{NoNetwork: true}, failed, "touch: cannot touch 'x': Read-only file system", ""},
+		{"network", &config.JobSandbox{NoNetwork: true}, failed, "curl: (6) Could not resolve host: example.com", "sandbox.no_network"},
+		{"memory", &config.JobSandbox{MaxMemoryMB: 64}, failed, "fatal error: runtime: out of memory", "64 MB (sandbox.max_memory_mb)"},
+		{"cpu", &config.JobSandbox{MaxCPUSeconds: 2}, failed, "bash: line 1: 12 CPU time limit exceeded", "2s (sandbox.max_cpu_seconds)"},
+		{"plain failure", &config.JobSandbox{MaxCPUSeconds: 2, NoNetwork: true}, failed, "lint: 3 issues", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SandboxViolation(tt.sb, tt.err, tt.stderr)
+			if tt.want == "" && got != "" || tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
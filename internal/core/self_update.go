@@ -0,0 +1,326 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ReleasesURLEnv overrides where releases are looked up, e.g. for a
+	// mirror; it takes the base of the GitHub releases API
+	ReleasesURLEnv = "BT_RELEASES_URL"
+	// defaultReleasesURL is the GitHub releases API of the project
+	defaultReleasesURL = "https://api.github.com/repos/klauern/blues-traveler/releases"
+	// releaseChecksumsAsset lists the SHA-256 of every release archive
+	releaseChecksumsAsset = "checksums.txt"
+	// maxReleaseDownloadBytes caps how much of an asset is read
+	maxReleaseDownloadBytes = 200 << 20
+)
+
+var releaseHTTPClient = &http.Client{}
+
+// Release is a published blues-traveler release
+type Release struct {
+	TagName    string         `json:"tag_name"`
+	Prerelease bool           `json:"prerelease"`
+	Assets     []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the asset called name
+func (r *Release) Asset(name string) (ReleaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// FetchRelease looks up the release tagged tag, or the latest release when
+// tag is empty
+func FetchRelease(ctx context.Context, tag string) (*Release, error) {
+	base := strings.TrimSuffix(os.Getenv(ReleasesURLEnv), "/")
+	if base == "" {
+		base = defaultReleasesURL
+	}
+	url := base + "/latest"
+	if tag != "" {
+		if !strings.HasPrefix(tag, "v") {
+			tag = "v" + tag
+		}
+		url = base + "/tags/" + tag
+	}
+	body, err := downloadRelease(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, errors.New("failed to parse release: no tag name")
+	}
+	return &release, nil
+}
+
+// ReleaseArchiveName is the release archive built for goos and goarch,
+// named as .goreleaser.yaml names it
+func ReleaseArchiveName(goos, goarch string) string {
+	osName := strings.ToUpper(goos[:1]) + goos[1:]
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("blues-traveler_%s_%s%s", osName, arch, ext)
+}
+
+// DownloadReleaseBinary downloads the archive for this platform from
+// release, checks it against the release's checksums and returns the
+// binary inside it
+func DownloadReleaseBinary(ctx context.Context, release *Release) ([]byte, error) {
+	name := ReleaseArchiveName(runtime.GOOS, runtime.GOARCH)
+	archive, ok := release.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	sums, ok := release.Asset(releaseChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s publishes no %s, so the download can't be verified", release.TagName, releaseChecksumsAsset)
+	}
+	checksums, err := downloadRelease(ctx, sums.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", releaseChecksumsAsset, err)
+	}
+	data, err := downloadRelease(ctx, archive.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := VerifyChecksum(checksums, name, data); err != nil {
+		return nil, err
+	}
+	return ExtractReleaseBinary(name, data)
+}
+
+// downloadRelease fetches url, sending GITHUB_TOKEN when set to avoid the
+// anonymous rate limit
+func downloadRelease(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "blues-traveler")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := releaseHTTPClient.Do(req) // #nosec G107 - release URLs come from the releases API
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseDownloadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxReleaseDownloadBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxReleaseDownloadBytes)
+	}
+	return body, nil
+}
+
+// VerifyChecksum checks data against the SHA-256 listed for name in a
+// sha256sum-style checksums file
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], hex.EncodeToString(sum[:]))
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not listed in %s", name, releaseChecksumsAsset)
+}
+
+// ExtractReleaseBinary returns the blues-traveler binary from a .tar.gz or
+// .zip release archive
+func ExtractReleaseBinary(archiveName string, data []byte) ([]byte, error) {
+	isBinary := func(name string) bool {
+		base := filepath.Base(filepath.ToSlash(name))
+		return base == "blues-traveler" || base == "blues-traveler.exe"
+	}
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !isBinary(f.Name) {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract %s: %w", f.Name, err)
+			}
+			defer func() { _ = rc.Close() }()
+			return io.ReadAll(io.LimitReader(rc, maxReleaseDownloadBytes))
+		}
+		return nil, fmt.Errorf("%s has no blues-traveler binary", archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s has no blues-traveler binary", archiveName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archiveName, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && isBinary(hdr.Name) {
+			return io.ReadAll(io.LimitReader(tr, maxReleaseDownloadBytes))
+		}
+	}
+}
+
+// ReplaceExecutable swaps the binary at path for data. The new binary is
+// written next to it and renamed into place, so path always holds a
+// complete binary. Windows can't overwrite a running executable, so there
+// the old one is first moved aside to path.old.
+func ReplaceExecutable(path string, data []byte) error {
+	mode := os.FileMode(0o755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blues-traveler-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary: %w\n  Suggestion: Check that %s is writable, or use --to to install elsewhere", err, filepath.Dir(path))
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	_, werr := tmp.Write(data)
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return fmt.Errorf("failed to write the new binary: %w", werr)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to make the new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to move the old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// CompareVersions compares two release versions such as "v1.2.3" and
+// "1.3.0-rc1" and returns -1, 0 or 1. A pre-release sorts before its
+// release; anything that isn't a version sorts before every version.
+func CompareVersions(a, b string) int {
+	pa, oka := parseVersion(a)
+	pb, okb := parseVersion(b)
+	switch {
+	case !oka && !okb:
+		return 0
+	case !oka:
+		return -1
+	case !okb:
+		return 1
+	}
+	for i := 0; i < 3; i++ {
+		if pa.parts[i] != pb.parts[i] {
+			if pa.parts[i] < pb.parts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case pa.pre == pb.pre:
+		return 0
+	case pa.pre == "":
+		return 1
+	case pb.pre == "":
+		return -1
+	case pa.pre < pb.pre:
+		return -1
+	}
+	return 1
+}
+
+type version struct {
+	parts [3]int
+	pre   string
+}
+
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	nums, pre, _ := strings.Cut(s, "-")
+	fields := strings.Split(nums, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return version{}, false
+	}
+	var v version
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.parts[i] = n
+	}
+	v.pre = pre
+	return v, true
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// releaseArchive builds the archive ReleaseArchiveName names for this platform
+func releaseArchive(t *testing.T, binary []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if runtime.GOOS == "windows" {
+		zw := zip.NewWriter(&buf)
+		for name, data := range map[string][]byte{"README.md": []byte("readme"), "blues-traveler.exe": binary} {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write(data)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"README.md", []byte("readme")}, {"blues-traveler", binary}} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o755, Size: int64(len(f.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write(f.data)
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func TestDownloadReleaseBinary(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new\n")
+	archive := releaseArchive(t, binary)
+	name := ReleaseArchiveName(runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(archive)
+	checksums := fmt.Sprintf("%s  %s\n%s  other.tar.gz\n", hex.EncodeToString(sum[:]), name, strings.Repeat("0", 64))
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/latest":
+			_, _ = fmt.Fprintf(w, `{"tag_name":"v1.2.0","assets":[{"name":%q,"browser_download_url":"%s/a"},{"name":"checksums.txt","browser_download_url":"%s/sums"}]}`, name, srv.URL, srv.URL)
+		case "/a":
+			_, _ = w.Write(archive)
+		case "/sums":
+			_, _ = w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(ReleasesURLEnv, srv.URL+"/releases")
+
+	release, err := FetchRelease(context.Background(), "")
+	if err != nil {
+		t.Fatalf("FetchRelease: %v", err)
+	}
+	if release.TagName != "v1.2.0" {
+		t.Fatalf("expected v1.2.0, got %s", release.TagName)
+	}
+	got, err := DownloadReleaseBinary(context.Background(), release)
+	if err != nil {
+		t.Fatalf("DownloadReleaseBinary: %v", err)
+	}
+	if !bytes.Equal(got, binary) {
+		t.Fatalf("extracted %q, want %q", got, binary)
+	}
+
+	if _, err := FetchRelease(context.Background(), "9.9.9"); err == nil {
+		t.Fatal("expected an unknown tag to fail")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  a.tar.gz\n")
+	if err := VerifyChecksum(checksums, "a.tar.gz", data); err != nil {
+		t.Fatalf("expected matching checksum to pass: %v", err)
+	}
+	if err := VerifyChecksum(checksums, "a.tar.gz", []byte("tampered")); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Fatalf("expected a mismatch, got %v", err)
+	}
+	if err := VerifyChecksum(checksums, "b.tar.gz", data); err == nil {
+		t.Fatal("expected an unlisted archive to fail")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blues-traveler")
+	if err := os.WriteFile(path, []byte("old"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceExecutable(path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("expected the new binary, got %q (%v)", data, err)
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0o700 {
+		t.Errorf("expected the old mode to be kept, got %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if runtime.GOOS != "windows" && len(entries) != 1 {
+		t.Errorf("expected no leftover temp files, got %d entries", len(entries))
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.9.9", 1},
+		{"1.3.0-rc1", "1.3.0", -1},
+		{"1.3", "1.3.0", 0},
+		{"dev", "v0.1.0", -1},
+		{"1.0.0+abc", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// shellVarRef matches ${NAME} and $NAME references in a job's command
+var shellVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// DefaultShell is the shell a job without one runs under: bash, or on
+// Windows without bash on PATH (e.g. Git Bash), pwsh
+func DefaultShell() string {
+	if runtime.GOOS != "windows" {
+		return config.ShellBash
+	}
+	if _, err := exec.LookPath("bash"); err == nil {
+		return config.ShellBash
+	}
+	return config.ShellPwsh
+}
+
+// ShellCommand returns the command that runs script under shell, one of
+// config.ValidShells or empty for DefaultShell, with env as its
+// environment. For pwsh and cmd, ${NAME} and $NAME references to variables
+// in env are rewritten to ${env:NAME} and %NAME%, so commands written for
+// bash that only read the hook variables run unchanged.
+func ShellCommand(ctx context.Context, shell, script string, env []string) *exec.Cmd {
+	if shell == "" {
+		shell = DefaultShell()
+	}
+	name, args := shellArgs(shell, script, env, exec.LookPath)
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- user-configured command execution is intentional
+	cmd.Env = env
+	if shell == config.ShellCmd {
+		setRawCommandLine(cmd, name, args)
+	}
+	return cmd
+}
+
+// shellArgs builds the program and arguments that run script under shell.
+// lookPath finds pwsh, falling back to Windows PowerShell.
+func shellArgs(shell, script string, env []string, lookPath func(string) (string, error)) (string, []string) {
+	switch shell {
+	case config.ShellSh:
+		return "sh", []string{"-c", script}
+	case config.ShellPwsh:
+		name := "pwsh"
+		if _, err := lookPath(name); err != nil {
+			if _, err := lookPath("powershell"); err == nil {
+				name = "powershell"
+			}
+		}
+		script = rewriteVarRefs(script, env, func(v string) string { return "${env:" + v + "}" })
+		return name, []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	case config.ShellCmd:
+		script = rewriteVarRefs(script, env, func(v string) string { return "%" + v + "%" })
+		return "cmd", []string{"/d", "/s", "/c", script}
+	default:
+		return "bash", []string{"-lc", script}
+	}
+}
+
+// rewriteVarRefs replaces references to variables set in env with ref(name),
+// leaving the shell's own variables alone
+func rewriteVarRefs(script string, env []string, ref func(string) string) string {
+	set := make(map[string]bool, len(env))
+	for _, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); ok {
+			set[k] = true
+		}
+	}
+	return shellVarRef.ReplaceAllStringFunc(script, func(m string) string {
+		name := strings.Trim(m, "${}")
+		if !set[name] {
+			return m
+		}
+		return ref(name)
+	})
+}
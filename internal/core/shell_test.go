@@ -0,0 +1,40 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestShellArgs(t *testing.T) {
+	env := []string{"TOOL_FILE=main.go", "FILES_CHANGED=a.go b.go"}
+	found := func(string) (string, error) { return "", nil }
+	cases := []struct {
+		shell, script string
+		name          string
+		args          []string
+	}{
+		{"bash", "gofmt -l $TOOL_FILE", "bash", []string{"-lc", "gofmt -l $TOOL_FILE"}},
+		{"sh", "true", "sh", []string{"-c", "true"}},
+		{"pwsh", "gofmt -l ${TOOL_FILE} $_ $HOME", "pwsh", []string{"-NoProfile", "-NonInteractive", "-Command", "gofmt -l ${env:TOOL_FILE} $_ $HOME"}},
+		{"cmd", "echo $FILES_CHANGED & echo %PATH%", "cmd", []string{"/d", "/s", "/c", "echo %FILES_CHANGED% & echo %PATH%"}},
+	}
+	for _, c := range cases {
+		name, args := shellArgs(c.shell, c.script, env, found)
+		if name != c.name || !slices.Equal(args, c.args) {
+			t.Errorf("shellArgs(%s) = %s %q, want %s %q", c.shell, name, args, c.name, c.args)
+		}
+	}
+}
+
+func TestShellArgsFallsBackToWindowsPowerShell(t *testing.T) {
+	onlyPowerShell := func(name string) (string, error) {
+		if name == "powershell" {
+			return name, nil
+		}
+		return "", errors.New("not found")
+	}
+	if name, _ := shellArgs("pwsh", "exit 0", nil, onlyPowerShell); name != "powershell" {
+		t.Errorf("name = %s, want powershell", name)
+	}
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// StateDirEnv is set for custom jobs to the current session's state directory
+	StateDirEnv = "BT_STATE_DIR"
+	// SessionIDEnv is set for custom jobs to the session id from the event payload
+	SessionIDEnv = "BT_SESSION_ID"
+
+	// stateSubDir is the directory under .claude/ holding per-session state
+	stateSubDir = "state"
+	// sessionEndedMarker records when SessionEnd was seen for a session
+	sessionEndedMarker = ".ended"
+	// sessionEndGrace keeps state around briefly after SessionEnd so every
+	// SessionEnd hook (they run concurrently) can still read it
+	sessionEndGrace = 5 * time.Minute
+	// sessionStaleAfter drops state for sessions that never reported SessionEnd
+	sessionStaleAfter = 7 * 24 * time.Hour
+)
+
+// stateNamePattern restricts session ids and keys to safe file names.
+// Leading dots are rejected so keys cannot collide with internal markers.
+var stateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// ErrStateKeyNotFound is returned by SessionState.Get for unset keys
+var ErrStateKeyNotFound = errors.New("state key not found")
+
+// SessionState is a small key-value store scoped to one Claude session. Each
+// key is a plain file in the session directory, so custom jobs can use it
+// with ordinary shell commands via $BT_STATE_DIR.
+type SessionState struct {
+	dir string
+}
+
+// StateRoot returns the project's session state directory (.claude/state).
+// BT_STATE_ROOT overrides the location (mainly for tests).
+func StateRoot() (string, error) {
+	if dir := os.Getenv("BT_STATE_ROOT"); dir != "" {
+		return dir, nil
+	}
+	claudeDir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(claudeDir, stateSubDir), nil
+}
+
+// OpenSessionState returns the store for sessionID, creating its directory.
+// Opening a store also prunes sessions that ended or went stale.
+func OpenSessionState(sessionID string) (*SessionState, error) {
+	if !stateNamePattern.MatchString(sessionID) {
+		return nil, fmt.Errorf("invalid session id '%s'", sessionID)
+	}
+	root, err := StateRoot()
+	if err != nil {
+		return nil, err
+	}
+	PruneSessionStates(root, time.Now())
+
+	dir := filepath.Join(root, sessionID)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	// Session state is scratch data; keep it out of the project's git status
+	ignore := filepath.Join(root, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		_ = os.WriteFile(ignore, []byte("*\n"), 0o600)
+	}
+	return &SessionState{dir: dir}, nil
+}
+
+// Dir returns the directory backing this store
+func (s *SessionState) Dir() string {
+	return s.dir
+}
+
+// Get returns the value stored under key, or ErrStateKeyNotFound
+func (s *SessionState) Get(key string) (string, error) {
+	path, err := s.keyPath(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - key validated against stateNamePattern
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrStateKeyNotFound, key)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read state key '%s': %w", key, err)
+	}
+	return string(data), nil
+}
+
+// Set stores value under key, replacing any previous value atomically
+func (s *SessionState) Set(key, value string) error {
+	path, err := s.keyPath(key)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, "."+key+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write state key '%s': %w", key, err)
+	}
+	if _, err := tmp.WriteString(value); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state key '%s': %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state key '%s': %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state key '%s': %w", key, err)
+	}
+	return nil
+}
+
+// GetJSON decodes the JSON value stored under key into v
+func (s *SessionState) GetJSON(key string, v any) error {
+	data, err := s.Get(key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode state key '%s': %w", key, err)
+	}
+	return nil
+}
+
+// SetJSON stores v under key as JSON
+func (s *SessionState) SetJSON(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode state key '%s': %w", key, err)
+	}
+	return s.Set(key, string(data))
+}
+
+// Delete removes key; deleting a missing key is not an error
+func (s *SessionState) Delete(key string) error {
+	path, err := s.keyPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete state key '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Keys lists stored keys in sorted order
+func (s *SessionState) Keys() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state: %w", err)
+	}
+	var keys []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+			keys = append(keys, e.Name())
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// MarkEnded records that the session is over. The store is removed by a
+// later prune once sessionEndGrace has passed.
+func (s *SessionState) MarkEnded() error {
+	marker := filepath.Join(s.dir, sessionEndedMarker)
+	if err := os.WriteFile(marker, nil, 0o600); err != nil {
+		return fmt.Errorf("failed to mark session ended: %w", err)
+	}
+	return nil
+}
+
+func (s *SessionState) keyPath(key string) (string, error) {
+	if !stateNamePattern.MatchString(key) {
+		return "", fmt.Errorf("invalid state key '%s': use letters, digits, '.', '_' or '-'", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// PruneSessionStates removes session directories under root that ended more
+// than sessionEndGrace ago or have not been touched for sessionStaleAfter.
+// Errors are ignored; pruning is best effort.
+func PruneSessionStates(root string, now time.Time) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if sessionStateExpired(dir, now) {
+			_ = os.RemoveAll(dir)
+		}
+	}
+}
+
+func sessionStateExpired(dir string, now time.Time) bool {
+	if info, err := os.Stat(filepath.Join(dir, sessionEndedMarker)); err == nil {
+		return now.Sub(info.ModTime()) > sessionEndGrace
+	}
+	latest := time.Time{}
+	if info, err := os.Stat(dir); err == nil {
+		latest = info.ModTime()
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return now.Sub(latest) > sessionStaleAfter
+}
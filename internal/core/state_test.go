@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSessionState_KeyValue(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("BT_STATE_ROOT", root)
+
+	state, err := OpenSessionState("abc-123")
+	if err != nil {
+		t.Fatalf("OpenSessionState: %v", err)
+	}
+	if state.Dir() != filepath.Join(root, "abc-123") {
+		t.Errorf("Dir() = %s", state.Dir())
+	}
+
+	if _, err := state.Get("missing"); !errors.Is(err, ErrStateKeyNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrStateKeyNotFound", err)
+	}
+	if err := state.Set("files", "a.go"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Set("files", "a.go b.go"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := state.Get("files"); err != nil || got != "a.go b.go" {
+		t.Errorf("Get(files) = %q, %v", got, err)
+	}
+
+	type counts struct{ Edits int }
+	if err := state.SetJSON("counts", counts{Edits: 3}); err != nil {
+		t.Fatal(err)
+	}
+	var c counts
+	if err := state.GetJSON("counts", &c); err != nil || c.Edits != 3 {
+		t.Errorf("GetJSON = %+v, %v", c, err)
+	}
+
+	keys, err := state.Keys()
+	if err != nil || !reflect.DeepEqual(keys, []string{"counts", "files"}) {
+		t.Errorf("Keys() = %v, %v", keys, err)
+	}
+	if err := state.Delete("files"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Delete("files"); err != nil {
+		t.Errorf("deleting a missing key should succeed: %v", err)
+	}
+
+	for _, bad := range []string{"", "../escape", ".ended", "a/b"} {
+		if err := state.Set(bad, "x"); err == nil {
+			t.Errorf("Set(%q) should fail", bad)
+		}
+	}
+	if _, err := OpenSessionState("../other"); err == nil {
+		t.Error("expected invalid session id to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(root, ".gitignore")); err != nil {
+		t.Errorf("expected state root to be git-ignored: %v", err)
+	}
+}
+
+func TestPruneSessionStates(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("BT_STATE_ROOT", root)
+	now := time.Now()
+
+	active, _ := OpenSessionState("active")
+	_ = active.Set("k", "v")
+
+	justEnded, _ := OpenSessionState("just-ended")
+	_ = justEnded.MarkEnded()
+
+	ended, _ := OpenSessionState("ended")
+	_ = ended.MarkEnded()
+	old := now.Add(-sessionEndGrace - time.Minute)
+	_ = os.Chtimes(filepath.Join(ended.Dir(), sessionEndedMarker), old, old)
+
+	stale, _ := OpenSessionState("stale")
+	_ = stale.Set("k", "v")
+	ancient := now.Add(-sessionStaleAfter - time.Hour)
+	_ = os.Chtimes(filepath.Join(stale.Dir(), "k"), ancient, ancient)
+	_ = os.Chtimes(stale.Dir(), ancient, ancient)
+
+	PruneSessionStates(root, now)
+
+	for name, want := range map[string]bool{"active": true, "just-ended": true, "ended": false, "stale": false} {
+		_, err := os.Stat(filepath.Join(root, name))
+		if got := err == nil; got != want {
+			t.Errorf("session %s kept = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/output"
+)
+
+// withTerse passes every message a handler returns through
+// output.TerseText when the context asks for terse output, so color codes
+// and emoji from hooks and the tools they run don't reach the agent
+func (h *BaseHook) withTerse(
+	preHandler func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	postHandler func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	rawHandler func(context.Context, string) *cchooks.RawResponse,
+) (
+	func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface,
+	func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface,
+	func(context.Context, string) *cchooks.RawResponse,
+) {
+	if !h.Context().TerseOutput {
+		return preHandler, postHandler, rawHandler
+	}
+
+	var pre func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface
+	if preHandler != nil {
+		pre = func(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			return tersePreResponse(preHandler(ctx, event))
+		}
+	}
+
+	var post func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface
+	if postHandler != nil {
+		post = func(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			return tersePostResponse(postHandler(ctx, event))
+		}
+	}
+
+	var raw func(context.Context, string) *cchooks.RawResponse
+	if rawHandler != nil {
+		raw = func(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+			resp := rawHandler(ctx, rawJSON)
+			if resp != nil && resp.Output != "" {
+				resp.Output = terseRawOutput(resp.Output)
+			}
+			return resp
+		}
+	}
+
+	return pre, post, raw
+}
+
+func tersePreResponse(resp cchooks.PreToolUseResponseInterface) cchooks.PreToolUseResponseInterface {
+	switch r := resp.(type) {
+	case *AskPreToolResponse:
+		r.userMessage = output.TerseText(r.userMessage)
+		r.agentMessage = output.TerseText(r.agentMessage)
+		r.Reason = output.TerseText(r.Reason)
+	case *DualMessagePreToolResponse:
+		r.userMessage = output.TerseText(r.userMessage)
+		r.agentMessage = output.TerseText(r.agentMessage)
+		r.Reason = output.TerseText(r.Reason)
+	case *cchooks.PreToolUseResponse:
+		r.Reason = output.TerseText(r.Reason)
+		r.StopReason = output.TerseText(r.StopReason)
+	}
+	return resp
+}
+
+func tersePostResponse(resp cchooks.PostToolUseResponseInterface) cchooks.PostToolUseResponseInterface {
+	switch r := resp.(type) {
+	case *DualMessagePostToolResponse:
+		r.userMessage = output.TerseText(r.userMessage)
+		r.agentMessage = output.TerseText(r.agentMessage)
+		r.Reason = output.TerseText(r.Reason)
+	case *cchooks.PostToolUseResponse:
+		r.Reason = output.TerseText(r.Reason)
+		r.StopReason = output.TerseText(r.StopReason)
+	}
+	return resp
+}
+
+// terseRawOutput cleans a raw response. JSON output has its string values
+// cleaned and is re-encoded, so a curly quote turned straight can't break it.
+func terseRawOutput(out string) string {
+	trimmed := strings.TrimSpace(out)
+	if strings.HasPrefix(trimmed, "{") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(trimmed), &v); err == nil {
+			if data, err := json.Marshal(terseJSONValue(v)); err == nil {
+				return string(data)
+			}
+		}
+	}
+	return output.TerseText(out)
+}
+
+func terseJSONValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return output.TerseText(t)
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = terseJSONValue(item)
+		}
+	case []interface{}:
+		for i, item := range t {
+			t[i] = terseJSONValue(item)
+		}
+	}
+	return v
+}
+
+// Progressf prints a progress line, such as which formatter ran, unless the
+// context asks for terse output
+func (h *BaseHook) Progressf(format string, args ...interface{}) {
+	if h.Context().TerseOutput {
+		return
+	}
+	output.Printf(format, args...)
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/brads3290/cchooks"
+)
+
+func TestRunner_TerseCleansMessagesAndDropsTiming(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	ctx := TestHookContext(nil)
+	ctx.TerseOutput = true
+	ctx.LatencyThreshold = time.Nanosecond
+	hook := NewBaseHook("fmt", "Format", "", ctx)
+
+	runner := hook.Runner(
+		func(context.Context, *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+			return BlockWithMessages("🚫 Blocked → use fd\n", "\x1b[31merror\x1b[0m: find is slow")
+		},
+		func(context.Context, *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+			return cchooks.PostBlock("⚠️  lint failed")
+		},
+		func(_ context.Context, rawJSON string) *cchooks.RawResponse {
+			if rawJSON == "json" {
+				data, _ := json.Marshal(cchooks.BlockStop("✅ “done”"))
+				return &cchooks.RawResponse{Output: string(data)}
+			}
+			return &cchooks.RawResponse{Output: "✅ PR readiness: all checks passed\n"}
+		},
+	).(*MockRunner)
+
+	pre := SummarizeResponse(runner.PreToolUse(context.Background(), &cchooks.PreToolUseEvent{}))
+	if pre.UserMessage != "Blocked -> use fd" || pre.AgentMessage != "error: find is slow" {
+		t.Errorf("pre = %+v, want plain messages without timing", pre)
+	}
+	post := SummarizeResponse(runner.PostToolUse(context.Background(), &cchooks.PostToolUseEvent{}))
+	if post.UserMessage != "[!]  lint failed" {
+		t.Errorf("post message = %q", post.UserMessage)
+	}
+
+	if got := runner.RawHook(context.Background(), "text").Output; got != "[ok] PR readiness: all checks passed" {
+		t.Errorf("raw text output = %q", got)
+	}
+	var stop cchooks.StopResponse
+	out := runner.RawHook(context.Background(), "json").Output
+	if err := json.Unmarshal([]byte(out), &stop); err != nil {
+		t.Fatalf("raw JSON output no longer parses: %v: %s", err, out)
+	}
+	if stop.Decision != cchooks.StopBlock || stop.Reason != `[ok] "done"` {
+		t.Errorf("stop response = %+v", stop)
+	}
+}
+
+func TestRunner_NotTerseLeavesMessages(t *testing.T) {
+	hook := NewBaseHook("fmt", "Format", "", TestHookContext(nil))
+	runner := hook.Runner(nil, nil, func(context.Context, string) *cchooks.RawResponse {
+		return &cchooks.RawResponse{Output: "✅ ok\n"}
+	}).(*MockRunner)
+	if got := runner.RawHook(context.Background(), "{}").Output; got != "✅ ok\n" {
+		t.Errorf("output = %q, want it unchanged", got)
+	}
+}
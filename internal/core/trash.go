@@ -0,0 +1,271 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// trashSubDir is the directory under .claude/ holding deleted files
+	trashSubDir = "trash"
+	// trashManifest describes one trash entry inside its directory
+	trashManifest = "manifest.json"
+	// trashFilesDir holds the moved files inside an entry directory
+	trashFilesDir = "files"
+	trashIDLayout = "20060102-150405"
+)
+
+// TrashItem is one file or directory held in a trash entry
+type TrashItem struct {
+	// Original is the absolute path the item is restored to
+	Original string `json:"original"`
+	// Stored is the item's path relative to the entry's files directory
+	Stored string `json:"stored"`
+}
+
+// TrashEntry is one deletion caught by the delete-guard hook
+type TrashEntry struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	Tool string    `json:"tool"`
+	// Command is the Bash command, when the deletion came from one
+	Command string      `json:"command,omitempty"`
+	Items   []TrashItem `json:"items"`
+}
+
+// TrashDir returns the project's trash directory (.claude/trash).
+// BT_TRASH_DIR overrides the location.
+func TrashDir() (string, error) {
+	if dir := os.Getenv("BT_TRASH_DIR"); dir != "" {
+		return dir, nil
+	}
+	claudeDir, err := config.ProjectClaudeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(claudeDir, trashSubDir), nil
+}
+
+// MoveToTrash moves paths into a new trash entry. With keepOriginal the
+// items are copied instead (used before a file is overwritten). Paths that
+// do not exist are skipped; nil is returned when nothing was trashed.
+func MoveToTrash(paths []string, tool, command string, keepOriginal bool) (*TrashEntry, error) {
+	root, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCacheDir(root); err != nil {
+		return nil, err
+	}
+	entry := &TrashEntry{Time: time.Now().UTC(), Tool: tool, Command: command}
+	dir, err := newTrashEntryDir(root, entry)
+	if err != nil {
+		return nil, err
+	}
+	filesDir := filepath.Join(dir, trashFilesDir)
+	if err := os.MkdirAll(filesDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create trash entry: %w", err)
+	}
+
+	for i, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Lstat(abs); err != nil {
+			continue
+		}
+		stored := strconv.Itoa(i) + "-" + filepath.Base(abs)
+		dest := filepath.Join(filesDir, stored)
+		if keepOriginal {
+			err = copyFile(abs, dest)
+		} else {
+			err = os.Rename(abs, dest)
+		}
+		if err != nil {
+			// Keep whatever was already moved restorable
+			_ = writeTrashManifest(dir, entry)
+			return entry, fmt.Errorf("failed to move %s to trash: %w", p, err)
+		}
+		entry.Items = append(entry.Items, TrashItem{Original: abs, Stored: stored})
+	}
+	if len(entry.Items) == 0 {
+		_ = os.RemoveAll(dir)
+		return nil, nil
+	}
+	return entry, writeTrashManifest(dir, entry)
+}
+
+// newTrashEntryDir creates a uniquely named entry directory and sets entry.ID
+func newTrashEntryDir(root string, entry *TrashEntry) (string, error) {
+	base := entry.Time.Format(trashIDLayout)
+	for n := 0; n < 1000; n++ {
+		id := base
+		if n > 0 {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		dir := filepath.Join(root, id)
+		if err := os.Mkdir(dir, 0o750); err == nil {
+			entry.ID = id
+			return dir, nil
+		} else if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create trash entry: %w", err)
+		}
+	}
+	return "", fmt.Errorf("failed to create trash entry: too many entries for %s", base)
+}
+
+// ListTrash returns trash entries, newest first
+func ListTrash() ([]TrashEntry, error) {
+	root, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	var entries []TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := readTrashManifest(filepath.Join(root, d.Name()))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	return entries, nil
+}
+
+// RestoreTrash moves an entry's items back to their original paths and
+// removes the entry. Without force, an item whose original path exists
+// again stops the restore before anything is moved.
+func RestoreTrash(id string, force bool) (*TrashEntry, error) {
+	root, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("invalid trash entry '%s'", id)
+	}
+	dir := filepath.Join(root, id)
+	entry, err := readTrashManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		for _, item := range entry.Items {
+			if _, err := os.Lstat(item.Original); err == nil {
+				return nil, fmt.Errorf("%s already exists\n  Suggestion: Move it aside or use --force to overwrite", item.Original)
+			}
+		}
+	}
+	for _, item := range entry.Items {
+		if force {
+			if err := os.RemoveAll(item.Original); err != nil {
+				return nil, fmt.Errorf("failed to replace %s: %w", item.Original, err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(item.Original), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", item.Original, err)
+		}
+		src := filepath.Join(dir, trashFilesDir, filepath.Base(item.Stored))
+		if err := os.Rename(src, item.Original); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", item.Original, err)
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return entry, fmt.Errorf("restored, but failed to remove trash entry: %w", err)
+	}
+	return entry, nil
+}
+
+// EmptyTrash permanently deletes entries older than before (all entries
+// when before is zero) and returns how many were removed
+func EmptyTrash(before time.Time) (int, error) {
+	entries, err := ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	root, err := TrashDir()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, e := range entries {
+		if !before.IsZero() && !e.Time.Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, e.ID)); err != nil {
+			return removed, fmt.Errorf("failed to remove trash entry %s: %w", e.ID, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func readTrashManifest(dir string) (*TrashEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, trashManifest)) // #nosec G304 - entry dir under the trash dir
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("trash entry '%s' not found\n  Suggestion: Run 'blues-traveler trash list' to see entries", filepath.Base(dir))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash entry: %w", err)
+	}
+	var entry TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse trash entry %s: %w", filepath.Base(dir), err)
+	}
+	return &entry, nil
+}
+
+func writeTrashManifest(dir string, entry *TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, trashManifest), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write trash entry: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies a regular file, keeping its permission bits
+func copyFile(src, dest string) error {
+	in, err := os.Open(src) // #nosec G304 - path named by the tool call being guarded
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", src)
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm()) // #nosec G304 - dest is inside the trash entry
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
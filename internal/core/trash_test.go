@@ -0,0 +1,64 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrash_RestoreRefusesToOverwrite(t *testing.T) {
+	t.Setenv("BT_TRASH_DIR", t.TempDir())
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(file, []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := MoveToTrash([]string{file, filepath.Join(dir, "missing.txt")}, "Bash", "rm a.txt missing.txt", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry == nil || len(entry.Items) != 1 {
+		t.Fatalf("expected only the existing file trashed, got %+v", entry)
+	}
+	if err := os.WriteFile(file, []byte("v2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RestoreTrash(entry.ID, false); err == nil {
+		t.Fatal("restore over an existing file should fail without force")
+	}
+	if _, err := RestoreTrash(entry.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "v1" {
+		t.Errorf("forced restore left %q", data)
+	}
+	if entries, _ := ListTrash(); len(entries) != 0 {
+		t.Errorf("restored entry should be removed, got %+v", entries)
+	}
+	if _, err := RestoreTrash("../escape", false); err == nil {
+		t.Error("ids with path separators must be rejected")
+	}
+}
+
+func TestEmptyTrash_OlderThan(t *testing.T) {
+	t.Setenv("BT_TRASH_DIR", t.TempDir())
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := MoveToTrash([]string{p}, "Bash", "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := EmptyTrash(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("recent entries should be kept, removed %d, %v", n, err)
+	}
+	if n, err := EmptyTrash(time.Time{}); err != nil || n != 2 {
+		t.Errorf("EmptyTrash() removed %d, %v; want 2", n, err)
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // IsInQuotedString performs a simple check if the pattern is within quotes in the command string.
 // This is a basic implementation - a full parser would be more accurate but this covers most shell cases.
@@ -19,3 +22,20 @@ func IsInQuotedString(command, pattern string) bool {
 	// This assumes balanced quotes and doesn't handle escaped quotes perfectly, but is sufficient for basic checks
 	return singleQuotes%2 == 1 || doubleQuotes%2 == 1
 }
+
+// FormatBytes renders a byte count in the largest whole binary unit
+func FormatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
+
+// ShellQuote single-quotes s for literal use in sh or bash
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -10,6 +10,7 @@ import (
 	"text/template"
 
 	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/output"
 )
 
 //go:embed templates/*
@@ -114,19 +115,19 @@ func (g *Generator) generateFile(templateName string, data TemplateData, outputF
 		return fmt.Errorf("failed to execute template: %v", err)
 	}
 
-	fmt.Printf("Generated: %s\n", outputPath)
+	output.Printf("Generated: %s\n", outputPath)
 	return nil
 }
 
 func (g *Generator) showRegistrationInstructions(data TemplateData) {
-	fmt.Println("\n📝 Registration Instructions:")
-	fmt.Printf("Add the following line to %s/init.go in the init() function:\n", constants.InternalHooksDir)
-	fmt.Printf("    MustRegisterHook(\"%s\", New%sHook)\n", data.LowerName, data.Name)
-	fmt.Println("\n🧪 Testing:")
-	fmt.Printf("    go test ./%s -run Test%sHook\n", constants.InternalHooksDir, data.Name)
-	fmt.Println("\n🔧 Usage:")
-	fmt.Printf("    ./%s run %s\n", constants.BinaryName, data.LowerName)
-	fmt.Printf("    ./%s install %s\n", constants.BinaryName, data.LowerName)
+	output.Println("\n📝 Registration Instructions:")
+	output.Printf("Add the following line to %s/init.go in the init() function:\n", constants.InternalHooksDir)
+	output.Printf("    MustRegisterHook(\"%s\", New%sHook)\n", data.LowerName, data.Name)
+	output.Println("\n🧪 Testing:")
+	output.Printf("    go test ./%s -run Test%sHook\n", constants.InternalHooksDir, data.Name)
+	output.Println("\n🔧 Usage:")
+	output.Printf("    ./%s run %s\n", constants.BinaryName, data.LowerName)
+	output.Printf("    ./%s install %s\n", constants.BinaryName, data.LowerName)
 }
 
 // ValidateHookName checks if a hook name is valid
@@ -9,139 +9,163 @@ import (
 	"time"
 
 	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
 )
 
-// AuditHook implements comprehensive audit logging
+// auditMaxInputLen caps long inputs such as commands in audit records
+const auditMaxInputLen = 2000
+
+// AuditHook records every tool call as a schema-versioned audit record in
+// per-day files under .claude/audit, queryable with "blues-traveler audit query",
+// and optionally exports each record to an OpenTelemetry collector
 type AuditHook struct {
 	*core.BaseHook
 }
 
-// AuditEntry represents an audit log entry
-type AuditEntry struct {
-	Timestamp string                 `json:"timestamp"`
-	Event     string                 `json:"event"`
-	ToolName  string                 `json:"tool_name"`
-	Details   map[string]interface{} `json:"details,omitempty"`
-}
-
 // NewAuditHook creates a new audit hook instance
 func NewAuditHook(ctx *core.HookContext) core.Hook {
-	base := core.NewBaseHook("audit", "Audit Hook", "Comprehensive audit logging with JSON output", ctx)
+	base := core.NewBaseHook("audit", "Audit Hook", "Queryable audit records of every tool call", ctx)
 	return &AuditHook{BaseHook: base}
 }
 
+// Manifest describes the audit hook
+func (h *AuditHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent), string(core.PostToolUseEvent)}
+	m.SettingsKey = "audit"
+	m.SettingsSchema = config.SectionSchema(config.AuditConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityWritesState}
+	return m
+}
+
 // Run executes the audit hook.
 func (h *AuditHook) Run() error {
 	return h.StandardRun(h.preToolUseHandler, h.postToolUseHandler)
 }
 
-// addToolSpecificDetails adds tool-specific details to the audit entry
-func (h *AuditHook) addToolSpecificDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	switch event.ToolName {
+// summarizeInputs reduces a tool input to the fields worth auditing. File
+// contents and edit strings are recorded as lengths only.
+func summarizeInputs(tool string, input json.RawMessage) map[string]interface{} {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return nil
+	}
+	summary := map[string]interface{}{}
+	keep := func(keys ...string) {
+		for _, k := range keys {
+			if v, ok := fields[k]; ok {
+				if s, isString := v.(string); isString && len(s) > auditMaxInputLen {
+					v = s[:auditMaxInputLen] + "…"
+				}
+				summary[k] = v
+			}
+		}
+	}
+	lengthOf := func(key string) {
+		if s, ok := fields[key].(string); ok {
+			summary[key+"_length"] = len(s)
+		}
+	}
+
+	switch tool {
 	case constants.ToolBash:
-		h.addBashDetails(entry, event)
+		keep("command", "description")
 	case constants.ToolEdit:
-		h.addEditDetails(entry, event)
+		keep("file_path", "replace_all")
+		lengthOf("old_string")
+		lengthOf("new_string")
+	case constants.ToolMultiEdit:
+		keep("file_path")
+		if edits, ok := fields["edits"].([]interface{}); ok {
+			summary["edit_count"] = len(edits)
+		}
 	case constants.ToolWrite:
-		h.addWriteDetails(entry, event)
+		keep("file_path")
+		lengthOf("content")
 	case constants.ToolRead:
-		h.addReadDetails(entry, event)
-	case constants.ToolGlob:
-		h.addGlobDetails(entry, event)
-	case constants.ToolGrep:
-		h.addGrepDetails(entry, event)
+		keep("file_path", "offset", "limit")
+	case constants.ToolGlob, constants.ToolGrep:
+		keep("pattern", "path", "glob")
+	default:
+		// Unknown and MCP tools: keep scalar fields, drop nested payloads
+		for k, v := range fields {
+			switch v.(type) {
+			case string, float64, bool:
+				keep(k)
+			}
+		}
 	}
-}
-
-func (h *AuditHook) addBashDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if bash, err := event.AsBash(); err == nil {
-		entry.Details["command"] = bash.Command
-		entry.Details["description"] = bash.Description
-	}
-}
-
-func (h *AuditHook) addEditDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if edit, err := event.AsEdit(); err == nil {
-		entry.Details["file_path"] = edit.FilePath
-		entry.Details["old_string_length"] = len(edit.OldString)
-		entry.Details["new_string_length"] = len(edit.NewString)
+	if len(summary) == 0 {
+		return nil
 	}
+	return summary
 }
 
-func (h *AuditHook) addWriteDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if write, err := event.AsWrite(); err == nil {
-		entry.Details["file_path"] = write.FilePath
-		entry.Details["content_length"] = len(write.Content)
+// postDecision reads a tool response for the error flags tools report
+func postDecision(response json.RawMessage) string {
+	var r struct {
+		Success *bool `json:"success"`
+		IsError bool  `json:"is_error"`
 	}
-}
-
-func (h *AuditHook) addReadDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if read, err := event.AsRead(); err == nil {
-		entry.Details["file_path"] = read.FilePath
+	if json.Unmarshal(response, &r) == nil && (r.IsError || (r.Success != nil && !*r.Success)) {
+		return core.AuditDecisionFailed
 	}
+	return core.AuditDecisionExecuted
 }
 
-func (h *AuditHook) addGlobDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if glob, err := event.AsGlob(); err == nil {
-		entry.Details["pattern"] = glob.Pattern
-	}
+func (h *AuditHook) preToolUseHandler(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	h.record(ctx, core.AuditRecord{
+		SessionID: event.SessionID,
+		Event:     string(core.PreToolUseEvent),
+		Tool:      event.ToolName,
+		Inputs:    summarizeInputs(event.ToolName, event.ToolInput),
+		Decision:  core.AuditDecisionRequested,
+	})
+	return cchooks.Approve()
 }
 
-func (h *AuditHook) addGrepDetails(entry *AuditEntry, event *cchooks.PreToolUseEvent) {
-	if grep, err := event.AsGrep(); err == nil {
-		entry.Details["pattern"] = grep.Pattern
-	}
+func (h *AuditHook) postToolUseHandler(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+	h.record(ctx, core.AuditRecord{
+		SessionID: event.SessionID,
+		Event:     string(core.PostToolUseEvent),
+		Tool:      event.ToolName,
+		Inputs:    summarizeInputs(event.ToolName, event.ToolInput),
+		Decision:  postDecision(event.ToolResponse),
+	})
+	return cchooks.Allow()
 }
 
-func (h *AuditHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
-	entry := AuditEntry{
-		Event:    "pre_tool_use",
-		ToolName: event.ToolName,
-		Details:  make(map[string]interface{}),
+// record stores rec and exports it when OTLP is configured, after the
+// redaction rules scrub its inputs; a failed write or export is reported
+// but never blocks the tool
+func (h *AuditHook) record(ctx context.Context, rec core.AuditRecord) {
+	if cwd, err := os.Getwd(); err == nil {
+		rec.Cwd = cwd
 	}
-
-	h.addToolSpecificDetails(&entry, event)
-	h.logAuditEntry(entry)
-
-	// Also use the new detailed logging if enabled
-	if h.Context().LoggingEnabled {
-		rawData := make(map[string]interface{})
-		rawData["tool_name"] = event.ToolName
-		h.LogHookEvent("pre_tool_use", event.ToolName, rawData, entry.Details)
+	rec.Time = time.Now()
+	rec.Schema, rec.Actor = core.AuditSchemaVersion, core.AuditActorAgent
+	// Scrub once so the store, the export and the hook log all get the same record
+	rec.Inputs = h.Context().Redactor.Map(rec.Inputs)
+	if err := core.AppendAuditRecord(rec); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write audit record: %v\n", err)
+		h.LogError("audit_write_failed", rec.Tool, err)
 	}
-
-	return cchooks.Approve()
-}
-
-func (h *AuditHook) postToolUseHandler(_ context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
-	entry := AuditEntry{
-		Event:    "post_tool_use",
-		ToolName: event.ToolName,
-		Details:  make(map[string]interface{}),
+	if cfg := h.loadConfig(); cfg.OTLP != nil {
+		if err := exportAuditOTLP(ctx, *cfg.OTLP, rec); err != nil {
+			h.LogError("audit_export_failed", rec.Tool, err)
+		}
 	}
 
-	h.logAuditEntry(entry)
-
-	// Also use the new detailed logging if enabled
 	if h.Context().LoggingEnabled {
-		rawData := make(map[string]interface{})
-		rawData["tool_name"] = event.ToolName
-		h.LogHookEvent("post_tool_use", event.ToolName, rawData, entry.Details)
+		rawData := map[string]interface{}{"tool_name": rec.Tool}
+		h.LogHookEvent("audit_"+rec.Decision, rec.Tool, rawData, rec.Inputs)
 	}
-
-	return cchooks.Allow()
 }
 
-func (h *AuditHook) logAuditEntry(entry AuditEntry) {
-	entry.Timestamp = time.Now().Format(time.RFC3339)
-
-	jsonData, err := json.Marshal(entry)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to marshal audit entry: %v\n", err)
-		return
-	}
-
-	fmt.Println(string(jsonData))
+// loadConfig returns the project's audit section, or the global one when
+// the project has none
+func (h *AuditHook) loadConfig() config.AuditConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.AuditConfig { return c.Audit })
 }
@@ -0,0 +1,226 @@
+package hooks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// OTLP signals the audit hook can export
+const (
+	otlpSignalLogs   = "logs"
+	otlpSignalTraces = "traces"
+)
+
+const (
+	// defaultAuditOTLPTimeout bounds each export request, in seconds; the
+	// tool call waits for it
+	defaultAuditOTLPTimeout = 2
+	// defaultOTLPServiceName is the service.name resource attribute
+	defaultOTLPServiceName = "blues-traveler"
+	// otlpScopeName names the instrumentation scope of exported records
+	otlpScopeName = "blues-traveler/audit"
+
+	// OTLP severity numbers and span status codes
+	otlpSeverityInfo     = 9
+	otlpSeverityWarn     = 13
+	otlpSpanKindInternal = 1
+	otlpStatusError      = 2
+)
+
+// exportAuditOTLP sends rec to the collector as a log record, a span or
+// both. Records of one session share a trace id derived from the session
+// id, and a record's log and span share a span id, so the collector can
+// correlate them.
+func exportAuditOTLP(ctx context.Context, cfg config.AuditOTLPConfig, rec core.AuditRecord) error {
+	endpoint := os.ExpandEnv(cfg.Endpoint)
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		return fmt.Errorf("endpoint is not set (set audit.otlp.endpoint or OTEL_EXPORTER_OTLP_ENDPOINT)")
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	signals := cfg.Signals
+	if len(signals) == 0 {
+		signals = []string{otlpSignalLogs}
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultAuditOTLPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	traceID, spanID := otlpTraceID(rec.SessionID), otlpSpanID()
+	resource := otlpResource(cfg)
+	for _, signal := range signals {
+		var path string
+		var payload map[string]interface{}
+		switch strings.ToLower(signal) {
+		case otlpSignalLogs:
+			path = "/v1/logs"
+			payload = map[string]interface{}{"resourceLogs": []interface{}{map[string]interface{}{
+				"resource":  resource,
+				"scopeLogs": []interface{}{map[string]interface{}{"scope": otlpScope(), "logRecords": []interface{}{otlpLogRecord(rec, traceID, spanID)}}},
+			}}}
+		case otlpSignalTraces:
+			path = "/v1/traces"
+			payload = map[string]interface{}{"resourceSpans": []interface{}{map[string]interface{}{
+				"resource":   resource,
+				"scopeSpans": []interface{}{map[string]interface{}{"scope": otlpScope(), "spans": []interface{}{otlpSpan(rec, traceID, spanID)}}},
+			}}}
+		default:
+			return fmt.Errorf("unknown signal %q (use %s or %s)", signal, otlpSignalLogs, otlpSignalTraces)
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if err := postJSON(ctx, http.MethodPost, endpoint+path, cfg.Headers, body); err != nil {
+			return fmt.Errorf("%s: %w", signal, err)
+		}
+	}
+	return nil
+}
+
+func otlpResource(cfg config.AuditOTLPConfig) map[string]interface{} {
+	service := cfg.ServiceName
+	if service == "" {
+		service = defaultOTLPServiceName
+	}
+	attrs := map[string]interface{}{"service.name": service}
+	for k, v := range cfg.ResourceAttributes {
+		attrs[k] = os.ExpandEnv(v)
+	}
+	return map[string]interface{}{"attributes": otlpAttributes(attrs)}
+}
+
+func otlpScope() map[string]interface{} {
+	return map[string]interface{}{"name": otlpScopeName}
+}
+
+// otlpRecordAttributes carries the audit fields; inputs are flattened
+// under claude.input.
+func otlpRecordAttributes(rec core.AuditRecord) []interface{} {
+	attrs := map[string]interface{}{
+		"claude.hook.event": rec.Event,
+		"claude.tool.name":  rec.Tool,
+		"claude.decision":   rec.Decision,
+		"claude.actor":      rec.Actor,
+		"audit.schema":      rec.Schema,
+	}
+	if rec.SessionID != "" {
+		attrs["session.id"] = rec.SessionID
+	}
+	if rec.Cwd != "" {
+		attrs["claude.cwd"] = rec.Cwd
+	}
+	for k, v := range rec.Inputs {
+		attrs["claude.input."+k] = v
+	}
+	return otlpAttributes(attrs)
+}
+
+func otlpLogRecord(rec core.AuditRecord, traceID, spanID string) map[string]interface{} {
+	severity, severityText := otlpSeverityInfo, "INFO"
+	if rec.Decision == core.AuditDecisionFailed {
+		severity, severityText = otlpSeverityWarn, "WARN"
+	}
+	ts := strconv.FormatInt(rec.Time.UnixNano(), 10)
+	return map[string]interface{}{
+		"timeUnixNano":         ts,
+		"observedTimeUnixNano": strconv.FormatInt(time.Now().UnixNano(), 10),
+		"severityNumber":       severity,
+		"severityText":         severityText,
+		"body":                 map[string]interface{}{"stringValue": fmt.Sprintf("%s %s %s", rec.Event, rec.Tool, rec.Decision)},
+		"attributes":           otlpRecordAttributes(rec),
+		"traceId":              traceID,
+		"spanId":               spanID,
+	}
+}
+
+// otlpSpan is a zero-length span at the record's time; each hook run sees
+// only one side of a tool call, so there is no duration to measure
+func otlpSpan(rec core.AuditRecord, traceID, spanID string) map[string]interface{} {
+	ts := strconv.FormatInt(rec.Time.UnixNano(), 10)
+	span := map[string]interface{}{
+		"traceId":           traceID,
+		"spanId":            spanID,
+		"name":              rec.Event + " " + rec.Tool,
+		"kind":              otlpSpanKindInternal,
+		"startTimeUnixNano": ts,
+		"endTimeUnixNano":   ts,
+		"attributes":        otlpRecordAttributes(rec),
+	}
+	if rec.Decision == core.AuditDecisionFailed {
+		span["status"] = map[string]interface{}{"code": otlpStatusError, "message": "tool reported an error"}
+	}
+	return span
+}
+
+// otlpAttributes encodes attrs as OTLP key-value pairs, sorted by key
+func otlpAttributes(attrs map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, map[string]interface{}{"key": k, "value": otlpValue(attrs[k])})
+	}
+	return out
+}
+
+// otlpValue encodes v as an OTLP AnyValue; 64-bit integers are strings in
+// the JSON encoding
+func otlpValue(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int:
+		return map[string]interface{}{"intValue": strconv.Itoa(v)}
+	case float64:
+		if v == float64(int64(v)) {
+			return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+		}
+		return map[string]interface{}{"doubleValue": v}
+	}
+	data, _ := json.Marshal(v)
+	return map[string]interface{}{"stringValue": string(data)}
+}
+
+// otlpTraceID derives a session's trace id so every record of the session
+// lands in one trace; records without a session get a random one
+func otlpTraceID(sessionID string) string {
+	if sessionID == "" {
+		return otlpRandomID(16)
+	}
+	sum := sha256.Sum256([]byte("blues-traveler/session/" + sessionID))
+	return hex.EncodeToString(sum[:16])
+}
+
+func otlpSpanID() string {
+	return otlpRandomID(8)
+}
+
+func otlpRandomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,143 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestAuditHook_WritesQueryableRecords(t *testing.T) {
+	t.Setenv("BT_AUDIT_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	h := NewAuditHook(core.TestHookContext(nil)).(*AuditHook)
+
+	h.preToolUseHandler(context.Background(), &cchooks.PreToolUseEvent{
+		SessionID: "s-1",
+		ToolName:  "Write",
+		ToolInput: json.RawMessage(`{"file_path":"main.go","content":"package main\n"}`),
+	})
+	h.postToolUseHandler(context.Background(), &cchooks.PostToolUseEvent{
+		SessionID:    "s-1",
+		ToolName:     "Bash",
+		ToolInput:    json.RawMessage(`{"command":"go test ./..."}`),
+		ToolResponse: json.RawMessage(`{"is_error":true}`),
+	})
+
+	recs, err := core.QueryAuditRecords(core.AuditFilter{SessionID: "s-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %+v", recs)
+	}
+	write := recs[0]
+	if write.Event != "PreToolUse" || write.Decision != core.AuditDecisionRequested ||
+		write.Inputs["file_path"] != "main.go" || write.Inputs["content_length"] != float64(13) {
+		t.Errorf("unexpected write record: %+v", write)
+	}
+	if _, ok := write.Inputs["content"]; ok {
+		t.Error("file content should not be recorded")
+	}
+	if bash := recs[1]; bash.Decision != core.AuditDecisionFailed || bash.Inputs["command"] != "go test ./..." {
+		t.Errorf("unexpected bash record: %+v", bash)
+	}
+}
+
+func TestAuditHook_ExportsOTLP(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string][]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(data, &body)
+		mu.Lock()
+		bodies[r.URL.Path+" "+r.Header.Get("X-Tenant")] = append(bodies[r.URL.Path+" "+r.Header.Get("X-Tenant")], body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BT_AUDIT_DIR", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL+"/")
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"audit":{"otlp":{
+		"signals":["logs","traces"],"headers":{"X-Tenant":"team-a"},"resourceAttributes":{"deployment.environment":"ci"}
+	}}}`)
+
+	h := NewAuditHook(core.TestHookContext(nil)).(*AuditHook)
+	h.preToolUseHandler(context.Background(), &cchooks.PreToolUseEvent{
+		SessionID: "s-1",
+		ToolName:  "Read",
+		ToolInput: json.RawMessage(`{"file_path":"main.go","limit":200}`),
+	})
+	h.postToolUseHandler(context.Background(), &cchooks.PostToolUseEvent{
+		SessionID:    "s-1",
+		ToolName:     "Bash",
+		ToolInput:    json.RawMessage(`{"command":"go test ./..."}`),
+		ToolResponse: json.RawMessage(`{"is_error":true}`),
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	logs, spans := bodies["/v1/logs team-a"], bodies["/v1/traces team-a"]
+	if len(logs) != 2 || len(spans) != 2 {
+		t.Fatalf("expected 2 log and 2 span exports, got %v", bodies)
+	}
+	first := dig(t, logs[0], "resourceLogs", "scopeLogs", "logRecords")
+	second := dig(t, logs[1], "resourceLogs", "scopeLogs", "logRecords")
+	if first["traceId"] != second["traceId"] || first["traceId"] != otlpTraceID("s-1") {
+		t.Errorf("records of a session should share a trace id: %v, %v", first["traceId"], second["traceId"])
+	}
+	if first["severityText"] != "INFO" || second["severityText"] != "WARN" {
+		t.Errorf("unexpected severities %v, %v", first["severityText"], second["severityText"])
+	}
+	attrs := map[string]interface{}{}
+	for _, a := range first["attributes"].([]interface{}) {
+		kv := a.(map[string]interface{})
+		attrs[kv["key"].(string)] = kv["value"]
+	}
+	for key, want := range map[string]string{
+		"session.id":             `{"stringValue":"s-1"}`,
+		"claude.tool.name":       `{"stringValue":"Read"}`,
+		"claude.decision":        `{"stringValue":"requested"}`,
+		"claude.input.file_path": `{"stringValue":"main.go"}`,
+		"claude.input.limit":     `{"intValue":"200"}`,
+	} {
+		if got, _ := json.Marshal(attrs[key]); string(got) != want {
+			t.Errorf("attribute %s = %s, want %s", key, got, want)
+		}
+	}
+	resource, _ := json.Marshal(logs[0]["resourceLogs"].([]interface{})[0].(map[string]interface{})["resource"])
+	if want := `{"attributes":[{"key":"deployment.environment","value":{"stringValue":"ci"}},{"key":"service.name","value":{"stringValue":"blues-traveler"}}]}`; string(resource) != want {
+		t.Errorf("resource = %s, want %s", resource, want)
+	}
+
+	span := dig(t, spans[1], "resourceSpans", "scopeSpans", "spans")
+	if span["name"] != "PostToolUse Bash" || span["spanId"] != second["spanId"] || span["status"] == nil {
+		t.Errorf("unexpected span %v", span)
+	}
+}
+
+// dig returns the first element of the nested OTLP lists at path
+func dig(t *testing.T, body map[string]interface{}, path ...string) map[string]interface{} {
+	t.Helper()
+	cur := body
+	for _, key := range path {
+		list, ok := cur[key].([]interface{})
+		if !ok || len(list) == 0 {
+			t.Fatalf("missing %s in %v", key, cur)
+		}
+		cur = list[0].(map[string]interface{})
+	}
+	return cur
+}
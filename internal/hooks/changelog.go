@@ -0,0 +1,162 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// changelogListLimit caps how many changed files a changelog message names
+const changelogListLimit = 10
+
+// defaultChangelogPaths are the changelog files and fragment directories
+// common tooling (towncrier, changesets, keep-a-changelog) uses
+var defaultChangelogPaths = []string{
+	"CHANGELOG.md", "CHANGES.md", "NEWS.md", "HISTORY.md",
+	"changelog.d/*", "changes/*", "newsfragments/*", ".changeset/*.md",
+}
+
+// defaultChangelogIgnore are changes that don't need a changelog entry
+var defaultChangelogIgnore = []string{"*.md", "docs/", ".github/"}
+
+// ChangelogHook checks on Stop that a session which changed source files
+// also added a changelog entry or news fragment, as many projects require
+// in CI. It warns by default; with changelog.action "block" it hands the
+// gap back to the agent once.
+type ChangelogHook struct {
+	*core.BaseHook
+}
+
+// NewChangelogHook creates a new changelog enforcement hook instance
+func NewChangelogHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("changelog", "Changelog Enforcer", "Warns or blocks on Stop when source changes have no changelog entry or news fragment", ctx)
+	return &ChangelogHook{BaseHook: base}
+}
+
+// Manifest describes the changelog hook
+func (h *ChangelogHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.StopEvent)}
+	m.DefaultEvent = string(core.StopEvent)
+	m.SettingsKey = "changelog"
+	m.SettingsSchema = config.SectionSchema(config.ChangelogConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the changelog hook
+func (h *ChangelogHook) Run() error {
+	return h.RunRaw(h.stopHandler)
+}
+
+func (h *ChangelogHook) stopHandler(_ context.Context, rawJSON string) *cchooks.RawResponse {
+	var event struct {
+		HookEventName  string `json:"hook_event_name"`
+		SessionID      string `json:"session_id"`
+		StopHookActive bool   `json:"stop_hook_active"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &event); err != nil || event.HookEventName != string(core.StopEvent) {
+		return nil
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return &cchooks.RawResponse{}
+	}
+	base, err := core.SessionGitBase(root, event.SessionID)
+	if err != nil {
+		return &cchooks.RawResponse{}
+	}
+	changed, err := core.GitChangedFiles(root, base)
+	if err != nil {
+		h.LogError("changelog_git_error", "", err)
+		return &cchooks.RawResponse{}
+	}
+
+	cfg := h.loadConfig()
+	needing, entries := classifyChangelogFiles(withoutClaudeDir(changed), cfg)
+	if len(needing) == 0 || len(entries) > 0 {
+		return &cchooks.RawResponse{}
+	}
+
+	details := map[string]interface{}{"changed": len(needing)}
+	listed := needing
+	if len(listed) > changelogListLimit {
+		listed = append(append([]string{}, listed[:changelogListLimit]...), fmt.Sprintf("... and %d more", len(needing)-changelogListLimit))
+	}
+	paths := cfg.Paths
+	if len(paths) == 0 {
+		paths = defaultChangelogPaths
+	}
+	summary := fmt.Sprintf("⚠️  Changelog: %d changed file(s) but no changelog entry (%s)", len(needing), strings.Join(paths, ", "))
+
+	if strings.EqualFold(cfg.Action, "block") && !event.StopHookActive {
+		h.LogBlock("changelog_block", "", details)
+		reason := fmt.Sprintf("These changes need a changelog entry before finishing. Add one to the changelog or as a news fragment (%s), following the style of the existing entries:\n- %s",
+			strings.Join(paths, ", "), strings.Join(listed, "\n- "))
+		data, err := json.Marshal(cchooks.BlockStop(reason))
+		if err == nil {
+			return &cchooks.RawResponse{Output: string(data)}
+		}
+	}
+	h.LogHookEvent("changelog_missing", "", details, nil)
+	return &cchooks.RawResponse{Output: summary + "\n- " + strings.Join(listed, "\n- ")}
+}
+
+// classifyChangelogFiles splits changed files into those that need a
+// changelog entry and the changelog entries themselves
+func classifyChangelogFiles(changed []string, cfg config.ChangelogConfig) (needing, entries []string) {
+	paths := cfg.Paths
+	if len(paths) == 0 {
+		paths = defaultChangelogPaths
+	}
+	ignore := cfg.Ignore
+	if ignore == nil {
+		ignore = defaultChangelogIgnore
+	}
+	for _, f := range changed {
+		switch {
+		case matchPathPattern(paths, f):
+			entries = append(entries, f)
+		case matchPathPattern(ignore, f):
+		case len(cfg.Sources) > 0 && !matchPathPattern(cfg.Sources, f):
+		default:
+			needing = append(needing, f)
+		}
+	}
+	return needing, entries
+}
+
+// matchPathPattern reports whether file matches any of patterns by path
+// or base name, or lies under a pattern ending in "/"
+func matchPathPattern(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "/") {
+			if strings.HasPrefix(file, p) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p, file); ok {
+			return true
+		}
+		if !strings.Contains(p, "/") {
+			if ok, _ := path.Match(p, path.Base(file)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadConfig reads changelog settings from the project config, falling back
+// to the global config
+func (h *ChangelogHook) loadConfig() config.ChangelogConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.ChangelogConfig { return c.Changelog })
+}
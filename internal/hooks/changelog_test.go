@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestChangelogHook_Stop(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	repo := t.TempDir()
+	t.Chdir(repo)
+	writeTestFile(t, filepath.Join(repo, "README.md"), "# app\n")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", "start"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeTestFile(t, filepath.Join(repo, ".claude", "hooks", "blues-traveler-config.json"), `{"changelog":{"action":"block"}}`)
+
+	hook := NewChangelogHook(core.TestHookContext(nil)).(*ChangelogHook)
+	stop := func(active bool) string {
+		raw := `{"hook_event_name":"Stop","session_id":"s-1","stop_hook_active":false}`
+		if active {
+			raw = strings.Replace(raw, "false", "true", 1)
+		}
+		resp := hook.stopHandler(context.Background(), raw)
+		if resp == nil {
+			t.Fatal("expected a response for Stop")
+		}
+		return resp.Output
+	}
+
+	// Documentation changes don't need an entry
+	writeTestFile(t, filepath.Join(repo, "README.md"), "# app\n\nMore docs.\n")
+	if out := stop(false); out != "" {
+		t.Fatalf("expected no output for docs-only changes, got %q", out)
+	}
+
+	writeTestFile(t, filepath.Join(repo, "main.go"), "package main\n")
+	out := stop(false)
+	if !strings.Contains(out, `"decision":"block"`) || !strings.Contains(out, "main.go") {
+		t.Fatalf("expected a block naming main.go, got %q", out)
+	}
+	if out := stop(true); !strings.Contains(out, "no changelog entry") || strings.Contains(out, `"decision"`) {
+		t.Fatalf("expected a warning once the agent was already stopped, got %q", out)
+	}
+
+	writeTestFile(t, filepath.Join(repo, "changelog.d", "123.feature.md"), "Add main.\n")
+	if out := stop(false); out != "" {
+		t.Fatalf("expected a news fragment to satisfy the hook, got %q", out)
+	}
+}
+
+func TestClassifyChangelogFiles(t *testing.T) {
+	changed := []string{"CHANGES.md", "cmd/main.go", "docs/guide.txt", "internal/x_test.go", "web/app.ts"}
+	needing, entries := classifyChangelogFiles(changed, config.ChangelogConfig{Sources: []string{"*.go"}, Ignore: []string{"*_test.go", "docs/"}})
+	if strings.Join(needing, ",") != "cmd/main.go" || strings.Join(entries, ",") != "CHANGES.md" {
+		t.Errorf("unexpected split: needing %v, entries %v", needing, entries)
+	}
+	needing, _ = classifyChangelogFiles(changed, config.ChangelogConfig{Paths: []string{"release-notes/"}})
+	if strings.Join(needing, ",") != "cmd/main.go,internal/x_test.go,web/app.ts" {
+		t.Errorf("unexpected files needing an entry: %v", needing)
+	}
+}
@@ -0,0 +1,274 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// codeOwnersLocations are searched in GitHub's order of precedence
+var codeOwnersLocations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// CodeOwnersHook maps edited files to their CODEOWNERS entries. It tells the
+// agent who owns what it is changing and blocks edits to paths owned by
+// restricted owners configured under codeOwners.restrictedOwners.
+type CodeOwnersHook struct {
+	*core.BaseHook
+}
+
+// NewCodeOwnersHook creates a new CODEOWNERS advisor hook instance
+func NewCodeOwnersHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("codeowners", "CODEOWNERS Advisor", "Reports owning teams for edited files and blocks edits to paths owned by restricted teams", ctx)
+	return &CodeOwnersHook{BaseHook: base}
+}
+
+// Manifest describes the codeowners hook
+func (h *CodeOwnersHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Edit|MultiEdit|Write|NotebookEdit"
+	m.SettingsKey = "codeOwners"
+	m.SettingsSchema = config.SectionSchema(config.CodeOwnersConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the CODEOWNERS advisor hook.
+func (h *CodeOwnersHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// ownedFile is an edited file together with the owners of its path
+type ownedFile struct {
+	Path       string
+	Owners     []string
+	Restricted string
+}
+
+func (h *CodeOwnersHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	switch event.ToolName {
+	case constants.ToolEdit, constants.ToolWrite, constants.ToolMultiEdit, constants.ToolNotebookEdit:
+	default:
+		return cchooks.Approve()
+	}
+
+	files := core.ParseToolPayload(event.ToolName, event.ToolInput).Files()
+	if len(files) == 0 {
+		return cchooks.Approve()
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+
+	rules, err := loadCodeOwners(root, cfg.File)
+	if err != nil {
+		// A broken CODEOWNERS file should not stop the agent from working
+		h.LogError("codeowners_error", event.ToolName, err)
+		return cchooks.Approve()
+	}
+	if len(rules) == 0 {
+		return cchooks.Approve()
+	}
+
+	owned := resolveOwnedFiles(root, files, rules, cfg.RestrictedOwners)
+	return h.respond(event.ToolName, owned)
+}
+
+// respond blocks when any file belongs to a restricted owner and otherwise
+// approves with an advisory listing of owners
+func (h *CodeOwnersHook) respond(toolName string, owned []ownedFile) cchooks.PreToolUseResponseInterface {
+	if len(owned) == 0 {
+		return cchooks.Approve()
+	}
+
+	var restricted, advisory []string
+	for _, f := range owned {
+		if f.Restricted != "" {
+			restricted = append(restricted, fmt.Sprintf("%s (owned by %s)", f.Path, f.Restricted))
+		}
+		advisory = append(advisory, fmt.Sprintf("%s: %s", f.Path, strings.Join(f.Owners, " ")))
+	}
+
+	if len(restricted) > 0 {
+		h.LogBlock("codeowners_block", toolName, map[string]interface{}{"files": restricted})
+		return core.BlockWithMessages(
+			"Edit blocked: the file is owned by a restricted team in CODEOWNERS.",
+			fmt.Sprintf("Edits to paths owned by restricted teams are not allowed:\n  %s\nLeave these files unchanged and describe the needed change for the owning team instead.",
+				strings.Join(restricted, "\n  ")),
+		)
+	}
+
+	h.LogApproval("codeowners_advisory", toolName, map[string]interface{}{"files": advisory})
+	return core.ApproveWithMessages(
+		fmt.Sprintf("CODEOWNERS: %s", strings.Join(advisory, "; ")),
+		fmt.Sprintf("These files have code owners who will review the change:\n  %s\nKeep edits within the scope of the task and call out changes to owned paths in your summary.",
+			strings.Join(advisory, "\n  ")),
+	)
+}
+
+// loadConfig reads codeOwners settings from the project config, falling back
+// to the global config
+func (h *CodeOwnersHook) loadConfig() config.CodeOwnersConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.CodeOwnersConfig { return c.CodeOwners })
+}
+
+// resolveOwnedFiles looks up owners for each file inside root. Files outside
+// the project or without owners are omitted.
+func resolveOwnedFiles(root string, files []string, rules codeOwnersRules, restrictedOwners []string) []ownedFile {
+	var owned []ownedFile
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(root, file)
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		owners := rules.Owners(rel)
+		if len(owners) == 0 {
+			continue
+		}
+		owned = append(owned, ownedFile{Path: rel, Owners: owners, Restricted: restrictedOwner(owners, restrictedOwners)})
+	}
+	return owned
+}
+
+// restrictedOwner returns the first owner that appears in restricted.
+// Comparison ignores case and a leading "@", as GitHub handles do.
+func restrictedOwner(owners, restricted []string) string {
+	for _, owner := range owners {
+		for _, r := range restricted {
+			if strings.EqualFold(strings.TrimPrefix(owner, "@"), strings.TrimPrefix(r, "@")) {
+				return owner
+			}
+		}
+	}
+	return ""
+}
+
+// codeOwnersRule is one pattern line from a CODEOWNERS file
+type codeOwnersRule struct {
+	Pattern  string
+	Owners   []string
+	segments []string
+}
+
+// codeOwnersRules holds rules in file order; later rules take precedence
+type codeOwnersRules []codeOwnersRule
+
+// Owners returns the owners of a slash-separated path relative to the
+// repository root. The last matching rule wins, and a matching rule with
+// no owners leaves the path unowned.
+func (rules codeOwnersRules) Owners(path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchGlobSegments(rules[i].segments, strings.Split(path, "/")) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// loadCodeOwners reads the CODEOWNERS file from override (if set) or the
+// first standard location under root. A missing file yields no rules.
+func loadCodeOwners(root, override string) (codeOwnersRules, error) {
+	candidates := codeOwnersLocations
+	if override != "" {
+		candidates = []string{override}
+	}
+	for _, candidate := range candidates {
+		path := candidate
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		f, err := os.Open(path) // #nosec G304 - CODEOWNERS path from fixed locations or project config
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		rules, err := parseCodeOwners(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return rules, nil
+	}
+	return nil, nil
+}
+
+// parseCodeOwners parses CODEOWNERS syntax. Lines with patterns GitHub does
+// not support (negation, character ranges) are skipped, as GitHub does.
+func parseCodeOwners(r io.Reader) (codeOwnersRules, error) {
+	var rules codeOwnersRules
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") {
+			continue
+		}
+
+		var owners []string
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "#") {
+				break
+			}
+			owners = append(owners, field)
+		}
+
+		rules = append(rules, codeOwnersRule{Pattern: pattern, Owners: owners, segments: codeOwnersSegments(pattern)})
+	}
+	return rules, scanner.Err()
+}
+
+// codeOwnersSegments converts a gitignore-style CODEOWNERS pattern to glob
+// segments for matchGlobSegments. Patterns containing a slash (other than a
+// trailing one) are anchored to the root; "*" and "?" stay within one path
+// segment and "**" spans segments. A pattern naming a directory matches
+// everything beneath it, except that a trailing wildcard segment such as
+// "docs/*" only matches direct children.
+func codeOwnersSegments(pattern string) []string {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	if !anchored {
+		segments = append([]string{"**"}, segments...)
+	}
+
+	last := segments[len(segments)-1]
+	switch {
+	case last == "**" && len(segments) > 1 && segments[len(segments)-2] != "**":
+		// "dir/**" matches what is inside dir, not dir itself
+		return append(segments[:len(segments)-1], "*", "**")
+	case last == "**":
+		return segments
+	case dirOnly:
+		return append(segments, "*", "**")
+	case anchored && strings.ContainsAny(last, "*?"):
+		return segments
+	default:
+		return append(segments, "**")
+	}
+}
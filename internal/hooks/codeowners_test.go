@@ -0,0 +1,121 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const sampleCodeOwners = `# Default owners
+*                    @org/everyone
+*.js                 @org/frontend
+/build/logs/         @org/ops
+docs/*               @org/docs
+apps/                @org/apps
+**/migrations        @org/dba
+/infra/**/secrets    @org/security  # inline comment
+/vendor/
+`
+
+func TestCodeOwnersRules_Owners(t *testing.T) {
+	rules, err := parseCodeOwners(strings.NewReader(sampleCodeOwners))
+	if err != nil {
+		t.Fatalf("parseCodeOwners: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/everyone"}},
+		{"web/app.js", []string{"@org/frontend"}},
+		{"build/logs/today.log", []string{"@org/ops"}},
+		{"src/build/logs/today.log", []string{"@org/everyone"}},
+		{"docs/intro.md", []string{"@org/docs"}},
+		{"docs/guides/intro.md", []string{"@org/everyone"}},
+		{"apps/api/main.go", []string{"@org/apps"}},
+		{"services/apps/main.go", []string{"@org/apps"}},
+		{"db/migrations/001.sql", []string{"@org/dba"}},
+		{"infra/prod/eu/secrets/key.yaml", []string{"@org/security"}},
+		{"infra/secrets", []string{"@org/security"}},
+		{"vendor/lib/lib.go", nil},
+	}
+	for _, tt := range tests {
+		if got := rules.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseCodeOwners_SkipsUnsupportedPatterns(t *testing.T) {
+	rules, err := parseCodeOwners(strings.NewReader("!keep.txt @a\n[Ab]bc @b\n\\#notes @c\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Pattern != "#notes" {
+		t.Fatalf("rules = %+v, want only the escaped #notes pattern", rules)
+	}
+}
+
+func TestRestrictedOwner(t *testing.T) {
+	owners := []string{"@org/Web", "@Org/Security"}
+	if got := restrictedOwner(owners, []string{"org/security"}); got != "@Org/Security" {
+		t.Errorf("restrictedOwner = %q, want @Org/Security", got)
+	}
+	if got := restrictedOwner(owners, []string{"@org/dba"}); got != "" {
+		t.Errorf("restrictedOwner = %q, want none", got)
+	}
+}
+
+func TestCodeOwnersHook_PreToolUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".github", "CODEOWNERS"), "* @org/everyone\n/secure/ @org/security\n")
+
+	hook := NewCodeOwnersHook(core.TestHookContext(nil)).(*CodeOwnersHook)
+	event := func(tool, input string) *cchooks.PreToolUseEvent {
+		return &cchooks.PreToolUseEvent{ToolName: tool, ToolInput: json.RawMessage(input)}
+	}
+
+	// Advisory when no owners are restricted
+	resp := hook.preToolUseHandler(context.Background(), event("Edit", `{"file_path":"`+filepath.Join(dir, "secure", "a.go")+`","old_string":"a","new_string":"b"}`))
+	summary := core.SummarizeResponse(resp)
+	if summary.Decision == "block" || !strings.Contains(summary.AgentMessage, "secure/a.go: @org/security") {
+		t.Fatalf("expected advisory approval naming the owner, got %+v", summary)
+	}
+
+	// Blocked once the owner is restricted in project config
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"codeOwners":{"restrictedOwners":["@org/security"]}}`)
+	resp = hook.preToolUseHandler(context.Background(), event("MultiEdit", `{"file_path":"README.md","edits":[{"old_string":"a","new_string":"b"},{"file_path":"secure/b.go","old_string":"c","new_string":"d"}]}`))
+	summary = core.SummarizeResponse(resp)
+	if summary.Decision != "block" || !strings.Contains(summary.AgentMessage, "secure/b.go (owned by @org/security)") {
+		t.Fatalf("expected block for restricted path, got %+v", summary)
+	}
+
+	// Other tools and files outside the project are ignored
+	for _, ev := range []*cchooks.PreToolUseEvent{
+		event("Bash", `{"command":"rm secure/b.go"}`),
+		event("Write", `{"file_path":"/elsewhere/secure/x.go","content":""}`),
+	} {
+		if summary := core.SummarizeResponse(hook.preToolUseHandler(context.Background(), ev)); summary.AgentMessage != "" || summary.Decision == "block" {
+			t.Errorf("expected plain approval for %s, got %+v", ev.ToolName, summary)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
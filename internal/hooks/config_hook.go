@@ -4,8 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"strings"
@@ -24,12 +25,36 @@ type ConfigHook struct {
 	groupName   string
 	envProvider core.EnvironmentProvider
 	lastRaw     string
+	// lockName/lockWait fence execution behind a machine-wide named lock
+	lockName string
+	lockWait time.Duration
+	// chained is set for jobs of an event with chain: true. chainAfter
+	// lists the jobs before this one, waited on for up to chainWait.
+	chained    bool
+	chainAfter []string
+	chainWait  time.Duration
+	// beforeAll/afterAll are the event's lifecycle commands, run once around
+	// all of eventJobs
+	beforeAll *config.LifecycleCommand
+	afterAll  *config.LifecycleCommand
+	eventJobs []string
 }
 
+// defaultChainWait is how long a chained job allows for an earlier job that
+// sets no timeout, matching Claude Code's default hook timeout
+const defaultChainWait = 60 * time.Second
+
 // NewConfigHook constructs a hook from config data
 func NewConfigHook(groupName, jobName string, job config.HookJob, event string, ctx *core.HookContext) core.Hook {
 	key := fmt.Sprintf("config:%s:%s", groupName, jobName)
-	base := core.NewBaseHook(key, jobName, fmt.Sprintf("Config job '%s' for %s", jobName, event), ctx)
+	description := job.Description
+	if description == "" {
+		description = fmt.Sprintf("Config job '%s' for %s", jobName, event)
+	}
+	base := core.NewBaseHook(key, jobName, description, ctx)
+	if level, err := config.ParseLogLevel(job.LogLevel); err == nil && level != "" {
+		base.SetLogLevel(level)
+	}
 	return &ConfigHook{
 		BaseHook:    base,
 		job:         job,
@@ -39,6 +64,16 @@ func NewConfigHook(groupName, jobName string, job config.HookJob, event string,
 	}
 }
 
+// Manifest describes the job: it runs a shell command for its configured
+// event and can block through its exit code or a Cursor-style response
+func (h *ConfigHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{h.event}
+	m.DefaultEvent = h.event
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands}
+	return m
+}
+
 // CursorHookResponse represents the JSON response format from Cursor-compatible hooks
 // Spec: https://cursor.com/docs/agent/hooks
 type CursorHookResponse struct {
@@ -54,6 +89,8 @@ type hookExecutionResult struct {
 	stdout   string
 	stderr   string
 	err      error
+	// violation explains how the job's sandbox stopped it
+	violation string
 }
 
 // parseCursorResponse attempts to parse JSON output from a hook script
@@ -95,51 +132,118 @@ func (h *ConfigHook) Run() error {
 		// For events not supported by cchooks, just no-op runner
 	}
 
-	runner := h.Context().RunnerFactory(pre, post, raw)
-	runner.Run()
+	runner := h.Runner(pre, post, raw)
+	runner.RunContext(h.RunContext())
 	return nil
 }
 
 func (h *ConfigHook) shouldRun(env map[string]string) (bool, error) {
+	reason, err := h.skipReason(env)
+	return reason == "" && err == nil, err
+}
+
+// skipReason returns which condition keeps the job from running in env, or
+// "" when it runs
+func (h *ConfigHook) skipReason(env map[string]string) (string, error) {
 	if strings.TrimSpace(h.job.Skip) != "" {
 		ok, err := core.EvalExpression(h.job.Skip, env)
 		if err != nil {
-			return false, err
+			return "", err
 		}
 		if ok {
-			return false, nil
+			return "skip matched: " + h.job.Skip, nil
 		}
 	}
 	if strings.TrimSpace(h.job.Only) != "" {
 		ok, err := core.EvalExpression(h.job.Only, env)
 		if err != nil {
-			return false, err
+			return "", err
 		}
 		if !ok {
-			return false, nil
+			return "only not met: " + h.job.Only, nil
 		}
 	}
-	return true, nil
+	return "", nil
 }
 
-func (h *ConfigHook) runCommandWithEnv(env map[string]string) (*hookExecutionResult, error) {
-	// Prepare environment
+func (h *ConfigHook) runCommandWithEnv(ctx context.Context, env map[string]string) (*hookExecutionResult, error) {
+	// Serialize with other sessions sharing the same lock before doing any work
+	if h.lockName != "" {
+		release, err := core.AcquireNamedLock(h.lockName, h.lockWait)
+		if err != nil {
+			return &hookExecutionResult{exitCode: 1, err: err}, err
+		}
+		defer release()
+	}
+	// A serialize job waits for its other instances in this project, e.g.
+	// from parallel tool calls
+	if h.job.Serialize {
+		name := config.JobLockName(h.groupName, h.job.Name)
+		release, err := core.AcquireJobLock(name, time.Duration(h.job.SerializeTimeout)*time.Second)
+		if err != nil {
+			err = fmt.Errorf("serialize: %w", err)
+			return &hookExecutionResult{exitCode: 1, err: err}, err
+		}
+		defer release()
+	}
+
+	// Cut oversized input for jobs that opted in, keeping the full payload on disk
+	input, err := core.GuardInput(h.lastRaw, env, h.job.MaxInputBytes)
+	if err != nil {
+		return &hookExecutionResult{exitCode: 1, err: err}, err
+	}
+	defer input.Cleanup()
+	env = input.Env
+
+	// Prepare environment; a clear_env sandbox inherits only what it allows
 	mergedEnv := os.Environ()
+	if sb := h.job.Sandbox; sb != nil && sb.ClearEnv {
+		mergedEnv = core.SandboxEnv(mergedEnv, sb.EnvAllow)
+	}
 	for k, v := range env {
 		mergedEnv = append(mergedEnv, fmt.Sprintf("%s=%s", k, v))
 	}
+	if id := env[core.SessionIDEnv]; id != "" {
+		// State is a convenience; a job still runs if the store is unavailable
+		if state, err := core.OpenSessionState(id); err == nil {
+			mergedEnv = append(mergedEnv, fmt.Sprintf("%s=%s", core.StateDirEnv, state.Dir()))
+		}
+	}
+	if h.Context().TerseOutput {
+		// Ask the job's tools for uncolored output; env_file and env can
+		// still override it
+		mergedEnv = append(mergedEnv, "NO_COLOR=1")
+	}
+	// env_file values override the process and event environment, and the
+	// job's inline env overrides them; exec keeps the last value of a key
+	if len(h.job.EnvFile) > 0 {
+		fileEnv, err := config.LoadEnvFiles(h.job.EnvFile, h.job.WorkDir, func(key string) (string, bool) {
+			if v, ok := env[key]; ok {
+				return v, true
+			}
+			return os.LookupEnv(key)
+		})
+		if err != nil {
+			return &hookExecutionResult{exitCode: 1, err: err}, err
+		}
+		for k, v := range fileEnv {
+			mergedEnv = append(mergedEnv, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
 	for k, v := range h.job.Env {
 		mergedEnv = append(mergedEnv, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	// Build command (with timeout-aware context)
-	cmdCtx := context.Background()
+	// Build command (with timeout-aware context). Cancelling ctx, e.g. on
+	// Ctrl+C, stops the job and anything it started.
+	cmdCtx := ctx
 	if h.job.Timeout > 0 {
 		var cancel context.CancelFunc
 		cmdCtx, cancel = context.WithTimeout(cmdCtx, time.Duration(h.job.Timeout)*time.Second)
 		defer cancel()
 	}
-	cmd := exec.CommandContext(cmdCtx, "bash", "-lc", h.job.Run) // #nosec G204 -- user-configured command execution is intentional and safe
+	cmd := core.ShellCommand(cmdCtx, h.job.Shell, h.job.Run, mergedEnv)
+	core.ConfigureCancel(cmd)
 
 	// Capture stdout and stderr
 	var stdout, stderr bytes.Buffer
@@ -148,28 +252,40 @@ func (h *ConfigHook) runCommandWithEnv(env map[string]string) (*hookExecutionRes
 
 	// If we have the original raw JSON for this event, pass it to child stdin so
 	// nested blues-traveler invocations can consume it.
-	if h.lastRaw != "" {
-		cmd.Stdin = strings.NewReader(h.lastRaw)
+	if input.Stdin != "" {
+		cmd.Stdin = strings.NewReader(input.Stdin)
 	}
 	if h.job.WorkDir != "" {
 		cmd.Dir = h.job.WorkDir
 	}
-	cmd.Env = mergedEnv
+	if h.job.Sandbox != nil {
+		workdir := cmd.Dir
+		if workdir == "" {
+			workdir = "."
+		}
+		if err := core.SandboxCommand(cmd, h.job.Sandbox, workdir); err != nil {
+			return &hookExecutionResult{exitCode: 1, err: err}, err
+		}
+	}
 
 	// Run and capture result
+	err = cmd.Run()
 	result := &hookExecutionResult{
-		stdout: stdout.String(),
-		stderr: stderr.String(),
+		stdout:    stdout.String(),
+		stderr:    stderr.String(),
+		err:       err,
+		violation: core.SandboxViolation(h.job.Sandbox, err, stderr.String()),
 	}
 
-	err := cmd.Run()
-	result.err = err
-
 	if err != nil {
 		// Translate deadline exceeded into a friendly timeout error
 		if cmdCtx.Err() == context.DeadlineExceeded && h.job.Timeout > 0 {
 			return result, fmt.Errorf("command timed out after %ds", h.job.Timeout)
 		}
+		if ctx.Err() != nil {
+			result.exitCode = 1
+			return result, fmt.Errorf("command cancelled: %w", ctx.Err())
+		}
 		// Try to extract exit code
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			result.exitCode = exitErr.ExitCode()
@@ -247,17 +363,281 @@ func (h PostToolUseHandler) createAllowWithMessagesResponse(userMsg, agentMsg st
 	return core.AllowWithMessages(userMsg, agentMsg)
 }
 
-// executeAndHandleResponse is the common logic for both pre and post handlers
-func (h *ConfigHook) executeAndHandleResponse(ctx context.Context, ev any, handler EventHandler) any {
+// joinChain runs for jobs of a chained event before anything else: it waits
+// for the event's earlier jobs and adds their outputs and BT_OUTPUT to env.
+// Outputs never replace the event's own variables. The returned func records
+// this job's outputs and must run once the job is done, whether it ran or not.
+func (h *ConfigHook) joinChain(ctx context.Context, env map[string]string) func() {
+	if !h.chained {
+		return func() {}
+	}
+	chain, err := core.OpenJobChain(env[core.SessionIDEnv], core.ChainID(h.groupName, h.event, h.lastRaw))
+	if err != nil {
+		h.LogError("chain_unavailable", env["TOOL_NAME"], err)
+		return func() {}
+	}
+
+	if missing := chain.Wait(ctx, h.activeJobs(h.chainAfter), h.chainWait); len(missing) > 0 {
+		h.LogHookEventAt(config.LogLevelWarn, "chain_wait_timeout", env["TOOL_NAME"], nil, map[string]interface{}{"job": h.job.Name, "missing": missing})
+	}
+	for k, v := range chain.Outputs(h.chainAfter) {
+		if _, ok := env[k]; !ok {
+			env[k] = v
+		}
+	}
+	if path, err := chain.PrepareOutput(h.job.Name); err == nil {
+		env[core.OutputEnv] = path
+	}
+	return func() {
+		if err := chain.Finish(h.job.Name); err != nil {
+			h.LogError("chain_output", env["TOOL_NAME"], err)
+		}
+	}
+}
+
+// activeJobs drops the group's jobs that are disabled or snoozed. They never
+// start, so nothing would mark them done.
+func (h *ConfigHook) activeJobs(jobs []string) []string {
+	var active []string
+	for _, job := range jobs {
+		key := fmt.Sprintf("config:%s:%s", h.groupName, job)
+		if checker := h.Context().SettingsChecker; checker != nil && !checker(key) {
+			continue
+		}
+		if _, snoozed := config.ActiveSnooze(key); snoozed {
+			continue
+		}
+		active = append(active, job)
+	}
+	return active
+}
+
+// enterLifecycle runs for jobs of an event with before_all or after_all,
+// before anything else. It runs before_all, or waits for the job that does.
+// skip is set, to the reason, when before_all failed and the job must not
+// run; report is true in the one job that should surface it. The returned
+// func marks the job done and runs after_all when it was the last; it
+// returns the after_all failure to report, or "".
+func (h *ConfigHook) enterLifecycle(ctx context.Context, env map[string]string) (skip string, report bool, leave func() string) {
+	leave = func() string { return "" }
+	if h.beforeAll == nil && h.afterAll == nil {
+		return "", false, leave
+	}
+	run, err := core.OpenEventRun(env[core.SessionIDEnv], core.ChainID(h.groupName, h.event, h.lastRaw))
+	if err != nil {
+		h.LogError("lifecycle_unavailable", env["TOOL_NAME"], err)
+		return "", false, leave
+	}
+
+	if h.beforeAll != nil {
+		wait := defaultChainWait
+		if h.beforeAll.Timeout > 0 {
+			wait = time.Duration(h.beforeAll.Timeout) * time.Second
+		}
+		result, ran, err := run.Before(ctx, wait, func() core.LifecycleResult {
+			return h.runLifecycle(ctx, "before_all", h.beforeAll, env)
+		})
+		switch {
+		case h.beforeAll.Continues():
+		case err != nil:
+			// Nobody else knows this job gave up waiting
+			skip, report = err.Error(), true
+		case !result.OK:
+			skip, report = result.Output, ran
+		}
+		if skip != "" {
+			h.LogHookEventAt(config.LogLevelDebug, "job_skipped", env["TOOL_NAME"], nil, map[string]interface{}{"job": h.job.Name, "reason": "before_all failed"})
+		}
+	}
+
+	if h.afterAll == nil {
+		return skip, report, leave
+	}
+	// The job may add chain outputs to env; after_all sees the event's own
+	env = maps.Clone(env)
+	leave = func() string {
+		result, ran := run.Leave(h.job.Name, h.activeJobs(h.eventJobs), func() core.LifecycleResult {
+			return h.runLifecycle(ctx, "after_all", h.afterAll, env)
+		})
+		if !ran || result.OK || h.afterAll.Continues() {
+			return ""
+		}
+		return result.Output
+	}
+	return skip, report, leave
+}
+
+// runLifecycle runs a before_all or after_all command with the event's
+// environment and logs the outcome
+func (h *ConfigHook) runLifecycle(ctx context.Context, name string, lc *config.LifecycleCommand, env map[string]string) core.LifecycleResult {
+	start := time.Now()
+	cmdCtx := ctx
+	if lc.Timeout > 0 {
+		var cancel context.CancelFunc
+		cmdCtx, cancel = context.WithTimeout(cmdCtx, time.Duration(lc.Timeout)*time.Second)
+		defer cancel()
+	}
+	cmdEnv := os.Environ()
+	for k, v := range env {
+		cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range lc.Env {
+		cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd := core.ShellCommand(cmdCtx, "", lc.Run, cmdEnv)
+	core.ConfigureCancel(cmd)
+	cmd.Dir = lc.WorkDir
+	if h.lastRaw != "" {
+		cmd.Stdin = strings.NewReader(h.lastRaw)
+	}
+	out, err := cmd.CombinedOutput()
+
+	result := core.LifecycleResult{OK: err == nil, Output: clipOutput(strings.TrimSpace(string(out)))}
+	details := map[string]interface{}{
+		"group":       h.groupName,
+		"event":       h.event,
+		"job":         h.job.Name,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	switch {
+	case err == nil:
+		if !h.quietSuccess() {
+			h.LogHookEventAt(config.LogLevelInfo, name+"_succeeded", env["TOOL_NAME"], nil, details)
+		}
+		return result
+	case cmdCtx.Err() == context.DeadlineExceeded && lc.Timeout > 0:
+		err = fmt.Errorf("timed out after %ds", lc.Timeout)
+	}
+	if result.Output == "" {
+		result.Output = err.Error()
+	} else {
+		result.Output = fmt.Sprintf("%v: %s", err, result.Output)
+	}
+	details["command"] = lc.Run
+	details["error"] = result.Output
+	level := config.LogLevelError
+	if lc.Continues() {
+		level = config.LogLevelWarn
+	}
+	h.LogHookEventAt(level, name+"_failed", env["TOOL_NAME"], nil, details)
+	return result
+}
+
+// blocksOrAsks reports whether a Pre/PostToolUse response stops the tool
+// or asks first
+func blocksOrAsks(resp any) bool {
+	switch core.SummarizeResponse(resp).Decision {
+	case cchooks.PreToolUseBlock, core.PreToolUseAsk:
+		return true
+	}
+	return false
+}
+
+// executeAndHandleResponse is the common logic for both pre and post handlers.
+// When a tool touched several files (MultiEdit, or future multi-file tools),
+// the job runs once per file with TOOL_FILE/TOOL_OUTPUT_FILE set to that file;
+// the first blocking or asking result wins.
+func (h *ConfigHook) executeAndHandleResponse(ctx context.Context, ev any, handler EventHandler) (resp any) {
 	c := handler.buildContext(ctx, ev)
 	env := h.envProvider.GetEnvironment(handler.getEventName(), c)
+	skip, report, leave := h.enterLifecycle(ctx, env)
+	defer func() {
+		// The job's own block or ask takes precedence over teardown trouble
+		if msg := leave(); msg != "" && !blocksOrAsks(resp) {
+			resp = handler.createBlockResponse(fmt.Sprintf("Hook group '%s' after_all failed", h.groupName), msg)
+		}
+	}()
+	defer h.joinChain(ctx, env)()
+	if skip != "" {
+		if report {
+			return handler.createBlockResponse(fmt.Sprintf("Hook group '%s' before_all failed", h.groupName), skip)
+		}
+		return handler.createAllowResponse()
+	}
+	if h.job.Scope == config.JobScopeGit {
+		gitEnv, ok := h.gitScopeEnvironment(env)
+		if !ok {
+			return handler.createAllowResponse()
+		}
+		if resp, proceed := h.respondForEnv(ctx, gitEnv, handler); !proceed || resp != nil {
+			return resp
+		}
+		return handler.createAllowResponse()
+	}
+	files, _ := c["files_changed"].([]string)
+
+	var withMessages any
+	for _, fileEnv := range core.PerFileEnvironments(env, files) {
+		resp, proceed := h.respondForEnv(ctx, fileEnv, handler)
+		if !proceed {
+			return resp
+		}
+		if resp != nil {
+			withMessages = resp
+		}
+	}
+	if withMessages != nil {
+		return withMessages
+	}
+	return handler.createAllowResponse()
+}
 
-	result, err := h.executeIfShouldRunWithResult(env)
+// gitScopeEnvironment adds GIT_CHANGED_FILES (filtered by the job's globs)
+// and GIT_BASE to env for a git-scoped job. ok is false when there is nothing
+// to run on: no matching changes, or not a git repository.
+func (h *ConfigHook) gitScopeEnvironment(env map[string]string) (map[string]string, bool) {
+	dir := env["PROJECT_ROOT"]
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	base, err := core.SessionGitBase(dir, env[core.SessionIDEnv])
+	if err != nil {
+		h.LogHookEventAt(config.LogLevelDebug, "job_skipped", "", nil, map[string]interface{}{"job": h.job.Name, "reason": err.Error()})
+		return nil, false
+	}
+	files, err := core.GitChangedFiles(dir, base)
+	if err != nil {
+		h.LogError("git_changed_files", env["TOOL_NAME"], err)
+		return nil, false
+	}
+	files = core.FilterFilesByGlob(files, h.job.Glob)
+	if len(files) == 0 {
+		return nil, false
+	}
+	out := make(map[string]string, len(env)+2)
+	for k, v := range env {
+		out[k] = v
+	}
+	out[core.GitChangedFilesEnv] = strings.Join(files, " ")
+	out[core.GitBaseEnv] = base
+	return out, true
+}
+
+// respondForEnv runs the job for one environment. proceed is false when the
+// returned response blocks or asks; a nil response with proceed means a
+// plain allow.
+func (h *ConfigHook) respondForEnv(ctx context.Context, env map[string]string, handler EventHandler) (resp any, proceed bool) {
+	start := time.Now()
+	result, err := h.executeIfShouldRunWithResult(ctx, env)
+	defer func() { h.logJobOutcome(env, result, err, time.Since(start), proceed) }()
+	if h.job.OnlyNewFindings && result != nil {
+		if resp, proceed, handled := h.respondWithNewFindings(env, result, handler); handled {
+			return resp, proceed
+		}
+	}
+	if result != nil && result.violation != "" {
+		userMsg := fmt.Sprintf("Hook '%s' was stopped by its sandbox", h.job.Name)
+		agentMsg := result.violation
+		if stderr := strings.TrimSpace(result.stderr); stderr != "" {
+			agentMsg += "\nstderr: " + clipOutput(stderr)
+		}
+		return handler.createBlockResponse(userMsg, agentMsg), false
+	}
 	if err != nil {
 		// User-friendly message + technical details for agent
 		userMsg := fmt.Sprintf("Hook '%s' execution failed", h.job.Name)
 		agentMsg := err.Error()
-		return handler.createBlockResponse(userMsg, agentMsg)
+		return handler.createBlockResponse(userMsg, agentMsg), false
 	}
 
 	// Try to parse Cursor JSON response
@@ -268,12 +648,12 @@ func (h *ConfigHook) executeAndHandleResponse(ctx context.Context, ev any, handl
 		if parseErr != nil {
 			userMsg := fmt.Sprintf("Hook '%s' returned invalid JSON", h.job.Name)
 			agentMsg := fmt.Sprintf("Hook output parsing failed: %v. Output: %s", parseErr, result.stdout)
-			return handler.createBlockResponse(userMsg, agentMsg)
+			return handler.createBlockResponse(userMsg, agentMsg), false
 		}
 
 		// Rule 2: Partial JSON = proceed with available fields
 		if cursorResp != nil {
-			return h.handleCursorResponse(cursorResp, handler)
+			return h.handleCursorResponse(cursorResp, handler), cursorAllows(cursorResp)
 		}
 	}
 
@@ -281,10 +661,118 @@ func (h *ConfigHook) executeAndHandleResponse(ctx context.Context, ev any, handl
 	if result != nil && result.exitCode != 0 {
 		userMsg := fmt.Sprintf("Hook '%s' failed with exit code %d", h.job.Name, result.exitCode)
 		agentMsg := fmt.Sprintf("Exit code: %d, stderr: %s", result.exitCode, result.stderr)
-		return handler.createBlockResponse(userMsg, agentMsg)
+		return handler.createBlockResponse(userMsg, agentMsg), false
 	}
 
-	return handler.createAllowResponse()
+	return nil, true
+}
+
+// respondWithNewFindings reports only output lines that the previous run of
+// this job for the same file did not produce. handled is false when the
+// result is not a plain lint outcome (a timeout, lock failure, or Cursor JSON
+// reply), leaving the normal response rules to apply.
+func (h *ConfigHook) respondWithNewFindings(env map[string]string, result *hookExecutionResult, handler EventHandler) (resp any, proceed, handled bool) {
+	subject := h.Key() + "|" + env["TOOL_FILE"]
+	if result.exitCode == 0 {
+		// A clean run resets the baseline so fixed issues count as new if they return
+		_, _ = core.NewFindings(subject, nil)
+		return nil, true, false
+	}
+	var exitErr *exec.ExitError
+	var cachedErr cachedExitError
+	if (!errors.As(result.err, &exitErr) && !errors.As(result.err, &cachedErr)) || result.exitCode < 0 || strings.HasPrefix(strings.TrimSpace(result.stdout), "{") {
+		return nil, false, false
+	}
+
+	findings := core.ParseFindings(result.stdout + "\n" + result.stderr)
+	fresh, err := core.NewFindings(subject, findings)
+	if err != nil {
+		return nil, false, false
+	}
+	if len(fresh) == 0 {
+		return nil, true, true
+	}
+
+	lines := make([]string, 0, len(fresh))
+	for _, f := range fresh {
+		lines = append(lines, f.Text)
+	}
+	userMsg := fmt.Sprintf("Hook '%s' found %d new issue(s)", h.job.Name, len(fresh))
+	agentMsg := fmt.Sprintf("%d new finding(s) since the last run (%d pre-existing omitted):\n%s",
+		len(fresh), len(findings)-len(fresh), strings.Join(lines, "\n"))
+	return handler.createBlockResponse(userMsg, agentMsg), false, true
+}
+
+// maxLoggedOutput caps the stdout/stderr recorded for a failed job
+const maxLoggedOutput = 4096
+
+// logJobOutcome records one run of the job. Failures log at error level with
+// the command, output and timing; successful and skipped runs log at info and
+// debug level, or not at all when quiet success is on.
+func (h *ConfigHook) logJobOutcome(env map[string]string, result *hookExecutionResult, err error, elapsed time.Duration, proceed bool) {
+	details := map[string]interface{}{
+		"group":       h.groupName,
+		"job":         h.job.Name,
+		"event":       h.event,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if file := env["TOOL_FILE"]; file != "" {
+		details["file"] = file
+	}
+	toolName := env["TOOL_NAME"]
+
+	switch {
+	case !proceed && (err != nil || (result != nil && result.exitCode != 0)):
+		details["command"] = h.job.Run
+		if result != nil {
+			details["exit_code"] = result.exitCode
+			details["stdout"] = clipOutput(result.stdout)
+			details["stderr"] = clipOutput(result.stderr)
+		}
+		if err != nil {
+			details["error"] = err.Error()
+		}
+		h.LogHookEventAt(config.LogLevelError, "job_failed", toolName, nil, details)
+	case !proceed:
+		if result != nil {
+			details["stdout"] = clipOutput(result.stdout)
+		}
+		h.LogHookEventAt(config.LogLevelWarn, "job_blocked", toolName, nil, details)
+	case h.quietSuccess():
+	case result == nil:
+		h.LogHookEventAt(config.LogLevelDebug, "job_skipped", toolName, nil, details)
+	default:
+		details["exit_code"] = result.exitCode
+		h.LogHookEventAt(config.LogLevelInfo, "job_succeeded", toolName, nil, details)
+	}
+}
+
+// quietSuccess reports whether successful runs of this job are left unlogged
+func (h *ConfigHook) quietSuccess() bool {
+	if h.job.QuietSuccess != nil {
+		return *h.job.QuietSuccess
+	}
+	return h.Context().QuietSuccess
+}
+
+// clipOutput keeps the tail of long command output, where errors usually are
+func clipOutput(out string) string {
+	if len(out) <= maxLoggedOutput {
+		return out
+	}
+	return "..." + out[len(out)-maxLoggedOutput:]
+}
+
+// cursorAllows reports whether a Cursor response lets execution continue
+func cursorAllows(resp *CursorHookResponse) bool {
+	if resp.Continue != nil && !*resp.Continue {
+		return false
+	}
+	switch strings.ToLower(resp.Permission) {
+	case "allow", "":
+		return true
+	}
+	return false
 }
 
 func (h *ConfigHook) preHandler(ctx context.Context, ev *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
@@ -296,7 +784,7 @@ func (h *ConfigHook) postHandler(ctx context.Context, ev *cchooks.PostToolUseEve
 }
 
 // executeIfShouldRunWithResult checks if the hook should run and executes it, returning the result
-func (h *ConfigHook) executeIfShouldRunWithResult(env map[string]string) (*hookExecutionResult, error) {
+func (h *ConfigHook) executeIfShouldRunWithResult(ctx context.Context, env map[string]string) (*hookExecutionResult, error) {
 	ok, err := h.shouldRun(env)
 	if err != nil {
 		return nil, fmt.Errorf("config hook error: %w", err)
@@ -304,13 +792,99 @@ func (h *ConfigHook) executeIfShouldRunWithResult(env map[string]string) (*hookE
 	if !ok {
 		return nil, nil
 	}
-	result, err := h.runCommandWithEnv(env)
+	result, err := h.runCommandCached(ctx, env)
 	if err != nil {
 		return result, fmt.Errorf("job '%s' failed: %w", h.job.Name, err)
 	}
 	return result, nil
 }
 
+// runCommandCached is runCommandWithEnv for a job that may set cache: while
+// the inputs it is keyed on are unchanged, the last outcome is replayed
+// instead of running the command
+func (h *ConfigHook) runCommandCached(ctx context.Context, env map[string]string) (*hookExecutionResult, error) {
+	if h.job.Cache == nil {
+		return h.runCommandCaptured(ctx, env)
+	}
+	digest := core.JobCacheDigest(h.Key(), h.job.Run, h.job.Cache.Key, env)
+	if outcome, hit := core.LoadJobOutcome(digest, h.job.Cache.TTLDuration()); hit {
+		h.LogHookEventAt(config.LogLevelDebug, "job_cached", env["TOOL_NAME"], nil, map[string]interface{}{"job": h.job.Name, "exit_code": outcome.ExitCode, "saved_at": outcome.SavedAt})
+		return cachedResult(outcome)
+	}
+	result, err := h.runCommandCaptured(ctx, env)
+	if cacheable(result, err) {
+		if serr := core.SaveJobOutcome(digest, core.JobOutcome{ExitCode: result.exitCode, Stdout: result.stdout, Stderr: result.stderr}); serr != nil {
+			h.LogError("job_cache", env["TOOL_NAME"], serr)
+		}
+	}
+	return result, err
+}
+
+// runCommandCaptured is runCommandWithEnv that, for a capture_output job,
+// keeps the run's output as an artifact. Failing to store it is logged and
+// doesn't change the outcome.
+func (h *ConfigHook) runCommandCaptured(ctx context.Context, env map[string]string) (*hookExecutionResult, error) {
+	if !h.job.CaptureOutput {
+		return h.runCommandWithEnv(ctx, env)
+	}
+	start := time.Now()
+	result, err := h.runCommandWithEnv(ctx, env)
+	artifact := core.JobArtifact{
+		Group:      h.groupName,
+		Job:        h.job.Name,
+		Time:       start,
+		Event:      h.event,
+		Tool:       env["TOOL_NAME"],
+		File:       env["TOOL_FILE"],
+		Command:    h.job.Run,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	var stdout, stderr string
+	if result != nil {
+		artifact.ExitCode = result.exitCode
+		stdout, stderr = result.stdout, result.stderr
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		artifact.Error = err.Error()
+	}
+	if _, serr := core.SaveJobArtifact(artifact, stdout, stderr, config.ResolveLogRotationConfig()); serr != nil {
+		h.LogError("capture_output", env["TOOL_NAME"], serr)
+	}
+	return result, err
+}
+
+// cacheable reports whether a run's outcome may be replayed: the command ran
+// to completion, as opposed to timing out, being cancelled, being stopped by
+// its sandbox, or failing to start or take a lock
+func cacheable(result *hookExecutionResult, err error) bool {
+	if result == nil || result.violation != "" {
+		return false
+	}
+	if err == nil {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.Is(err, result.err) && errors.As(err, &exitErr)
+}
+
+// cachedExitError stands in for the exec.ExitError of a cached failed run
+type cachedExitError struct{ code int }
+
+func (e cachedExitError) Error() string {
+	return fmt.Sprintf("exit status %d (cached)", e.code)
+}
+
+// cachedResult replays a cached outcome the way the run returned it
+func cachedResult(outcome *core.JobOutcome) (*hookExecutionResult, error) {
+	result := &hookExecutionResult{exitCode: outcome.ExitCode, stdout: outcome.Stdout, stderr: outcome.Stderr}
+	if outcome.ExitCode == 0 {
+		return result, nil
+	}
+	result.err = cachedExitError{code: outcome.ExitCode}
+	return result, result.err
+}
+
 // resolveMessages fills in default messages if user/agent messages are empty
 func (h *ConfigHook) resolveMessages(userMsg, agentMsg, defaultMsg string) (string, string) {
 	if userMsg == "" {
@@ -371,41 +945,82 @@ func (h *ConfigHook) handleCursorResponse(resp *CursorHookResponse, handler Even
 // rawHandler handles unsupported events (e.g., UserPromptSubmit) by parsing the raw JSON
 // and executing the configured job when the event name matches this hook's event.
 func (h *ConfigHook) rawHandler() func(context.Context, string) *cchooks.RawResponse {
-	return func(_ context.Context, rawJSON string) *cchooks.RawResponse {
-		var rawEvent map[string]any
-		if err := json.Unmarshal([]byte(rawJSON), &rawEvent); err != nil {
+	return func(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+		ev, err := core.ParseRawEvent(rawJSON)
+		if err != nil {
 			return nil
 		}
-		evName, _ := rawEvent["hook_event_name"].(string)
-		if evName == "" || evName != h.event {
-			return nil
+		h.handleRawEvent(ctx, ev)
+		return nil
+	}
+}
+
+// handleRawEvent runs the job for an event cchooks has no type for. On top
+// of the usual variables the job gets every payload field as
+// BT_EVENT_<FIELD> and the payload itself in the BT_EVENT_JSON file.
+func (h *ConfigHook) handleRawEvent(ctx context.Context, ev *core.RawEvent) {
+	if ev.Name == "" || ev.Name != h.event {
+		return
+	}
+	// Store raw JSON to feed to any nested commands launched by this hook
+	h.lastRaw = ev.JSON
+	if ev.Name == string(core.PreToolUseEvent) || ev.Name == string(core.PostToolUseEvent) {
+		// The typed handlers run the job for these; running it here too
+		// would run it twice
+		return
+	}
+	env := h.rawEventEnvironment(ev)
+	// Raw events cannot block, so lifecycle failures are only logged
+	skip, _, leave := h.enterLifecycle(ctx, env)
+	defer leave()
+	defer h.joinChain(ctx, env)()
+	if skip != "" {
+		return
+	}
+	if ev.Name == string(core.SessionStartEvent) && ev.SessionID != "" {
+		// Pin the base git-scoped jobs measure changes from
+		_, _ = core.SessionGitBase(env["PROJECT_ROOT"], ev.SessionID)
+	}
+	if h.job.Scope == config.JobScopeGit {
+		gitEnv, ok := h.gitScopeEnvironment(env)
+		if !ok {
+			return
 		}
-		// Store raw JSON to feed to any nested commands launched by this hook
-		h.lastRaw = rawJSON
-		// Build minimal context for env provider
-		ctxData := map[string]any{}
-		if v, ok := rawEvent["tool_name"].(string); ok {
-			ctxData["tool_name"] = v
+		env = gitEnv
+	}
+	if ok, err := h.shouldRun(env); err == nil && ok {
+		start := time.Now()
+		path, cleanup, err := ev.WriteFile()
+		var result *hookExecutionResult
+		if err == nil {
+			env[core.EventJSONEnv] = path
+			result, err = h.runCommandCached(ctx, env)
+			cleanup()
+		} else {
+			result = &hookExecutionResult{exitCode: 1, err: err}
 		}
-		if v, ok := rawEvent["user_prompt"].(string); ok {
-			ctxData["user_prompt"] = v
+		h.logJobOutcome(env, result, err, time.Since(start), err == nil && result.exitCode == 0)
+	}
+	if ev.Name == string(core.SessionEndEvent) && ev.SessionID != "" {
+		if state, err := core.OpenSessionState(ev.SessionID); err == nil {
+			_ = state.MarkEnded()
 		}
-		env := h.envProvider.GetEnvironment(evName, ctxData)
-		if ok, err := h.shouldRun(env); err == nil && ok {
-			_, _ = h.runCommandWithEnv(env)
+	}
+}
+
+// rawEventEnvironment is the event environment plus a BT_EVENT_<FIELD>
+// variable per payload field; the event's own variables win on a clash
+func (h *ConfigHook) rawEventEnvironment(ev *core.RawEvent) map[string]string {
+	env := h.envProvider.GetEnvironment(ev.Name, ev.Context())
+	for k, v := range ev.Environment() {
+		if _, taken := env[k]; !taken {
+			env[k] = v
 		}
-		return nil
 	}
+	return env
 }
 
 func (h *ConfigHook) processRawFromStdin() error {
-	data, err := io.ReadAll(os.Stdin)
-	if err != nil {
-		return nil // fail open
-	}
-	handler := h.rawHandler()
-	if handler != nil {
-		handler(context.Background(), string(data))
-	}
+	core.RunRawEvent(h.RunContext(), os.Stdin, h.handleRawEvent)
 	return nil
 }
@@ -0,0 +1,222 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// DryRun reports whether the job would run for payload, with its command
+// expanded and its environment, without running it. before_all, after_all,
+// chains and locks are described in notes but not entered.
+func (h *ConfigHook) DryRun(ctx context.Context, payload []byte) (*core.DryRunReport, error) {
+	report := &core.DryRunReport{}
+	if payload == nil {
+		payload = h.samplePayload()
+		report.Synthesized = true
+	}
+	report.Payload = payload
+
+	var rawEvent map[string]any
+	if err := json.Unmarshal(payload, &rawEvent); err != nil {
+		return nil, fmt.Errorf("invalid event JSON: %w", err)
+	}
+	report.Event, _ = rawEvent["hook_event_name"].(string)
+	if report.Event == "" {
+		report.Event = h.event
+	}
+	report.Tool, _ = rawEvent["tool_name"].(string)
+	if report.Event != h.event {
+		report.Notes = append(report.Notes, fmt.Sprintf("The job handles %s, so it ignores %s events.", h.event, report.Event))
+		return report, nil
+	}
+
+	env, files, err := h.dryRunEnvironment(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	report.Notes = h.dryRunNotes(files)
+
+	envs := core.PerFileEnvironments(env, files)
+	if h.job.Scope == config.JobScopeGit {
+		gitEnv, ok := h.gitScopeEnvironment(env)
+		if !ok {
+			report.Steps = append(report.Steps, core.DryRunStep{Reason: "scope: git found no changed files matching glob (or this is not a git repository)", Env: env})
+			return report, nil
+		}
+		envs = []map[string]string{gitEnv}
+	}
+	for _, e := range envs {
+		step := core.DryRunStep{Env: h.dryRunJobEnv(e)}
+		if len(envs) > 1 {
+			step.File = e["TOOL_FILE"]
+		}
+		reason, err := h.skipReason(e)
+		switch {
+		case err != nil:
+			// The real run turns a bad condition into a block
+			step.Reason = "condition error, the job would block: " + err.Error()
+		case reason != "":
+			step.Reason = reason
+		default:
+			step.WouldRun = true
+		}
+		step.Command = expandDryRunCommand(h.job.Run, step.Env)
+		report.Steps = append(report.Steps, step)
+	}
+	return report, nil
+}
+
+// dryRunEnvironment builds the event environment as the job's handler
+// would, returning the files the event touched
+func (h *ConfigHook) dryRunEnvironment(ctx context.Context, payload []byte) (map[string]string, []string, error) {
+	var c map[string]any
+	switch h.event {
+	case string(core.PreToolUseEvent):
+		var ev cchooks.PreToolUseEvent
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s event JSON: %w", h.event, err)
+		}
+		c = PreToolUseHandler{}.buildContext(ctx, &ev)
+	case string(core.PostToolUseEvent):
+		var ev cchooks.PostToolUseEvent
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s event JSON: %w", h.event, err)
+		}
+		c = PostToolUseHandler{}.buildContext(ctx, &ev)
+	default:
+		ev, err := core.ParseRawEvent(string(payload))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s event JSON: %w", h.event, err)
+		}
+		ev.Name = h.event
+		env := h.rawEventEnvironment(ev)
+		env[core.EventJSONEnv] = "(temp file holding the event payload)"
+		return env, nil, nil
+	}
+	files, _ := c["files_changed"].([]string)
+	return h.envProvider.GetEnvironment(h.event, c), files, nil
+}
+
+// dryRunJobEnv adds the job's env and env_file entries to the event
+// environment. env_file values are not shown, as those files usually
+// hold secrets.
+func (h *ConfigHook) dryRunJobEnv(env map[string]string) map[string]string {
+	out := make(map[string]string, len(env)+len(h.job.Env))
+	for k, v := range env {
+		out[k] = v
+	}
+	if len(h.job.EnvFile) > 0 {
+		fileEnv, err := config.LoadEnvFiles(h.job.EnvFile, h.job.WorkDir, func(key string) (string, bool) {
+			if v, ok := env[key]; ok {
+				return v, true
+			}
+			return os.LookupEnv(key)
+		})
+		if err == nil {
+			for k := range fileEnv {
+				out[k] = "(from env_file)"
+			}
+		}
+	}
+	for k, v := range h.job.Env {
+		out[k] = v
+	}
+	return out
+}
+
+// dryRunNotes describes what happens around the job's command
+func (h *ConfigHook) dryRunNotes(files []string) []string {
+	var notes []string
+	if !h.IsEnabled() {
+		notes = append(notes, "The job is disabled in the config; 'hooks run' would allow without running it.")
+	}
+	if len(h.job.Glob) > 0 && h.job.Scope != config.JobScopeGit && len(files) > 0 {
+		matched := core.FilterFilesByGlob(files, h.job.Glob)
+		notes = append(notes, fmt.Sprintf("glob %v matches %d of the event's %d file(s); only scope: git jobs and exported scripts filter on it.", h.job.Glob, len(matched), len(files)))
+	}
+	if h.beforeAll != nil {
+		notes = append(notes, "before_all would run first, once per event: "+h.beforeAll.Run)
+	}
+	if h.afterAll != nil {
+		notes = append(notes, "after_all would run after the event's last job: "+h.afterAll.Run)
+	}
+	if h.chained && len(h.chainAfter) > 0 {
+		notes = append(notes, "chain: waits for "+strings.Join(h.chainAfter, ", ")+" first")
+	}
+	if h.lockName != "" {
+		notes = append(notes, "lock: runs while holding "+h.lockName)
+	}
+	if h.job.Serialize {
+		notes = append(notes, "serialize: one instance at a time in this project")
+	}
+	if shell := h.job.Shell; shell != "" && shell != config.ShellBash {
+		notes = append(notes, "shell: runs under "+shell)
+	}
+	if h.job.WorkDir != "" {
+		notes = append(notes, "workdir: "+h.job.WorkDir)
+	}
+	return notes
+}
+
+// samplePayload makes up an event for the job's event type. Tool events
+// use Bash, or a Write to a file matching the job's first glob.
+func (h *ConfigHook) samplePayload() []byte {
+	ev := map[string]any{"session_id": "dry-run", "hook_event_name": h.event}
+	if wd, err := os.Getwd(); err == nil {
+		ev["cwd"] = wd
+	}
+	switch h.event {
+	case string(core.PreToolUseEvent), string(core.PostToolUseEvent):
+		if len(h.job.Glob) > 0 {
+			ev["tool_name"] = "Write"
+			ev["tool_input"] = map[string]any{"file_path": sampleFileForGlob(h.job.Glob[0]), "content": ""}
+		} else {
+			ev["tool_name"] = "Bash"
+			ev["tool_input"] = map[string]any{"command": "true"}
+		}
+		if h.event == string(core.PostToolUseEvent) {
+			ev["tool_response"] = map[string]any{"success": true}
+		}
+	case string(core.UserPromptSubmitEvent):
+		ev["user_prompt"] = "dry run"
+	}
+	data, _ := json.Marshal(ev)
+	return data
+}
+
+// sampleFileForGlob returns a file name the glob matches, for the common
+// patterns such as *.go or src/**/*.ts
+func sampleFileForGlob(glob string) string {
+	if strings.ContainsAny(glob, "[{") {
+		return "example.txt"
+	}
+	return strings.NewReplacer("**/", "", "**", "example", "*", "example", "?", "x").Replace(glob)
+}
+
+// expandDryRunCommand expands the variables in run that env or the process
+// environment set. Others are left for the shell, as written.
+func expandDryRunCommand(run string, env map[string]string) string {
+	return os.Expand(run, func(name string) string {
+		if v, ok := env[name]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		if len(name) == 1 && !isLetter(name[0]) {
+			return "$" + name
+		}
+		return "${" + name + "}"
+	})
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestConfigHookDryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	job := config.HookJob{
+		Name: "fmt",
+		Run:  "gofmt -l ${TOOL_OUTPUT_FILE} > $REPORT; echo $UNSET_IN_DRY_RUN",
+		Glob: []string{"*.go"},
+		Only: `${TOOL_NAME} == "Edit" || ${TOOL_NAME} == "Write" || ${TOOL_NAME} == "MultiEdit"`,
+		Skip: `${TOOL_OUTPUT_FILE} matches *_test.go`,
+		Env:  map[string]string{"REPORT": "fmt.txt"},
+	}
+	hook := NewConfigHook("go", "fmt", job, string(core.PostToolUseEvent), core.TestHookContext(nil)).(*ConfigHook)
+	ctx := context.Background()
+
+	report, err := hook.DryRun(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Synthesized || report.Tool != "Write" || len(report.Steps) != 1 {
+		t.Fatalf("synthesized report = %+v", report)
+	}
+	step := report.Steps[0]
+	if !step.WouldRun {
+		t.Errorf("expected the synthesized Write to example.go to run, got %q", step.Reason)
+	}
+	if step.Command != "gofmt -l example.go > fmt.txt; echo ${UNSET_IN_DRY_RUN}" {
+		t.Errorf("Command = %q", step.Command)
+	}
+	if step.Env["REPORT"] != "fmt.txt" || step.Env["TOOL_NAME"] != "Write" {
+		t.Errorf("Env = %v", step.Env)
+	}
+
+	multi := `{"hook_event_name":"PostToolUse","tool_name":"MultiEdit","tool_input":{"file_path":"a_test.go","edits":[]},"tool_response":{}}`
+	report, err = hook.DryRun(ctx, []byte(multi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Steps) != 1 || report.Steps[0].WouldRun || !strings.HasPrefix(report.Steps[0].Reason, "skip matched") {
+		t.Errorf("expected the skip condition to match a_test.go, got %+v", report.Steps)
+	}
+
+	bash := `{"hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}`
+	report, err = hook.DryRun(ctx, []byte(bash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Steps) != 1 || report.Steps[0].WouldRun || !strings.HasPrefix(report.Steps[0].Reason, "only not met") {
+		t.Errorf("expected the only condition to reject Bash, got %+v", report.Steps)
+	}
+
+	report, err = hook.DryRun(ctx, []byte(`{"hook_event_name":"Stop"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Steps) != 0 || len(report.Notes) != 1 || !strings.Contains(report.Notes[0], "ignores Stop") {
+		t.Errorf("expected a note for another event, got %+v", report)
+	}
+
+	if _, err := hook.DryRun(ctx, []byte("not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestSampleFileForGlob(t *testing.T) {
+	for glob, want := range map[string]string{
+		"*.go":        "example.go",
+		"src/**/*.ts": "src/example.ts",
+		"Makefile":    "Makefile",
+		"*.{js,ts}":   "example.txt",
+	} {
+		if got := sampleFileForGlob(glob); got != want {
+			t.Errorf("sampleFileForGlob(%q) = %q, want %q", glob, got, want)
+		}
+	}
+}
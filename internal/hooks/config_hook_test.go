@@ -1,8 +1,18 @@
 package hooks
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/brads3290/cchooks"
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
 )
@@ -169,3 +179,677 @@ func (m messageExpectation) assert(t *testing.T, resp *CursorHookResponse) {
 func boolPtr(v bool) *bool {
 	return &v
 }
+
+func TestConfigHook_LockFencesExecution(t *testing.T) {
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	cfg := config.CustomHooksConfig{
+		"infra": &config.HookGroup{Lock: "compose", LockTimeout: 1, Events: map[string]*config.EventConfig{
+			"PreToolUse": {
+				Jobs: []config.HookJob{{Name: "up", Run: "true"}},
+			},
+		}},
+	}
+	factory, ok := buildConfigHookFactories(&cfg)["config:infra:up"]
+	if !ok {
+		t.Fatal("expected factory for config:infra:up")
+	}
+	hook := factory(core.TestHookContext(nil)).(*ConfigHook)
+	if hook.lockName != "compose" || hook.lockWait != time.Second {
+		t.Fatalf("lock settings not propagated: name=%q wait=%v", hook.lockName, hook.lockWait)
+	}
+
+	// Another session holds the lock: the job must fail rather than run concurrently
+	release, err := core.AcquireNamedLock("compose", time.Second)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if _, err := hook.runCommandWithEnv(context.Background(), nil); !errors.Is(err, core.ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout while lock is held, got %v", err)
+	}
+	release()
+
+	result, err := hook.runCommandWithEnv(context.Background(), nil)
+	if err != nil || result.exitCode != 0 {
+		t.Fatalf("expected job to run after release, got result=%+v err=%v", result, err)
+	}
+}
+
+func TestConfigHook_SerializeRunsOneInstanceAtATime(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	cfg := config.CustomHooksConfig{
+		"fmt": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{Name: "go fmt", Run: "true", Serialize: true, SerializeTimeout: 1}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:fmt:go fmt"](core.TestHookContext(nil)).(*ConfigHook)
+
+	// Another instance of the job is running: this one waits, then fails
+	release, err := core.AcquireJobLock(config.JobLockName("fmt", "go fmt"), time.Second)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude", "hooks", "locks", "fmt.go_fmt.lock")); err != nil {
+		t.Fatalf("expected the lock under .claude/hooks/locks: %v", err)
+	}
+	if _, err := hook.runCommandWithEnv(context.Background(), nil); !errors.Is(err, core.ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout while another instance runs, got %v", err)
+	}
+	release()
+
+	result, err := hook.runCommandWithEnv(context.Background(), nil)
+	if err != nil || result.exitCode != 0 {
+		t.Fatalf("expected job to run after release, got result=%+v err=%v", result, err)
+	}
+}
+
+func TestConfigHook_CancelStopsJobAndChildren(t *testing.T) {
+	cfg := config.CustomHooksConfig{
+		"slow": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PreToolUse": {
+				// The sleep is a child of the shell and keeps its output open;
+				// it must be stopped along with the shell
+				Jobs: []config.HookJob{{Name: "wait", Run: "sleep 30 & wait"}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:slow:wait"](core.TestHookContext(nil)).(*ConfigHook)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := hook.runCommandWithEnv(ctx, nil)
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("expected cancelled error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("job kept running %s after cancel", elapsed)
+	}
+}
+
+func TestConfigHook_MaxInputBytesTruncatesPayload(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "seen.txt")
+	cfg := config.CustomHooksConfig{
+		"big": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PreToolUse": {
+				MaxInputBytes: 2048,
+				Jobs: []config.HookJob{{
+					Name: "inspect",
+					Run:  `{ wc -c < /dev/stdin; echo "$BT_PAYLOAD_TRUNCATED"; wc -c < "$BT_PAYLOAD_FILE"; } > ` + out,
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:big:inspect"](core.TestHookContext(nil)).(*ConfigHook)
+	if hook.job.MaxInputBytes != 2048 {
+		t.Fatalf("event max_input_bytes not propagated to the job: %d", hook.job.MaxInputBytes)
+	}
+	hook.lastRaw = `{"hook_event_name":"PreToolUse","tool_name":"Write","tool_input":{"file_path":"a.txt","content":"` + strings.Repeat("x", 100000) + `"}}`
+
+	result, err := hook.runCommandWithEnv(context.Background(), nil)
+	if err != nil || result.exitCode != 0 {
+		t.Fatalf("job failed: %+v %v", result, err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 3 {
+		t.Fatalf("unexpected job output %q", data)
+	}
+	if n, _ := strconv.Atoi(fields[0]); n > 2048 {
+		t.Errorf("stdin was %d bytes, want at most 2048", n)
+	}
+	if fields[1] != "1" {
+		t.Errorf("BT_PAYLOAD_TRUNCATED = %q, want 1", fields[1])
+	}
+	if n, _ := strconv.Atoi(fields[2]); n != len(hook.lastRaw) {
+		t.Errorf("full payload file was %d bytes, want %d", n, len(hook.lastRaw))
+	}
+}
+
+func TestConfigHook_EnvFilePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "seen.txt")
+	if err := os.WriteFile(filepath.Join(dir, "base.env"), []byte("FROM_FILE=base\nSHARED=file\nOVERRIDDEN=base\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "local.env"), []byte("OVERRIDDEN=local\nDERIVED=${FROM_FILE}-${TOOL_NAME}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SHARED", "process")
+	t.Setenv("FROM_PROCESS", "process")
+
+	hook := NewConfigHook("g", "env", config.HookJob{
+		Name:    "env",
+		Run:     `echo "$FROM_FILE $SHARED $OVERRIDDEN $DERIVED $FROM_PROCESS $INLINE" > ` + out,
+		WorkDir: dir,
+		EnvFile: config.EnvFiles{"base.env", "local.env"},
+		Env:     map[string]string{"INLINE": "inline", "OVERRIDDEN": "inline"},
+	}, "PreToolUse", core.TestHookContext(nil)).(*ConfigHook)
+
+	result, err := hook.runCommandWithEnv(context.Background(), map[string]string{"TOOL_NAME": "Bash"})
+	if err != nil || result.exitCode != 0 {
+		t.Fatalf("job failed: %+v %v", result, err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "base file inline base-Bash process inline"; got != want {
+		t.Errorf("job saw %q, want %q", got, want)
+	}
+
+	hook.job.EnvFile = config.EnvFiles{"missing.env"}
+	if _, err := hook.runCommandWithEnv(context.Background(), nil); err == nil {
+		t.Error("expected an error for a missing env_file")
+	}
+}
+
+func TestConfigHook_MultiEditRunsPerFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "files.txt")
+	cfg := config.CustomHooksConfig{
+		"fmt": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{
+					Name: "each",
+					Run:  `echo "$TOOL_OUTPUT_FILE|$FILES_CHANGED" >> ` + out,
+					Skip: "${TOOL_OUTPUT_FILE} matches *.md",
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:fmt:each"](core.TestHookContext(nil)).(*ConfigHook)
+
+	ev := &cchooks.PostToolUseEvent{
+		ToolName:  "MultiEdit",
+		ToolInput: json.RawMessage(`{"file_path":"a.go","edits":[{"old_string":"1","new_string":"2"},{"file_path":"README.md","old_string":"3","new_string":"4"},{"file_path":"b.go","old_string":"5","new_string":"6"}]}`),
+	}
+	hook.postHandler(context.Background(), ev)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a.go|a.go README.md b.go\nb.go|a.go README.md b.go\n"
+	if string(data) != want {
+		t.Errorf("per-file runs wrote %q, want %q", string(data), want)
+	}
+}
+
+func TestConfigHook_SessionStateAcrossEvents(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("BT_STATE_ROOT", root)
+	out := filepath.Join(t.TempDir(), "seen.txt")
+
+	cfg := config.CustomHooksConfig{
+		"track": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{Name: "count", Run: `echo "$TOOL_NAME" >> "$BT_STATE_DIR/tools"`}},
+			},
+			"SessionEnd": {
+				Jobs: []config.HookJob{{Name: "report", Run: `cat "$BT_STATE_DIR/tools" > ` + out}},
+			},
+		}},
+	}
+	factories := buildConfigHookFactories(&cfg)
+	post := factories["config:track:count"](core.TestHookContext(nil)).(*ConfigHook)
+	for _, tool := range []string{"Edit", "Write"} {
+		post.postHandler(context.Background(), &cchooks.PostToolUseEvent{SessionID: "s-1", ToolName: tool, ToolInput: json.RawMessage(`{}`)})
+	}
+
+	end := factories["config:track:report"](core.TestHookContext(nil)).(*ConfigHook)
+	end.rawHandler()(context.Background(), `{"hook_event_name":"SessionEnd","session_id":"s-1"}`)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Edit\nWrite\n" {
+		t.Errorf("SessionEnd job saw %q, want state written by earlier events", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(root, "s-1", ".ended")); err != nil {
+		t.Errorf("expected session to be marked ended: %v", err)
+	}
+}
+
+func TestConfigHook_ChainPassesOutputsInOrder(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	out := filepath.Join(t.TempDir(), "seen.txt")
+	cfg := config.CustomHooksConfig{
+		"release": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"UserPromptSubmit": {
+				Chain: true,
+				Jobs: []config.HookJob{
+					{Name: "version", Run: `sleep 0.2; echo "VERSION=1.2" >> "$BT_OUTPUT"; echo "EVENT_NAME=spoofed" >> "$BT_OUTPUT"`},
+					{Name: "report", Run: `echo "$VERSION $EVENT_NAME" > ` + out},
+				},
+			},
+		}},
+	}
+	factories := buildConfigHookFactories(&cfg)
+	payload := `{"hook_event_name":"UserPromptSubmit","session_id":"s-1","prompt":"ship it"}`
+
+	// Claude Code starts both hooks at once; the later job waits for the earlier one
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		report := factories["config:release:report"](core.TestHookContext(nil)).(*ConfigHook)
+		report.rawHandler()(context.Background(), payload)
+	}()
+	version := factories["config:release:version"](core.TestHookContext(nil)).(*ConfigHook)
+	version.rawHandler()(context.Background(), payload)
+	<-done
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "1.2 UserPromptSubmit" {
+		t.Errorf("chained job saw %q, want the earlier job's VERSION and its own EVENT_NAME", got)
+	}
+}
+
+func TestConfigHook_RawEventExposesPayload(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "seen.txt")
+	job := config.HookJob{Name: "snapshot", Run: `echo "$BT_EVENT_TRIGGER $(cat "$BT_EVENT_JSON" | wc -c | tr -d ' ')" > ` + out}
+	hook := NewConfigHook("memory", "snapshot", job, "PreCompact", core.TestHookContext(nil)).(*ConfigHook)
+
+	payload := `{"hook_event_name":"PreCompact","session_id":"s-1","trigger":"auto"}`
+	hook.rawHandler()(context.Background(), payload)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "auto "+strconv.Itoa(len(payload)) {
+		t.Errorf("job saw %q, want the trigger field and the full payload file", got)
+	}
+}
+
+func TestConfigHook_LifecycleRunsOnceAroundJobs(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	log := filepath.Join(t.TempDir(), "log.txt")
+	newCfg := func(before string) config.CustomHooksConfig {
+		return config.CustomHooksConfig{
+			"db": &config.HookGroup{Events: map[string]*config.EventConfig{
+				"PreToolUse": {
+					BeforeAll: &config.LifecycleCommand{Run: before},
+					AfterAll:  &config.LifecycleCommand{Run: "echo after >> " + log},
+					Jobs: []config.HookJob{
+						{Name: "a", Run: "echo a >> " + log},
+						{Name: "b", Run: "echo b >> " + log},
+					},
+				},
+			}},
+		}
+	}
+	runJobs := func(cfg config.CustomHooksConfig, command string) []core.ResponseSummary {
+		factories := buildConfigHookFactories(&cfg)
+		payload := `{"hook_event_name":"PreToolUse","session_id":"s-1","tool_name":"Bash","tool_input":{"command":"` + command + `"}}`
+		var got []core.ResponseSummary
+		for _, job := range []string{"a", "b"} {
+			h := factories["config:db:"+job](core.TestHookContext(nil)).(*ConfigHook)
+			h.rawHandler()(context.Background(), payload)
+			resp := h.preHandler(context.Background(), &cchooks.PreToolUseEvent{SessionID: "s-1", ToolName: "Bash", ToolInput: json.RawMessage(`{"command":"` + command + `"}`)})
+			got = append(got, core.SummarizeResponse(resp))
+		}
+		return got
+	}
+	readLog := func() string {
+		data, _ := os.ReadFile(log)
+		_ = os.Remove(log)
+		return string(data)
+	}
+
+	runJobs(newCfg("echo before >> "+log), "ls")
+	if got := readLog(); got != "before\na\nb\nafter\n" {
+		t.Errorf("log = %q, want before_all and after_all once around both jobs", got)
+	}
+
+	got := runJobs(newCfg("echo no database >&2; exit 3"), "pwd")
+	if got[0].Decision != cchooks.PreToolUseBlock || !strings.Contains(got[0].AgentMessage, "no database") {
+		t.Errorf("job that ran before_all = %+v, want a block with its output", got[0])
+	}
+	if got[1].Decision == cchooks.PreToolUseBlock {
+		t.Errorf("second job should skip quietly, got %+v", got[1])
+	}
+	if log := readLog(); log != "after\n" {
+		t.Errorf("log = %q, want jobs skipped and after_all still run", log)
+	}
+}
+
+func TestConfigHook_ToolEventsRunOnce(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "runs.txt")
+	cfg := config.CustomHooksConfig{
+		"g": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PreToolUse": {Jobs: []config.HookJob{{Name: "count", Run: "echo run >> " + out}}},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:g:count"](core.TestHookContext(nil)).(*ConfigHook)
+	payload := `{"hook_event_name":"PreToolUse","session_id":"s-1","tool_name":"Bash","tool_input":{"command":"ls"}}`
+
+	// The runner offers the payload to the raw handler before the typed one
+	if resp := hook.rawHandler()(context.Background(), payload); resp != nil {
+		t.Fatalf("raw handler should leave PreToolUse to the typed handler, got %+v", resp)
+	}
+	hook.preHandler(context.Background(), &cchooks.PreToolUseEvent{SessionID: "s-1", ToolName: "Bash", ToolInput: json.RawMessage(`{"command":"ls"}`)})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "run\n" {
+		t.Errorf("job ran %d times, want once", strings.Count(string(data), "run"))
+	}
+}
+
+func TestConfigHook_OnlyNewFindings(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	lintOut := filepath.Join(t.TempDir(), "lint.txt")
+	cfg := config.CustomHooksConfig{
+		"lint": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{
+					Name:            "check",
+					Run:             `if [ -s ` + lintOut + ` ]; then cat ` + lintOut + `; exit 1; fi`,
+					OnlyNewFindings: true,
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:lint:check"](core.TestHookContext(nil)).(*ConfigHook)
+	run := func(output string) core.ResponseSummary {
+		t.Helper()
+		if err := os.WriteFile(lintOut, []byte(output), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		ev := &cchooks.PostToolUseEvent{ToolName: "Edit", ToolInput: json.RawMessage(`{"file_path":"a.go","old_string":"1","new_string":"2"}`)}
+		return core.SummarizeResponse(hook.postHandler(context.Background(), ev))
+	}
+
+	if s := run("a.go:3: unused x\n"); s.Decision != "block" || !strings.Contains(s.AgentMessage, "unused x") {
+		t.Fatalf("first run should report existing findings, got %+v", s)
+	}
+	if s := run("a.go:8: unused x\n"); s.Decision == "block" {
+		t.Fatalf("pre-existing finding on a shifted line should be suppressed, got %+v", s)
+	}
+	s := run("a.go:8: unused x\na.go:12: shadowed err\n")
+	if s.Decision != "block" || strings.Contains(s.AgentMessage, "unused x") || !strings.Contains(s.AgentMessage, "1 new finding(s) since the last run") {
+		t.Fatalf("only the new finding should be reported, got %+v", s)
+	}
+}
+
+func TestConfigHook_QuietSuccessAndLogLevel(t *testing.T) {
+	quiet := true
+	cfg := config.CustomHooksConfig{
+		"ci": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{
+					{Name: "ok", Run: "echo fine", QuietSuccess: &quiet},
+					{Name: "fail", Run: "echo broken >&2; exit 3", QuietSuccess: &quiet},
+					{Name: "chatty", Run: "true", LogLevel: "error"},
+					{Name: "verbose", Run: "true"},
+				},
+			},
+		}},
+	}
+	factories := buildConfigHookFactories(&cfg)
+	var buf strings.Builder
+	ctx := core.TestHookContext(nil)
+	ctx.LoggingEnabled = true
+	ctx.LoggingDir = t.TempDir()
+	ctx.LogWriter = &buf
+
+	run := func(key string) []core.LogEntry {
+		t.Helper()
+		buf.Reset()
+		hook := factories[key](ctx).(*ConfigHook)
+		ev := &cchooks.PostToolUseEvent{ToolName: "Edit", ToolInput: json.RawMessage(`{"file_path":"a.go","old_string":"1","new_string":"2"}`)}
+		hook.postHandler(context.Background(), ev)
+		var entries []core.LogEntry
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var e core.LogEntry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("bad log line %q: %v", line, err)
+			}
+			entries = append(entries, e)
+		}
+		return entries
+	}
+
+	if entries := run("config:ci:ok"); len(entries) != 0 {
+		t.Errorf("quiet_success should log nothing on success, got %+v", entries)
+	}
+	entries := run("config:ci:fail")
+	if len(entries) != 1 || entries[0].Event != "job_failed" || entries[0].Level != config.LogLevelError {
+		t.Fatalf("expected one job_failed error entry, got %+v", entries)
+	}
+	if stderr, _ := entries[0].Details["stderr"].(string); !strings.Contains(stderr, "broken") || entries[0].Details["command"] == nil {
+		t.Errorf("failure entry should carry command and stderr, got %+v", entries[0].Details)
+	}
+	if entries := run("config:ci:chatty"); len(entries) != 0 {
+		t.Errorf("log_level error should drop success entries, got %+v", entries)
+	}
+	if entries := run("config:ci:verbose"); len(entries) != 1 || entries[0].Event != "job_succeeded" {
+		t.Errorf("expected job_succeeded by default, got %+v", entries)
+	}
+
+	// The logging.quietSuccess default applies to jobs that don't set quiet_success
+	ctx.QuietSuccess = true
+	if entries := run("config:ci:verbose"); len(entries) != 0 {
+		t.Errorf("context QuietSuccess should silence success, got %+v", entries)
+	}
+}
+
+func TestConfigHook_GitScope(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	repo := t.TempDir()
+	t.Chdir(repo)
+	for _, args := range [][]string{{"init", "-q"}, {"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "start"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	out := filepath.Join(t.TempDir(), "targets.txt")
+	cfg := config.CustomHooksConfig{
+		"test": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{
+					Name:  "touched",
+					Run:   `echo "$TOOL_FILE|$GIT_CHANGED_FILES" >> ` + out,
+					Glob:  []string{"*.go"},
+					Scope: config.JobScopeGit,
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:test:touched"](core.TestHookContext(nil)).(*ConfigHook)
+	edit := func(file string) {
+		if err := os.WriteFile(filepath.Join(repo, file), []byte("x\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		hook.postHandler(context.Background(), &cchooks.PostToolUseEvent{
+			SessionID: "s-1",
+			ToolName:  "Write",
+			ToolInput: json.RawMessage(`{"file_path":"` + file + `","content":"x\n"}`),
+		})
+	}
+	edit("notes.md") // no Go files changed yet: the job is skipped
+	edit("a.go")
+	edit("b.go")
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a.go|a.go\nb.go|a.go b.go\n"
+	if string(data) != want {
+		t.Errorf("git-scoped runs wrote %q, want %q", string(data), want)
+	}
+}
+
+func TestConfigHook_CacheSkipsUnchangedInputs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	src := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(src, []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.CustomHooksConfig{
+		"test": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{
+					Name:  "suite",
+					Run:   `echo run >> ` + runs + `; echo "FAIL $FILES_CHANGED"; grep -q broken "$FILES_CHANGED" && exit 1; exit 0`,
+					Cache: &config.JobCache{Key: []string{"FILES_CHANGED"}, TTL: 60},
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:test:suite"](core.TestHookContext(nil)).(*ConfigHook)
+	env := map[string]string{"FILES_CHANGED": src}
+	run := func() (*hookExecutionResult, error) {
+		t.Helper()
+		return hook.executeIfShouldRunWithResult(context.Background(), env)
+	}
+	countRuns := func() int {
+		t.Helper()
+		data, _ := os.ReadFile(runs)
+		return strings.Count(string(data), "run")
+	}
+
+	for i := 0; i < 2; i++ {
+		if result, err := run(); err != nil || result.exitCode != 0 {
+			t.Fatalf("run %d: result=%+v err=%v", i, result, err)
+		}
+	}
+	if n := countRuns(); n != 1 {
+		t.Fatalf("job ran %d times for unchanged input, want 1", n)
+	}
+
+	// Editing the file invalidates the entry, and the failure is cached too
+	if err := os.WriteFile(src, []byte("broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		result, err := run()
+		if err == nil || result.exitCode != 1 || !strings.Contains(result.stdout, "FAIL "+src) {
+			t.Fatalf("changed run %d: result=%+v err=%v", i, result, err)
+		}
+	}
+	if n := countRuns(); n != 2 {
+		t.Fatalf("job ran %d times, want 2", n)
+	}
+}
+
+func TestConfigHook_CaptureOutputKeepsArtifacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	cfg := config.CustomHooksConfig{
+		"lint": &config.HookGroup{Events: map[string]*config.EventConfig{
+			"PostToolUse": {
+				Jobs: []config.HookJob{{
+					Name:          "vet",
+					Run:           `echo "checked $TOOL_FILE"; echo oops >&2; exit 3`,
+					CaptureOutput: true,
+				}},
+			},
+		}},
+	}
+	hook := buildConfigHookFactories(&cfg)["config:lint:vet"](core.TestHookContext(nil)).(*ConfigHook)
+	env := map[string]string{"TOOL_NAME": "Edit", "TOOL_FILE": "main.go"}
+	// The default rotation keeps five runs per job
+	for i := 0; i < 7; i++ {
+		if result, err := hook.executeIfShouldRunWithResult(context.Background(), env); err == nil || result.exitCode != 3 {
+			t.Fatalf("run %d: result=%+v err=%v", i, result, err)
+		}
+	}
+
+	artifacts, err := core.ListJobArtifacts("lint", "vet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifacts) != 5 {
+		t.Fatalf("expected 5 kept runs, got %d", len(artifacts))
+	}
+	a := artifacts[0]
+	if a.ExitCode != 3 || a.Event != "PostToolUse" || a.Tool != "Edit" || a.File != "main.go" || a.Error != "" {
+		t.Errorf("unexpected artifact %+v", a)
+	}
+	dir, _ := core.JobArtifactDir(a)
+	for name, want := range map[string]string{core.JobArtifactStdout: "checked main.go\n", core.JobArtifactStderr: "oops\n"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != want {
+			t.Errorf("%s = %q (%v), want %q", name, data, err, want)
+		}
+	}
+}
+
+func TestConfigHook_SandboxClearsEnvAndReportsViolations(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "env.txt")
+	t.Setenv("BT_SANDBOX_SECRET", "leaked")
+	t.Setenv("BT_SANDBOX_KEPT", "kept")
+	hook := NewConfigHook("g", "sandboxed", config.HookJob{
+		Name:    "sandboxed",
+		Run:     `echo "${BT_SANDBOX_SECRET:-none} $BT_SANDBOX_KEPT $TOOL_NAME $INLINE" > ` + out,
+		WorkDir: dir,
+		Env:     map[string]string{"INLINE": "inline"},
+		Sandbox: &config.JobSandbox{ClearEnv: true, EnvAllow: []string{"PATH", "BT_SANDBOX_K*"}},
+	}, "PreToolUse", core.TestHookContext(nil)).(*ConfigHook)
+	if result, err := hook.runCommandWithEnv(context.Background(), map[string]string{"TOOL_NAME": "Bash"}); err != nil {
+		t.Fatalf("job failed: %+v %v", result, err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "none kept Bash inline"; got != want {
+		t.Errorf("job saw %q, want %q", got, want)
+	}
+
+	if err := exec.Command("unshare", "--map-root-user", "--mount", "--", "true").Run(); err != nil {
+		t.Skip("unshare with user namespaces not available")
+	}
+	hook.job.Run = "touch written.txt"
+	hook.job.Sandbox = &config.JobSandbox{ReadOnlyWorkdir: true, NoNetwork: true}
+	ev := &cchooks.PreToolUseEvent{ToolName: "Bash", ToolInput: json.RawMessage(`{"command":"ls"}`)}
+	s := core.SummarizeResponse(hook.preHandler(context.Background(), ev))
+	if s.Decision != "block" || !strings.Contains(s.UserMessage, "stopped by its sandbox") || !strings.Contains(s.AgentMessage, "sandbox.read_only_workdir") {
+		t.Fatalf("expected a read-only violation, got %+v", s)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "written.txt")); !os.IsNotExist(err) {
+		t.Error("the job wrote to its read-only workdir")
+	}
+
+	hook.job.Run = "while :; do :; done"
+	hook.job.Sandbox = &config.JobSandbox{MaxCPUSeconds: 1}
+	s = core.SummarizeResponse(hook.preHandler(context.Background(), ev))
+	if s.Decision != "block" || !strings.Contains(s.AgentMessage, "sandbox.max_cpu_seconds") {
+		t.Fatalf("expected a CPU limit violation, got %+v", s)
+	}
+}
+
+func TestNewConfigHook_Description(t *testing.T) {
+	described := NewConfigHook("infra", "step3", config.HookJob{Name: "step3", Run: "true", Description: "Applies the terraform plan"}, "Stop", core.TestHookContext(nil))
+	if got := described.Description(); got != "Applies the terraform plan" {
+		t.Errorf("Description() = %q, want the job's description", got)
+	}
+	plain := NewConfigHook("infra", "step4", config.HookJob{Name: "step4", Run: "true"}, "Stop", core.TestHookContext(nil))
+	if got := plain.Description(); got != "Config job 'step4' for Stop" {
+		t.Errorf("Description() = %q, want the generated one", got)
+	}
+}
@@ -0,0 +1,269 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const (
+	// defaultCoverageThreshold is the coverageGate.threshold default, in percent
+	defaultCoverageThreshold = 80
+	// defaultCoverageTimeout bounds the coverage command, in seconds
+	defaultCoverageTimeout = 300
+	// coverageOutputLines is how much of the command's output the agent gets
+	coverageOutputLines = 20
+)
+
+// coverageLanguage is a project type the gate measures: the source files
+// it reacts to, the marker files that identify the project and the
+// coverage command, with {package} for the touched package's directory
+type coverageLanguage struct {
+	name       string
+	extensions []string
+	markers    []string
+	command    string
+}
+
+var coverageLanguages = []coverageLanguage{
+	{
+		name:       "go",
+		extensions: []string{".go"},
+		markers:    []string{"go.mod"},
+		command:    "go test -cover ./{package}",
+	},
+	{
+		name:       "python",
+		extensions: []string{".py"},
+		markers:    []string{"pyproject.toml", "setup.py", "setup.cfg", "pytest.ini", "tox.ini"},
+		command:    "python -m pytest -q --cov={package} --cov-report=term",
+	},
+	{
+		name:       "typescript",
+		extensions: []string{".ts", ".tsx", ".mts", ".cts"},
+		markers:    []string{"package.json"},
+		command:    "npx --no-install jest --coverage --coverageReporters=text-summary --collectCoverageFrom='{package}/**/*.{ts,tsx,mts,cts}'",
+	},
+}
+
+// vitestCoverageCommand replaces the jest command in projects using vitest
+const vitestCoverageCommand = "npx --no-install vitest run --coverage --coverage.reporter=text-summary --coverage.include='{package}/**'"
+
+// coveragePatterns find the total in the output of go test -cover, pytest-cov
+// and the istanbul text summary jest and vitest print, in that order
+var coveragePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`),
+	regexp.MustCompile(`(?m)^TOTAL\s.*?(\d+(?:\.\d+)?)%\s*$`),
+	regexp.MustCompile(`Statements\s*:\s*(\d+(?:\.\d+)?)%`),
+}
+
+// safePackagePattern matches directories that can go into a command unquoted
+var safePackagePattern = regexp.MustCompile(`^[A-Za-z0-9_./@+-]+$`)
+
+// CoverageGateHook runs the project's tests with coverage after a source
+// file is edited and blocks, or warns the agent, when the coverage of the
+// package the file is in is below the configured threshold.
+type CoverageGateHook struct {
+	*core.BaseHook
+}
+
+// NewCoverageGateHook creates a new coverage gate hook instance
+func NewCoverageGateHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("coverage-gate", "Coverage Gate", "Runs tests with coverage after edits and flags packages below the coverage threshold", ctx)
+	return &CoverageGateHook{BaseHook: base}
+}
+
+// Manifest describes the coverage-gate hook
+func (h *CoverageGateHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PostToolUseEvent)}
+	m.DefaultEvent = string(core.PostToolUseEvent)
+	m.DefaultMatcher = "Edit|Write"
+	m.SettingsKey = "coverageGate"
+	m.SettingsSchema = config.SectionSchema(config.CoverageGateConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands}
+	return m
+}
+
+// Run executes the coverage gate hook.
+func (h *CoverageGateHook) Run() error {
+	return h.StandardRun(nil, h.postToolUseHandler)
+}
+
+// coverageTarget is the package an edit touched
+type coverageTarget struct {
+	Language string
+	// Package is the file's directory relative to the project root, "."
+	// for the root itself
+	Package string
+	Command string
+}
+
+func (h *CoverageGateHook) postToolUseHandler(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+	if event.ToolName != constants.ToolEdit && event.ToolName != constants.ToolWrite {
+		return cchooks.Allow()
+	}
+	filePath := h.extractFilePath(event)
+	if filePath == "" {
+		return cchooks.Allow()
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Allow()
+	}
+	cfg := h.loadConfig()
+	target, ok := coverageTargetFor(root, filePath, cfg)
+	if !ok {
+		return cchooks.Allow()
+	}
+
+	output, pct, err := h.measure(ctx, target, cfg.Timeout)
+	if err != nil {
+		// No number to judge: failing tests are for other checks to report
+		h.LogError("coverage_gate_error", event.ToolName, err)
+		return cchooks.Allow()
+	}
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = defaultCoverageThreshold
+	}
+	details := map[string]interface{}{
+		"file_path": filePath,
+		"package":   target.Package,
+		"coverage":  pct,
+		"threshold": threshold,
+	}
+	if pct >= threshold {
+		h.LogHookEvent("coverage_gate_pass", event.ToolName, nil, details)
+		h.Progressf("Coverage of %s: %.1f%%\n", target.Package, pct)
+		return cchooks.Allow()
+	}
+
+	userMsg := fmt.Sprintf("Coverage of %s is %.1f%%, below the %.1f%% threshold", target.Package, pct, threshold)
+	agentMsg := fmt.Sprintf("After editing %s, statement coverage of %s is %.1f%%, below the required %.1f%%. Add or extend tests for the code you changed so coverage gets back above the threshold.\n`%s`:\n%s",
+		filePath, target.Package, pct, threshold, target.Command, tailLines(output, coverageOutputLines))
+	if strings.EqualFold(cfg.Action, "warn") {
+		h.LogHookEvent("coverage_gate_warn", event.ToolName, nil, details)
+		return core.AllowWithMessages(userMsg, agentMsg)
+	}
+	h.LogBlock("coverage_gate_block", event.ToolName, details)
+	return core.PostBlockWithMessages(userMsg, agentMsg)
+}
+
+func (h *CoverageGateHook) extractFilePath(event *cchooks.PostToolUseEvent) string {
+	switch event.ToolName {
+	case constants.ToolEdit:
+		if edit, err := event.InputAsEdit(); err == nil {
+			return edit.FilePath
+		}
+	case constants.ToolWrite:
+		if write, err := event.InputAsWrite(); err == nil {
+			return write.FilePath
+		}
+	}
+	return ""
+}
+
+// measure runs the target's coverage command and parses its total
+func (h *CoverageGateHook) measure(ctx context.Context, target coverageTarget, timeout int) (string, float64, error) {
+	if timeout <= 0 {
+		timeout = defaultCoverageTimeout
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	out, runErr := h.Context().CommandExecutor.ExecuteCommand(cmdCtx, "bash", "-lc", target.Command)
+	output := string(out)
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return output, 0, fmt.Errorf("`%s` timed out after %ds", target.Command, timeout)
+	}
+	pct, ok := parseCoverage(output)
+	if !ok {
+		if runErr != nil {
+			return output, 0, fmt.Errorf("`%s` failed: %w", target.Command, runErr)
+		}
+		return output, 0, fmt.Errorf("`%s` printed no coverage total", target.Command)
+	}
+	return output, pct, nil
+}
+
+// coverageTargetFor picks the language and package for an edited file,
+// or reports false when the file is not source the gate measures
+func coverageTargetFor(root, filePath string, cfg config.CoverageGateConfig) (coverageTarget, bool) {
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(root, filePath)
+	}
+	rel, err := filepath.Rel(root, filepath.Dir(filePath))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return coverageTarget{}, false
+	}
+	pkg := filepath.ToSlash(rel)
+	if !safePackagePattern.MatchString(pkg) {
+		return coverageTarget{}, false
+	}
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if strings.HasSuffix(strings.ToLower(filePath), ".d.ts") {
+		return coverageTarget{}, false
+	}
+
+	for _, lang := range coverageLanguages {
+		if !slices.Contains(lang.extensions, ext) || !hasAnyMarker(root, lang.markers) {
+			continue
+		}
+		command := cfg.Commands[lang.name]
+		if command == "" {
+			command = lang.command
+			if lang.name == "typescript" && usesVitest(root) {
+				command = vitestCoverageCommand
+			}
+		}
+		return coverageTarget{Language: lang.name, Package: pkg, Command: strings.ReplaceAll(command, "{package}", pkg)}, true
+	}
+	return coverageTarget{}, false
+}
+
+// parseCoverage returns the coverage total printed in output
+func parseCoverage(output string) (float64, bool) {
+	for _, re := range coveragePatterns {
+		m := re.FindStringSubmatch(output)
+		if m == nil {
+			continue
+		}
+		if pct, err := strconv.ParseFloat(m[1], 64); err == nil {
+			return pct, true
+		}
+	}
+	return 0, false
+}
+
+// hasAnyMarker reports whether one of markers exists in root
+func hasAnyMarker(root string, markers []string) bool {
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(root, m)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// usesVitest reports whether the project's package.json mentions vitest
+func usesVitest(root string) bool {
+	data, err := os.ReadFile(filepath.Join(root, "package.json")) // #nosec G304 - project manifest
+	return err == nil && strings.Contains(string(data), `"vitest"`)
+}
+
+// loadConfig reads coverageGate settings from the project config, falling back
+// to the global config
+func (h *CoverageGateHook) loadConfig() config.CoverageGateConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.CoverageGateConfig { return c.CoverageGate })
+}
@@ -0,0 +1,115 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestCoverageTargetFor(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.CoverageGateConfig{Commands: map[string]string{"python": "pytest --cov={package}"}}
+
+	tests := []struct {
+		file    string
+		ok      bool
+		command string
+	}{
+		{"internal/store/db.go", true, "go test -cover ./internal/store"},
+		{filepath.Join(root, "main.go"), true, "go test -cover ./."},
+		{"README.md", false, ""},
+		// No Python marker in the project
+		{"tools/gen.py", false, ""},
+		{"../other/x.go", false, ""},
+		{"dir with space/x.go", false, ""},
+	}
+	for _, tt := range tests {
+		target, ok := coverageTargetFor(root, tt.file, cfg)
+		if ok != tt.ok || target.Command != tt.command {
+			t.Errorf("coverageTargetFor(%q) = %q, %v; want %q, %v", tt.file, target.Command, ok, tt.command, tt.ok)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "pyproject.toml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if target, ok := coverageTargetFor(root, "tools/gen.py", cfg); !ok || target.Command != "pytest --cov=tools" {
+		t.Errorf("expected configured python command, got %q, %v", target.Command, ok)
+	}
+}
+
+func TestParseCoverage(t *testing.T) {
+	tests := []struct {
+		output string
+		want   float64
+		ok     bool
+	}{
+		{"ok  \texample.com/m/store\t0.01s\tcoverage: 71.4% of statements\n", 71.4, true},
+		{"Name    Stmts   Miss  Cover\n-----\napp.py     10      1    90%\nTOTAL      20      5    75%\n", 75, true},
+		{"=============================== Coverage summary ===============================\nStatements   : 85.71% ( 6/7 )\nBranches     : 100% ( 0/0 )\n", 85.71, true},
+		{"?   \texample.com/m/store\t[no test files]\n", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCoverage(tt.output)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseCoverage(%q) = %v, %v; want %v, %v", tt.output, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCoverageGateHook_BlocksBelowThreshold(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+	if err := os.WriteFile(filepath.Join(project, "go.mod"), []byte("module example.com/m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeCoverageConfig(t, config.CoverageGateConfig{Threshold: 75})
+
+	exec := core.NewMockCommandExecutor()
+	ctx := core.TestHookContext(nil)
+	ctx.CommandExecutor = exec
+	hook := NewCoverageGateHook(ctx).(*CoverageGateHook)
+	event := &cchooks.PostToolUseEvent{
+		ToolName:  "Edit",
+		ToolInput: json.RawMessage(`{"file_path":"store/db.go","old_string":"a","new_string":"b"}`),
+	}
+
+	exec.SetResponse("bash -lc", []byte("ok  \texample.com/m/store\t0.01s\tcoverage: 62.5% of statements\n"), nil)
+	if s := core.SummarizeResponse(hook.postToolUseHandler(context.Background(), event)); s.Decision != "block" {
+		t.Fatalf("expected coverage below threshold to block, got %+v", s)
+	}
+	cmds := exec.GetExecutedCommands()
+	if len(cmds) != 1 || cmds[0].Args[1] != "go test -cover ./store" {
+		t.Fatalf("unexpected commands %+v", cmds)
+	}
+
+	exec.SetResponse("bash -lc", []byte("ok  \texample.com/m/store\t0.01s\tcoverage: 80.0% of statements\n"), nil)
+	if s := core.SummarizeResponse(hook.postToolUseHandler(context.Background(), event)); s.Decision == "block" {
+		t.Fatalf("expected coverage above threshold to pass, got %+v", s)
+	}
+
+	writeCoverageConfig(t, config.CoverageGateConfig{Threshold: 90, Action: "warn"})
+	if s := core.SummarizeResponse(hook.postToolUseHandler(context.Background(), event)); s.Decision == "block" {
+		t.Fatalf("expected warn action not to block, got %+v", s)
+	}
+}
+
+func writeCoverageConfig(t *testing.T, gate config.CoverageGateConfig) {
+	t.Helper()
+	path, err := config.GetLogConfigPath(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveLogConfig(path, &config.LogConfig{CoverageGate: &gate}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -25,6 +25,14 @@ func NewDebugHook(ctx *core.HookContext) core.Hook {
 	return &DebugHook{BaseHook: base}
 }
 
+// Manifest describes the debug hook
+func (h *DebugHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent), string(core.PostToolUseEvent)}
+	m.Capabilities = []core.Capability{core.CapabilityWritesState}
+	return m
+}
+
 // Run executes the debug hook.
 func (h *DebugHook) Run() error {
 	if !h.IsEnabled() {
@@ -41,8 +49,8 @@ func (h *DebugHook) Run() error {
 			}
 		}
 	}()
-	runner := h.Context().RunnerFactory(h.preToolUseHandler, h.postToolUseHandler, h.CreateRawHandler())
-	runner.Run()
+	runner := h.Runner(h.preToolUseHandler, h.postToolUseHandler, h.CreateRawHandler())
+	runner.RunContext(h.RunContext())
 	return nil
 }
 
@@ -67,7 +75,7 @@ func (h *DebugHook) ensureLogger() {
 		fmt.Fprintf(os.Stderr, "failed to open debug log file %s: %v\n", logPath, err)
 		return
 	}
-	h.logger = log.New(h.logFile, "", log.LstdFlags)
+	h.logger = log.New(h.Context().Redactor.Writer(h.logFile), "", log.LstdFlags)
 }
 
 func (h *DebugHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
@@ -0,0 +1,378 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+var (
+	// commandSeparatorPattern splits a command line into simple commands
+	commandSeparatorPattern = regexp.MustCompile(`\s*(?:&&|\|\||[;|\n])\s*`)
+	// alwaysProtectedPaths cannot be deleted regardless of deleteGuard.protectedPaths
+	alwaysProtectedPaths = []string{".git"}
+	// deleteTools are tools whose only purpose is removing a file
+	deleteTools = map[string]bool{"Delete": true, "DeleteFile": true, "delete_file": true}
+)
+
+// DeleteGuardHook models file deletions made through tools: rm and friends
+// in Bash, a Write that empties an existing file, and dedicated delete
+// tools. Protected paths are never deleted, and with deleteGuard.trash the
+// targets are moved to .claude/trash/ where "blues-traveler trash restore"
+// can bring them back.
+type DeleteGuardHook struct {
+	*core.BaseHook
+}
+
+// NewDeleteGuardHook creates a new deletion guard hook instance
+func NewDeleteGuardHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("delete-guard", "Deletion Guard", "Protects paths from deletion and moves deleted files to a restorable trash", ctx)
+	return &DeleteGuardHook{BaseHook: base}
+}
+
+// Manifest describes the delete-guard hook
+func (h *DeleteGuardHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.SettingsKey = "deleteGuard"
+	m.SettingsSchema = config.SectionSchema(config.DeleteGuardConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityModifiesFiles}
+	return m
+}
+
+// Run executes the deletion guard hook.
+func (h *DeleteGuardHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// deletion is one deleting command found in a Bash command line
+type deletion struct {
+	Program string
+	Targets []string
+	// Trashable deletions can be replaced by moving their targets
+	Trashable bool
+}
+
+func (h *DeleteGuardHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+
+	switch {
+	case event.ToolName == constants.ToolBash:
+		bash, err := event.AsBash()
+		if err != nil {
+			return cchooks.Approve()
+		}
+		return h.checkBash(root, bash.Command, cfg)
+	case event.ToolName == constants.ToolWrite:
+		payload := core.ParseToolPayload(event.ToolName, event.ToolInput)
+		if len(payload.Edits) == 0 {
+			return cchooks.Approve()
+		}
+		return h.checkEmptyingWrite(root, payload.Edits[0], cfg)
+	case deleteTools[event.ToolName]:
+		return h.checkDeleteTool(root, event, cfg)
+	}
+	return cchooks.Approve()
+}
+
+// checkBash guards rm, rmdir, unlink, git rm and find -delete
+func (h *DeleteGuardHook) checkBash(root, command string, cfg config.DeleteGuardConfig) cchooks.PreToolUseResponseInterface {
+	deletions, other := parseBashDeletions(command)
+	if len(deletions) == 0 {
+		return cchooks.Approve()
+	}
+
+	var targets []string
+	trashable := !other
+	for _, d := range deletions {
+		for _, t := range d.Targets {
+			targets = append(targets, expandTarget(root, t)...)
+		}
+		trashable = trashable && d.Trashable
+	}
+	if resp := h.blockProtected(root, constants.ToolBash, targets, cfg); resp != nil {
+		return resp
+	}
+	if !cfg.Trash || insideTrash(targets) {
+		return cchooks.Approve()
+	}
+	if !deletesWorkingFiles(deletions) {
+		// git rm --cached and friends leave the files in place
+		return cchooks.Approve()
+	}
+	if !trashable {
+		h.LogBlock("delete_guard_untrashable", constants.ToolBash, map[string]interface{}{"command": command})
+		return core.BlockWithMessages(
+			"Deletion blocked: only plain rm, rmdir and unlink commands can be moved to trash.",
+			"Trash mode is on, so deletions must be recoverable. Run the deletion on its own as 'rm <paths>' (not combined with other commands, find -delete or git rm) so the files can be moved to .claude/trash/.",
+		)
+	}
+	return h.trashAndBlock(constants.ToolBash, command, targets)
+}
+
+// checkEmptyingWrite guards a Write that replaces an existing file with nothing
+func (h *DeleteGuardHook) checkEmptyingWrite(root string, edit core.FileEdit, cfg config.DeleteGuardConfig) cchooks.PreToolUseResponseInterface {
+	if strings.TrimSpace(edit.NewString) != "" {
+		return cchooks.Approve()
+	}
+	path := edit.FilePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() == 0 {
+		return cchooks.Approve()
+	}
+	if resp := h.blockProtected(root, constants.ToolWrite, []string{path}, cfg); resp != nil {
+		return resp
+	}
+	if cfg.Trash {
+		// Keep a copy and let the write go ahead
+		if _, err := core.MoveToTrash([]string{path}, constants.ToolWrite, "", true); err != nil {
+			h.LogError("delete_guard_trash", constants.ToolWrite, err)
+			return core.BlockWithMessages(
+				fmt.Sprintf("Could not back up %s before emptying it.", edit.FilePath),
+				fmt.Sprintf("Saving a trash copy failed: %v", err),
+			)
+		}
+	}
+	return cchooks.Approve()
+}
+
+// checkDeleteTool guards tools that exist to delete a file
+func (h *DeleteGuardHook) checkDeleteTool(root string, event *cchooks.PreToolUseEvent, cfg config.DeleteGuardConfig) cchooks.PreToolUseResponseInterface {
+	var input map[string]interface{}
+	if err := json.Unmarshal(event.ToolInput, &input); err != nil {
+		return cchooks.Approve()
+	}
+	var targets []string
+	for _, key := range []string{"file_path", "path", "target_file"} {
+		if v, ok := input[key].(string); ok && v != "" {
+			targets = append(targets, expandTarget(root, v)...)
+		}
+	}
+	if len(targets) == 0 {
+		return cchooks.Approve()
+	}
+	if resp := h.blockProtected(root, event.ToolName, targets, cfg); resp != nil {
+		return resp
+	}
+	if !cfg.Trash || insideTrash(targets) {
+		return cchooks.Approve()
+	}
+	return h.trashAndBlock(event.ToolName, "", targets)
+}
+
+// blockProtected blocks when any target is, or contains, a protected path
+func (h *DeleteGuardHook) blockProtected(root, toolName string, targets []string, cfg config.DeleteGuardConfig) cchooks.PreToolUseResponseInterface {
+	entries := append(append([]string{}, alwaysProtectedPaths...), cfg.ProtectedPaths...)
+	for _, t := range targets {
+		entry, ok := protectedBy(root, t, entries)
+		if !ok {
+			continue
+		}
+		h.LogBlock("delete_guard_protected", toolName, map[string]interface{}{"target": t, "protected": entry})
+		return core.BlockWithMessages(
+			fmt.Sprintf("Deletion blocked: %s is protected.", displayPath(root, t)),
+			fmt.Sprintf("Deleting %s would remove protected path '%s' (deleteGuard.protectedPaths). Leave it in place, or ask the user to remove it themselves.", displayPath(root, t), entry),
+		)
+	}
+	return nil
+}
+
+// trashAndBlock moves targets to the trash in place of the tool's deletion
+// and blocks the tool call, which no longer has anything to do
+func (h *DeleteGuardHook) trashAndBlock(toolName, command string, targets []string) cchooks.PreToolUseResponseInterface {
+	entry, err := core.MoveToTrash(targets, toolName, command, false)
+	if err != nil {
+		h.LogError("delete_guard_trash", toolName, err)
+		return core.BlockWithMessages(
+			"Deletion blocked: moving files to trash failed.",
+			fmt.Sprintf("Trash mode is on but the targets could not be moved: %v", err),
+		)
+	}
+	if entry == nil {
+		// Nothing exists to delete; let the tool report that itself
+		return cchooks.Approve()
+	}
+	h.LogBlock("delete_guard_trashed", toolName, map[string]interface{}{"trash_id": entry.ID, "items": len(entry.Items)})
+	return core.BlockWithMessages(
+		fmt.Sprintf("Moved %d item(s) to trash instead of deleting. Restore with: blues-traveler trash restore %s", len(entry.Items), entry.ID),
+		fmt.Sprintf("The deletion is already done: the targets were moved to .claude/trash/%s instead of being deleted. Do not run it again; continue as if it succeeded.", entry.ID),
+	)
+}
+
+// loadConfig reads deleteGuard settings from the project config, falling back
+// to the global config
+func (h *DeleteGuardHook) loadConfig() config.DeleteGuardConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.DeleteGuardConfig { return c.DeleteGuard })
+}
+
+// parseBashDeletions finds deleting commands in a command line. other
+// reports whether the line also runs anything else.
+func parseBashDeletions(command string) (deletions []deletion, other bool) {
+	for _, segment := range commandSeparatorPattern.Split(command, -1) {
+		tokens := unquoteTokens(strings.Fields(segment))
+		for len(tokens) > 0 && (tokens[0] == "sudo" || tokens[0] == "command" || isEnvAssignment(tokens[0])) {
+			tokens = tokens[1:]
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		if d, ok := parseDeletion(tokens); ok {
+			deletions = append(deletions, d)
+		} else {
+			other = true
+		}
+	}
+	return deletions, other
+}
+
+func parseDeletion(tokens []string) (deletion, bool) {
+	switch filepath.Base(tokens[0]) {
+	case "rm", "rmdir", "unlink":
+		return deletion{Program: filepath.Base(tokens[0]), Targets: operands(tokens[1:]), Trashable: true}, true
+	case "git":
+		if len(tokens) > 1 && tokens[1] == "rm" {
+			d := deletion{Program: "git rm", Targets: operands(tokens[2:])}
+			for _, t := range tokens[2:] {
+				if t == "--cached" {
+					d.Program = "git rm --cached"
+				}
+			}
+			return d, true
+		}
+	case "find":
+		for _, t := range tokens[1:] {
+			if t == "-delete" {
+				var roots []string
+				for _, r := range tokens[1:] {
+					if strings.HasPrefix(r, "-") || r == "(" || r == "!" {
+						break
+					}
+					roots = append(roots, r)
+				}
+				if len(roots) == 0 {
+					roots = []string{"."}
+				}
+				return deletion{Program: "find -delete", Targets: roots}, true
+			}
+		}
+	}
+	return deletion{}, false
+}
+
+// operands returns the non-flag arguments; "--" ends flag parsing
+func operands(args []string) []string {
+	var out []string
+	flags := true
+	for _, a := range args {
+		if flags && a == "--" {
+			flags = false
+			continue
+		}
+		if flags && strings.HasPrefix(a, "-") && a != "-" {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// deletesWorkingFiles reports whether any deletion removes files on disk
+func deletesWorkingFiles(deletions []deletion) bool {
+	for _, d := range deletions {
+		if d.Program != "git rm --cached" {
+			return true
+		}
+	}
+	return false
+}
+
+func unquoteTokens(tokens []string) []string {
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = strings.Trim(t, `'"`)
+	}
+	return out
+}
+
+func isEnvAssignment(token string) bool {
+	eq := strings.IndexByte(token, '=')
+	return eq > 0 && !strings.ContainsAny(token[:eq], "/-.")
+}
+
+// expandTarget resolves a target to absolute paths, expanding ~ and globs.
+// A glob without matches is kept as written.
+func expandTarget(root, target string) []string {
+	if target == "~" || strings.HasPrefix(target, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			target = filepath.Join(home, strings.TrimPrefix(target, "~"))
+		}
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	target = filepath.Clean(target)
+	if strings.ContainsAny(target, "*?[") {
+		if matches, err := filepath.Glob(target); err == nil && len(matches) > 0 {
+			return matches
+		}
+	}
+	return []string{target}
+}
+
+// protectedBy returns the entry protecting target: target lies inside a
+// protected directory or glob, or target is a directory containing one
+func protectedBy(root, target string, entries []string) (string, bool) {
+	for _, entry := range entries {
+		if inAllowedDir(root, target, []string{entry}) {
+			return entry, true
+		}
+		if strings.ContainsAny(entry, "*?[") {
+			continue
+		}
+		rel, err := filepath.Rel(target, filepath.Join(root, filepath.FromSlash(entry)))
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// insideTrash reports whether every target is already in the trash, where
+// deleting means emptying it
+func insideTrash(targets []string) bool {
+	dir, err := core.TrashDir()
+	if err != nil {
+		return false
+	}
+	for _, t := range targets {
+		rel, err := filepath.Rel(dir, t)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
+
+// displayPath shows paths inside root relative to it
+func displayPath(root, p string) string {
+	if rel, err := filepath.Rel(root, p); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return p
+}
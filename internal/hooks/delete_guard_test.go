@@ -0,0 +1,124 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestParseBashDeletions(t *testing.T) {
+	tests := []struct {
+		command  string
+		programs []string
+		targets  []string
+		other    bool
+	}{
+		{"ls -la", nil, nil, true},
+		{"rm -rf build dist", []string{"rm"}, []string{"build", "dist"}, false},
+		{"FOO=1 sudo rm -- -weird.txt", []string{"rm"}, []string{"-weird.txt"}, false},
+		{"make clean && rm out.txt", []string{"rm"}, []string{"out.txt"}, true},
+		{"git rm --cached secrets.env; unlink tmp.lock", []string{"git rm --cached", "unlink"}, []string{"secrets.env", "tmp.lock"}, false},
+		{"find logs -name '*.log' -delete", []string{"find -delete"}, []string{"logs"}, false},
+	}
+	for _, tt := range tests {
+		deletions, other := parseBashDeletions(tt.command)
+		var programs, targets []string
+		for _, d := range deletions {
+			programs = append(programs, d.Program)
+			targets = append(targets, d.Targets...)
+		}
+		if strings.Join(programs, ",") != strings.Join(tt.programs, ",") || other != tt.other {
+			t.Errorf("%q: programs %v other %v, want %v %v", tt.command, programs, other, tt.programs, tt.other)
+		}
+		if len(tt.targets) > 0 && targets[0] != tt.targets[0] {
+			t.Errorf("%q: targets %v, want prefix %v", tt.command, targets, tt.targets)
+		}
+	}
+}
+
+func TestDeleteGuardHook_Protected(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"deleteGuard":{"protectedPaths":["migrations","*.pem"]}}`)
+	writeTestFile(t, filepath.Join(dir, "migrations", "001.sql"), "create table t;")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "keep me")
+
+	hook := NewDeleteGuardHook(core.TestHookContext(nil)).(*DeleteGuardHook)
+	bash := func(command string) core.ResponseSummary {
+		input, _ := json.Marshal(map[string]string{"command": command})
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: "Bash", ToolInput: input}))
+	}
+
+	for _, command := range []string{"rm migrations/001.sql", "rm -rf .", "rm -rf .git", "rm server.pem", "find migrations -delete"} {
+		if got := bash(command); got.Decision != cchooks.PreToolUseBlock {
+			t.Errorf("%q should be blocked, got %+v", command, got)
+		}
+	}
+	if got := bash("rm notes.txt"); got.Decision == cchooks.PreToolUseBlock {
+		t.Errorf("unprotected rm without trash should pass, got %+v", got)
+	}
+
+	input, _ := json.Marshal(map[string]string{"file_path": "migrations/001.sql", "content": ""})
+	got := core.SummarizeResponse(hook.preToolUseHandler(context.Background(), &cchooks.PreToolUseEvent{ToolName: "Write", ToolInput: input}))
+	if got.Decision != cchooks.PreToolUseBlock {
+		t.Errorf("emptying a protected file should be blocked, got %+v", got)
+	}
+}
+
+func TestDeleteGuardHook_TrashAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"deleteGuard":{"trash":true}}`)
+	writeTestFile(t, filepath.Join(dir, "build", "out.bin"), "artifact")
+	writeTestFile(t, filepath.Join(dir, "a.txt"), "alpha")
+
+	hook := NewDeleteGuardHook(core.TestHookContext(nil)).(*DeleteGuardHook)
+	run := func(tool string, input map[string]string) core.ResponseSummary {
+		raw, _ := json.Marshal(input)
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: tool, ToolInput: raw}))
+	}
+
+	got := run("Bash", map[string]string{"command": "rm -rf build"})
+	if got.Decision != cchooks.PreToolUseBlock || !strings.Contains(got.UserMessage, "trash restore") {
+		t.Fatalf("rm should be replaced by a trash move, got %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build")); !os.IsNotExist(err) {
+		t.Fatalf("build should have been moved, stat err = %v", err)
+	}
+
+	if got := run("Bash", map[string]string{"command": "rm a.txt && make"}); got.Decision != cchooks.PreToolUseBlock {
+		t.Errorf("combined command should be blocked in trash mode, got %+v", got)
+	}
+	if got := run("Write", map[string]string{"file_path": "a.txt", "content": ""}); got.Decision == cchooks.PreToolUseBlock {
+		t.Errorf("emptying write should proceed after a backup, got %+v", got)
+	}
+
+	entries, err := core.ListTrash()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 trash entries, got %+v, %v", entries, err)
+	}
+	var rmEntry core.TrashEntry
+	for _, e := range entries {
+		if e.Tool == "Bash" {
+			rmEntry = e
+		}
+	}
+	if _, err := core.RestoreTrash(rmEntry.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "build", "out.bin")); err != nil || string(data) != "artifact" {
+		t.Errorf("restore = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Errorf("backed-up file should stay in place: %v", err)
+	}
+}
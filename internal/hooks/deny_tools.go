@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// DenyToolsHook enforces a project's policy on which Claude Code tools the
+// agent may use, e.g. no WebFetch or Bash in a repository with sensitive data
+type DenyToolsHook struct {
+	*core.BaseHook
+}
+
+// NewDenyToolsHook creates a new deny-tools hook instance
+func NewDenyToolsHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("deny-tools", "Deny Tools", "Blocks tools the project's allow/deny policy doesn't permit", ctx)
+	return &DenyToolsHook{BaseHook: base}
+}
+
+// Manifest describes the deny-tools hook
+func (h *DenyToolsHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "*"
+	m.SettingsKey = "denyTools"
+	m.SettingsSchema = config.SectionSchema(config.DenyToolsConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the deny-tools hook.
+func (h *DenyToolsHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+func (h *DenyToolsHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	cfg := h.loadConfig()
+	reason := toolDenial(cfg, event.ToolName)
+	if reason == "" {
+		return cchooks.Approve()
+	}
+
+	details := map[string]interface{}{"reason": reason}
+	policy := reason
+	if reason == "not allowlisted" {
+		policy = "only " + strings.Join(cfg.Allowed, ", ") + " are allowed"
+	}
+	why := ""
+	if cfg.Reason != "" {
+		why = " Reason: " + strings.TrimSuffix(cfg.Reason, ".") + "."
+	}
+	agentMsg := fmt.Sprintf("This project's tool policy does not let you use %s (%s).%s Don't retry it or get the same result through another tool, such as curl in Bash for WebFetch; continue with the tools the policy allows, or tell the user which tool you need and why.", event.ToolName, policy, why)
+	if strings.EqualFold(cfg.Action, "ask") {
+		h.LogApproval("deny_tools_ask", event.ToolName, details)
+		return core.AskWithMessages(fmt.Sprintf("Allow %s? The project's denyTools policy doesn't (%s).%s", event.ToolName, policy, why), agentMsg)
+	}
+	h.LogBlock("deny_tools_block", event.ToolName, details)
+	return core.BlockWithMessages(fmt.Sprintf("%s blocked by the project's denyTools policy: %s.%s", event.ToolName, policy, why), agentMsg)
+}
+
+// toolDenial returns why cfg refuses toolName, or "" when it is allowed.
+// Denied tools win over the allowlist.
+func toolDenial(cfg config.DenyToolsConfig, toolName string) string {
+	if matchAnyTool(cfg.Denied, toolName) {
+		return "denied"
+	}
+	if len(cfg.Allowed) == 0 || matchAnyTool(cfg.Allowed, toolName) {
+		return ""
+	}
+	return "not allowlisted"
+}
+
+// matchAnyTool reports whether any of patterns matches toolName
+func matchAnyTool(patterns []string, toolName string) bool {
+	for _, p := range patterns {
+		if matchToolPattern(p, toolName) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchToolPattern matches a tool name or glob, or mcp:<server>[/<tool>]
+// against the MCP tool's server and tool
+func matchToolPattern(pattern, toolName string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if rest, ok := strings.CutPrefix(pattern, "mcp:"); ok {
+		server, tool, isMCP := core.ParseMCPToolName(toolName)
+		if !isMCP {
+			return false
+		}
+		if rest == "" {
+			rest = "*"
+		}
+		return core.MatchMCPPattern(rest, server, tool)
+	}
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(toolName))
+	return ok
+}
+
+// loadConfig reads denyTools settings from the project config, falling back
+// to the global config
+func (h *DenyToolsHook) loadConfig() config.DenyToolsConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.DenyToolsConfig { return c.DenyTools })
+}
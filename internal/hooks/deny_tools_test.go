@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestToolDenial(t *testing.T) {
+	cfg := config.DenyToolsConfig{
+		Allowed: []string{"Read", "Edit", "Notebook*", "mcp:github"},
+		Denied:  []string{"NotebookEdit", "mcp:github/delete_*"},
+	}
+	tests := []struct {
+		tool, want string
+	}{
+		{"Read", ""},
+		{"edit", ""},
+		{"NotebookRead", ""},
+		{"NotebookEdit", "denied"},
+		{"mcp__github__create_issue", ""},
+		{"mcp__github__delete_repo", "denied"},
+		{"mcp__slack__post_message", "not allowlisted"},
+		{"Bash", "not allowlisted"},
+	}
+	for _, tt := range tests {
+		if got := toolDenial(cfg, tt.tool); got != tt.want {
+			t.Errorf("toolDenial(%s) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+	if got := toolDenial(config.DenyToolsConfig{Denied: []string{"mcp:"}}, "mcp__any__tool"); got != "denied" {
+		t.Errorf("mcp: should match every MCP tool, got %q", got)
+	}
+	if got := toolDenial(config.DenyToolsConfig{}, "Bash"); got != "" {
+		t.Errorf("an empty policy should allow everything, got %q", got)
+	}
+}
+
+func TestDenyToolsHook_PreToolUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"denyTools":{"allowed":["Read","Edit"],"denied":["WebFetch"],"reason":"this repo holds customer data"}}`)
+
+	hook := NewDenyToolsHook(core.TestHookContext(nil)).(*DenyToolsHook)
+	run := func(tool string) core.ResponseSummary {
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: tool, ToolInput: []byte(`{}`)}))
+	}
+
+	if s := run("Edit"); s.Decision == "block" {
+		t.Fatalf("allowed tool should pass, got %+v", s)
+	}
+	s := run("WebFetch")
+	if s.Decision != "block" || !strings.Contains(s.UserMessage, "WebFetch blocked by the project's denyTools policy: denied") {
+		t.Fatalf("expected denied tool blocked, got %+v", s)
+	}
+	if !strings.Contains(s.AgentMessage, "Reason: this repo holds customer data.") || !strings.Contains(s.AgentMessage, "Don't retry it") {
+		t.Errorf("agent message should explain the policy, got %q", s.AgentMessage)
+	}
+	if s := run("Bash"); s.Decision != "block" || !strings.Contains(s.AgentMessage, "only Read, Edit are allowed") {
+		t.Fatalf("expected tool outside the allowlist blocked, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"denyTools":{"denied":["Bash"],"action":"ask"}}`)
+	if s := run("Bash"); s.Decision != "ask" {
+		t.Fatalf("expected ask, got %+v", s)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -25,12 +26,23 @@ func NewFetchBlockerHook(ctx *core.HookContext) core.Hook {
 	return &FetchBlockerHook{BaseHook: base}
 }
 
+// Manifest describes the fetch-blocker hook
+func (h *FetchBlockerHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "WebFetch"
+	m.SettingsKey = "blockedUrls"
+	m.SettingsSchema = config.SectionSchema([]config.BlockedURL{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
 // Run executes the fetch blocker hook.
 func (h *FetchBlockerHook) Run() error {
 	return h.StandardRun(h.preToolUseHandler, nil)
 }
 
-func (h *FetchBlockerHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+func (h *FetchBlockerHook) preToolUseHandler(ctx context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
 	h.logEventDetails(event)
 
 	// Only check WebFetch calls
@@ -51,6 +63,7 @@ func (h *FetchBlockerHook) preToolUseHandler(_ context.Context, event *cchooks.P
 		// If we can't load the file, allow the request (fail open)
 		return cchooks.Approve()
 	}
+	blockedPrefixes = append(blockedPrefixes, h.loadRemoteBlocked(ctx, event.ToolName)...)
 
 	// Check and handle blocked URLs
 	return h.checkAndBlockURL(webFetch.URL, blockedPrefixes)
@@ -94,6 +107,22 @@ func (h *FetchBlockerHook) loadAllBlockedPrefixes() ([]BlockedPrefix, error) {
 	return blockedPrefixes, nil
 }
 
+// loadRemoteBlocked returns the prefixes from blockedUrlsRemote, if set.
+// Download errors are logged and the local prefixes still apply.
+func (h *FetchBlockerHook) loadRemoteBlocked(ctx context.Context, toolName string) []BlockedPrefix {
+	remote := config.GetBlockedURLsRemote()
+	if remote == nil {
+		return nil
+	}
+	prefixes, err := h.loadRemoteBlockedPrefixes(ctx, remote)
+	if err != nil && h.Context().LoggingEnabled {
+		h.LogHookEvent("fetch_blocker_remote_error", toolName, map[string]interface{}{
+			"error": fmt.Sprintf("failed to load remote blocked prefixes: %v", err),
+		}, nil)
+	}
+	return prefixes
+}
+
 // checkAndBlockURL checks if a URL should be blocked and returns appropriate response
 func (h *FetchBlockerHook) checkAndBlockURL(url string, blockedPrefixes []BlockedPrefix) cchooks.PreToolUseResponseInterface {
 	blocked, matchedPrefix, suggestion := h.isURLBlocked(url, blockedPrefixes)
@@ -124,13 +153,13 @@ func (h *FetchBlockerHook) getBlockedURLsFilePaths() []string {
 	var filePaths []string
 
 	// Project-local file
-	if cwd, err := os.Getwd(); err == nil {
-		filePaths = append(filePaths, filepath.Join(cwd, ".claude", "blocked-urls.txt"))
+	if dir, err := config.ProjectClaudeDir(); err == nil {
+		filePaths = append(filePaths, filepath.Join(dir, "blocked-urls.txt"))
 	}
 
 	// Global file
-	if homeDir, err := os.UserHomeDir(); err == nil {
-		filePaths = append(filePaths, filepath.Join(homeDir, ".claude", "blocked-urls.txt"))
+	if dir, err := config.GlobalClaudeDir(); err == nil {
+		filePaths = append(filePaths, filepath.Join(dir, "blocked-urls.txt"))
 	}
 
 	return filePaths
@@ -142,7 +171,7 @@ func (h *FetchBlockerHook) loadPrefixesFromFile(filePath string) ([]BlockedPrefi
 		return nil, nil // File doesn't exist, not an error
 	}
 
-	file, err := os.Open(filePath) // #nosec G304 - paths constructed from os.Getwd()/os.UserHomeDir() + fixed ".claude/blocked-urls.txt" suffix
+	file, err := os.Open(filePath) // #nosec G304 - paths constructed from the project and global .claude directories + fixed "blocked-urls.txt" suffix
 	if err != nil {
 		return nil, nil // File can't be opened, not an error
 	}
@@ -154,9 +183,9 @@ func (h *FetchBlockerHook) loadPrefixesFromFile(filePath string) ([]BlockedPrefi
 }
 
 // parseBlockedURLsFile parses the content of a blocked URLs file
-func (h *FetchBlockerHook) parseBlockedURLsFile(file *os.File) ([]BlockedPrefix, error) {
+func (h *FetchBlockerHook) parseBlockedURLsFile(r io.Reader) ([]BlockedPrefix, error) {
 	var prefixes []BlockedPrefix
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
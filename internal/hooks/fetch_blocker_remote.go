@@ -0,0 +1,157 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+const (
+	// remoteBlockedURLsSubDir holds downloaded denylists under the XDG cache dir
+	remoteBlockedURLsSubDir = "blocked-urls"
+	// defaultRemoteBlockedURLsTimeout bounds the download when timeout is unset
+	defaultRemoteBlockedURLsTimeout = 5 * time.Second
+	// maxRemoteBlockedURLsBytes caps how much of a response is read
+	maxRemoteBlockedURLsBytes = 4 << 20
+)
+
+var remoteBlockedURLsHTTPClient = &http.Client{}
+
+// remoteBlockedURLsCachePath is where the list from remote is cached. The
+// name hashes the configured URL, so tokens in it don't reach the disk.
+func remoteBlockedURLsCachePath(remote *config.BlockedURLsRemote) string {
+	sum := sha256.Sum256([]byte(remote.URL))
+	return filepath.Join(config.XDGCacheDir(), remoteBlockedURLsSubDir, hex.EncodeToString(sum[:8])+".txt")
+}
+
+// loadRemoteBlockedPrefixes returns the prefixes from remote, downloading
+// them when the cached copy is older than the cache TTL. If the download
+// fails, a stale copy is still used and the error is returned with it.
+func (h *FetchBlockerHook) loadRemoteBlockedPrefixes(ctx context.Context, remote *config.BlockedURLsRemote) ([]BlockedPrefix, error) {
+	ttl, err := remote.CacheTTLDuration()
+	if err != nil {
+		return nil, err
+	}
+	path := remoteBlockedURLsCachePath(remote)
+	cached, cacheErr := os.ReadFile(path) // #nosec G304 - path under the XDG cache dir named by a hash
+	if cacheErr == nil {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < ttl {
+			return h.parseRemoteBlockedURLs(cached)
+		}
+	}
+
+	body, fetchErr := fetchRemoteBlockedURLs(ctx, remote)
+	if fetchErr == nil {
+		prefixes, err := h.parseRemoteBlockedURLs(body)
+		if err == nil {
+			if err := writeRemoteBlockedURLsCache(path, body); err != nil {
+				return prefixes, err
+			}
+			return prefixes, nil
+		}
+		fetchErr = err
+	}
+	if cacheErr != nil {
+		return nil, fetchErr
+	}
+	prefixes, err := h.parseRemoteBlockedURLs(cached)
+	if err != nil {
+		return nil, fetchErr
+	}
+	return prefixes, fmt.Errorf("using cached copy: %w", fetchErr)
+}
+
+// fetchRemoteBlockedURLs downloads the list. Errors leave the URL out,
+// since it may embed a token.
+func fetchRemoteBlockedURLs(ctx context.Context, remote *config.BlockedURLsRemote) ([]byte, error) {
+	timeout := defaultRemoteBlockedURLsTimeout
+	if remote.Timeout > 0 {
+		timeout = time.Duration(remote.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, os.ExpandEnv(remote.URL), nil)
+	if err != nil {
+		return nil, errors.New("invalid blockedUrlsRemote url")
+	}
+	req.Header.Set("User-Agent", "blues-traveler")
+	for k, v := range remote.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	resp, err := remoteBlockedURLsHTTPClient.Do(req) // #nosec G107 - user-configured denylist source
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("denylist source returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteBlockedURLsBytes))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	return body, nil
+}
+
+// parseRemoteBlockedURLs reads a JSON array of blockedUrls entries, or
+// otherwise blocked-urls.txt lines
+func (h *FetchBlockerHook) parseRemoteBlockedURLs(body []byte) ([]BlockedPrefix, error) {
+	trimmed := bytes.TrimSpace(body)
+	if !bytes.HasPrefix(trimmed, []byte("[")) {
+		return h.parseBlockedURLsFile(bytes.NewReader(body))
+	}
+	var entries []config.BlockedURL
+	if err := json.Unmarshal(trimmed, &entries); err != nil {
+		return nil, fmt.Errorf("invalid denylist JSON: %w", err)
+	}
+	prefixes := make([]BlockedPrefix, 0, len(entries))
+	for _, e := range entries {
+		if strings.TrimSpace(e.Prefix) == "" {
+			continue
+		}
+		prefixes = append(prefixes, BlockedPrefix{Prefix: e.Prefix, Suggestion: e.Suggestion})
+	}
+	return prefixes, nil
+}
+
+// writeRemoteBlockedURLsCache replaces the cached list in one rename, so a
+// concurrent reader sees the old list or the new one
+func writeRemoteBlockedURLsCache(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blocked-urls-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache denylist: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(body); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to cache denylist: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache denylist: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to cache denylist: %w", err)
+	}
+	return nil
+}
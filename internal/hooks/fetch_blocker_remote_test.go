@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestFetchBlockerHook_RemoteList(t *testing.T) {
+	var hits atomic.Int32
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if fail.Load() || r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"prefix":"https://wiki.internal/","suggestion":"Use the wiki MCP server"}]`))
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("DENYLIST_TOKEN", "s3cret")
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{
+		"blockedUrls": [{"prefix": "https://local.example/"}],
+		"blockedUrlsRemote": {"url": "`+server.URL+`/deny.json", "headers": {"Authorization": "Bearer ${DENYLIST_TOKEN}"}, "cacheTtl": "1h"}
+	}`)
+
+	hook := NewFetchBlockerHook(core.TestHookContext(nil)).(*FetchBlockerHook)
+	fetch := func(url string) string {
+		input, _ := json.Marshal(map[string]string{"url": url, "prompt": "read"})
+		ev := &cchooks.PreToolUseEvent{ToolName: "WebFetch", ToolInput: input}
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(), ev)).Decision
+	}
+
+	for _, url := range []string{"https://wiki.internal/page", "https://local.example/x"} {
+		if got := fetch(url); got != "block" {
+			t.Errorf("%s: decision %q, want block", url, got)
+		}
+	}
+	if got := fetch("https://public.example/"); got == "block" {
+		t.Error("unlisted URL should be allowed")
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("denylist downloaded %d times, want once within the cache TTL", n)
+	}
+
+	// Once the cache is stale a failed download falls back to the cached list
+	cache := remoteBlockedURLsCachePath(config.GetBlockedURLsRemote())
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(cache, old, old); err != nil {
+		t.Fatal(err)
+	}
+	fail.Store(true)
+	if got := fetch("https://wiki.internal/page"); got != "block" {
+		t.Errorf("stale cache: decision %q, want block", got)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("stale cache should trigger a download, got %d downloads", n)
+	}
+}
+
+func TestParseRemoteBlockedURLs_Lines(t *testing.T) {
+	hook := NewFetchBlockerHook(core.TestHookContext(nil)).(*FetchBlockerHook)
+	got, err := hook.parseRemoteBlockedURLs([]byte("# shared list\nhttps://a.example/|Use the API\n\nhttps://b.example/*/private\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Suggestion != "Use the API" || got[1].Prefix != "https://b.example/*/private" {
+		t.Errorf("unexpected prefixes: %+v", got)
+	}
+	if _, err := hook.parseRemoteBlockedURLs([]byte("[not json")); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
@@ -21,6 +21,15 @@ func NewFindBlockerHook(ctx *core.HookContext) core.Hook {
 	return &FindBlockerHook{BaseHook: base}
 }
 
+// Manifest describes the find-blocker hook
+func (h *FindBlockerHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Bash"
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
 // Run executes the find blocker hook.
 func (h *FindBlockerHook) Run() error {
 	return h.StandardRun(h.preToolUseHandler, nil)
@@ -4,16 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
 )
 
+// defaultFormatterTimeout bounds each configured formatter, in seconds
+const defaultFormatterTimeout = 30
+
 var (
 	// Cache command availability to avoid repeated PATH lookups
 	gofumptOnce      sync.Once
@@ -40,12 +46,24 @@ func NewFormatHook(ctx *core.HookContext) core.Hook {
 	return &FormatHook{BaseHook: base}
 }
 
+// Manifest describes the format hook
+func (h *FormatHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PostToolUseEvent)}
+	m.DefaultEvent = string(core.PostToolUseEvent)
+	m.DefaultMatcher = "Edit|Write"
+	m.SettingsKey = "format"
+	m.SettingsSchema = config.SectionSchema(config.FormatConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityModifiesFiles, core.CapabilityRunsCommands}
+	return m
+}
+
 // Run executes the format hook.
 func (h *FormatHook) Run() error {
 	return h.StandardRun(nil, h.postToolUseHandler)
 }
 
-func (h *FormatHook) postToolUseHandler(_ context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+func (h *FormatHook) postToolUseHandler(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
 	// Format code files after editing
 	if event.ToolName != constants.ToolEdit && event.ToolName != constants.ToolWrite {
 		return cchooks.Allow()
@@ -58,7 +76,7 @@ func (h *FormatHook) postToolUseHandler(_ context.Context, event *cchooks.PostTo
 
 	h.logFormatEvent(event.ToolName, filePath)
 
-	if err := h.formatFile(filePath); err != nil {
+	if err := h.formatFile(ctx, filePath); err != nil {
 		// User-friendly message + technical details for agent
 		userMsg := fmt.Sprintf("Code formatting failed for %s", filepath.Base(filePath))
 		agentMsg := fmt.Sprintf("Formatting failed for %s: %v", filePath, err)
@@ -98,33 +116,37 @@ func (h *FormatHook) logFormatEvent(toolName, filePath string) {
 	h.LogHookEvent("format_file", toolName, rawData, details)
 }
 
-func (h *FormatHook) formatFile(filePath string) error {
+func (h *FormatHook) formatFile(ctx context.Context, filePath string) error {
+	if cfg := h.loadConfig(); len(cfg.Formatters) > 0 {
+		return h.runFormatters(ctx, cfg, filePath)
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	switch ext {
 	case ".go":
-		return h.formatGoFile(filePath)
+		return h.formatGoFile(ctx, filePath)
 	case ".js", ".ts", ".jsx", ".tsx":
-		return h.formatJSFile(filePath)
+		return h.formatJSFile(ctx, filePath)
 	case ".py":
-		return h.formatPythonFile(filePath)
+		return h.formatPythonFile(ctx, filePath)
 	case ".yml", ".yaml":
-		return h.formatYAMLFile(filePath)
+		return h.formatYAMLFile(ctx, filePath)
 	}
 	return nil
 }
 
-func (h *FormatHook) formatGoFile(filePath string) error {
+func (h *FormatHook) formatGoFile(ctx context.Context, filePath string) error {
 	var output []byte
 	var err error
 	var formatter string
 
 	// Prefer gofumpt over gofmt if available
 	if checkGofumptAvailable() {
-		output, err = h.Context().CommandExecutor.ExecuteCommand("gofumpt", "-w", filePath)
+		output, err = h.Context().CommandExecutor.ExecuteCommand(ctx, "gofumpt", "-w", filePath)
 		formatter = "gofumpt"
 	} else {
-		output, err = h.Context().CommandExecutor.ExecuteCommand("gofmt", "-w", filePath)
+		output, err = h.Context().CommandExecutor.ExecuteCommand(ctx, "gofmt", "-w", filePath)
 		formatter = "gofmt"
 	}
 
@@ -132,45 +154,136 @@ func (h *FormatHook) formatGoFile(filePath string) error {
 		log.Printf("%s error on %s: %s", formatter, filePath, output)
 		return fmt.Errorf("%s failed: %s", formatter, output)
 	}
-	fmt.Printf("Formatted Go file with %s: %s\n", formatter, filePath)
+	h.Progressf("Formatted Go file with %s: %s\n", formatter, filePath)
 	return nil
 }
 
-func (h *FormatHook) formatJSFile(filePath string) error {
-	output, err := h.Context().CommandExecutor.ExecuteCommand("prettier", "--write", filePath)
+func (h *FormatHook) formatJSFile(ctx context.Context, filePath string) error {
+	output, err := h.Context().CommandExecutor.ExecuteCommand(ctx, "prettier", "--write", filePath)
 	if err != nil {
 		log.Printf("prettier error on %s: %s", filePath, output)
 		return fmt.Errorf("prettier failed: %s", output)
 	}
-	fmt.Printf("Formatted JS/TS file: %s\n", filePath)
+	h.Progressf("Formatted JS/TS file: %s\n", filePath)
 	return nil
 }
 
-func (h *FormatHook) formatPythonFile(filePath string) error {
+func (h *FormatHook) formatPythonFile(ctx context.Context, filePath string) error {
 	// Run ruff format first
-	output, err := h.Context().CommandExecutor.ExecuteCommand("uvx", "ruff", "format", filePath)
+	output, err := h.Context().CommandExecutor.ExecuteCommand(ctx, "uvx", "ruff", "format", filePath)
 	if err != nil {
 		log.Printf("ruff format error on %s: %s", filePath, output)
 		return fmt.Errorf("ruff format failed: %s", output)
 	}
 
 	// Run ruff check --fix second
-	output, err = h.Context().CommandExecutor.ExecuteCommand("uvx", "ruff", "check", "--fix", filePath)
+	output, err = h.Context().CommandExecutor.ExecuteCommand(ctx, "uvx", "ruff", "check", "--fix", filePath)
 	if err != nil {
 		log.Printf("ruff check --fix error on %s: %s", filePath, output)
 		return fmt.Errorf("ruff check --fix failed: %s", output)
 	}
 
-	fmt.Printf("Formatted Python file: %s\n", filePath)
+	h.Progressf("Formatted Python file: %s\n", filePath)
 	return nil
 }
 
-func (h *FormatHook) formatYAMLFile(filePath string) error {
-	output, err := h.Context().CommandExecutor.ExecuteCommand("prettier", "--write", filePath)
+func (h *FormatHook) formatYAMLFile(ctx context.Context, filePath string) error {
+	output, err := h.Context().CommandExecutor.ExecuteCommand(ctx, "prettier", "--write", filePath)
 	if err != nil {
 		log.Printf("prettier error on %s: %s", filePath, output)
 		return fmt.Errorf("prettier failed: %s", output)
 	}
-	fmt.Printf("Formatted YAML file: %s\n", filePath)
+	h.Progressf("Formatted YAML file: %s\n", filePath)
+	return nil
+}
+
+// runFormatters runs the configured formatters matching filePath in order
+func (h *FormatHook) runFormatters(ctx context.Context, cfg config.FormatConfig, filePath string) error {
+	rel := formatRelPath(filePath)
+	for _, f := range cfg.Formatters {
+		if f.Run == "" || !matchPathPattern(f.Globs, rel) {
+			continue
+		}
+		name := formatterName(f)
+		if err := h.runFormatter(ctx, f, cfg, filePath); err != nil {
+			onError := f.OnError
+			if onError == "" {
+				onError = cfg.OnError
+			}
+			if onError != "allow" {
+				return fmt.Errorf("%s failed: %w", name, err)
+			}
+			log.Printf("%s error on %s (allowed): %v", name, filePath, err)
+			h.Progressf("Formatter %s failed on %s, continuing: %v\n", name, filePath, err)
+		} else {
+			h.Progressf("Formatted %s with %s\n", filePath, name)
+		}
+		if f.Stop {
+			break
+		}
+	}
+	return nil
+}
+
+func (h *FormatHook) runFormatter(ctx context.Context, f config.Formatter, cfg config.FormatConfig, filePath string) error {
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = cfg.Timeout
+	}
+	if timeout <= 0 {
+		timeout = defaultFormatterTimeout
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	output, err := h.Context().CommandExecutor.ExecuteCommand(cmdCtx, "bash", "-lc", formatterCommand(f.Run, filePath))
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %ds", timeout)
+	}
+	if err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return fmt.Errorf("%s", out)
+		}
+		return err
+	}
 	return nil
 }
+
+// formatterCommand puts the single-quoted file path in place of "{file}",
+// or after the command when it has none
+func formatterCommand(run, filePath string) string {
+	quoted := "'" + strings.ReplaceAll(filePath, "'", `'\''`) + "'"
+	if strings.Contains(run, "{file}") {
+		return strings.ReplaceAll(run, "{file}", quoted)
+	}
+	return run + " " + quoted
+}
+
+// formatterName is the formatter's name, or its command's first word
+func formatterName(f config.Formatter) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if fields := strings.Fields(f.Run); len(fields) > 0 {
+		return fields[0]
+	}
+	return "formatter"
+}
+
+// formatRelPath makes filePath relative to the working directory, with
+// forward slashes, so globs like "infra/*.tf" match edits given absolute paths
+func formatRelPath(filePath string) string {
+	if filepath.IsAbs(filePath) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+				filePath = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filePath)
+}
+
+// loadConfig reads format settings from the project config, falling back
+// to the global one so a formatter matrix can be set once for every project
+func (h *FormatHook) loadConfig() config.FormatConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.FormatConfig { return c.Format })
+}
@@ -1,8 +1,12 @@
 package hooks
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
 )
 
@@ -44,7 +48,7 @@ func TestFormatHookGoFile(t *testing.T) {
 	hook := NewFormatHook(ctx).(*FormatHook)
 
 	// Test formatting Go file
-	_ = hook.formatFile("test.go")
+	_ = hook.formatFile(context.Background(), "test.go")
 
 	// Check that either gofumpt or gofmt was called (prefers gofumpt when available)
 	gofumptCalled := mockCmd.WasCommandExecuted("gofumpt", "-w", "test.go")
@@ -72,7 +76,7 @@ func TestFormatHookJavaScriptFile(t *testing.T) {
 
 	for _, file := range files {
 		t.Run(file, func(t *testing.T) {
-			_ = hook.formatFile(file)
+			_ = hook.formatFile(context.Background(), file)
 
 			// Check that prettier was called
 			if !mockCmd.WasCommandExecuted("prettier", "--write", file) {
@@ -95,7 +99,7 @@ func TestFormatHookPythonFile(t *testing.T) {
 	hook := NewFormatHook(ctx).(*FormatHook)
 
 	// Test formatting Python file
-	_ = hook.formatFile("test.py")
+	_ = hook.formatFile(context.Background(), "test.py")
 
 	// Check that ruff format was called
 	if !mockCmd.WasCommandExecuted("uvx", "ruff", "format", "test.py") {
@@ -120,7 +124,7 @@ func TestFormatHookYAMLFile(t *testing.T) {
 
 	for _, file := range files {
 		t.Run(file, func(t *testing.T) {
-			_ = hook.formatFile(file)
+			_ = hook.formatFile(context.Background(), file)
 
 			// Check that prettier was called
 			if !mockCmd.WasCommandExecuted("prettier", "--write", file) {
@@ -143,7 +147,7 @@ func TestFormatHookUnsupportedFile(t *testing.T) {
 	hook := NewFormatHook(ctx).(*FormatHook)
 
 	// Test unsupported file extension
-	_ = hook.formatFile("test.txt")
+	_ = hook.formatFile(context.Background(), "test.txt")
 
 	// Check that no commands were executed
 	commands := mockCmd.GetExecutedCommands()
@@ -151,3 +155,46 @@ func TestFormatHookUnsupportedFile(t *testing.T) {
 		t.Errorf("Expected no commands to be executed for unsupported file, got %d commands", len(commands))
 	}
 }
+
+func TestFormatHookConfiguredFormatters(t *testing.T) {
+	mockCmd := core.NewMockCommandExecutor()
+	ctx := &core.HookContext{
+		FileSystem:      core.NewMockFileSystem(),
+		CommandExecutor: mockCmd,
+		RunnerFactory:   core.MockRunnerFactory,
+		SettingsChecker: func(string) bool { return true },
+		Platform:        core.PlatformClaude,
+	}
+	hook := NewFormatHook(ctx).(*FormatHook)
+
+	cfg := config.FormatConfig{Formatters: []config.Formatter{
+		{Globs: []string{"*.tf"}, Run: "terraform fmt"},
+		{Globs: []string{"*.rs"}, Run: "rustfmt --edition 2021 {file}", Stop: true},
+		{Globs: []string{"*.rs"}, Run: "never-runs"},
+	}}
+	if err := hook.runFormatters(context.Background(), cfg, "src/main.rs"); err != nil {
+		t.Fatal(err)
+	}
+	cmds := mockCmd.GetExecutedCommands()
+	if len(cmds) != 1 || cmds[0].Args[1] != "rustfmt --edition 2021 'src/main.rs'" {
+		t.Fatalf("commands = %+v, want only rustfmt", cmds)
+	}
+
+	mockCmd.SetResponse("bash -lc", []byte("boom"), errors.New("exit status 1"))
+	if err := hook.runFormatters(context.Background(), cfg, "main.tf"); err == nil || !strings.Contains(err.Error(), "terraform failed: boom") {
+		t.Errorf("expected a fail-closed error, got %v", err)
+	}
+	cfg.OnError = "allow"
+	if err := hook.runFormatters(context.Background(), cfg, "main.tf"); err != nil {
+		t.Errorf("expected onError allow to fail open, got %v", err)
+	}
+}
+
+func TestFormatterCommand(t *testing.T) {
+	if got := formatterCommand("terraform fmt", "it's.tf"); got != `terraform fmt 'it'\''s.tf'` {
+		t.Errorf("appended path = %s", got)
+	}
+	if got := formatterCommand("prettier --write {file} --log-level warn", "a.md"); got != "prettier --write 'a.md' --log-level warn" {
+		t.Errorf("placeholder path = %s", got)
+	}
+}
@@ -0,0 +1,334 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// defaultProtectedBranches are protected when gitGuard.protectedBranches is unset
+var defaultProtectedBranches = []string{"main", "master", "release", "release/*", "release-*"}
+
+// GitGuardHook blocks git commands that are hard to undo or skip review:
+// force pushes, pushes and commits to protected branches, --no-verify, and
+// history rewrites.
+type GitGuardHook struct {
+	*core.BaseHook
+}
+
+// NewGitGuardHook creates a new git guard hook instance
+func NewGitGuardHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("git-guard", "Git Guard", "Blocks force pushes, pushes and commits to protected branches, --no-verify and history rewrites", ctx)
+	return &GitGuardHook{BaseHook: base}
+}
+
+// Manifest describes the git-guard hook
+func (h *GitGuardHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = constants.ToolBash
+	m.SettingsKey = "gitGuard"
+	m.SettingsSchema = config.SectionSchema(config.GitGuardConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands}
+	return m
+}
+
+// Run executes the git guard hook.
+func (h *GitGuardHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// gitViolation is one git command the guard objects to
+type gitViolation struct {
+	Rule       string
+	Problem    string
+	Suggestion string
+}
+
+func (h *GitGuardHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	if event.ToolName != constants.ToolBash {
+		return cchooks.Approve()
+	}
+	bash, err := event.AsBash()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+	found := checkGitCommands(parseGitInvocations(root, bash.Command), cfg, func(dir string) string {
+		branch, _ := core.GitCurrentBranch(dir)
+		return branch
+	})
+	if len(found) == 0 {
+		return cchooks.Approve()
+	}
+	return h.respond(bash.Command, found, cfg)
+}
+
+// respond blocks (or with gitGuard.action "ask", asks about) the command
+func (h *GitGuardHook) respond(command string, found []gitViolation, cfg config.GitGuardConfig) cchooks.PreToolUseResponseInterface {
+	problems := make([]string, len(found))
+	rules := make([]string, len(found))
+	var advice []string
+	for i, v := range found {
+		problems[i], rules[i] = v.Problem, v.Rule
+		advice = append(advice, v.Suggestion)
+	}
+	summary := strings.Join(problems, "; ")
+	details := map[string]interface{}{"command": command, "rules": strings.Join(rules, ",")}
+	agentMsg := fmt.Sprintf("Git command stopped: %s. %s", summary, strings.Join(advice, " "))
+
+	if strings.EqualFold(cfg.Action, "ask") {
+		h.LogApproval("git_guard_ask", constants.ToolBash, details)
+		return core.AskWithMessages(fmt.Sprintf("Allow %s?", summary), agentMsg)
+	}
+	h.LogBlock("git_guard_block", constants.ToolBash, details)
+	return core.BlockWithMessages(fmt.Sprintf("Git command blocked: %s.", summary), agentMsg)
+}
+
+// loadConfig reads gitGuard settings from the project config, falling back
+// to the global one
+func (h *GitGuardHook) loadConfig() config.GitGuardConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.GitGuardConfig { return c.GitGuard })
+}
+
+// checkGitCommands applies the guard's rules to each git command.
+// currentBranch looks up the branch checked out in a directory, "" when
+// unknown.
+func checkGitCommands(cmds []gitInvocation, cfg config.GitGuardConfig, currentBranch func(dir string) string) []gitViolation {
+	protected := cfg.ProtectedBranches
+	if len(protected) == 0 {
+		protected = defaultProtectedBranches
+	}
+	var found []gitViolation
+	for _, g := range cmds {
+		branch := func() string { return currentBranch(g.Dir) }
+		switch g.Sub {
+		case "push":
+			found = append(found, checkPush(g.Args, protected, cfg, branch)...)
+		case "commit":
+			found = append(found, checkCommit(g.Args, protected, cfg, branch)...)
+		case "rebase":
+			if rebaseStarts(g.Args) {
+				if b := branch(); isProtectedBranch(b, protected) {
+					found = append(found, historyRewrite("git rebase", b))
+				}
+			}
+		case "reset":
+			if resetMovesBranch(g.Args) {
+				if b := branch(); isProtectedBranch(b, protected) {
+					found = append(found, historyRewrite("git reset to another commit", b))
+				}
+			}
+		case "filter-branch", "filter-repo":
+			found = append(found, gitViolation{
+				Rule:       "history-rewrite",
+				Problem:    "git " + g.Sub + " rewrites the repository's history",
+				Suggestion: "History rewrites need a person to run them deliberately; describe what should change instead.",
+			})
+		}
+	}
+	return found
+}
+
+// checkPush flags force pushes, pushes to protected branches and --no-verify
+func checkPush(args, protected []string, cfg config.GitGuardConfig, branch func() string) []gitViolation {
+	var found []gitViolation
+	force, all, tags, noVerify := false, false, false, false
+	var ops []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			ops = append(ops, args[i+1:]...)
+			i = len(args)
+		case a == "--force":
+			force = true
+		case strings.HasPrefix(a, "--force-with-lease"), a == "--force-if-includes":
+			// Safe forces: the remote must still be where we last saw it
+		case a == "--all", a == "--branches", a == "--mirror":
+			all = true
+		case a == "--tags":
+			tags = true
+		case a == "--no-verify":
+			noVerify = true
+		case a == "-o", a == "--push-option", a == "--repo", a == "--receive-pack", a == "--exec":
+			i++
+		case strings.HasPrefix(a, "--"):
+		case strings.HasPrefix(a, "-"):
+			if strings.Contains(a, "f") {
+				force = true
+			}
+		default:
+			ops = append(ops, a)
+		}
+	}
+
+	var targets []string
+	plus := false
+	if len(ops) > 1 {
+		for _, spec := range ops[1:] {
+			if strings.HasPrefix(spec, "+") {
+				plus = true
+				spec = spec[1:]
+			}
+			dst := spec
+			if i := strings.LastIndex(spec, ":"); i >= 0 {
+				dst = spec[i+1:]
+			}
+			targets = append(targets, strings.TrimPrefix(dst, "refs/heads/"))
+		}
+	} else if !all && !tags {
+		// Without a refspec git pushes the current branch
+		targets = []string{"HEAD"}
+	}
+	for i, t := range targets {
+		if t == "HEAD" {
+			targets[i] = branch()
+		}
+	}
+
+	if all {
+		found = append(found, gitViolation{
+			Rule:       "protected-push",
+			Problem:    "git push of every branch includes protected branches",
+			Suggestion: "Push only the branch you worked on: git push origin <branch>.",
+		})
+	}
+	for _, t := range targets {
+		if isProtectedBranch(t, protected) {
+			found = append(found, gitViolation{
+				Rule:       "protected-push",
+				Problem:    fmt.Sprintf("direct push to protected branch %s", t),
+				Suggestion: fmt.Sprintf("Push a feature branch instead (git switch -c <name> && git push -u origin <name>) and open a pull request into %s.", t),
+			})
+		}
+	}
+	if (force || plus) && !cfg.AllowForcePush && !protectedAmong(targets, protected) {
+		found = append(found, gitViolation{
+			Rule:       "force-push",
+			Problem:    "git push --force overwrites the remote branch",
+			Suggestion: "Use git push --force-with-lease, which refuses to overwrite commits you haven't seen.",
+		})
+	}
+	if noVerify && !cfg.AllowNoVerify {
+		found = append(found, noVerifyViolation("git push"))
+	}
+	return found
+}
+
+// checkCommit flags --no-verify and commits on protected branches
+func checkCommit(args, protected []string, cfg config.GitGuardConfig, branch func() string) []gitViolation {
+	var found []gitViolation
+	noVerify, amend := false, false
+args:
+	for _, a := range args {
+		switch {
+		case a == "--":
+			break args
+		case a == "--no-verify":
+			noVerify = true
+		case a == "--amend":
+			amend = true
+		case strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--"):
+			// -n is --no-verify; letters after one that takes a value are
+			// that value, as in -mnote
+			for _, c := range a[1:] {
+				if strings.ContainsRune("mFcCt", c) {
+					break
+				}
+				if c == 'n' {
+					noVerify = true
+				}
+			}
+		}
+	}
+	if noVerify && !cfg.AllowNoVerify {
+		found = append(found, noVerifyViolation("git commit"))
+	}
+	if b := branch(); isProtectedBranch(b, protected) {
+		if amend {
+			found = append(found, historyRewrite("git commit --amend", b))
+		} else {
+			found = append(found, gitViolation{
+				Rule:       "protected-commit",
+				Problem:    fmt.Sprintf("commit directly on protected branch %s", b),
+				Suggestion: "Create a branch first (git switch -c <name>), commit there and open a pull request.",
+			})
+		}
+	}
+	return found
+}
+
+func noVerifyViolation(command string) gitViolation {
+	return gitViolation{
+		Rule:       "no-verify",
+		Problem:    command + " --no-verify skips the repository's hooks",
+		Suggestion: "Run it without --no-verify and fix what the hooks report.",
+	}
+}
+
+func historyRewrite(what, branch string) gitViolation {
+	return gitViolation{
+		Rule:       "history-rewrite",
+		Problem:    fmt.Sprintf("%s rewrites protected branch %s", what, branch),
+		Suggestion: "Rewrite history on a feature branch, or add a new commit (git revert <commit>) to undo a change on " + branch + ".",
+	}
+}
+
+// rebaseStarts reports whether git rebase args start a rebase, as opposed
+// to continuing or ending one
+func rebaseStarts(args []string) bool {
+	for _, a := range args {
+		switch a {
+		case "--continue", "--abort", "--skip", "--quit", "--edit-todo", "--show-current-patch":
+			return false
+		}
+	}
+	return true
+}
+
+// resetMovesBranch reports whether git reset args point the branch at
+// another commit rather than unstaging or discarding changes
+func resetMovesBranch(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if !strings.HasPrefix(a, "-") {
+			return a != "HEAD" && a != "@"
+		}
+	}
+	return false
+}
+
+func isProtectedBranch(branch string, protected []string) bool {
+	if branch == "" || branch == "HEAD" {
+		return false
+	}
+	for _, p := range protected {
+		if ok, _ := path.Match(p, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func protectedAmong(branches, protected []string) bool {
+	for _, b := range branches {
+		if isProtectedBranch(b, protected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestCheckGitCommands(t *testing.T) {
+	onBranch := func(branch string) func(string) string {
+		return func(string) string { return branch }
+	}
+	tests := []struct {
+		command string
+		branch  string
+		cfg     config.GitGuardConfig
+		want    string
+	}{
+		{"git push origin feature", "feature", config.GitGuardConfig{}, ""},
+		{"git push", "feature", config.GitGuardConfig{}, ""},
+		{"git push", "main", config.GitGuardConfig{}, "protected-push"},
+		{"git push origin HEAD:refs/heads/release/1.2", "feature", config.GitGuardConfig{}, "protected-push"},
+		{"git push origin --delete master", "feature", config.GitGuardConfig{}, "protected-push"},
+		{"git push --all", "feature", config.GitGuardConfig{}, "protected-push"},
+		{"git push --tags", "main", config.GitGuardConfig{}, ""},
+		{"git push -f origin feature", "feature", config.GitGuardConfig{}, "force-push"},
+		{"git push origin +feature", "feature", config.GitGuardConfig{}, "force-push"},
+		{"git push --force-with-lease origin feature", "feature", config.GitGuardConfig{}, ""},
+		{"git push --force origin feature", "feature", config.GitGuardConfig{AllowForcePush: true}, ""},
+		{"git push --force-with-lease origin main", "feature", config.GitGuardConfig{}, "protected-push"},
+		{"git push --no-verify origin feature", "feature", config.GitGuardConfig{}, "no-verify"},
+		{"git commit -m wip", "feature", config.GitGuardConfig{}, ""},
+		{"git commit -m wip", "main", config.GitGuardConfig{}, "protected-commit"},
+		{"git commit -nm wip", "feature", config.GitGuardConfig{}, "no-verify"},
+		{"git commit -mnote", "feature", config.GitGuardConfig{}, ""},
+		{"git commit --no-verify -m wip", "feature", config.GitGuardConfig{AllowNoVerify: true}, ""},
+		{"git commit --amend --no-edit", "master", config.GitGuardConfig{}, "history-rewrite"},
+		{"git commit -m wip", "trunk", config.GitGuardConfig{ProtectedBranches: []string{"trunk"}}, "protected-commit"},
+		{"git commit -m wip", "main", config.GitGuardConfig{ProtectedBranches: []string{"trunk"}}, ""},
+		{"git rebase -i HEAD~3", "main", config.GitGuardConfig{}, "history-rewrite"},
+		{"git rebase --continue", "main", config.GitGuardConfig{}, ""},
+		{"git rebase main", "feature", config.GitGuardConfig{}, ""},
+		{"git reset --hard HEAD~1", "main", config.GitGuardConfig{}, "history-rewrite"},
+		{"git reset --hard", "main", config.GitGuardConfig{}, ""},
+		{"git reset -- main.go", "main", config.GitGuardConfig{}, ""},
+		{"git filter-repo --path secrets.txt --invert-paths", "feature", config.GitGuardConfig{}, "history-rewrite"},
+		{"go test ./... && git -C sub push -f", "feature", config.GitGuardConfig{}, "force-push"},
+		{"echo git push --force", "main", config.GitGuardConfig{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command+"@"+tt.branch, func(t *testing.T) {
+			found := checkGitCommands(parseGitInvocations("/repo", tt.command), tt.cfg, onBranch(tt.branch))
+			var rules []string
+			for _, v := range found {
+				rules = append(rules, v.Rule)
+			}
+			if got := strings.Join(rules, ","); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitGuardHook_PreToolUse(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if out, err := exec.Command("git", "init", "-q", "-b", "main").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	hook := NewGitGuardHook(core.TestHookContext(nil)).(*GitGuardHook)
+	run := func(command string) core.ResponseSummary {
+		raw, _ := json.Marshal(map[string]string{"command": command})
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: "Bash", ToolInput: raw}))
+	}
+
+	s := run("git commit -am 'fix'")
+	if s.Decision != "block" || !strings.Contains(s.UserMessage, "protected branch main") {
+		t.Fatalf("expected a block on main, got %+v", s)
+	}
+	if !strings.Contains(s.AgentMessage, "git switch -c") {
+		t.Errorf("agent message should suggest a branch, got %q", s.AgentMessage)
+	}
+	if s := run("git status"); s.Decision == "block" || s.Decision == "ask" {
+		t.Fatalf("expected other git commands to pass, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"gitGuard":{"action":"ask","protectedBranches":["prod"]}}`)
+	if s := run("git commit -am 'fix'"); s.Decision == "block" || s.Decision == "ask" {
+		t.Fatalf("main is no longer protected, got %+v", s)
+	}
+	if s := run("git push origin feature:prod"); s.Decision != "ask" {
+		t.Fatalf("expected ask for a push to prod, got %+v", s)
+	}
+}
@@ -0,0 +1,28 @@
+package hooks
+
+import "path"
+
+// matchGlobSegments reports whether the slash-separated parts of a path
+// match pattern, one path.Match glob per segment. A "**" segment matches
+// any number of path segments, including none. The path guard and
+// CODEOWNERS patterns are both turned into segments for it.
+func matchGlobSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchGlobSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], parts[1:])
+}
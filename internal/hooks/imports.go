@@ -0,0 +1,158 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Language keys accepted in the imports plugin's "languages" settings
+const (
+	ImportsLanguageGo         = "go"
+	ImportsLanguagePython     = "python"
+	ImportsLanguageJavaScript = "javascript"
+)
+
+// importOrganizer describes one tool that can organize imports for a language.
+// Organizers are tried in order and the first one found on PATH is used.
+type importOrganizer struct {
+	command string
+	args    []string
+}
+
+// importOrganizersByLanguage lists import organizers per language in preference order
+var importOrganizersByLanguage = map[string][]importOrganizer{
+	ImportsLanguageGo: {
+		{command: "goimports", args: []string{"-w"}},
+	},
+	ImportsLanguagePython: {
+		{command: "isort", args: []string{"--quiet"}},
+		{command: "ruff", args: []string{"check", "--select", "I", "--fix", "--quiet"}},
+	},
+	// Dedicated import sorters only: eslint --fix would apply every fix the
+	// project's lint config allows, not just import order. Biome runs with
+	// its formatter and linter off, leaving only its organize-imports action.
+	ImportsLanguageJavaScript: {
+		{command: "biome", args: []string{"check", "--write", "--formatter-enabled=false", "--linter-enabled=false"}},
+		{command: "organize-imports-cli"},
+	},
+}
+
+// ImportsHook organizes imports in files changed by Edit, MultiEdit or Write. It is kept
+// separate from the format hook so import hygiene can be enabled on its own
+// and toggled per language via plugin settings.
+type ImportsHook struct {
+	*core.BaseHook
+	lookPath        func(string) (string, error)
+	languageEnabled func(language string) bool
+}
+
+// NewImportsHook creates a new imports hook instance
+func NewImportsHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("imports", "Import Organizer Hook", "Organizes imports (goimports, isort, biome) in changed files", ctx)
+	return &ImportsHook{
+		BaseHook: base,
+		lookPath: exec.LookPath,
+		languageEnabled: func(language string) bool {
+			return config.IsPluginLanguageEnabled("imports", language)
+		},
+	}
+}
+
+// Manifest describes the imports hook
+func (h *ImportsHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PostToolUseEvent)}
+	m.DefaultEvent = string(core.PostToolUseEvent)
+	m.DefaultMatcher = "Edit|MultiEdit|Write"
+	m.Capabilities = []core.Capability{core.CapabilityModifiesFiles, core.CapabilityRunsCommands}
+	return m
+}
+
+// Run executes the imports hook.
+func (h *ImportsHook) Run() error {
+	return h.StandardRun(nil, h.postToolUseHandler)
+}
+
+func (h *ImportsHook) postToolUseHandler(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+	switch event.ToolName {
+	case constants.ToolEdit, constants.ToolMultiEdit, constants.ToolWrite:
+	default:
+		return cchooks.Allow()
+	}
+
+	// A MultiEdit may touch several files; each is organized in turn
+	for _, filePath := range core.ParseToolPayload(event.ToolName, event.ToolInput).Files() {
+		if filePath == "" {
+			continue
+		}
+		if err := h.organizeImports(ctx, event.ToolName, filePath); err != nil {
+			userMsg := fmt.Sprintf("Import organization failed for %s", filepath.Base(filePath))
+			agentMsg := fmt.Sprintf("Organizing imports failed for %s: %v", filePath, err)
+			return core.PostBlockWithMessages(userMsg, agentMsg)
+		}
+	}
+	return cchooks.Allow()
+}
+
+// organizeImports runs the first available organizer for the file's language.
+// Disabled languages and missing tools are skipped rather than treated as errors.
+func (h *ImportsHook) organizeImports(ctx context.Context, toolName, filePath string) error {
+	language := importsLanguageForFile(filePath)
+	if language == "" || !h.languageEnabled(language) {
+		return nil
+	}
+
+	for _, org := range importOrganizersByLanguage[language] {
+		if _, err := h.lookPath(org.command); err != nil {
+			continue
+		}
+		h.logImportsEvent(toolName, filePath, language, org.command)
+
+		args := append(append([]string{}, org.args...), filePath)
+		output, err := h.Context().CommandExecutor.ExecuteCommand(ctx, org.command, args...)
+		if err != nil {
+			log.Printf("%s error on %s: %s", org.command, filePath, output)
+			return fmt.Errorf("%s failed: %s", org.command, output)
+		}
+		h.Progressf("Organized imports with %s: %s\n", org.command, filePath)
+		return nil
+	}
+	return nil
+}
+
+func (h *ImportsHook) logImportsEvent(toolName, filePath, language, organizer string) {
+	if !h.Context().LoggingEnabled {
+		return
+	}
+	details := map[string]interface{}{
+		"file_path": filePath,
+		"language":  language,
+		"organizer": organizer,
+	}
+	rawData := map[string]interface{}{
+		"tool_name": toolName,
+	}
+	h.LogHookEvent("organize_imports", toolName, rawData, details)
+}
+
+// importsLanguageForFile maps a file extension to an imports language key
+func importsLanguageForFile(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".go":
+		return ImportsLanguageGo
+	case ".py", ".pyi":
+		return ImportsLanguagePython
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs":
+		return ImportsLanguageJavaScript
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// newTestImportsHook returns an imports hook whose PATH lookups succeed only
+// for the listed tools and whose language toggles come from disabled.
+func newTestImportsHook(mockCmd *core.MockCommandExecutor, available []string, disabled ...string) *ImportsHook {
+	ctx := core.TestHookContext(nil)
+	ctx.CommandExecutor = mockCmd
+	hook := NewImportsHook(ctx).(*ImportsHook)
+	hook.lookPath = func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	hook.languageEnabled = func(language string) bool {
+		for _, d := range disabled {
+			if d == language {
+				return false
+			}
+		}
+		return true
+	}
+	return hook
+}
+
+func TestImportsHook(t *testing.T) {
+	hook := NewImportsHook(core.TestHookContext(nil))
+	if hook.Key() != "imports" {
+		t.Errorf("Expected key 'imports', got '%s'", hook.Key())
+	}
+	if err := hook.Run(); err != nil {
+		t.Errorf("Hook run failed: %v", err)
+	}
+}
+
+func TestImportsHookOrganizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		available []string
+		disabled  []string
+		wantCmd   string
+		wantArgs  []string
+	}{
+		{"go uses goimports", "main.go", []string{"goimports"}, nil, "goimports", []string{"-w", "main.go"}},
+		{"python prefers isort", "app.py", []string{"isort", "ruff"}, nil, "isort", []string{"--quiet", "app.py"}},
+		{"python falls back to ruff", "app.py", []string{"ruff"}, nil, "ruff", []string{"check", "--select", "I"}},
+		{"typescript prefers biome", "index.tsx", []string{"biome", "organize-imports-cli"}, nil, "biome", []string{"check", "--write", "--formatter-enabled=false", "--linter-enabled=false", "index.tsx"}},
+		{"javascript falls back to organize-imports-cli", "app.js", []string{"organize-imports-cli"}, nil, "organize-imports-cli", []string{"app.js"}},
+		{"eslint is not an organizer", "app.js", []string{"eslint"}, nil, "", nil},
+		{"disabled language skipped", "app.py", []string{"isort"}, []string{"python"}, "", nil},
+		{"missing tool skipped", "main.go", nil, nil, "", nil},
+		{"unknown extension skipped", "README.md", []string{"goimports"}, nil, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCmd := core.NewMockCommandExecutor()
+			hook := newTestImportsHook(mockCmd, tt.available, tt.disabled...)
+
+			if err := hook.organizeImports(context.Background(), "Edit", tt.file); err != nil {
+				t.Fatalf("organizeImports returned error: %v", err)
+			}
+
+			executed := mockCmd.GetExecutedCommands()
+			if tt.wantCmd == "" {
+				if len(executed) != 0 {
+					t.Fatalf("expected no commands, got %+v", executed)
+				}
+				return
+			}
+			if !mockCmd.WasCommandExecuted(tt.wantCmd, tt.wantArgs...) {
+				t.Errorf("expected %s %v to run, got %+v", tt.wantCmd, tt.wantArgs, executed)
+			}
+		})
+	}
+}
+
+func TestImportsHookOrganizerFailure(t *testing.T) {
+	mockCmd := core.NewMockCommandExecutor()
+	mockCmd.SetResponse("goimports -w", []byte("main.go:1: syntax error"), errors.New("exit status 1"))
+	hook := newTestImportsHook(mockCmd, []string{"goimports"})
+
+	if err := hook.organizeImports(context.Background(), "Write", "main.go"); err == nil {
+		t.Error("expected error when goimports fails")
+	}
+}
+
+func TestImportsHookMultiEditOrganizesEachFile(t *testing.T) {
+	mockCmd := core.NewMockCommandExecutor()
+	hook := newTestImportsHook(mockCmd, []string{"goimports", "isort"})
+
+	hook.postToolUseHandler(context.Background(), &cchooks.PostToolUseEvent{
+		ToolName:  "MultiEdit",
+		ToolInput: json.RawMessage(`{"file_path":"main.go","edits":[{"old_string":"a","new_string":"b"},{"file_path":"tools/gen.py","old_string":"c","new_string":"d"},{"file_path":"README.md","old_string":"e","new_string":"f"}]}`),
+	})
+
+	if !mockCmd.WasCommandExecuted("goimports", "-w", "main.go") {
+		t.Errorf("expected goimports on main.go, got %+v", mockCmd.GetExecutedCommands())
+	}
+	if !mockCmd.WasCommandExecuted("isort", "--quiet", "tools/gen.py") {
+		t.Errorf("expected isort on tools/gen.py, got %+v", mockCmd.GetExecutedCommands())
+	}
+	if n := len(mockCmd.GetExecutedCommands()); n != 2 {
+		t.Errorf("expected 2 organizer runs, got %d", n)
+	}
+}
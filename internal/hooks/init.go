@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
@@ -10,13 +11,28 @@ import (
 // init registers all built-in hooks using batch registration for better performance
 func init() {
 	builtinHooks := map[string]core.HookFactory{
-		"security":      NewSecurityHook,
-		"format":        NewFormatHook,
-		"debug":         NewDebugHook,
-		"audit":         NewAuditHook,
-		"vet":           NewVetHook,
-		"fetch-blocker": NewFetchBlockerHook,
-		"find-blocker":  NewFindBlockerHook,
+		"security":       NewSecurityHook,
+		"format":         NewFormatHook,
+		"debug":          NewDebugHook,
+		"audit":          NewAuditHook,
+		"vet":            NewVetHook,
+		"fetch-blocker":  NewFetchBlockerHook,
+		"find-blocker":   NewFindBlockerHook,
+		"imports":        NewImportsHook,
+		"codeowners":     NewCodeOwnersHook,
+		"large-files":    NewLargeFilesHook,
+		"delete-guard":   NewDeleteGuardHook,
+		"secrets":        NewSecretsHook,
+		"lockfile-churn": NewLockfileChurnHook,
+		"git-guard":      NewGitGuardHook,
+		"mcp-guard":      NewMCPGuardHook,
+		"deny-tools":     NewDenyToolsHook,
+		"path-guard":     NewPathGuardHook,
+		"pr-readiness":   NewPRReadinessHook,
+		"coverage-gate":  NewCoverageGateHook,
+		"notify":         NewNotifyHook,
+		"changelog":      NewChangelogHook,
+		"context":        NewContextHook,
 		// "performance": NewPerformanceHook, // TODO: Enable when performance.go is properly integrated
 	}
 	core.RegisterBuiltinHooks(builtinHooks)
@@ -44,11 +60,14 @@ func buildConfigHookFactories(cfg *config.CustomHooksConfig) map[string]core.Hoo
 	factories := make(map[string]core.HookFactory)
 
 	for groupName, group := range *cfg {
-		for eventName, eventCfg := range group {
+		if group == nil {
+			continue
+		}
+		for eventName, eventCfg := range group.Events {
 			if eventCfg == nil {
 				continue
 			}
-			addJobFactories(factories, groupName, eventName, eventCfg.Jobs)
+			addJobFactories(factories, groupName, eventName, eventCfg, group)
 		}
 	}
 
@@ -56,16 +75,41 @@ func buildConfigHookFactories(cfg *config.CustomHooksConfig) map[string]core.Hoo
 }
 
 // addJobFactories adds hook factories for each job in the configuration
-func addJobFactories(factories map[string]core.HookFactory, groupName, eventName string, jobs []config.HookJob) {
-	for _, job := range jobs {
+func addJobFactories(factories map[string]core.HookFactory, groupName, eventName string, eventCfg *config.EventConfig, group *config.HookGroup) {
+	lock, lockWait := group.Lock, time.Duration(group.LockTimeout)*time.Second
+	var earlier []string
+	var chainWait time.Duration
+	var all []string
+	for _, job := range eventCfg.Jobs {
+		if job.Name != "" {
+			all = append(all, job.Name)
+		}
+	}
+	for _, job := range eventCfg.Jobs {
 		if job.Name == "" {
 			continue
 		}
 		key := fmt.Sprintf("config:%s:%s", groupName, job.Name)
 		// Capture variables for closure
 		g, j, e := groupName, job, eventName
+		if j.MaxInputBytes == 0 {
+			j.MaxInputBytes = eventCfg.MaxInputBytes
+		}
+		chained, after, wait := eventCfg.Chain, earlier, chainWait
+		before, afterAll := eventCfg.BeforeAll, eventCfg.AfterAll
 		factories[key] = func(ctx *core.HookContext) core.Hook {
-			return NewConfigHook(g, j.Name, j, e, ctx)
+			h := NewConfigHook(g, j.Name, j, e, ctx).(*ConfigHook)
+			h.lockName, h.lockWait = lock, lockWait
+			h.chained, h.chainAfter, h.chainWait = chained, after, wait
+			h.beforeAll, h.afterAll, h.eventJobs = before, afterAll, all
+			return h
+		}
+		// A chained job waits as long as the jobs before it may take together
+		earlier = append(earlier[:len(earlier):len(earlier)], j.Name)
+		if j.Timeout > 0 {
+			chainWait += time.Duration(j.Timeout) * time.Second
+		} else {
+			chainWait += defaultChainWait
 		}
 	}
 }
@@ -0,0 +1,165 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const (
+	// defaultMaxWriteBytes is the Write size limit when largeFiles.maxBytes is unset
+	defaultMaxWriteBytes int64 = 1 << 20
+	// binarySniffBytes is how much content is inspected for NUL bytes, as git does
+	binarySniffBytes = 8000
+)
+
+// LargeFilesHook stops Write calls that would create oversized or binary
+// files outside the directories allowed by largeFiles.allowedDirs, so agents
+// don't drop datasets or build artifacts into the repository.
+type LargeFilesHook struct {
+	*core.BaseHook
+}
+
+// NewLargeFilesHook creates a new large-file and binary-write guard hook instance
+func NewLargeFilesHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("large-files", "Large File Guard", "Blocks or asks before Writes that create large or binary files outside allowed directories", ctx)
+	return &LargeFilesHook{BaseHook: base}
+}
+
+// Manifest describes the large-files hook
+func (h *LargeFilesHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Write"
+	m.SettingsKey = "largeFiles"
+	m.SettingsSchema = config.SectionSchema(config.LargeFilesConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the large-file guard hook.
+func (h *LargeFilesHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// oversizedWrite describes why a Write was flagged
+type oversizedWrite struct {
+	Path   string
+	Size   int64
+	Binary bool
+}
+
+func (h *LargeFilesHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	if event.ToolName != constants.ToolWrite {
+		return cchooks.Approve()
+	}
+
+	payload := core.ParseToolPayload(event.ToolName, event.ToolInput)
+	if len(payload.Edits) == 0 {
+		return cchooks.Approve()
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+
+	edit := payload.Edits[0]
+	flagged := checkWrite(edit.FilePath, edit.NewString, cfg.MaxBytes)
+	if flagged == nil || inAllowedDir(root, edit.FilePath, cfg.AllowedDirs) {
+		return cchooks.Approve()
+	}
+	return h.respond(event.ToolName, *flagged, cfg)
+}
+
+// respond blocks the write, or asks the user when largeFiles.action is "ask"
+func (h *LargeFilesHook) respond(toolName string, w oversizedWrite, cfg config.LargeFilesConfig) cchooks.PreToolUseResponseInterface {
+	reason := fmt.Sprintf("%s is %s", w.Path, core.FormatBytes(w.Size))
+	if w.Binary {
+		reason = fmt.Sprintf("%s contains binary content", w.Path)
+	}
+	details := map[string]interface{}{"file": w.Path, "size": w.Size, "binary": w.Binary}
+
+	agentMsg := fmt.Sprintf("Writing %s would add a large or binary file to the repository. Generated data, datasets and build output belong outside version control; write to an ignored or allowed directory (largeFiles.allowedDirs) or produce a smaller text file instead.", reason)
+	if strings.EqualFold(cfg.Action, "ask") {
+		h.LogApproval("large_files_ask", toolName, details)
+		return core.AskWithMessages(fmt.Sprintf("Allow writing %s?", reason), agentMsg)
+	}
+
+	h.LogBlock("large_files_block", toolName, details)
+	return core.BlockWithMessages(fmt.Sprintf("Write blocked: %s.", reason), agentMsg)
+}
+
+// loadConfig reads largeFiles settings from the project config, falling back
+// to the global config
+func (h *LargeFilesHook) loadConfig() config.LargeFilesConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.LargeFilesConfig { return c.LargeFiles })
+}
+
+// checkWrite reports content that exceeds maxBytes (or the default when
+// maxBytes is not positive) or looks binary. It returns nil when the write is fine.
+func checkWrite(filePath, content string, maxBytes int64) *oversizedWrite {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxWriteBytes
+	}
+	size := int64(len(content))
+	binary := isBinaryContent(content)
+	if size <= maxBytes && !binary {
+		return nil
+	}
+	return &oversizedWrite{Path: filePath, Size: size, Binary: binary}
+}
+
+// isBinaryContent uses git's heuristic: a NUL byte near the start means binary
+func isBinaryContent(content string) bool {
+	sniff := content
+	if len(sniff) > binarySniffBytes {
+		sniff = sniff[:binarySniffBytes]
+	}
+	return bytes.IndexByte([]byte(sniff), 0) >= 0
+}
+
+// inAllowedDir reports whether file lies under one of the allowed directories
+// or matches one of the allowed globs. Entries are relative to root.
+func inAllowedDir(root, file string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return false
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(root, file)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, entry := range allowed {
+		entry = strings.Trim(filepath.ToSlash(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if strings.ContainsAny(entry, "*?[") {
+			if ok, _ := path.Match(entry, rel); ok {
+				return true
+			}
+			if ok, _ := path.Match(entry, path.Dir(rel)); ok {
+				return true
+			}
+			continue
+		}
+		if rel == entry || strings.HasPrefix(rel, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestInAllowedDir(t *testing.T) {
+	root := "/repo"
+	allowed := []string{"testdata/", "assets", "fixtures/*.bin"}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"testdata/big.json", true},
+		{"/repo/assets/img/logo.png", true},
+		{"fixtures/blob.bin", true},
+		{"fixtures/blob.txt", false},
+		{"assets-old/logo.png", false},
+		{"src/main.go", false},
+		{"/other/testdata/big.json", false},
+	}
+	for _, tt := range tests {
+		if got := inAllowedDir(root, tt.file, allowed); got != tt.want {
+			t.Errorf("inAllowedDir(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWrite(t *testing.T) {
+	if w := checkWrite("a.txt", "hello", 0); w != nil {
+		t.Errorf("small text flagged: %+v", w)
+	}
+	if w := checkWrite("a.txt", strings.Repeat("x", 11), 10); w == nil || w.Binary || w.Size != 11 {
+		t.Errorf("oversized text = %+v, want size 11, not binary", w)
+	}
+	if w := checkWrite("a.bin", "PK\x00\x03", 0); w == nil || !w.Binary {
+		t.Errorf("binary content = %+v, want binary", w)
+	}
+}
+
+func TestLargeFilesHook_PreToolUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"largeFiles":{"maxBytes":16,"allowedDirs":["data"]}}`)
+
+	hook := NewLargeFilesHook(core.TestHookContext(nil)).(*LargeFilesHook)
+	write := func(path, content string) core.ResponseSummary {
+		input, _ := json.Marshal(map[string]string{"file_path": path, "content": content})
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: "Write", ToolInput: input}))
+	}
+
+	if s := write("out/dump.csv", strings.Repeat("a,b\n", 10)); s.Decision != "block" || !strings.Contains(s.UserMessage, "40 bytes") {
+		t.Fatalf("expected block for oversized write, got %+v", s)
+	}
+	if s := write("out/model.bin", "\x00\x01"); s.Decision != "block" || !strings.Contains(s.UserMessage, "binary") {
+		t.Fatalf("expected block for binary write, got %+v", s)
+	}
+	if s := write("data/dump.csv", strings.Repeat("a,b\n", 10)); s.Decision == "block" {
+		t.Fatalf("expected allowed dir to pass, got %+v", s)
+	}
+	if s := write("main.go", "package main\n"); s.Decision == "block" {
+		t.Fatalf("expected small text write to pass, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"largeFiles":{"maxBytes":16,"action":"ask"}}`)
+	if s := write("out/dump.csv", strings.Repeat("a,b\n", 10)); s.Decision != core.PreToolUseAsk {
+		t.Fatalf("expected ask when action is ask, got %+v", s)
+	}
+}
@@ -0,0 +1,325 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// defaultMaxChurnLines is the lockfileChurn.maxLines default
+const defaultMaxChurnLines = 500
+
+var (
+	// defaultLockfiles are dependency lockfiles of common package managers
+	defaultLockfiles = []string{
+		"go.sum", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml",
+		"Cargo.lock", "poetry.lock", "Pipfile.lock", "uv.lock", "Gemfile.lock", "composer.lock",
+		"mix.lock", "Podfile.lock", "pubspec.lock", "flake.lock", "packages.lock.json",
+	}
+	// defaultVendorDirs hold third-party code checked into the repository
+	defaultVendorDirs = []string{"vendor", "node_modules", "third_party"}
+)
+
+// LockfileChurnHook asks before git add or git commit stages huge changes to
+// lockfiles or vendored directories, which agents tend to sweep up with
+// their real changes and which then bury those changes in review.
+type LockfileChurnHook struct {
+	*core.BaseHook
+}
+
+// NewLockfileChurnHook creates a new lockfile churn guard hook instance
+func NewLockfileChurnHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("lockfile-churn", "Lockfile Churn Guard", "Asks before git add or commit stages large lockfile or vendored directory changes", ctx)
+	return &LockfileChurnHook{BaseHook: base}
+}
+
+// Manifest describes the lockfile-churn hook
+func (h *LockfileChurnHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = constants.ToolBash
+	m.SettingsKey = "lockfileChurn"
+	m.SettingsSchema = config.SectionSchema(config.LockfileChurnConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands}
+	return m
+}
+
+// Run executes the lockfile churn guard hook.
+func (h *LockfileChurnHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// gitStaging is a git add or git commit found in a command line
+type gitStaging struct {
+	// Dir is where git runs, after any -C
+	Dir string
+	// Commit is true for git commit, false for git add
+	Commit bool
+	// All is git add -A/-u or git commit -a
+	All bool
+	// Pathspecs are the paths given to git add
+	Pathspecs []string
+}
+
+// churn is a lockfile, or a vendored directory's files together, whose
+// change is over the limit
+type churn struct {
+	Path  string
+	Lines int
+	Files int
+}
+
+func (h *LockfileChurnHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	if event.ToolName != constants.ToolBash {
+		return cchooks.Approve()
+	}
+	bash, err := event.AsBash()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+
+	var found []churn
+	for _, s := range parseGitStaging(root, bash.Command) {
+		stats, err := stagedStats(s)
+		if err != nil {
+			// Not a repository, or git is unavailable: nothing to judge
+			continue
+		}
+		found = append(found, findChurn(stats, cfg)...)
+	}
+	if len(found) == 0 {
+		return cchooks.Approve()
+	}
+	return h.respond(bash.Command, found, cfg)
+}
+
+// respond asks about (or with lockfileChurn.action "block", blocks) the staging
+func (h *LockfileChurnHook) respond(command string, found []churn, cfg config.LockfileChurnConfig) cchooks.PreToolUseResponseInterface {
+	var parts []string
+	for _, c := range found {
+		if c.Files > 1 {
+			parts = append(parts, fmt.Sprintf("%s (%d lines across %d files)", c.Path, c.Lines, c.Files))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s (%d lines)", c.Path, c.Lines))
+		}
+	}
+	summary := strings.Join(parts, ", ")
+	details := map[string]interface{}{"command": command, "churn": summary}
+
+	agentMsg := fmt.Sprintf("This would stage large dependency churn: %s. Changes like these bury the real change in review. If the dependency update isn't part of the task, leave these paths out (stage specific files, or 'git restore --staged <path>') and restore them; if it is, commit it separately from code changes.", summary)
+	if strings.EqualFold(cfg.Action, "block") {
+		h.LogBlock("lockfile_churn_block", constants.ToolBash, details)
+		return core.BlockWithMessages(fmt.Sprintf("Staging blocked: large changes to %s.", summary), agentMsg)
+	}
+	h.LogApproval("lockfile_churn_ask", constants.ToolBash, details)
+	return core.AskWithMessages(fmt.Sprintf("Stage large changes to %s?", summary), agentMsg)
+}
+
+// loadConfig reads lockfileChurn settings from the project config, falling back
+// to the global config
+func (h *LockfileChurnHook) loadConfig() config.LockfileChurnConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.LockfileChurnConfig { return c.LockfileChurn })
+}
+
+// gitInvocation is one git command found in a command line
+type gitInvocation struct {
+	// Dir is where git runs, after any -C
+	Dir string
+	// Sub is the subcommand, e.g. push
+	Sub  string
+	Args []string
+}
+
+// parseGitInvocations finds the git commands in a command line, skipping
+// sudo, command and environment assignments before git and the global
+// options before the subcommand
+func parseGitInvocations(root, command string) []gitInvocation {
+	var found []gitInvocation
+	for _, segment := range commandSeparatorPattern.Split(command, -1) {
+		tokens := unquoteTokens(strings.Fields(segment))
+		for len(tokens) > 0 && (tokens[0] == "sudo" || tokens[0] == "command" || isEnvAssignment(tokens[0])) {
+			tokens = tokens[1:]
+		}
+		if len(tokens) < 2 || filepath.Base(tokens[0]) != "git" {
+			continue
+		}
+		g := gitInvocation{Dir: root}
+		i := 1
+		// Global options come before the subcommand
+		for ; i < len(tokens) && strings.HasPrefix(tokens[i], "-"); i++ {
+			switch tokens[i] {
+			case "-C":
+				if i+1 < len(tokens) {
+					i++
+					g.Dir = tokens[i]
+					if !filepath.IsAbs(g.Dir) {
+						g.Dir = filepath.Join(root, g.Dir)
+					}
+				}
+			case "-c", "--git-dir", "--work-tree":
+				i++
+			}
+		}
+		if i >= len(tokens) {
+			continue
+		}
+		g.Sub, g.Args = tokens[i], tokens[i+1:]
+		found = append(found, g)
+	}
+	return found
+}
+
+// parseGitStaging finds git add and git commit invocations in a command line
+func parseGitStaging(root, command string) []gitStaging {
+	var found []gitStaging
+	for _, g := range parseGitInvocations(root, command) {
+		s := gitStaging{Dir: g.Dir}
+		switch g.Sub {
+		case "add":
+			for _, a := range g.Args {
+				if a == "-A" || a == "--all" || a == "-u" || a == "--update" {
+					s.All = true
+				}
+			}
+			s.Pathspecs = operands(g.Args)
+			if !s.All && len(s.Pathspecs) == 0 {
+				continue
+			}
+		case "commit":
+			s.Commit = true
+			for _, a := range g.Args {
+				if a == "--all" || (strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--") && strings.Contains(a, "a")) {
+					s.All = true
+				}
+			}
+		default:
+			continue
+		}
+		found = append(found, s)
+	}
+	return found
+}
+
+// stagedStats measures what s would stage. For git add that is the
+// unstaged changes and untracked files under its pathspecs; for git commit
+// it is the index, or with -a every tracked change since HEAD.
+func stagedStats(s gitStaging) ([]core.DiffStat, error) {
+	if s.Commit {
+		if s.All {
+			if stats, err := core.GitDiffStats(s.Dir, "HEAD", "--"); err == nil {
+				return stats, nil
+			}
+		}
+		return core.GitDiffStats(s.Dir, "--cached", "--")
+	}
+
+	stats, err := core.GitDiffStats(s.Dir, append([]string{"--"}, s.Pathspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := core.GitUntrackedFiles(s.Dir, s.Pathspecs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range untracked {
+		stats = append(stats, core.DiffStat{Path: f, Added: countLines(filepath.Join(s.Dir, f))})
+	}
+	return stats, nil
+}
+
+// findChurn groups the changes to lockfiles and vendored directories and
+// returns those over the limit, largest first
+func findChurn(stats []core.DiffStat, cfg config.LockfileChurnConfig) []churn {
+	maxLines := cfg.MaxLines
+	if maxLines <= 0 {
+		maxLines = defaultMaxChurnLines
+	}
+	lockfiles := append(append([]string{}, defaultLockfiles...), cfg.Lockfiles...)
+	vendorDirs := append(append([]string{}, defaultVendorDirs...), cfg.VendorDirs...)
+
+	groups := map[string]*churn{}
+	for _, st := range stats {
+		key := churnGroup(filepath.ToSlash(st.Path), lockfiles, vendorDirs)
+		if key == "" {
+			continue
+		}
+		if groups[key] == nil {
+			groups[key] = &churn{Path: key}
+		}
+		groups[key].Lines += st.Lines()
+		groups[key].Files++
+	}
+
+	var found []churn
+	for _, c := range groups {
+		if c.Lines > maxLines {
+			found = append(found, *c)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Lines != found[j].Lines {
+			return found[i].Lines > found[j].Lines
+		}
+		return found[i].Path < found[j].Path
+	})
+	return found
+}
+
+// churnGroup returns the vendored directory file lies in (ending in "/"),
+// file itself when it is a lockfile, or "" otherwise
+func churnGroup(file string, lockfiles, vendorDirs []string) string {
+	for _, dir := range vendorDirs {
+		dir = strings.Trim(filepath.ToSlash(dir), "/")
+		if dir == "" {
+			continue
+		}
+		if strings.HasPrefix(file, dir+"/") {
+			return dir + "/"
+		}
+		// Nested vendor directories, e.g. web/node_modules/
+		if i := strings.Index(file, "/"+dir+"/"); i >= 0 && !strings.Contains(dir, "/") {
+			return file[:i+len(dir)+2]
+		}
+	}
+	base := path.Base(file)
+	for _, pattern := range lockfiles {
+		if ok, _ := path.Match(pattern, base); ok {
+			return file
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return file
+		}
+	}
+	return ""
+}
+
+// countLines counts the lines of a file, 0 when it can't be read
+func countLines(name string) int {
+	f, err := os.Open(name) // #nosec G304 - file listed by git
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestParseGitStaging(t *testing.T) {
+	root := "/repo"
+	tests := []struct {
+		command string
+		want    []gitStaging
+	}{
+		{"git add go.sum main.go", []gitStaging{{Dir: root, Pathspecs: []string{"go.sum", "main.go"}}}},
+		{"git add -A", []gitStaging{{Dir: root, All: true}}},
+		{"go mod tidy && git -C sub add .", []gitStaging{{Dir: "/repo/sub", Pathspecs: []string{"."}}}},
+		{"git commit -am 'bump deps'", []gitStaging{{Dir: root, Commit: true, All: true}}},
+		{"GIT_AUTHOR_NAME=x git -c core.pager=cat commit -m msg", []gitStaging{{Dir: root, Commit: true}}},
+		{"git add", nil},
+		{"git status; echo git add x", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := parseGitStaging(root, tt.command)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Dir != w.Dir || g.Commit != w.Commit || g.All != w.All || strings.Join(g.Pathspecs, " ") != strings.Join(w.Pathspecs, " ") {
+					t.Errorf("got %+v, want %+v", g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestFindChurn(t *testing.T) {
+	stats := []core.DiffStat{
+		{Path: "go.sum", Added: 400, Deleted: 200},
+		{Path: "web/yarn.lock", Added: 10},
+		{Path: "vendor/a/a.go", Added: 300},
+		{Path: "vendor/b/b.go", Added: 300},
+		{Path: "web/node_modules/x/index.js", Added: 100},
+		{Path: "main.go", Added: 5000},
+		{Path: "deps/custom.lock", Added: 900},
+	}
+	found := findChurn(stats, config.LockfileChurnConfig{Lockfiles: []string{"*.lock"}})
+	if len(found) != 3 {
+		t.Fatalf("expected custom.lock, vendor/ and go.sum, got %+v", found)
+	}
+	if found[0].Path != "deps/custom.lock" || found[1].Path != "go.sum" || found[2].Path != "vendor/" || found[2].Files != 2 {
+		t.Errorf("unexpected churn %+v", found)
+	}
+	if found := findChurn(stats, config.LockfileChurnConfig{MaxLines: 50}); len(found) != 3 || found[2].Path != "web/node_modules/" {
+		t.Errorf("expected web/node_modules/ over a lower limit too, got %+v", found)
+	}
+}
+
+func TestLockfileChurnHook_PreToolUse(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	writeTestFile(t, filepath.Join(dir, "go.sum"), "a\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	writeTestFile(t, filepath.Join(dir, "go.sum"), strings.Repeat("example.com/m v1.0.0 h1:x=\n", 600))
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+
+	hook := NewLockfileChurnHook(core.TestHookContext(nil)).(*LockfileChurnHook)
+	run := func(command string) core.ResponseSummary {
+		raw, _ := json.Marshal(map[string]string{"command": command})
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: "Bash", ToolInput: raw}))
+	}
+
+	if s := run("git add main.go"); s.Decision == "ask" || s.Decision == "block" {
+		t.Fatalf("expected code-only add to pass, got %+v", s)
+	}
+	s := run("git add -A")
+	if s.Decision != "ask" || !strings.Contains(s.UserMessage, "go.sum (601 lines)") {
+		t.Fatalf("expected ask for go.sum churn, got %+v", s)
+	}
+	if !strings.Contains(s.AgentMessage, "git restore --staged") {
+		t.Errorf("agent message should explain how to unstage, got %q", s.AgentMessage)
+	}
+	if s := run("git commit -m wip"); s.Decision == "ask" {
+		t.Fatalf("nothing is staged yet, got %+v", s)
+	}
+	git("add", "go.sum")
+	if s := run("git commit -m wip"); s.Decision != "ask" {
+		t.Fatalf("expected ask for staged go.sum, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"lockfileChurn":{"action":"block","maxLines":1000}}`)
+	if s := run("git commit -m wip"); s.Decision == "ask" || s.Decision == "block" {
+		t.Fatalf("expected go.sum under the raised limit to pass, got %+v", s)
+	}
+}
@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestBuiltinManifests(t *testing.T) {
+	settingsKeys := map[string]bool{}
+	for _, key := range []string{"blockedUrls", "codeOwners", "largeFiles", "deleteGuard", "secrets", "lockfileChurn", "gitGuard", "mcpGuard", "denyTools", "pathGuard", "prReadiness", "coverageGate", "notify", "changelog", "context", "format", "audit"} {
+		settingsKeys[key] = true
+	}
+	for _, key := range core.GetHookKeys() {
+		hook, err := core.CreateHook(key)
+		if err != nil {
+			t.Fatalf("CreateHook(%s): %v", key, err)
+		}
+		m := hook.Manifest()
+		if m.Key != key || m.Version == "" || m.Description == "" {
+			t.Errorf("%s: incomplete manifest %+v", key, m)
+		}
+		if !m.HandlesEvent(m.DefaultEvent) {
+			t.Errorf("%s: default event %s is not among its events %v", key, m.DefaultEvent, m.Events)
+		}
+		if m.DefaultMatcher == "" {
+			t.Errorf("%s: missing default matcher", key)
+		}
+		if m.SettingsKey != "" && (!settingsKeys[m.SettingsKey] || m.SettingsSchema == nil) {
+			t.Errorf("%s: settings key %q must be a blues-traveler-config.json key with a schema", key, m.SettingsKey)
+		}
+	}
+}
+
+func TestConfigHookManifest(t *testing.T) {
+	hook := NewConfigHook("docs", "spell", config.HookJob{Name: "spell", Run: "true"}, "UserPromptSubmit", core.TestHookContext(nil))
+	m := hook.Manifest()
+	if m.DefaultEvent != "UserPromptSubmit" || !m.HandlesEvent("UserPromptSubmit") || m.HandlesEvent("PreToolUse") {
+		t.Fatalf("expected a UserPromptSubmit-only manifest, got %+v", m)
+	}
+	if !m.Has(core.CapabilityRunsCommands) || m.Has(core.CapabilityNeedsNetwork) {
+		t.Fatalf("unexpected capabilities %v", m.Capabilities)
+	}
+}
@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// MCPGuardHook limits which MCP servers and tools the agent may call. MCP
+// tools reach outside the project (issue trackers, databases, browsers), so
+// projects allowlist the ones a task needs.
+type MCPGuardHook struct {
+	*core.BaseHook
+}
+
+// NewMCPGuardHook creates a new MCP guard hook instance
+func NewMCPGuardHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("mcp-guard", "MCP Guard", "Blocks MCP tool calls outside the configured server and tool allowlists", ctx)
+	return &MCPGuardHook{BaseHook: base}
+}
+
+// Manifest describes the mcp-guard hook
+func (h *MCPGuardHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = core.MCPAnyToolMatcher
+	m.SettingsKey = "mcpGuard"
+	m.SettingsSchema = config.SectionSchema(config.MCPGuardConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the MCP guard hook.
+func (h *MCPGuardHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+func (h *MCPGuardHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	server, tool, ok := core.ParseMCPToolName(event.ToolName)
+	if !ok {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+	reason := mcpDenial(cfg, server, tool)
+	if reason == "" {
+		return cchooks.Approve()
+	}
+
+	details := map[string]interface{}{"server": server, "tool": tool, "reason": reason}
+	agentMsg := fmt.Sprintf("The MCP tool %s (server %q) is %s by this project's mcpGuard settings. Don't retry it or reach the same service another way; continue without it, or tell the user which tool you need and why.", event.ToolName, server, reason)
+	if strings.EqualFold(cfg.Action, "ask") {
+		h.LogApproval("mcp_guard_ask", event.ToolName, details)
+		return core.AskWithMessages(fmt.Sprintf("Allow MCP tool %s/%s (%s)?", server, tool, reason), agentMsg)
+	}
+	h.LogBlock("mcp_guard_block", event.ToolName, details)
+	return core.BlockWithMessages(fmt.Sprintf("MCP tool %s/%s blocked: %s.", server, tool, reason), agentMsg)
+}
+
+// mcpDenial returns why cfg refuses server's tool, or "" when it is allowed.
+// Denied tools win over both allowlists.
+func mcpDenial(cfg config.MCPGuardConfig, server, tool string) string {
+	if matchAnyMCP(cfg.DeniedTools, server, tool) {
+		return "denied"
+	}
+	if len(cfg.AllowedServers) == 0 && len(cfg.AllowedTools) == 0 {
+		return ""
+	}
+	if matchAnyMCP(cfg.AllowedServers, server, tool) || matchAnyMCP(cfg.AllowedTools, server, tool) {
+		return ""
+	}
+	return "not allowlisted"
+}
+
+// matchAnyMCP reports whether any of patterns matches server's tool
+func matchAnyMCP(patterns []string, server, tool string) bool {
+	for _, p := range patterns {
+		if core.MatchMCPPattern(p, server, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfig reads mcpGuard settings from the project config, falling back
+// to the global config
+func (h *MCPGuardHook) loadConfig() config.MCPGuardConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.MCPGuardConfig { return c.MCPGuard })
+}
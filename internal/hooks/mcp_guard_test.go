@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestMCPDenial(t *testing.T) {
+	cfg := config.MCPGuardConfig{
+		AllowedServers: []string{"github"},
+		AllowedTools:   []string{"slack/post_message"},
+		DeniedTools:    []string{"github/delete_*"},
+	}
+	tests := []struct {
+		server, tool, want string
+	}{
+		{"github", "create_issue", ""},
+		{"github", "delete_repo", "denied"},
+		{"slack", "post_message", ""},
+		{"slack", "list_channels", "not allowlisted"},
+		{"postgres", "query", "not allowlisted"},
+	}
+	for _, tt := range tests {
+		if got := mcpDenial(cfg, tt.server, tt.tool); got != tt.want {
+			t.Errorf("mcpDenial(%s/%s) = %q, want %q", tt.server, tt.tool, got, tt.want)
+		}
+	}
+	if got := mcpDenial(config.MCPGuardConfig{}, "postgres", "query"); got != "" {
+		t.Errorf("no allowlists should allow everything, got %q", got)
+	}
+}
+
+func TestMCPGuardHook_PreToolUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"mcpGuard":{"allowedServers":["github"],"deniedTools":["mcp__github__delete_repo"]}}`)
+
+	hook := NewMCPGuardHook(core.TestHookContext(nil)).(*MCPGuardHook)
+	run := func(tool string) core.ResponseSummary {
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: tool, ToolInput: []byte(`{}`)}))
+	}
+
+	if s := run("Bash"); s.Decision == "block" {
+		t.Fatalf("non-MCP tools should pass, got %+v", s)
+	}
+	if s := run("mcp__github__create_issue"); s.Decision == "block" {
+		t.Fatalf("allowed server should pass, got %+v", s)
+	}
+	s := run("mcp__github__delete_repo")
+	if s.Decision != "block" || !strings.Contains(s.UserMessage, "github/delete_repo blocked: denied") {
+		t.Fatalf("expected denied tool blocked, got %+v", s)
+	}
+	if s := run("mcp__postgres__query"); s.Decision != "block" || !strings.Contains(s.AgentMessage, "not allowlisted") {
+		t.Fatalf("expected other servers blocked, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"mcpGuard":{"allowedServers":["github"],"action":"ask"}}`)
+	if s := run("mcp__postgres__query"); s.Decision != "ask" {
+		t.Fatalf("expected ask, got %+v", s)
+	}
+}
@@ -0,0 +1,370 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Notify sink types
+const (
+	notifySinkDesktop = "desktop"
+	notifySinkSlack   = "slack"
+	notifySinkWebhook = "webhook"
+)
+
+const (
+	// defaultNotifyTimeout bounds each sink, in seconds
+	defaultNotifyTimeout = 5
+	// defaultNotifyTitle is the desktop title when neither the event nor the sink sets one
+	defaultNotifyTitle = "Claude Code"
+)
+
+// notifyGOOS selects the desktop notifier; tests override it
+var notifyGOOS = runtime.GOOS
+
+// notifyHTTPClient sends Slack and webhook requests; each request carries
+// its sink's timeout
+var notifyHTTPClient = &http.Client{}
+
+// NotifyHook forwards Notification and Stop events to desktop
+// notifications, Slack and HTTP endpoints, so the user hears when the agent
+// needs them or has finished without watching the terminal.
+type NotifyHook struct {
+	*core.BaseHook
+}
+
+// NewNotifyHook creates a new notify hook instance
+func NewNotifyHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("notify", "Notify", "Forwards Notification and Stop events to desktop, Slack and webhook sinks", ctx)
+	return &NotifyHook{BaseHook: base}
+}
+
+// Manifest describes the notify hook
+func (h *NotifyHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.NotificationEvent), string(core.StopEvent)}
+	m.DefaultEvent = string(core.NotificationEvent)
+	m.SettingsKey = "notify"
+	m.SettingsSchema = config.SectionSchema(config.NotifyConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityRunsCommands, core.CapabilityNeedsNetwork}
+	return m
+}
+
+// Run executes the notify hook
+func (h *NotifyHook) Run() error {
+	return h.RunRaw(h.eventHandler)
+}
+
+// notifyEvent is the data sink templates render
+type notifyEvent struct {
+	Event     string `json:"event"`
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	Type      string `json:"type,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Project   string `json:"project"`
+	Cwd       string `json:"cwd"`
+	Time      string `json:"time"`
+	// Count is how many notifications a digest stands for; 1 otherwise
+	Count int `json:"count"`
+}
+
+func (h *NotifyHook) eventHandler(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+	var raw struct {
+		HookEventName    string `json:"hook_event_name"`
+		SessionID        string `json:"session_id"`
+		Cwd              string `json:"cwd"`
+		Message          string `json:"message"`
+		Title            string `json:"title"`
+		NotificationType string `json:"notification_type"`
+		StopHookActive   bool   `json:"stop_hook_active"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &raw); err != nil {
+		return nil
+	}
+	switch raw.HookEventName {
+	case string(core.NotificationEvent):
+	case string(core.StopEvent):
+		if raw.StopHookActive {
+			// The agent is finishing a turn a Stop hook asked for; the
+			// first Stop was already announced
+			return &cchooks.RawResponse{}
+		}
+	default:
+		return nil
+	}
+
+	event := newNotifyEvent(raw.HookEventName, raw.Title, raw.Message, raw.NotificationType, raw.SessionID, raw.Cwd)
+	h.deliver(ctx, h.loadConfig().Sinks, event)
+	return &cchooks.RawResponse{}
+}
+
+// newNotifyEvent fills in what the payload leaves out
+func newNotifyEvent(name, title, message, kind, sessionID, cwd string) notifyEvent {
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+	project := filepath.Base(cwd)
+	if title == "" {
+		title = defaultNotifyTitle
+	}
+	if message == "" && name == string(core.StopEvent) {
+		message = fmt.Sprintf("Finished working in %s", project)
+	}
+	return notifyEvent{
+		Event:     name,
+		Title:     title,
+		Message:   message,
+		Type:      kind,
+		SessionID: sessionID,
+		Project:   project,
+		Cwd:       cwd,
+		Time:      time.Now().Format(time.RFC3339),
+		Count:     1,
+	}
+}
+
+// deliver sends event to each sink that takes it, concurrently. Failures
+// are logged; a notification never holds up the agent. Sinks with a digest
+// window are also visited on Stop to send what they hold.
+func (h *NotifyHook) deliver(ctx context.Context, sinks []config.NotifySink, event notifyEvent) {
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		takes := len(sink.Events) == 0 || containsFold(sink.Events, event.Event)
+		if !takes && sink.DigestWindow <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, sink config.NotifySink) {
+			defer wg.Done()
+			name := sink.Name
+			if name == "" {
+				name = sink.Type
+			}
+			out, ok := h.digest(i, sink, event, takes)
+			if !ok {
+				return
+			}
+			if err := h.send(ctx, sink, out); err != nil {
+				h.LogError("notify_error", "", fmt.Errorf("sink %s: %w", name, err))
+				return
+			}
+			h.LogHookEvent("notify_sent", "", map[string]interface{}{"sink": name, "event": out.Event, "count": out.Count}, nil)
+		}(i, sink)
+	}
+	wg.Wait()
+}
+
+// digest runs event through the digest of a sink with a digest window and
+// returns what to send now; ok is false when the sink has nothing to send.
+// takes says whether event is for this sink or only flushes its digest.
+func (h *NotifyHook) digest(index int, sink config.NotifySink, event notifyEvent, takes bool) (notifyEvent, bool) {
+	if sink.DigestWindow <= 0 {
+		return event, takes
+	}
+	key := sink.Name
+	if key == "" {
+		key = fmt.Sprintf("%s-%d", sink.Type, index)
+	}
+	var in *notifyEvent
+	if takes {
+		in = &event
+	}
+	window := time.Duration(sink.DigestWindow) * time.Second
+	out, err := batchNotification(key, window, in, event.Event == string(core.StopEvent), time.Now())
+	if err != nil {
+		// Send it on its own rather than lose it
+		h.LogError("notify_error", "", fmt.Errorf("sink %s digest: %w", key, err))
+		return event, takes
+	}
+	if out == nil {
+		if takes {
+			h.LogHookEvent("notify_held", "", map[string]interface{}{"sink": key, "event": event.Event}, nil)
+		}
+		return notifyEvent{}, false
+	}
+	return *out, true
+}
+
+// send delivers event to one sink within its timeout
+func (h *NotifyHook) send(ctx context.Context, sink config.NotifySink, event notifyEvent) error {
+	timeout := sink.Timeout
+	if timeout <= 0 {
+		timeout = defaultNotifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	switch strings.ToLower(sink.Type) {
+	case notifySinkDesktop:
+		title, err := renderNotifyTemplate(sink.Title, "{{.Title}}", event)
+		if err != nil {
+			return err
+		}
+		body, err := renderNotifyTemplate(sink.Template, "{{.Message}}", event)
+		if err != nil {
+			return err
+		}
+		name, args, err := desktopNotifyCommand(notifyGOOS, title, body)
+		if err != nil {
+			return err
+		}
+		if out, err := h.Context().CommandExecutor.ExecuteCommand(ctx, name, args...); err != nil {
+			return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	case notifySinkSlack:
+		text, err := renderNotifyTemplate(sink.Template, "*{{.Title}}* ({{.Project}}): {{.Message}}", event)
+		if err != nil {
+			return err
+		}
+		payload, err := json.Marshal(map[string]string{"text": text})
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, http.MethodPost, sink.URL, nil, payload)
+	case notifySinkWebhook:
+		var body []byte
+		if sink.Template == "" {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			body = data
+		} else {
+			text, err := renderNotifyTemplate(sink.Template, "", event)
+			if err != nil {
+				return err
+			}
+			body = []byte(text)
+		}
+		method := strings.ToUpper(sink.Method)
+		if method == "" {
+			method = http.MethodPost
+		}
+		return postJSON(ctx, method, sink.URL, sink.Headers, body)
+	default:
+		return fmt.Errorf("unknown sink type %q (use desktop, slack or webhook)", sink.Type)
+	}
+}
+
+// notifyTemplateFuncs are available in sink templates; json quotes a value
+// for use inside a JSON body
+var notifyTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// renderNotifyTemplate executes text, or fallback when text is empty
+func renderNotifyTemplate(text, fallback string, event notifyEvent) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New("notify").Funcs(notifyTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", fmt.Errorf("template failed: %w", err)
+	}
+	return b.String(), nil
+}
+
+// desktopNotifyCommand returns the command that shows a desktop
+// notification on goos. Text is passed as arguments, never spliced into a
+// script, except on Windows where it is quoted for PowerShell.
+func desktopNotifyCommand(goos, title, body string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "osascript", []string{
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body,
+		}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=blues-traveler", title, body}, nil
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := strings.Join([]string{
+			"Add-Type -AssemblyName System.Windows.Forms",
+			"$n = New-Object System.Windows.Forms.NotifyIcon",
+			"$n.Icon = [System.Drawing.SystemIcons]::Information",
+			"$n.Visible = $true",
+			"$n.ShowBalloonTip(5000, " + quote(title) + ", " + quote(body) + ", 'Info')",
+			"Start-Sleep -Seconds 3",
+			"$n.Dispose()",
+		}, "; ")
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}, nil
+	default:
+		return "", nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+	}
+}
+
+// postJSON sends a JSON body to endpoint and fails on a non-2xx response.
+// It serves notification sinks and the audit hook's OTLP export. The
+// endpoint and header values expand environment variables. Errors leave
+// the URL out, since webhook URLs often embed their secret.
+func postJSON(ctx context.Context, method, endpoint string, headers map[string]string, body []byte) error {
+	endpoint = os.ExpandEnv(endpoint)
+	if endpoint == "" {
+		return fmt.Errorf("url is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid url or method")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "blues-traveler")
+	for k, v := range headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	resp, err := notifyHTTPClient.Do(req) // #nosec G107 - user-configured notification endpoint
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// containsFold reports whether list has s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfig reads notify settings from the project config, falling back
+// to the global one so personal sinks can be set once for every project
+func (h *NotifyHook) loadConfig() config.NotifyConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.NotifyConfig { return c.Notify })
+}
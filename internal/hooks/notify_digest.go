@@ -0,0 +1,112 @@
+package hooks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const (
+	// notifyDigestFile keeps each digest sink's held notifications in the
+	// project's state directory; every hook run is a new process
+	notifyDigestFile = "notify-digest.json"
+	// notifyDigestLockWait bounds waiting for another hook process that is
+	// updating the digest state
+	notifyDigestLockWait = 2 * time.Second
+	// notifyDigestMaxLines caps the messages a digest lists; older ones are
+	// only counted
+	notifyDigestMaxLines = 10
+)
+
+// notifyDigestState is what one sink has sent and is holding back
+type notifyDigestState struct {
+	LastSent time.Time     `json:"last_sent"`
+	Held     int           `json:"held,omitempty"`
+	Pending  []notifyEvent `json:"pending,omitempty"`
+}
+
+// batchNotification adds event (when not nil) to the digest of the sink
+// named key and returns the notification to send now, or nil while the
+// window since the last one sent is still open. The first notification
+// after a quiet window goes out on its own; flush sends whatever is held
+// regardless of the window.
+func batchNotification(key string, window time.Duration, event *notifyEvent, flush bool, now time.Time) (*notifyEvent, error) {
+	root, err := core.StateRoot()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(root))
+	release, err := core.AcquireNamedLock("notify-digest-"+hex.EncodeToString(sum[:6]), notifyDigestLockWait)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	path := filepath.Join(root, notifyDigestFile)
+	states := map[string]*notifyDigestState{}
+	if data, err := os.ReadFile(path); err == nil { // #nosec G304 - fixed file under the state dir
+		// A corrupt file only loses what it held
+		_ = json.Unmarshal(data, &states)
+	}
+	state := states[key]
+	if state == nil {
+		state = &notifyDigestState{}
+		states[key] = state
+	}
+	if event != nil {
+		state.Held++
+		state.Pending = append(state.Pending, *event)
+		if len(state.Pending) > notifyDigestMaxLines {
+			state.Pending = state.Pending[len(state.Pending)-notifyDigestMaxLines:]
+		}
+	}
+	if state.Held == 0 {
+		return nil, nil
+	}
+
+	var out *notifyEvent
+	if flush || now.Sub(state.LastSent) >= window {
+		digest := summarizeNotifications(state.Held, state.Pending)
+		out = &digest
+		state.LastSent = now
+		state.Held, state.Pending = 0, nil
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.Marshal(states)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write notify digest: %w", err)
+	}
+	return out, nil
+}
+
+// summarizeNotifications folds held notifications into one, taking the
+// event, title and context of the latest and listing the messages
+func summarizeNotifications(held int, pending []notifyEvent) notifyEvent {
+	digest := pending[len(pending)-1]
+	digest.Count = held
+	if held == 1 {
+		return digest
+	}
+	var lines []string
+	if earlier := held - len(pending); earlier > 0 {
+		lines = append(lines, fmt.Sprintf("- ... %d earlier", earlier))
+	}
+	for _, ev := range pending {
+		lines = append(lines, "- "+ev.Message)
+	}
+	digest.Message = fmt.Sprintf("%d notifications:\n%s", held, strings.Join(lines, "\n"))
+	return digest
+}
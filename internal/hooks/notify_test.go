@@ -0,0 +1,208 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestNotifyHook_Sinks(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests[r.URL.Path] = r.Method + " " + r.Header.Get("Authorization") + " " + string(body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+	request := func(path string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		got, ok := requests[path]
+		return got, ok
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NOTIFY_TEST_URL", server.URL)
+	t.Setenv("NOTIFY_TEST_TOKEN", "abc")
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"notify":{"sinks":[
+		{"type":"desktop","title":"{{.Project}}"},
+		{"type":"slack","url":"${NOTIFY_TEST_URL}/slack","events":["Stop"]},
+		{"type":"webhook","url":"${NOTIFY_TEST_URL}/hook","method":"put","headers":{"Authorization":"Bearer ${NOTIFY_TEST_TOKEN}"},
+		 "template":"{\"msg\":{{json .Message}},\"event\":\"{{.Event}}\"}"}
+	]}}`)
+
+	orig := notifyGOOS
+	notifyGOOS = "linux"
+	defer func() { notifyGOOS = orig }()
+	hookCtx := core.TestHookContext(nil)
+	executor := hookCtx.CommandExecutor.(*core.MockCommandExecutor)
+	hook := NewNotifyHook(hookCtx).(*NotifyHook)
+
+	resp := hook.eventHandler(context.Background(), `{"hook_event_name":"Notification","cwd":"/work/app","message":"Claude needs \"permission\""}`)
+	if resp == nil || resp.Output != "" {
+		t.Fatalf("expected an empty response, got %+v", resp)
+	}
+	cmds := executor.GetExecutedCommands()
+	if len(cmds) != 1 || cmds[0].Name != "notify-send" || strings.Join(cmds[0].Args[1:], "|") != `app|Claude needs "permission"` {
+		t.Fatalf("unexpected desktop command: %+v", cmds)
+	}
+	if _, ok := request("/slack"); ok {
+		t.Error("slack sink is limited to Stop")
+	}
+	if got, _ := request("/hook"); got != `PUT Bearer abc {"msg":"Claude needs \"permission\"","event":"Notification"}` {
+		t.Errorf("unexpected webhook request %q", got)
+	}
+
+	hook.eventHandler(context.Background(), `{"hook_event_name":"Stop","cwd":"/work/app","stop_hook_active":false}`)
+	var slack struct{ Text string }
+	got, _ := request("/slack")
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(got, "POST  ")), &slack); err != nil {
+		t.Fatalf("unexpected slack request %q: %v", got, err)
+	}
+	if slack.Text != "*Claude Code* (app): Finished working in app" {
+		t.Errorf("unexpected slack text %q", slack.Text)
+	}
+
+	// A Stop hook continuing the agent doesn't notify twice
+	mu.Lock()
+	delete(requests, "/slack")
+	mu.Unlock()
+	hook.eventHandler(context.Background(), `{"hook_event_name":"Stop","stop_hook_active":true}`)
+	if _, ok := request("/slack"); ok {
+		t.Error("expected no notification while a stop hook is active")
+	}
+
+	if resp := hook.eventHandler(context.Background(), `{"hook_event_name":"PreToolUse","tool_name":"Bash"}`); resp != nil {
+		t.Errorf("expected other events to pass through, got %+v", resp)
+	}
+}
+
+func TestPostNotification_HidesURLAndStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	err := postJSON(context.Background(), http.MethodPost, server.URL+"/services/secret", nil, []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected a status error, got %v", err)
+	}
+	server.Close()
+
+	err = postJSON(context.Background(), http.MethodPost, server.URL+"/services/secret", nil, []byte("{}"))
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("expected a connection error without the URL, got %v", err)
+	}
+}
+
+func TestDesktopNotifyCommand(t *testing.T) {
+	name, args, err := desktopNotifyCommand("darwin", "T", `say "hi"`)
+	if err != nil || name != "osascript" || args[len(args)-1] != `say "hi"` {
+		t.Errorf("unexpected macOS command %s %v %v", name, args, err)
+	}
+	name, args, err = desktopNotifyCommand("windows", "it's", "done")
+	if err != nil || name != "powershell" || !strings.Contains(args[len(args)-1], "'it''s', 'done'") {
+		t.Errorf("unexpected Windows command %s %v %v", name, args, err)
+	}
+	if _, _, err := desktopNotifyCommand("plan9", "T", "M"); err == nil {
+		t.Error("expected unsupported platforms to fail")
+	}
+}
+
+func TestBatchNotification_HoldsWithinWindow(t *testing.T) {
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+
+	start := time.Now()
+	ev := func(msg string) *notifyEvent { return &notifyEvent{Event: "Notification", Message: msg, Count: 1} }
+	batch := func(e *notifyEvent, flush bool, at time.Duration) *notifyEvent {
+		t.Helper()
+		out, err := batchNotification("desktop-0", time.Minute, e, flush, start.Add(at))
+		if err != nil {
+			t.Fatalf("batchNotification: %v", err)
+		}
+		return out
+	}
+
+	// The first notification after a quiet spell goes out on its own
+	if out := batch(ev("one"), false, 0); out == nil || out.Message != "one" || out.Count != 1 {
+		t.Fatalf("expected the first notification right away, got %+v", out)
+	}
+	if out := batch(ev("two"), false, 10*time.Second); out != nil {
+		t.Fatalf("expected a notification inside the window to be held, got %+v", out)
+	}
+	if out := batch(ev("three"), false, 20*time.Second); out != nil {
+		t.Fatalf("expected a notification inside the window to be held, got %+v", out)
+	}
+	out := batch(ev("four"), false, 61*time.Second)
+	if out == nil || out.Count != 3 || out.Message != "3 notifications:\n- two\n- three\n- four" {
+		t.Fatalf("expected a digest of the held notifications, got %+v", out)
+	}
+
+	// Stop flushes whatever is held, even without a new notification
+	if out := batch(ev("five"), false, 70*time.Second); out != nil {
+		t.Fatalf("expected a hold, got %+v", out)
+	}
+	if out := batch(nil, true, 71*time.Second); out == nil || out.Message != "five" {
+		t.Fatalf("expected the held notification on flush, got %+v", out)
+	}
+	if out := batch(nil, true, 72*time.Second); out != nil {
+		t.Fatalf("expected nothing left to flush, got %+v", out)
+	}
+}
+
+func TestSummarizeNotifications_CountsDroppedMessages(t *testing.T) {
+	var pending []notifyEvent
+	for i := 0; i < notifyDigestMaxLines; i++ {
+		pending = append(pending, notifyEvent{Message: "m"})
+	}
+	got := summarizeNotifications(notifyDigestMaxLines+2, pending)
+	if got.Count != notifyDigestMaxLines+2 || !strings.Contains(got.Message, "- ... 2 earlier\n") {
+		t.Errorf("unexpected digest %+v", got)
+	}
+}
+
+func TestNotifyHook_DigestWindowBatchesUntilStop(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BT_LOCK_DIR", t.TempDir())
+	t.Setenv("NOTIFY_TEST_URL", server.URL)
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"), `{"notify":{"sinks":[
+		{"type":"webhook","url":"${NOTIFY_TEST_URL}","events":["Notification"],"digestWindow":3600,"template":"{{.Count}}|{{.Message}}"}
+	]}}`)
+	hook := NewNotifyHook(core.TestHookContext(nil)).(*NotifyHook)
+
+	for _, msg := range []string{"first", "second", "third"} {
+		hook.eventHandler(context.Background(), `{"hook_event_name":"Notification","message":"`+msg+`"}`)
+	}
+	hook.eventHandler(context.Background(), `{"hook_event_name":"Stop"}`)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"1|first", "2|2 notifications:\n- second\n- third"}
+	if len(bodies) != len(want) || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Fatalf("requests = %q, want %q", bodies, want)
+	}
+}
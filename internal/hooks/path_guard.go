@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// pathGuardOverrideEnv, set to a non-empty value in the environment Claude
+// Code starts with, lets every edit through, for emergencies such as a
+// broken deploy config that has to be fixed now
+const pathGuardOverrideEnv = "BT_PATH_GUARD_OVERRIDE"
+
+// defaultProtectedPaths are guarded when pathGuard.protected is unset
+var defaultProtectedPaths = []string{".env", ".env.*", "*.lock", "deploy/**", ".github/workflows/**"}
+
+// defaultAllowedPaths are the templates checked in next to .env files
+var defaultAllowedPaths = []string{".env.example", ".env.sample", ".env.template"}
+
+// PathGuardHook stops Edit, Write and MultiEdit calls on protected paths
+// such as secrets, lockfiles and CI workflows, which a human should change
+type PathGuardHook struct {
+	*core.BaseHook
+}
+
+// NewPathGuardHook creates a new path-guard hook instance
+func NewPathGuardHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("path-guard", "Path Guard", "Blocks edits to protected paths such as .env files, lockfiles and CI workflows", ctx)
+	return &PathGuardHook{BaseHook: base}
+}
+
+// Manifest describes the path-guard hook
+func (h *PathGuardHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Edit|MultiEdit|Write"
+	m.SettingsKey = "pathGuard"
+	m.SettingsSchema = config.SectionSchema(config.PathGuardConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the path-guard hook.
+func (h *PathGuardHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+func (h *PathGuardHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	payload := core.ParseToolPayload(event.ToolName, event.ToolInput)
+	if len(payload.Edits) == 0 {
+		return cchooks.Approve()
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	cfg := h.loadConfig()
+
+	for _, edit := range payload.Edits {
+		rel := projectRelPath(root, edit.FilePath)
+		pattern := protectedPattern(cfg, rel)
+		if pattern == "" {
+			continue
+		}
+		details := map[string]interface{}{"file": rel, "pattern": pattern}
+		if os.Getenv(pathGuardOverrideEnv) != "" {
+			h.LogApproval("path_guard_override", event.ToolName, details)
+			return cchooks.Approve()
+		}
+		agentMsg := fmt.Sprintf("%s is protected by this project's path-guard policy (%s), so a person has to change it. Don't edit it another way, such as with sed or a shell redirect; tell the user what change %s needs and why, and continue with the rest of the task.", rel, pattern, rel)
+		if strings.EqualFold(cfg.Action, "ask") {
+			h.LogApproval("path_guard_ask", event.ToolName, details)
+			return core.AskWithMessages(fmt.Sprintf("Allow %s on protected path %s (%s)?", event.ToolName, rel, pattern), agentMsg)
+		}
+		h.LogBlock("path_guard_block", event.ToolName, details)
+		return core.BlockWithMessages(fmt.Sprintf("%s blocked: %s is protected (%s). Add it to pathGuard.allowed, or set %s=1 before starting Claude Code to allow all edits in an emergency.", event.ToolName, rel, pattern, pathGuardOverrideEnv), agentMsg)
+	}
+	return cchooks.Approve()
+}
+
+// protectedPattern returns the protected pattern rel matches, or "" when
+// rel is not protected or an allowed pattern exempts it
+func protectedPattern(cfg config.PathGuardConfig, rel string) string {
+	protected, allowed := cfg.Protected, cfg.Allowed
+	if len(protected) == 0 {
+		protected = defaultProtectedPaths
+		allowed = append(append([]string{}, allowed...), defaultAllowedPaths...)
+	}
+	for _, p := range allowed {
+		if matchGuardPattern(p, rel) {
+			return ""
+		}
+	}
+	for _, p := range protected {
+		if matchGuardPattern(p, rel) {
+			return p
+		}
+	}
+	return ""
+}
+
+// projectRelPath returns file relative to root with forward slashes, or
+// the cleaned absolute path when file is outside root
+func projectRelPath(root, file string) string {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(root, file)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchGuardPattern matches rel against a glob in which ** spans any number
+// of directories. A pattern without a slash matches the base name, and one
+// ending in "/" everything under that directory.
+func matchGuardPattern(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(pattern)), "./")
+	if pattern == "" {
+		return false
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchGlobSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(strings.TrimPrefix(rel, "/"), "/"))
+}
+
+// loadConfig reads pathGuard settings from the project config, falling back
+// to the global config
+func (h *PathGuardHook) loadConfig() config.PathGuardConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.PathGuardConfig { return c.PathGuard })
+}
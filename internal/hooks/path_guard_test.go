@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestMatchGuardPattern(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{".env", ".env", true},
+		{".env", "services/api/.env", true},
+		{".env.*", ".env.production", true},
+		{"*.lock", "Cargo.lock", true},
+		{"*.lock", "lockfile.go", false},
+		{"deploy/**", "deploy/prod/values.yaml", true},
+		{"deploy/**", "deploy", true},
+		{"deploy/**", "tools/deploy/run.sh", false},
+		{"deploy/", "deploy/prod.yml", true},
+		{"**/migrations/*.sql", "db/migrations/001.sql", true},
+		{"**/migrations/*.sql", "migrations/001.sql", true},
+		{"**/migrations/*.sql", "db/migrations/old/001.sql", false},
+		{".github/workflows/**", ".github/workflows/ci.yml", true},
+		{"./config/*.yml", "config/app.yml", true},
+	}
+	for _, tt := range tests {
+		if got := matchGuardPattern(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchGuardPattern(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestProtectedPattern(t *testing.T) {
+	defaults := config.PathGuardConfig{}
+	if got := protectedPattern(defaults, ".env.local"); got != ".env.*" {
+		t.Errorf("default policy should protect .env.local, got %q", got)
+	}
+	if got := protectedPattern(defaults, ".env.example"); got != "" {
+		t.Errorf("default policy should allow .env.example, got %q", got)
+	}
+	cfg := config.PathGuardConfig{Protected: []string{"infra/**"}, Allowed: []string{"infra/README.md"}}
+	if got := protectedPattern(cfg, "infra/README.md"); got != "" {
+		t.Errorf("allowed path should pass, got %q", got)
+	}
+	if got := protectedPattern(cfg, "infra/main.tf"); got != "infra/**" {
+		t.Errorf("expected infra/main.tf protected, got %q", got)
+	}
+	if got := protectedPattern(cfg, ".env"); got != "" {
+		t.Errorf("setting protected should replace the defaults, got %q", got)
+	}
+}
+
+func TestPathGuardHook_PreToolUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(pathGuardOverrideEnv, "")
+	hook := NewPathGuardHook(core.TestHookContext(nil)).(*PathGuardHook)
+	run := func(tool string, input map[string]interface{}) core.ResponseSummary {
+		raw, _ := json.Marshal(input)
+		return core.SummarizeResponse(hook.preToolUseHandler(context.Background(),
+			&cchooks.PreToolUseEvent{ToolName: tool, ToolInput: raw}))
+	}
+
+	if s := run("Edit", map[string]interface{}{"file_path": filepath.Join(dir, "main.go"), "old_string": "a", "new_string": "b"}); s.Decision == "block" {
+		t.Fatalf("unprotected file should pass, got %+v", s)
+	}
+	s := run("MultiEdit", map[string]interface{}{"file_path": filepath.Join(dir, ".github", "workflows", "ci.yml"), "edits": []map[string]string{{"old_string": "a", "new_string": "b"}}})
+	if s.Decision != "block" || !strings.Contains(s.UserMessage, ".github/workflows/ci.yml is protected (.github/workflows/**)") {
+		t.Fatalf("expected workflow edit blocked, got %+v", s)
+	}
+	if !strings.Contains(s.AgentMessage, "Don't edit it another way") || strings.Contains(s.AgentMessage, pathGuardOverrideEnv) {
+		t.Errorf("agent message should steer away from workarounds without naming the override, got %q", s.AgentMessage)
+	}
+
+	t.Setenv(pathGuardOverrideEnv, "1")
+	if s := run("Write", map[string]interface{}{"file_path": ".env", "content": "X=1"}); s.Decision == "block" {
+		t.Fatalf("override should let the edit through, got %+v", s)
+	}
+	t.Setenv(pathGuardOverrideEnv, "")
+
+	writeTestFile(t, filepath.Join(home, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"pathGuard":{"protected":["infra/**"]}}`)
+	if s := run("Write", map[string]interface{}{"file_path": "infra/main.tf", "content": "x"}); s.Decision != "block" {
+		t.Fatalf("expected the global config's list to apply without a project one, got %+v", s)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"pathGuard":{"protected":["deploy/**"],"action":"ask"}}`)
+	if s := run("Write", map[string]interface{}{"file_path": "deploy/prod.yml", "content": "x"}); s.Decision != "ask" {
+		t.Fatalf("expected ask, got %+v", s)
+	}
+	if s := run("Write", map[string]interface{}{"file_path": ".env", "content": "X=1"}); s.Decision == "block" || s.Decision == "ask" {
+		t.Fatalf("configured list should replace the defaults, got %+v", s)
+	}
+}
@@ -0,0 +1,344 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const (
+	// defaultReadinessReport is where the report goes when prReadiness.report is unset
+	defaultReadinessReport = ".claude/pr-readiness.md"
+	// defaultReadinessTimeout bounds each command check, in seconds
+	defaultReadinessTimeout = 300
+	// readinessOutputLines is how much of a failing command's output is kept
+	readinessOutputLines = 20
+)
+
+// Readiness check results
+const (
+	readinessPass = "pass"
+	readinessFail = "fail"
+	readinessSkip = "skip"
+)
+
+// todoPattern finds markers that mean the work is not finished
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME|XXX)\b`)
+
+// defaultReadinessChecks is the checklist used when prReadiness.checks is unset
+var defaultReadinessChecks = []config.PRReadinessCheck{
+	{Name: "build"}, {Name: "test"}, {Name: "todo"}, {Name: "changelog"},
+}
+
+// projectCommands are the build and test commands for a project type,
+// detected by its marker file
+var projectCommands = []struct {
+	marker      string
+	build, test string
+}{
+	{"go.mod", "go build ./...", "go test ./..."},
+	{"Cargo.toml", "cargo build", "cargo test"},
+	{"package.json", "npm run build --if-present", "npm test"},
+}
+
+// PRReadinessHook runs a readiness checklist when the agent stops: the
+// build and tests pass, no TODOs were added and the changelog was updated.
+// It writes a pass/fail report for the user and, with prReadiness.block,
+// hands the gaps back to the agent once.
+type PRReadinessHook struct {
+	*core.BaseHook
+}
+
+// NewPRReadinessHook creates a new PR-readiness checker hook instance
+func NewPRReadinessHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("pr-readiness", "PR Readiness Checker", "Runs a readiness checklist on Stop and reports whether the session's work is mergeable", ctx)
+	return &PRReadinessHook{BaseHook: base}
+}
+
+// Manifest describes the pr-readiness hook
+func (h *PRReadinessHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.StopEvent)}
+	m.DefaultEvent = string(core.StopEvent)
+	m.SettingsKey = "prReadiness"
+	m.SettingsSchema = config.SectionSchema(config.PRReadinessConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands, core.CapabilityWritesState}
+	return m
+}
+
+// Run executes the PR-readiness hook
+func (h *PRReadinessHook) Run() error {
+	return h.RunRaw(h.stopHandler)
+}
+
+// readinessResult is the outcome of one check
+type readinessResult struct {
+	Name   string
+	Status string
+	// Detail is a one-line summary; Output holds a failing command's tail
+	Detail string
+	Output string
+}
+
+func (h *PRReadinessHook) stopHandler(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+	var event struct {
+		HookEventName  string `json:"hook_event_name"`
+		SessionID      string `json:"session_id"`
+		StopHookActive bool   `json:"stop_hook_active"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &event); err != nil || event.HookEventName != string(core.StopEvent) {
+		return nil
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return &cchooks.RawResponse{}
+	}
+	cfg := h.loadConfig()
+
+	var changed []string
+	var added []core.AddedLine
+	var gitErr error
+	base, err := core.SessionGitBase(root, event.SessionID)
+	if err == nil {
+		changed, err = core.GitChangedFiles(root, base)
+	}
+	if err == nil {
+		added, err = core.GitAddedLines(root, base)
+	}
+	if err != nil {
+		gitErr = err
+	} else {
+		changed = withoutClaudeDir(changed)
+		if len(changed) == 0 {
+			// Nothing to merge yet
+			return &cchooks.RawResponse{}
+		}
+	}
+
+	results := h.runChecks(ctx, root, cfg, changed, added, gitErr)
+	reportPath := cfg.Report
+	if reportPath == "" {
+		reportPath = defaultReadinessReport
+	}
+	if !filepath.IsAbs(reportPath) {
+		reportPath = filepath.Join(root, reportPath)
+	}
+	if err := writeReadinessReport(reportPath, event.SessionID, changed, results); err != nil {
+		h.LogError("pr_readiness_report_error", "", err)
+	}
+
+	gaps := readinessGaps(results)
+	h.LogHookEvent("pr_readiness", "", map[string]interface{}{"checks": len(results), "gaps": len(gaps)}, nil)
+	if len(gaps) == 0 {
+		return &cchooks.RawResponse{Output: fmt.Sprintf("✅ PR readiness: all %d checks passed (%s)", len(results), displayPath(root, reportPath))}
+	}
+
+	summary := fmt.Sprintf("⚠️  PR readiness: %d of %d checks failing (%s)", len(gaps), len(results), displayPath(root, reportPath))
+	if cfg.Block && !event.StopHookActive {
+		reason := "The work is not ready to merge yet. Close these gaps before finishing:\n" + strings.Join(gaps, "\n")
+		data, err := json.Marshal(cchooks.BlockStop(reason))
+		if err == nil {
+			return &cchooks.RawResponse{Output: string(data)}
+		}
+	}
+	return &cchooks.RawResponse{Output: summary + "\n" + strings.Join(gaps, "\n")}
+}
+
+// runChecks runs the checklist in order. gitErr, when set, skips the checks
+// that compare against the session's starting commit.
+func (h *PRReadinessHook) runChecks(ctx context.Context, root string, cfg config.PRReadinessConfig, changed []string, added []core.AddedLine, gitErr error) []readinessResult {
+	checks := cfg.Checks
+	if len(checks) == 0 {
+		checks = defaultReadinessChecks
+	}
+	results := make([]readinessResult, 0, len(checks))
+	for _, check := range checks {
+		if check.Run != "" {
+			results = append(results, h.runCommandCheck(ctx, check.Name, check.Run, check.Timeout))
+			continue
+		}
+		switch check.Name {
+		case "build", "test":
+			cmd := detectProjectCommand(root, check.Name)
+			if cmd == "" {
+				results = append(results, readinessResult{Name: check.Name, Status: readinessSkip, Detail: "no " + check.Name + " command detected; set one with \"run\""})
+				continue
+			}
+			results = append(results, h.runCommandCheck(ctx, check.Name, cmd, check.Timeout))
+		case "todo":
+			if gitErr != nil {
+				results = append(results, readinessResult{Name: check.Name, Status: readinessSkip, Detail: gitErr.Error()})
+				continue
+			}
+			results = append(results, checkAddedTodos(added))
+		case "changelog":
+			if gitErr != nil {
+				results = append(results, readinessResult{Name: check.Name, Status: readinessSkip, Detail: gitErr.Error()})
+				continue
+			}
+			results = append(results, checkChangelog(changed, check.File))
+		default:
+			results = append(results, readinessResult{Name: check.Name, Status: readinessSkip, Detail: "unknown check; set \"run\" to a command"})
+		}
+	}
+	return results
+}
+
+func (h *PRReadinessHook) runCommandCheck(ctx context.Context, name, command string, timeout int) readinessResult {
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	output, err := h.Context().CommandExecutor.ExecuteCommand(cmdCtx, "bash", "-lc", command)
+	if err == nil {
+		return readinessResult{Name: name, Status: readinessPass, Detail: "`" + command + "`"}
+	}
+	detail := fmt.Sprintf("`%s` failed: %v", command, err)
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		detail = fmt.Sprintf("`%s` timed out after %ds", command, timeout)
+	}
+	return readinessResult{Name: name, Status: readinessFail, Detail: detail, Output: tailLines(string(output), readinessOutputLines)}
+}
+
+// checkAddedTodos fails when a line added this session has a TODO marker
+func checkAddedTodos(added []core.AddedLine) readinessResult {
+	var found []string
+	for _, l := range added {
+		if strings.HasPrefix(l.File, constants.ClaudeDir+"/") {
+			continue
+		}
+		if todoPattern.MatchString(l.Text) {
+			found = append(found, fmt.Sprintf("%s:%d: %s", l.File, l.Line, strings.TrimSpace(l.Text)))
+		}
+	}
+	if len(found) == 0 {
+		return readinessResult{Name: "todo", Status: readinessPass, Detail: "no TODO/FIXME added"}
+	}
+	return readinessResult{
+		Name:   "todo",
+		Status: readinessFail,
+		Detail: fmt.Sprintf("%d TODO/FIXME marker(s) added", len(found)),
+		Output: tailLines(strings.Join(found, "\n"), readinessOutputLines),
+	}
+}
+
+// checkChangelog passes when the changelog is among the changed files
+func checkChangelog(changed []string, file string) readinessResult {
+	if file == "" {
+		file = "CHANGELOG.md"
+	}
+	file = filepath.ToSlash(filepath.Clean(file))
+	for _, f := range changed {
+		if f == file {
+			return readinessResult{Name: "changelog", Status: readinessPass, Detail: file + " updated"}
+		}
+	}
+	return readinessResult{Name: "changelog", Status: readinessFail, Detail: file + " not updated this session"}
+}
+
+// detectProjectCommand returns the build or test command for the project in root
+func detectProjectCommand(root, kind string) string {
+	for _, p := range projectCommands {
+		if _, err := os.Stat(filepath.Join(root, p.marker)); err != nil {
+			continue
+		}
+		if kind == "build" {
+			return p.build
+		}
+		return p.test
+	}
+	return ""
+}
+
+// readinessGaps describes each failing check for the agent
+func readinessGaps(results []readinessResult) []string {
+	var gaps []string
+	for _, r := range results {
+		if r.Status != readinessFail {
+			continue
+		}
+		gap := fmt.Sprintf("- %s: %s", r.Name, r.Detail)
+		if r.Output != "" {
+			gap += "\n    " + strings.ReplaceAll(r.Output, "\n", "\n    ")
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+func writeReadinessReport(path, sessionID string, changed []string, results []readinessResult) error {
+	passed := 0
+	for _, r := range results {
+		if r.Status != readinessFail {
+			passed++
+		}
+	}
+	verdict := "READY"
+	if passed < len(results) {
+		verdict = "NOT READY"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# PR readiness: %s\n\n", verdict)
+	fmt.Fprintf(&b, "Checked %s", time.Now().Format(time.RFC3339))
+	if sessionID != "" {
+		fmt.Fprintf(&b, " for session %s", sessionID)
+	}
+	fmt.Fprintf(&b, ". %d of %d checks passed; %d file(s) changed since the session started.\n\n", passed, len(results), len(changed))
+	b.WriteString("| Check | Result | Details |\n|---|---|---|\n")
+	icons := map[string]string{readinessPass: "✅ pass", readinessFail: "❌ fail", readinessSkip: "➖ skip"}
+	for _, r := range results {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", r.Name, icons[r.Status], strings.ReplaceAll(r.Detail, "|", "\\|"))
+	}
+	for _, r := range results {
+		if r.Status == readinessFail && r.Output != "" {
+			fmt.Fprintf(&b, "\n## %s\n\n```\n%s\n```\n", r.Name, r.Output)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write readiness report: %w", err)
+	}
+	return nil
+}
+
+// withoutClaudeDir drops files under .claude, which hold hook state and
+// reports rather than the session's work
+func withoutClaudeDir(files []string) []string {
+	var out []string
+	for _, f := range files {
+		if !strings.HasPrefix(f, constants.ClaudeDir+"/") {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// tailLines keeps the last n lines of s
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadConfig reads prReadiness settings from the project config, falling back
+// to the global config
+func (h *PRReadinessHook) loadConfig() config.PRReadinessConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.PRReadinessConfig { return c.PRReadiness })
+}
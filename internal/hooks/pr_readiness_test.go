@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestPRReadinessHook_Stop(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("BT_STATE_ROOT", t.TempDir())
+	repo := t.TempDir()
+	t.Chdir(repo)
+	writeTestFile(t, filepath.Join(repo, "go.mod"), "module example.com/m\n")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", "start"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	writeTestFile(t, filepath.Join(repo, ".claude", "hooks", "blues-traveler-config.json"), `{"prReadiness":{"block":true}}`)
+
+	hookCtx := core.TestHookContext(nil)
+	executor := hookCtx.CommandExecutor.(*core.MockCommandExecutor)
+	hook := NewPRReadinessHook(hookCtx).(*PRReadinessHook)
+	stop := func(active bool) string {
+		raw := `{"hook_event_name":"Stop","session_id":"s-1","stop_hook_active":false}`
+		if active {
+			raw = strings.Replace(raw, "false", "true", 1)
+		}
+		resp := hook.stopHandler(context.Background(), raw)
+		if resp == nil {
+			t.Fatal("expected a response for Stop")
+		}
+		return resp.Output
+	}
+
+	// No changes since the session started: nothing to check
+	if out := stop(false); out != "" {
+		t.Fatalf("expected no output without changes, got %q", out)
+	}
+	if len(executor.GetExecutedCommands()) != 0 {
+		t.Fatalf("checks ran without changes: %+v", executor.GetExecutedCommands())
+	}
+
+	writeTestFile(t, filepath.Join(repo, "main.go"), "package main\n\n// TODO: handle errors\nfunc main() {}\n")
+	executor.SetResponse("bash -lc", []byte("main.go:4: undefined: x\n"), errors.New("exit status 1"))
+
+	out := stop(false)
+	if !strings.Contains(out, `"decision":"block"`) || !strings.Contains(out, "main.go:3: // TODO: handle errors") || !strings.Contains(out, "CHANGELOG.md not updated") {
+		t.Fatalf("expected a block with the gaps, got %q", out)
+	}
+	if got := len(executor.GetExecutedCommands()); got != 2 {
+		t.Fatalf("expected build and test commands, got %d", got)
+	}
+
+	// The agent already had its chance: report without blocking again
+	out = stop(true)
+	if strings.Contains(out, `"decision"`) || !strings.Contains(out, "4 of 4 checks failing") {
+		t.Fatalf("expected a summary without blocking, got %q", out)
+	}
+
+	report, err := os.ReadFile(filepath.Join(repo, ".claude", "pr-readiness.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "# PR readiness: NOT READY") || !strings.Contains(string(report), "undefined: x") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+
+	// Fix everything: the session is ready
+	writeTestFile(t, filepath.Join(repo, "main.go"), "package main\n\nfunc main() {}\n")
+	writeTestFile(t, filepath.Join(repo, "CHANGELOG.md"), "- Add main\n")
+	executor.SetResponse("bash -lc", []byte("ok\n"), nil)
+	if out := stop(false); !strings.Contains(out, "all 4 checks passed") {
+		t.Fatalf("expected all checks to pass, got %q", out)
+	}
+}
+
+func TestPRReadinessHook_IgnoresOtherEvents(t *testing.T) {
+	hook := NewPRReadinessHook(core.TestHookContext(nil)).(*PRReadinessHook)
+	if resp := hook.stopHandler(context.Background(), `{"hook_event_name":"PreToolUse"}`); resp != nil {
+		t.Fatalf("expected nil for PreToolUse, got %+v", resp)
+	}
+}
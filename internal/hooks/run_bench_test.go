@@ -0,0 +1,199 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Fixtures for the 'hooks run' hot path: the payloads Claude Code sends
+// most often and a project with a typical config and hooks.yml
+const (
+	benchBashPayload  = `{"session_id":"bench","transcript_path":"/tmp/bench.jsonl","cwd":".","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"go test ./...","description":"Run the tests"}}`
+	benchBlockPayload = `{"session_id":"bench","transcript_path":"/tmp/bench.jsonl","cwd":".","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"sudo rm -rf /"}}`
+	benchWritePayload = `{"session_id":"bench","transcript_path":"/tmp/bench.jsonl","cwd":".","hook_event_name":"PostToolUse","tool_name":"Write","tool_input":{"file_path":"internal/app/main.go","content":"package app\n\nfunc Run() error {\n\treturn nil\n}\n"},"tool_response":{"success":true}}`
+
+	benchConfig = `{
+  "logRotation": {"maxAge": 30, "maxSize": 10, "maxBackups": 5, "compress": true},
+  "plugins": {"security": {"enabled": true}, "format": {"enabled": true}, "debug": {"enabled": false}},
+  "latencyThresholdMs": 2000
+}`
+	// Only fmt's conditions match a fixture (the Write), so timing the
+	// others leaves out user commands
+	benchHooks = `bench:
+  description: Typical project checks
+  PreToolUse:
+    jobs:
+      - name: guard-writes
+        run: 'true'
+        only: ${TOOL_NAME} == "Write"
+  PostToolUse:
+    jobs:
+      - name: fmt
+        run: gofmt -l ${TOOL_OUTPUT_FILE}
+        glob: ["*.go"]
+        only: ${TOOL_NAME} == "Edit" || ${TOOL_NAME} == "Write"
+      - name: lint
+        run: 'true'
+        skip: ${TOOL_OUTPUT_FILE} regex \.go$
+`
+)
+
+var benchHooksOnce sync.Once
+
+// setupBenchProject makes a project with the fixture config the working
+// directory and registers its config hooks
+func setupBenchProject(tb testing.TB) {
+	tb.Helper()
+	project, home := tb.TempDir(), tb.TempDir()
+	tb.Setenv("HOME", home)
+	tb.Setenv("BT_STATE_ROOT", filepath.Join(home, "state"))
+	tb.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	hooksDir := filepath.Join(project, ".claude", "hooks")
+	if err := os.MkdirAll(hooksDir, 0o750); err != nil {
+		tb.Fatal(err)
+	}
+	for name, content := range map[string]string{"blues-traveler-config.json": benchConfig, "hooks.yml": benchHooks} {
+		if err := os.WriteFile(filepath.Join(hooksDir, name), []byte(content), 0o600); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	tb.Chdir(project)
+	// The registry is process-wide and refuses duplicate keys
+	benchHooksOnce.Do(registerConfigBasedHooks)
+}
+
+// benchEvent parses a fixture payload as 'hooks run' receives it
+func benchEvent(tb testing.TB, payload string) core.ReplayEvent {
+	tb.Helper()
+	events, err := core.ParseReplayEvents([]byte(payload), "bench")
+	if err != nil || len(events) != 1 {
+		tb.Fatalf("bad fixture: %v", err)
+	}
+	return events[0]
+}
+
+// loadRunSettings reads the settings 'hooks run' consults before the hook
+// starts, in the same order
+func loadRunSettings(key string) bool {
+	if _, off := config.ActiveKillSwitch(); off {
+		return false
+	}
+	config.GetTerseOutput()
+	if !config.IsPluginEnabled(key) {
+		return false
+	}
+	if _, snoozed := config.ActiveSnooze(key); snoozed {
+		return false
+	}
+	core.SetGlobalLatencyThreshold(config.GetLatencyThreshold())
+	core.SetGlobalLoggingDefaults(config.GetLoggingDefaults())
+	core.SetGlobalExperiments(config.GetExperiments())
+	core.SetGlobalMetricsEnabled(config.GetMetricsEnabled())
+	return true
+}
+
+// runHotPath is one 'hooks run' after process start: parse the payload,
+// load the config and get the hook's decision
+func runHotPath(tb testing.TB, key, payload string) *core.ReplayResult {
+	events, err := core.ParseReplayEvents([]byte(payload), "stdin")
+	if err != nil || len(events) == 0 {
+		tb.Fatalf("parse: %v", err)
+	}
+	if !loadRunSettings(key) {
+		tb.Fatalf("%s is not enabled in the fixture config", key)
+	}
+	result, err := core.ReplayHook(context.Background(), key, events[0])
+	if err != nil {
+		tb.Fatalf("ReplayHook: %v", err)
+	}
+	return result
+}
+
+func BenchmarkHooksRun_ParsePayload(b *testing.B) {
+	payload := []byte(benchWritePayload)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := core.ParseReplayEvents(payload, "stdin"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHooksRun_LoadConfig(b *testing.B) {
+	setupBenchProject(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if !loadRunSettings("security") {
+			b.Fatal("security is not enabled")
+		}
+		cfg, err := config.LoadHooksConfig()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(buildConfigHookFactories(cfg)) == 0 {
+			b.Fatal("expected config hooks from the fixture")
+		}
+	}
+}
+
+func BenchmarkHooksRun_Decision(b *testing.B) {
+	setupBenchProject(b)
+	cases := []struct {
+		name, key, payload string
+	}{
+		{"security-allow", "security", benchBashPayload},
+		{"security-block", "security", benchBlockPayload},
+		{"config-only-miss", "config:bench:guard-writes", benchBashPayload},
+		{"config-skip-match", "config:bench:lint", benchWritePayload},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			ev := benchEvent(b, c.payload)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := core.ReplayHook(context.Background(), c.key, ev); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHooksRun_CriticalPath(b *testing.B) {
+	setupBenchProject(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		runHotPath(b, "security", benchBashPayload)
+	}
+}
+
+// TestHooksRun_OverheadBudget fails when the hot path exceeds the
+// performance budget. It is slow and timing-sensitive, so it only runs
+// with BT_PERF_BUDGET=1, as the CI bench job sets.
+func TestHooksRun_OverheadBudget(t *testing.T) {
+	if os.Getenv("BT_PERF_BUDGET") == "" {
+		t.Skip("set BT_PERF_BUDGET=1 to check the overhead budget")
+	}
+	setupBenchProject(t)
+	if r := runHotPath(t, "security", benchBlockPayload); !strings.Contains(r.Stdout, `"block"`) {
+		t.Fatalf("expected the fixture to be blocked, got exit %d: %s", r.ExitCode, r.Stdout)
+	}
+	result := testing.Benchmark(func(b *testing.B) {
+		for b.Loop() {
+			runHotPath(b, "security", benchBashPayload)
+		}
+	})
+	perRun := time.Duration(result.NsPerOp())
+	t.Logf("hot path: %s per run over %d runs (budget %s)", perRun, result.N, core.HookOverheadBudget)
+	if perRun > core.HookOverheadBudget {
+		t.Errorf("hooks run overhead %s exceeds the %s budget", perRun, core.HookOverheadBudget)
+	}
+}
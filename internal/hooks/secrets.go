@@ -0,0 +1,339 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/constants"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+const (
+	// defaultSecretsAllowlist is read when secrets.allowlistFile is unset
+	defaultSecretsAllowlist = ".claude/secrets-allowlist.txt"
+	// defaultEntropyThreshold is in bits per character; random base64 of
+	// the default length averages about 4.6, long identifiers about 4.1
+	defaultEntropyThreshold = 4.3
+	defaultMinTokenLength   = 32
+)
+
+// builtinSecretPatterns cover common credential formats. When a pattern has
+// a capture group, the group is the secret and the rest is context.
+var builtinSecretPatterns = []config.SecretPattern{
+	{Name: "AWS access key ID", Regex: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "AWS secret access key", Regex: `(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`},
+	{Name: "private key", Regex: `-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`},
+	{Name: "GitHub token", Regex: `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`},
+	{Name: "Slack token", Regex: `\bxox[abprs]-[A-Za-z0-9-]{10,}`},
+	{Name: "Stripe live key", Regex: `\b[rs]k_live_[A-Za-z0-9]{24,}\b`},
+	{Name: "Google API key", Regex: `\bAIza[0-9A-Za-z_-]{35}\b`},
+	{Name: "API key or password", Regex: `(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*["']([^"'\s$<{}]{12,})["']`},
+}
+
+// entropyTokenPattern splits text into candidate tokens for the entropy
+// check. '/' is left out so file paths fall apart into short pieces.
+var entropyTokenPattern = regexp.MustCompile(`[A-Za-z0-9+=_-]+`)
+
+// SecretsHook blocks Edit, Write and Bash calls whose content looks like it
+// carries credentials: known key formats from the built-in and configured
+// patterns, or long random-looking tokens.
+type SecretsHook struct {
+	*core.BaseHook
+}
+
+// NewSecretsHook creates a new secrets scanner hook instance
+func NewSecretsHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("secrets", "Secrets Scanner", "Blocks file writes and commands that contain API keys, credentials or private keys", ctx)
+	return &SecretsHook{BaseHook: base}
+}
+
+// Manifest describes the secrets hook
+func (h *SecretsHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Edit|MultiEdit|Write|Bash"
+	m.SettingsKey = "secrets"
+	m.SettingsSchema = config.SectionSchema(config.SecretsConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
+// Run executes the secrets scanner hook.
+func (h *SecretsHook) Run() error {
+	return h.StandardRun(h.preToolUseHandler, nil)
+}
+
+// secretFinding is one suspected secret
+type secretFinding struct {
+	Kind  string
+	Value string
+	// Where is the file the secret would be written to, or "command"
+	Where string
+}
+
+// secretScanner holds the compiled patterns and allowlist for one call
+type secretScanner struct {
+	patterns  []namedPattern
+	threshold float64
+	minLength int
+	allowlist secretAllowlist
+}
+
+type namedPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+func (h *SecretsHook) preToolUseHandler(_ context.Context, event *cchooks.PreToolUseEvent) cchooks.PreToolUseResponseInterface {
+	root, err := os.Getwd()
+	if err != nil {
+		return cchooks.Approve()
+	}
+	scanner := newSecretScanner(root, h.loadConfig())
+
+	var findings []secretFinding
+	if event.ToolName == constants.ToolBash {
+		bash, err := event.AsBash()
+		if err != nil {
+			return cchooks.Approve()
+		}
+		findings = scanner.scan(bash.Command, "command")
+	} else {
+		for _, edit := range core.ParseToolPayload(event.ToolName, event.ToolInput).Edits {
+			if scanner.allowlist.allowsFile(root, edit.FilePath) {
+				continue
+			}
+			findings = append(findings, scanner.scan(edit.NewString, edit.FilePath)...)
+		}
+	}
+	if len(findings) == 0 {
+		return cchooks.Approve()
+	}
+	return h.block(event.ToolName, findings, scanner.allowlist.path)
+}
+
+// block reports the findings with their values redacted
+func (h *SecretsHook) block(toolName string, findings []secretFinding, allowlistPath string) cchooks.PreToolUseResponseInterface {
+	var lines []string
+	var kinds []string
+	for _, f := range findings {
+		lines = append(lines, fmt.Sprintf("  - %s in %s: %s", f.Kind, f.Where, redactSecret(f.Value)))
+		kinds = append(kinds, f.Kind)
+	}
+	h.LogBlock("secrets_block", toolName, map[string]interface{}{"findings": len(findings), "kinds": kinds})
+
+	target := "This write"
+	if toolName == constants.ToolBash {
+		target = "This command"
+	}
+	userMsg := fmt.Sprintf("%s blocked: possible secrets found:\n%s", toolName, strings.Join(lines, "\n"))
+	agentMsg := fmt.Sprintf("%s contains what looks like a credential:\n%s\nDon't put secrets in files or commands. Read them from an environment variable or the project's secret store instead, and use a placeholder in code and examples. If the value is not a secret, the user can add it to %s.", target, strings.Join(lines, "\n"), allowlistPath)
+	return core.BlockWithMessages(userMsg, agentMsg)
+}
+
+// loadConfig reads secrets settings from the project config, falling back
+// to the global config
+func (h *SecretsHook) loadConfig() config.SecretsConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.SecretsConfig { return c.Secrets })
+}
+
+// newSecretScanner compiles the built-in and configured patterns. Invalid
+// configured patterns are skipped so a typo doesn't disable the scan.
+func newSecretScanner(root string, cfg config.SecretsConfig) *secretScanner {
+	s := &secretScanner{threshold: cfg.EntropyThreshold, minLength: cfg.MinTokenLength}
+	if s.threshold == 0 {
+		s.threshold = defaultEntropyThreshold
+	}
+	if s.minLength <= 0 {
+		s.minLength = defaultMinTokenLength
+	}
+	for _, p := range append(append([]config.SecretPattern{}, builtinSecretPatterns...), cfg.Patterns...) {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = "secret"
+		}
+		s.patterns = append(s.patterns, namedPattern{name: name, re: re})
+	}
+
+	allowlistPath := cfg.AllowlistFile
+	if allowlistPath == "" {
+		allowlistPath = defaultSecretsAllowlist
+	}
+	s.allowlist = loadSecretAllowlist(root, allowlistPath)
+	return s
+}
+
+// scan returns the secrets found in text, one finding per distinct value
+func (s *secretScanner) scan(text, where string) []secretFinding {
+	var findings []secretFinding
+	seen := map[string]bool{}
+	add := func(kind, value string) {
+		if value == "" || seen[value] || s.allowlist.allowsValue(value) {
+			return
+		}
+		seen[value] = true
+		findings = append(findings, secretFinding{Kind: kind, Value: value, Where: where})
+	}
+
+	for _, p := range s.patterns {
+		for _, m := range p.re.FindAllStringSubmatch(text, -1) {
+			value := m[0]
+			if len(m) > 1 && m[1] != "" {
+				value = m[1]
+			}
+			add(p.name, value)
+		}
+	}
+	if s.threshold > 0 {
+		for _, token := range entropyTokenPattern.FindAllString(text, -1) {
+			if len(token) < s.minLength || seen[token] || !mixedCharClasses(token) {
+				continue
+			}
+			if shannonEntropy(token) >= s.threshold {
+				add("high-entropy string", token)
+			}
+		}
+	}
+	return findings
+}
+
+// mixedCharClasses reports whether token has upper and lower case letters
+// and digits, as generated keys do. Hex digests and long identifiers don't.
+func mixedCharClasses(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// redactSecret keeps enough of a value to recognize it in the message
+func redactSecret(value string) string {
+	if len(value) <= 8 {
+		return fmt.Sprintf("[%d chars]", len(value))
+	}
+	return fmt.Sprintf("%s… [%d chars]", value[:4], len(value))
+}
+
+// secretAllowlist holds the entries of the allowlist file: literal values,
+// /regex/ values and file:<glob> paths whose writes are not scanned
+type secretAllowlist struct {
+	path     string
+	literals map[string]bool
+	patterns []*regexp.Regexp
+	files    []string
+}
+
+// loadSecretAllowlist reads the allowlist at file (relative to root). A
+// missing file is an empty allowlist; unparseable regexes are skipped.
+func loadSecretAllowlist(root, file string) secretAllowlist {
+	a := secretAllowlist{path: file, literals: map[string]bool{}}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(root, file)
+	}
+	f, err := os.Open(file) // #nosec G304 - allowlist path comes from the project config
+	if err != nil {
+		return a
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "file:"):
+			if glob := strings.TrimSpace(strings.TrimPrefix(line, "file:")); glob != "" {
+				a.files = append(a.files, glob)
+			}
+		case len(line) > 2 && strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/"):
+			if re, err := regexp.Compile(line[1 : len(line)-1]); err == nil {
+				a.patterns = append(a.patterns, re)
+			}
+		default:
+			a.literals[line] = true
+		}
+	}
+	return a
+}
+
+// allowsValue reports whether a suspected secret is allowlisted
+func (a secretAllowlist) allowsValue(value string) bool {
+	if a.literals[value] {
+		return true
+	}
+	for _, re := range a.patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsFile reports whether writes to file skip the scan. Globs are
+// matched against the project-relative path and its base name.
+func (a secretAllowlist) allowsFile(root, file string) bool {
+	if len(a.files) == 0 {
+		return false
+	}
+	rel := file
+	if filepath.IsAbs(file) {
+		if r, err := filepath.Rel(root, file); err == nil {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(filepath.Clean(rel))
+	for _, glob := range a.files {
+		glob = strings.TrimPrefix(filepath.ToSlash(glob), "./")
+		if ok, _ := path.Match(glob, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, path.Base(rel)); ok {
+			return true
+		}
+		if dir := strings.TrimSuffix(glob, "/"); !strings.ContainsAny(dir, "*?[") && strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
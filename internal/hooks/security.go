@@ -22,6 +22,15 @@ func NewSecurityHook(ctx *core.HookContext) core.Hook {
 	return &SecurityHook{BaseHook: base}
 }
 
+// Manifest describes the security hook
+func (h *SecurityHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PreToolUseEvent)}
+	m.DefaultMatcher = "Bash"
+	m.Capabilities = []core.Capability{core.CapabilityBlocks}
+	return m
+}
+
 // Run executes the security hook.
 func (h *SecurityHook) Run() error {
 	return h.StandardRun(h.preToolUseHandler, nil)
@@ -0,0 +1,245 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Sections of the context hook's report, selected by context.include
+const (
+	contextBranch     = "branch"
+	contextStatus     = "status"
+	contextCommits    = "commits"
+	contextTodos      = "todos"
+	contextToolchains = "toolchains"
+)
+
+// contextSections is the default context.include, in report order
+var contextSections = []string{contextBranch, contextStatus, contextCommits, contextTodos, contextToolchains}
+
+const (
+	defaultContextCommits = 5
+	// contextCommandTimeout bounds each git or toolchain command
+	contextCommandTimeout = 5 * time.Second
+)
+
+// toolchainVersionCommands are the version commands reported for a
+// project type, detected by its marker files
+var toolchainVersionCommands = []struct {
+	markers []string
+	command string
+}{
+	{[]string{"go.mod"}, "go version"},
+	{[]string{"Cargo.toml"}, "rustc --version"},
+	{[]string{"package.json"}, "node --version"},
+	{[]string{"pyproject.toml", "requirements.txt"}, "python3 --version"},
+	{[]string{"Gemfile"}, "ruby --version"},
+}
+
+// ContextHook tells the agent about the project when a session starts: the
+// git branch and working tree, recent commits, the number of TODO markers
+// and the toolchain versions. The context section of the project config
+// chooses what is included.
+type ContextHook struct {
+	*core.BaseHook
+}
+
+// NewContextHook creates a new session context hook instance
+func NewContextHook(ctx *core.HookContext) core.Hook {
+	base := core.NewBaseHook("context", "Session Context", "Adds git state, recent commits, TODO counts and toolchain versions to the agent's context on SessionStart", ctx)
+	return &ContextHook{BaseHook: base}
+}
+
+// Manifest describes the context hook
+func (h *ContextHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.SessionStartEvent)}
+	m.DefaultEvent = string(core.SessionStartEvent)
+	m.SettingsKey = "context"
+	m.SettingsSchema = config.SectionSchema(config.ContextConfig{})
+	m.Capabilities = []core.Capability{core.CapabilityRunsCommands}
+	return m
+}
+
+// Run executes the context hook
+func (h *ContextHook) Run() error {
+	return h.RunRaw(h.sessionStartHandler)
+}
+
+// sessionStartOutput is the response that adds text to the agent's context
+type sessionStartOutput struct {
+	HookSpecificOutput struct {
+		HookEventName     string `json:"hookEventName"`
+		AdditionalContext string `json:"additionalContext"`
+	} `json:"hookSpecificOutput"`
+}
+
+func (h *ContextHook) sessionStartHandler(ctx context.Context, rawJSON string) *cchooks.RawResponse {
+	var event struct {
+		HookEventName string `json:"hook_event_name"`
+		Source        string `json:"source"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &event); err != nil || event.HookEventName != string(core.SessionStartEvent) {
+		return nil
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return &cchooks.RawResponse{}
+	}
+
+	report := h.buildReport(ctx, root, h.loadConfig())
+	if report == "" {
+		return &cchooks.RawResponse{}
+	}
+	h.LogHookEvent("context_injected", "", map[string]interface{}{"source": event.Source, "bytes": len(report)}, nil)
+
+	var out sessionStartOutput
+	out.HookSpecificOutput.HookEventName = string(core.SessionStartEvent)
+	out.HookSpecificOutput.AdditionalContext = report
+	data, err := json.Marshal(out)
+	if err != nil {
+		return &cchooks.RawResponse{}
+	}
+	return &cchooks.RawResponse{Output: string(data)}
+}
+
+// buildReport renders the selected sections; sections with nothing to say
+// (outside a git repository, no known toolchain) are left out
+func (h *ContextHook) buildReport(ctx context.Context, root string, cfg config.ContextConfig) string {
+	include := cfg.Include
+	if len(include) == 0 {
+		include = contextSections
+	}
+	var lines []string
+	for _, section := range contextSections {
+		if !containsFold(include, section) {
+			continue
+		}
+		lines = append(lines, h.reportSection(ctx, root, section, cfg)...)
+	}
+	if notes := strings.TrimSpace(cfg.Notes); notes != "" {
+		lines = append(lines, "", notes)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "Project context for " + filepath.Base(root) + ":\n" + strings.Join(lines, "\n")
+}
+
+func (h *ContextHook) reportSection(ctx context.Context, root, section string, cfg config.ContextConfig) []string {
+	switch section {
+	case contextBranch:
+		if branch, ok := h.command(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD"); ok && branch != "" {
+			return []string{"- Git branch: " + branch}
+		}
+	case contextStatus:
+		status, ok := h.command(ctx, "git", "status", "--porcelain")
+		if !ok {
+			return nil
+		}
+		if status == "" {
+			return []string{"- Working tree: clean"}
+		}
+		return []string{fmt.Sprintf("- Working tree: %d uncommitted change(s)", len(strings.Split(status, "\n")))}
+	case contextCommits:
+		n := cfg.Commits
+		if n <= 0 {
+			n = defaultContextCommits
+		}
+		if log, ok := h.command(ctx, "git", "log", "-n", strconv.Itoa(n), "--oneline", "--no-decorate"); ok && log != "" {
+			return append([]string{"- Recent commits:"}, prefixLines(log, "  ")...)
+		}
+	case contextTodos:
+		if count, ok := h.countTodos(ctx); ok {
+			return []string{fmt.Sprintf("- TODO/FIXME/XXX markers in tracked files: %d", count)}
+		}
+	case contextToolchains:
+		var versions []string
+		for _, command := range h.toolchainCommands(root, cfg) {
+			fields := strings.Fields(command)
+			if out, ok := h.command(ctx, fields[0], fields[1:]...); ok && out != "" {
+				versions = append(versions, strings.SplitN(out, "\n", 2)[0])
+			}
+		}
+		if len(versions) > 0 {
+			return append([]string{"- Toolchains:"}, prefixLines(strings.Join(versions, "\n"), "  ")...)
+		}
+	}
+	return nil
+}
+
+// countTodos totals TODO markers in tracked files. git grep exits 1 when
+// nothing matches, which counts as zero rather than a failure.
+func (h *ContextHook) countTodos(ctx context.Context) (int, bool) {
+	if _, ok := h.command(ctx, "git", "rev-parse", "--git-dir"); !ok {
+		return 0, false
+	}
+	out, _ := h.command(ctx, "git", "grep", "-I", "-c", "-w", "-E", "TODO|FIXME|XXX")
+	total := 0
+	for _, line := range strings.Split(out, "\n") {
+		i := strings.LastIndex(line, ":")
+		if i < 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(line[i+1:]); err == nil {
+			total += n
+		}
+	}
+	return total, true
+}
+
+// toolchainCommands returns context.toolchains, or the version commands
+// for the project types detected in root
+func (h *ContextHook) toolchainCommands(root string, cfg config.ContextConfig) []string {
+	var commands []string
+	if len(cfg.Toolchains) > 0 {
+		for _, c := range cfg.Toolchains {
+			if strings.TrimSpace(c) != "" {
+				commands = append(commands, c)
+			}
+		}
+		return commands
+	}
+	for _, tc := range toolchainVersionCommands {
+		for _, marker := range tc.markers {
+			if _, err := os.Stat(filepath.Join(root, marker)); err == nil {
+				commands = append(commands, tc.command)
+				break
+			}
+		}
+	}
+	return commands
+}
+
+// command runs name and returns its trimmed output
+func (h *ContextHook) command(ctx context.Context, name string, args ...string) (string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, contextCommandTimeout)
+	defer cancel()
+	out, err := h.Context().CommandExecutor.ExecuteCommand(ctx, name, args...)
+	return strings.TrimSpace(string(out)), err == nil
+}
+
+// prefixLines indents each line of text
+func prefixLines(text, prefix string) []string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return lines
+}
+
+// loadConfig reads context settings from the project config, falling back
+// to the global config
+func (h *ContextHook) loadConfig() config.ContextConfig {
+	return config.LoadSection(func(c *config.LogConfig) *config.ContextConfig { return c.Context })
+}
@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+func TestContextHook_SessionStart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example\n")
+
+	hookCtx := core.TestHookContext(nil)
+	executor := hookCtx.CommandExecutor.(*core.MockCommandExecutor)
+	executor.SetResponse("git rev-parse", []byte("feature/login\n"), nil)
+	executor.SetResponse("git status", []byte(" M main.go\n?? notes.txt\n"), nil)
+	executor.SetResponse("git log", []byte("abc123 Add login form\ndef456 Initial commit\n"), nil)
+	executor.SetResponse("git grep", []byte("main.go:2\nutil/x.go:1\n"), nil)
+	executor.SetResponse("go version", []byte("go version go1.25.4 linux/amd64\n"), nil)
+	hook := NewContextHook(hookCtx).(*ContextHook)
+
+	resp := hook.sessionStartHandler(context.Background(), `{"hook_event_name":"SessionStart","source":"startup"}`)
+	var out sessionStartOutput
+	if err := json.Unmarshal([]byte(resp.Output), &out); err != nil {
+		t.Fatalf("expected a JSON response, got %q: %v", resp.Output, err)
+	}
+	if out.HookSpecificOutput.HookEventName != "SessionStart" {
+		t.Errorf("hookEventName = %q", out.HookSpecificOutput.HookEventName)
+	}
+	report := out.HookSpecificOutput.AdditionalContext
+	for _, want := range []string{
+		"- Git branch: feature/login",
+		"- Working tree: 2 uncommitted change(s)",
+		"  abc123 Add login form\n  def456 Initial commit",
+		"- TODO/FIXME/XXX markers in tracked files: 3",
+		"  go version go1.25.4 linux/amd64",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if !executor.WasCommandExecuted("git", "log", "-n", "5", "--oneline", "--no-decorate") {
+		t.Error("expected the default of five recent commits")
+	}
+
+	if resp := hook.sessionStartHandler(context.Background(), `{"hook_event_name":"Stop"}`); resp != nil {
+		t.Errorf("other events should be ignored, got %+v", resp)
+	}
+}
+
+func TestContextHook_ConfiguredSections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFile(t, filepath.Join(dir, ".claude", "hooks", "blues-traveler-config.json"),
+		`{"context": {"include": ["branch", "toolchains"], "toolchains": ["terraform version"], "notes": "Deploys go through make release."}}`)
+
+	hookCtx := core.TestHookContext(nil)
+	executor := hookCtx.CommandExecutor.(*core.MockCommandExecutor)
+	executor.SetResponse("git rev-parse", nil, errors.New("not a git repository"))
+	executor.SetResponse("terraform version", []byte("Terraform v1.9.0\non linux_amd64\n"), nil)
+	hook := NewContextHook(hookCtx).(*ContextHook)
+
+	report := hook.buildReport(context.Background(), dir, hook.loadConfig())
+	want := "Project context for " + filepath.Base(dir) + ":\n- Toolchains:\n  Terraform v1.9.0\n\nDeploys go through make release."
+	if report != want {
+		t.Errorf("report = %q, want %q", report, want)
+	}
+	if executor.WasCommandExecuted("git", "status", "--porcelain") {
+		t.Error("sections left out of include should not run")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/brads3290/cchooks"
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
 )
@@ -15,12 +16,30 @@ import (
 // VetHook implements Python type checking logic using ty
 type VetHook struct {
 	*core.BaseHook
+	// onlyNewFindings reports whether to hide findings seen on the previous
+	// check of the same file (plugins.vet.onlyNewFindings)
+	onlyNewFindings func() bool
 }
 
 // NewVetHook creates a new vet hook instance
 func NewVetHook(ctx *core.HookContext) core.Hook {
 	base := core.NewBaseHook("vet", "Vet Hook", "Performs Python type checking using ty", ctx)
-	return &VetHook{BaseHook: base}
+	return &VetHook{
+		BaseHook: base,
+		onlyNewFindings: func() bool {
+			return config.PluginOnlyNewFindings("vet")
+		},
+	}
+}
+
+// Manifest describes the vet hook
+func (h *VetHook) Manifest() core.Manifest {
+	m := h.BaseHook.Manifest()
+	m.Events = []string{string(core.PostToolUseEvent)}
+	m.DefaultEvent = string(core.PostToolUseEvent)
+	m.DefaultMatcher = "Edit|Write"
+	m.Capabilities = []core.Capability{core.CapabilityBlocks, core.CapabilityRunsCommands, core.CapabilityWritesState}
+	return m
 }
 
 // Run executes the vet hook.
@@ -28,7 +47,7 @@ func (h *VetHook) Run() error {
 	return h.StandardRun(nil, h.postToolUseHandler)
 }
 
-func (h *VetHook) postToolUseHandler(_ context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
+func (h *VetHook) postToolUseHandler(ctx context.Context, event *cchooks.PostToolUseEvent) cchooks.PostToolUseResponseInterface {
 	// Type check Python files after editing
 	if event.ToolName != constants.ToolEdit && event.ToolName != constants.ToolWrite {
 		return cchooks.Allow()
@@ -41,7 +60,11 @@ func (h *VetHook) postToolUseHandler(_ context.Context, event *cchooks.PostToolU
 
 	h.logVetEvent(event.ToolName, filePath)
 
-	if err := h.typeCheckFile(filePath); err != nil {
+	output, err := h.typeCheckFile(ctx, filePath)
+	if h.onlyNewFindings() {
+		return h.respondWithNewFindings(event.ToolName, filePath, output, err)
+	}
+	if err != nil {
 		// User-friendly message + technical details for agent
 		userMsg := fmt.Sprintf("Code quality check failed for %s", filepath.Base(filePath))
 		agentMsg := fmt.Sprintf("Type checking failed for %s: %v", filePath, err)
@@ -51,6 +74,31 @@ func (h *VetHook) postToolUseHandler(_ context.Context, event *cchooks.PostToolU
 	return cchooks.Allow()
 }
 
+// respondWithNewFindings blocks only on type errors the previous check of
+// this file did not report. toolName is the tool whose edit was checked.
+func (h *VetHook) respondWithNewFindings(toolName, filePath, output string, checkErr error) cchooks.PostToolUseResponseInterface {
+	var findings []core.Finding
+	if checkErr != nil {
+		findings = core.ParseFindings(output)
+	}
+	fresh, err := core.NewFindings("vet|"+filePath, findings)
+	if err != nil {
+		h.LogError("vet_findings_cache_error", toolName, err)
+	}
+	if len(fresh) == 0 {
+		return cchooks.Allow()
+	}
+
+	lines := make([]string, 0, len(fresh))
+	for _, f := range fresh {
+		lines = append(lines, f.Text)
+	}
+	userMsg := fmt.Sprintf("Code quality check found %d new issue(s) in %s", len(fresh), filepath.Base(filePath))
+	agentMsg := fmt.Sprintf("Type checking found %d new issue(s) in %s (%d pre-existing omitted):\n%s",
+		len(fresh), filePath, len(findings)-len(fresh), strings.Join(lines, "\n"))
+	return core.PostBlockWithMessages(userMsg, agentMsg)
+}
+
 func (h *VetHook) extractFilePath(event *cchooks.PostToolUseEvent) string {
 	switch event.ToolName {
 	case constants.ToolEdit:
@@ -86,12 +134,12 @@ func (h *VetHook) isPythonFile(filePath string) bool {
 	return ext == ".py"
 }
 
-func (h *VetHook) typeCheckFile(filePath string) error {
-	output, err := h.Context().CommandExecutor.ExecuteCommand("uvx", "ty", "check", filePath)
+func (h *VetHook) typeCheckFile(ctx context.Context, filePath string) (string, error) {
+	output, err := h.Context().CommandExecutor.ExecuteCommand(ctx, "uvx", "ty", "check", filePath)
 	if err != nil {
 		log.Printf("ty check error on %s: %s", filePath, output)
-		return fmt.Errorf("ty check failed: %s", output)
+		return string(output), fmt.Errorf("ty check failed: %s", output)
 	}
-	fmt.Printf("Vetted Python file: %s\n", filePath)
-	return nil
+	h.Progressf("Vetted Python file: %s\n", filePath)
+	return string(output), nil
 }
@@ -1,8 +1,11 @@
 package hooks
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/klauern/blues-traveler/internal/constants"
 	"github.com/klauern/blues-traveler/internal/core"
 )
 
@@ -52,3 +55,25 @@ func TestVetHookIsPythonFile(t *testing.T) {
 		}
 	}
 }
+
+func TestVetHookOnlyNewFindings(t *testing.T) {
+	t.Setenv("BT_CACHE_DIR", t.TempDir())
+	exec := core.NewMockCommandExecutor()
+	ctx := core.TestHookContext(nil)
+	ctx.CommandExecutor = exec
+	hook := NewVetHook(ctx).(*VetHook)
+	hook.onlyNewFindings = func() bool { return true }
+
+	check := func(output string) core.ResponseSummary {
+		exec.SetResponse("uvx ty", []byte(output), errors.New("exit status 1"))
+		out, err := hook.typeCheckFile(context.Background(), "app.py")
+		return core.SummarizeResponse(hook.respondWithNewFindings(constants.ToolWrite, "app.py", out, err))
+	}
+
+	if s := check("app.py:1:1: error[unresolved-import]\n"); s.Decision != "block" {
+		t.Fatalf("expected first findings to block, got %+v", s)
+	}
+	if s := check("app.py:4:1: error[unresolved-import]\n"); s.Decision == "block" {
+		t.Fatalf("expected unchanged findings to pass, got %+v", s)
+	}
+}
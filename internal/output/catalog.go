@@ -0,0 +1,180 @@
+package output
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/klauern/blues-traveler/internal/config"
+)
+
+// DefaultLocale is the language of the built-in catalog
+const DefaultLocale = "en"
+
+// The catalog holds the messages of hooks run, install, uninstall and
+// snooze, trash, and the top-level error line. Other commands print English
+// through Printf and friends; move their strings here as they are reworked.
+//
+//go:embed locales/*.json
+var builtinLocales embed.FS
+
+// catalog holds the message templates in use: the active locale's pack
+// over the English catalog
+type catalog struct {
+	locale    string
+	messages  map[string]string
+	templates map[string]*template.Template
+}
+
+var (
+	catalogMu sync.Mutex
+	active    *catalog
+)
+
+// T renders the catalog message id with data (a map or struct whose fields
+// the template references). Messages missing from the active locale fall
+// back to English; an unknown id renders as the id itself.
+func T(id string, data any) string {
+	catalogMu.Lock()
+	if active == nil {
+		active = loadCatalog(DetectLocale())
+	}
+	tmpl, err := active.template(id)
+	catalogMu.Unlock()
+	if err != nil {
+		return id
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return id
+	}
+	return b.String()
+}
+
+// SetLocale switches the catalog to locale, e.g. "de" or "pt_BR". Unknown
+// locales keep English.
+func SetLocale(locale string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	active = loadCatalog(locale)
+}
+
+// Locale returns the locale of the active catalog
+func Locale() string {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if active == nil {
+		active = loadCatalog(DetectLocale())
+	}
+	return active.locale
+}
+
+// Messages returns the English catalog, the starting point for a locale pack
+func Messages() map[string]string {
+	messages, _ := builtinPack(DefaultLocale)
+	return messages
+}
+
+// DetectLocale reads the locale from BT_LANG, then LC_ALL, LC_MESSAGES and
+// LANG, ignoring the encoding ("de_DE.UTF-8" is "de_DE")
+func DetectLocale() string {
+	for _, key := range []string{"BT_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		v = strings.SplitN(strings.SplitN(v, ".", 2)[0], "@", 2)[0]
+		if v == "C" || v == "POSIX" {
+			return DefaultLocale
+		}
+		return v
+	}
+	return DefaultLocale
+}
+
+// LocaleDir is where user locale packs are read from: BT_LOCALE_DIR, or
+// locales/ under the XDG config directory
+func LocaleDir() string {
+	if dir := os.Getenv("BT_LOCALE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(config.NewXDGConfig().GetConfigDir(), "locales")
+}
+
+// loadCatalog layers the pack for locale (trying "pt_BR", then "pt") over
+// English. Packs in LocaleDir win over built-in ones.
+func loadCatalog(locale string) *catalog {
+	c := &catalog{locale: DefaultLocale, messages: Messages(), templates: map[string]*template.Template{}}
+	candidates := []string{locale}
+	if lang, _, ok := strings.Cut(locale, "_"); ok {
+		candidates = append(candidates, lang)
+	}
+	for _, name := range candidates {
+		if name == "" || name == DefaultLocale || filepath.Base(name) != name {
+			continue
+		}
+		pack, err := userPack(name)
+		if err != nil {
+			pack, err = builtinPack(name)
+		}
+		if err != nil {
+			continue
+		}
+		for id, msg := range pack {
+			c.messages[id] = msg
+		}
+		c.locale = name
+		break
+	}
+	return c
+}
+
+func (c *catalog) template(id string) (*template.Template, error) {
+	if t, ok := c.templates[id]; ok {
+		return t, nil
+	}
+	msg, ok := c.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown message %q", id)
+	}
+	t, err := template.New(id).Option("missingkey=zero").Parse(msg)
+	if err != nil && c.locale != DefaultLocale {
+		// A broken translation falls back to the English message
+		t, err = template.New(id).Option("missingkey=zero").Parse(Messages()[id])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("message %q: %w", id, err)
+	}
+	c.templates[id] = t
+	return t, nil
+}
+
+func builtinPack(locale string) (map[string]string, error) {
+	data, err := builtinLocales.ReadFile("locales/" + locale + ".json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePack(data)
+}
+
+func userPack(locale string) (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(LocaleDir(), locale+".json")) // #nosec G304 - locale name is a single path element
+	if err != nil {
+		return nil, err
+	}
+	return parsePack(data)
+}
+
+func parsePack(data []byte) (map[string]string, error) {
+	var pack map[string]string
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("invalid locale pack: %w", err)
+	}
+	return pack, nil
+}
@@ -0,0 +1,85 @@
+// Package output renders user-facing CLI text. Messages come from a catalog
+// of templates that locale packs can translate, and plain mode replaces
+// emoji and typographic symbols with ASCII for terminals and log files that
+// mangle them. Terse mode, for hook output Claude Code reads, is plain mode
+// without progress banners.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+var (
+	plain atomic.Bool
+	terse atomic.Bool
+)
+
+// SetPlain turns plain mode on or off for everything written through this package
+func SetPlain(on bool) {
+	plain.Store(on)
+}
+
+// IsPlain reports whether plain mode is on
+func IsPlain() bool {
+	return plain.Load()
+}
+
+// SetTerse turns terse mode on or off. Terse mode implies plain mode, and
+// callers skip banners and progress lines while it is on.
+func SetTerse(on bool) {
+	terse.Store(on)
+}
+
+// IsTerse reports whether terse mode is on
+func IsTerse() bool {
+	return terse.Load()
+}
+
+// writer resolves its destination on every write so tests that swap
+// os.Stdout still capture output
+type writer struct {
+	dest func() io.Writer
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if !IsPlain() && !IsTerse() {
+		return w.dest().Write(p)
+	}
+	if _, err := io.WriteString(w.dest(), PlainText(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Stdout returns a writer to os.Stdout that honors plain mode
+func Stdout() io.Writer {
+	return writer{dest: func() io.Writer { return os.Stdout }}
+}
+
+// Stderr returns a writer to os.Stderr that honors plain mode
+func Stderr() io.Writer {
+	return writer{dest: func() io.Writer { return os.Stderr }}
+}
+
+// Printf formats to standard output like fmt.Printf
+func Printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(Stdout(), format, args...)
+}
+
+// Println writes to standard output like fmt.Println
+func Println(args ...any) {
+	_, _ = fmt.Fprintln(Stdout(), args...)
+}
+
+// Print writes to standard output like fmt.Print
+func Print(args ...any) {
+	_, _ = fmt.Fprint(Stdout(), args...)
+}
+
+// Say writes the catalog message id, rendered with data, and a newline
+func Say(id string, data any) {
+	_, _ = fmt.Fprintln(Stdout(), T(id, data))
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"✅ Installed", "[ok] Installed"},
+		{"⚠️  Hook already installed", "[!]  Hook already installed"},
+		{"💤 Snoozed 'format'", "Snoozed 'format'"},
+		{"🗑️ Removed", "Removed"},
+		{"a → b … c — d", "a -> b ... c - d"},
+		{"• item", "- item"},
+		{"Einträge entfernt", "Einträge entfernt"},
+		{"plain ascii\n", "plain ascii\n"},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.in); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogTemplatesParse(t *testing.T) {
+	for id, msg := range Messages() {
+		if _, err := template.New(id).Parse(msg); err != nil {
+			t.Errorf("message %s: %v", id, err)
+		}
+	}
+}
+
+func TestT_LocalePack(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_LOCALE_DIR", dir)
+	pack := `{"trash.empty": "Papierkorb ist leer.", "trash.emptied": "{{.Count"}`
+	if err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(pack), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetLocale(DefaultLocale) })
+
+	SetLocale("de_DE")
+	if Locale() != "de" {
+		t.Fatalf("expected de_DE to fall back to the de pack, got %q", Locale())
+	}
+	if got := T("trash.empty", nil); got != "Papierkorb ist leer." {
+		t.Errorf("translated message = %q", got)
+	}
+	// A broken translation and a missing one both use English
+	if got := T("trash.emptied", map[string]any{"Count": 1}); got != "✅ Removed 1 trash entry" {
+		t.Errorf("broken translation = %q", got)
+	}
+	if got := T("trash.restored", map[string]any{"Path": "/x"}); got != "✅ Restored /x" {
+		t.Errorf("untranslated message = %q", got)
+	}
+	if got := T("no.such.message", nil); got != "no.such.message" {
+		t.Errorf("unknown id = %q", got)
+	}
+
+	SetLocale("fr")
+	if Locale() != DefaultLocale {
+		t.Fatalf("expected unknown locale to keep English, got %q", Locale())
+	}
+	if got := T("trash.emptied", map[string]any{"Count": 3}); got != "✅ Removed 3 trash entries" {
+		t.Errorf("English message = %q", got)
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	for _, key := range []string{"BT_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(key, "")
+	}
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Errorf("empty environment = %q", got)
+	}
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	if got := DetectLocale(); got != "pt_BR" {
+		t.Errorf("LANG = %q", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Errorf("LC_ALL=C = %q", got)
+	}
+	t.Setenv("BT_LANG", "ja")
+	if got := DetectLocale(); got != "ja" {
+		t.Errorf("BT_LANG = %q", got)
+	}
+}
+
+func TestStdout_Plain(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = orig; SetPlain(false) })
+
+	SetPlain(true)
+	Printf("✅ done %d\n", 2)
+	SetPlain(false)
+	Println("✅ done")
+	_ = w.Close()
+	data := make([]byte, 256)
+	n, _ := r.Read(data)
+	if got := string(data[:n]); !strings.HasPrefix(got, "[ok] done 2\n✅ done\n") {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
+func TestTerseText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"\x1b[1;31merror\x1b[0m: bad\n\n", "error: bad"},
+		{"\x1b]8;;https://x\x07link\x1b]8;;\x07", "link"},
+		{"✅ all passed", "[ok] all passed"},
+	}
+	for _, tt := range tests {
+		if got := TerseText(tt.in); got != tt.want {
+			t.Errorf("TerseText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
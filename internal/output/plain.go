@@ -0,0 +1,76 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ansiEscape matches terminal color and control sequences (CSI and OSC)
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// plainReplacer maps the symbols the CLI prints to ASCII equivalents
+var plainReplacer = strings.NewReplacer(
+	"✅", "[ok]",
+	"✓", "[ok]",
+	"❌", "[x]",
+	"✗", "[x]",
+	"⚠️", "[!]",
+	"⚠", "[!]",
+	"❓", "[?]",
+	"💡", "Tip:",
+	"•", "-",
+	"→", "->",
+	"←", "<-",
+	"…", "...",
+	"—", "-",
+	"–", "-",
+	"“", `"`,
+	"”", `"`,
+	"‘", "'",
+	"’", "'",
+)
+
+// PlainText replaces known symbols with ASCII and drops other emoji along
+// with the space that follows them. Letters in any script are kept so
+// translated messages stay readable.
+func PlainText(s string) string {
+	s = plainReplacer.Replace(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	skipSpace := false
+	for _, r := range s {
+		if r == '\u200d' || unicode.Is(unicode.Variation_Selector, r) {
+			// Joiners and emoji presentation selectors belong to the emoji
+			continue
+		}
+		if skipSpace {
+			skipSpace = false
+			if r == ' ' {
+				continue
+			}
+		}
+		switch {
+		case r < 0x80:
+			b.WriteRune(r)
+		case unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r):
+			skipSpace = true
+		case unicode.IsSpace(r):
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// StripANSI removes terminal color and control sequences from s
+func StripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// TerseText is s as terse mode sends it to Claude Code: without terminal
+// escapes, in plain ASCII symbols, and without trailing whitespace
+func TerseText(s string) string {
+	return strings.TrimRight(PlainText(StripANSI(s)), " \t\n")
+}
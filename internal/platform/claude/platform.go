@@ -1,10 +1,7 @@
 package claude
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
-
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
 	"github.com/klauern/blues-traveler/internal/platform"
 )
@@ -29,11 +26,7 @@ func (p *ClaudeCodePlatform) Name() string {
 
 // ConfigPath returns the path to the Claude Code settings.json file
 func (p *ClaudeCodePlatform) ConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
-	}
-	return filepath.Join(home, ".claude", "settings.json"), nil
+	return config.GetSettingsPath(true)
 }
 
 // SupportsEvent returns true if Claude Code supports the given event
@@ -0,0 +1,81 @@
+// Package presets defines curated bundles of built-in hooks that
+// 'hooks install preset <name>' installs in one step.
+package presets
+
+import "sort"
+
+// Hook is one built-in hook a preset installs. An empty Event or Matcher
+// falls back to the hook's manifest defaults.
+type Hook struct {
+	Key     string
+	Event   string
+	Matcher string
+}
+
+// Preset is a named bundle of hooks for a kind of project
+type Preset struct {
+	Name        string
+	Description string
+	Hooks       []Hook
+}
+
+// presets are the built-in bundles, keyed by name
+var presets = map[string]Preset{
+	"go-dev": {
+		Name:        "go-dev",
+		Description: "Go projects: gofmt and go vet after edits, risky command blocking, and an audit log of every tool call",
+		Hooks: []Hook{
+			{Key: "format", Event: "PostToolUse", Matcher: "Edit|MultiEdit|Write"},
+			{Key: "vet", Event: "PostToolUse", Matcher: "Edit|MultiEdit|Write"},
+			{Key: "security", Event: "PreToolUse", Matcher: "Bash"},
+			{Key: "audit", Event: "PreToolUse", Matcher: "*"},
+			{Key: "audit", Event: "PostToolUse", Matcher: "*"},
+		},
+	},
+	"secure-defaults": {
+		Name:        "secure-defaults",
+		Description: "Any project: blocks dangerous commands, credentials in files, unguarded deletes, oversized writes and blocked URLs",
+		Hooks: []Hook{
+			{Key: "security"},
+			{Key: "secrets"},
+			{Key: "delete-guard"},
+			{Key: "large-files"},
+			{Key: "fetch-blocker"},
+		},
+	},
+	"review-ready": {
+		Name:        "review-ready",
+		Description: "Team repositories: CODEOWNERS checks, lockfile churn prompts and a PR readiness report when the agent stops",
+		Hooks: []Hook{
+			{Key: "codeowners"},
+			{Key: "lockfile-churn"},
+			{Key: "pr-readiness"},
+		},
+	},
+}
+
+// Get returns the preset called name
+func Get(name string) (Preset, bool) {
+	p, ok := presets[name]
+	return p, ok
+}
+
+// All returns every preset, sorted by name
+func All() []Preset {
+	out := make([]Preset, 0, len(presets))
+	for _, p := range presets {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Names returns the preset names, sorted
+func Names() []string {
+	all := All()
+	names := make([]string, len(all))
+	for i, p := range all {
+		names[i] = p.Name
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package presets
+
+import "testing"
+
+func TestPresetsWellFormed(t *testing.T) {
+	if len(All()) == 0 {
+		t.Fatal("expected built-in presets")
+	}
+	for _, p := range All() {
+		if got, ok := Get(p.Name); !ok || got.Name != p.Name {
+			t.Errorf("preset %q is registered under another name", p.Name)
+		}
+		if p.Description == "" || len(p.Hooks) == 0 {
+			t.Errorf("preset %q needs a description and hooks", p.Name)
+		}
+		seen := map[Hook]bool{}
+		for _, h := range p.Hooks {
+			if h.Key == "" {
+				t.Errorf("preset %q has a hook without a key", p.Name)
+			}
+			if seen[h] {
+				t.Errorf("preset %q lists %+v twice", p.Name, h)
+			}
+			seen[h] = true
+		}
+	}
+}
+
+func TestGoDevPreset(t *testing.T) {
+	p, ok := Get("go-dev")
+	if !ok {
+		t.Fatal("go-dev preset missing")
+	}
+	want := map[string]string{"format": "PostToolUse", "security": "PreToolUse"}
+	for _, h := range p.Hooks {
+		if event, ok := want[h.Key]; ok && h.Event == event {
+			delete(want, h.Key)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("go-dev is missing %v", want)
+	}
+}
@@ -0,0 +1,172 @@
+// Package templates holds the project templates 'hooks init --template'
+// scaffolds from. Each template is a hooks.yml to start the project's custom
+// hooks from plus the built-in hooks it recommends installing. Built-in
+// templates are embedded; user templates in Dir add to or replace them.
+package templates
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ManifestFile describes a template: its description and built-in hooks
+	ManifestFile = "template.yml"
+	// HooksFile is the custom hooks config a template scaffolds
+	HooksFile = "hooks.yml"
+	// SourceBuiltin is the Source of templates embedded in the binary
+	SourceBuiltin = "built-in"
+)
+
+//go:embed builtin
+var builtinFS embed.FS
+
+// Hook is one built-in hook a template installs. An empty Event or Matcher
+// falls back to the hook's manifest defaults.
+type Hook struct {
+	Key     string `yaml:"key"`
+	Event   string `yaml:"event,omitempty"`
+	Matcher string `yaml:"matcher,omitempty"`
+}
+
+// Template is a named project template
+type Template struct {
+	Name        string `yaml:"-"`
+	Description string `yaml:"description"`
+	Hooks       []Hook `yaml:"hooks,omitempty"`
+	// HooksConfig is the hooks.yml written to .claude/hooks
+	HooksConfig []byte `yaml:"-"`
+	// Source is SourceBuiltin or the directory a user template was read from
+	Source string `yaml:"-"`
+}
+
+// Dir is where user templates are read from: BT_TEMPLATE_DIR, or
+// templates/ under the XDG config directory. Each template is a
+// subdirectory holding template.yml and hooks.yml.
+func Dir() string {
+	if dir := os.Getenv("BT_TEMPLATE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(config.NewXDGConfig().GetConfigDir(), "templates")
+}
+
+// All returns every template, sorted by name. User templates replace
+// built-in ones of the same name.
+func All() ([]Template, error) {
+	byName := map[string]Template{}
+	builtins, err := fs.Sub(builtinFS, "builtin")
+	if err != nil {
+		return nil, err
+	}
+	if err := loadInto(byName, builtins, SourceBuiltin); err != nil {
+		return nil, err
+	}
+	dir := Dir()
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		if err := loadInto(byName, os.DirFS(dir), dir); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Get returns the template called name
+func Get(name string) (Template, bool, error) {
+	all, err := All()
+	if err != nil {
+		return Template{}, false, err
+	}
+	for _, t := range all {
+		if t.Name == name {
+			return t, true, nil
+		}
+	}
+	return Template{}, false, nil
+}
+
+// Names returns the names of all, in order
+func Names(all []Template) []string {
+	names := make([]string, len(all))
+	for i, t := range all {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// loadInto reads each template directory in fsys into byName. Directories
+// without a template.yml are not templates and are skipped.
+func loadInto(byName map[string]Template, fsys fs.FS, source string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := load(fsys, e.Name())
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("template '%s' in %s: %w", e.Name(), source, err)
+		}
+		t.Source = source
+		if source != SourceBuiltin {
+			t.Source = filepath.Join(source, e.Name())
+		}
+		byName[t.Name] = t
+	}
+	return nil
+}
+
+func load(fsys fs.FS, name string) (Template, error) {
+	data, err := fs.ReadFile(fsys, path.Join(name, ManifestFile))
+	if err != nil {
+		return Template{}, err
+	}
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	t.Name = name
+	for _, h := range t.Hooks {
+		if h.Key == "" {
+			return Template{}, fmt.Errorf("%s lists a hook without a key", ManifestFile)
+		}
+	}
+
+	t.HooksConfig, err = fs.ReadFile(fsys, path.Join(name, HooksFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		if len(t.Hooks) == 0 {
+			return Template{}, fmt.Errorf("needs a %s or built-in hooks", HooksFile)
+		}
+		return t, nil
+	}
+	if err != nil {
+		return Template{}, err
+	}
+	cfg, err := config.ParseHooksConfig(t.HooksConfig, config.FormatYAML)
+	if err == nil {
+		err = config.ValidateHooksConfig(&cfg)
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("invalid %s: %w", HooksFile, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltinTemplatesWellFormed(t *testing.T) {
+	t.Setenv("BT_TEMPLATE_DIR", t.TempDir())
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"go": true, "python": true, "node": true, "terraform": true, "monorepo": true}
+	for _, tmpl := range all {
+		delete(want, tmpl.Name)
+		if tmpl.Source != SourceBuiltin {
+			t.Errorf("template %q should be built in, got source %q", tmpl.Name, tmpl.Source)
+		}
+		if tmpl.Description == "" || len(tmpl.HooksConfig) == 0 {
+			t.Errorf("template %q needs a description and a hooks file", tmpl.Name)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("missing built-in templates %v", want)
+	}
+}
+
+func TestUserTemplates(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BT_TEMPLATE_DIR", dir)
+	write := func(name, file, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, file), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("rust", ManifestFile, "description: Rust crates\nhooks:\n  - key: security\n")
+	write("go", ManifestFile, "description: Our Go setup\n")
+	write("go", HooksFile, "go:\n  PostToolUse:\n    jobs:\n      - name: build\n        run: make build\n")
+	write("notes", "README.md", "not a template")
+
+	rust, ok, err := Get("rust")
+	if err != nil || !ok {
+		t.Fatalf("expected the user template, got ok=%v err=%v", ok, err)
+	}
+	if rust.Source != filepath.Join(dir, "rust") || len(rust.Hooks) != 1 || rust.HooksConfig != nil {
+		t.Errorf("unexpected user template %+v", rust)
+	}
+	goTmpl, _, _ := Get("go")
+	if goTmpl.Description != "Our Go setup" || len(goTmpl.Hooks) != 0 {
+		t.Errorf("user template should replace the built-in one, got %+v", goTmpl)
+	}
+	if _, ok, _ := Get("notes"); ok {
+		t.Error("a directory without template.yml is not a template")
+	}
+
+	write("broken", ManifestFile, "description: Broken\n")
+	write("broken", HooksFile, "broken:\n  Stop:\n    jobs:\n      - name: no-run\n")
+	if _, err := All(); err == nil {
+		t.Error("expected an error for an invalid user hooks file")
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 
 	"github.com/klauern/blues-traveler/internal/cmd"
 	"github.com/klauern/blues-traveler/internal/compat"
+	"github.com/klauern/blues-traveler/internal/config"
 	"github.com/klauern/blues-traveler/internal/core"
 	_ "github.com/klauern/blues-traveler/internal/hooks" // Import for init() registration
+	"github.com/klauern/blues-traveler/internal/output"
 	"github.com/urfave/cli/v3"
 )
 
@@ -69,17 +73,72 @@ func main() {
 		Usage: "Claude Code hook runner and manager - 'The hook brings you back'",
 		Description: `A CLI tool that runs Claude Code hooks directly and manages hook installations.
 Like the classic Blues Traveler song, our hooks will bring you back to clean, secure, and well-formatted code.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "plain",
+				Usage:   "Print ASCII instead of emoji and typographic symbols",
+				Sources: cli.EnvVars("BT_PLAIN"),
+			},
+			&cli.StringFlag{
+				Name:    "lang",
+				Usage:   "Locale for messages (default from BT_LANG, LC_ALL or LANG)",
+				Sources: cli.EnvVars("BT_LANG"),
+			},
+			&cli.StringFlag{
+				Name:    "config-dir",
+				Usage:   "Sandbox every config location (project and global .claude, XDG config and cache) under this directory",
+				Sources: cli.EnvVars(config.ConfigDirEnv),
+			},
+			&cli.StringFlag{
+				Name:    "claude-dir",
+				Usage:   "Use this directory instead of the project's ./.claude",
+				Sources: cli.EnvVars(config.ClaudeDirEnv),
+			},
+			&cli.StringFlag{
+				Name:    "global-claude-dir",
+				Usage:   "Use this directory instead of ~/.claude (default from CLAUDE_CONFIG_DIR when set)",
+				Sources: cli.EnvVars(config.GlobalClaudeDirEnv),
+			},
+		},
+		EnableShellCompletion:           true,
+		ConfigureShellCompletionCommand: cmd.ConfigureCompletionCommand,
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			output.SetPlain(c.Bool("plain"))
+			if lang := c.String("lang"); lang != "" {
+				output.SetLocale(lang)
+			}
+			if err := config.SetConfigDirOverride(c.String("config-dir")); err != nil {
+				return ctx, err
+			}
+			if err := config.SetClaudeDirOverrides(c.String("claude-dir"), c.String("global-claude-dir")); err != nil {
+				return ctx, err
+			}
+			return ctx, nil
+		},
 		Commands: []*cli.Command{
 			cmd.NewHooksCommand(hooksConfig),
+			cmd.NewLegacyRunCommand(hooksConfig),
 			cmd.NewDoctorCommand(),
 			cmd.NewConfigCmd(),
 			cmd.NewGenerateCmd(),
+			cmd.NewAuditCmd(),
+			cmd.NewTrashCmd(),
+			cmd.NewCleanCmd(),
+			cmd.NewExamplesCmd(hooksConfig),
+			cmd.NewSchemaCmd(),
+			cmd.NewSupportCmd(versionInfo),
+			cmd.NewSelfUpdateCmd(versionInfo),
 			cmd.NewVersionCmd(versionInfo),
 		},
 	}
 
-	if err := app.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
+	// Ctrl+C or SIGTERM cancels the command context so running hook jobs
+	// and the processes they started are stopped instead of orphaned
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := app.Run(ctx, os.Args)
+	stop()
+	if err != nil {
+		_, _ = fmt.Fprintln(output.Stderr(), output.T("error.command", map[string]any{"Error": err}))
 		os.Exit(1)
 	}
 }
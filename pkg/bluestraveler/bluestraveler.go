@@ -0,0 +1,168 @@
+// Package bluestraveler lets Go programs manage Claude Code hooks the way the
+// blues-traveler CLI does, without running it: read and write settings.json,
+// add hook entries, sync the custom hooks config into settings, and look up
+// or register hooks in the plugin registry.
+//
+// Paths follow the CLI. Project scope is the .claude directory of the
+// working directory, global scope is ~/.claude, and the hooks config is
+// read from both.
+package bluestraveler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/klauern/blues-traveler/internal/config"
+	"github.com/klauern/blues-traveler/internal/core"
+)
+
+// Settings is a parsed Claude Code settings.json
+type Settings = config.Settings
+
+// SyncChange is one step of a sync: an entry added, or a group's entries
+// pruned or cleaned up
+type SyncChange = config.SyncChange
+
+// SettingsPath returns the settings.json path for the project or, when
+// global is set, the user
+func SettingsPath(global bool) (string, error) {
+	return config.GetSettingsPath(global)
+}
+
+// LoadSettings reads the settings file at path. A missing file gives empty
+// settings.
+func LoadSettings(path string) (*Settings, error) {
+	return config.LoadSettings(path)
+}
+
+// SaveSettings writes s to path, keeping keys blues-traveler doesn't manage
+func SaveSettings(path string, s *Settings) error {
+	return config.SaveSettings(path, s)
+}
+
+// AddHook adds a command hook for event and matcher to s, with timeout in
+// seconds or nil for Claude Code's default. It returns false when s already
+// had the entry.
+func AddHook(s *Settings, event, matcher, command string, timeout *int) bool {
+	return !config.AddHookToSettings(s, event, matcher, command, timeout).WasDuplicate
+}
+
+// SyncOptions controls Sync. The zero value syncs every group in the
+// project scope.
+type SyncOptions struct {
+	// Global syncs into ~/.claude/settings.json instead of the project's
+	Global bool
+	// Group and Event limit the sync to one group or event when set
+	Group string
+	Event string
+	// Matcher defaults to "*"; PostMatcher, used for PostToolUse, to
+	// "Edit,Write"
+	Matcher     string
+	PostMatcher string
+	// Timeout overrides each job's timeout, in seconds, when positive
+	Timeout int
+	// ExecPath is the blues-traveler binary the entries run; by default
+	// the one the scope's execPath setting names
+	ExecPath string
+	// DryRun works out the changes without writing settings
+	DryRun bool
+}
+
+// SyncResult is what Sync did
+type SyncResult struct {
+	SettingsPath string
+	// Changed is how many entries were added or removed
+	Changed int
+	Changes []SyncChange
+	// Settings is the synced settings, written unless DryRun was set
+	Settings *Settings
+}
+
+// Sync rewrites the hooks config's groups into settings, like 'config
+// sync': entries of groups gone from the config are removed, each group's
+// entries are replaced by its current jobs, and built-in installs are
+// restored. Settings are written only when something changed.
+func Sync(opts SyncOptions) (*SyncResult, error) {
+	if err := normalizeSyncOptions(&opts); err != nil {
+		return nil, err
+	}
+	settingsPath, err := config.GetSettingsPath(opts.Global)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		release, err := config.LockFile(settingsPath)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		target, err := config.SettingsPreflightTarget(opts.Global)
+		if err != nil {
+			return nil, err
+		}
+		if err := config.Preflight("sync", target); err != nil {
+			return nil, err
+		}
+	}
+
+	hooksCfg, err := config.LoadHooksConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load hooks config: %w", err)
+	}
+	if hooksCfg != nil && (*hooksCfg)[config.BuiltinGroupName] != nil {
+		return nil, fmt.Errorf("group name '%s' is reserved for built-in hook installs", config.BuiltinGroupName)
+	}
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{SettingsPath: settingsPath, Settings: settings}
+	result.Changed = config.SyncHooksToSettings(settings, hooksCfg, config.SyncOptions{
+		Group:       opts.Group,
+		Event:       opts.Event,
+		Matcher:     opts.Matcher,
+		PostMatcher: opts.PostMatcher,
+		Timeout:     opts.Timeout,
+		ExecPath:    opts.ExecPath,
+		Global:      opts.Global,
+	}, func(c SyncChange) { result.Changes = append(result.Changes, c) })
+
+	if opts.DryRun || result.Changed == 0 {
+		return result, nil
+	}
+	if err := config.SaveSettings(settingsPath, settings); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// normalizeSyncOptions fills in defaults and checks the event filter
+func normalizeSyncOptions(opts *SyncOptions) error {
+	if opts.Event = strings.TrimSpace(opts.Event); opts.Event != "" {
+		if resolved := core.ResolveEventAlias(opts.Event); resolved != "" {
+			opts.Event = resolved
+		}
+		if !core.IsValidEventType(opts.Event) {
+			return fmt.Errorf("invalid event '%s'; valid events: %s", opts.Event, strings.Join(core.ValidEventTypes(), ", "))
+		}
+	}
+	if opts.Matcher == "" {
+		opts.Matcher = "*"
+	}
+	if opts.PostMatcher == "" {
+		opts.PostMatcher = "Edit,Write"
+	}
+	opts.Matcher = core.ExpandMCPMatcher(opts.Matcher)
+	opts.PostMatcher = core.ExpandMCPMatcher(opts.PostMatcher)
+	if opts.ExecPath == "" {
+		strategy, err := config.ConfiguredExecPathStrategy(opts.Global)
+		if err != nil {
+			return err
+		}
+		if opts.ExecPath, err = config.HookExecutable(strategy, !opts.DryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package bluestraveler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type embeddedHook struct {
+	*BaseHook
+}
+
+func (h *embeddedHook) Run() error { return nil }
+
+func TestSync(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project := t.TempDir()
+	t.Chdir(project)
+	hooksYAML := "go:\n  PreToolUse:\n    jobs:\n      - name: vet\n        run: go vet ./...\n"
+	if err := os.MkdirAll(filepath.Join(project, ".claude"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ".claude", "hooks.yml"), []byte(hooksYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := Sync(SyncOptions{ExecPath: "blues-traveler", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Changed != 1 || len(dry.Changes) != 1 || dry.Changes[0].Command != "blues-traveler hooks run config:go:vet" {
+		t.Fatalf("dry run reported %d changes %+v", dry.Changed, dry.Changes)
+	}
+	if _, err := os.Stat(dry.SettingsPath); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote %s", dry.SettingsPath)
+	}
+
+	res, err := Sync(SyncOptions{ExecPath: "blues-traveler"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadSettings(res.SettingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if AddHook(settings, "PreToolUse", "*", "blues-traveler hooks run config:go:vet", nil) {
+		t.Error("expected the synced entry to be in the saved settings")
+	}
+	if !AddHook(settings, "Stop", "*", "blues-traveler hooks run notify", nil) {
+		t.Error("expected a new entry to be added")
+	}
+
+	if _, err := Sync(SyncOptions{ExecPath: "blues-traveler", Event: "NoSuchEvent"}); err == nil || !strings.Contains(err.Error(), "invalid event") {
+		t.Errorf("expected an invalid event error, got %v", err)
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	manifests := Plugins()
+	if len(manifests) == 0 || len(manifests) != len(PluginKeys()) {
+		t.Fatalf("expected a manifest per key, got %d manifests for %d keys", len(manifests), len(PluginKeys()))
+	}
+	if h, ok := Plugin("security"); !ok || h.Manifest().Key != "security" {
+		t.Fatalf("expected the built-in security hook, got %v", h)
+	}
+
+	factory := func(ctx *HookContext) Hook {
+		return &embeddedHook{BaseHook: NewBaseHook("embedded-test", "Embedded Test", "Registered by an embedding program", ctx)}
+	}
+	if err := RegisterPlugin("embedded-test", factory); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Plugin("embedded-test"); !ok {
+		t.Error("expected the registered hook to be created")
+	}
+	if err := RegisterPlugin("security", factory); err == nil {
+		t.Error("expected registering a taken key to fail")
+	}
+}
@@ -0,0 +1,68 @@
+package bluestraveler
+
+import (
+	"sort"
+
+	"github.com/klauern/blues-traveler/internal/core"
+
+	// The compat shim sets the context hooks are created with, and hooks
+	// registers the built-in ones
+	_ "github.com/klauern/blues-traveler/internal/compat"
+	_ "github.com/klauern/blues-traveler/internal/hooks"
+)
+
+// Hook is a registered hook: built-in, from the hooks config, or added with
+// RegisterPlugin
+type Hook = core.Hook
+
+// HookContext is what a HookFactory builds its hook with
+type HookContext = core.HookContext
+
+// HookFactory creates a hook for RegisterPlugin
+type HookFactory = core.HookFactory
+
+// Manifest describes a hook's events, install defaults, settings and
+// capabilities
+type Manifest = core.Manifest
+
+// BaseHook gives a hook its key, name, description and manifest; embed it
+// and add a Run method
+type BaseHook = core.BaseHook
+
+// NewBaseHook creates the BaseHook for a hook's factory
+func NewBaseHook(key, name, description string, ctx *HookContext) *BaseHook {
+	return core.NewBaseHook(key, name, description, ctx)
+}
+
+// PluginKeys returns the keys of every registered hook, sorted
+func PluginKeys() []string {
+	keys := core.GetHookKeys()
+	sort.Strings(keys)
+	return keys
+}
+
+// Plugin creates the hook registered under key
+func Plugin(key string) (Hook, bool) {
+	h, err := core.CreateHook(key)
+	if err != nil || h == nil {
+		return nil, false
+	}
+	return h, true
+}
+
+// Plugins returns the manifest of every registered hook, sorted by key
+func Plugins() []Manifest {
+	var manifests []Manifest
+	for _, key := range PluginKeys() {
+		if h, ok := Plugin(key); ok {
+			manifests = append(manifests, h.Manifest())
+		}
+	}
+	return manifests
+}
+
+// RegisterPlugin adds a hook under key, so Plugin and 'hooks run' in the
+// same process can create it. It fails when key is already registered.
+func RegisterPlugin(key string, factory HookFactory) error {
+	return core.RegisterHook(key, factory)
+}